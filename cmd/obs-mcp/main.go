@@ -13,17 +13,21 @@ import (
 	"slices"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/oklog/run"
 	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	versioncollector "github.com/prometheus/client_golang/prometheus/collectors/version"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/promslog"
 	"github.com/prometheus/common/version"
 
 	"github.com/rhobs/obs-mcp/pkg/auth"
 	"github.com/rhobs/obs-mcp/pkg/health"
+	"github.com/rhobs/obs-mcp/pkg/instrumentation"
 	"github.com/rhobs/obs-mcp/pkg/k8s"
 	"github.com/rhobs/obs-mcp/pkg/logs"
 	mcpserver "github.com/rhobs/obs-mcp/pkg/mcp"
@@ -36,6 +40,7 @@ import (
 const (
 	defaultPrometheusURL   = "http://localhost:9090"
 	defaultAlertmanagerURL = "http://localhost:9093"
+	tracingShutdownTimeout = 5 * time.Second
 )
 
 func main() {
@@ -46,13 +51,18 @@ func main() {
 	var authMode = flag.String("auth-mode", "", "Authentication mode: kubeconfig or header")
 	var insecure = flag.Bool("insecure", false, "Skip TLS certificate verification")
 	var logLevel = flag.String("log-level", "info", "Log level: debug, info, warn, error")
+	var prometheusURL = flag.String("prometheus-url", "", "Prometheus or Thanos Querier URL (overrides PROMETHEUS_URL and route discovery when set)")
+	var prometheusURLFromConfigMap = flag.String("prometheus-url-from-configmap", "", "Read the Prometheus or Thanos Querier URL at startup from a ConfigMap key given as namespace/name/key "+
+		"(overrides PROMETHEUS_URL and route discovery when set; cannot be combined with --prometheus-url)")
+	var alertmanagerURLFlag = flag.String("alertmanager-url", "", "Alertmanager URL (overrides ALERTMANAGER_URL and route discovery when set)")
 	var metricsBackend = flag.String("metrics-backend", "thanos", "Metrics backend: thanos (default, with prometheus fallback) or prometheus (strict, no fallback)")
 	var guardrails = flag.String("guardrails", "all",
 		"Which safety checks are enforced on PromQL queries.\n"+
-			"  'all': enable every guardrail\n"+
+			"  'all': enable every guardrail, including guardrails added by later releases,\n"+
+			"      so upgrading can reject queries that were accepted before\n"+
 			"  'none': disable every guardrail\n"+
 			"  Comma-separated list: enable only the named guardrails, e.g.\n"+
-			"      disallow-explicit-name-label,require-label-matcher,disallow-blanket-regex,max-metric-cardinality\n"+
+			"      disallow-explicit-name-label,require-label-matcher,disallow-blanket-regex,max-metric-cardinality,limit-matchers,limit-subqueries,disallow-all-metrics,max-nesting-depth\n"+
 			"  Comma-separated list with ! prefix: disable the listed guardrails (enable the rest), e.g.\n"+
 			"      !disallow-blanket-regex,!require-label-matcher\n"+
 			"  '!tsdb' is a shortcut that disables both TSDB-dependent guardrails at once\n"+
@@ -61,7 +71,76 @@ func main() {
 	var maxLabelCardinality = flag.Uint64("guardrails.max-label-cardinality", prometheus.DefaultMaxLabelCardinality,
 		"Maximum allowed label value count for blanket regex (0 = always disallow blanket regex).\n"+
 			"Only takes effect if disallow-blanket-regex is enabled.")
+	var maxMatchersPerSelector = flag.Uint64("guardrails.max-matchers-per-selector", prometheus.DefaultMaxMatchersPerSelector,
+		"Maximum number of label matchers in a selector.\n"+
+			"Only takes effect if limit-matchers is enabled.")
+	var maxRegexAlternatives = flag.Uint64("guardrails.max-regex-alternatives", prometheus.DefaultMaxRegexAlternatives,
+		"Maximum number of literal alternatives in a regex label matcher, e.g. 4 for (a|b)-(c|d); a character class such as [0-9] counts as one.\n"+
+			"Only takes effect if limit-matchers is enabled.")
+	// Subquery limits are PromQL durations, so that they accept the units of queries, e.g. 1d.
+	var maxSubqueryRange = model.Duration(prometheus.DefaultMaxSubqueryRange)
+	flag.Var(&maxSubqueryRange, "guardrails.max-subquery-range",
+		"Maximum range a subquery may cover, including the ranges of the subqueries it is nested in.\n"+
+			"Only takes effect if limit-subqueries is enabled.")
+	var minSubqueryStep = model.Duration(prometheus.DefaultMinSubqueryStep)
+	flag.Var(&minSubqueryStep, "guardrails.min-subquery-step",
+		"Finest resolution a subquery may use.\n"+
+			"Only takes effect if limit-subqueries is enabled.")
+	var maxNestingDepth = flag.Uint64("guardrails.max-nesting-depth", prometheus.DefaultMaxNestingDepth,
+		"Maximum number of functions, aggregations and subqueries nested in each other, e.g. 2 for sum(rate(x[5m])).\n"+
+			"Only takes effect if max-nesting-depth is enabled.")
+	var maxResultSeries = flag.Uint64("guardrails.max-result-series", 0,
+		"Maximum number of series a query may return (0 = no limit).\n"+
+			"Single-selector queries are estimated via the series API before execution.")
+	var trustGuardrailHeader = flag.Bool("trust-guardrail-header", false,
+		"Relax guardrails per request through the header set by --guardrail-header.\n"+
+			"Only enable behind a gateway that strips the header from client requests.")
+	var guardrailHeader = flag.String("guardrail-header", auth.DefaultGuardrailHeader,
+		"Request header listing guardrails to disable for the request, e.g. 'require-label-matcher,max-result-series'.\n"+
+			"Only takes effect if --trust-guardrail-header is enabled.")
+	var allowClientNow = flag.Bool("allow-client-now", false,
+		"Resolve NOW to the time clients send in the X-Obs-MCP-Now header (RFC3339 or Unix timestamp) instead of the server time,\n"+
+			"e.g. to replay recorded sessions. Only applies to the HTTP server.")
+	var maxLabelValues = flag.Int("max-label-values", metrics.DefaultMaxLabelValues, "Maximum number of values returned by get_label_values, also used when no limit is requested (0 = no limit)")
+	var limits = flag.String("limits", "",
+		"Default and maximum number of results per tool, as a comma-separated list of <key>.default=<n> and <key>.max=<n>,\n"+
+			"e.g. 'get_series.max=500,list_metrics.default=200' (0 = no limit). Keys: list_metrics, get_label_values,\n"+
+			"get_labels_overview, get_series, query_result_series, get_metric_metadata. Calls requesting more than the maximum are rejected.")
 	var fullRangeQueryResponse = flag.Bool("full-range-query-response", false, "Return full data points for range queries")
+	var splitRangeQueries = flag.Bool("split-range-queries", false,
+		"Split range queries with more than 11000 points per series into sub-range requests and stitch the results together")
+	var maxSplitPoints = flag.Int("max-split-points", prometheus.DefaultMaxSplitPoints,
+		"Maximum number of points per series of a split range query.\n"+
+			"Only takes effect if --split-range-queries is enabled.")
+	var metricNamePrefix = flag.String("metric-name-prefix", "",
+		"Prefix the backend stores metric names with, e.g. by federation. It is added to the metric names of queries\n"+
+			"and removed from the metric names of results.")
+	var metricNameSuffix = flag.String("metric-name-suffix", "",
+		"Suffix the backend stores metric names with. It is added to the metric names of queries\n"+
+			"and removed from the metric names of results.")
+	var oversizedStepPolicy = flag.String("oversized-step-policy", string(metrics.StepPolicyReject),
+		"How range queries with a step larger than their time range are handled:\n"+
+			"  'reject': return a validation error\n"+
+			"  'shrink': reduce the step to fit the range and add a warning to the result")
+	var deployMarkerQuery = flag.String("deploy-marker-query", "",
+		"PromQL query returning the Unix timestamps of deploys, e.g. 'max(deployment_created_timestamp)'.\n"+
+			"When set, execute_range_query accepts SINCE_LAST_DEPLOY as start or end.")
+	var maxIdleConns = flag.Int("max-idle-conns", auth.DefaultMaxIdleConns, "Maximum number of idle connections kept open to Prometheus and Alertmanager (0 = no limit)")
+	var maxConnsPerHost = flag.Int("max-conns-per-host", auth.DefaultMaxConnsPerHost, "Maximum number of connections per Prometheus or Alertmanager host (0 = no limit)")
+	var idleConnTimeout = flag.Duration("idle-conn-timeout", auth.DefaultIdleConnTimeout, "How long idle connections to Prometheus and Alertmanager are kept open (0 = no timeout)")
+	var tokenFile = flag.String("token-file", "", "File the bearer token sent to Prometheus and Alertmanager is read from instead of the kubeconfig, e.g. a projected service account token (requires --auth-mode kubeconfig)")
+	var tokenReloadInterval = flag.Duration("token-reload-interval", auth.DefaultTokenReloadInterval, "How often --token-file is re-read to pick up rotated tokens")
+	var logQueries = flag.Bool("log-queries", false, "Log every executed PromQL query and its time window at info level")
+	var otelEndpoint = flag.String("otel-endpoint", "",
+		"OTLP/HTTP endpoint to export a trace span per tool call to, e.g. http://otel-collector:4318. Off by default.\n"+
+			"The W3C trace context of incoming requests is forwarded to the backends either way.")
+	var alertLabels = flag.String("alert-labels", "", "Comma-separated alert labels get_alerts returns, e.g. alertname,severity,namespace (default: all labels)")
+	var alertAnnotations = flag.String("alert-annotations", "", "Comma-separated alert annotations get_alerts returns, e.g. summary (default: all annotations)")
+	var allowWrites = flag.Bool("allow-writes", false, "Enable the tools changing the state of Alertmanager, such as delete_silence")
+	var allowFileOutput = flag.Bool("allow-file-output", false, "Enable the save_query_result tool, which writes query results to files in --file-output-dir")
+	var fileOutputDir = flag.String("file-output-dir", "", "Directory save_query_result writes files to (required with --allow-file-output)")
+	var toolDescriptionsFile = flag.String("tool-descriptions-file", "", "TOML file replacing the descriptions of metrics tools, with one 'tool_name = \"description\"' entry per tool")
+	var statusChecksFile = flag.String("status-checks-file", "", "TOML file replacing the checks cluster_status runs, with one [[status_checks]] table per check")
 	var tempoURL = flag.String("traces.tempo-url", "", "Tempo API base URL (overrides TEMPO_URL when explicitly set)")
 	var tracesUseRoute = flag.Bool("traces.use-route", false, "Use Route instead of internal service DNS when connecting to Tempo API")
 	var lokiURL = flag.String("loki-url", "", "Loki API base URL (overrides LOKI_URL when explicitly set)")
@@ -100,13 +179,13 @@ func main() {
 	// Fail fast if it's set in any other mode to avoid silent misconfiguration.
 	if parsedAuthMode != auth.AuthModeKubeConfig && isFlagExplicitlySet("metrics-backend") {
 		log.Fatalf("--metrics-backend has no effect with --auth-mode %s; "+
-			"set PROMETHEUS_URL to point at your Thanos/Prometheus instance instead", parsedAuthMode)
+			"set --prometheus-url or PROMETHEUS_URL to point at your Thanos/Prometheus instance instead", parsedAuthMode)
 	}
 
 	metricsBackendURL := ""
 	metricsURLSource := ""
 	if slices.Contains(parsedToolsets, metrics.ToolsetName) {
-		metricsBackendURL, metricsURLSource, err = determineMetricsBackendURL(parsedAuthMode, parsedMetricsBackend)
+		metricsBackendURL, metricsURLSource, err = determineMetricsBackendURL(*prometheusURL, *prometheusURLFromConfigMap, parsedAuthMode, parsedMetricsBackend)
 		if err != nil {
 			log.Fatalf("%v", err)
 		}
@@ -115,7 +194,7 @@ func main() {
 	alertmanagerURL := ""
 	alertmanagerURLSource := ""
 	if slices.Contains(parsedToolsets, metrics.ToolsetName) {
-		alertmanagerURL, alertmanagerURLSource, err = determineAlertmanagerURL(parsedAuthMode)
+		alertmanagerURL, alertmanagerURLSource, err = determineAlertmanagerURL(*alertmanagerURLFlag, parsedAuthMode)
 		if err != nil {
 			log.Fatalf("%v", err)
 		}
@@ -147,7 +226,21 @@ func main() {
 			PrometheusURL:          metricsBackendURL,
 			AlertmanagerURL:        alertmanagerURL,
 			Guardrails:             *guardrails,
+			TrustGuardrailHeader:   *trustGuardrailHeader,
+			AllowClientNow:         *allowClientNow,
 			RangeQueryFullResponse: *fullRangeQueryResponse,
+			SplitRangeQueries:      *splitRangeQueries,
+			MetricNamePrefix:       *metricNamePrefix,
+			MetricNameSuffix:       *metricNameSuffix,
+			OversizedStepPolicy:    *oversizedStepPolicy,
+			DeployMarkerQuery:      *deployMarkerQuery,
+			LogQueries:             *logQueries,
+			AlertLabels:            metrics.ParseAlertFields(*alertLabels),
+			AlertAnnotations:       metrics.ParseAlertFields(*alertAnnotations),
+			AllowFileOutput:        *allowFileOutput,
+			FileOutputDir:          *fileOutputDir,
+			AllowWrites:            *allowWrites,
+			TokenFile:              *tokenFile,
 		},
 		Traces: &traces.Config{
 			AuthMode: parsedAuthMode,
@@ -175,11 +268,79 @@ func main() {
 	if isFlagExplicitlySet("guardrails.max-label-cardinality") {
 		opts.Metrics.MaxLabelCardinality = maxLabelCardinality
 	}
+	if isFlagExplicitlySet("guardrails.max-matchers-per-selector") {
+		opts.Metrics.MaxMatchersPerSelector = maxMatchersPerSelector
+	}
+	if isFlagExplicitlySet("guardrails.max-regex-alternatives") {
+		opts.Metrics.MaxRegexAlternatives = maxRegexAlternatives
+	}
+	if isFlagExplicitlySet("guardrails.max-subquery-range") {
+		opts.Metrics.MaxSubqueryRange = maxSubqueryRange.String()
+	}
+	if isFlagExplicitlySet("guardrails.min-subquery-step") {
+		opts.Metrics.MinSubqueryStep = minSubqueryStep.String()
+	}
+	if isFlagExplicitlySet("guardrails.max-nesting-depth") {
+		opts.Metrics.MaxNestingDepth = maxNestingDepth
+	}
+	if isFlagExplicitlySet("guardrails.max-result-series") {
+		opts.Metrics.MaxResultSeries = maxResultSeries
+	}
+	if isFlagExplicitlySet("max-split-points") {
+		opts.Metrics.MaxSplitPoints = maxSplitPoints
+	}
+	if isFlagExplicitlySet("guardrail-header") {
+		opts.Metrics.GuardrailHeader = *guardrailHeader
+	}
+	if isFlagExplicitlySet("max-label-values") {
+		opts.Metrics.MaxLabelValues = maxLabelValues
+	}
+	if *limits != "" {
+		parsedLimits, err := metrics.ParseLimits(*limits)
+		if err != nil {
+			log.Fatalf("Invalid --limits: %v", err)
+		}
+		opts.Metrics.Limits = parsedLimits
+	}
+	if isFlagExplicitlySet("max-idle-conns") {
+		opts.Metrics.MaxIdleConns = maxIdleConns
+	}
+	if isFlagExplicitlySet("max-conns-per-host") {
+		opts.Metrics.MaxConnsPerHost = maxConnsPerHost
+	}
+	if isFlagExplicitlySet("idle-conn-timeout") {
+		opts.Metrics.IdleConnTimeout = idleConnTimeout.String()
+	}
+	if isFlagExplicitlySet("token-reload-interval") {
+		opts.Metrics.TokenReloadInterval = tokenReloadInterval.String()
+	}
+	if *toolDescriptionsFile != "" {
+		descriptions, err := loadToolDescriptions(*toolDescriptionsFile)
+		if err != nil {
+			log.Fatalf("Invalid tool descriptions file: %v", err)
+		}
+		opts.Metrics.ToolDescriptions = descriptions
+	}
+	if *statusChecksFile != "" {
+		checks, err := loadStatusChecks(*statusChecksFile)
+		if err != nil {
+			log.Fatalf("Invalid status checks file: %v", err)
+		}
+		opts.Metrics.StatusChecks = checks
+	}
 
 	if err := validateConfigs(opts); err != nil {
 		log.Fatalf("%v", err)
 	}
 
+	var shutdownTracing func(context.Context) error
+	if *otelEndpoint != "" {
+		shutdownTracing, err = instrumentation.SetupTracing(context.Background(), *otelEndpoint, version.Version)
+		if err != nil {
+			log.Fatalf("Failed to set up tracing: %v", err)
+		}
+	}
+
 	// Create MCP server
 	mcpServer, err := mcpserver.NewMCPServer(opts)
 	if err != nil {
@@ -238,7 +399,7 @@ func main() {
 	// Choose server mode based on flags
 	if *listen != "" {
 		// HTTP mode
-		httpServer, shutdown := mcpserver.NewHTTPServer(mcpServer, *listen, reg, parsedAuthMode)
+		httpServer, shutdown := mcpserver.NewHTTPServer(mcpServer, *listen, reg, parsedAuthMode, opts.Metrics.GetTrustedGuardrailHeader(), opts.Metrics.AllowClientNow)
 		g.Add(func() error {
 			slog.Info("HTTP server starting", "listen_addr", *listen)
 			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -264,6 +425,13 @@ func main() {
 	if err := g.Run(); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
+	if shutdownTracing != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			slog.Error("Failed to flush trace spans", "error", err)
+		}
+		shutdownCancel()
+	}
 	slog.Info("Exiting")
 }
 
@@ -329,54 +497,117 @@ func parseMetricsBackend(backend string) (k8s.MetricsBackend, error) {
 	}
 }
 
-// determineMetricsBackendURL determines the metrics backend URL based on auth mode and environment.
-// Returns the resolved URL, a source description for logging, and an error if the configuration is invalid.
-func determineMetricsBackendURL(authMode auth.AuthMode, backend k8s.MetricsBackend) (url, source string, err error) {
-	if prometheusURL := os.Getenv("PROMETHEUS_URL"); prometheusURL != "" {
-		return prometheusURL, "PROMETHEUS_URL env var", nil
+// backendURLSources are the places the URL of a backend is read from, in order of precedence.
+type backendURLSources struct {
+	// Flag is the value of the URL flag named FlagName ("" when not set).
+	Flag, FlagName string
+	// Env is the value of the environment variable named EnvName ("" when not set).
+	Env, EnvName string
+	// Discover looks the URL up in the cluster; nil when discovery is not available.
+	Discover func() (string, error)
+	// Default is used when discovery fails; "" when there is no safe default.
+	Default string
+}
+
+// resolveBackendURL returns the URL of a backend from the first source that provides
+// one: the flag, the environment variable, discovery, then the default. source
+// describes where the URL came from, and discoveryErr is why discovery failed when the
+// default was used. It returns an empty URL when no source provides one.
+func resolveBackendURL(s backendURLSources) (url, source string, discoveryErr error) {
+	if s.Flag != "" {
+		return s.Flag, s.FlagName + " flag", nil
+	}
+	if s.Env != "" {
+		return s.Env, s.EnvName + " env var", nil
+	}
+	if s.Discover != nil {
+		url, err := s.Discover()
+		if err == nil && url != "" {
+			return url, "route discovery", nil
+		}
+		if s.Default != "" {
+			return s.Default, "default (route discovery failed)", err
+		}
+		return "", "unset", err
+	}
+	if s.Default != "" {
+		return s.Default, "default", nil
 	}
+	return "", "unset", nil
+}
 
-	if authMode == auth.AuthModeKubeConfig {
-		slog.Info("No PROMETHEUS_URL set, attempting route discovery", "backend", backend)
-		url, err := k8s.GetMetricsBackendURL(backend)
+// determineMetricsBackendURL determines the metrics backend URL based on the
+// --prometheus-url and --prometheus-url-from-configmap flags, the environment and the auth mode.
+// Returns the resolved URL, a source description for logging, and an error if the configuration is invalid.
+func determineMetricsBackendURL(flagURL, configMapRef string, authMode auth.AuthMode, backend k8s.MetricsBackend) (url, source string, err error) {
+	sources := backendURLSources{
+		Flag:     flagURL,
+		FlagName: "--prometheus-url",
+		Env:      os.Getenv("PROMETHEUS_URL"),
+		EnvName:  "PROMETHEUS_URL",
+	}
+	if configMapRef != "" {
+		if flagURL != "" {
+			return "", "", fmt.Errorf("--prometheus-url and --prometheus-url-from-configmap cannot both be set")
+		}
+		// The ConfigMap is read with the credentials of the server itself, whatever the auth mode.
+		sources.Flag, err = k8s.GetConfigMapValue(configMapRef)
 		if err != nil {
-			slog.Warn("Route discovery failed, falling back to default", "err", err, "default", defaultPrometheusURL)
-			return defaultPrometheusURL, "default (route discovery failed)", nil
+			return "", "", fmt.Errorf("failed to read the metrics backend URL from --prometheus-url-from-configmap: %w", err)
 		}
-		return url, "route discovery", nil
+		sources.FlagName = "--prometheus-url-from-configmap"
+		slog.Info("Read metrics backend URL from ConfigMap", "configmap", configMapRef)
 	}
-
 	// header mode is designed for deployments where the URL
 	// is always known ahead of time. Falling back to localhost is never correct.
-	return "", "", fmt.Errorf(
-		"PROMETHEUS_URL must be set when using --auth-mode %s\n"+
-			"  Set it via environment variable or use --auth-mode kubeconfig for auto-discovery",
-		authMode,
-	)
+	if authMode == auth.AuthModeKubeConfig {
+		sources.Discover = func() (string, error) { return k8s.GetMetricsBackendURL(backend) }
+		sources.Default = defaultPrometheusURL
+	}
+
+	url, source, discoveryErr := resolveBackendURL(sources)
+	if discoveryErr != nil {
+		slog.Warn("Route discovery failed, falling back to default", "backend", backend, "err", discoveryErr, "default", url)
+	}
+	if url == "" {
+		return "", "", fmt.Errorf(
+			"PROMETHEUS_URL must be set when using --auth-mode %s\n"+
+				"  Set it via environment variable or --prometheus-url, or use --auth-mode kubeconfig for auto-discovery",
+			authMode,
+		)
+	}
+	slog.Info("Resolved metrics backend URL", "url", url, "source", source)
+	return url, source, nil
 }
 
-// determineAlertmanagerURL determines the Alertmanager URL based on auth mode and environment.
+// determineAlertmanagerURL determines the Alertmanager URL based on the
+// --alertmanager-url flag, the environment and the auth mode.
 // Returns the resolved URL, a source description for logging, and an error if the configuration is invalid.
-func determineAlertmanagerURL(authMode auth.AuthMode) (url, source string, err error) {
-	if alertmanagerURL := os.Getenv("ALERTMANAGER_URL"); alertmanagerURL != "" {
-		return alertmanagerURL, "ALERTMANAGER_URL env var", nil
+func determineAlertmanagerURL(flagURL string, authMode auth.AuthMode) (url, source string, err error) {
+	sources := backendURLSources{
+		Flag:     flagURL,
+		FlagName: "--alertmanager-url",
+		Env:      os.Getenv("ALERTMANAGER_URL"),
+		EnvName:  "ALERTMANAGER_URL",
 	}
-
 	if authMode == auth.AuthModeKubeConfig {
-		slog.Info("No ALERTMANAGER_URL set, attempting route discovery")
-		url, err := k8s.GetAlertmanagerURL()
-		if err != nil {
-			slog.Warn("Route discovery failed, falling back to default", "err", err, "default", defaultAlertmanagerURL)
-			return defaultAlertmanagerURL, "default (route discovery failed)", nil
-		}
-		return url, "route discovery", nil
+		sources.Discover = k8s.GetAlertmanagerURL
+		sources.Default = defaultAlertmanagerURL
 	}
 
-	return "", "", fmt.Errorf(
-		"ALERTMANAGER_URL must be set when using --auth-mode %s\n"+
-			"  Set it via environment variable or use --auth-mode kubeconfig for auto-discovery",
-		authMode,
-	)
+	url, source, discoveryErr := resolveBackendURL(sources)
+	if discoveryErr != nil {
+		slog.Warn("Route discovery failed, falling back to default", "err", discoveryErr, "default", url)
+	}
+	if url == "" {
+		return "", "", fmt.Errorf(
+			"ALERTMANAGER_URL must be set when using --auth-mode %s\n"+
+				"  Set it via environment variable or --alertmanager-url, or use --auth-mode kubeconfig for auto-discovery",
+			authMode,
+		)
+	}
+	slog.Info("Resolved Alertmanager URL", "url", url, "source", source)
+	return url, source, nil
 }
 
 func determineTempoURL(flagURL string) (url, source string) {
@@ -401,6 +632,31 @@ func determineLokiURL(flagURL string) (url, source string, err error) {
 	return "", "unset", nil
 }
 
+// loadToolDescriptions reads tool description overrides from a TOML file mapping tool
+// names to descriptions.
+func loadToolDescriptions(path string) (map[string]string, error) {
+	var descriptions map[string]string
+	if _, err := toml.DecodeFile(path, &descriptions); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return descriptions, nil
+}
+
+// loadStatusChecks reads the checks of cluster_status from a TOML file with a
+// [[status_checks]] table per check, as in the toolset configuration.
+func loadStatusChecks(path string) ([]metrics.StatusCheck, error) {
+	var file struct {
+		StatusChecks []metrics.StatusCheck `toml:"status_checks"`
+	}
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(file.StatusChecks) == 0 {
+		return nil, fmt.Errorf("%s defines no status_checks", path)
+	}
+	return file.StatusChecks, nil
+}
+
 // isFlagExplicitlySet reports whether the named flag was explicitly provided on
 // the command line (as opposed to relying on its default value).
 func isFlagExplicitlySet(name string) bool {
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rhobs/obs-mcp/pkg/auth"
@@ -98,7 +103,7 @@ func TestDetermineMetricsBackendURL_RequiresURLForNonKubeconfigModes(t *testing.
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, _, err := determineMetricsBackendURL(tt.authMode, tt.backend)
+			_, _, err := determineMetricsBackendURL("", "", tt.authMode, tt.backend)
 			if err == nil {
 				t.Errorf("expected error for auth mode %q without PROMETHEUS_URL, got nil", tt.authMode)
 			}
@@ -106,9 +111,9 @@ func TestDetermineMetricsBackendURL_RequiresURLForNonKubeconfigModes(t *testing.
 	}
 }
 
-// TestDetermineMetricsBackendURL_EnvVarOverridesAll verifies that the
-// PROMETHEUS_URL environment variable takes highest precedence and
-// overrides all other configuration (auth mode, metrics-backend flag).
+// TestDetermineMetricsBackendURL_EnvVarOverridesAll verifies that, without
+// --prometheus-url, the PROMETHEUS_URL environment variable overrides all
+// other configuration (auth mode, metrics-backend flag).
 func TestDetermineMetricsBackendURL_EnvVarOverridesAll(t *testing.T) {
 	customURL := "https://custom-prometheus.example.com:9090"
 	t.Setenv("PROMETHEUS_URL", customURL)
@@ -120,7 +125,7 @@ func TestDetermineMetricsBackendURL_EnvVarOverridesAll(t *testing.T) {
 
 	for _, authMode := range authModes {
 		t.Run(string(authMode), func(t *testing.T) {
-			url, source, err := determineMetricsBackendURL(authMode, k8s.MetricsBackendThanos)
+			url, source, err := determineMetricsBackendURL("", "", authMode, k8s.MetricsBackendThanos)
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -134,6 +139,107 @@ func TestDetermineMetricsBackendURL_EnvVarOverridesAll(t *testing.T) {
 	}
 }
 
+func TestDetermineMetricsBackendURL_FlagOverridesEnvVar(t *testing.T) {
+	t.Setenv("PROMETHEUS_URL", "http://from-env:9090")
+
+	for _, authMode := range []auth.AuthMode{auth.AuthModeKubeConfig, auth.AuthModeHeader} {
+		t.Run(string(authMode), func(t *testing.T) {
+			url, source, err := determineMetricsBackendURL("http://from-flag:9090", "", authMode, k8s.MetricsBackendThanos)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if url != "http://from-flag:9090" || source != "--prometheus-url flag" {
+				t.Errorf("unexpected result: %s (%s)", url, source)
+			}
+		})
+	}
+}
+
+func TestDetermineMetricsBackendURL_ConfigMap(t *testing.T) {
+	t.Run("cannot be combined with --prometheus-url", func(t *testing.T) {
+		_, _, err := determineMetricsBackendURL("http://from-flag:9090", "monitoring/obs-mcp/prometheus-url", auth.AuthModeHeader, k8s.MetricsBackendThanos)
+		if err == nil || !strings.Contains(err.Error(), "cannot both be set") {
+			t.Errorf("expected a combination error, got %v", err)
+		}
+	})
+
+	t.Run("invalid reference fails startup", func(t *testing.T) {
+		t.Setenv("PROMETHEUS_URL", "http://from-env:9090")
+		_, _, err := determineMetricsBackendURL("", "monitoring/obs-mcp", auth.AuthModeHeader, k8s.MetricsBackendThanos)
+		if err == nil || !strings.Contains(err.Error(), "must be namespace/name/key") {
+			t.Errorf("expected an invalid reference error, got %v", err)
+		}
+	})
+}
+
+func TestResolveBackendURL(t *testing.T) {
+	discovered := func() (string, error) { return "https://discovered:9091", nil }
+	discoveryFails := func() (string, error) { return "", errors.New("no route") }
+
+	tests := []struct {
+		name             string
+		sources          backendURLSources
+		wantURL          string
+		wantSource       string
+		wantDiscoveryErr bool
+	}{
+		{
+			name:       "flag wins over everything",
+			sources:    backendURLSources{Flag: "http://flag", FlagName: "--url", Env: "http://env", EnvName: "URL", Discover: discovered, Default: "http://localhost"},
+			wantURL:    "http://flag",
+			wantSource: "--url flag",
+		},
+		{
+			name:       "env wins over discovery",
+			sources:    backendURLSources{Env: "http://env", EnvName: "URL", Discover: discovered, Default: "http://localhost"},
+			wantURL:    "http://env",
+			wantSource: "URL env var",
+		},
+		{
+			name:       "discovery wins over the default",
+			sources:    backendURLSources{Discover: discovered, Default: "http://localhost"},
+			wantURL:    "https://discovered:9091",
+			wantSource: "route discovery",
+		},
+		{
+			name:             "default when discovery fails",
+			sources:          backendURLSources{Discover: discoveryFails, Default: "http://localhost"},
+			wantURL:          "http://localhost",
+			wantSource:       "default (route discovery failed)",
+			wantDiscoveryErr: true,
+		},
+		{
+			name:             "unset when discovery fails without default",
+			sources:          backendURLSources{Discover: discoveryFails},
+			wantSource:       "unset",
+			wantDiscoveryErr: true,
+		},
+		{
+			name:       "default without discovery",
+			sources:    backendURLSources{Default: "http://localhost"},
+			wantURL:    "http://localhost",
+			wantSource: "default",
+		},
+		{
+			name:       "unset without any source",
+			sources:    backendURLSources{},
+			wantSource: "unset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, source, discoveryErr := resolveBackendURL(tt.sources)
+			if url != tt.wantURL || source != tt.wantSource {
+				t.Errorf("got %q (%s), want %q (%s)", url, source, tt.wantURL, tt.wantSource)
+			}
+			if (discoveryErr != nil) != tt.wantDiscoveryErr {
+				t.Errorf("discovery error = %v, want error: %v", discoveryErr, tt.wantDiscoveryErr)
+			}
+		})
+	}
+}
+
 func TestDetermineLokiURL(t *testing.T) {
 	t.Run("explicit flag wins", func(t *testing.T) {
 		t.Setenv("LOKI_URL", "http://from-env:3100")
@@ -194,3 +300,31 @@ func TestDetermineTempoURL(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadToolDescriptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "descriptions.toml")
+	content := `execute_range_query = "Run range queries against team-prefixed metrics."
+list_metrics = """
+List metrics.
+Prefer metrics starting with the team name."""
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadToolDescriptions(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"execute_range_query": "Run range queries against team-prefixed metrics.",
+		"list_metrics":        "List metrics.\nPrefer metrics starting with the team name.",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("loadToolDescriptions() = %v, want %v", got, want)
+	}
+
+	if _, err := loadToolDescriptions(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -29,8 +29,14 @@ const (
 
 const (
 	serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+	// DefaultGuardrailHeader is the request header read for guardrail overrides
+	// when no other header is configured.
+	DefaultGuardrailHeader = "X-Obs-MCP-Guardrails"
 )
 
+type guardrailOverrideKey struct{}
+
 // ParseAuthMode validates and converts a string to AuthMode
 func ParseAuthMode(mode string) (AuthMode, error) {
 	switch mode {
@@ -45,6 +51,27 @@ func ParseAuthMode(mode string) (AuthMode, error) {
 
 // BuildRoundTripper creates an http.RoundTripper using the configured auth mode.
 func BuildRoundTripper(ctx context.Context, restConfig *rest.Config, authMode AuthMode, useTLS, insecure bool) (http.RoundTripper, error) {
+	return buildRoundTripper(ctx, restConfig, authMode, "", useTLS, insecure, nil)
+}
+
+// BuildRoundTripperWithTransport is like BuildRoundTripper, but sends the requests over
+// a transport with the given connection pool settings, shared by all round trippers for
+// the same backend address, so that the settings limit the connections of concurrent
+// tool calls together.
+func BuildRoundTripperWithTransport(ctx context.Context, restConfig *rest.Config, authMode AuthMode, address string, useTLS, insecure bool, transport TransportConfig) (http.RoundTripper, error) {
+	return buildRoundTripper(ctx, restConfig, authMode, address, useTLS, insecure, &transport)
+}
+
+// BuildRoundTripperWithTokenFile is like BuildRoundTripperWithTransport, but sends the
+// token of the given file instead of the one of the kubeconfig.
+func BuildRoundTripperWithTokenFile(restConfig *rest.Config, tokens *FileTokenProvider, address string, useTLS, insecure bool, transport TransportConfig) (http.RoundTripper, error) {
+	if restConfig == nil {
+		return nil, fmt.Errorf("no REST config available")
+	}
+	return createRoundTripper(restConfig, tokens, address, useTLS, insecure, &transport)
+}
+
+func buildRoundTripper(ctx context.Context, restConfig *rest.Config, authMode AuthMode, address string, useTLS, insecure bool, transport *TransportConfig) (http.RoundTripper, error) {
 	if restConfig == nil {
 		return nil, fmt.Errorf("no REST config available")
 	}
@@ -58,18 +85,52 @@ func BuildRoundTripper(ctx context.Context, restConfig *rest.Config, authMode Au
 		return nil, err
 	}
 
-	return createRoundTripperWithToken(restConfig, token, useTLS, insecure)
+	var credentials promcfg.SecretReader
+	if token != "" {
+		credentials = promcfg.NewInlineSecret(token)
+	}
+	return createRoundTripper(restConfig, credentials, address, useTLS, insecure, transport)
 }
 
-func createRoundTripperWithToken(restConfig *rest.Config, token string, useTLS, insecure bool) (http.RoundTripper, error) {
+func createRoundTripper(restConfig *rest.Config, credentials promcfg.SecretReader, address string, useTLS, insecure bool, transport *TransportConfig) (http.RoundTripper, error) {
+	var rt *http.Transport
+	var err error
+	if transport != nil {
+		rt, err = sharedTransport(restConfig, address, useTLS, insecure, *transport)
+	} else {
+		rt, err = newTransport(restConfig, useTLS, insecure, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	base := &queryParamsRoundTripper{next: &dryRunRoundTripper{next: rt}}
+
+	if !useTLS {
+		slog.Warn("Connecting without TLS")
+		return base, nil
+	}
+
+	if credentials != nil {
+		return promcfg.NewAuthorizationCredentialsRoundTripper("Bearer", credentials, base), nil
+	}
+
+	return base, nil
+}
+
+// newTransport returns a clone of the default transport of the Prometheus client with the
+// given connection pool settings, if any, verifying TLS connections with the cluster CA
+// unless insecure is set.
+func newTransport(restConfig *rest.Config, useTLS, insecure bool, transport *TransportConfig) (*http.Transport, error) {
 	defaultRt, ok := promapi.DefaultRoundTripper.(*http.Transport)
 	if !ok {
 		return nil, fmt.Errorf("unexpected RoundTripper type: %T, expected *http.Transport", promapi.DefaultRoundTripper)
 	}
 	rt := defaultRt.Clone()
-
+	if transport != nil {
+		transport.apply(rt)
+	}
 	if !useTLS {
-		slog.Warn("Connecting without TLS")
 		return rt, nil
 	}
 
@@ -88,12 +149,6 @@ func createRoundTripperWithToken(restConfig *rest.Config, token string, useTLS,
 			RootCAs:    certs,
 		}
 	}
-
-	if token != "" {
-		return promcfg.NewAuthorizationCredentialsRoundTripper(
-			"Bearer", promcfg.NewInlineSecret(token), rt), nil
-	}
-
 	return rt, nil
 }
 
@@ -146,3 +201,19 @@ func ContextWithAuthFromRequest(ctx context.Context, r *http.Request) context.Co
 	}
 	return ctx
 }
+
+// ContextWithGuardrailOverrideFromRequest stores the value of the given request header in
+// the context, to relax guardrails for the request. The header must only be read when it
+// is set by a trusted gateway, which strips it from client requests.
+func ContextWithGuardrailOverrideFromRequest(ctx context.Context, r *http.Request, header string) context.Context {
+	if value := strings.TrimSpace(r.Header.Get(header)); value != "" {
+		ctx = context.WithValue(ctx, guardrailOverrideKey{}, value)
+	}
+	return ctx
+}
+
+// GuardrailOverrideFromContext returns the guardrail override stored in the context, if any.
+func GuardrailOverrideFromContext(ctx context.Context) string {
+	value, _ := ctx.Value(guardrailOverrideKey{}).(string)
+	return value
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	promapi "github.com/prometheus/client_golang/api"
@@ -222,3 +223,84 @@ func TestContextWithAuthFromRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildRoundTripperWithTransport(t *testing.T) {
+	transport := TransportConfig{
+		MaxIdleConns:    10,
+		MaxConnsPerHost: 5,
+		IdleConnTimeout: 15 * time.Second,
+	}
+
+	rt, err := BuildRoundTripperWithTransport(context.Background(), &rest.Config{}, AuthModeKubeConfig, "https://prometheus.example:9091", true, true, transport)
+	require.NoError(t, err)
+
+	paramsRt, ok := rt.(*queryParamsRoundTripper)
+	require.True(t, ok, "expected *queryParamsRoundTripper without a bearer token, got %T", rt)
+	dryRunRt, ok := paramsRt.next.(*dryRunRoundTripper)
+	require.True(t, ok, "expected *dryRunRoundTripper, got %T", paramsRt.next)
+	httpRt, ok := dryRunRt.next.(*http.Transport)
+	require.True(t, ok, "expected *http.Transport, got %T", dryRunRt.next)
+	require.Equal(t, 10, httpRt.MaxIdleConns)
+	require.Equal(t, 10, httpRt.MaxIdleConnsPerHost)
+	require.Equal(t, 5, httpRt.MaxConnsPerHost)
+	require.Equal(t, 15*time.Second, httpRt.IdleConnTimeout)
+
+	// The shared default transport must not be modified.
+	defaultRt := promapi.DefaultRoundTripper.(*http.Transport)
+	require.Zero(t, defaultRt.MaxConnsPerHost)
+}
+
+func TestBuildRoundTripperWithTransport_SharesTransport(t *testing.T) {
+	transport := TransportConfig{MaxIdleConns: 10, MaxConnsPerHost: 5, IdleConnTimeout: 15 * time.Second}
+	httpTransport := func(rt http.RoundTripper) *http.Transport {
+		t.Helper()
+		paramsRt, ok := rt.(*queryParamsRoundTripper)
+		require.True(t, ok, "expected *queryParamsRoundTripper, got %T", rt)
+		return paramsRt.next.(*dryRunRoundTripper).next.(*http.Transport)
+	}
+
+	// Calls with different credentials, as tool calls in header mode, share the connection pool.
+	first, err := BuildRoundTripperWithTransport(ContextWithAuthFromRequest(t.Context(), requestWithAuth("Bearer first")), &rest.Config{}, AuthModeHeader, "http://prometheus.example:9090", false, false, transport)
+	require.NoError(t, err)
+	second, err := BuildRoundTripperWithTransport(ContextWithAuthFromRequest(t.Context(), requestWithAuth("Bearer second")), &rest.Config{}, AuthModeHeader, "http://prometheus.example:9090", false, false, transport)
+	require.NoError(t, err)
+	require.Same(t, httpTransport(first), httpTransport(second))
+
+	other, err := BuildRoundTripperWithTransport(t.Context(), &rest.Config{}, AuthModeHeader, "http://alertmanager.example:9093", false, false, transport)
+	require.NoError(t, err)
+	require.NotSame(t, httpTransport(first), httpTransport(other))
+
+	transport.MaxConnsPerHost = 1
+	tuned, err := BuildRoundTripperWithTransport(t.Context(), &rest.Config{}, AuthModeHeader, "http://prometheus.example:9090", false, false, transport)
+	require.NoError(t, err)
+	require.NotSame(t, httpTransport(first), httpTransport(tuned))
+	require.Equal(t, 1, httpTransport(tuned).MaxConnsPerHost)
+}
+
+func requestWithAuth(authorization string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/mcp", http.NoBody)
+	req.Header.Set("Authorization", authorization)
+	return req
+}
+
+func TestBuildRoundTripperWithTransport_EvictsLeastRecentlyUsedTransport(t *testing.T) {
+	transport := DefaultTransportConfig()
+	build := func(i int) *http.Transport {
+		t.Helper()
+		rt, err := BuildRoundTripperWithTransport(t.Context(), &rest.Config{}, AuthModeHeader, fmt.Sprintf("http://prometheus-%d.example:9090", i), false, false, transport)
+		require.NoError(t, err)
+		return rt.(*queryParamsRoundTripper).next.(*dryRunRoundTripper).next.(*http.Transport)
+	}
+
+	first := build(0)
+	second := build(1)
+	for i := 2; i < maxSharedTransports; i++ {
+		build(i)
+	}
+	// Using the first transport again makes the second one the least recently used.
+	require.Same(t, first, build(0))
+
+	build(maxSharedTransports)
+	require.Same(t, first, build(0))
+	require.NotSame(t, second, build(1))
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redactedValue = "<redacted>"
+
+// sensitiveHeaderRe matches header names whose values are redacted from recorded requests.
+var sensitiveHeaderRe = regexp.MustCompile(`(?i)(authorization|cookie|token|secret|password|api-?key)`)
+
+// errDryRun is returned by the transport in place of sending an intercepted request.
+var errDryRun = errors.New("request not sent: dry run")
+
+type dryRunKey struct{}
+
+// RecordedRequest is an outbound HTTP request captured during a dry run,
+// with the values of sensitive headers redacted.
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   string
+}
+
+// DryRunRecorder collects the requests made under a dry-run context.
+type DryRunRecorder struct {
+	intercept func(*http.Request) bool
+
+	mu          sync.Mutex
+	requests    []RecordedRequest
+	intercepted bool
+}
+
+// ContextWithDryRun returns a context under which round trippers built by this package
+// record every outbound request. Requests for which intercept returns true are not sent;
+// the round trip fails instead. Other requests are recorded and sent as usual.
+func ContextWithDryRun(ctx context.Context, intercept func(*http.Request) bool) (context.Context, *DryRunRecorder) {
+	rec := &DryRunRecorder{intercept: intercept}
+	return context.WithValue(ctx, dryRunKey{}, rec), rec
+}
+
+// Requests returns the recorded requests in the order they were made.
+func (r *DryRunRecorder) Requests() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedRequest(nil), r.requests...)
+}
+
+// Intercepted reports whether at least one request was held back instead of being sent.
+// Errors returned by the client after an interception are a consequence of the dry run.
+func (r *DryRunRecorder) Intercepted() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.intercepted
+}
+
+func (r *DryRunRecorder) record(req *http.Request) (bool, error) {
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: redactHeader(req.Header),
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return false, err
+		}
+		b, err := io.ReadAll(body)
+		_ = body.Close()
+		if err != nil {
+			return false, err
+		}
+		recorded.Body = string(b)
+	}
+
+	intercept := r.intercept == nil || r.intercept(req)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, recorded)
+	r.intercepted = r.intercepted || intercept
+	return intercept, nil
+}
+
+// redactHeader returns a copy of the header with sensitive values replaced,
+// keeping the authorization scheme (e.g. "Bearer") so that it can still be checked.
+func redactHeader(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for name, values := range h {
+		if !sensitiveHeaderRe.MatchString(name) {
+			redacted[name] = append([]string(nil), values...)
+			continue
+		}
+		for _, v := range values {
+			if scheme, _, ok := strings.Cut(v, " "); ok && strings.EqualFold(name, "Authorization") {
+				redacted[name] = append(redacted[name], scheme+" "+redactedValue)
+			} else {
+				redacted[name] = append(redacted[name], redactedValue)
+			}
+		}
+	}
+	return redacted
+}
+
+// dryRunRoundTripper sits below the authentication round trippers, so that recorded
+// requests carry every header that would be sent to the backend.
+type dryRunRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec, ok := req.Context().Value(dryRunKey{}).(*DryRunRecorder)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	intercept, err := rec.record(req)
+	if err != nil {
+		return nil, err
+	}
+	if intercept {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, errDryRun
+	}
+	return rt.next.RoundTrip(req)
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestDryRunRoundTripper(t *testing.T) {
+	var served []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = append(served, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.WithValue(t.Context(), kubernetes.OAuthAuthorizationHeader, "Bearer secret-token")
+	rt, err := BuildRoundTripper(ctx, &rest.Config{}, AuthModeHeader, true, true)
+	require.NoError(t, err)
+
+	dryCtx, rec := ContextWithDryRun(ctx, func(r *http.Request) bool {
+		return strings.HasSuffix(r.URL.Path, "/api/v1/query")
+	})
+
+	// Requests that are not intercepted are recorded and sent.
+	req, err := http.NewRequestWithContext(dryCtx, http.MethodGet, server.URL+"/api/v1/labels", http.NoBody)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.False(t, rec.Intercepted())
+
+	// Intercepted requests are recorded but never reach the server.
+	req, err = http.NewRequestWithContext(dryCtx, http.MethodPost, server.URL+"/api/v1/query", strings.NewReader("query=up"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Api-Key", "abc")
+	_, err = rt.RoundTrip(req)
+	require.ErrorIs(t, err, errDryRun)
+	require.True(t, rec.Intercepted())
+	require.Equal(t, []string{"/api/v1/labels"}, served)
+
+	requests := rec.Requests()
+	require.Len(t, requests, 2)
+	query := requests[1]
+	require.Equal(t, http.MethodPost, query.Method)
+	require.Equal(t, server.URL+"/api/v1/query", query.URL)
+	require.Equal(t, "query=up", query.Body)
+	require.Equal(t, "Bearer <redacted>", query.Header.Get("Authorization"))
+	require.Equal(t, "<redacted>", query.Header.Get("X-Api-Key"))
+	require.Equal(t, "application/x-www-form-urlencoded", query.Header.Get("Content-Type"))
+
+	// Without a dry-run context, requests are sent as usual.
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/api/v1/query", http.NoBody)
+	require.NoError(t, err)
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Len(t, served, 2)
+}
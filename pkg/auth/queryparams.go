@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+)
+
+type queryParamsKey struct{}
+
+// ContextWithQueryParams returns a context under which round trippers built by this
+// package add the given parameters to the URL of every outbound request, e.g. to pass
+// backend-specific options the API client does not know about. Parameters already
+// present on a request are replaced.
+func ContextWithQueryParams(ctx context.Context, params url.Values) context.Context {
+	if len(params) == 0 {
+		return ctx
+	}
+	merged := url.Values{}
+	if existing, ok := ctx.Value(queryParamsKey{}).(url.Values); ok {
+		maps.Copy(merged, existing)
+	}
+	maps.Copy(merged, params)
+	return context.WithValue(ctx, queryParamsKey{}, merged)
+}
+
+// queryParamsRoundTripper sits above the dry-run round tripper, so that recorded requests
+// carry the added parameters.
+type queryParamsRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *queryParamsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	params, ok := req.Context().Value(queryParamsKey{}).(url.Values)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	// RoundTrip must not modify the request it was given.
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	req.URL.RawQuery = query.Encode()
+	return rt.next.RoundTrip(req)
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestQueryParamsRoundTripper(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rt, err := BuildRoundTripper(context.Background(), &rest.Config{}, AuthModeKubeConfig, false, false)
+	require.NoError(t, err)
+
+	ctx := ContextWithQueryParams(t.Context(), url.Values{"dedup": {"false"}})
+	ctx = ContextWithQueryParams(ctx, url.Values{"max_source_resolution": {"1h"}, "step": {"60"}})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/query_range?query=up&step=30", http.NoBody)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, queries, 1)
+	require.Equal(t, url.Values{
+		"query":                 {"up"},
+		"step":                  {"60"},
+		"dedup":                 {"false"},
+		"max_source_resolution": {"1h"},
+	}, queries[0])
+	// The caller's request is left untouched.
+	require.Equal(t, "query=up&step=30", req.URL.RawQuery)
+
+	// Recorded dry-run requests carry the parameters too.
+	dryCtx, rec := ContextWithDryRun(ctx, nil)
+	req, err = http.NewRequestWithContext(dryCtx, http.MethodPost, server.URL+"/api/v1/query", http.NoBody)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	require.ErrorIs(t, err, errDryRun)
+	require.Equal(t, server.URL+"/api/v1/query?dedup=false&max_source_resolution=1h&step=60", rec.Requests()[0].URL)
+}
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"os"
@@ -63,3 +65,14 @@ func readTokenFromContext(ctx context.Context) string {
 	}
 	return strings.TrimSpace(authHeader)
 }
+
+// CredentialScope identifies the credentials requests are sent with under the given auth
+// mode, without revealing them. In kubeconfig mode all requests share the server's identity;
+// in header mode the scope is derived from the caller's token.
+func CredentialScope(ctx context.Context, authMode AuthMode) string {
+	if authMode != AuthModeHeader {
+		return string(authMode)
+	}
+	sum := sha256.Sum256([]byte(readTokenFromContext(ctx)))
+	return string(authMode) + ":" + hex.EncodeToString(sum[:])
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
@@ -48,3 +49,24 @@ func TestReadTokenFromContext(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialScope(t *testing.T) {
+	ctxWithToken := func(token string) context.Context {
+		return context.WithValue(context.Background(), kubernetes.OAuthAuthorizationHeader, "Bearer "+token)
+	}
+
+	if got := CredentialScope(ctxWithToken("a"), AuthModeKubeConfig); got != CredentialScope(ctxWithToken("b"), AuthModeKubeConfig) {
+		t.Errorf("expected a single scope in kubeconfig mode, got %q", got)
+	}
+
+	scopeA := CredentialScope(ctxWithToken("token-a"), AuthModeHeader)
+	if scopeA != CredentialScope(ctxWithToken("token-a"), AuthModeHeader) {
+		t.Error("expected the same token to yield the same scope")
+	}
+	if scopeA == CredentialScope(ctxWithToken("token-b"), AuthModeHeader) {
+		t.Error("expected different tokens to yield different scopes")
+	}
+	if strings.Contains(scopeA, "token-a") {
+		t.Errorf("scope %q reveals the token", scopeA)
+	}
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	promcfg "github.com/prometheus/common/config"
+)
+
+// DefaultTokenReloadInterval is how often a token file is re-read when no interval is configured.
+const DefaultTokenReloadInterval = time.Minute
+
+// FileTokenProvider reads the bearer token sent to the backends from a file, e.g. a
+// projected service account token, and re-reads it once the reload interval has passed,
+// so that rotated tokens are picked up without a restart.
+type FileTokenProvider struct {
+	path     string
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	token    string
+	loadedAt time.Time
+}
+
+var _ promcfg.SecretReader = (*FileTokenProvider)(nil)
+
+// NewFileTokenProvider returns a provider of the token in the file at path, re-read every interval.
+func NewFileTokenProvider(path string, interval time.Duration) *FileTokenProvider {
+	return &FileTokenProvider{path: path, interval: interval, now: time.Now}
+}
+
+type fileTokenKey struct {
+	path     string
+	interval time.Duration
+}
+
+var (
+	fileTokenProvidersMu sync.Mutex
+	fileTokenProviders   = map[fileTokenKey]*FileTokenProvider{}
+)
+
+// FileToken returns the provider of the token in the file at path. Backend clients are
+// created per tool call, so they share one provider per file, which reads the file at
+// most once per interval.
+func FileToken(path string, interval time.Duration) *FileTokenProvider {
+	fileTokenProvidersMu.Lock()
+	defer fileTokenProvidersMu.Unlock()
+
+	key := fileTokenKey{path: path, interval: interval}
+	provider, ok := fileTokenProviders[key]
+	if !ok {
+		provider = NewFileTokenProvider(path, interval)
+		fileTokenProviders[key] = provider
+	}
+	return provider
+}
+
+// Fetch returns the token, re-reading the file when the reload interval has passed. If
+// the file cannot be re-read, e.g. while it is being replaced, the previous token is
+// returned until the next reload.
+func (p *FileTokenProvider) Fetch(_ context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.token != "" && now.Sub(p.loadedAt) < p.interval {
+		return p.token, nil
+	}
+
+	token, err := readTokenFile(p.path)
+	if err != nil {
+		if p.token == "" {
+			return "", err
+		}
+		slog.Warn("Failed to reload token file, using the previous token", "file", p.path, "error", err)
+		p.loadedAt = now
+		return p.token, nil
+	}
+
+	if p.token != "" && token != p.token {
+		slog.Info("Reloaded rotated token", "file", p.path)
+	}
+	p.token = token
+	p.loadedAt = now
+	return token, nil
+}
+
+// Description implements promcfg.SecretReader.
+func (p *FileTokenProvider) Description() string {
+	return "file " + p.path
+}
+
+// Immutable implements promcfg.SecretReader.
+func (p *FileTokenProvider) Immutable() bool {
+	return false
+}
+
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
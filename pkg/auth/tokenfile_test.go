@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestFileTokenProvider_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeToken := func(token string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+			t.Fatalf("failed to write token file: %v", err)
+		}
+	}
+	writeToken("token-1")
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewFileTokenProvider(path, time.Minute)
+	provider.now = func() time.Time { return now }
+
+	fetch := func(want string) {
+		t.Helper()
+		got, err := provider.Fetch(t.Context())
+		if err != nil {
+			t.Fatalf("Fetch() unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("Fetch() = %q, want %q", got, want)
+		}
+	}
+
+	fetch("token-1")
+
+	// The rotated token is not read before the reload interval has passed.
+	writeToken("token-2")
+	now = now.Add(30 * time.Second)
+	fetch("token-1")
+
+	now = now.Add(30 * time.Second)
+	fetch("token-2")
+
+	// A token file that cannot be read keeps the previous token.
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove token file: %v", err)
+	}
+	now = now.Add(time.Minute)
+	fetch("token-2")
+}
+
+func TestFileTokenProvider_MissingFile(t *testing.T) {
+	provider := NewFileTokenProvider(filepath.Join(t.TempDir(), "missing"), time.Minute)
+	if _, err := provider.Fetch(t.Context()); err == nil {
+		t.Error("expected error for missing token file, got nil")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(empty, []byte("\n"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	if _, err := NewFileTokenProvider(empty, time.Minute).Fetch(t.Context()); err == nil {
+		t.Error("expected error for empty token file, got nil")
+	}
+}
+
+func TestBuildRoundTripperWithTokenFile(t *testing.T) {
+	var authorization string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-token"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	restConfig := &rest.Config{BearerToken: "kubeconfig-token"}
+	rt, err := BuildRoundTripperWithTokenFile(restConfig, NewFileTokenProvider(path, time.Minute), srv.URL, true, true, DefaultTransportConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, http.NoBody)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if authorization != "Bearer file-token" {
+		t.Errorf("Authorization = %q, want %q", authorization, "Bearer file-token")
+	}
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// Default connection pool settings for backend clients. Tool calls arrive in bursts
+// and each one talks to the same backend host, so idle connections are kept per host
+// instead of Go's default of 2, and dropped quickly once the burst is over. The number
+// of connections per host is not limited, as before the settings were introduced.
+const (
+	DefaultMaxIdleConns    = 100
+	DefaultMaxConnsPerHost = 0
+	DefaultIdleConnTimeout = 30 * time.Second
+)
+
+// maxSharedTransports bounds the number of shared transports. Each key combines the
+// backend address with the TLS settings of the caller, so that their number grows with
+// the backends and CA bundles seen.
+const maxSharedTransports = 64
+
+// TransportConfig tunes the connection pool of the HTTP transport used for backend clients.
+// Zero values follow the http.Transport semantics: no limit, or no idle timeout.
+type TransportConfig struct {
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections kept open.
+	MaxIdleConns int
+	// MaxConnsPerHost limits the total number of connections per backend host.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept before it is closed.
+	IdleConnTimeout time.Duration
+}
+
+// DefaultTransportConfig returns the connection pool settings used when none are configured.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:    DefaultMaxIdleConns,
+		MaxConnsPerHost: DefaultMaxConnsPerHost,
+		IdleConnTimeout: DefaultIdleConnTimeout,
+	}
+}
+
+func (c TransportConfig) apply(rt *http.Transport) {
+	rt.MaxIdleConns = c.MaxIdleConns
+	rt.MaxIdleConnsPerHost = c.MaxIdleConns
+	rt.MaxConnsPerHost = c.MaxConnsPerHost
+	rt.IdleConnTimeout = c.IdleConnTimeout
+}
+
+// transportKey identifies the transports that can be shared: the ones for the same
+// backend with the same TLS verification and connection pool settings.
+type transportKey struct {
+	address  string
+	useTLS   bool
+	insecure bool
+	caData   string
+	config   TransportConfig
+}
+
+// sharedTransports holds a transport per transportKey, up to maxSharedTransports, evicting
+// the least recently used one. Backend clients are created per tool call, and their
+// connection pools must be shared for the pool settings to bound the connections of
+// concurrent calls and for idle connections to be reused.
+var (
+	sharedTransportsMu sync.Mutex
+	sharedTransports   = map[transportKey]*list.Element{}
+	// sharedTransportsLRU orders the *sharedTransportEntry values, most recently used first.
+	sharedTransportsLRU = list.New()
+)
+
+type sharedTransportEntry struct {
+	key transportKey
+	rt  *http.Transport
+}
+
+// sharedTransport returns the transport for the backend at address, creating it on first use.
+func sharedTransport(restConfig *rest.Config, address string, useTLS, insecure bool, config TransportConfig) (*http.Transport, error) {
+	key := transportKey{
+		address:  address,
+		useTLS:   useTLS,
+		insecure: insecure,
+		caData:   string(restConfig.CAData),
+		config:   config,
+	}
+
+	sharedTransportsMu.Lock()
+	defer sharedTransportsMu.Unlock()
+	if elem, ok := sharedTransports[key]; ok {
+		sharedTransportsLRU.MoveToFront(elem)
+		return elem.Value.(*sharedTransportEntry).rt, nil
+	}
+
+	rt, err := newTransport(restConfig, useTLS, insecure, &config)
+	if err != nil {
+		return nil, err
+	}
+	sharedTransports[key] = sharedTransportsLRU.PushFront(&sharedTransportEntry{key: key, rt: rt})
+
+	if sharedTransportsLRU.Len() > maxSharedTransports {
+		// Requests in flight on the evicted transport complete; only its idle connections are closed.
+		oldest := sharedTransportsLRU.Remove(sharedTransportsLRU.Back()).(*sharedTransportEntry)
+		delete(sharedTransports, oldest.key)
+		oldest.rt.CloseIdleConnections()
+	}
+	return rt, nil
+}
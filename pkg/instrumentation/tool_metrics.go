@@ -62,13 +62,15 @@ func NewToolMetrics(reg prometheus.Registerer) *ToolMetrics {
 }
 
 // ToolHandler wraps an MCP tool handler with metrics instrumentation.
-// It records call counts, durations, and error details for each tool invocation.
+// It records call counts, durations, and error details for each tool invocation,
+// and a span per invocation when tracing is set up.
 func ToolHandler[I, O any](
 	toolName string,
 	metrics *ToolMetrics,
 	handler mcp.ToolHandlerFor[I, O],
 ) mcp.ToolHandlerFor[I, O] {
-	// If metrics is nil, return the handler unchanged (for tests or when metrics disabled)
+	handler = tracedToolHandler(toolName, handler)
+	// If metrics is nil, return the traced handler (for tests or when metrics disabled)
 	if metrics == nil {
 		return handler
 	}
@@ -103,6 +105,7 @@ func ToolHandlerUntyped(
 	metrics *ToolMetrics,
 	handler mcp.ToolHandler,
 ) mcp.ToolHandler {
+	handler = tracedToolHandlerUntyped(toolName, handler)
 	if metrics == nil {
 		return handler
 	}
@@ -127,6 +130,26 @@ func ToolHandlerUntyped(
 	}
 }
 
+// tracedToolHandler wraps an MCP tool handler in a span per invocation.
+func tracedToolHandler[I, O any](toolName string, handler mcp.ToolHandlerFor[I, O]) mcp.ToolHandlerFor[I, O] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input I) (*mcp.CallToolResult, O, error) {
+		ctx, span := startToolSpan(ctx, toolName)
+		result, output, err := handler(ctx, req, input)
+		endToolSpan(span, result, err)
+		return result, output, err
+	}
+}
+
+// tracedToolHandlerUntyped is the equivalent of tracedToolHandler for untyped handlers.
+func tracedToolHandlerUntyped(toolName string, handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := startToolSpan(ctx, toolName)
+		result, err := handler(ctx, req)
+		endToolSpan(span, result, err)
+		return result, err
+	}
+}
+
 // categorizeError categorizes errors into types for metrics labeling.
 func categorizeError(err error) string {
 	if err == nil {
@@ -0,0 +1,98 @@
+package instrumentation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/rhobs/obs-mcp"
+
+// traceContext propagates the W3C traceparent and tracestate headers. It is used
+// directly rather than through the global propagator, so that the trace context of
+// incoming requests is forwarded to the backends even when no exporter is configured.
+var traceContext = propagation.TraceContext{}
+
+// SetupTracing exports a span per tool call to the OTLP/HTTP endpoint, e.g.
+// http://otel-collector:4318. Without it, tool calls are not traced, but incoming trace
+// context is still forwarded to the backends. The returned function flushes the
+// pending spans and stops the exporter.
+func SetupTracing(ctx context.Context, endpoint, serviceVersion string) (shutdown func(context.Context) error, err error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "obs-mcp"),
+			attribute.String("service.version", serviceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(traceContext)
+	return provider.Shutdown, nil
+}
+
+// TraceContextMiddleware adds the trace context of the traceparent header of incoming
+// requests, if any, to their context.
+func TraceContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := traceContext.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type traceContextRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *traceContextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		return rt.next.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the request they are given.
+	req = req.Clone(req.Context())
+	traceContext.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+	return rt.next.RoundTrip(req)
+}
+
+// TraceContextRoundTripper sets the traceparent header of outbound requests from the
+// trace context of their context, i.e. that of the tool call span or, when tracing is
+// not set up, that of the incoming request. Requests without a trace context are sent
+// unchanged.
+func TraceContextRoundTripper(tripper http.RoundTripper) http.RoundTripper {
+	return &traceContextRoundTripper{next: tripper}
+}
+
+// startToolSpan starts the span of a tool call, which is a no-op unless SetupTracing
+// was called.
+func startToolSpan(ctx context.Context, toolName string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "tools/call "+toolName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("mcp.tool.name", toolName)),
+	)
+}
+
+// endToolSpan records the outcome of a tool call on its span and ends it.
+func endToolSpan(span trace.Span, result *mcp.CallToolResult, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case result != nil && result.IsError:
+		span.SetStatus(codes.Error, "tool returned an error")
+	}
+	span.End()
+}
@@ -0,0 +1,109 @@
+package instrumentation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type recordingRoundTripper struct {
+	header http.Header
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.header = req.Header
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestTraceContextForwarding(t *testing.T) {
+	backend := &recordingRoundTripper{}
+	client := &http.Client{Transport: TraceContextRoundTripper(backend)}
+	handler := TraceContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, "http://prometheus/api/v1/query", http.NoBody)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+	}))
+
+	t.Run("incoming trace context is forwarded", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+		req.Header.Set("traceparent", testTraceparent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if got := backend.header.Get("traceparent"); got != testTraceparent {
+			t.Errorf("backend traceparent = %q, want %q", got, testTraceparent)
+		}
+	})
+
+	t.Run("requests without trace context are sent unchanged", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if got := backend.header.Get("traceparent"); got != "" {
+			t.Errorf("backend traceparent = %q, want none", got)
+		}
+	})
+
+	t.Run("invalid trace context is dropped", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+		req.Header.Set("traceparent", "not-a-traceparent")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if got := backend.header.Get("traceparent"); got != "" {
+			t.Errorf("backend traceparent = %q, want none", got)
+		}
+	})
+}
+
+func TestToolHandlerSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+	req.Header.Set("traceparent", testTraceparent)
+	ctx := traceContext.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+	var handlerSpan trace.SpanContext
+	handler := ToolHandler("execute_instant_query", nil, func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, struct{}, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil, struct{}{}, errors.New("invalid query")
+	})
+	if _, _, err := handler(ctx, &mcp.CallToolRequest{}, struct{}{}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "tools/call execute_instant_query" {
+		t.Errorf("span name = %q, want %q", span.Name(), "tools/call execute_instant_query")
+	}
+	if got := span.Parent().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("parent trace ID = %s, want that of the incoming traceparent", got)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want %v", span.Status().Code, codes.Error)
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("handler context does not carry the tool call span")
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -20,6 +25,7 @@ const (
 	prometheusRouteName    = "prometheus-k8s"
 	alertmanagerRouteName  = "alertmanager-main"
 	routeDiscoveryTimeout  = 10 * time.Second
+	configMapReadTimeout   = 10 * time.Second
 )
 
 // routeResponse represents the OpenShift Route API response structure
@@ -147,3 +153,51 @@ func parseHostFromRouteBody(body []byte) (string, error) {
 func GetAlertmanagerURL() (string, error) {
 	return discoverRoute(alertmanagerRouteName)
 }
+
+// ParseConfigMapRef splits a reference to a ConfigMap key given as "namespace/name/key".
+func ParseConfigMapRef(ref string) (namespace, name, key string, err error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return "", "", "", fmt.Errorf("invalid ConfigMap reference %q, must be namespace/name/key", ref)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// GetConfigMapValue reads the value of a ConfigMap key given as "namespace/name/key",
+// with surrounding whitespace trimmed.
+func GetConfigMapValue(ref string) (string, error) {
+	namespace, name, key, err := ParseConfigMapRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), configMapReadTimeout)
+	defer cancel()
+
+	kubeClient, err := GetKubeClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+	return configMapValue(ctx, kubeClient, namespace, name, key)
+}
+
+func configMapValue(ctx context.Context, client kubernetes.Interface, namespace, name, key string) (string, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("ConfigMap %s/%s not found", namespace, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	value, ok := cm.Data[key]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap %s/%s has no key %q (keys: %s)",
+			namespace, name, key, strings.Join(slices.Sorted(maps.Keys(cm.Data)), ", "))
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("key %q of ConfigMap %s/%s is empty", key, namespace, name)
+	}
+	return value, nil
+}
@@ -2,6 +2,10 @@ package k8s
 
 import (
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestGetRouteURLParseHost(t *testing.T) {
@@ -85,3 +89,86 @@ func TestGetRouteURLParseHost(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConfigMapRef(t *testing.T) {
+	namespace, name, key, err := ParseConfigMapRef("monitoring/obs-mcp/prometheus-url")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if namespace != "monitoring" || name != "obs-mcp" || key != "prometheus-url" {
+		t.Errorf("got %s/%s/%s, want monitoring/obs-mcp/prometheus-url", namespace, name, key)
+	}
+
+	for _, ref := range []string{"", "obs-mcp/prometheus-url", "monitoring//prometheus-url", "a/b/c/d", "monitoring/obs-mcp/"} {
+		if _, _, _, err := ParseConfigMapRef(ref); err == nil {
+			t.Errorf("ParseConfigMapRef(%q) succeeded, want an error", ref)
+		}
+	}
+}
+
+func TestConfigMapValue(t *testing.T) {
+	client := fake.NewClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "obs-mcp"},
+		Data: map[string]string{
+			"prometheus-url": "https://thanos-querier.monitoring.svc:9091\n",
+			"loki-url":       "http://loki.monitoring.svc:3100",
+			"empty":          " ",
+		},
+	})
+
+	tests := []struct {
+		name      string
+		namespace string
+		configMap string
+		key       string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "value is trimmed",
+			namespace: "monitoring",
+			configMap: "obs-mcp",
+			key:       "prometheus-url",
+			want:      "https://thanos-querier.monitoring.svc:9091",
+		},
+		{
+			name:      "missing ConfigMap",
+			namespace: "default",
+			configMap: "obs-mcp",
+			key:       "prometheus-url",
+			wantErr:   "ConfigMap default/obs-mcp not found",
+		},
+		{
+			name:      "missing key lists the available ones",
+			namespace: "monitoring",
+			configMap: "obs-mcp",
+			key:       "thanos-url",
+			wantErr:   `ConfigMap monitoring/obs-mcp has no key "thanos-url" (keys: empty, loki-url, prometheus-url)`,
+		},
+		{
+			name:      "empty value",
+			namespace: "monitoring",
+			configMap: "obs-mcp",
+			key:       "empty",
+			wantErr:   `key "empty" of ConfigMap monitoring/obs-mcp is empty`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := configMapValue(t.Context(), client, tt.namespace, tt.configMap, tt.key)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
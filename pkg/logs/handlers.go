@@ -1,6 +1,7 @@
 package logs
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -31,7 +32,7 @@ func labelNamesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error
 		return api.NewToolCallResult("", fmt.Errorf("failed to list label names: %w", err)), nil
 	}
 
-	start, end, err := parseDefaultTimeRange(startStr, endStr)
+	start, end, err := parseDefaultTimeRange(params.Context, startStr, endStr)
 	if err != nil {
 		return api.NewToolCallResult("", err), nil
 	}
@@ -62,7 +63,7 @@ func labelValuesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, erro
 		return api.NewToolCallResult("", fmt.Errorf("label parameter is required and must be a string")), nil
 	}
 
-	start, end, err := parseDefaultTimeRange(startStr, endStr)
+	start, end, err := parseDefaultTimeRange(params.Context, startStr, endStr)
 	if err != nil {
 		return api.NewToolCallResult("", err), nil
 	}
@@ -96,7 +97,7 @@ func queryRangeHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error
 		return api.NewToolCallResult("", fmt.Errorf("query parameter is required and must be a string")), nil
 	}
 
-	start, end, err := parseQueryTimeRange(startStr, endStr, duration)
+	start, end, err := parseQueryTimeRange(params.Context, startStr, endStr, duration)
 	if err != nil {
 		return api.NewToolCallResult("", err), nil
 	}
@@ -158,9 +159,9 @@ func listInstancesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, er
 	return api.NewToolCallResultStructured(result, nil), nil
 }
 
-func parseDefaultTimeRange(start, end string) (startTime, endTime time.Time, err error) {
+func parseDefaultTimeRange(ctx context.Context, start, end string) (startTime, endTime time.Time, err error) {
 	if start == "" && end == "" {
-		endTime = time.Now()
+		endTime = prometheus.Now(ctx)
 		startTime = endTime.Add(-defaultQueryLookback)
 		return startTime, endTime, nil
 	}
@@ -168,11 +169,11 @@ func parseDefaultTimeRange(start, end string) (startTime, endTime time.Time, err
 		return time.Time{}, time.Time{}, fmt.Errorf("both start and end must be provided together")
 	}
 
-	startTime, err = prometheus.ParseTimestamp(start)
+	startTime, err = prometheus.ParseTimestamp(ctx, start)
 	if err != nil {
 		return time.Time{}, time.Time{}, fmt.Errorf("invalid start time format: %w", err)
 	}
-	endTime, err = prometheus.ParseTimestamp(end)
+	endTime, err = prometheus.ParseTimestamp(ctx, end)
 	if err != nil {
 		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time format: %w", err)
 	}
@@ -182,9 +183,9 @@ func parseDefaultTimeRange(start, end string) (startTime, endTime time.Time, err
 	return startTime, endTime, nil
 }
 
-func parseQueryTimeRange(startStr, endStr, durationStr string) (start, end time.Time, err error) {
+func parseQueryTimeRange(ctx context.Context, startStr, endStr, durationStr string) (start, end time.Time, err error) {
 	if startStr != "" || endStr != "" {
-		return parseDefaultTimeRange(startStr, endStr)
+		return parseDefaultTimeRange(ctx, startStr, endStr)
 	}
 
 	dur := defaultQueryLookback
@@ -199,7 +200,7 @@ func parseQueryTimeRange(startStr, endStr, durationStr string) (start, end time.
 		}
 	}
 
-	end = time.Now()
+	end = prometheus.Now(ctx)
 	start = end.Add(-dur)
 	return start, end, nil
 }
@@ -221,6 +222,7 @@ func getLokiClient(params api.ToolHandlerParams) (loki.Loader, error) {
 	}
 
 	rt = instrumentation.RoundTripper(rt, cfg.ClientMetrics, "loki")
+	rt = instrumentation.TraceContextRoundTripper(rt)
 
 	httpClient := &http.Client{
 		Timeout:   loki.RequestTimeout,
@@ -2,7 +2,9 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	promapi "github.com/prometheus/client_golang/api"
@@ -24,6 +26,12 @@ const (
 	TestAlertmanagerClientKey ContextKey = "test-alertmanager-client"
 )
 
+// errAlertmanagerNotConfigured is returned by the tools calling Alertmanager when the
+// server was created without an Alertmanager URL, e.g. when embedding the server.
+var errAlertmanagerNotConfigured = errors.New("alerts are not configured for this server: set --alertmanager-url or " +
+	"ALERTMANAGER_URL to enable the Alertmanager tools; get_alert_history and get_alert_threshold work without it, " +
+	"as they read the ALERTS metric from Prometheus")
+
 func getPromClient(ctx context.Context, opts ObsMCPOptions) (prometheus.Loader, error) {
 	// Check if a test client was injected via context
 	if testClient := ctx.Value(TestPromClientKey); testClient != nil {
@@ -49,11 +57,15 @@ func getPromClient(ctx context.Context, opts ObsMCPOptions) (prometheus.Loader,
 		return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
 	}
 
-	guardrails, err := opts.Metrics.GetGuardrails()
+	guardrails, err := opts.Metrics.GetRequestGuardrails(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse guardrails: %w", err)
 	}
 	promClient.WithGuardrails(guardrails)
+	promClient.WithQueryLogging(opts.Metrics.LogQueries)
+	promClient.WithRangeSplitting(opts.Metrics.GetMaxSplitPoints())
+	promClient.WithMetricNameTransform(opts.Metrics.GetMetricNameTransform())
+	promClient.WithSharedScope(auth.CredentialScope(ctx, opts.Metrics.GetAuthMode()))
 
 	return promClient, nil
 }
@@ -66,6 +78,10 @@ func getAlertmanagerClient(ctx context.Context, opts ObsMCPOptions) (alertmanage
 		}
 	}
 
+	if opts.Metrics.AlertmanagerURL == "" {
+		return nil, errAlertmanagerNotConfigured
+	}
+
 	apiConfig, err := createAPIConfig(ctx, opts, opts.Metrics.AlertmanagerURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API config: %w", err)
@@ -90,14 +106,29 @@ func createAPIConfig(ctx context.Context, opts ObsMCPOptions, url string) (proma
 		return promapi.Config{}, fmt.Errorf("failed to get kubeconfig: %w", err)
 	}
 
+	transport, err := opts.Metrics.GetTransportConfig()
+	if err != nil {
+		return promapi.Config{}, err
+	}
+
+	tokens, err := opts.Metrics.GetTokenFile()
+	if err != nil {
+		return promapi.Config{}, err
+	}
+
 	tls := strings.HasPrefix(url, "https://")
-	rt, err := auth.BuildRoundTripper(ctx, restConfig, opts.Metrics.GetAuthMode(), tls, opts.Metrics.Insecure)
+	var rt http.RoundTripper
+	if tokens != nil {
+		rt, err = auth.BuildRoundTripperWithTokenFile(restConfig, tokens, url, tls, opts.Metrics.Insecure, transport)
+	} else {
+		rt, err = auth.BuildRoundTripperWithTransport(ctx, restConfig, opts.Metrics.GetAuthMode(), url, tls, opts.Metrics.Insecure, transport)
+	}
 	if err != nil {
 		return promapi.Config{}, fmt.Errorf("failed to create round tripper: %w", err)
 	}
 
 	return promapi.Config{
 		Address:      url,
-		RoundTripper: rt,
+		RoundTripper: instrumentation.TraceContextRoundTripper(rt),
 	}, nil
 }
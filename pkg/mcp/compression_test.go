@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGzipMiddleware(t *testing.T) {
+	const body = `{"jsonrpc":"2.0","id":1,"result":{"content":[]}}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		wantGzip       bool
+	}{
+		{
+			name:           "json response is compressed when client accepts gzip",
+			acceptEncoding: "gzip, deflate",
+			contentType:    "application/json",
+			wantGzip:       true,
+		},
+		{
+			name:        "response is not compressed without Accept-Encoding",
+			contentType: "application/json",
+		},
+		{
+			name:           "gzip with q=0 is refused",
+			acceptEncoding: "gzip;q=0, deflate",
+			contentType:    "application/json",
+		},
+		{
+			name:           "event stream is never compressed",
+			acceptEncoding: "gzip",
+			contentType:    "text/event-stream",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(body))
+				w.(http.Flusher).Flush()
+			})
+
+			ts := httptest.NewServer(gzipMiddleware(inner))
+			defer ts.Close()
+
+			req, err := http.NewRequest("POST", ts.URL, http.NoBody)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			if tt.acceptEncoding != "" {
+				// Setting the header explicitly disables transparent decompression in the client.
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			gotGzip := resp.Header.Get("Content-Encoding") == "gzip"
+			if gotGzip != tt.wantGzip {
+				t.Fatalf("Content-Encoding gzip = %v, want %v", gotGzip, tt.wantGzip)
+			}
+
+			var reader io.Reader = resp.Body
+			if gotGzip {
+				gz, err := gzip.NewReader(resp.Body)
+				if err != nil {
+					t.Fatalf("failed to create gzip reader: %v", err)
+				}
+				defer gz.Close()
+				reader = gz
+			}
+
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+			if string(got) != body {
+				t.Errorf("body = %q, want %q", got, body)
+			}
+		})
+	}
+}
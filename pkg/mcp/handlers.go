@@ -2,7 +2,10 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -18,33 +21,66 @@ func ListMetricsHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.ListMetrics
 			return nil, tools.ListMetricsOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
 		}
 
-		result := tools.ListMetricsHandler(ctx, promClient, input)
+		result := tools.ListMetricsHandler(ctx, promClient, input, opts.Metrics.GetLimit(tools.LimitListMetrics))
 		output, err := resultutil.Unwrap[tools.ListMetricsOutput](result)
 		if err != nil {
 			return nil, tools.ListMetricsOutput{}, err
 		}
+		// A truncated list is not the whole catalog and must not replace it.
+		if isMatchAllRegex(input.NameRegex) && !output.Truncated {
+			opts.catalog.update(ctx, output.Metrics)
+		}
+		return nil, output, nil
+	}
+}
+
+// ListMetricGroupsHandler handles grouping the available metrics by name prefix.
+// The metric names are taken from the cached metric catalog when it is fresh.
+func ListMetricGroupsHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.MetricGroupsInput, tools.MetricGroupsOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.MetricGroupsInput) (*mcp.CallToolResult, tools.MetricGroupsOutput, error) {
+		metrics, ok := opts.catalog.get()
+		if !ok {
+			promClient, err := getPromClient(ctx, opts)
+			if err != nil {
+				return nil, tools.MetricGroupsOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+			}
+
+			metrics, err = promClient.ListMetrics(ctx, ".*")
+			if err != nil {
+				return nil, tools.MetricGroupsOutput{}, fmt.Errorf("failed to list metrics: %w", err)
+			}
+			metrics = opts.catalog.update(ctx, metrics)
+		}
+
+		result := tools.ListMetricGroupsHandler(ctx, metrics, input)
+		output, err := resultutil.Unwrap[tools.MetricGroupsOutput](result)
+		if err != nil {
+			return nil, tools.MetricGroupsOutput{}, err
+		}
 		return nil, output, nil
 	}
 }
 
 // ShowTimeseriesHandler handles the show_timeseries tool.
-func ShowTimeseriesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.ShowTimeseriesInput, struct{}] {
-	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.ShowTimeseriesInput) (*mcp.CallToolResult, struct{}, error) {
+func ShowTimeseriesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.ShowTimeseriesInput, tools.ShowTimeseriesOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.ShowTimeseriesInput) (*mcp.CallToolResult, tools.ShowTimeseriesOutput, error) {
 		promClient, err := getPromClient(ctx, opts)
 		if err != nil {
-			return nil, struct{}{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+			return nil, tools.ShowTimeseriesOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
 		}
 
 		result := tools.ShowTimeseriesHandler(ctx, promClient, input)
-		_, err = resultutil.Unwrap[struct{}](result)
+		// Without target_points the result is empty, to not overwhelm the LLM
+		// context: the purpose of the tool is to validate the query, and the
+		// visualization takes the required data from the tool inputs. With
+		// target_points, the data is bounded by the chart width and returned
+		// with the computed step. An UI-only tool could be introduced to load
+		// the data for the visualization, if needed (MCP-apps case).
+		output, err := resultutil.Unwrap[tools.ShowTimeseriesOutput](result)
 		if err != nil {
-			return nil, struct{}{}, err
+			return nil, tools.ShowTimeseriesOutput{}, err
 		}
-		// We return empty result to not overwhelm the LLM context. The purpose
-		// of the tool is to validate the query. The visualization is taking the
-		// required data from the tool inputs. An UI-only tool could be introduced
-		// to load the data for the visualization, if needed (MCP-apps case).
-		return nil, struct{}{}, nil
+		return nil, output, nil
 	}
 }
 
@@ -56,7 +92,7 @@ func ExecuteInstantQueryHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.Ins
 			return nil, tools.InstantQueryOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
 		}
 
-		result := tools.ExecuteInstantQueryHandler(ctx, promClient, input)
+		result := tools.ExecuteInstantQueryHandler(ctx, promClient, input, opts.Metrics.GetMaxResultSeries())
 		output, err := resultutil.Unwrap[tools.InstantQueryOutput](result)
 		if err != nil {
 			return nil, tools.InstantQueryOutput{}, err
@@ -67,13 +103,14 @@ func ExecuteInstantQueryHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.Ins
 
 // ExecuteRangeQueryHandler handles the execution of Prometheus range queries.
 func ExecuteRangeQueryHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.RangeQueryInput, tools.RangeQueryOutput] {
+	rangeOpts := opts.Metrics.GetRangeQueryOptions()
 	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.RangeQueryInput) (*mcp.CallToolResult, tools.RangeQueryOutput, error) {
 		promClient, err := getPromClient(ctx, opts)
 		if err != nil {
 			return nil, tools.RangeQueryOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
 		}
 
-		result := tools.ExecuteRangeQueryHandler(ctx, promClient, input, opts.Metrics.RangeQueryFullResponse)
+		result := tools.ExecuteRangeQueryHandler(ctx, promClient, input, rangeOpts)
 		output, err := resultutil.Unwrap[tools.RangeQueryOutput](result)
 		if err != nil {
 			return nil, tools.RangeQueryOutput{}, err
@@ -82,6 +119,91 @@ func ExecuteRangeQueryHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.Range
 	}
 }
 
+// QueryHeatmapHandler handles the query_heatmap tool.
+func QueryHeatmapHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.HeatmapInput, tools.HeatmapOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.HeatmapInput) (*mcp.CallToolResult, tools.HeatmapOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.HeatmapOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.QueryHeatmapHandler(ctx, promClient, input, opts.Metrics.GetOversizedStepPolicy())
+		output, err := resultutil.Unwrap[tools.HeatmapOutput](result)
+		if err != nil {
+			return nil, tools.HeatmapOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// LabelCardinalityTrendHandler handles the label_cardinality_trend tool.
+func LabelCardinalityTrendHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.LabelCardinalityTrendInput, tools.LabelCardinalityTrendOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.LabelCardinalityTrendInput) (*mcp.CallToolResult, tools.LabelCardinalityTrendOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.LabelCardinalityTrendOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.LabelCardinalityTrendHandler(ctx, promClient, input, opts.Metrics.GetOversizedStepPolicy())
+		output, err := resultutil.Unwrap[tools.LabelCardinalityTrendOutput](result)
+		if err != nil {
+			return nil, tools.LabelCardinalityTrendOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// EvaluateConditionHandler handles the evaluate_condition tool.
+func EvaluateConditionHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.EvaluateConditionInput, tools.EvaluateConditionOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.EvaluateConditionInput) (*mcp.CallToolResult, tools.EvaluateConditionOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.EvaluateConditionOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.EvaluateConditionHandler(ctx, promClient, input, opts.Metrics.GetOversizedStepPolicy())
+		output, err := resultutil.Unwrap[tools.EvaluateConditionOutput](result)
+		if err != nil {
+			return nil, tools.EvaluateConditionOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// ClusterStatusHandler handles the cluster_status tool.
+func ClusterStatusHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.ClusterStatusInput, tools.ClusterStatusOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.ClusterStatusInput) (*mcp.CallToolResult, tools.ClusterStatusOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.ClusterStatusOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.ClusterStatusHandler(ctx, promClient, input, opts.Metrics.GetStatusChecks())
+		output, err := resultutil.Unwrap[tools.ClusterStatusOutput](result)
+		if err != nil {
+			return nil, tools.ClusterStatusOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// SLOComplianceHandler handles the slo_compliance tool.
+func SLOComplianceHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SLOComplianceInput, tools.SLOComplianceOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.SLOComplianceInput) (*mcp.CallToolResult, tools.SLOComplianceOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.SLOComplianceOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.SLOComplianceHandler(ctx, promClient, input, opts.Metrics.GetOversizedStepPolicy())
+		output, err := resultutil.Unwrap[tools.SLOComplianceOutput](result)
+		if err != nil {
+			return nil, tools.SLOComplianceOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
 // GetLabelNamesHandler handles the retrieval of label names.
 func GetLabelNamesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.LabelNamesInput, tools.LabelNamesOutput] {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.LabelNamesInput) (*mcp.CallToolResult, tools.LabelNamesOutput, error) {
@@ -107,7 +229,7 @@ func GetLabelValuesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.LabelVal
 			return nil, tools.LabelValuesOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
 		}
 
-		result := tools.GetLabelValuesHandler(ctx, promClient, input)
+		result := tools.GetLabelValuesHandler(ctx, promClient, input, opts.Metrics.GetLimit(tools.LimitLabelValues))
 		output, err := resultutil.Unwrap[tools.LabelValuesOutput](result)
 		if err != nil {
 			return nil, tools.LabelValuesOutput{}, err
@@ -116,6 +238,23 @@ func GetLabelValuesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.LabelVal
 	}
 }
 
+// GetLabelsOverviewHandler handles the get_labels_overview tool.
+func GetLabelsOverviewHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.LabelsOverviewInput, tools.LabelsOverviewOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.LabelsOverviewInput) (*mcp.CallToolResult, tools.LabelsOverviewOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.LabelsOverviewOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetLabelsOverviewHandler(ctx, promClient, input, opts.Metrics.GetLimit(tools.LimitLabelsOverview))
+		output, err := resultutil.Unwrap[tools.LabelsOverviewOutput](result)
+		if err != nil {
+			return nil, tools.LabelsOverviewOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
 // GetSeriesHandler handles the retrieval of time series.
 func GetSeriesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SeriesInput, tools.SeriesOutput] {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.SeriesInput) (*mcp.CallToolResult, tools.SeriesOutput, error) {
@@ -124,7 +263,7 @@ func GetSeriesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SeriesInput,
 			return nil, tools.SeriesOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
 		}
 
-		result := tools.GetSeriesHandler(ctx, promClient, input)
+		result := tools.GetSeriesHandler(ctx, promClient, input, opts.Metrics.GetLimit(tools.LimitSeries))
 		output, err := resultutil.Unwrap[tools.SeriesOutput](result)
 		if err != nil {
 			return nil, tools.SeriesOutput{}, err
@@ -133,15 +272,225 @@ func GetSeriesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SeriesInput,
 	}
 }
 
+// GetExternalLabelsHandler handles the get_external_labels tool.
+func GetExternalLabelsHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.ExternalLabelsInput, tools.ExternalLabelsOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.ExternalLabelsInput) (*mcp.CallToolResult, tools.ExternalLabelsOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.ExternalLabelsOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetExternalLabelsHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.ExternalLabelsOutput](result)
+		if err != nil {
+			return nil, tools.ExternalLabelsOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// GetActiveQueriesHandler handles the get_active_queries tool.
+func GetActiveQueriesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.ActiveQueriesInput, tools.ActiveQueriesOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.ActiveQueriesInput) (*mcp.CallToolResult, tools.ActiveQueriesOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.ActiveQueriesOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetActiveQueriesHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.ActiveQueriesOutput](result)
+		if err != nil {
+			return nil, tools.ActiveQueriesOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// InspectMetricHandler handles the inspect_metric tool.
+func InspectMetricHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.InspectMetricInput, tools.InspectMetricOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.InspectMetricInput) (*mcp.CallToolResult, tools.InspectMetricOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.InspectMetricOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.InspectMetricHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.InspectMetricOutput](result)
+		if err != nil {
+			return nil, tools.InspectMetricOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// GetMetricMetadataHandler handles the get_metric_metadata tool.
+func GetMetricMetadataHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.MetricMetadataInput, tools.MetricMetadataOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.MetricMetadataInput) (*mcp.CallToolResult, tools.MetricMetadataOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.MetricMetadataOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetMetricMetadataHandler(ctx, promClient, input, opts.Metrics.GetLimit(tools.LimitMetricMetadata))
+		output, err := resultutil.Unwrap[tools.MetricMetadataOutput](result)
+		if err != nil {
+			return nil, tools.MetricMetadataOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// GetScrapeIntervalHandler handles the get_scrape_interval tool.
+func GetScrapeIntervalHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.ScrapeIntervalInput, tools.ScrapeIntervalOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.ScrapeIntervalInput) (*mcp.CallToolResult, tools.ScrapeIntervalOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.ScrapeIntervalOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetScrapeIntervalHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.ScrapeIntervalOutput](result)
+		if err != nil {
+			return nil, tools.ScrapeIntervalOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// CheckSeriesUniquenessHandler handles the check_series_uniqueness tool.
+func CheckSeriesUniquenessHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SeriesUniquenessInput, tools.SeriesUniquenessOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.SeriesUniquenessInput) (*mcp.CallToolResult, tools.SeriesUniquenessOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.SeriesUniquenessOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.CheckSeriesUniquenessHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.SeriesUniquenessOutput](result)
+		if err != nil {
+			return nil, tools.SeriesUniquenessOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// GetRuleGroupHandler handles the get_rule_group tool.
+func GetRuleGroupHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.RuleGroupInput, tools.RuleGroupOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.RuleGroupInput) (*mcp.CallToolResult, tools.RuleGroupOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.RuleGroupOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetRuleGroupHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.RuleGroupOutput](result)
+		if err != nil {
+			return nil, tools.RuleGroupOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// ListRecordingRulesHandler handles the listing of recording rules.
+func ListRecordingRulesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.RecordingRulesInput, tools.RecordingRulesOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.RecordingRulesInput) (*mcp.CallToolResult, tools.RecordingRulesOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.RecordingRulesOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.ListRecordingRulesHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.RecordingRulesOutput](result)
+		if err != nil {
+			return nil, tools.RecordingRulesOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// ListQueryTemplatesHandler handles the listing of query templates.
+func ListQueryTemplatesHandler(_ ObsMCPOptions) mcp.ToolHandlerFor[tools.QueryTemplatesInput, tools.QueryTemplatesOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.QueryTemplatesInput) (*mcp.CallToolResult, tools.QueryTemplatesOutput, error) {
+		result := tools.ListQueryTemplatesHandler(ctx, input)
+		output, err := resultutil.Unwrap[tools.QueryTemplatesOutput](result)
+		if err != nil {
+			return nil, tools.QueryTemplatesOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// RenderQueryTemplateHandler handles rendering of query templates.
+func RenderQueryTemplateHandler(_ ObsMCPOptions) mcp.ToolHandlerFor[tools.RenderQueryTemplateInput, tools.RenderQueryTemplateOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.RenderQueryTemplateInput) (*mcp.CallToolResult, tools.RenderQueryTemplateOutput, error) {
+		result := tools.RenderQueryTemplateHandler(ctx, input)
+		output, err := resultutil.Unwrap[tools.RenderQueryTemplateOutput](result)
+		if err != nil {
+			return nil, tools.RenderQueryTemplateOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// QueryToPanelHandler handles turning queries into Perses panels.
+func QueryToPanelHandler(_ ObsMCPOptions) mcp.ToolHandlerFor[tools.QueryToPanelInput, tools.QueryToPanelOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.QueryToPanelInput) (*mcp.CallToolResult, tools.QueryToPanelOutput, error) {
+		result := tools.QueryToPanelHandler(ctx, input)
+		output, err := resultutil.Unwrap[tools.QueryToPanelOutput](result)
+		if err != nil {
+			return nil, tools.QueryToPanelOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// ParseTimeHandler handles resolving time expressions.
+func ParseTimeHandler(_ ObsMCPOptions) mcp.ToolHandlerFor[tools.ParseTimeInput, tools.ParseTimeOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.ParseTimeInput) (*mcp.CallToolResult, tools.ParseTimeOutput, error) {
+		result := tools.ParseTimeHandler(ctx, input)
+		output, err := resultutil.Unwrap[tools.ParseTimeOutput](result)
+		if err != nil {
+			return nil, tools.ParseTimeOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// SaveQueryResultHandler handles the save_query_result tool.
+func SaveQueryResultHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SaveQueryResultInput, tools.SaveQueryResultOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.SaveQueryResultInput) (*mcp.CallToolResult, tools.SaveQueryResultOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.SaveQueryResultOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.SaveQueryResultHandler(ctx, promClient, input, opts.Metrics.GetFileOutputDir())
+		output, err := resultutil.Unwrap[tools.SaveQueryResultOutput](result)
+		if err != nil {
+			return nil, tools.SaveQueryResultOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// alertmanagerClientError returns the error of a tool that could not create its
+// Alertmanager client.
+func alertmanagerClientError(err error) error {
+	if errors.Is(err, errAlertmanagerNotConfigured) {
+		return err
+	}
+	return fmt.Errorf("failed to create Alertmanager client: %w", err)
+}
+
 // GetAlertsHandler handles the retrieval of alerts from Alertmanager.
 func GetAlertsHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.AlertsInput, tools.AlertsOutput] {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.AlertsInput) (*mcp.CallToolResult, tools.AlertsOutput, error) {
 		amClient, err := getAlertmanagerClient(ctx, opts)
 		if err != nil {
-			return nil, tools.AlertsOutput{}, fmt.Errorf("failed to create Alertmanager client: %w", err)
+			return nil, tools.AlertsOutput{}, alertmanagerClientError(err)
 		}
 
-		result := tools.GetAlertsHandler(ctx, amClient, input)
+		result := tools.GetAlertsHandler(ctx, amClient, input, opts.Metrics.GetAlertProjection(), alertsProgress(ctx, req))
 		output, err := resultutil.Unwrap[tools.AlertsOutput](result)
 		if err != nil {
 			return nil, tools.AlertsOutput{}, err
@@ -150,12 +499,125 @@ func GetAlertsHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.AlertsInput,
 	}
 }
 
+// alertsProgress returns an AlertsProgressFunc sending each batch of alerts as a progress
+// notification, with the alerts as a JSON array in the message, or nil when the client did
+// not ask for progress notifications.
+func alertsProgress(ctx context.Context, req *mcp.CallToolRequest) tools.AlertsProgressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+	return func(batch []tools.Alert, sent int) {
+		data, err := json.Marshal(batch)
+		if err != nil {
+			slog.Warn("Failed to encode alerts progress", "error", err)
+			return
+		}
+		err = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(sent + len(batch)),
+			Message:       string(data),
+		})
+		if err != nil {
+			slog.Debug("Failed to send alerts progress", "error", err)
+		}
+	}
+}
+
+// SummarizeAlertsHandler handles the summarize_alerts tool.
+func SummarizeAlertsHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SummarizeAlertsInput, tools.AlertsSummaryOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.SummarizeAlertsInput) (*mcp.CallToolResult, tools.AlertsSummaryOutput, error) {
+		amClient, err := getAlertmanagerClient(ctx, opts)
+		if err != nil {
+			return nil, tools.AlertsSummaryOutput{}, alertmanagerClientError(err)
+		}
+
+		result := tools.SummarizeAlertsHandler(ctx, amClient, input)
+		output, err := resultutil.Unwrap[tools.AlertsSummaryOutput](result)
+		if err != nil {
+			return nil, tools.AlertsSummaryOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// GetAlertHistoryHandler handles the get_alert_history tool.
+func GetAlertHistoryHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.AlertHistoryInput, tools.AlertHistoryOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.AlertHistoryInput) (*mcp.CallToolResult, tools.AlertHistoryOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.AlertHistoryOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetAlertHistoryHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.AlertHistoryOutput](result)
+		if err != nil {
+			return nil, tools.AlertHistoryOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// GetAlertThresholdHandler handles the get_alert_threshold tool.
+func GetAlertThresholdHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.AlertThresholdInput, tools.AlertThresholdOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.AlertThresholdInput) (*mcp.CallToolResult, tools.AlertThresholdOutput, error) {
+		promClient, err := getPromClient(ctx, opts)
+		if err != nil {
+			return nil, tools.AlertThresholdOutput{}, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+
+		result := tools.GetAlertThresholdHandler(ctx, promClient, input)
+		output, err := resultutil.Unwrap[tools.AlertThresholdOutput](result)
+		if err != nil {
+			return nil, tools.AlertThresholdOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// DeleteSilenceHandler handles the delete_silence tool.
+func DeleteSilenceHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.DeleteSilenceInput, tools.DeleteSilenceOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.DeleteSilenceInput) (*mcp.CallToolResult, tools.DeleteSilenceOutput, error) {
+		amClient, err := getAlertmanagerClient(ctx, opts)
+		if err != nil {
+			return nil, tools.DeleteSilenceOutput{}, alertmanagerClientError(err)
+		}
+
+		result := tools.DeleteSilenceHandler(ctx, amClient, input, opts.Metrics.AllowWrites)
+		output, err := resultutil.Unwrap[tools.DeleteSilenceOutput](result)
+		if err != nil {
+			return nil, tools.DeleteSilenceOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
+// GetAlertmanagerStatusHandler handles the get_alertmanager_status tool.
+func GetAlertmanagerStatusHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.AlertmanagerStatusInput, tools.AlertmanagerStatusOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.AlertmanagerStatusInput) (*mcp.CallToolResult, tools.AlertmanagerStatusOutput, error) {
+		amClient, err := getAlertmanagerClient(ctx, opts)
+		if err != nil {
+			return nil, tools.AlertmanagerStatusOutput{}, alertmanagerClientError(err)
+		}
+
+		result := tools.GetAlertmanagerStatusHandler(ctx, amClient, input)
+		output, err := resultutil.Unwrap[tools.AlertmanagerStatusOutput](result)
+		if err != nil {
+			return nil, tools.AlertmanagerStatusOutput{}, err
+		}
+		return nil, output, nil
+	}
+}
+
 // GetSilencesHandler handles the retrieval of silences from Alertmanager.
 func GetSilencesHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[tools.SilencesInput, tools.SilencesOutput] {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input tools.SilencesInput) (*mcp.CallToolResult, tools.SilencesOutput, error) {
 		amClient, err := getAlertmanagerClient(ctx, opts)
 		if err != nil {
-			return nil, tools.SilencesOutput{}, fmt.Errorf("failed to create Alertmanager client: %w", err)
+			return nil, tools.SilencesOutput{}, alertmanagerClientError(err)
 		}
 
 		result := tools.GetSilencesHandler(ctx, amClient, input)
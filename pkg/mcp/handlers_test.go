@@ -3,17 +3,36 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/go-openapi/strfmt"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/prometheus/alertmanager/api/v2/models"
+	promapi "github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/client-go/rest"
 
+	"github.com/rhobs/obs-mcp/pkg/auth"
 	tools "github.com/rhobs/obs-mcp/pkg/metrics"
 	"github.com/rhobs/obs-mcp/pkg/metrics/alertmanager"
 	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
+	"github.com/rhobs/obs-mcp/pkg/resultutil"
 )
 
 // MockedLoader is a mock implementation of prometheus.PromClient for testing
@@ -22,8 +41,14 @@ type MockedLoader struct {
 	ExecuteRangeQueryFunc   func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error)
 	ExecuteInstantQueryFunc func(ctx context.Context, query string, time time.Time) (map[string]any, error)
 	GetLabelNamesFunc       func(ctx context.Context, metricName string, start, end time.Time) ([]string, error)
-	GetLabelValuesFunc      func(ctx context.Context, label string, metricName string, start, end time.Time) ([]string, error)
+	GetLabelValuesFunc      func(ctx context.Context, label string, metricName string, start, end time.Time, limit uint64) ([]string, error)
 	GetSeriesFunc           func(ctx context.Context, matches []string, start, end time.Time) ([]map[string]string, error)
+	GetRulesFunc            func(ctx context.Context) (v1.RulesResult, error)
+	GetExternalLabelsFunc   func(ctx context.Context) (*prometheus.ExternalLabels, error)
+	GetMetricMetadataFunc   func(ctx context.Context, metric string) ([]v1.Metadata, error)
+	ListMetricMetadataFunc  func(ctx context.Context, limit int) (map[string][]v1.Metadata, error)
+	GetActiveQueriesFunc    func(ctx context.Context) (*prometheus.ActiveQueries, error)
+	GetLabelValueCountsFunc func(ctx context.Context) (map[string]uint64, error)
 }
 
 func (m *MockedLoader) ListMetrics(ctx context.Context, nameRegex string) ([]string, error) {
@@ -60,9 +85,9 @@ func (m *MockedLoader) GetLabelNames(ctx context.Context, metricName string, sta
 	return []string{}, nil
 }
 
-func (m *MockedLoader) GetLabelValues(ctx context.Context, label, metricName string, start, end time.Time) ([]string, error) {
+func (m *MockedLoader) GetLabelValues(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
 	if m.GetLabelValuesFunc != nil {
-		return m.GetLabelValuesFunc(ctx, label, metricName, start, end)
+		return m.GetLabelValuesFunc(ctx, label, metricName, start, end, limit)
 	}
 	return []string{}, nil
 }
@@ -74,13 +99,57 @@ func (m *MockedLoader) GetSeries(ctx context.Context, matches []string, start, e
 	return []map[string]string{}, nil
 }
 
+func (m *MockedLoader) GetExternalLabels(ctx context.Context) (*prometheus.ExternalLabels, error) {
+	if m.GetExternalLabelsFunc != nil {
+		return m.GetExternalLabelsFunc(ctx)
+	}
+	return &prometheus.ExternalLabels{}, nil
+}
+
+func (m *MockedLoader) GetMetricMetadata(ctx context.Context, metric string) ([]v1.Metadata, error) {
+	if m.GetMetricMetadataFunc != nil {
+		return m.GetMetricMetadataFunc(ctx, metric)
+	}
+	return nil, nil
+}
+
+func (m *MockedLoader) ListMetricMetadata(ctx context.Context, limit int) (map[string][]v1.Metadata, error) {
+	if m.ListMetricMetadataFunc != nil {
+		return m.ListMetricMetadataFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockedLoader) GetLabelValueCounts(ctx context.Context) (map[string]uint64, error) {
+	if m.GetLabelValueCountsFunc != nil {
+		return m.GetLabelValueCountsFunc(ctx)
+	}
+	return nil, fmt.Errorf("TSDB stats not available")
+}
+
+func (m *MockedLoader) GetActiveQueries(ctx context.Context) (*prometheus.ActiveQueries, error) {
+	if m.GetActiveQueriesFunc != nil {
+		return m.GetActiveQueriesFunc(ctx)
+	}
+	return &prometheus.ActiveQueries{}, nil
+}
+
+func (m *MockedLoader) GetRules(ctx context.Context) (v1.RulesResult, error) {
+	if m.GetRulesFunc != nil {
+		return m.GetRulesFunc(ctx)
+	}
+	return v1.RulesResult{}, nil
+}
+
 // Ensure MockPromClient implements prometheus.PromClient at compile time
 var _ prometheus.Loader = (*MockedLoader)(nil)
 
 // MockedAlertmanagerLoader is a mock implementation of alertmanager.Loader for testing
 type MockedAlertmanagerLoader struct {
-	GetAlertsFunc   func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error)
-	GetSilencesFunc func(ctx context.Context, filter []string) (models.GettableSilences, error)
+	GetAlertsFunc     func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error)
+	GetSilencesFunc   func(ctx context.Context, filter []string) (models.GettableSilences, error)
+	GetStatusFunc     func(ctx context.Context) (*models.AlertmanagerStatus, error)
+	DeleteSilenceFunc func(ctx context.Context, id string) error
 }
 
 func (m *MockedAlertmanagerLoader) GetAlerts(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
@@ -97,6 +166,20 @@ func (m *MockedAlertmanagerLoader) GetSilences(ctx context.Context, filter []str
 	return models.GettableSilences{}, nil
 }
 
+func (m *MockedAlertmanagerLoader) GetStatus(ctx context.Context) (*models.AlertmanagerStatus, error) {
+	if m.GetStatusFunc != nil {
+		return m.GetStatusFunc(ctx)
+	}
+	return &models.AlertmanagerStatus{}, nil
+}
+
+func (m *MockedAlertmanagerLoader) DeleteSilence(ctx context.Context, id string) error {
+	if m.DeleteSilenceFunc != nil {
+		return m.DeleteSilenceFunc(ctx, id)
+	}
+	return nil
+}
+
 // Ensure MockedAlertmanagerLoader implements alertmanager.Loader at compile time
 var _ alertmanager.Loader = (*MockedAlertmanagerLoader)(nil)
 
@@ -123,8 +206,8 @@ func withMockAlertmanagerClient(ctx context.Context, client alertmanager.Loader)
 }
 
 func TestExecuteRangeQueryHandler_ExplicitTimeRange_RFC3339(t *testing.T) {
-	expectedStart, _ := prometheus.ParseTimestamp("2024-01-01T00:00:00Z")
-	expectedEnd, _ := prometheus.ParseTimestamp("2024-01-01T01:00:00Z")
+	expectedStart, _ := prometheus.ParseTimestamp(context.Background(), "2024-01-01T00:00:00Z")
+	expectedEnd, _ := prometheus.ParseTimestamp(context.Background(), "2024-01-01T01:00:00Z")
 
 	mockClient := &MockedLoader{
 		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
@@ -205,6 +288,365 @@ func TestExecuteRangeQueryHandler_StepParsing_ValidSteps(t *testing.T) {
 	}
 }
 
+func TestExecuteRangeQueryHandler_StepParsing_NumericSeconds(t *testing.T) {
+	tests := []struct {
+		name     string
+		step     any
+		wantStep time.Duration
+		wantErr  bool
+	}{
+		{name: "JSON number is seconds", step: float64(60), wantStep: 60 * time.Second},
+		{name: "integer string is seconds", step: "60", wantStep: 60 * time.Second},
+		{name: "fractional seconds", step: "0.5", wantStep: 500 * time.Millisecond},
+		{name: "duration string still works", step: "1m", wantStep: time.Minute},
+		{name: "zero seconds is rejected", step: float64(0), wantErr: true},
+		{name: "JSON number in exponent form is seconds", step: json.Number("6e1"), wantStep: 60 * time.Second},
+		{name: "JSON number with uppercase exponent is seconds", step: json.Number("1.5E2"), wantStep: 150 * time.Second},
+		{name: "negative seconds are rejected", step: json.Number("-60"), wantErr: true},
+		{name: "infinite seconds are rejected", step: "Inf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStep time.Duration
+			mockClient := &MockedLoader{
+				ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+					gotStep = step
+					return map[string]any{"resultType": "matrix", "result": []any{}}, nil
+				},
+			}
+
+			ctx := withMockClient(t.Context(), mockClient)
+			handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+			paramsMap := map[string]any{
+				"query": "up{job=\"api\"}",
+				"step":  tt.step,
+			}
+			req := newMockRequest(paramsMap)
+
+			// Decode the arguments the same way the MCP SDK does for typed tool handlers.
+			var input tools.RangeQueryInput
+			if err := json.Unmarshal(req.Params.Arguments, &input); err != nil {
+				t.Fatalf("failed to decode arguments: %v", err)
+			}
+			if input != tools.BuildRangeQueryInput(paramsMap) {
+				t.Errorf("decoded input %+v does not match BuildRangeQueryInput %+v", input, tools.BuildRangeQueryInput(paramsMap))
+			}
+
+			_, _, err := handler(ctx, &req, input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotStep != tt.wantStep {
+				t.Errorf("expected step %v, got %v", tt.wantStep, gotStep)
+			}
+		})
+	}
+}
+
+func TestQueryHandlers_ClientDisconnect(t *testing.T) {
+	// waitForCancel blocks like a long backend query until the context is canceled.
+	waitForCancel := func(ctx context.Context, started chan<- struct{}) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("query was not canceled")
+		}
+	}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+		mock func(client *MockedLoader, started chan<- struct{})
+	}{
+		{
+			name: "range query",
+			mock: func(client *MockedLoader, started chan<- struct{}) {
+				client.ExecuteRangeQueryFunc = func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+					return nil, waitForCancel(ctx, started)
+				}
+			},
+			call: func(ctx context.Context) error {
+				params := map[string]any{"query": "up", "step": "1m", "duration": "1h"}
+				req := newMockRequest(params)
+				_, _, err := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})(ctx, &req, tools.BuildRangeQueryInput(params))
+				return err
+			},
+		},
+		{
+			name: "instant query",
+			mock: func(client *MockedLoader, started chan<- struct{}) {
+				client.ExecuteInstantQueryFunc = func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+					return nil, waitForCancel(ctx, started)
+				}
+			},
+			call: func(ctx context.Context) error {
+				params := map[string]any{"query": "up"}
+				req := newMockRequest(params)
+				_, _, err := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})(ctx, &req, tools.BuildInstantQueryInput(params))
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockedLoader{}
+			started := make(chan struct{})
+			tt.mock(client, started)
+
+			ctx, cancel := context.WithCancel(withMockClient(t.Context(), client))
+			errs := make(chan error, 1)
+			go func() { errs <- tt.call(ctx) }()
+
+			<-started
+			cancel()
+			err := <-errs
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("expected the query to observe the cancellation, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteRangeQueryHandler_ShowGaps(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{
+						Metric: model.Metric{"__name__": "up"},
+						Values: []model.SamplePair{
+							{Timestamp: 60_000, Value: 1},
+							{Timestamp: 120_000, Value: 1},
+							{Timestamp: 300_000, Value: 0},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{RangeQueryFullResponse: true}})
+
+	tests := []struct {
+		name       string
+		showGaps   bool
+		wantValues [][]any
+	}{
+		{
+			name:     "gaps are omitted by default",
+			showGaps: false,
+			wantValues: [][]any{
+				{60.0, "1"}, {120.0, "1"}, {300.0, "0"},
+			},
+		},
+		{
+			name:     "missing steps are marked with null",
+			showGaps: true,
+			wantValues: [][]any{
+				{60.0, "1"}, {120.0, "1"}, {180.0, nil}, {240.0, nil}, {300.0, "0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]any{"query": "up", "step": "1m", "show_gaps": tt.showGaps}
+			req := newMockRequest(params)
+			_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(output.Result) != 1 {
+				t.Fatalf("expected 1 series, got %d", len(output.Result))
+			}
+			if !reflect.DeepEqual(output.Result[0].Values, tt.wantValues) {
+				t.Errorf("values = %v, want %v", output.Result[0].Values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestExecuteRangeQueryHandler_ProjectLabels(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{
+						Metric: model.Metric{"job": "api", "instance": "1"},
+						Values: []model.SamplePair{{Timestamp: 60_000, Value: 1}, {Timestamp: 120_000, Value: 1}},
+					},
+					{
+						Metric: model.Metric{"job": "api", "instance": "2"},
+						Values: []model.SamplePair{{Timestamp: 60_000, Value: 2}, {Timestamp: 120_000, Value: 3}},
+					},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	params := map[string]any{"query": "up", "step": "1m", "project_labels": "job"}
+
+	t.Run("full response", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{RangeQueryFullResponse: true}})
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Result) != 1 {
+			t.Fatalf("expected 1 series, got %d", len(output.Result))
+		}
+		series := output.Result[0]
+		wantValues := [][]any{{60.0, "3"}, {120.0, "4"}}
+		if !reflect.DeepEqual(series.Metric, map[string]string{"job": "api"}) || !reflect.DeepEqual(series.Values, wantValues) || series.Merged != 2 {
+			t.Errorf("series = %+v, want {job=api} with values %v merged from 2 series", series, wantValues)
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Summary) != 1 {
+			t.Fatalf("expected 1 series, got %d", len(output.Summary))
+		}
+		if summary := output.Summary[0]; summary.Merged != 2 || summary.Max != 4 {
+			t.Errorf("summary = %+v, want max 4 merged from 2 series", summary)
+		}
+	})
+}
+
+func TestExecuteRangeQueryHandler_StaleSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	samplesUntil := func(last time.Time) []model.SamplePair {
+		var samples []model.SamplePair
+		for ts := start; !ts.After(last); ts = ts.Add(time.Minute) {
+			samples = append(samples, model.SamplePair{Timestamp: model.TimeFromUnixNano(ts.UnixNano()), Value: 1})
+		}
+		return samples
+	}
+
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{Metric: model.Metric{"pod": "running"}, Values: samplesUntil(end)},
+					{Metric: model.Metric{"pod": "late-scrape"}, Values: samplesUntil(end.Add(-2 * time.Minute))},
+					{Metric: model.Metric{"pod": "deleted"}, Values: samplesUntil(start.Add(20 * time.Minute))},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	params := map[string]any{
+		"query": `up{job="api"}`,
+		"step":  "1m",
+		"start": start.Format(time.RFC3339),
+		"end":   end.Format(time.RFC3339),
+	}
+	req := newMockRequest(params)
+	want := []bool{false, false, true}
+
+	t.Run("full response", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{RangeQueryFullResponse: true}})
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, series := range output.Result {
+			if series.Stale != want[i] {
+				t.Errorf("series %v: stale = %v, want %v", series.Metric, series.Stale, want[i])
+			}
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, summary := range output.Summary {
+			if summary.Stale != want[i] {
+				t.Errorf("series %v: stale = %v, want %v", summary.Series, summary.Stale, want[i])
+			}
+		}
+	})
+}
+
+func TestExecuteRangeQueryHandler_StepLargerThanRange(t *testing.T) {
+	var gotStep time.Duration
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			gotStep = step
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{
+				{Metric: model.Metric{"__name__": "up"}, Values: []model.SamplePair{{Timestamp: model.TimeFromUnix(end.Unix()), Value: 1}}},
+			}}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	params := map[string]any{"query": "up", "duration": "5m", "step": "1h"}
+
+	t.Run("rejected by default", func(t *testing.T) {
+		gotStep = 0
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err == nil || !strings.Contains(err.Error(), "larger than the query range") {
+			t.Fatalf("expected step validation error, got %v", err)
+		}
+		if gotStep != 0 {
+			t.Error("query should not have been executed")
+		}
+	})
+
+	t.Run("shrunk with the shrink policy", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{OversizedStepPolicy: "shrink"}})
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotStep != 5*time.Second {
+			t.Errorf("step = %v, want 5s", gotStep)
+		}
+		if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "reduced to 5s") {
+			t.Errorf("expected a warning about the reduced step, got %v", output.Warnings)
+		}
+	})
+
+	t.Run("step equal to the range is kept", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "up", "duration": "1h", "step": "1h"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotStep != time.Hour {
+			t.Errorf("step = %v, want 1h", gotStep)
+		}
+	})
+}
+
 func TestExecuteRangeQueryHandler_RequiredParameters(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -219,7 +661,7 @@ func TestExecuteRangeQueryHandler_RequiredParameters(t *testing.T) {
 		{
 			name:          "missing step parameter",
 			params:        map[string]any{"query": "up{job=\"api\"}"},
-			expectedError: "step parameter is required and must be a string",
+			expectedError: "step parameter is required and must be a string, unless target_points is set",
 		},
 		{
 			name: "invalid step format",
@@ -367,6 +809,41 @@ func TestExecuteRangeQueryHandler_DurationMode_CustomDuration(t *testing.T) {
 	}
 }
 
+func TestExecuteRangeQueryHandler_PromQLDurationUnits(t *testing.T) {
+	// The same inputs are tested against the toolset handlers in toolset_tools.
+	tests := []struct {
+		params    map[string]any
+		wantStep  time.Duration
+		wantRange time.Duration
+	}{
+		{map[string]any{"step": "1d", "duration": "1w"}, 24 * time.Hour, 7 * 24 * time.Hour},
+		{map[string]any{"step": "1h30m", "duration": "2d"}, 90 * time.Minute, 48 * time.Hour},
+		{map[string]any{"step": "1d", "start": "NOW-2w", "end": "NOW"}, 24 * time.Hour, 14 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.params), func(t *testing.T) {
+			var gotStep, gotRange time.Duration
+			mockClient := &MockedLoader{
+				ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+					gotStep, gotRange = step, end.Sub(start)
+					return map[string]any{"resultType": "matrix", "result": model.Matrix{}}, nil
+				},
+			}
+			ctx := withMockClient(prometheus.ContextWithNow(t.Context(), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), mockClient)
+			handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+			params := map[string]any{"query": "up"}
+			maps.Copy(params, tt.params)
+			req := newMockRequest(params)
+			if _, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotStep != tt.wantStep || gotRange != tt.wantRange {
+				t.Errorf("step = %v and range = %v, want %v and %v", gotStep, gotRange, tt.wantStep, tt.wantRange)
+			}
+		})
+	}
+}
+
 func TestExecuteRangeQueryHandler_DurationMode_NOWKeyword(t *testing.T) {
 	mockClient := &MockedLoader{
 		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
@@ -396,7 +873,7 @@ func TestExecuteRangeQueryHandler_DurationMode_NOWKeyword(t *testing.T) {
 
 func TestExecuteRangeQueryHandler_NOWKeyword_CaseInsensitive(t *testing.T) {
 	nowVariations := []string{"NOW", "now", "Now", "nOw", "NoW"}
-	expectedStart, _ := prometheus.ParseTimestamp("2024-01-01T00:00:00Z")
+	expectedStart, _ := prometheus.ParseTimestamp(context.Background(), "2024-01-01T00:00:00Z")
 
 	for _, nowStr := range nowVariations {
 		t.Run(nowStr, func(t *testing.T) {
@@ -600,76 +1077,1803 @@ func TestShowTimeseriesHandler(t *testing.T) {
 	}
 }
 
-func TestExecuteInstantQueryHandler_RelativeTime(t *testing.T) {
-	tests := []struct {
-		name       string
-		time       string
-		validateFn func(t *testing.T, queryTime time.Time)
-	}{
-		{
-			name: "NOW-5m",
-			time: "NOW-5m",
-			validateFn: func(t *testing.T, queryTime time.Time) {
-				expected := time.Now().Add(-5 * time.Minute)
-				diff := queryTime.Sub(expected).Abs()
-				if diff > 2*time.Second {
-					t.Errorf("expected query time to be ~5m ago, got %v (diff: %v)", queryTime, diff)
-				}
-			},
+func TestShowTimeseriesHandler_TargetPoints(t *testing.T) {
+	var gotStep time.Duration
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			gotStep = step
+			matrix := model.Matrix{&model.SampleStream{Metric: model.Metric{"job": "api"}}}
+			for ts := start; !ts.After(end); ts = ts.Add(step) {
+				matrix[0].Values = append(matrix[0].Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(ts.UnixNano()), Value: 1})
+			}
+			return map[string]any{"resultType": "matrix", "result": matrix}, nil
 		},
+	}
+
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := ShowTimeseriesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("step computed from the points", func(t *testing.T) {
+		params := map[string]any{
+			"query":         "up{job=\"api\"}",
+			"target_points": 800,
+			"start":         "2024-01-01T00:00:00Z",
+			"end":           "2024-01-02T00:00:00Z",
+		}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildShowTimeseriesInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 24h over 799 steps is 108.14s, rounded up to the second.
+		if gotStep != 109*time.Second || output.Step != "1m49s" {
+			t.Errorf("expected a step of 1m49s, got %s (output %q)", gotStep, output.Step)
+		}
+		if len(output.Result) != 1 || len(output.Result[0].Values) > 800 {
+			t.Errorf("expected one series of at most 800 points, got %+v", output.Result)
+		}
+	})
+
+	t.Run("no data without target_points", func(t *testing.T) {
+		params := map[string]any{
+			"query": "up{job=\"api\"}",
+			"step":  "1m",
+			"start": "2024-01-01T00:00:00Z",
+			"end":   "2024-01-01T01:00:00Z",
+		}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildShowTimeseriesInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output.Step != "" || output.Result != nil {
+			t.Errorf("expected an empty output, got %+v", output)
+		}
+	})
+
+	for _, tt := range []struct {
+		name    string
+		params  map[string]any
+		wantErr string
+	}{
 		{
-			name: "NOW-1h",
-			time: "NOW-1h",
-			validateFn: func(t *testing.T, queryTime time.Time) {
-				expected := time.Now().Add(-1 * time.Hour)
-				diff := queryTime.Sub(expected).Abs()
-				if diff > 2*time.Second {
-					t.Errorf("expected query time to be ~1h ago, got %v (diff: %v)", queryTime, diff)
-				}
-			},
+			name:    "with step",
+			params:  map[string]any{"query": "up{job=\"api\"}", "step": "1m", "target_points": 100},
+			wantErr: "cannot be combined",
 		},
 		{
-			name: "now-30s (lowercase)",
-			time: "now-30s",
-			validateFn: func(t *testing.T, queryTime time.Time) {
-				expected := time.Now().Add(-30 * time.Second)
-				diff := queryTime.Sub(expected).Abs()
-				if diff > 2*time.Second {
-					t.Errorf("expected query time to be ~30s ago, got %v (diff: %v)", queryTime, diff)
-				}
-			},
+			name:    "too few points",
+			params:  map[string]any{"query": "up{job=\"api\"}", "target_points": 1},
+			wantErr: "target_points must be between 2 and 11000",
 		},
 		{
-			name: "NOW+5m (future time)",
-			time: "NOW+5m",
-			validateFn: func(t *testing.T, queryTime time.Time) {
-				expected := time.Now().Add(5 * time.Minute)
-				diff := queryTime.Sub(expected).Abs()
-				if diff > 2*time.Second {
-					t.Errorf("expected query time to be ~5m from now, got %v (diff: %v)", queryTime, diff)
-				}
-			},
+			name:    "neither step nor points",
+			params:  map[string]any{"query": "up{job=\"api\"}"},
+			wantErr: "unless target_points is set",
 		},
-	}
-
-	for _, tt := range tests {
+	} {
 		t.Run(tt.name, func(t *testing.T) {
-			mockClient := &MockedLoader{
-				ExecuteInstantQueryFunc: func(ctx context.Context, query string, queryTime time.Time) (map[string]any, error) {
-					if tt.validateFn != nil {
-						tt.validateFn(t, queryTime)
-					}
-					return map[string]any{"resultType": "vector", "result": []any{}}, nil
-				},
+			req := newMockRequest(tt.params)
+			_, _, err := handler(ctx, &req, tools.BuildShowTimeseriesInput(tt.params))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
 			}
+		})
+	}
+}
 
-			ctx := withMockClient(context.Background(), mockClient)
-			handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-			paramsMap := map[string]any{
-				"query": "up{job=\"api\"}",
-				"time":  tt.time,
-			}
-			req := newMockRequest(paramsMap)
+func TestExecuteRangeQueryHandler_TargetPoints(t *testing.T) {
+	var gotStep time.Duration
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			gotStep = step
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{}}, nil
+		},
+	}
+
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{"query": "up", "target_points": 61, "duration": "1h"}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotStep != time.Minute || output.Step != "1m" {
+		t.Errorf("expected a step of 1m, got %s (output %q)", gotStep, output.Step)
+	}
+}
+
+func TestExecuteRangeQueryHandler_InjectedNow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var gotStart, gotEnd time.Time
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			gotStart, gotEnd = start, end
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{}}, nil
+		},
+	}
+
+	ctx := prometheus.ContextWithNow(withMockClient(context.Background(), mockClient), now)
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	for _, params := range []map[string]any{
+		{"query": "up", "step": "1m", "duration": "1h"},
+		{"query": "up", "step": "1m", "start": "NOW-1h", "end": "NOW"},
+	} {
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !gotStart.Equal(now.Add(-time.Hour)) || !gotEnd.Equal(now) {
+			t.Errorf("params %v: got range %v - %v, want %v - %v", params, gotStart, gotEnd, now.Add(-time.Hour), now)
+		}
+	}
+}
+
+func TestExecuteInstantQueryHandler_InjectedNow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var gotTime time.Time
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			gotTime = ts
+			return map[string]any{"resultType": "vector", "result": model.Vector{}}, nil
+		},
+	}
+
+	ctx := prometheus.ContextWithNow(withMockClient(context.Background(), mockClient), now)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	for _, tt := range []struct {
+		params map[string]any
+		want   time.Time
+	}{
+		{params: map[string]any{"query": "up"}, want: now},
+		{params: map[string]any{"query": "up", "time": "NOW"}, want: now},
+		{params: map[string]any{"query": "up", "time": "NOW-15m"}, want: now.Add(-15 * time.Minute)},
+		{params: map[string]any{"query": "up", "time": "2023-12-31T08:00:00Z"}, want: time.Date(2023, 12, 31, 8, 0, 0, 0, time.UTC)},
+	} {
+		req := newMockRequest(tt.params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(tt.params)); err != nil {
+			t.Fatalf("params %v: unexpected error: %v", tt.params, err)
+		}
+		if !gotTime.Equal(tt.want) {
+			t.Errorf("params %v: got query time %v, want %v", tt.params, gotTime, tt.want)
+		}
+	}
+}
+
+func TestExecuteRangeQueryHandler_SinceLastDeploy(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	deployedAt := now.Add(-90 * time.Minute)
+	var gotStart, gotEnd time.Time
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			if query != "deployment_created_timestamp" {
+				t.Errorf("expected the deploy marker query, got %q", query)
+			}
+			return map[string]any{"resultType": "vector", "result": model.Vector{
+				{Metric: model.Metric{"app": "api"}, Value: model.SampleValue(deployedAt.Add(-time.Hour).Unix())},
+				{Metric: model.Metric{"app": "web"}, Value: model.SampleValue(deployedAt.Unix())},
+			}}, nil
+		},
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			gotStart, gotEnd = start, end
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{}}, nil
+		},
+	}
+	ctx := prometheus.ContextWithNow(withMockClient(context.Background(), mockClient), now)
+
+	t.Run("start at the last deploy", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{DeployMarkerQuery: "deployment_created_timestamp"}})
+		params := map[string]any{"query": "up", "step": "1m", "start": "SINCE_LAST_DEPLOY"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !gotStart.Equal(deployedAt) || !gotEnd.Equal(now) {
+			t.Errorf("got range %v - %v, want %v - %v", gotStart, gotEnd, deployedAt, now)
+		}
+	})
+
+	t.Run("end at the last deploy", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{DeployMarkerQuery: "deployment_created_timestamp"}})
+		params := map[string]any{"query": "up", "step": "1m", "start": "NOW-6h", "end": "SINCE_LAST_DEPLOY"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !gotStart.Equal(now.Add(-6*time.Hour)) || !gotEnd.Equal(deployedAt) {
+			t.Errorf("got range %v - %v, want %v - %v", gotStart, gotEnd, now.Add(-6*time.Hour), deployedAt)
+		}
+	})
+
+	t.Run("no deploy marker query", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "up", "step": "1m", "start": "SINCE_LAST_DEPLOY"}
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err == nil || !strings.Contains(err.Error(), "no deploy marker query is configured") {
+			t.Errorf("expected an error about the missing deploy marker query, got %v", err)
+		}
+	})
+
+	t.Run("deploy marker query rejected by the guardrails", func(t *testing.T) {
+		mockClient.ExecuteInstantQueryFunc = func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return nil, fmt.Errorf("query validation failed: %w", &prometheus.GuardrailViolation{
+				Guardrail: prometheus.GuardrailRequireLabelMatcher,
+				Message:   `query for metric "deployment_created_timestamp" does not have any label matchers, which is required`,
+			})
+		}
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{DeployMarkerQuery: "deployment_created_timestamp"}})
+		params := map[string]any{"query": "up", "step": "1m", "start": "SINCE_LAST_DEPLOY"}
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err == nil || !strings.Contains(err.Error(), `deploy_marker_query "deployment_created_timestamp" is rejected by the require-label-matcher guardrail`) {
+			t.Errorf("expected an error pointing at the deploy marker query, got %v", err)
+		}
+	})
+
+	t.Run("no deploys", func(t *testing.T) {
+		mockClient.ExecuteInstantQueryFunc = func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{"resultType": "vector", "result": model.Vector{}}, nil
+		}
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{DeployMarkerQuery: "deployment_created_timestamp"}})
+		params := map[string]any{"query": "up", "step": "1m", "start": "SINCE_LAST_DEPLOY"}
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err == nil || !strings.Contains(err.Error(), "returned no deploy time") {
+			t.Errorf("expected an error about the missing deploy time, got %v", err)
+		}
+	})
+}
+
+func TestQueryHeatmapHandler(t *testing.T) {
+	bucket := func(le string, values ...float64) *model.SampleStream {
+		series := &model.SampleStream{Metric: model.Metric{"le": model.LabelValue(le)}}
+		for i, v := range values {
+			series.Values = append(series.Values, model.SamplePair{Timestamp: model.Time(1704067200000 + i*60000), Value: model.SampleValue(v)})
+		}
+		return series
+	}
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			want := `sum by (le) (increase(http_request_duration_seconds_bucket{job="api"}[1m]))`
+			if query != want {
+				t.Errorf("expected query %q, got %q", want, query)
+			}
+			return map[string]any{
+				"resultType": "matrix",
+				"result":     model.Matrix{bucket("+Inf", 10, 12), bucket("0.1", 4, 6), bucket("1", 9, 6)},
+			}, nil
+		},
+	}
+
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := QueryHeatmapHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{
+		"metric":   "http_request_duration_seconds_bucket",
+		"selector": `{job="api"}`,
+		"step":     "1m",
+		"duration": "1h",
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildHeatmapInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(output.Buckets, []string{"0.1", "1", "+Inf"}) {
+		t.Errorf("unexpected buckets: %v", output.Buckets)
+	}
+	if !slices.Equal(output.Timestamps, []float64{1704067200, 1704067260}) {
+		t.Errorf("unexpected timestamps: %v", output.Timestamps)
+	}
+	wantCounts := [][]float64{{4, 5, 1}, {6, 0, 6}}
+	for i := range wantCounts {
+		if i >= len(output.Counts) || !slices.Equal(output.Counts[i], wantCounts[i]) {
+			t.Fatalf("expected counts %v, got %v", wantCounts, output.Counts)
+		}
+	}
+
+	params["metric"] = "http_request_duration_seconds"
+	req = newMockRequest(params)
+	if _, _, err := handler(ctx, &req, tools.BuildHeatmapInput(params)); err == nil || !strings.Contains(err.Error(), "_bucket") {
+		t.Errorf("expected error for a metric without the _bucket suffix, got %v", err)
+	}
+}
+
+func TestLabelCardinalityTrendHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			want := `count(count by (path) (http_requests_total{job="api"}))`
+			if query != want {
+				t.Errorf("expected query %q, got %q", want, query)
+			}
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{{Metric: model.Metric{}, Values: []model.SamplePair{
+				{Timestamp: 1704067200000, Value: 12},
+				{Timestamp: 1704067260000, Value: 480},
+			}}}}, nil
+		},
+	}
+
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := LabelCardinalityTrendHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{
+		"metric":   "http_requests_total",
+		"label":    "path",
+		"selector": `job="api"`,
+		"step":     "1m",
+		"duration": "1h",
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildLabelCardinalityTrendInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Points) != 2 || output.Peak == nil || output.Peak.Timestamp != 1704067260 || output.Peak.Count != 480 {
+		t.Errorf("expected 2 points peaking at 480 values at 1704067260, got %+v", output)
+	}
+
+	delete(params, "label")
+	req = newMockRequest(params)
+	if _, _, err := handler(ctx, &req, tools.BuildLabelCardinalityTrendInput(params)); err == nil || !strings.Contains(err.Error(), "label parameter is required") {
+		t.Errorf("expected missing label error, got %v", err)
+	}
+}
+
+func TestSLOComplianceHandler(t *testing.T) {
+	series := func(values ...float64) model.Matrix {
+		s := &model.SampleStream{Metric: model.Metric{}}
+		for i, v := range values {
+			s.Values = append(s.Values, model.SamplePair{Timestamp: model.Time(1704067200000 + i*60000), Value: model.SampleValue(v)})
+		}
+		return model.Matrix{s}
+	}
+	var mu sync.Mutex
+	steps := make(map[string]time.Duration)
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			mu.Lock()
+			steps[query] = step
+			mu.Unlock()
+			result := series(100, 100)
+			if strings.Contains(query, "code!~") {
+				result = series(100, 99.8)
+			}
+			return map[string]any{"resultType": "matrix", "result": result}, nil
+		},
+	}
+
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := SLOComplianceHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{
+		"good_query":  `sum(rate(http_requests_total{code!~"5.."}[5m]))`,
+		"total_query": `sum(rate(http_requests_total[5m]))`,
+		"target":      99.9,
+		"step":        "5m",
+		"duration":    "1h",
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildSLOComplianceInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 2 || steps[params["good_query"].(string)] != 5*time.Minute || steps[params["total_query"].(string)] != 5*time.Minute {
+		t.Errorf("queries = %v, want both queries at a 5m step", steps)
+	}
+	if output.SLI != 99.9 || !output.Compliant || output.BurnRate != 1 || output.ErrorBudgetRemaining != 0 {
+		t.Errorf("output = %+v, want an SLI exactly at the target", output)
+	}
+
+	params["target"] = 100.0
+	if _, _, err = handler(ctx, &req, tools.BuildSLOComplianceInput(params)); err == nil || !strings.Contains(err.Error(), "target must be a percentage") {
+		t.Errorf("error = %v, want the target to be rejected", err)
+	}
+}
+
+func TestEvaluateConditionHandler_For(t *testing.T) {
+	var gotStart, gotEnd time.Time
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			gotStart, gotEnd = start, end
+			s := &model.SampleStream{Metric: model.Metric{"pod": "a"}}
+			for ts := start; !ts.After(end); ts = ts.Add(step) {
+				s.Values = append(s.Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(ts.UnixNano()), Value: 0.9})
+			}
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{s}}, nil
+		},
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := prometheus.ContextWithNow(withMockClient(t.Context(), mockClient), now)
+	handler := EvaluateConditionHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{
+		"query": `avg(rate(node_cpu_seconds_total{mode!="idle"}[5m])) > 0.8`,
+		"step":  "1m",
+		"for":   "10m",
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildEvaluateConditionInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gotStart.Equal(now.Add(-10*time.Minute)) || !gotEnd.Equal(now) {
+		t.Errorf("range = %v - %v, want the last 10m", gotStart, gotEnd)
+	}
+	if output.ForWindow == nil || !output.ForWindow.FiringAtEnd || !output.ForWindow.WouldHaveFired || output.ForWindow.For != "10m" {
+		t.Errorf("forWindow = %+v, want the alert firing at the end", output.ForWindow)
+	}
+
+	params["step"] = "15m"
+	params["duration"] = "1h"
+	_, output, err = handler(ctx, &req, tools.BuildEvaluateConditionInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "larger than the for duration") {
+		t.Errorf("warnings = %v, want a warning about the step", output.Warnings)
+	}
+
+	params["for"] = "0s"
+	if _, _, err = handler(ctx, &req, tools.BuildEvaluateConditionInput(params)); err == nil || !strings.Contains(err.Error(), "invalid for duration") {
+		t.Errorf("error = %v, want the for duration to be rejected", err)
+	}
+}
+
+func TestClusterStatusHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			switch {
+			case strings.Contains(query, "kube_node_status_condition"):
+				return map[string]any{"resultType": "vector", "result": model.Vector{{Metric: model.Metric{}, Value: 0}}}, nil
+			case strings.Contains(query, "kube_pod_container_status_restarts_total"):
+				return map[string]any{"resultType": "vector", "result": model.Vector{{Metric: model.Metric{}, Value: 2}}}, nil
+			case strings.Contains(query, "apiserver_request_duration_seconds_bucket"):
+				return nil, fmt.Errorf("server unavailable")
+			default:
+				return map[string]any{"resultType": "vector", "result": model.Vector{}}, nil
+			}
+		},
+	}
+
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ClusterStatusHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+	_, output, err := handler(ctx, &req, tools.BuildClusterStatusInput(map[string]any{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"node_readiness": tools.StatusOK,
+		"pod_restarts":   tools.StatusWarning,
+		"api_latency":    tools.StatusUnknown,
+		"etcd_health":    tools.StatusUnknown,
+	}
+	if len(output.Checks) != len(want) {
+		t.Fatalf("checks = %+v, want the %d default checks", output.Checks, len(want))
+	}
+	for _, check := range output.Checks {
+		if check.Status != want[check.Name] {
+			t.Errorf("check %s status = %q, want %q", check.Name, check.Status, want[check.Name])
+		}
+	}
+	if output.Checks[2].Error != "server unavailable" || output.Checks[2].Value != nil {
+		t.Errorf("failed check = %+v, want its error without a value", output.Checks[2])
+	}
+	if output.Checks[3].Error == "" {
+		t.Errorf("check without data = %+v, want an error", output.Checks[3])
+	}
+	if output.Status != tools.StatusWarning {
+		t.Errorf("status = %q, want the worst status %q", output.Status, tools.StatusWarning)
+	}
+
+	params := map[string]any{"checks": []any{"node_readiness"}}
+	_, output, err = handler(ctx, &req, tools.BuildClusterStatusInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Checks) != 1 || output.Status != tools.StatusOK {
+		t.Errorf("output = %+v, want only the ok node_readiness check", output)
+	}
+
+	params["checks"] = []any{"disk_space"}
+	if _, _, err = handler(ctx, &req, tools.BuildClusterStatusInput(params)); err == nil || !strings.Contains(err.Error(), `unknown check "disk_space"`) {
+		t.Errorf("error = %v, want the check to be rejected", err)
+	}
+}
+
+func TestClusterStatusHandler_ConfiguredChecks(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{"resultType": "scalar", "result": &model.Scalar{Value: 12}}, nil
+		},
+	}
+
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ClusterStatusHandler(ObsMCPOptions{Metrics: &tools.Config{
+		StatusChecks: []tools.StatusCheck{{Name: "pending_pods", Query: `sum(kube_pod_status_phase{phase="Pending"})`, Warning: 0, Critical: 10}},
+	}})
+	req := newMockRequest(map[string]any{})
+	_, output, err := handler(ctx, &req, tools.BuildClusterStatusInput(map[string]any{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Checks) != 1 || output.Checks[0].Name != "pending_pods" || output.Checks[0].Value == nil || *output.Checks[0].Value != 12 {
+		t.Fatalf("checks = %+v, want only the configured check with value 12", output.Checks)
+	}
+	if output.Status != tools.StatusCritical {
+		t.Errorf("status = %q, want %q", output.Status, tools.StatusCritical)
+	}
+}
+
+func TestEvaluateConditionHandler(t *testing.T) {
+	var gotQuery string
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			gotQuery = query
+			s := &model.SampleStream{Metric: model.Metric{}}
+			for i := range 4 {
+				ts := model.TimeFromUnixNano(start.Add(time.Duration(i) * step).UnixNano())
+				s.Values = append(s.Values, model.SamplePair{Timestamp: ts, Value: 0.9})
+			}
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{s}}, nil
+		},
+	}
+
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := EvaluateConditionHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{
+		"query": `avg(rate(node_cpu_seconds_total{mode!="idle"}[5m])) > 0.8`,
+		"step":  "5m",
+		"start": "2024-01-01T00:00:00Z",
+		"end":   "2024-01-01T01:00:00Z",
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildEvaluateConditionInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotQuery != params["query"] {
+		t.Errorf("query = %q, want %q", gotQuery, params["query"])
+	}
+	if output.TotalSteps != 13 || output.TrueSteps != 4 || output.LongestStreak == nil || output.LongestStreak.Duration != "15m" {
+		t.Errorf("output = %+v (streak %+v), want 4 of 13 steps true in a 15m streak", output, output.LongestStreak)
+	}
+
+	params["query"] = `avg(rate(node_cpu_seconds_total{mode!="idle"}[5m]))`
+	if _, _, err = handler(ctx, &req, tools.BuildEvaluateConditionInput(params)); err == nil || !strings.Contains(err.Error(), "must be a comparison") {
+		t.Errorf("error = %v, want the query to be rejected", err)
+	}
+}
+
+func TestEvaluateConditionHandler_SubMillisecondStart(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			// Prometheus rounds the start it receives to the millisecond.
+			s := &model.SampleStream{Metric: model.Metric{}}
+			for ts := start.Round(time.Millisecond); !ts.After(end); ts = ts.Add(step) {
+				s.Values = append(s.Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(ts.UnixNano()), Value: 0.9})
+			}
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{s}}, nil
+		},
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, int(600*time.Microsecond), time.UTC)
+	ctx := prometheus.ContextWithNow(withMockClient(t.Context(), mockClient), now)
+	handler := EvaluateConditionHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{
+		"query":    `avg(rate(node_cpu_seconds_total{mode!="idle"}[5m])) > 0.8`,
+		"step":     "1m",
+		"duration": "10m",
+		"for":      "5m",
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildEvaluateConditionInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.TrueFraction != 1 || output.ForWindow == nil || !output.ForWindow.FiringAtEnd {
+		t.Errorf("output = %+v (for window %+v), want the condition true at every step", output, output.ForWindow)
+	}
+
+	params["step"] = 0.0001
+	if _, _, err = handler(ctx, &req, tools.BuildEvaluateConditionInput(params)); err == nil || !strings.Contains(err.Error(), "at least 1ms") {
+		t.Errorf("error = %v, want a step below 1ms to be rejected", err)
+	}
+}
+
+func TestListMetricGroupsHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		ListMetricsFunc: func(ctx context.Context, nameRegex string) ([]string, error) {
+			if nameRegex != ".*" {
+				t.Errorf("expected all metrics to be listed, got name_regex %q", nameRegex)
+			}
+			return []string{"kube_pod_info", "kube_node_info", "node_load1", "up"}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ListMetricGroupsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildMetricGroupsInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.TotalMetrics != 4 {
+		t.Errorf("totalMetrics = %d, want 4", output.TotalMetrics)
+	}
+	want := []tools.MetricGroup{
+		{Prefix: "kube_", Count: 2, Examples: []string{"kube_node_info", "kube_pod_info"}},
+		{Prefix: "node_", Count: 1, Examples: []string{"node_load1"}},
+		{Prefix: "up", Count: 1, Examples: []string{"up"}},
+	}
+	if !reflect.DeepEqual(output.Groups, want) {
+		t.Errorf("groups = %+v, want %+v", output.Groups, want)
+	}
+}
+
+func TestExecuteInstantQueryHandler_Nearest(t *testing.T) {
+	queryTime := time.Unix(1700000000, 0)
+	stepTime := model.TimeFromUnix(queryTime.Add(-20 * time.Minute).Unix())
+	// The last sample was scraped before the last step it is returned at.
+	sampleTime := stepTime - 4*60_000 - 12_345
+
+	var rangeStart, rangeEnd time.Time
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{"resultType": "vector", "result": model.Vector{}}, nil
+		},
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			rangeStart, rangeEnd = start, end
+			if query == "timestamp(last_run)" {
+				return map[string]any{
+					"resultType": "matrix",
+					"result": model.Matrix{
+						{
+							Metric: model.Metric{"job": "batch"},
+							Values: []model.SamplePair{{Timestamp: stepTime - 60_000, Value: model.SampleValue(sampleTime-60_000) / 1000}, {Timestamp: stepTime, Value: model.SampleValue(sampleTime) / 1000}},
+						},
+					},
+				}, nil
+			}
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{
+						Metric: model.Metric{"__name__": "last_run", "job": "batch"},
+						Values: []model.SamplePair{{Timestamp: stepTime - 60_000, Value: 1}, {Timestamp: stepTime, Value: 2}},
+					},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("empty result is kept without nearest", func(t *testing.T) {
+		params := map[string]any{"query": "last_run", "time": "1700000000"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Result) != 0 || output.Nearest {
+			t.Errorf("expected an empty result, got %+v", output)
+		}
+	})
+
+	t.Run("nearest returns the latest preceding sample", func(t *testing.T) {
+		params := map[string]any{"query": "last_run", "time": "1700000000", "nearest": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !rangeEnd.Equal(queryTime) || !rangeStart.Equal(queryTime.Add(-time.Hour)) {
+			t.Errorf("range query over [%v, %v], want the hour before %v", rangeStart, rangeEnd, queryTime)
+		}
+		if !output.Nearest {
+			t.Error("expected the result to be marked as nearest")
+		}
+		if len(output.Result) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(output.Result))
+		}
+		want := []any{float64(sampleTime) / 1000, "2"}
+		if !reflect.DeepEqual(output.Result[0].Value, want) {
+			t.Errorf("value = %v, want %v", output.Result[0].Value, want)
+		}
+	})
+}
+
+func TestExecuteInstantQueryHandler_LabelsOnly(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"pod": "api-1"}, Value: 1, Timestamp: 1700000000000},
+					{Metric: model.Metric{"pod": "api-2"}, Value: 0, Timestamp: 1700000000000},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("values are omitted", func(t *testing.T) {
+		params := map[string]any{"query": `up{job="api"}`, "labels_only": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Result) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(output.Result))
+		}
+		for i, pod := range []string{"api-1", "api-2"} {
+			if output.Result[i].Metric["pod"] != pod || output.Result[i].Value != nil {
+				t.Errorf("result %d = %+v, want labels of %s without a value", i, output.Result[i], pod)
+			}
+		}
+	})
+
+	t.Run("cannot be combined with group_by", func(t *testing.T) {
+		params := map[string]any{"query": `up{job="api"}`, "labels_only": true, "group_by": "pod"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestExecuteInstantQueryHandler_ScalarValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		result model.Value
+		params map[string]any
+		want   string
+	}{
+		{
+			name:   "single sample without labels",
+			query:  `count(up{job="api"})`,
+			result: model.Vector{{Metric: model.Metric{}, Value: 3, Timestamp: 1700000000000}},
+			want:   "3",
+		},
+		{
+			name:   "single sample with only a metric name",
+			query:  `up{job="api"}`,
+			result: model.Vector{{Metric: model.Metric{"__name__": "up"}, Value: 1, Timestamp: 1700000000000}},
+			want:   "1",
+		},
+		{
+			name:   "scalar",
+			query:  `scalar(count(up{job="api"}))`,
+			result: &model.Scalar{Value: 2.5, Timestamp: 1700000000000},
+			want:   "2.5",
+		},
+		{
+			name:   "single sample with labels",
+			query:  `up{job="api"}`,
+			result: model.Vector{{Metric: model.Metric{"job": "api"}, Value: 1, Timestamp: 1700000000000}},
+		},
+		{
+			name:  "several samples",
+			query: `count by (job) (up)`,
+			result: model.Vector{
+				{Metric: model.Metric{}, Value: 1, Timestamp: 1700000000000},
+				{Metric: model.Metric{}, Value: 2, Timestamp: 1700000000000},
+			},
+		},
+		{
+			name:   "labels only",
+			query:  `count(up{job="api"})`,
+			result: model.Vector{{Metric: model.Metric{}, Value: 3, Timestamp: 1700000000000}},
+			params: map[string]any{"labels_only": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockedLoader{
+				ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+					return map[string]any{"resultType": tt.result.Type().String(), "result": tt.result}, nil
+				},
+			}
+			ctx := withMockClient(t.Context(), mockClient)
+			handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+			params := map[string]any{"query": tt.query}
+			maps.Copy(params, tt.params)
+			req := newMockRequest(params)
+			_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.ScalarValue != tt.want {
+				t.Errorf("scalarValue = %q, want %q", output.ScalarValue, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteInstantQueryHandler_ProjectLabels(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"namespace": "a", "pod": "a-1"}, Value: 1, Timestamp: 1700000000000},
+					{Metric: model.Metric{"namespace": "a", "pod": "a-2"}, Value: 2, Timestamp: 1700000000000},
+					{Metric: model.Metric{"namespace": "b", "pod": "b-1"}, Value: 4, Timestamp: 1700000000000},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("series with the same projected labels are merged", func(t *testing.T) {
+		params := map[string]any{"query": "up", "project_labels": "namespace"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []tools.InstantResult{
+			{Metric: map[string]string{"namespace": "a"}, Value: []any{1700000000.0, "3"}, Merged: 2},
+			{Metric: map[string]string{"namespace": "b"}, Value: []any{1700000000.0, "4"}},
+		}
+		if !reflect.DeepEqual(output.Result, want) {
+			t.Errorf("result = %+v, want %+v", output.Result, want)
+		}
+	})
+
+	t.Run("cannot be combined with group_by", func(t *testing.T) {
+		params := map[string]any{"query": "up", "project_labels": "namespace", "group_by": "pod"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("invalid label name", func(t *testing.T) {
+		params := map[string]any{"query": "up", "project_labels": "name space"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestExecuteInstantQueryHandler_Rank(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"pod": "api-1"}, Value: 2, Timestamp: 1700000000000},
+					{Metric: model.Metric{"pod": "api-2"}, Value: 7, Timestamp: 1700000000000},
+					{Metric: model.Metric{"pod": "api-3"}, Value: 5, Timestamp: 1700000000000},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("series are sorted and ranked by value", func(t *testing.T) {
+		params := map[string]any{"query": `topk(3, rate(http_requests_total{job="api"}[5m]))`, "rank": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []tools.InstantResult{
+			{Metric: map[string]string{"pod": "api-2"}, Value: []any{1700000000.0, "7"}, Rank: 1},
+			{Metric: map[string]string{"pod": "api-3"}, Value: []any{1700000000.0, "5"}, Rank: 2},
+			{Metric: map[string]string{"pod": "api-1"}, Value: []any{1700000000.0, "2"}, Rank: 3},
+		}
+		if !reflect.DeepEqual(output.Result, want) {
+			t.Errorf("result = %+v, want %+v", output.Result, want)
+		}
+		if output.RankedCount != 3 {
+			t.Errorf("rankedCount = %d, want 3", output.RankedCount)
+		}
+	})
+
+	t.Run("results are not ranked by default", func(t *testing.T) {
+		params := map[string]any{"query": `up{job="api"}`}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output.Result[0].Metric["pod"] != "api-1" || output.Result[0].Rank != 0 || output.RankedCount != 0 {
+			t.Errorf("result = %+v (rankedCount %d), want the backend order without ranks", output.Result, output.RankedCount)
+		}
+	})
+
+	t.Run("cannot be combined with group_by", func(t *testing.T) {
+		params := map[string]any{"query": `up{job="api"}`, "rank": true, "group_by": "pod"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestExecuteInstantQueryHandler_AggregationAdvisory(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "vector",
+				"result":     model.Vector{{Metric: model.Metric{}, Value: 3, Timestamp: 1700000000000}},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	tests := []struct {
+		query       string
+		wantWarning bool
+	}{
+		{query: "sum(up)", wantWarning: true},
+		{query: "sum by (job) (up)", wantWarning: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			params := map[string]any{"query": tt.query}
+			req := newMockRequest(params)
+			_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotWarning := slices.ContainsFunc(output.Warnings, func(w string) bool {
+				return strings.Contains(w, "no metric name or identifying labels")
+			})
+			if gotWarning != tt.wantWarning {
+				t.Errorf("warnings = %q, want aggregation advisory: %v", output.Warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestExecuteInstantQueryHandler_EmptyResultWarning(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			if query == "up" {
+				return map[string]any{
+					"resultType": "vector",
+					"result":     model.Vector{{Metric: model.Metric{"__name__": "up"}, Value: 1, Timestamp: 1700000000000}},
+				}, nil
+			}
+			return map[string]any{"resultType": "vector", "result": model.Vector{}}, nil
+		},
+		GetMetricMetadataFunc: func(ctx context.Context, metric string) ([]v1.Metadata, error) {
+			switch metric {
+			case "http_requests_total":
+				return []v1.Metadata{{Type: v1.MetricTypeCounter, Help: "Total number of HTTP requests."}}, nil
+			case "node_load1":
+				return nil, errors.New("metadata unavailable")
+			}
+			return nil, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	tests := []struct {
+		query       string
+		wantWarning string
+	}{
+		{query: "up", wantWarning: ""},
+		{
+			query:       `rate(http_requests_total{code="500"}[5m])`,
+			wantWarning: "the query returned no data; check that the metrics it selects are the intended ones: http_requests_total (counter): Total number of HTTP requests.",
+		},
+		{
+			query:       "node_load1 > http_request_total",
+			wantWarning: "the query returned no data; check that the metrics it selects are the intended ones: http_request_total (no metadata; it may be misspelled, a recording rule or not scraped); node_load1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			params := map[string]any{"query": tt.query}
+			req := newMockRequest(params)
+			_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got string
+			for _, w := range output.Warnings {
+				if strings.HasPrefix(w, "the query returned no data") {
+					got = w
+				}
+			}
+			if got != tt.wantWarning {
+				t.Errorf("empty result warning = %q, want %q", got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestExecuteInstantQueryHandler_Verbosity(t *testing.T) {
+	queryTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"job": "api", "pod": "api-1"}, Value: 1},
+					{Metric: model.Metric{"job": "api", "pod": "api-2"}, Value: 2},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	run := func(t *testing.T, verbosity string) tools.InstantQueryOutput {
+		t.Helper()
+		params := map[string]any{
+			"query":          "sum(up)",
+			"time":           queryTime.Format(time.RFC3339),
+			"project_labels": "job",
+			"verbosity":      verbosity,
+		}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Result) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(output.Result))
+		}
+		return output
+	}
+
+	t.Run("standard", func(t *testing.T) {
+		output := run(t, "")
+		if output.ExecutedQuery != nil || output.Stats != nil {
+			t.Errorf("expected no executed query or stats, got %+v, %+v", output.ExecutedQuery, output.Stats)
+		}
+		if len(output.Warnings) == 0 || output.Result[0].Merged != 2 {
+			t.Errorf("expected warnings and merged series, got %q, merged %d", output.Warnings, output.Result[0].Merged)
+		}
+	})
+
+	t.Run("minimal", func(t *testing.T) {
+		output := run(t, "minimal")
+		if output.Result[0].Merged != 0 || output.ExecutedQuery != nil || output.Stats != nil {
+			t.Errorf("expected only values, got %+v", output)
+		}
+		// Warnings tell that the result is partial or approximate, so they are always kept.
+		if len(output.Warnings) == 0 {
+			t.Error("expected the warnings to be kept at minimal verbosity")
+		}
+		if output.Result[0].Value[1] != "3" {
+			t.Errorf("value = %v, want 3", output.Result[0].Value[1])
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		output := run(t, "full")
+		want := &tools.ExecutedQuery{Query: "sum(up)", Time: "2024-01-01T00:00:00Z"}
+		if !reflect.DeepEqual(output.ExecutedQuery, want) {
+			t.Errorf("executedQuery = %+v, want %+v", output.ExecutedQuery, want)
+		}
+		if output.Stats == nil || output.Stats.Series != 2 || output.Stats.Samples != 2 || output.Stats.Duration == "" {
+			t.Errorf("unexpected stats %+v", output.Stats)
+		}
+		if len(output.Warnings) == 0 || output.Result[0].Merged != 2 {
+			t.Errorf("expected warnings and merged series, got %q, merged %d", output.Warnings, output.Result[0].Merged)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		params := map[string]any{"query": "up", "verbosity": "verbose"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error for an invalid verbosity, got nil")
+		}
+	})
+}
+
+func TestExecuteRangeQueryHandler_Verbosity(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{
+						Metric: model.Metric{"pod": "deleted"},
+						Values: []model.SamplePair{{Timestamp: model.TimeFromUnixNano(start.UnixNano()), Value: 1}, {Timestamp: model.TimeFromUnixNano(start.Add(time.Minute).UnixNano()), Value: 1}},
+					},
+				},
+				"warnings": []string{"partial response"},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	run := func(t *testing.T, verbosity string) tools.RangeQueryOutput {
+		t.Helper()
+		params := map[string]any{
+			"query":     `up{job="api"}`,
+			"step":      "1m",
+			"start":     start.Format(time.RFC3339),
+			"end":       end.Format(time.RFC3339),
+			"verbosity": verbosity,
+		}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Summary) != 1 {
+			t.Fatalf("expected 1 summary, got %d", len(output.Summary))
+		}
+		return output
+	}
+
+	if output := run(t, "minimal"); output.Summary[0].Stale || output.Stats != nil || !slices.Contains(output.Warnings, "partial response") {
+		t.Errorf("expected the warnings but no stale annotation or stats at minimal verbosity, got %+v", output)
+	}
+	if output := run(t, "standard"); !output.Summary[0].Stale || output.Stats != nil {
+		t.Errorf("expected a stale annotation and no stats at standard verbosity, got %+v", output)
+	}
+	output := run(t, "full")
+	want := &tools.ExecutedQuery{Query: `up{job="api"}`, Start: "2024-01-01T00:00:00Z", End: "2024-01-01T01:00:00Z", Step: "1m"}
+	if !reflect.DeepEqual(output.ExecutedQuery, want) {
+		t.Errorf("executedQuery = %+v, want %+v", output.ExecutedQuery, want)
+	}
+	if output.Stats == nil || output.Stats.Series != 1 || output.Stats.Samples != 2 {
+		t.Errorf("unexpected stats %+v", output.Stats)
+	}
+}
+
+func TestExecuteRangeQueryHandler_RawResponse(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	matrix := model.Matrix{
+		{
+			Metric: model.Metric{"__name__": "up", "job": "api"},
+			Values: []model.SamplePair{{Timestamp: model.TimeFromUnixNano(start.UnixNano()), Value: 1}, {Timestamp: model.TimeFromUnixNano(start.Add(time.Minute).UnixNano()), Value: 0}},
+		},
+	}
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{"resultType": "matrix", "result": matrix, "warnings": []string{"partial response"}}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("prometheus envelope", func(t *testing.T) {
+		params := map[string]any{
+			"query":        `up{job="api"}`,
+			"step":         "1m",
+			"start":        start.Format(time.RFC3339),
+			"end":          start.Add(time.Hour).Format(time.RFC3339),
+			"raw_response": "prometheus",
+		}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := json.Marshal(output)
+		if err != nil {
+			t.Fatalf("failed to marshal output: %v", err)
+		}
+
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			t.Fatalf("failed to decode envelope: %v", err)
+		}
+		if keys := slices.Sorted(maps.Keys(envelope)); !slices.Equal(keys, []string{"data", "status", "warnings"}) {
+			t.Fatalf("expected only the keys of a Prometheus API response, got %v", keys)
+		}
+
+		// Decode the way Prometheus API clients do.
+		var response struct {
+			Status string `json:"status"`
+			Data   struct {
+				ResultType model.ValueType `json:"resultType"`
+				Result     model.Matrix    `json:"result"`
+			} `json:"data"`
+			Warnings []string `json:"warnings"`
+		}
+		if err := json.Unmarshal(data, &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Status != "success" || response.Data.ResultType != model.ValMatrix {
+			t.Errorf("unexpected status %q or result type %q", response.Status, response.Data.ResultType)
+		}
+		if !reflect.DeepEqual(response.Data.Result, matrix) {
+			t.Errorf("result = %v, want %v", response.Data.Result, matrix)
+		}
+		if !slices.Equal(response.Warnings, []string{"partial response"}) {
+			t.Errorf("warnings = %v, want [partial response]", response.Warnings)
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		params := map[string]any{"query": "up", "step": "1m", "raw_response": "grafana"}
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err == nil || !strings.Contains(err.Error(), "invalid raw_response") {
+			t.Errorf("expected an invalid raw_response error, got %v", err)
+		}
+	})
+
+	t.Run("cannot be combined with project_labels", func(t *testing.T) {
+		params := map[string]any{"query": "up", "step": "1m", "raw_response": "prometheus", "project_labels": "job"}
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err == nil || !strings.Contains(err.Error(), "cannot be combined with raw_response") {
+			t.Errorf("expected a combination error, got %v", err)
+		}
+	})
+}
+
+func TestExecuteRangeQueryHandler_ConvertUnits(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	matrix := model.Matrix{
+		{
+			Metric: model.Metric{"pod": "api"},
+			Values: []model.SamplePair{{Timestamp: model.TimeFromUnixNano(start.UnixNano()), Value: 512 << 20}, {Timestamp: model.TimeFromUnixNano(start.Add(time.Minute).UnixNano()), Value: 1536 << 20}},
+		},
+	}
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{"resultType": "matrix", "result": matrix}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+
+	run := func(t *testing.T, cfg *tools.Config, query string) tools.RangeQueryOutput {
+		t.Helper()
+		params := map[string]any{
+			"query":         query,
+			"step":          "1m",
+			"start":         start.Format(time.RFC3339),
+			"end":           start.Add(2 * time.Minute).Format(time.RFC3339),
+			"convert_units": true,
+		}
+		req := newMockRequest(params)
+		_, output, err := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: cfg})(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return output
+	}
+
+	t.Run("full response", func(t *testing.T) {
+		output := run(t, &tools.Config{RangeQueryFullResponse: true}, "container_memory_working_set_bytes")
+		if output.Unit != tools.UnitBytes {
+			t.Errorf("unit = %q, want %q", output.Unit, tools.UnitBytes)
+		}
+		// All values of the series share the unit of the largest one; raw values are kept.
+		if want := []any{"0.5 GiB", "1.5 GiB"}; !reflect.DeepEqual(output.Result[0].Converted, want) {
+			t.Errorf("converted = %v, want %v", output.Result[0].Converted, want)
+		}
+		if raw := output.Result[0].Values[1][1]; raw != "1610612736" {
+			t.Errorf("raw value = %v, want 1610612736", raw)
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		output := run(t, &tools.Config{}, "container_memory_working_set_bytes")
+		converted := output.Summary[0].Converted
+		if converted == nil || converted.Min != "512 MiB" || converted.Max != "1.5 GiB" || converted.Avg != "1 GiB" {
+			t.Errorf("converted summary = %+v, want min 512 MiB, max 1.5 GiB and avg 1 GiB", converted)
+		}
+		if output.Summary[0].Max != 1536<<20 {
+			t.Errorf("raw max = %v, want %v", output.Summary[0].Max, 1536<<20)
+		}
+	})
+
+	t.Run("unknown unit", func(t *testing.T) {
+		output := run(t, &tools.Config{}, "kube_pod_info")
+		if output.Unit != "" || output.Summary[0].Converted != nil {
+			t.Errorf("expected no conversion, got unit %q and %+v", output.Unit, output.Summary[0].Converted)
+		}
+		if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "could not be inferred") {
+			t.Errorf("expected a warning about the unknown unit, got %v", output.Warnings)
+		}
+	})
+}
+
+func TestExecuteRangeQueryHandler_DescribeShape(t *testing.T) {
+	values := make([]model.SamplePair, 60)
+	for i := range values {
+		values[i] = model.SamplePair{Timestamp: model.Time(int64(i) * 60_000), Value: model.SampleValue(100 + 10*i)}
+	}
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{Metric: model.Metric{"pod": "api-1"}, Values: values},
+				},
+			}, nil
+		},
+	}
+
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{RangeQueryFullResponse: true}})
+
+	params := map[string]any{"query": "container_memory_working_set_bytes", "step": "1m", "describe_shape": true}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Result) != 0 || len(output.Summary) != 0 {
+		t.Errorf("expected no values or summaries, got %d series and %d summaries", len(output.Result), len(output.Summary))
+	}
+	if len(output.Shapes) != 1 {
+		t.Fatalf("expected 1 shape, got %d", len(output.Shapes))
+	}
+	shape := output.Shapes[0]
+	if shape.Series["pod"] != "api-1" {
+		t.Errorf("series = %v, want pod api-1", shape.Series)
+	}
+	if shape.Shape.Trend != resultutil.TrendUp || shape.Shape.Min != 100 || shape.Shape.Current != 690 {
+		t.Errorf("shape = %+v, want trend up from 100 to 690", shape.Shape)
+	}
+
+	t.Run("cannot be combined with raw_response", func(t *testing.T) {
+		params := map[string]any{"query": "up", "step": "1m", "describe_shape": true, "raw_response": "prometheus"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildRangeQueryInput(params)); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestExecuteRangeQueryHandler_Pagination(t *testing.T) {
+	var matrix model.Matrix
+	for _, pod := range []string{"web-2", "api-1", "web-1", "api-2", "db-1"} {
+		matrix = append(matrix, &model.SampleStream{
+			Metric: model.Metric{"pod": model.LabelValue(pod)},
+			Values: []model.SamplePair{{Timestamp: 0, Value: 1}},
+		})
+	}
+	type window struct{ start, end time.Time }
+	var windows []window
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			windows = append(windows, window{start, end})
+			// The backend returns the series in no particular order.
+			shuffled := slices.Clone(matrix)
+			rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+			return map[string]any{"resultType": "matrix", "result": shuffled}, nil
+		},
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	call := func(t *testing.T, ctx context.Context, params map[string]any) (tools.RangeQueryOutput, error) {
+		t.Helper()
+		req := newMockRequest(params)
+		_, output, err := handler(withMockClient(ctx, mockClient), &req, tools.BuildRangeQueryInput(params))
+		return output, err
+	}
+
+	t.Run("pages cover every series once in label order", func(t *testing.T) {
+		windows = nil
+		var pods []string
+		params := map[string]any{"query": "up", "step": "1m", "duration": "1h", "page_size": 2}
+		for page := 0; ; page++ {
+			if page == 3 {
+				t.Fatal("expected 5 series to fit in 3 pages")
+			}
+			// Later pages are fetched later, but must cover the window of the first one.
+			output, err := call(t, prometheus.ContextWithNow(t.Context(), now.Add(time.Duration(page)*time.Minute)), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Page == nil || output.Page.TotalSeries != 5 {
+				t.Fatalf("page = %+v, want 5 series in total", output.Page)
+			}
+			for _, s := range output.Summary {
+				pods = append(pods, s.Series["pod"])
+			}
+			if output.NextToken == "" {
+				break
+			}
+			params = map[string]any{"query": "up", "step": "1m", "page_token": output.NextToken}
+		}
+		if want := []string{"api-1", "api-2", "db-1", "web-1", "web-2"}; !slices.Equal(pods, want) {
+			t.Errorf("pages returned %v, want %v", pods, want)
+		}
+		for _, w := range windows {
+			if !w.start.Equal(now.Add(-time.Hour)) || !w.end.Equal(now) {
+				t.Errorf("page queried [%s, %s], want the window of the first page [%s, %s]", w.start, w.end, now.Add(-time.Hour), now)
+			}
+		}
+	})
+
+	t.Run("token of another query is rejected", func(t *testing.T) {
+		first, err := call(t, prometheus.ContextWithNow(t.Context(), now), map[string]any{"query": "up", "step": "1m", "duration": "1h", "page_size": 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = call(t, t.Context(), map[string]any{"query": "up{job=\"api\"}", "step": "1m", "page_token": first.NextToken})
+		if err == nil || !strings.Contains(err.Error(), "issued for another query") {
+			t.Errorf("expected a query mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("cannot be combined with sampling", func(t *testing.T) {
+		_, err := call(t, t.Context(), map[string]any{"query": "up", "step": "1m", "page_size": 2, "sampling": true})
+		if err == nil || !strings.Contains(err.Error(), "cannot be combined with sampling") {
+			t.Errorf("expected a combination error, got %v", err)
+		}
+	})
+}
+
+func TestExecuteRangeQueryHandler_PaginationKeepsSeriesLimit(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data string
+		switch r.URL.Path {
+		case "/api/v1/label/__name__/values":
+			data = `["up"]`
+		case "/api/v1/query_range":
+			data = `{"resultType":"matrix","result":[` +
+				`{"metric":{"job":"api"},"values":[[1704067200,"1"]]},` +
+				`{"metric":{"job":"db"},"values":[[1704067200,"1"]]},` +
+				`{"metric":{"job":"web"},"values":[[1704067200,"1"]]}]}`
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, data)
+	}))
+	defer backend.Close()
+
+	guardrails := &prometheus.Guardrails{MaxResultSeries: 2}
+	handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	call := func(guardrails *prometheus.Guardrails) (tools.RangeQueryOutput, error) {
+		promClient, err := prometheus.NewPrometheusLoader(promapi.Config{Address: backend.URL})
+		if err != nil {
+			t.Fatalf("failed to create Prometheus client: %v", err)
+		}
+		params := map[string]any{"query": "up", "step": "1m", "page_size": 1}
+		req := newMockRequest(params)
+		_, output, err := handler(withMockClient(t.Context(), promClient.WithGuardrails(guardrails)), &req, tools.BuildRangeQueryInput(params))
+		return output, err
+	}
+
+	if _, err := call(guardrails); err == nil || !strings.Contains(err.Error(), "exceeds maximum allowed") {
+		t.Errorf("expected the series limit to reject a paginated query, got %v", err)
+	}
+
+	// A trusted guardrail override still lifts the limit.
+	relaxed, err := guardrails.Relax(prometheus.GuardrailMaxResultSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output, err := call(relaxed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Page == nil || output.Page.TotalSeries != 3 || output.NextToken == "" {
+		t.Errorf("page = %+v, want the first of 3 series with a next token", output.Page)
+	}
+}
+
+func TestExecuteInstantQueryHandler_ConvertUnits(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{"resultType": "vector", "result": model.Vector{{Metric: model.Metric{"job": "api"}, Value: 0.25}}}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{
+		"query":         "histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket[5m])))",
+		"convert_units": true,
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Unit != tools.UnitSeconds || output.Result[0].Converted != "250 ms" {
+		t.Errorf("unit = %q and converted = %q, want seconds and 250 ms", output.Unit, output.Result[0].Converted)
+	}
+	if output.Result[0].Value[1] != "0.25" {
+		t.Errorf("raw value = %v, want 0.25", output.Result[0].Value[1])
+	}
+}
+
+func TestQueryHandlers_CardinalityAdvisory(t *testing.T) {
+	advisory := "high cardinality metrics, above 50% of the maximum allowed 20000 series: http_requests_total (15000 series); add label matchers to select only the series needed"
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{"resultType": "vector", "result": model.Vector{}, "advisories": []string{advisory}}, nil
+		},
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{}, "advisories": []string{advisory}}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+
+	t.Run("instant query", func(t *testing.T) {
+		params := map[string]any{"query": "http_requests_total"}
+		req := newMockRequest(params)
+		_, output, err := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(output.Warnings, advisory) {
+			t.Errorf("expected the advisory in the warnings, got %v", output.Warnings)
+		}
+	})
+
+	t.Run("range query", func(t *testing.T) {
+		params := map[string]any{"query": "http_requests_total", "step": "1m"}
+		req := newMockRequest(params)
+		_, output, err := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(output.Warnings, advisory) {
+			t.Errorf("expected the advisory in the warnings, got %v", output.Warnings)
+		}
+	})
+}
+
+func TestExecuteInstantQueryHandler_Sampling(t *testing.T) {
+	vector := make(model.Vector, 50)
+	for i := range vector {
+		vector[i] = &model.Sample{Metric: model.Metric{"pod": model.LabelValue(fmt.Sprintf("api-%02d", i))}, Value: model.SampleValue(i)}
+	}
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{"resultType": "vector", "result": vector}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{MaxResultSeries: new(uint64(10))}})
+
+	run := func(t *testing.T, params map[string]any) tools.InstantQueryOutput {
+		t.Helper()
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return output
+	}
+
+	t.Run("oversized result is sampled", func(t *testing.T) {
+		output := run(t, map[string]any{"query": `up{job="api"}`, "sampling": true, "seed": 7})
+		if len(output.Result) != 10 {
+			t.Fatalf("expected 10 series, got %d", len(output.Result))
+		}
+		if output.Sampled == nil || output.Sampled.TotalSeries != 50 || output.Sampled.Seed != 7 {
+			t.Fatalf("unexpected sampling info: %+v", output.Sampled)
+		}
+		if !slices.ContainsFunc(output.Result, func(r tools.InstantResult) bool { return r.Metric["pod"] == "api-49" }) {
+			t.Error("sample misses the series with the highest value")
+		}
+
+		again := run(t, map[string]any{"query": `up{job="api"}`, "sampling": true, "seed": 7})
+		if !reflect.DeepEqual(output.Result, again.Result) {
+			t.Error("sample changed with the same seed")
+		}
+	})
+
+	t.Run("a seed is generated when none is given", func(t *testing.T) {
+		output := run(t, map[string]any{"query": `up{job="api"}`, "sampling": true})
+		if output.Sampled == nil || output.Sampled.Seed == 0 {
+			t.Fatalf("expected a generated seed, got %+v", output.Sampled)
+		}
+	})
+
+	t.Run("series are ranked before sampling", func(t *testing.T) {
+		output := run(t, map[string]any{"query": `up{job="api"}`, "sampling": true, "seed": 7, "rank": true})
+		if len(output.Result) != 10 || output.RankedCount != 50 {
+			t.Fatalf("expected 10 of 50 ranked series, got %d of %d", len(output.Result), output.RankedCount)
+		}
+		for i, r := range output.Result {
+			// The value of api-NN is NN, so its rank in the full result is 50-NN.
+			var n int
+			if _, err := fmt.Sscanf(r.Metric["pod"], "api-%d", &n); err != nil {
+				t.Fatalf("unexpected series %v", r.Metric)
+			}
+			if r.Rank != 50-n {
+				t.Errorf("rank of %s = %d, want %d", r.Metric["pod"], r.Rank, 50-n)
+			}
+			if i > 0 && r.Rank <= output.Result[i-1].Rank {
+				t.Errorf("series not in rank order: %d after %d", r.Rank, output.Result[i-1].Rank)
+			}
+		}
+		if output.Result[0].Rank != 1 {
+			t.Errorf("sample misses the series ranked first, got rank %d", output.Result[0].Rank)
+		}
+	})
+
+	t.Run("result is complete without sampling", func(t *testing.T) {
+		output := run(t, map[string]any{"query": `up{job="api"}`})
+		if len(output.Result) != 50 || output.Sampled != nil {
+			t.Errorf("expected the full result, got %d series and %+v", len(output.Result), output.Sampled)
+		}
+	})
+}
+
+func TestExecuteInstantQueryHandler_GroupBy(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"namespace": "a", "pod": "a-1"}, Value: 2},
+					{Metric: model.Metric{"namespace": "a", "pod": "a-2"}, Value: 6},
+					{Metric: model.Metric{"namespace": "b", "pod": "b-1"}, Value: 1},
+					{Metric: model.Metric{"pod": "orphan"}, Value: 3},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	tests := []struct {
+		agg  string
+		want map[string]tools.InstantGroup
+	}{
+		{
+			agg: "",
+			want: map[string]tools.InstantGroup{
+				"a": {Value: "8", SeriesCount: 2}, "b": {Value: "1", SeriesCount: 1}, "": {Value: "3", SeriesCount: 1},
+			},
+		},
+		{
+			agg: "max",
+			want: map[string]tools.InstantGroup{
+				"a": {Value: "6", SeriesCount: 2}, "b": {Value: "1", SeriesCount: 1}, "": {Value: "3", SeriesCount: 1},
+			},
+		},
+		{
+			agg: "avg",
+			want: map[string]tools.InstantGroup{
+				"a": {Value: "4", SeriesCount: 2}, "b": {Value: "1", SeriesCount: 1}, "": {Value: "3", SeriesCount: 1},
+			},
+		},
+		{
+			agg: "count",
+			want: map[string]tools.InstantGroup{
+				"a": {Value: "2", SeriesCount: 2}, "b": {Value: "1", SeriesCount: 1}, "": {Value: "1", SeriesCount: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("agg "+tt.agg, func(t *testing.T) {
+			params := map[string]any{"query": "errors", "group_by": "namespace"}
+			if tt.agg != "" {
+				params["group_agg"] = tt.agg
+			}
+			req := newMockRequest(params)
+			_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Result != nil {
+				t.Errorf("expected no individual series when grouping, got %v", output.Result)
+			}
+			data, err := json.Marshal(output)
+			if err != nil {
+				t.Fatalf("failed to marshal output: %v", err)
+			}
+			if strings.Contains(string(data), `"result"`) {
+				t.Errorf("expected result to be omitted when grouping, got %s", data)
+			}
+			if !maps.Equal(output.Groups, tt.want) {
+				t.Errorf("groups = %v, want %v", output.Groups, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid aggregation", func(t *testing.T) {
+		params := map[string]any{"query": "errors", "group_by": "namespace", "group_agg": "median"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error for invalid group_agg, got nil")
+		}
+	})
+
+	t.Run("aggregation without group_by", func(t *testing.T) {
+		params := map[string]any{"query": "errors", "group_agg": "max"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error for group_agg without group_by, got nil")
+		}
+	})
+}
+
+func TestExecuteInstantQueryHandler_RelativeTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		time       string
+		validateFn func(t *testing.T, queryTime time.Time)
+	}{
+		{
+			name: "NOW-5m",
+			time: "NOW-5m",
+			validateFn: func(t *testing.T, queryTime time.Time) {
+				expected := time.Now().Add(-5 * time.Minute)
+				diff := queryTime.Sub(expected).Abs()
+				if diff > 2*time.Second {
+					t.Errorf("expected query time to be ~5m ago, got %v (diff: %v)", queryTime, diff)
+				}
+			},
+		},
+		{
+			name: "NOW-1h",
+			time: "NOW-1h",
+			validateFn: func(t *testing.T, queryTime time.Time) {
+				expected := time.Now().Add(-1 * time.Hour)
+				diff := queryTime.Sub(expected).Abs()
+				if diff > 2*time.Second {
+					t.Errorf("expected query time to be ~1h ago, got %v (diff: %v)", queryTime, diff)
+				}
+			},
+		},
+		{
+			name: "now-30s (lowercase)",
+			time: "now-30s",
+			validateFn: func(t *testing.T, queryTime time.Time) {
+				expected := time.Now().Add(-30 * time.Second)
+				diff := queryTime.Sub(expected).Abs()
+				if diff > 2*time.Second {
+					t.Errorf("expected query time to be ~30s ago, got %v (diff: %v)", queryTime, diff)
+				}
+			},
+		},
+		{
+			name: "NOW+5m (future time)",
+			time: "NOW+5m",
+			validateFn: func(t *testing.T, queryTime time.Time) {
+				expected := time.Now().Add(5 * time.Minute)
+				diff := queryTime.Sub(expected).Abs()
+				if diff > 2*time.Second {
+					t.Errorf("expected query time to be ~5m from now, got %v (diff: %v)", queryTime, diff)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockedLoader{
+				ExecuteInstantQueryFunc: func(ctx context.Context, query string, queryTime time.Time) (map[string]any, error) {
+					if tt.validateFn != nil {
+						tt.validateFn(t, queryTime)
+					}
+					return map[string]any{"resultType": "vector", "result": []any{}}, nil
+				},
+			}
+
+			ctx := withMockClient(context.Background(), mockClient)
+			handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+			paramsMap := map[string]any{
+				"query": "up{job=\"api\"}",
+				"time":  tt.time,
+			}
+			req := newMockRequest(paramsMap)
 			input := tools.BuildInstantQueryInput(paramsMap)
 			_, _, err := handler(ctx, &req, input)
 			if err != nil {
@@ -679,20 +2883,815 @@ func TestExecuteInstantQueryHandler_RelativeTime(t *testing.T) {
 	}
 }
 
-func TestGetAlertsHandler_AllAlerts(t *testing.T) {
+func TestQueryHandlers_DryRun(t *testing.T) {
+	ctx := context.WithValue(context.Background(), kubernetes.OAuthAuthorizationHeader, "Bearer secret-token")
+	rt, err := auth.BuildRoundTripper(ctx, &rest.Config{}, auth.AuthModeHeader, true, true)
+	if err != nil {
+		t.Fatalf("failed to create round tripper: %v", err)
+	}
+	// The address is never dialed: the query request is recorded instead of sent.
+	promClient, err := prometheus.NewPrometheusLoader(promapi.Config{Address: "https://prometheus.invalid:9091", RoundTripper: rt})
+	if err != nil {
+		t.Fatalf("failed to create Prometheus client: %v", err)
+	}
+	promClient.WithGuardrails(nil)
+	ctx = withMockClient(ctx, promClient)
+
+	checkRequest := func(t *testing.T, dryRun *tools.DryRunOutput, path string) {
+		t.Helper()
+		if dryRun == nil {
+			t.Fatal("expected dry run output, got nil")
+		}
+		if dryRun.Error != "" {
+			t.Errorf("unexpected dry run error: %s", dryRun.Error)
+		}
+		if len(dryRun.Requests) != 1 {
+			t.Fatalf("expected 1 recorded request, got %d", len(dryRun.Requests))
+		}
+		req := dryRun.Requests[0]
+		if req.URL != "https://prometheus.invalid:9091"+path {
+			t.Errorf("url = %s, want %s", req.URL, path)
+		}
+		if req.Headers["Authorization"] != "Bearer <redacted>" {
+			t.Errorf("authorization header = %q, want redacted bearer token", req.Headers["Authorization"])
+		}
+		if !strings.Contains(req.Body, "query=vector%281%29") {
+			t.Errorf("body = %q, expected it to contain the query", req.Body)
+		}
+	}
+
+	t.Run("instant query", func(t *testing.T) {
+		handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "vector(1)", "dry_run": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		checkRequest(t, output.DryRun, "/api/v1/query")
+	})
+
+	t.Run("range query", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "vector(1)", "step": "1m", "dry_run": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		checkRequest(t, output.DryRun, "/api/v1/query_range")
+	})
+}
+
+func TestQueryHandlers_ThanosParams(t *testing.T) {
+	rt, err := auth.BuildRoundTripper(context.Background(), &rest.Config{}, auth.AuthModeKubeConfig, false, false)
+	if err != nil {
+		t.Fatalf("failed to create round tripper: %v", err)
+	}
+	promClient, err := prometheus.NewPrometheusLoader(promapi.Config{Address: "http://thanos.invalid:9090", RoundTripper: rt})
+	if err != nil {
+		t.Fatalf("failed to create Prometheus client: %v", err)
+	}
+	promClient.WithGuardrails(nil)
+	ctx := withMockClient(t.Context(), promClient)
+
+	recordedURL := func(t *testing.T, dryRun *tools.DryRunOutput) *url.URL {
+		t.Helper()
+		if dryRun == nil || len(dryRun.Requests) != 1 {
+			t.Fatalf("expected 1 recorded request, got %+v", dryRun)
+		}
+		u, err := url.Parse(dryRun.Requests[0].URL)
+		if err != nil {
+			t.Fatalf("invalid recorded URL: %v", err)
+		}
+		if !strings.HasPrefix(u.Path, "/api/v1/query") {
+			t.Fatalf("recorded request = %s, want a query request", u)
+		}
+		return u
+	}
+
+	t.Run("instant query", func(t *testing.T) {
+		handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "vector(1)", "dedup": false, "max_resolution": "raw", "dry_run": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		query := recordedURL(t, output.DryRun).Query()
+		if query.Get("dedup") != "false" || query.Get("max_source_resolution") != "0s" {
+			t.Errorf("query parameters = %v, want dedup=false and max_source_resolution=0s", query)
+		}
+	})
+
+	t.Run("range query", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "vector(1)", "step": "1h", "duration": "30d", "max_resolution": "1h", "dry_run": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		query := recordedURL(t, output.DryRun).Query()
+		if query.Get("max_source_resolution") != "1h" || query.Has("dedup") {
+			t.Errorf("query parameters = %v, want max_source_resolution=1h without dedup", query)
+		}
+	})
+
+	t.Run("not sent unless requested", func(t *testing.T) {
+		handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "vector(1)", "dry_run": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if u := recordedURL(t, output.DryRun); u.RawQuery != "" {
+			t.Errorf("unexpected query parameters %q", u.RawQuery)
+		}
+	})
+
+	t.Run("invalid max_resolution", func(t *testing.T) {
+		handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": "vector(1)", "max_resolution": "hourly"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildInstantQueryInput(params)); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestQueryHandlers_BackendWarnings(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data string
+		switch r.URL.Path {
+		case "/api/v1/label/__name__/values":
+			data = `["up"]`
+		case "/api/v1/query":
+			data = `{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[1704067200,"1"]}]}`
+		case "/api/v1/query_range":
+			data = `{"resultType":"matrix","result":[{"metric":{"job":"api"},"values":[[1704067200,"1"],[1704067260,"1"]]}]}`
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":%s,"warnings":["partial response: store unavailable"]}`, data)
+	}))
+	defer backend.Close()
+
+	promClient, err := prometheus.NewPrometheusLoader(promapi.Config{Address: backend.URL})
+	if err != nil {
+		t.Fatalf("failed to create Prometheus client: %v", err)
+	}
+	promClient.WithGuardrails(nil)
+	ctx := withMockClient(t.Context(), promClient)
+
+	const want = "partial response: store unavailable"
+	t.Run("instant query", func(t *testing.T) {
+		handler := ExecuteInstantQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": `up{job="api"}`}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildInstantQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(output.Warnings, want) {
+			t.Errorf("expected warnings to contain %q, got %v", want, output.Warnings)
+		}
+	})
+
+	t.Run("range query", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": `up{job="api"}`, "step": "1m"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(output.Warnings, want) {
+			t.Errorf("expected warnings to contain %q, got %v", want, output.Warnings)
+		}
+	})
+
+	t.Run("range query raw response", func(t *testing.T) {
+		handler := ExecuteRangeQueryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"query": `up{job="api"}`, "step": "1m", "raw_response": "prometheus"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRangeQueryInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(output.Warnings, []string{want}) {
+			t.Errorf("warnings = %v, want [%q]", output.Warnings, want)
+		}
+	})
+}
+
+func TestGetLabelNamesHandler_ValueCounts(t *testing.T) {
+	labelNames := func(ctx context.Context, metricName string, start, end time.Time) ([]string, error) {
+		return []string{"__name__", "namespace", "pod"}, nil
+	}
+	params := map[string]any{"metric": "kube_pod_info"}
+
+	t.Run("counts from TSDB stats", func(t *testing.T) {
+		var calls int
+		mockClient := &MockedLoader{
+			GetLabelNamesFunc: labelNames,
+			GetLabelValueCountsFunc: func(ctx context.Context) (map[string]uint64, error) {
+				calls++
+				return map[string]uint64{"__name__": 900, "namespace": 40, "pod": 1200, "le": 20}, nil
+			},
+			GetLabelValuesFunc: func(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
+				t.Errorf("unexpected label values request for %s", label)
+				return nil, nil
+			},
+		}
+		ctx := withMockClient(t.Context(), mockClient)
+		req := newMockRequest(params)
+		_, output, err := GetLabelNamesHandler(ObsMCPOptions{Metrics: &tools.Config{}})(ctx, &req, tools.BuildLabelNamesInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]uint64{"__name__": 900, "namespace": 40, "pod": 1200}
+		if !maps.Equal(output.ValueCounts, want) || calls != 1 {
+			t.Errorf("value counts = %v from %d requests, want %v from 1", output.ValueCounts, calls, want)
+		}
+	})
+
+	t.Run("TSDB stats unavailable", func(t *testing.T) {
+		ctx := withMockClient(t.Context(), &MockedLoader{GetLabelNamesFunc: labelNames})
+		req := newMockRequest(params)
+		_, output, err := GetLabelNamesHandler(ObsMCPOptions{Metrics: &tools.Config{}})(ctx, &req, tools.BuildLabelNamesInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Labels) != 3 || output.ValueCounts != nil {
+			t.Errorf("expected the label names without counts, got %+v", output)
+		}
+	})
+}
+
+func TestGetLabelValuesHandler_Limit(t *testing.T) {
+	values := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name           string
+		maxValues      *int
+		limit          int
+		nativeLimit    bool
+		wantFetchLimit uint64
+		wantValues     []string
+		wantTruncated  bool
+		wantTotal      int
+		wantErr        string
+	}{
+		{
+			name:           "limit applied by the backend",
+			limit:          2,
+			nativeLimit:    true,
+			wantFetchLimit: 3,
+			wantValues:     []string{"a", "b"},
+			wantTruncated:  true,
+		},
+		{
+			name:           "limit ignored by the backend is applied client-side",
+			limit:          2,
+			wantFetchLimit: 3,
+			wantValues:     []string{"a", "b"},
+			wantTruncated:  true,
+			wantTotal:      5,
+		},
+		{
+			name:           "server cap is the default limit",
+			maxValues:      new(3),
+			wantFetchLimit: 4,
+			wantValues:     []string{"a", "b", "c"},
+			wantTruncated:  true,
+			wantTotal:      5,
+		},
+		{
+			name:      "requested limit above the server cap is rejected",
+			maxValues: new(3),
+			limit:     10,
+			wantErr:   "limit 10 exceeds the server-side maximum of 3",
+		},
+		{
+			name:           "values within the limit are not truncated",
+			limit:          5,
+			nativeLimit:    true,
+			wantFetchLimit: 6,
+			wantValues:     values,
+			wantTotal:      5,
+		},
+		{
+			name:       "zero cap disables the limit",
+			maxValues:  new(0),
+			wantValues: values,
+			wantTotal:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFetchLimit uint64
+			mockLoader := &MockedLoader{
+				GetLabelValuesFunc: func(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
+					gotFetchLimit = limit
+					if tt.nativeLimit && limit > 0 && int(limit) < len(values) {
+						return values[:limit], nil
+					}
+					return values, nil
+				},
+			}
+
+			ctx := withMockClient(context.Background(), mockLoader)
+			handler := GetLabelValuesHandler(ObsMCPOptions{Metrics: &tools.Config{MaxLabelValues: tt.maxValues}})
+			params := map[string]any{"label": "job"}
+			if tt.limit > 0 {
+				params["limit"] = float64(tt.limit)
+			}
+			req := newMockRequest(params)
+
+			_, output, err := handler(ctx, &req, tools.BuildLabelValuesInput(params))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotFetchLimit != tt.wantFetchLimit {
+				t.Errorf("backend limit = %d, want %d", gotFetchLimit, tt.wantFetchLimit)
+			}
+			if !reflect.DeepEqual(output.Values, tt.wantValues) {
+				t.Errorf("values = %v, want %v", output.Values, tt.wantValues)
+			}
+			if output.Truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", output.Truncated, tt.wantTruncated)
+			}
+			if output.TotalCount != tt.wantTotal {
+				t.Errorf("totalCount = %d, want %d", output.TotalCount, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestGetLabelValuesHandler_ByFrequency(t *testing.T) {
+	var gotQuery string
+	mockLoader := &MockedLoader{
+		GetLabelValuesFunc: func(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
+			return []string{"api", "db", "web"}, nil
+		},
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			gotQuery = query
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"job": "api"}, Value: 3},
+					{Metric: model.Metric{"job": "db"}, Value: 1},
+					{Metric: model.Metric{"job": "web"}, Value: 12},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockLoader)
+	handler := GetLabelValuesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{"label": "job", "metric": "up", "start": "NOW-1h", "by_frequency": true, "limit": float64(2)}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildLabelValuesInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `count by (job) (last_over_time({__name__="up",job!=""}[1h]))`; gotQuery != want {
+		t.Errorf("query = %s, want %s", gotQuery, want)
+	}
+	wantFrequencies := []tools.LabelValueFrequency{{Value: "web", SeriesCount: 12}, {Value: "api", SeriesCount: 3}}
+	if !reflect.DeepEqual(output.Frequencies, wantFrequencies) {
+		t.Errorf("frequencies = %v, want %v", output.Frequencies, wantFrequencies)
+	}
+	if !reflect.DeepEqual(output.Values, []string{"web", "api"}) || !output.Truncated || output.TotalCount != 3 {
+		t.Errorf("values = %v (truncated %v, total %d), want [web api] truncated from 3", output.Values, output.Truncated, output.TotalCount)
+	}
+}
+
+func TestGetLabelValuesHandler_ByFrequencyTooManyValues(t *testing.T) {
+	mockLoader := &MockedLoader{
+		GetLabelValuesFunc: func(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
+			values := make([]string, limit)
+			for i := range values {
+				values[i] = fmt.Sprintf("pod-%d", i)
+			}
+			return values, nil
+		},
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			t.Error("the count query must not run for labels with too many values")
+			return nil, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockLoader)
+	handler := GetLabelValuesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{"label": "pod", "metric": "kube_pod_info", "by_frequency": true}
+	req := newMockRequest(params)
+	if _, _, err := handler(ctx, &req, tools.BuildLabelValuesInput(params)); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestGetLabelValuesHandler_ByFrequencyRequiresMetric(t *testing.T) {
+	mockLoader := &MockedLoader{
+		GetLabelValuesFunc: func(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
+			t.Error("label values must not be fetched without a metric")
+			return nil, nil
+		},
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			t.Error("the count query must not run without a metric")
+			return nil, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockLoader)
+	handler := GetLabelValuesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{"label": "job", "by_frequency": true}
+	req := newMockRequest(params)
+	_, _, err := handler(ctx, &req, tools.BuildLabelValuesInput(params))
+	if err == nil || !strings.Contains(err.Error(), "metric parameter is required with by_frequency") {
+		t.Errorf("expected an error requiring a metric, got %v", err)
+	}
+}
+
+func TestGetLabelsOverviewHandler(t *testing.T) {
+	values := map[string][]string{
+		"namespace": {"default", "monitoring"},
+		"pod":       {"a", "b", "c", "d"},
+	}
+	var mu sync.Mutex
+	fetchLimits := make(map[string]uint64)
+	mockLoader := &MockedLoader{
+		GetLabelValuesFunc: func(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
+			if metricName != "kube_pod_info" {
+				t.Errorf("metric = %q, want kube_pod_info", metricName)
+			}
+			mu.Lock()
+			fetchLimits[label] = limit
+			mu.Unlock()
+			if label == "broken" {
+				return nil, errors.New("backend unavailable")
+			}
+			return values[label], nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockLoader)
+
+	t.Run("values of each label", func(t *testing.T) {
+		handler := GetLabelsOverviewHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		params := map[string]any{"metric": "kube_pod_info", "labels": []any{"namespace", "pod", "namespace"}, "limit": float64(3)}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildLabelsOverviewInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []tools.LabelOverview{
+			{Label: "namespace", Values: []string{"default", "monitoring"}},
+			{Label: "pod", Values: []string{"a", "b", "c"}, Truncated: true},
+		}
+		if !reflect.DeepEqual(output.Labels, want) {
+			t.Errorf("labels = %+v, want %+v", output.Labels, want)
+		}
+		if fetchLimits["pod"] != 4 {
+			t.Errorf("backend limit = %d, want 4", fetchLimits["pod"])
+		}
+	})
+
+	t.Run("limit capped by the server", func(t *testing.T) {
+		handler := GetLabelsOverviewHandler(ObsMCPOptions{Metrics: &tools.Config{MaxLabelValues: new(2)}})
+		params := map[string]any{"metric": "kube_pod_info", "labels": []any{"pod"}}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildLabelsOverviewInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Labels) != 1 || len(output.Labels[0].Values) != 2 || !output.Labels[0].Truncated {
+			t.Errorf("labels = %+v, want 2 truncated pod values", output.Labels)
+		}
+	})
+
+	errorTests := []struct {
+		name    string
+		params  map[string]any
+		wantErr string
+	}{
+		{name: "missing metric", params: map[string]any{"labels": []any{"pod"}}, wantErr: "metric parameter is required"},
+		{name: "missing labels", params: map[string]any{"metric": "kube_pod_info"}, wantErr: "labels parameter is required"},
+		{name: "invalid label", params: map[string]any{"metric": "kube_pod_info", "labels": []any{"pod name"}}, wantErr: "invalid label name"},
+		{
+			name:    "too many labels",
+			params:  map[string]any{"metric": "kube_pod_info", "labels": []any{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}},
+			wantErr: "at most 10 are allowed",
+		},
+		{name: "backend error", params: map[string]any{"metric": "kube_pod_info", "labels": []any{"pod", "broken"}}, wantErr: `failed to get values of label "broken"`},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := GetLabelsOverviewHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+			req := newMockRequest(tt.params)
+			_, _, err := handler(ctx, &req, tools.BuildLabelsOverviewInput(tt.params))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveQueryResultHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"job": "api"}, Value: 1, Timestamp: 1000},
+					{Metric: model.Metric{"job": "db"}, Value: 0, Timestamp: 1000},
+				},
+			}, nil
+		},
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error) {
+			if step != time.Minute {
+				t.Errorf("expected step 1m, got %v", step)
+			}
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{Metric: model.Metric{"job": "api"}, Values: []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 61000, Value: 1}}},
+				},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+
+	dir := t.TempDir()
+	handler := SaveQueryResultHandler(ObsMCPOptions{Metrics: &tools.Config{AllowFileOutput: true, FileOutputDir: dir}})
+
+	t.Run("instant query", func(t *testing.T) {
+		params := map[string]any{"query": "up", "filename": "../up", "format": "csv"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildSaveQueryResultInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output.Path != filepath.Join(dir, "up.csv") {
+			t.Errorf("path = %s, want %s", output.Path, filepath.Join(dir, "up.csv"))
+		}
+		if output.ResultType != "vector" || output.SeriesCount != 2 || output.SampleCount != 2 {
+			t.Errorf("unexpected summary: %+v", output)
+		}
+		data, err := os.ReadFile(output.Path)
+		if err != nil {
+			t.Fatalf("failed to read saved file: %v", err)
+		}
+		if int64(len(data)) != output.SizeBytes {
+			t.Errorf("sizeBytes = %d, file has %d bytes", output.SizeBytes, len(data))
+		}
+	})
+
+	t.Run("range query", func(t *testing.T) {
+		params := map[string]any{"query": "up", "step": "1m"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildSaveQueryResultInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if filepath.Dir(output.Path) != dir || output.Format != "json" {
+			t.Errorf("unexpected output file %s (%s)", output.Path, output.Format)
+		}
+		if output.ResultType != "matrix" || output.SeriesCount != 1 || output.SampleCount != 2 {
+			t.Errorf("unexpected summary: %+v", output)
+		}
+	})
+
+	t.Run("file output disabled", func(t *testing.T) {
+		handler := SaveQueryResultHandler(ObsMCPOptions{Metrics: &tools.Config{FileOutputDir: dir}})
+		params := map[string]any{"query": "up"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildSaveQueryResultInput(params)); err == nil {
+			t.Error("expected error when file output is disabled, got nil")
+		}
+	})
+}
+
+func TestGetAlertsHandler_AllAlerts(t *testing.T) {
+	activeState := "active"
+	now := strfmt.DateTime(time.Now())
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			// Verify no filters are applied
+			if active != nil || silenced != nil || inhibited != nil || unprocessed != nil {
+				t.Error("expected no boolean filters")
+			}
+			if len(filter) != 0 {
+				t.Errorf("expected no filters, got %v", filter)
+			}
+			if receiver != "" {
+				t.Errorf("expected no receiver, got %s", receiver)
+			}
+
+			return models.GettableAlerts{
+				&models.GettableAlert{
+					Alert: models.Alert{
+						Labels: models.LabelSet{
+							"alertname": "HighCPU",
+							"severity":  "warning",
+						},
+					},
+					Annotations: models.LabelSet{
+						"description": "CPU usage is high",
+					},
+					StartsAt: &now,
+					EndsAt:   &now,
+					Status: &models.AlertStatus{
+						State:       &activeState,
+						SilencedBy:  []string{},
+						InhibitedBy: []string{},
+					},
+				},
+			}, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+
+	input := tools.BuildAlertsInput(map[string]any{})
+	_, _, err := handler(ctx, &req, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// manyAlerts returns n active alerts, named Alert0 to Alert<n-1>, in the same namespace.
+func manyAlerts(n int) models.GettableAlerts {
+	state := "active"
+	now := strfmt.DateTime(time.Now())
+	alerts := make(models.GettableAlerts, n)
+	for i := range alerts {
+		alerts[i] = &models.GettableAlert{
+			Alert:    models.Alert{Labels: models.LabelSet{"alertname": fmt.Sprintf("Alert%d", i), "severity": "warning", "namespace": "web"}},
+			StartsAt: &now,
+			Status:   &models.AlertStatus{State: &state},
+		}
+	}
+	return alerts
+}
+
+func TestGetAlertsHandler_ManyAlerts(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			return manyAlerts(150), nil
+		},
+	}
+	ctx := withMockAlertmanagerClient(t.Context(), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("summarized by default", func(t *testing.T) {
+		params := map[string]any{}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildAlertsInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Alerts) != 0 || output.Summary == nil || output.Summary.Total != 150 {
+			t.Fatalf("expected a summary of 150 alerts and no list, got %d alerts and summary %+v", len(output.Alerts), output.Summary)
+		}
+		if !strings.Contains(output.Note, "set 'full'") {
+			t.Errorf("expected a note explaining how to list the alerts, got %q", output.Note)
+		}
+	})
+
+	t.Run("listed with full", func(t *testing.T) {
+		params := map[string]any{"full": true}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildAlertsInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Alerts) != 150 || output.Summary != nil {
+			t.Errorf("expected 150 alerts and no summary, got %d alerts and summary %+v", len(output.Alerts), output.Summary)
+		}
+	})
+}
+
+func TestGetAlertsHandler_AlertProjection(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			alerts := manyAlerts(1)
+			alerts[0].Annotations = models.LabelSet{"summary": "Alert0 is firing", "runbook_url": "https://runbooks.example.com/Alert0"}
+			return alerts, nil
+		},
+	}
+	ctx := withMockAlertmanagerClient(t.Context(), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{
+		AlertLabels:      []string{"alertname", "severity"},
+		AlertAnnotations: []string{"summary"},
+	}})
+
+	t.Run("configured allowlists", func(t *testing.T) {
+		params := map[string]any{}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildAlertsInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		alert := output.Alerts[0]
+		if want := map[string]string{"alertname": "Alert0", "severity": "warning"}; !maps.Equal(alert.Labels, want) {
+			t.Errorf("labels = %v, want %v", alert.Labels, want)
+		}
+		if want := map[string]string{"summary": "Alert0 is firing"}; !maps.Equal(alert.Annotations, want) {
+			t.Errorf("annotations = %v, want %v", alert.Annotations, want)
+		}
+	})
+
+	t.Run("overridden per call", func(t *testing.T) {
+		params := map[string]any{"labels": []any{"namespace"}, "annotations": []any{"*"}}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildAlertsInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		alert := output.Alerts[0]
+		if want := map[string]string{"namespace": "web"}; !maps.Equal(alert.Labels, want) {
+			t.Errorf("labels = %v, want %v", alert.Labels, want)
+		}
+		if len(alert.Annotations) != 2 {
+			t.Errorf("expected all annotations, got %v", alert.Annotations)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		params := map[string]any{"labels": []any{"not a label"}}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildAlertsInput(params)); err == nil {
+			t.Fatal("expected error for an invalid label name, got nil")
+		}
+	})
+}
+
+func TestGetAlertsHandler_WithActiveFilter(t *testing.T) {
+	active := true
+	activeState := "active"
+	now := strfmt.DateTime(time.Now())
+
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, activeParam, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			if activeParam == nil || !*activeParam {
+				t.Error("expected active parameter to be true")
+			}
+
+			return models.GettableAlerts{
+				&models.GettableAlert{
+					Alert: models.Alert{
+						Labels: models.LabelSet{
+							"alertname": "HighCPU",
+						},
+					},
+					Annotations: models.LabelSet{},
+					StartsAt:    &now,
+					EndsAt:      &now,
+					Status: &models.AlertStatus{
+						State:       &activeState,
+						SilencedBy:  []string{},
+						InhibitedBy: []string{},
+					},
+				},
+			}, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	paramsMap := map[string]any{
+		"active": active,
+	}
+	req := newMockRequest(paramsMap)
+
+	input := tools.BuildAlertsInput(paramsMap)
+	_, _, err := handler(ctx, &req, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetAlertsHandler_WithFilter(t *testing.T) {
 	activeState := "active"
 	now := strfmt.DateTime(time.Now())
+
 	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
-			// Verify no filters are applied
-			if active != nil || silenced != nil || inhibited != nil || unprocessed != nil {
-				t.Error("expected no boolean filters")
-			}
-			if len(filter) != 0 {
-				t.Errorf("expected no filters, got %v", filter)
-			}
-			if receiver != "" {
-				t.Errorf("expected no receiver, got %s", receiver)
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filterParam []string, receiver string) (models.GettableAlerts, error) {
+			if len(filterParam) != 1 || filterParam[0] != "alertname=HighCPU" {
+				t.Errorf("expected filter 'alertname=HighCPU', got %v", filterParam)
 			}
 
 			return models.GettableAlerts{
@@ -700,14 +3699,11 @@ func TestGetAlertsHandler_AllAlerts(t *testing.T) {
 					Alert: models.Alert{
 						Labels: models.LabelSet{
 							"alertname": "HighCPU",
-							"severity":  "warning",
 						},
 					},
-					Annotations: models.LabelSet{
-						"description": "CPU usage is high",
-					},
-					StartsAt: &now,
-					EndsAt:   &now,
+					Annotations: models.LabelSet{},
+					StartsAt:    &now,
+					EndsAt:      &now,
 					Status: &models.AlertStatus{
 						State:       &activeState,
 						SilencedBy:  []string{},
@@ -720,24 +3716,26 @@ func TestGetAlertsHandler_AllAlerts(t *testing.T) {
 
 	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
 	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-	req := newMockRequest(map[string]any{})
+	paramsMap := map[string]any{
+		"filter": "alertname=HighCPU",
+	}
+	req := newMockRequest(paramsMap)
 
-	input := tools.BuildAlertsInput(map[string]any{})
+	input := tools.BuildAlertsInput(paramsMap)
 	_, _, err := handler(ctx, &req, input)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
-func TestGetAlertsHandler_WithActiveFilter(t *testing.T) {
-	active := true
+func TestGetAlertsHandler_WithReceiver(t *testing.T) {
 	activeState := "active"
 	now := strfmt.DateTime(time.Now())
 
 	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, activeParam, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
-			if activeParam == nil || !*activeParam {
-				t.Error("expected active parameter to be true")
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiverParam string) (models.GettableAlerts, error) {
+			if receiverParam != "team-notifications" {
+				t.Errorf("expected receiver 'team-notifications', got %s", receiverParam)
 			}
 
 			return models.GettableAlerts{
@@ -763,10 +3761,9 @@ func TestGetAlertsHandler_WithActiveFilter(t *testing.T) {
 	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
 	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 	paramsMap := map[string]any{
-		"active": active,
+		"receiver": "team-notifications",
 	}
 	req := newMockRequest(paramsMap)
-
 	input := tools.BuildAlertsInput(paramsMap)
 	_, _, err := handler(ctx, &req, input)
 	if err != nil {
@@ -774,21 +3771,165 @@ func TestGetAlertsHandler_WithActiveFilter(t *testing.T) {
 	}
 }
 
-func TestGetAlertsHandler_WithFilter(t *testing.T) {
-	activeState := "active"
+func TestGetSilencesHandler_AllSilences(t *testing.T) {
+	silenceID := "test-silence-id"
+	silenceState := "active"
 	now := strfmt.DateTime(time.Now())
 
 	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filterParam []string, receiver string) (models.GettableAlerts, error) {
+		GetSilencesFunc: func(ctx context.Context, filter []string) (models.GettableSilences, error) {
+			if len(filter) != 0 {
+				t.Errorf("expected no filters, got %v", filter)
+			}
+
+			return models.GettableSilences{
+				&models.GettableSilence{
+					ID: &silenceID,
+					Status: &models.SilenceStatus{
+						State: &silenceState,
+					},
+					Silence: models.Silence{
+						Matchers: models.Matchers{
+							&models.Matcher{
+								Name:    new("alertname"),
+								Value:   new("HighCPU"),
+								IsRegex: new(false),
+								IsEqual: new(true),
+							},
+						},
+						StartsAt:  &now,
+						EndsAt:    &now,
+						CreatedBy: new("admin"),
+						Comment:   new("Maintenance window"),
+					},
+				},
+			}, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := GetSilencesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+
+	input := tools.BuildSilencesInput(map[string]any{})
+	_, _, err := handler(ctx, &req, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetSilencesHandler_WithFilter(t *testing.T) {
+	silenceID := "test-silence-id"
+	silenceState := "active"
+	now := strfmt.DateTime(time.Now())
+
+	mockClient := &MockedAlertmanagerLoader{
+		GetSilencesFunc: func(ctx context.Context, filterParam []string) (models.GettableSilences, error) {
 			if len(filterParam) != 1 || filterParam[0] != "alertname=HighCPU" {
 				t.Errorf("expected filter 'alertname=HighCPU', got %v", filterParam)
 			}
 
+			return models.GettableSilences{
+				&models.GettableSilence{
+					ID: &silenceID,
+					Status: &models.SilenceStatus{
+						State: &silenceState,
+					},
+					Silence: models.Silence{
+						Matchers: models.Matchers{
+							&models.Matcher{
+								Name:    new("alertname"),
+								Value:   new("HighCPU"),
+								IsRegex: new(false),
+								IsEqual: new(true),
+							},
+						},
+						StartsAt:  &now,
+						EndsAt:    &now,
+						CreatedBy: new("admin"),
+						Comment:   new("Planned maintenance"),
+					},
+				},
+			}, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := GetSilencesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	paramsMap := map[string]any{
+		"filter": "alertname=HighCPU",
+	}
+	req := newMockRequest(paramsMap)
+
+	input := tools.BuildSilencesInput(paramsMap)
+	_, _, err := handler(ctx, &req, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetSilencesHandler_EmptyResult(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetSilencesFunc: func(ctx context.Context, filter []string) (models.GettableSilences, error) {
+			return models.GettableSilences{}, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := GetSilencesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+
+	input := tools.BuildSilencesInput(map[string]any{})
+	_, _, err := handler(ctx, &req, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetAlertsHandler_ClientError(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+
+	input := tools.BuildAlertsInput(map[string]any{})
+	_, _, err := handler(ctx, &req, input)
+	if err == nil {
+		t.Fatal("expected error result, got success")
+	}
+	if err.Error() != "failed to get alerts: connection refused" {
+		t.Errorf("expected error message 'failed to get alerts: connection refused', got %q", err.Error())
+	}
+}
+
+func TestGetAlertsHandler_WithMultipleFilters(t *testing.T) {
+	activeState := "active"
+	now := strfmt.DateTime(time.Now())
+
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filterParam []string, receiver string) (models.GettableAlerts, error) {
+			// Verify multiple filters are passed correctly
+			expectedFilters := []string{"alertname=HighCPU", "severity=critical"}
+			if len(filterParam) != len(expectedFilters) {
+				t.Errorf("expected %d filters, got %d: %v", len(expectedFilters), len(filterParam), filterParam)
+			}
+			for i, expected := range expectedFilters {
+				if i < len(filterParam) && filterParam[i] != expected {
+					t.Errorf("expected filter[%d] to be %q, got %q", i, expected, filterParam[i])
+				}
+			}
+
 			return models.GettableAlerts{
 				&models.GettableAlert{
 					Alert: models.Alert{
 						Labels: models.LabelSet{
 							"alertname": "HighCPU",
+							"severity":  "critical",
 						},
 					},
 					Annotations: models.LabelSet{},
@@ -807,7 +3948,7 @@ func TestGetAlertsHandler_WithFilter(t *testing.T) {
 	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
 	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 	paramsMap := map[string]any{
-		"filter": "alertname=HighCPU",
+		"filter": "alertname=HighCPU, severity=critical",
 	}
 	req := newMockRequest(paramsMap)
 
@@ -818,14 +3959,126 @@ func TestGetAlertsHandler_WithFilter(t *testing.T) {
 	}
 }
 
-func TestGetAlertsHandler_WithReceiver(t *testing.T) {
+func TestGetAlertsHandler_AnyOf(t *testing.T) {
+	activeState := "active"
+	newAlert := func(fingerprint, alertname string) *models.GettableAlert {
+		return &models.GettableAlert{
+			Alert:       models.Alert{Labels: models.LabelSet{"alertname": alertname, "namespace": "x"}},
+			Fingerprint: new(fingerprint),
+			Status:      &models.AlertStatus{State: &activeState},
+		}
+	}
+	alerts := models.GettableAlerts{newAlert("1", "HighCPU"), newAlert("2", "HighMemory"), newAlert("3", "DiskFull")}
+
+	var calls [][]string
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			calls = append(calls, filter)
+			// Match the alertname matchers; the namespace matcher matches every alert.
+			var matched models.GettableAlerts
+			for _, alert := range alerts {
+				if slices.Contains(filter, "alertname="+alert.Labels["alertname"]) || (slices.Contains(filter, "alertname=~High.*") && strings.HasPrefix(alert.Labels["alertname"], "High")) {
+					matched = append(matched, alert)
+				}
+			}
+			return matched, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(t.Context(), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	params := map[string]any{
+		"filter": "namespace=x",
+		"any_of": []any{"alertname=HighCPU", "alertname=~High.*"},
+	}
+	req := newMockRequest(params)
+
+	_, output, err := handler(ctx, &req, tools.BuildAlertsInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantCalls := [][]string{{"namespace=x", "alertname=HighCPU"}, {"namespace=x", "alertname=~High.*"}}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("Alertmanager calls = %v, want %v", calls, wantCalls)
+	}
+	var names []string
+	for _, alert := range output.Alerts {
+		names = append(names, alert.Labels["alertname"])
+	}
+	if want := []string{"HighCPU", "HighMemory"}; !slices.Equal(names, want) {
+		t.Errorf("alerts = %v, want %v without duplicates", names, want)
+	}
+}
+
+func TestGetAlertsHandler_MinActiveDuration(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	activeState := "active"
+	newAlert := func(alertname string, startsAt *strfmt.DateTime) *models.GettableAlert {
+		return &models.GettableAlert{
+			Alert:    models.Alert{Labels: models.LabelSet{"alertname": alertname}},
+			StartsAt: startsAt,
+			Status:   &models.AlertStatus{State: &activeState},
+		}
+	}
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			return models.GettableAlerts{
+				newAlert("Persistent", new(strfmt.DateTime(now.Add(-3*time.Hour)))),
+				newAlert("Flapping", new(strfmt.DateTime(now.Add(-5*time.Minute)))),
+				newAlert("NoStart", nil),
+			}, nil
+		},
+	}
+	ctx := withMockAlertmanagerClient(prometheus.ContextWithNow(t.Context(), now), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{"min_active_duration": "1h"}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildAlertsInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Alerts) != 1 || output.Alerts[0].Labels["alertname"] != "Persistent" {
+		t.Errorf("expected only the alert active for 3h, got %+v", output.Alerts)
+	}
+
+	params = map[string]any{"min_active_duration": "an hour"}
+	req = newMockRequest(params)
+	if _, _, err := handler(ctx, &req, tools.BuildAlertsInput(params)); err == nil || !strings.Contains(err.Error(), "invalid min_active_duration") {
+		t.Errorf("expected an invalid min_active_duration error, got %v", err)
+	}
+}
+
+func TestGetAlertsHandler_AnyOfTooManyGroups(t *testing.T) {
+	ctx := withMockAlertmanagerClient(t.Context(), &MockedAlertmanagerLoader{})
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	groups := make([]any, 11)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("alertname=Alert%d", i)
+	}
+	params := map[string]any{"any_of": groups}
+	req := newMockRequest(params)
+
+	if _, _, err := handler(ctx, &req, tools.BuildAlertsInput(params)); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestGetAlertsHandler_WithMultipleFiltersNoSpaces(t *testing.T) {
 	activeState := "active"
 	now := strfmt.DateTime(time.Now())
 
 	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiverParam string) (models.GettableAlerts, error) {
-			if receiverParam != "team-notifications" {
-				t.Errorf("expected receiver 'team-notifications', got %s", receiverParam)
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filterParam []string, receiver string) (models.GettableAlerts, error) {
+			// Verify multiple filters without spaces are parsed and trimmed correctly
+			expectedFilters := []string{"alertname=HighCPU", "severity=warning", "job=api"}
+			if len(filterParam) != len(expectedFilters) {
+				t.Errorf("expected %d filters, got %d: %v", len(expectedFilters), len(filterParam), filterParam)
+			}
+			for i, expected := range expectedFilters {
+				if i < len(filterParam) && filterParam[i] != expected {
+					t.Errorf("expected filter[%d] to be %q, got %q", i, expected, filterParam[i])
+				}
 			}
 
 			return models.GettableAlerts{
@@ -851,9 +4104,10 @@ func TestGetAlertsHandler_WithReceiver(t *testing.T) {
 	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
 	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 	paramsMap := map[string]any{
-		"receiver": "team-notifications",
+		"filter": "alertname=HighCPU,severity=warning,job=api",
 	}
 	req := newMockRequest(paramsMap)
+
 	input := tools.BuildAlertsInput(paramsMap)
 	_, _, err := handler(ctx, &req, input)
 	if err != nil {
@@ -861,280 +4115,1077 @@ func TestGetAlertsHandler_WithReceiver(t *testing.T) {
 	}
 }
 
-func TestGetSilencesHandler_AllSilences(t *testing.T) {
-	silenceID := "test-silence-id"
-	silenceState := "active"
-	now := strfmt.DateTime(time.Now())
+func TestGetAlertsHandler_EmptyResult(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			return models.GettableAlerts{}, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+
+	input := tools.BuildAlertsInput(map[string]any{})
+	_, _, err := handler(ctx, &req, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
 
+func TestSummarizeAlertsHandler(t *testing.T) {
 	mockClient := &MockedAlertmanagerLoader{
-		GetSilencesFunc: func(ctx context.Context, filter []string) (models.GettableSilences, error) {
-			if len(filter) != 0 {
-				t.Errorf("expected no filters, got %v", filter)
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			if active == nil || !*active {
+				t.Error("expected the active filter to be passed through")
 			}
+			if !slices.Equal(filter, []string{"namespace=web"}) {
+				t.Errorf("filter = %v, want [namespace=web]", filter)
+			}
+			return models.GettableAlerts{
+				{Alert: models.Alert{Labels: models.LabelSet{"alertname": "HighCPU", "severity": "warning", "namespace": "web"}}},
+				{Alert: models.Alert{Labels: models.LabelSet{"alertname": "HighCPU", "severity": "warning", "namespace": "web"}}},
+				{Alert: models.Alert{Labels: models.LabelSet{"alertname": "PodDown", "severity": "critical", "namespace": "web"}}},
+			}, nil
+		},
+	}
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	handler := SummarizeAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 
-			return models.GettableSilences{
-				&models.GettableSilence{
-					ID: &silenceID,
-					Status: &models.SilenceStatus{
-						State: &silenceState,
+	params := map[string]any{"active": true, "filter": "namespace=web", "top": float64(1)}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildSummarizeAlertsInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Total != 3 {
+		t.Errorf("total = %d, want 3", output.Total)
+	}
+	if len(output.TopAlerts) != 1 || output.TopAlerts[0].Alertname != "PodDown" {
+		t.Errorf("topAlerts = %+v, want only PodDown", output.TopAlerts)
+	}
+
+	params = map[string]any{"top": float64(-1)}
+	req = newMockRequest(params)
+	if _, _, err := handler(ctx, &req, tools.BuildSummarizeAlertsInput(params)); err == nil {
+		t.Error("expected error for negative top, got nil")
+	}
+}
+
+func TestGetAlertHistoryHandler(t *testing.T) {
+	until := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	since := until.Add(-time.Hour)
+	step := 30 * time.Second
+	at := func(n int) model.SamplePair {
+		return model.SamplePair{Timestamp: model.TimeFromUnixNano(since.Add(time.Duration(n) * step).UnixNano()), Value: 1}
+	}
+
+	var gotQuery string
+	mockClient := &MockedLoader{
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, start, end time.Time, s time.Duration) (map[string]any, error) {
+			gotQuery = query
+			if !start.Equal(since) || !end.Equal(until) || s != step {
+				t.Errorf("unexpected range %v - %v step %v", start, end, s)
+			}
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{
+						Metric: model.Metric{"__name__": "ALERTS", "alertname": "HighCPU", "alertstate": "firing"},
+						Values: []model.SamplePair{at(100), at(101), at(102), at(110), at(111), at(120)},
 					},
-					Silence: models.Silence{
-						Matchers: models.Matchers{
-							&models.Matcher{
-								Name:    new("alertname"),
-								Value:   new("HighCPU"),
-								IsRegex: new(false),
-								IsEqual: new(true),
-							},
-						},
-						StartsAt:  &now,
-						EndsAt:    &now,
-						CreatedBy: new("admin"),
-						Comment:   new("Maintenance window"),
+					{
+						Metric: model.Metric{"__name__": "ALERTS", "alertname": "PodCrashLooping", "alertstate": "firing"},
+						Values: []model.SamplePair{at(10), at(11)},
 					},
 				},
 			}, nil
 		},
 	}
 
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := GetAlertHistoryHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{
+		"since":  since.Format(time.RFC3339),
+		"until":  until.Format(time.RFC3339),
+		"filter": "namespace=default, severity=~warning|critical",
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildAlertHistoryInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantQuery := `ALERTS{alertstate="firing", namespace="default", severity=~"warning|critical"}`
+	if gotQuery != wantQuery {
+		t.Errorf("query = %s, want %s", gotQuery, wantQuery)
+	}
+	if output.Resolution != "30s" {
+		t.Errorf("resolution = %s, want 30s", output.Resolution)
+	}
+
+	ts := func(n int) string { return since.Add(time.Duration(n) * step).Format(time.RFC3339) }
+	want := []tools.AlertHistory{
+		{
+			Labels:    map[string]string{"alertname": "PodCrashLooping", "alertstate": "firing"},
+			Intervals: []tools.AlertInterval{{Start: ts(10), End: ts(11)}},
+		},
+		{
+			Labels: map[string]string{"alertname": "HighCPU", "alertstate": "firing"},
+			Intervals: []tools.AlertInterval{
+				{Start: ts(100), End: ts(102)},
+				{Start: ts(110), End: ts(111)},
+				{Start: ts(120), End: ts(120), Ongoing: true},
+			},
+		},
+	}
+	if !reflect.DeepEqual(output.Alerts, want) {
+		t.Errorf("alerts = %+v, want %+v", output.Alerts, want)
+	}
+
+	t.Run("alertstate matcher overrides the default", func(t *testing.T) {
+		params := map[string]any{"filter": "alertstate=pending"}
+		req := newMockRequest(params)
+		mockClient.ExecuteRangeQueryFunc = func(ctx context.Context, query string, start, end time.Time, s time.Duration) (map[string]any, error) {
+			gotQuery = query
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{}}, nil
+		}
+		if _, _, err := handler(ctx, &req, tools.BuildAlertHistoryInput(params)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotQuery != `ALERTS{alertstate="pending"}` {
+			t.Errorf("query = %s, want ALERTS{alertstate=\"pending\"}", gotQuery)
+		}
+	})
+
+	t.Run("since after until", func(t *testing.T) {
+		params := map[string]any{"since": "NOW", "until": "NOW-1h"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildAlertHistoryInput(params)); err == nil {
+			t.Error("expected error when since is after until, got nil")
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		params := map[string]any{"filter": "alertname=~("}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildAlertHistoryInput(params)); err == nil {
+			t.Error("expected error for invalid filter, got nil")
+		}
+	})
+}
+
+func TestGetSilencesHandler_ClientError(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetSilencesFunc: func(ctx context.Context, filter []string) (models.GettableSilences, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
 	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
 	handler := GetSilencesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 	req := newMockRequest(map[string]any{})
 
 	input := tools.BuildSilencesInput(map[string]any{})
 	_, _, err := handler(ctx, &req, input)
+	if err == nil {
+		t.Fatal("expected error result, got success")
+	}
+	if err.Error() != "failed to get silences: connection refused" {
+		t.Errorf("expected error message 'failed to get silences: connection refused', got %q", err.Error())
+	}
+}
+
+func TestGetAlertmanagerStatusHandler(t *testing.T) {
+	started := strfmt.DateTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	mockClient := &MockedAlertmanagerLoader{
+		GetStatusFunc: func(ctx context.Context) (*models.AlertmanagerStatus, error) {
+			return &models.AlertmanagerStatus{
+				Cluster: &models.ClusterStatus{
+					Name:   "01HXYZ",
+					Status: new("ready"),
+					Peers: []*models.PeerStatus{
+						{Name: new("01HXYZ"), Address: new("10.0.0.1:9094")},
+						{Name: new("01HABC"), Address: new("10.0.0.2:9094")},
+					},
+				},
+				Config:      &models.AlertmanagerConfig{Original: new("route:\n  receiver: default\n")},
+				Uptime:      &started,
+				VersionInfo: &models.VersionInfo{Version: new("0.27.0"), Revision: new("abc123")},
+			}, nil
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(t.Context(), mockClient)
+	handler := GetAlertmanagerStatusHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+
+	_, output, err := handler(ctx, &req, tools.BuildAlertmanagerStatusInput(map[string]any{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if output.Version != "0.27.0" || output.Revision != "abc123" {
+		t.Errorf("version = %s (%s), want 0.27.0 (abc123)", output.Version, output.Revision)
+	}
+	if output.StartedAt != "2024-01-01T12:00:00Z" {
+		t.Errorf("startedAt = %s, want 2024-01-01T12:00:00Z", output.StartedAt)
+	}
+	if output.ClusterStatus != "ready" || output.ClusterName != "01HXYZ" || len(output.Peers) != 2 {
+		t.Errorf("cluster = %s/%s with peers %v, want ready/01HXYZ with 2 peers", output.ClusterStatus, output.ClusterName, output.Peers)
+	}
+	if len(output.ConfigHash) != 64 || strings.Contains(output.ConfigHash, "receiver") {
+		t.Errorf("configHash = %q, want a SHA-256 hex digest", output.ConfigHash)
+	}
 }
 
-func TestGetSilencesHandler_WithFilter(t *testing.T) {
-	silenceID := "test-silence-id"
-	silenceState := "active"
-	now := strfmt.DateTime(time.Now())
+func TestGetAlertmanagerStatusHandler_ClientError(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetStatusFunc: func(ctx context.Context) (*models.AlertmanagerStatus, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	ctx := withMockAlertmanagerClient(t.Context(), mockClient)
+	handler := GetAlertmanagerStatusHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	req := newMockRequest(map[string]any{})
+
+	_, _, err := handler(ctx, &req, tools.BuildAlertmanagerStatusInput(map[string]any{}))
+	if err == nil || err.Error() != "failed to get Alertmanager status: connection refused" {
+		t.Errorf("expected client error, got %v", err)
+	}
+}
 
+func TestDeleteSilenceHandler(t *testing.T) {
+	const id = "4d4c7a8e-3f1b-4c5e-9d2a-1b6f0e8c7a21"
+	var deleted string
 	mockClient := &MockedAlertmanagerLoader{
-		GetSilencesFunc: func(ctx context.Context, filterParam []string) (models.GettableSilences, error) {
-			if len(filterParam) != 1 || filterParam[0] != "alertname=HighCPU" {
-				t.Errorf("expected filter 'alertname=HighCPU', got %v", filterParam)
+		DeleteSilenceFunc: func(ctx context.Context, silenceID string) error {
+			if silenceID != id {
+				return fmt.Errorf("%w: %s", alertmanager.ErrSilenceNotFound, silenceID)
 			}
+			deleted = silenceID
+			return nil
+		},
+	}
+	ctx := withMockAlertmanagerClient(t.Context(), mockClient)
 
-			return models.GettableSilences{
-				&models.GettableSilence{
-					ID: &silenceID,
-					Status: &models.SilenceStatus{
-						State: &silenceState,
+	t.Run("deletes the silence", func(t *testing.T) {
+		params := map[string]any{"id": id}
+		req := newMockRequest(params)
+		_, output, err := DeleteSilenceHandler(ObsMCPOptions{Metrics: &tools.Config{AllowWrites: true}})(ctx, &req, tools.BuildDeleteSilenceInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deleted != id || output.ID != id {
+			t.Errorf("deleted = %q, output ID = %q, want %q", deleted, output.ID, id)
+		}
+		if output.Message == "" {
+			t.Error("expected a success message")
+		}
+	})
+
+	t.Run("unknown silence", func(t *testing.T) {
+		params := map[string]any{"id": "9b2e6f0a-7c3d-4e1f-8a5b-2c4d6e8f0a1b"}
+		req := newMockRequest(params)
+		_, _, err := DeleteSilenceHandler(ObsMCPOptions{Metrics: &tools.Config{AllowWrites: true}})(ctx, &req, tools.BuildDeleteSilenceInput(params))
+		if !errors.Is(err, alertmanager.ErrSilenceNotFound) {
+			t.Errorf("error = %v, want %v", err, alertmanager.ErrSilenceNotFound)
+		}
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		params := map[string]any{}
+		req := newMockRequest(params)
+		if _, _, err := DeleteSilenceHandler(ObsMCPOptions{Metrics: &tools.Config{AllowWrites: true}})(ctx, &req, tools.BuildDeleteSilenceInput(params)); err == nil {
+			t.Error("expected error when id is missing, got nil")
+		}
+	})
+
+	t.Run("writes disabled", func(t *testing.T) {
+		deleted = ""
+		params := map[string]any{"id": id}
+		req := newMockRequest(params)
+		if _, _, err := DeleteSilenceHandler(ObsMCPOptions{Metrics: &tools.Config{}})(ctx, &req, tools.BuildDeleteSilenceInput(params)); err == nil {
+			t.Error("expected error when writes are disabled, got nil")
+		}
+		if deleted != "" {
+			t.Error("silence was deleted although writes are disabled")
+		}
+	})
+}
+
+func TestAlertmanagerTools_NotConfigured(t *testing.T) {
+	opts := ObsMCPOptions{Metrics: &tools.Config{PrometheusURL: "http://localhost:9090"}}
+	params := map[string]any{}
+	req := newMockRequest(params)
+	calls := map[string]func() error{
+		"get_alerts": func() error {
+			_, _, err := GetAlertsHandler(opts)(t.Context(), &req, tools.BuildAlertsInput(params))
+			return err
+		},
+		"summarize_alerts": func() error {
+			_, _, err := SummarizeAlertsHandler(opts)(t.Context(), &req, tools.BuildSummarizeAlertsInput(params))
+			return err
+		},
+		"get_silences": func() error {
+			_, _, err := GetSilencesHandler(opts)(t.Context(), &req, tools.BuildSilencesInput(params))
+			return err
+		},
+		"get_alertmanager_status": func() error {
+			_, _, err := GetAlertmanagerStatusHandler(opts)(t.Context(), &req, tools.BuildAlertmanagerStatusInput(params))
+			return err
+		},
+		"delete_silence": func() error {
+			_, _, err := DeleteSilenceHandler(opts)(t.Context(), &req, tools.BuildDeleteSilenceInput(params))
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+			if !errors.Is(err, errAlertmanagerNotConfigured) {
+				t.Errorf("error = %v, want %v", err, errAlertmanagerNotConfigured)
+			}
+		})
+	}
+}
+
+func TestGetSeriesHandler_WithLastSeen(t *testing.T) {
+	end := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	start := end.Add(-time.Hour)
+	at := func(d time.Duration) model.SamplePair {
+		return model.SamplePair{Timestamp: model.TimeFromUnixNano(end.Add(-d).UnixNano()), Value: 1}
+	}
+
+	series := []map[string]string{
+		{"__name__": "up", "job": "api", "instance": "a:8080"},
+		{"__name__": "up", "job": "api", "instance": "b:8080"},
+		{"__name__": "up", "job": "api", "instance": "c:8080"},
+	}
+	var rangeQueries int
+	mockClient := &MockedLoader{
+		GetSeriesFunc: func(ctx context.Context, matches []string, s, e time.Time) ([]map[string]string, error) {
+			return series, nil
+		},
+		ExecuteRangeQueryFunc: func(ctx context.Context, query string, s, e time.Time, step time.Duration) (map[string]any, error) {
+			rangeQueries++
+			if query != `up{job="api"}` || !s.Equal(start) || !e.Equal(end) || step != 30*time.Second {
+				t.Errorf("unexpected probe %s over %v - %v step %v", query, s, e, step)
+			}
+			return map[string]any{
+				"resultType": "matrix",
+				"result": model.Matrix{
+					{
+						Metric: model.Metric{"__name__": "up", "job": "api", "instance": "b:8080"},
+						Values: []model.SamplePair{at(40 * time.Minute), at(30 * time.Minute)},
 					},
-					Silence: models.Silence{
-						Matchers: models.Matchers{
-							&models.Matcher{
-								Name:    new("alertname"),
-								Value:   new("HighCPU"),
-								IsRegex: new(false),
-								IsEqual: new(true),
-							},
-						},
-						StartsAt:  &now,
-						EndsAt:    &now,
-						CreatedBy: new("admin"),
-						Comment:   new("Planned maintenance"),
+					{
+						Metric: model.Metric{"__name__": "up", "job": "api", "instance": "a:8080"},
+						Values: []model.SamplePair{at(2 * time.Minute), at(time.Minute)},
 					},
 				},
 			}, nil
 		},
 	}
 
-	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
-	handler := GetSilencesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-	paramsMap := map[string]any{
-		"filter": "alertname=HighCPU",
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := GetSeriesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{
+		"matches": `up{job="api"}`,
+		"start":   start.Format(time.RFC3339),
+		"end":     end.Format(time.RFC3339),
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildSeriesInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rangeQueries != 0 || output.LastSeen != nil {
+		t.Errorf("expected no probe without with_last_seen, got %d queries and %v", rangeQueries, output.LastSeen)
+	}
+
+	params["with_last_seen"] = true
+	_, output, err = handler(ctx, &req, tools.BuildSeriesInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"1m ago", "stale 30m", "no samples in range"}
+	if !slices.Equal(output.LastSeen, want) {
+		t.Errorf("lastSeen = %v, want %v", output.LastSeen, want)
+	}
+}
+
+func TestListMetricsHandler_Limit(t *testing.T) {
+	mockClient := &MockedLoader{
+		ListMetricsFunc: func(ctx context.Context, nameRegex string) ([]string, error) {
+			return []string{"up_a", "up_b", "up_c"}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := ListMetricsHandler(ObsMCPOptions{Metrics: &tools.Config{
+		Limits: map[string]tools.ToolLimitConfig{tools.LimitListMetrics: {Default: new(2), Max: new(5)}},
+	}})
+
+	params := map[string]any{"name_regex": "up_.*"}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildListMetricsInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(output.Metrics, []string{"up_a", "up_b"}) || !output.Truncated || output.TotalCount != 3 {
+		t.Errorf("output = %+v, want the first 2 of 3 metrics", output)
+	}
+
+	params["limit"] = float64(5)
+	_, output, err = handler(ctx, &req, tools.BuildListMetricsInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Metrics) != 3 || output.Truncated {
+		t.Errorf("output = %+v, want all metrics", output)
+	}
+
+	params["limit"] = float64(6)
+	if _, _, err = handler(ctx, &req, tools.BuildListMetricsInput(params)); err == nil || !strings.Contains(err.Error(), "exceeds the server-side maximum of 5") {
+		t.Errorf("error = %v, want the limit to be rejected", err)
+	}
+}
+
+func TestGetSeriesHandler_Limit(t *testing.T) {
+	mockClient := &MockedLoader{
+		GetSeriesFunc: func(ctx context.Context, matches []string, s, e time.Time) ([]map[string]string, error) {
+			return []map[string]string{{"instance": "a"}, {"instance": "b"}, {"instance": "c"}}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := GetSeriesHandler(ObsMCPOptions{Metrics: &tools.Config{
+		Limits: map[string]tools.ToolLimitConfig{tools.LimitSeries: {Max: new(2)}},
+	}})
+
+	params := map[string]any{"matches": `up{job="api"}`}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildSeriesInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Series) != 2 || !output.Truncated || output.Cardinality != 3 {
+		t.Errorf("output = %+v, want 2 of 3 series", output)
+	}
+
+	params["limit"] = float64(3)
+	if _, _, err = handler(ctx, &req, tools.BuildSeriesInput(params)); err == nil || !strings.Contains(err.Error(), "exceeds the server-side maximum of 2") {
+		t.Errorf("error = %v, want the limit to be rejected", err)
+	}
+}
+
+func TestInspectMetricHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		GetSeriesFunc: func(ctx context.Context, matches []string, start, end time.Time) ([]map[string]string, error) {
+			if len(matches) != 1 || matches[0] != "requests_total" {
+				t.Errorf("expected selector requests_total, got %v", matches)
+			}
+			return []map[string]string{
+				{"__name__": "requests_total", "job": "api", "code": "200"},
+				{"__name__": "requests_total", "job": "proxy", "backend": "db"},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := InspectMetricHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{"metric": "requests_total"}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildInspectMetricInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !output.Ambiguous || len(output.Jobs) != 2 {
+		t.Errorf("expected an ambiguous metric exported by 2 jobs, got %+v", output)
+	}
+
+	params = map[string]any{"metric": `requests_total{job="api"}`}
+	req = newMockRequest(params)
+	if _, _, err := handler(ctx, &req, tools.BuildInspectMetricInput(params)); err == nil || !strings.Contains(err.Error(), "invalid metric name") {
+		t.Errorf("expected invalid metric name error, got %v", err)
+	}
+}
+
+func TestGetMetricMetadataHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		GetMetricMetadataFunc: func(ctx context.Context, metric string) ([]v1.Metadata, error) {
+			if metric != "node_cpu_seconds_total" {
+				t.Errorf("expected metric node_cpu_seconds_total, got %q", metric)
+			}
+			return []v1.Metadata{{Type: v1.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"}}, nil
+		},
+		ListMetricMetadataFunc: func(ctx context.Context, limit int) (map[string][]v1.Metadata, error) {
+			if limit != 3 {
+				t.Errorf("expected the metadata of 3 metrics to be fetched, got %d", limit)
+			}
+			return map[string][]v1.Metadata{
+				"up":                               {{Type: v1.MetricTypeGauge, Help: "Whether the target is up."}},
+				"node_cpu_scaling_frequency_hertz": {{Type: v1.MetricTypeGauge, Help: "Current scaled CPU thread frequency in hertz."}},
+				"node_cpu_seconds_total":           {{Type: v1.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode."}},
+			}, nil
+		},
+	}
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := GetMetricMetadataHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("single metric", func(t *testing.T) {
+		params := map[string]any{"metric": "node_cpu_seconds_total"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildMetricMetadataInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []tools.MetricMetadata{{Metric: "node_cpu_seconds_total", Type: "counter", Help: "Seconds the CPUs spent in each mode.", Unit: "seconds"}}
+		if !reflect.DeepEqual(output.Metadata, want) {
+			t.Errorf("got metadata %+v, want %+v", output.Metadata, want)
+		}
+	})
+
+	t.Run("all metrics up to the limit", func(t *testing.T) {
+		params := map[string]any{"limit": 2}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildMetricMetadataInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !output.Truncated || len(output.Metadata) != 2 {
+			t.Fatalf("expected 2 truncated entries, got %+v", output)
+		}
+		if output.Metadata[0].Metric != "node_cpu_scaling_frequency_hertz" || output.Metadata[1].Type != "counter" {
+			t.Errorf("expected the entries sorted by metric name, got %+v", output.Metadata)
+		}
+	})
+
+	t.Run("invalid metric name", func(t *testing.T) {
+		params := map[string]any{"metric": `up{job="api"}`}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildMetricMetadataInput(params)); err == nil || !strings.Contains(err.Error(), "invalid metric name") {
+			t.Errorf("expected invalid metric name error, got %v", err)
+		}
+	})
+}
+
+func TestGetScrapeIntervalHandler(t *testing.T) {
+	var gotQuery string
+	mockClient := &MockedLoader{
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			gotQuery = query
+			series := &model.SampleStream{Metric: model.Metric{"job": "api"}}
+			for i := range 5 {
+				series.Values = append(series.Values, model.SamplePair{Timestamp: model.Time(i * 30_000), Value: 1})
+			}
+			return map[string]any{"resultType": "matrix", "result": model.Matrix{series}}, nil
+		},
 	}
-	req := newMockRequest(paramsMap)
+	ctx := withMockClient(t.Context(), mockClient)
+	handler := GetScrapeIntervalHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 
-	input := tools.BuildSilencesInput(paramsMap)
-	_, _, err := handler(ctx, &req, input)
+	params := map[string]any{"metric": `up{job="api"}`}
+	req := newMockRequest(params)
+	_, output, err := handler(ctx, &req, tools.BuildScrapeIntervalInput(params))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if gotQuery != `up{job="api"}[10m]` {
+		t.Errorf("query = %q, want the raw samples of the last 10m", gotQuery)
+	}
+	if output.Interval != "30s" || output.MinRateWindow != "2m" {
+		t.Errorf("got interval %s and rate window %s, want 30s and 2m", output.Interval, output.MinRateWindow)
+	}
+
+	for _, params := range []map[string]any{
+		{"metric": "rate(up[5m])"},
+		{"metric": "up"},
+		{"metric": `{__name__="up"}`},
+		{"metric": `up{job="api"}`, "window": "ten minutes"},
+	} {
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildScrapeIntervalInput(params)); err == nil || !strings.Contains(err.Error(), "invalid") {
+			t.Errorf("expected a validation error for %v, got %v", params, err)
+		}
+	}
 }
 
-func TestGetSilencesHandler_EmptyResult(t *testing.T) {
-	mockClient := &MockedAlertmanagerLoader{
-		GetSilencesFunc: func(ctx context.Context, filter []string) (models.GettableSilences, error) {
-			return models.GettableSilences{}, nil
+func TestCheckSeriesUniquenessHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		series      []map[string]string
+		wantUnique  bool
+		wantCommon  map[string]string
+		wantVarying []tools.VaryingLabel
+	}{
+		{
+			name: "single series is unique",
+			series: []map[string]string{
+				{"__name__": "up", "job": "api", "instance": "a:8080"},
+			},
+			wantUnique: true,
+			wantCommon: map[string]string{"__name__": "up", "job": "api", "instance": "a:8080"},
+		},
+		{
+			name: "varying labels are reported",
+			series: []map[string]string{
+				{"__name__": "up", "job": "api", "instance": "a:8080", "pod": "api-1"},
+				{"__name__": "up", "job": "api", "instance": "b:8080", "pod": "api-2"},
+				{"__name__": "up", "job": "api", "instance": "c:8080"},
+			},
+			wantUnique: false,
+			wantCommon: map[string]string{"__name__": "up", "job": "api"},
+			wantVarying: []tools.VaryingLabel{
+				{Name: "instance", ValueCount: 3, Values: []string{"a:8080", "b:8080", "c:8080"}},
+				{Name: "pod", ValueCount: 3, Values: []string{"", "api-1", "api-2"}},
+			},
+		},
+		{
+			name:       "no matching series",
+			series:     []map[string]string{},
+			wantUnique: false,
 		},
 	}
 
-	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
-	handler := GetSilencesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-	req := newMockRequest(map[string]any{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockedLoader{
+				GetSeriesFunc: func(ctx context.Context, matches []string, start, end time.Time) ([]map[string]string, error) {
+					if len(matches) != 1 || matches[0] != `up{job="api"}` {
+						t.Errorf("expected selector up{job=\"api\"}, got %v", matches)
+					}
+					return tt.series, nil
+				},
+			}
 
-	input := tools.BuildSilencesInput(map[string]any{})
-	_, _, err := handler(ctx, &req, input)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+			ctx := withMockClient(context.Background(), mockClient)
+			handler := CheckSeriesUniquenessHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+			params := map[string]any{"selector": `up{job="api"}`}
+			req := newMockRequest(params)
+			_, output, err := handler(ctx, &req, tools.BuildSeriesUniquenessInput(params))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if output.Unique != tt.wantUnique || output.SeriesCount != len(tt.series) {
+				t.Errorf("unique = %v, seriesCount = %d, want %v, %d", output.Unique, output.SeriesCount, tt.wantUnique, len(tt.series))
+			}
+			if !maps.Equal(output.CommonLabels, tt.wantCommon) {
+				t.Errorf("commonLabels = %v, want %v", output.CommonLabels, tt.wantCommon)
+			}
+			if !reflect.DeepEqual(output.VaryingLabels, tt.wantVarying) {
+				t.Errorf("varyingLabels = %+v, want %+v", output.VaryingLabels, tt.wantVarying)
+			}
+		})
 	}
+
+	t.Run("missing selector", func(t *testing.T) {
+		handler := CheckSeriesUniquenessHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		req := newMockRequest(map[string]any{})
+		ctx := withMockClient(context.Background(), &MockedLoader{})
+		if _, _, err := handler(ctx, &req, tools.BuildSeriesUniquenessInput(map[string]any{})); err == nil {
+			t.Error("expected error for missing selector, got nil")
+		}
+	})
 }
 
-func TestGetAlertsHandler_ClientError(t *testing.T) {
-	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
-			return nil, fmt.Errorf("connection refused")
+func TestGetExternalLabelsHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		GetExternalLabelsFunc: func(ctx context.Context) (*prometheus.ExternalLabels, error) {
+			return &prometheus.ExternalLabels{
+				Labels: map[string][]string{"replica": {"a", "b"}, "cluster": {"prod"}},
+				Source: prometheus.ExternalLabelsSourceStores,
+			}, nil
 		},
 	}
 
-	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
-	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := GetExternalLabelsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 	req := newMockRequest(map[string]any{})
+	_, output, err := handler(ctx, &req, tools.ExternalLabelsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	input := tools.BuildAlertsInput(map[string]any{})
-	_, _, err := handler(ctx, &req, input)
-	if err == nil {
-		t.Fatal("expected error result, got success")
+	want := []tools.ExternalLabel{
+		{Name: "cluster", Values: []string{"prod"}},
+		{Name: "replica", Values: []string{"a", "b"}},
 	}
-	if err.Error() != "failed to get alerts: connection refused" {
-		t.Errorf("expected error message 'failed to get alerts: connection refused', got %q", err.Error())
+	if !reflect.DeepEqual(output.Labels, want) {
+		t.Errorf("labels = %+v, want %+v", output.Labels, want)
+	}
+	if output.Source != prometheus.ExternalLabelsSourceStores {
+		t.Errorf("source = %q, want %q", output.Source, prometheus.ExternalLabelsSourceStores)
 	}
 }
 
-func TestGetAlertsHandler_WithMultipleFilters(t *testing.T) {
-	activeState := "active"
-	now := strfmt.DateTime(time.Now())
+func TestGetActiveQueriesHandler(t *testing.T) {
+	t.Run("engines are reported with the total", func(t *testing.T) {
+		mockClient := &MockedLoader{
+			GetActiveQueriesFunc: func(ctx context.Context) (*prometheus.ActiveQueries, error) {
+				return &prometheus.ActiveQueries{
+					Engines: []prometheus.QueryEngine{
+						{Labels: map[string]string{"pod": "prometheus-k8s-0"}, Queries: 3, MaxConcurrency: 20},
+						{Labels: map[string]string{"pod": "prometheus-k8s-1"}, Queries: 20, MaxConcurrency: 20},
+					},
+					QueryLogFile: "/prometheus/query.log",
+				}, nil
+			},
+		}
 
-	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filterParam []string, receiver string) (models.GettableAlerts, error) {
-			// Verify multiple filters are passed correctly
-			expectedFilters := []string{"alertname=HighCPU", "severity=critical"}
-			if len(filterParam) != len(expectedFilters) {
-				t.Errorf("expected %d filters, got %d: %v", len(expectedFilters), len(filterParam), filterParam)
-			}
-			for i, expected := range expectedFilters {
-				if i < len(filterParam) && filterParam[i] != expected {
-					t.Errorf("expected filter[%d] to be %q, got %q", i, expected, filterParam[i])
-				}
-			}
+		ctx := withMockClient(context.Background(), mockClient)
+		handler := GetActiveQueriesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		req := newMockRequest(map[string]any{})
+		_, output, err := handler(ctx, &req, tools.ActiveQueriesInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-			return models.GettableAlerts{
-				&models.GettableAlert{
-					Alert: models.Alert{
-						Labels: models.LabelSet{
-							"alertname": "HighCPU",
-							"severity":  "critical",
+		if len(output.Engines) != 2 || output.TotalQueries != 23 {
+			t.Errorf("engines = %+v, totalQueries = %v, want 2 engines and 23 queries", output.Engines, output.TotalQueries)
+		}
+		if output.QueryLogFile != "/prometheus/query.log" {
+			t.Errorf("queryLogFile = %q, want /prometheus/query.log", output.QueryLogFile)
+		}
+		if output.Note != "" {
+			t.Errorf("unexpected note: %q", output.Note)
+		}
+	})
+
+	t.Run("missing engine metrics are explained", func(t *testing.T) {
+		ctx := withMockClient(context.Background(), &MockedLoader{})
+		handler := GetActiveQueriesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+		req := newMockRequest(map[string]any{})
+		_, output, err := handler(ctx, &req, tools.ActiveQueriesInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Engines) != 0 || output.Note == "" {
+			t.Errorf("expected no engines and a note, got %+v", output)
+		}
+	})
+}
+
+func TestListRecordingRulesHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		GetRulesFunc: func(ctx context.Context) (v1.RulesResult, error) {
+			return v1.RulesResult{
+				Groups: []v1.RuleGroup{
+					{
+						Name: "k8s.rules",
+						Rules: v1.Rules{
+							v1.RecordingRule{
+								Name:   "namespace:container_cpu_usage:sum",
+								Query:  "sum by (namespace) (rate(container_cpu_usage_seconds_total[5m]))",
+								Health: v1.RuleHealthGood,
+							},
+							v1.RecordingRule{
+								Name:  "namespace:container_memory_usage:sum",
+								Query: "sum by (namespace) (container_memory_working_set_bytes)",
+							},
+							v1.AlertingRule{
+								Name:  "HighCPU",
+								Query: "namespace:container_cpu_usage:sum > 10",
+							},
 						},
 					},
-					Annotations: models.LabelSet{},
-					StartsAt:    &now,
-					EndsAt:      &now,
-					Status: &models.AlertStatus{
-						State:       &activeState,
-						SilencedBy:  []string{},
-						InhibitedBy: []string{},
-					},
 				},
 			}, nil
 		},
+		ListMetricsFunc: func(ctx context.Context, nameRegex string) ([]string, error) {
+			return []string{"namespace:container_cpu_usage:sum", "up"}, nil
+		},
 	}
 
-	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
-	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-	paramsMap := map[string]any{
-		"filter": "alertname=HighCPU, severity=critical",
-	}
-	req := newMockRequest(paramsMap)
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := ListRecordingRulesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 
-	input := tools.BuildAlertsInput(paramsMap)
-	_, _, err := handler(ctx, &req, input)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-}
+	t.Run("all recording rules", func(t *testing.T) {
+		req := newMockRequest(map[string]any{})
+		_, output, err := handler(ctx, &req, tools.BuildRecordingRulesInput(map[string]any{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Rules) != 2 {
+			t.Fatalf("expected 2 recording rules, got %d", len(output.Rules))
+		}
 
-func TestGetAlertsHandler_WithMultipleFiltersNoSpaces(t *testing.T) {
-	activeState := "active"
-	now := strfmt.DateTime(time.Now())
+		cpu := output.Rules[0]
+		if cpu.Metric != "namespace:container_cpu_usage:sum" || cpu.Group != "k8s.rules" || cpu.Health != "ok" {
+			t.Errorf("unexpected rule: %+v", cpu)
+		}
+		if cpu.HasData == nil || !*cpu.HasData {
+			t.Errorf("expected %q to have data", cpu.Metric)
+		}
+		if mem := output.Rules[1]; mem.HasData == nil || *mem.HasData {
+			t.Errorf("expected %q to have no data", mem.Metric)
+		}
+	})
 
-	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filterParam []string, receiver string) (models.GettableAlerts, error) {
-			// Verify multiple filters without spaces are parsed and trimmed correctly
-			expectedFilters := []string{"alertname=HighCPU", "severity=warning", "job=api"}
-			if len(filterParam) != len(expectedFilters) {
-				t.Errorf("expected %d filters, got %d: %v", len(expectedFilters), len(filterParam), filterParam)
-			}
-			for i, expected := range expectedFilters {
-				if i < len(filterParam) && filterParam[i] != expected {
-					t.Errorf("expected filter[%d] to be %q, got %q", i, expected, filterParam[i])
-				}
-			}
+	t.Run("filtered by name_regex", func(t *testing.T) {
+		params := map[string]any{"name_regex": ".*memory.*"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRecordingRulesInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(output.Rules) != 1 || output.Rules[0].Metric != "namespace:container_memory_usage:sum" {
+			t.Errorf("expected only the memory recording rule, got %+v", output.Rules)
+		}
+	})
 
-			return models.GettableAlerts{
-				&models.GettableAlert{
-					Alert: models.Alert{
-						Labels: models.LabelSet{
-							"alertname": "HighCPU",
+	t.Run("invalid name_regex", func(t *testing.T) {
+		params := map[string]any{"name_regex": "[invalid"}
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRecordingRulesInput(params))
+		if err == nil {
+			t.Fatal("expected error for invalid regex, got nil")
+		}
+	})
+}
+
+func TestGetRuleGroupHandler(t *testing.T) {
+	mockClient := &MockedLoader{
+		GetRulesFunc: func(ctx context.Context) (v1.RulesResult, error) {
+			return v1.RulesResult{
+				Groups: []v1.RuleGroup{
+					{
+						Name:     "k8s.rules",
+						File:     "/etc/prometheus/rules/k8s.yaml",
+						Interval: 30,
+						Rules: v1.Rules{
+							v1.RecordingRule{
+								Name:   "namespace:container_cpu_usage:sum",
+								Query:  "sum by (namespace) (rate(container_cpu_usage_seconds_total[5m]))",
+								Health: v1.RuleHealthGood,
+							},
+							v1.AlertingRule{
+								Name:      "HighCPU",
+								Query:     "namespace:container_cpu_usage:sum > 10",
+								Health:    v1.RuleHealthBad,
+								LastError: "query timed out",
+							},
 						},
 					},
-					Annotations: models.LabelSet{},
-					StartsAt:    &now,
-					EndsAt:      &now,
-					Status: &models.AlertStatus{
-						State:       &activeState,
-						SilencedBy:  []string{},
-						InhibitedBy: []string{},
-					},
+					{Name: "node.rules", File: "/etc/prometheus/rules/node.yaml"},
 				},
 			}, nil
 		},
 	}
 
-	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
-	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-	paramsMap := map[string]any{
-		"filter": "alertname=HighCPU,severity=warning,job=api",
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := GetRuleGroupHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("existing group", func(t *testing.T) {
+		params := map[string]any{"group": "k8s.rules"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildRuleGroupInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output.File != "/etc/prometheus/rules/k8s.yaml" || output.Interval != "30s" {
+			t.Errorf("unexpected group: %+v", output)
+		}
+		if len(output.Rules) != 2 {
+			t.Fatalf("expected 2 rules, got %d", len(output.Rules))
+		}
+		if len(output.Failing) != 1 || output.Failing[0] != "HighCPU" {
+			t.Errorf("expected HighCPU to be failing, got %v", output.Failing)
+		}
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		params := map[string]any{"group": "etcd.rules"}
+		req := newMockRequest(params)
+		_, _, err := handler(ctx, &req, tools.BuildRuleGroupInput(params))
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+
+	t.Run("missing group", func(t *testing.T) {
+		req := newMockRequest(map[string]any{})
+		_, _, err := handler(ctx, &req, tools.BuildRuleGroupInput(map[string]any{}))
+		if err == nil {
+			t.Fatal("expected error for missing group, got nil")
+		}
+	})
+}
+
+func TestGetAlertThresholdHandler(t *testing.T) {
+	var gotQueries []string
+	mockClient := &MockedLoader{
+		GetRulesFunc: func(ctx context.Context) (v1.RulesResult, error) {
+			return v1.RulesResult{
+				Groups: []v1.RuleGroup{
+					{
+						Name: "node.rules",
+						Rules: v1.Rules{
+							v1.AlertingRule{
+								Name:     "HighMemoryUsage",
+								Query:    "90 < node_memory_usage_percent",
+								Duration: 300,
+							},
+							v1.AlertingRule{
+								Name:  "DiskFull",
+								Query: "disk_used_bytes > disk_capacity_bytes",
+							},
+						},
+					},
+				},
+			}, nil
+		},
+		ExecuteInstantQueryFunc: func(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
+			gotQueries = append(gotQueries, query)
+			return map[string]any{
+				"resultType": "vector",
+				"result": model.Vector{
+					{Metric: model.Metric{"instance": "a"}, Value: 95.5},
+					{Metric: model.Metric{"instance": "b"}, Value: 60},
+				},
+			}, nil
+		},
 	}
-	req := newMockRequest(paramsMap)
 
-	input := tools.BuildAlertsInput(paramsMap)
-	_, _, err := handler(ctx, &req, input)
+	ctx := withMockClient(context.Background(), mockClient)
+	handler := GetAlertThresholdHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	t.Run("constant threshold", func(t *testing.T) {
+		gotQueries = nil
+		params := map[string]any{"alertname": "HighMemoryUsage"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildAlertThresholdInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(gotQueries, []string{"node_memory_usage_percent"}) {
+			t.Errorf("queries = %v, want [node_memory_usage_percent]", gotQueries)
+		}
+		if len(output.Rules) != 1 {
+			t.Fatalf("expected 1 rule, got %d", len(output.Rules))
+		}
+		rule := output.Rules[0]
+		if rule.Group != "node.rules" || rule.For != "5m" || rule.Operator != ">" || rule.Threshold != "90" {
+			t.Errorf("unexpected rule %+v", rule)
+		}
+		want := []tools.ThresholdSeries{
+			{Labels: map[string]string{"instance": "a"}, Value: "95.5", Margin: "5.5", Breached: true},
+			{Labels: map[string]string{"instance": "b"}, Value: "60", Margin: "-30", Breached: false},
+		}
+		if !reflect.DeepEqual(rule.Series, want) {
+			t.Errorf("series = %+v, want %+v", rule.Series, want)
+		}
+	})
+
+	t.Run("no constant threshold", func(t *testing.T) {
+		gotQueries = nil
+		params := map[string]any{"alertname": "DiskFull"}
+		req := newMockRequest(params)
+		_, output, err := handler(ctx, &req, tools.BuildAlertThresholdInput(params))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(gotQueries) != 0 {
+			t.Errorf("expected no queries, got %v", gotQueries)
+		}
+		if len(output.Rules) != 1 || output.Rules[0].Note == "" || output.Rules[0].Threshold != "" {
+			t.Errorf("expected the rule with a note and no threshold, got %+v", output.Rules)
+		}
+	})
+
+	t.Run("unknown alert", func(t *testing.T) {
+		params := map[string]any{"alertname": "Unknown"}
+		req := newMockRequest(params)
+		if _, _, err := handler(ctx, &req, tools.BuildAlertThresholdInput(params)); err == nil {
+			t.Error("expected error for an unknown alert, got nil")
+		}
+	})
+}
+
+func TestRenderQueryTemplateHandler(t *testing.T) {
+	handler := RenderQueryTemplateHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{
+		"name":   "pod_memory_usage",
+		"params": map[string]any{"namespace": "openshift-monitoring"},
+	}
+	req := newMockRequest(params)
+	_, output, err := handler(context.Background(), &req, tools.BuildRenderQueryTemplateInput(params))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-}
 
-func TestGetAlertsHandler_EmptyResult(t *testing.T) {
-	mockClient := &MockedAlertmanagerLoader{
-		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
-			return models.GettableAlerts{}, nil
-		},
+	want := `sum by (pod) (container_memory_working_set_bytes{namespace="openshift-monitoring", container!=""})`
+	if output.Query != want {
+		t.Errorf("expected query %q, got %q", want, output.Query)
 	}
 
-	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
-	handler := GetAlertsHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-	req := newMockRequest(map[string]any{})
+	params = map[string]any{"name": "pod_memory_usage"}
+	req = newMockRequest(params)
+	if _, _, err := handler(context.Background(), &req, tools.BuildRenderQueryTemplateInput(params)); err == nil {
+		t.Error("expected error for missing namespace parameter, got nil")
+	}
+}
 
-	input := tools.BuildAlertsInput(map[string]any{})
-	_, _, err := handler(ctx, &req, input)
+func TestQueryToPanelHandler(t *testing.T) {
+	handler := QueryToPanelHandler(ObsMCPOptions{Metrics: &tools.Config{}})
+
+	params := map[string]any{"query": "sum(up)", "title": "Targets up", "chart_type": "stat"}
+	req := newMockRequest(params)
+	_, output, err := handler(context.Background(), &req, tools.BuildQueryToPanelInput(params))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-}
+	if output.Panel.Spec.Plugin.Kind != "StatChart" {
+		t.Errorf("plugin kind = %q, want StatChart", output.Panel.Spec.Plugin.Kind)
+	}
+	if len(output.Panel.Spec.Queries) != 1 || output.Panel.Spec.Queries[0].Spec.Plugin.Spec["query"] != "sum(up)" {
+		t.Errorf("queries = %+v, want the query sum(up)", output.Panel.Spec.Queries)
+	}
 
-func TestGetSilencesHandler_ClientError(t *testing.T) {
-	mockClient := &MockedAlertmanagerLoader{
-		GetSilencesFunc: func(ctx context.Context, filter []string) (models.GettableSilences, error) {
-			return nil, fmt.Errorf("connection refused")
-		},
+	for _, params := range []map[string]any{
+		{"title": "Targets up"},
+		{"query": "sum(up)"},
+		{"query": "sum(up", "title": "Targets up"},
+		{"query": "sum(up)", "title": "Targets up", "chart_type": "heatmap"},
+	} {
+		req := newMockRequest(params)
+		if _, _, err := handler(context.Background(), &req, tools.BuildQueryToPanelInput(params)); err == nil {
+			t.Errorf("expected error for params %v, got nil", params)
+		}
 	}
+}
 
-	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
-	handler := GetSilencesHandler(ObsMCPOptions{Metrics: &tools.Config{}})
-	req := newMockRequest(map[string]any{})
+func TestParseTimeHandler(t *testing.T) {
+	handler := ParseTimeHandler(ObsMCPOptions{Metrics: &tools.Config{}})
 
-	input := tools.BuildSilencesInput(map[string]any{})
-	_, _, err := handler(ctx, &req, input)
-	if err == nil {
-		t.Fatal("expected error result, got success")
+	params := map[string]any{"time": "2024-01-02T15:04:05+02:00"}
+	req := newMockRequest(params)
+	_, output, err := handler(context.Background(), &req, tools.BuildParseTimeInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if err.Error() != "failed to get silences: connection refused" {
-		t.Errorf("expected error message 'failed to get silences: connection refused', got %q", err.Error())
+	if output.Timestamp != "2024-01-02T13:04:05Z" || output.Unix != 1704200645 {
+		t.Errorf("unexpected resolved time: %+v", output)
+	}
+
+	params = map[string]any{"time": "NOW-1h"}
+	req = newMockRequest(params)
+	_, output, err = handler(context.Background(), &req, tools.BuildParseTimeInput(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Relative != "1h0m0s ago" {
+		t.Errorf("expected relative time %q, got %q", "1h0m0s ago", output.Relative)
+	}
+
+	params = map[string]any{"time": "2 hours ago"}
+	req = newMockRequest(params)
+	_, _, err = handler(context.Background(), &req, tools.BuildParseTimeInput(params))
+	if err == nil || !strings.Contains(err.Error(), `did you mean "NOW-2h"?`) {
+		t.Errorf("expected error suggesting NOW-2h, got %v", err)
 	}
 }
@@ -0,0 +1,18 @@
+package mcp
+
+import (
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/rhobs/obs-mcp/pkg/metrics"
+)
+
+// SetupPrompts registers the MCP prompts of the enabled toolsets.
+func SetupPrompts(mcpServer *mcp.Server, opts ObsMCPOptions) {
+	if slices.Contains(opts.Toolsets, metrics.ToolsetName) {
+		for _, p := range metrics.AllWorkflowPrompts() {
+			mcpServer.AddPrompt(p.ToMCPPrompt())
+		}
+	}
+}
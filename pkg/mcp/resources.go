@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/rhobs/obs-mcp/pkg/auth"
+	tools "github.com/rhobs/obs-mcp/pkg/metrics"
+)
+
+const (
+	metricCatalogURI = "metrics://catalog"
+	// metricCatalogTTL is how long a cached catalog is served before it is fetched again.
+	metricCatalogTTL = 5 * time.Minute
+)
+
+// metricCatalog caches the full list of metric names, as returned by list_metrics,
+// to serve the metrics://catalog resource. Subscribers of the resource are notified
+// whenever a refresh yields a different list.
+type metricCatalog struct {
+	mu      sync.Mutex
+	server  *mcp.Server
+	metrics []string
+	fetched time.Time
+}
+
+// newMetricCatalog returns a catalog cache, or nil when the catalog must not be shared
+// between callers. In header auth mode every caller has its own credentials and
+// may see a different set of metrics.
+func newMetricCatalog(cfg *tools.Config) *metricCatalog {
+	if cfg == nil || cfg.GetAuthMode() == auth.AuthModeHeader {
+		return nil
+	}
+	return &metricCatalog{}
+}
+
+// get returns the cached metric names if they are still fresh.
+func (c *metricCatalog) get() ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fetched.IsZero() || time.Since(c.fetched) > metricCatalogTTL {
+		return nil, false
+	}
+	return c.metrics, true
+}
+
+// update stores the metric names and notifies subscribers if the catalog changed.
+// It returns the names in the sorted order in which the catalog is served.
+func (c *metricCatalog) update(ctx context.Context, metrics []string) []string {
+	metrics = slices.Sorted(slices.Values(metrics))
+	if c == nil {
+		return metrics
+	}
+
+	c.mu.Lock()
+	changed := !c.fetched.IsZero() && !slices.Equal(c.metrics, metrics)
+	c.metrics = metrics
+	c.fetched = time.Now()
+	server := c.server
+	c.mu.Unlock()
+
+	if changed && server != nil {
+		if err := server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: metricCatalogURI}); err != nil {
+			slog.Warn("Failed to notify resource subscribers", "uri", metricCatalogURI, "error", err)
+		}
+	}
+	return metrics
+}
+
+func (c *metricCatalog) subscribe(_ context.Context, req *mcp.SubscribeRequest) error {
+	if req.Params.URI != metricCatalogURI {
+		return fmt.Errorf("subscriptions are not supported for resource %q", req.Params.URI)
+	}
+	return nil
+}
+
+func (c *metricCatalog) unsubscribe(_ context.Context, _ *mcp.UnsubscribeRequest) error {
+	return nil
+}
+
+// isMatchAllRegex reports whether a list_metrics name_regex selects every metric.
+func isMatchAllRegex(nameRegex string) bool {
+	return nameRegex == ".*" || nameRegex == ".+"
+}
+
+// SetupResources registers the MCP resources of the enabled toolsets.
+func SetupResources(mcpServer *mcp.Server, opts ObsMCPOptions) {
+	if slices.Contains(opts.Toolsets, tools.ToolsetName) {
+		mcpServer.AddResource(&mcp.Resource{
+			URI:         metricCatalogURI,
+			Name:        "metric-catalog",
+			Title:       "Metric catalog",
+			Description: "Names of all metrics available in the metrics backend",
+			MIMEType:    "application/json",
+		}, MetricCatalogResourceHandler(opts))
+	}
+}
+
+// MetricCatalogResourceHandler serves the metrics://catalog resource.
+func MetricCatalogResourceHandler(opts ObsMCPOptions) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		metrics, ok := opts.catalog.get()
+		if !ok {
+			promClient, err := getPromClient(ctx, opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
+			}
+
+			metrics, err = promClient.ListMetrics(ctx, ".*")
+			if err != nil {
+				return nil, fmt.Errorf("failed to list metrics: %w", err)
+			}
+			metrics = opts.catalog.update(ctx, metrics)
+		}
+
+		data, err := json.Marshal(tools.ListMetricsOutput{Metrics: metrics})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode metric catalog: %w", err)
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      req.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			}},
+		}, nil
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/rhobs/obs-mcp/pkg/instrumentation"
 	"github.com/rhobs/obs-mcp/pkg/logs"
 	"github.com/rhobs/obs-mcp/pkg/metrics"
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
 	"github.com/rhobs/obs-mcp/pkg/otelcol"
 	"github.com/rhobs/obs-mcp/pkg/traces"
 )
@@ -37,6 +38,7 @@ type ObsMCPOptions struct {
 	Registry               prom.Registerer
 	clientMetrics          *instrumentation.ClientMetrics
 	toolMetrics            *instrumentation.ToolMetrics
+	catalog                *metricCatalog
 }
 
 const (
@@ -59,7 +61,7 @@ func NewMCPServer(opts ObsMCPOptions) (*mcp.Server, error) {
 
 	impl := &mcp.Implementation{
 		Name:    serverName,
-		Version: serverVersion,
+		Version: buildVersion(),
 	}
 
 	var instructions []string
@@ -80,11 +82,24 @@ func NewMCPServer(opts ObsMCPOptions) (*mcp.Server, error) {
 		Instructions: strings.Join(instructions, "\n"),
 	}
 
+	if slices.Contains(opts.Toolsets, metrics.ToolsetName) {
+		opts.catalog = newMetricCatalog(opts.Metrics)
+	}
+	if opts.catalog != nil {
+		serverOpts.SubscribeHandler = opts.catalog.subscribe
+		serverOpts.UnsubscribeHandler = opts.catalog.unsubscribe
+	}
+
 	mcpServer := mcp.NewServer(impl, serverOpts)
+	if opts.catalog != nil {
+		opts.catalog.server = mcpServer
+	}
 
 	if err := SetupTools(mcpServer, opts); err != nil {
 		return nil, err
 	}
+	SetupResources(mcpServer, opts)
+	SetupPrompts(mcpServer, opts)
 
 	return mcpServer, nil
 }
@@ -116,25 +131,82 @@ func SetupTools(mcpServer *mcp.Server, opts ObsMCPOptions) error {
 		}
 	}
 
+	mcp.AddTool(mcpServer, Version.ToMCPTool(),
+		instrumentation.ToolHandler(Version.Name, opts.toolMetrics, VersionHandler(opts)))
+
 	if slices.Contains(opts.Toolsets, metrics.ToolsetName) {
-		mcp.AddTool(mcpServer, metrics.ListMetrics.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.ListMetrics.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.ListMetrics.Name, opts.toolMetrics, ListMetricsHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.ExecuteInstantQuery.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.ListMetricGroups.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.ListMetricGroups.Name, opts.toolMetrics, ListMetricGroupsHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.ExecuteInstantQuery.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.ExecuteInstantQuery.Name, opts.toolMetrics, ExecuteInstantQueryHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.ExecuteRangeQuery.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.ExecuteRangeQuery.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.ExecuteRangeQuery.Name, opts.toolMetrics, ExecuteRangeQueryHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.ShowTimeseries.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.ShowTimeseries.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.ShowTimeseries.Name, opts.toolMetrics, ShowTimeseriesHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.GetLabelNames.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.QueryHeatmap.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.QueryHeatmap.Name, opts.toolMetrics, QueryHeatmapHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.LabelCardinalityTrend.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.LabelCardinalityTrend.Name, opts.toolMetrics, LabelCardinalityTrendHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.SLOCompliance.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.SLOCompliance.Name, opts.toolMetrics, SLOComplianceHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.EvaluateCondition.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.EvaluateCondition.Name, opts.toolMetrics, EvaluateConditionHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.ClusterStatus.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.ClusterStatus.Name, opts.toolMetrics, ClusterStatusHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetLabelNames.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.GetLabelNames.Name, opts.toolMetrics, GetLabelNamesHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.GetLabelValues.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.GetLabelValues.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.GetLabelValues.Name, opts.toolMetrics, GetLabelValuesHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.GetSeries.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.GetLabelsOverview.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetLabelsOverview.Name, opts.toolMetrics, GetLabelsOverviewHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetSeries.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.GetSeries.Name, opts.toolMetrics, GetSeriesHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.GetAlerts.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.CheckSeriesUniqueness.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.CheckSeriesUniqueness.Name, opts.toolMetrics, CheckSeriesUniquenessHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.InspectMetric.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.InspectMetric.Name, opts.toolMetrics, InspectMetricHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetMetricMetadata.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetMetricMetadata.Name, opts.toolMetrics, GetMetricMetadataHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetScrapeInterval.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetScrapeInterval.Name, opts.toolMetrics, GetScrapeIntervalHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetExternalLabels.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetExternalLabels.Name, opts.toolMetrics, GetExternalLabelsHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetActiveQueries.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetActiveQueries.Name, opts.toolMetrics, GetActiveQueriesHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.ListRecordingRules.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.ListRecordingRules.Name, opts.toolMetrics, ListRecordingRulesHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetRuleGroup.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetRuleGroup.Name, opts.toolMetrics, GetRuleGroupHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.ListQueryTemplates.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.ListQueryTemplates.Name, opts.toolMetrics, ListQueryTemplatesHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.RenderQueryTemplate.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.RenderQueryTemplate.Name, opts.toolMetrics, RenderQueryTemplateHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.QueryToPanel.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.QueryToPanel.Name, opts.toolMetrics, QueryToPanelHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.ParseTime.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.ParseTime.Name, opts.toolMetrics, ParseTimeHandler(opts)))
+		if opts.Metrics.AllowFileOutput {
+			mcp.AddTool(mcpServer, withDescription(metrics.SaveQueryResult.ToMCPTool(), opts.Metrics),
+				instrumentation.ToolHandler(metrics.SaveQueryResult.Name, opts.toolMetrics, SaveQueryResultHandler(opts)))
+		}
+		mcp.AddTool(mcpServer, withDescription(metrics.GetAlerts.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.GetAlerts.Name, opts.toolMetrics, GetAlertsHandler(opts)))
-		mcp.AddTool(mcpServer, metrics.GetSilences.ToMCPTool(),
+		mcp.AddTool(mcpServer, withDescription(metrics.SummarizeAlerts.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.SummarizeAlerts.Name, opts.toolMetrics, SummarizeAlertsHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetAlertHistory.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetAlertHistory.Name, opts.toolMetrics, GetAlertHistoryHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetAlertThreshold.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetAlertThreshold.Name, opts.toolMetrics, GetAlertThresholdHandler(opts)))
+		mcp.AddTool(mcpServer, withDescription(metrics.GetSilences.ToMCPTool(), opts.Metrics),
 			instrumentation.ToolHandler(metrics.GetSilences.Name, opts.toolMetrics, GetSilencesHandler(opts)))
+		if opts.Metrics.AllowWrites {
+			mcp.AddTool(mcpServer, withDescription(metrics.DeleteSilence.ToMCPTool(), opts.Metrics),
+				instrumentation.ToolHandler(metrics.DeleteSilence.Name, opts.toolMetrics, DeleteSilenceHandler(opts)))
+		}
+		mcp.AddTool(mcpServer, withDescription(metrics.GetAlertmanagerStatus.ToMCPTool(), opts.Metrics),
+			instrumentation.ToolHandler(metrics.GetAlertmanagerStatus.Name, opts.toolMetrics, GetAlertmanagerStatusHandler(opts)))
 	}
 
 	if slices.Contains(opts.Toolsets, traces.ToolsetName) {
@@ -187,6 +259,34 @@ func authMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// guardrailOverrideMiddleware reads guardrail overrides from the given trusted header.
+func guardrailOverrideMiddleware(header string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := auth.ContextWithGuardrailOverrideFromRequest(r.Context(), r, header)
+		r = r.WithContext(ctx)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NowHeader is the request header clients set the time NOW resolves to with, when enabled.
+const NowHeader = "X-Obs-MCP-Now"
+
+// clientNowMiddleware resolves NOW to the time given in the NowHeader request header.
+// Requests without the header use the server time.
+func clientNowMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if value := r.Header.Get(NowHeader); value != "" {
+			now, err := prometheus.ParseTimestamp(r.Context(), value)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s header: %v", NowHeader, err), http.StatusBadRequest)
+				return
+			}
+			r = r.WithContext(prometheus.ContextWithNow(r.Context(), now))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		slog.Info("Incoming request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
@@ -199,8 +299,10 @@ func loggingMiddleware(next http.Handler) http.Handler {
 }
 
 // NewHTTPServer creates an HTTP server for MCP over SSE.
+// Guardrail overrides are read from guardrailHeader, unless it is empty, and the time
+// NOW resolves to from the NowHeader when clientNow is set.
 // Returns the server and a shutdown function to be used with run.Group.
-func NewHTTPServer(mcpServer *mcp.Server, listenAddr string, registry prom.Registerer, authMode auth.AuthMode) (httpServer *http.Server, shutdown func(error)) {
+func NewHTTPServer(mcpServer *mcp.Server, listenAddr string, registry prom.Registerer, authMode auth.AuthMode, guardrailHeader string, clientNow bool) (httpServer *http.Server, shutdown func(error)) {
 	mux := http.NewServeMux()
 
 	var instrMiddleware instrumentation.Middleware
@@ -214,6 +316,13 @@ func NewHTTPServer(mcpServer *mcp.Server, listenAddr string, registry prom.Regis
 	if authMode == auth.AuthModeHeader {
 		handler = authMiddleware(handler)
 	}
+	if guardrailHeader != "" {
+		handler = guardrailOverrideMiddleware(guardrailHeader, handler)
+	}
+	if clientNow {
+		handler = clientNowMiddleware(handler)
+	}
+	handler = instrumentation.TraceContextMiddleware(handler)
 
 	httpServer = &http.Server{
 		Addr:    listenAddr,
@@ -227,8 +336,8 @@ func NewHTTPServer(mcpServer *mcp.Server, listenAddr string, registry prom.Regis
 	streamableHandler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
 		return mcpServer
 	}, opts)
-	mux.Handle(mcpEndpoint, instrMiddleware.NewHandler("mcp", streamableHandler))
-	mux.Handle("/", instrMiddleware.NewHandler("root", streamableHandler))
+	mux.Handle(mcpEndpoint, instrMiddleware.NewHandler("mcp", gzipMiddleware(streamableHandler)))
+	mux.Handle("/", instrMiddleware.NewHandler("root", gzipMiddleware(streamableHandler)))
 
 	mux.HandleFunc(healthEndpoint, func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -2,18 +2,23 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/rhobs/obs-mcp/pkg/auth"
 	"github.com/rhobs/obs-mcp/pkg/metrics"
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
 	"github.com/rhobs/obs-mcp/pkg/otelcol"
 )
 
@@ -77,6 +82,57 @@ func TestAuthMiddleware(t *testing.T) {
 	}
 }
 
+func TestGuardrailOverrideMiddleware(t *testing.T) {
+	var got string
+	inner := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = auth.GuardrailOverrideFromContext(r.Context())
+	})
+	handler := guardrailOverrideMiddleware("X-Policy-Guardrails", inner)
+
+	req := httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Set("X-Policy-Guardrails", " require-label-matcher ")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "require-label-matcher" {
+		t.Errorf("override = %q, want %q", got, "require-label-matcher")
+	}
+
+	req = httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Set(auth.DefaultGuardrailHeader, "all")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "" {
+		t.Errorf("override from another header = %q, want none", got)
+	}
+}
+
+func TestClientNowMiddleware(t *testing.T) {
+	var got time.Time
+	inner := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = prometheus.Now(r.Context())
+	})
+	handler := clientNowMiddleware(inner)
+
+	req := httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Set(NowHeader, "2024-01-01T12:00:00Z")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if want := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("now = %v, want %v", got, want)
+	}
+
+	req = httptest.NewRequest("GET", "/test", http.NoBody)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if time.Since(got) > 2*time.Second {
+		t.Errorf("now without the header = %v, want the server time", got)
+	}
+
+	req = httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Set(NowHeader, "yesterday")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status for an invalid header = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHeaderAuthRejectsUnauthenticatedToolCall(t *testing.T) {
 	kubeClientConfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{
 		Clusters:       map[string]*clientcmdapi.Cluster{"test": {Server: "https://localhost", InsecureSkipTLSVerify: true}},
@@ -134,3 +190,217 @@ func TestHeaderAuthRejectsUnauthenticatedToolCall(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricCatalogResource(t *testing.T) {
+	catalog := []string{"up", "http_requests_total"}
+	listCalls := 0
+	mockClient := &MockedLoader{
+		ListMetricsFunc: func(_ context.Context, _ string) ([]string, error) {
+			listCalls++
+			return catalog, nil
+		},
+	}
+
+	mcpServer, err := NewMCPServer(ObsMCPOptions{
+		Toolsets: []string{metrics.ToolsetName},
+		Metrics:  &metrics.Config{AuthMode: auth.AuthModeKubeConfig},
+	})
+	require.NoError(t, err)
+
+	ctx := withMockClient(context.Background(), mockClient)
+	clientTransport, serverTransport := mcpsdk.NewInMemoryTransports()
+	_, err = mcpServer.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+
+	updated := make(chan string, 1)
+	client := mcpsdk.NewClient(&mcpsdk.Implementation{Name: "test-client", Version: "0.0.1"}, &mcpsdk.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcpsdk.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	for range 2 {
+		res, err := session.ReadResource(context.Background(), &mcpsdk.ReadResourceParams{URI: metricCatalogURI})
+		require.NoError(t, err)
+		require.Len(t, res.Contents, 1)
+		require.JSONEq(t, `{"metrics":["http_requests_total","up"]}`, res.Contents[0].Text)
+	}
+	require.Equal(t, 1, listCalls, "second read should be served from the cached catalog")
+
+	err = session.Subscribe(context.Background(), &mcpsdk.SubscribeParams{URI: metricCatalogURI})
+	require.NoError(t, err)
+
+	catalog = []string{"up", "http_requests_total", "node_load1"}
+	_, err = session.CallTool(context.Background(), &mcpsdk.CallToolParams{
+		Name:      metrics.ListMetrics.Name,
+		Arguments: map[string]any{"name_regex": ".*", "limit": 1},
+	})
+	require.NoError(t, err)
+
+	select {
+	case uri := <-updated:
+		t.Fatalf("unexpected resource updated notification for %s after a limited list", uri)
+	case <-time.After(100 * time.Millisecond):
+	}
+	res, err := session.ReadResource(context.Background(), &mcpsdk.ReadResourceParams{URI: metricCatalogURI})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"metrics":["http_requests_total","up"]}`, res.Contents[0].Text, "a limited list should leave the catalog unchanged")
+
+	_, err = session.CallTool(context.Background(), &mcpsdk.CallToolParams{
+		Name:      metrics.ListMetrics.Name,
+		Arguments: map[string]any{"name_regex": ".*"},
+	})
+	require.NoError(t, err)
+
+	select {
+	case uri := <-updated:
+		require.Equal(t, metricCatalogURI, uri)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a resource updated notification after the catalog changed")
+	}
+
+	res, err = session.ReadResource(context.Background(), &mcpsdk.ReadResourceParams{URI: metricCatalogURI})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"metrics":["http_requests_total","node_load1","up"]}`, res.Contents[0].Text)
+}
+
+func TestToolDescriptionOverrides(t *testing.T) {
+	const description = "Run PromQL range queries. Our metrics are prefixed with the team name."
+
+	mcpServer, err := NewMCPServer(ObsMCPOptions{
+		Toolsets: []string{metrics.ToolsetName},
+		Metrics: &metrics.Config{
+			AuthMode:         auth.AuthModeKubeConfig,
+			ToolDescriptions: map[string]string{metrics.ExecuteRangeQuery.Name: description},
+		},
+	})
+	require.NoError(t, err)
+
+	clientTransport, serverTransport := mcpsdk.NewInMemoryTransports()
+	_, err = mcpServer.Connect(context.Background(), serverTransport, nil)
+	require.NoError(t, err)
+
+	client := mcpsdk.NewClient(&mcpsdk.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	res, err := session.ListTools(context.Background(), nil)
+	require.NoError(t, err)
+
+	descriptions := make(map[string]string)
+	for _, tool := range res.Tools {
+		descriptions[tool.Name] = tool.Description
+	}
+	require.Equal(t, description, descriptions[metrics.ExecuteRangeQuery.Name])
+	require.Equal(t, metrics.ExecuteInstantQuery.Description, descriptions[metrics.ExecuteInstantQuery.Name])
+}
+
+func TestToolAnnotations(t *testing.T) {
+	// Tools with side effects; every other tool must be read-only.
+	writeTools := map[string]struct{ destructive, idempotent bool }{
+		// Results are written to new files and never overwrite existing ones.
+		metrics.SaveQueryResult.Name: {destructive: false, idempotent: false},
+		// Expiring a silence that already expired changes nothing.
+		metrics.DeleteSilence.Name: {destructive: true, idempotent: true},
+	}
+
+	mcpServer, err := NewMCPServer(ObsMCPOptions{
+		Toolsets: []string{metrics.ToolsetName},
+		Metrics: &metrics.Config{
+			AuthMode:        auth.AuthModeKubeConfig,
+			AllowFileOutput: true,
+			FileOutputDir:   t.TempDir(),
+			AllowWrites:     true,
+		},
+	})
+	require.NoError(t, err)
+
+	clientTransport, serverTransport := mcpsdk.NewInMemoryTransports()
+	_, err = mcpServer.Connect(context.Background(), serverTransport, nil)
+	require.NoError(t, err)
+
+	client := mcpsdk.NewClient(&mcpsdk.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	res, err := session.ListTools(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, res.Tools, len(metrics.AllTools())+1) // the version tool
+
+	for _, tool := range res.Tools {
+		annotations := tool.Annotations
+		require.NotNil(t, annotations, "tool %s has no annotations", tool.Name)
+		require.NotNil(t, annotations.DestructiveHint, "tool %s has no destructive hint", tool.Name)
+		require.NotNil(t, annotations.OpenWorldHint, "tool %s has no open world hint", tool.Name)
+		require.NotEmpty(t, annotations.Title, "tool %s has no title", tool.Name)
+
+		write, ok := writeTools[tool.Name]
+		require.Equal(t, !ok, annotations.ReadOnlyHint, "read-only hint of tool %s", tool.Name)
+		require.Equal(t, write.destructive, *annotations.DestructiveHint, "destructive hint of tool %s", tool.Name)
+		if ok {
+			require.Equal(t, write.idempotent, annotations.IdempotentHint, "idempotent hint of tool %s", tool.Name)
+		}
+	}
+}
+
+func TestGetAlertsProgress(t *testing.T) {
+	mockClient := &MockedAlertmanagerLoader{
+		GetAlertsFunc: func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
+			return manyAlerts(120), nil
+		},
+	}
+
+	mcpServer, err := NewMCPServer(ObsMCPOptions{
+		Toolsets: []string{metrics.ToolsetName},
+		Metrics:  &metrics.Config{AuthMode: auth.AuthModeKubeConfig},
+	})
+	require.NoError(t, err)
+
+	ctx := withMockAlertmanagerClient(context.Background(), mockClient)
+	clientTransport, serverTransport := mcpsdk.NewInMemoryTransports()
+	_, err = mcpServer.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var progress []float64
+	var streamed []metrics.Alert
+	client := mcpsdk.NewClient(&mcpsdk.Implementation{Name: "test-client", Version: "0.0.1"}, &mcpsdk.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcpsdk.ProgressNotificationClientRequest) {
+			var batch []metrics.Alert
+			require.NoError(t, json.Unmarshal([]byte(req.Params.Message), &batch))
+			mu.Lock()
+			defer mu.Unlock()
+			progress = append(progress, req.Params.Progress)
+			streamed = append(streamed, batch...)
+		},
+	})
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	params := &mcpsdk.CallToolParams{
+		Name:      metrics.GetAlerts.Name,
+		Arguments: map[string]any{"full": true},
+	}
+	params.SetProgressToken("alerts")
+	res, err := session.CallTool(context.Background(), params)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(progress) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []float64{50, 100, 120}, progress)
+	require.Len(t, streamed, 120)
+	require.Equal(t, "Alert0", streamed[0].Labels["alertname"])
+	require.Equal(t, "Alert119", streamed[119].Labels["alertname"])
+}
@@ -41,7 +41,7 @@ func GroupedTools() []ToolGroup {
 	var promTools, alertTools []mcp.Tool
 	for _, t := range toMCP(promDefs) {
 		switch t.Name {
-		case "get_alerts", "get_silences":
+		case "get_alerts", "get_silences", "delete_silence", "get_alertmanager_status":
 			alertTools = append(alertTools, t)
 		default:
 			promTools = append(promTools, t)
@@ -54,6 +54,7 @@ func GroupedTools() []ToolGroup {
 		{Name: "Tempo (Distributed Tracing)", Icon: "🔍", Tools: toolsetToMCPTools(&traces.Toolset{})},
 		{Name: "Loki (Log Management)", Icon: "📋", Tools: toolsetToMCPTools(&logs.Toolset{})},
 		{Name: "OpenTelemetry Collector", Icon: "⚙️", Tools: toolsetToMCPTools(&otelcol.Toolset{})},
+		{Name: "Server", Icon: "ℹ️", Tools: []mcp.Tool{*Version.ToMCPTool()}},
 	}
 }
 
@@ -62,6 +63,10 @@ func CreateListMetricsTool() mcp.Tool {
 	return *tools.ListMetrics.ToMCPTool()
 }
 
+func CreateListMetricGroupsTool() mcp.Tool {
+	return *tools.ListMetricGroups.ToMCPTool()
+}
+
 func CreateExecuteInstantQueryTool() mcp.Tool {
 	return *tools.ExecuteInstantQuery.ToMCPTool()
 }
@@ -75,6 +80,26 @@ func CreateShowTimeseriesTool() mcp.Tool {
 	return *tools.ShowTimeseries.ToMCPTool()
 }
 
+func CreateQueryHeatmapTool() mcp.Tool {
+	return *tools.QueryHeatmap.ToMCPTool()
+}
+
+func CreateLabelCardinalityTrendTool() mcp.Tool {
+	return *tools.LabelCardinalityTrend.ToMCPTool()
+}
+
+func CreateSLOComplianceTool() mcp.Tool {
+	return *tools.SLOCompliance.ToMCPTool()
+}
+
+func CreateEvaluateConditionTool() mcp.Tool {
+	return *tools.EvaluateCondition.ToMCPTool()
+}
+
+func CreateClusterStatusTool() mcp.Tool {
+	return *tools.ClusterStatus.ToMCPTool()
+}
+
 func CreateGetLabelNamesTool() mcp.Tool {
 	return *tools.GetLabelNames.ToMCPTool()
 }
@@ -83,18 +108,100 @@ func CreateGetLabelValuesTool() mcp.Tool {
 	return *tools.GetLabelValues.ToMCPTool()
 }
 
+func CreateGetLabelsOverviewTool() mcp.Tool {
+	return *tools.GetLabelsOverview.ToMCPTool()
+}
+
 func CreateGetSeriesTool() mcp.Tool {
 	return *tools.GetSeries.ToMCPTool()
 }
 
+func CreateCheckSeriesUniquenessTool() mcp.Tool {
+	return *tools.CheckSeriesUniqueness.ToMCPTool()
+}
+
+func CreateInspectMetricTool() mcp.Tool {
+	return *tools.InspectMetric.ToMCPTool()
+}
+
+func CreateGetMetricMetadataTool() mcp.Tool {
+	return *tools.GetMetricMetadata.ToMCPTool()
+}
+
+func CreateGetScrapeIntervalTool() mcp.Tool {
+	return *tools.GetScrapeInterval.ToMCPTool()
+}
+
+func CreateGetExternalLabelsTool() mcp.Tool {
+	return *tools.GetExternalLabels.ToMCPTool()
+}
+
+func CreateGetActiveQueriesTool() mcp.Tool {
+	return *tools.GetActiveQueries.ToMCPTool()
+}
+
+func CreateListRecordingRulesTool() mcp.Tool {
+	return *tools.ListRecordingRules.ToMCPTool()
+}
+
+func CreateGetRuleGroupTool() mcp.Tool {
+	return *tools.GetRuleGroup.ToMCPTool()
+}
+
+func CreateListQueryTemplatesTool() mcp.Tool {
+	return *tools.ListQueryTemplates.ToMCPTool()
+}
+
+func CreateRenderQueryTemplateTool() mcp.Tool {
+	return *tools.RenderQueryTemplate.ToMCPTool()
+}
+
+func CreateQueryToPanelTool() mcp.Tool {
+	return *tools.QueryToPanel.ToMCPTool()
+}
+
+func CreateParseTimeTool() mcp.Tool {
+	return *tools.ParseTime.ToMCPTool()
+}
+
+func CreateSaveQueryResultTool() mcp.Tool {
+	return *tools.SaveQueryResult.ToMCPTool()
+}
+
 func CreateGetAlertsTool() mcp.Tool {
 	return *tools.GetAlerts.ToMCPTool()
 }
 
+func CreateSummarizeAlertsTool() mcp.Tool {
+	return *tools.SummarizeAlerts.ToMCPTool()
+}
+
+func CreateGetAlertHistoryTool() mcp.Tool {
+	return *tools.GetAlertHistory.ToMCPTool()
+}
+
+func CreateGetAlertThresholdTool() mcp.Tool {
+	return *tools.GetAlertThreshold.ToMCPTool()
+}
+
 func CreateGetSilencesTool() mcp.Tool {
 	return *tools.GetSilences.ToMCPTool()
 }
 
+func CreateDeleteSilenceTool() mcp.Tool {
+	return *tools.DeleteSilence.ToMCPTool()
+}
+
+func CreateGetAlertmanagerStatusTool() mcp.Tool {
+	return *tools.GetAlertmanagerStatus.ToMCPTool()
+}
+
+// withDescription applies the configured description override, if any, to a metrics tool.
+func withDescription(tool *mcp.Tool, cfg *tools.Config) *mcp.Tool {
+	tool.Description = cfg.GetToolDescription(tool.Name, tool.Description)
+	return tool
+}
+
 // toolsetToMCPTools converts a Toolset's tools to mcp.Tool for documentation generation.
 // TODO: remove once all toolsets are converted to the Toolset API.
 func toolsetToMCPTools(ts api.Toolset) []mcp.Tool {
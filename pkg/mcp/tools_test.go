@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
 
 	tools "github.com/rhobs/obs-mcp/pkg/metrics"
 )
@@ -168,6 +169,133 @@ func TestSeriesResultSerialization(t *testing.T) {
 	}
 }
 
+func TestLabelNamesOutputSerialization(t *testing.T) {
+	tests := []struct {
+		name  string
+		input tools.LabelNamesOutput
+	}{
+		{
+			name:  "empty",
+			input: tools.LabelNamesOutput{Labels: []string{}},
+		},
+		{
+			name:  "labels only",
+			input: tools.LabelNamesOutput{Labels: []string{"__name__", "instance", "job"}},
+		},
+		{
+			name: "with value counts",
+			input: tools.LabelNamesOutput{
+				Labels:      []string{"instance", "job"},
+				ValueCounts: map[string]uint64{"instance": 42, "job": 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+
+			var result tools.LabelNamesOutput
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			require.Equal(t, tt.input, result)
+		})
+	}
+}
+
+func TestLabelValuesOutputSerialization(t *testing.T) {
+	tests := []struct {
+		name  string
+		input tools.LabelValuesOutput
+	}{
+		{
+			name:  "empty",
+			input: tools.LabelValuesOutput{Values: []string{}},
+		},
+		{
+			name:  "with total count",
+			input: tools.LabelValuesOutput{Values: []string{"api", "db"}, TotalCount: 2},
+		},
+		{
+			name:  "truncated",
+			input: tools.LabelValuesOutput{Values: []string{"api"}, Truncated: true},
+		},
+		{
+			name: "with frequencies",
+			input: tools.LabelValuesOutput{
+				Values:      []string{"api", "db"},
+				TotalCount:  2,
+				Frequencies: []tools.LabelValueFrequency{{Value: "api", SeriesCount: 10}, {Value: "db", SeriesCount: 2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+
+			var result tools.LabelValuesOutput
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			require.Equal(t, tt.input, result)
+		})
+	}
+}
+
+func TestSeriesOutputSerialization(t *testing.T) {
+	tests := []struct {
+		name  string
+		input tools.SeriesOutput
+	}{
+		{
+			name:  "empty",
+			input: tools.SeriesOutput{Series: []map[string]string{}},
+		},
+		{
+			name: "multiple series",
+			input: tools.SeriesOutput{
+				Series: []map[string]string{
+					{"__name__": "up", "job": "api", "instance": "a:9090"},
+					{"__name__": "up", "job": "api", "instance": "b:9090"},
+				},
+				Cardinality: 2,
+			},
+		},
+		{
+			name: "truncated with last seen",
+			input: tools.SeriesOutput{
+				Series:      []map[string]string{{"__name__": "up", "job": "api"}},
+				Cardinality: 5,
+				Truncated:   true,
+				LastSeen:    []string{"2m ago"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+
+			var result tools.SeriesOutput
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			require.Equal(t, tt.input, result)
+		})
+	}
+}
+
 func TestToolParameters(t *testing.T) {
 	tests := []struct {
 		tool             mcp.Tool
@@ -181,8 +309,8 @@ func TestToolParameters(t *testing.T) {
 		},
 		{
 			tool:             CreateExecuteRangeQueryTool(),
-			expectedRequired: []string{"query", "step"},
-			expectedOptional: []string{"start", "end", "duration"},
+			expectedRequired: []string{"query"},
+			expectedOptional: []string{"step", "target_points", "start", "end", "duration"},
 		},
 	}
 
@@ -267,8 +395,8 @@ func TestToolPatternValidation(t *testing.T) {
 				{
 					param:         "step",
 					hasPattern:    true,
-					validInputs:   []string{"1s", "30s", "1m", "5m", "1h", "24h", "1d", "7d", "1w", "2w"},
-					invalidInputs: []string{"", "1", "s", "1x", "1.5m", "1m30s", "invalid"},
+					validInputs:   []string{"1s", "30s", "1m", "5m", "1h", "24h", "1d", "7d", "1w", "2w", "1", "60", "0.5"},
+					invalidInputs: []string{"", "s", "1x", "1.5m", "1m30s", "invalid", "-60", "1e3"},
 				},
 				{
 					param:         "duration",
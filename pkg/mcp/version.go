@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"runtime"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/common/version"
+
+	"github.com/rhobs/obs-mcp/pkg/logs"
+	tools "github.com/rhobs/obs-mcp/pkg/metrics"
+	"github.com/rhobs/obs-mcp/pkg/traces"
+)
+
+// VersionOutput defines the output schema for the version tool.
+type VersionOutput struct {
+	ServerName    string   `json:"serverName" jsonschema:"Name of the MCP server"`
+	ServerVersion string   `json:"serverVersion" jsonschema:"Version of the server build"`
+	Commit        string   `json:"commit,omitempty" jsonschema:"Git commit the server was built from"`
+	Branch        string   `json:"branch,omitempty" jsonschema:"Git branch the server was built from"`
+	BuildDate     string   `json:"buildDate,omitempty" jsonschema:"Date the server was built"`
+	GoVersion     string   `json:"goVersion" jsonschema:"Go version the server was built with"`
+	Toolsets      []string `json:"toolsets" jsonschema:"Enabled toolsets"`
+	Backends      []string `json:"backends" jsonschema:"Backends the enabled tools query, e.g. prometheus, alertmanager, tempo or loki"`
+	Guardrails    string   `json:"guardrails,omitempty" jsonschema:"Configured query guardrails"`
+	Features      []string `json:"features,omitempty" jsonschema:"Enabled optional features"`
+}
+
+// VersionInput defines the input parameters for the version tool.
+type VersionInput struct{}
+
+// Version describes the server build and the enabled features.
+var Version = tools.ToolDef[VersionOutput]{
+	Name: "version",
+	Description: `Get the version of the obs-mcp server and the features it has enabled.
+
+Use it to confirm which build is deployed, or to check why a tool or backend is not available.`,
+	Title:       "Server Version",
+	ReadOnly:    true,
+	Destructive: false,
+	Idempotent:  true,
+	OpenWorld:   false,
+	Params:      []tools.ParamDef{},
+}
+
+// buildVersion returns the version injected at build time, falling back to serverVersion
+// for builds without one (e.g. go run).
+func buildVersion() string {
+	return cmp.Or(version.Version, serverVersion)
+}
+
+// VersionHandler handles the version tool.
+func VersionHandler(opts ObsMCPOptions) mcp.ToolHandlerFor[VersionInput, VersionOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input VersionInput) (*mcp.CallToolResult, VersionOutput, error) {
+		return nil, versionInfo(opts), nil
+	}
+}
+
+func versionInfo(opts ObsMCPOptions) VersionOutput {
+	output := VersionOutput{
+		ServerName:    serverName,
+		ServerVersion: buildVersion(),
+		Commit:        version.GetRevision(),
+		Branch:        version.Branch,
+		BuildDate:     version.BuildDate,
+		GoVersion:     runtime.Version(),
+		Toolsets:      slices.Clone(opts.Toolsets),
+		Backends:      []string{},
+	}
+
+	if slices.Contains(opts.Toolsets, tools.ToolsetName) && opts.Metrics != nil {
+		output.Backends = append(output.Backends, "prometheus")
+		if opts.Metrics.AlertmanagerURL != "" {
+			output.Backends = append(output.Backends, "alertmanager")
+		}
+		output.Guardrails = cmp.Or(opts.Metrics.Guardrails, "all")
+
+		for _, f := range []struct {
+			name    string
+			enabled bool
+		}{
+			{"full-range-query-response", opts.Metrics.RangeQueryFullResponse},
+			{"split-range-queries", opts.Metrics.SplitRangeQueries},
+			{"file-output", opts.Metrics.AllowFileOutput},
+			{"writes", opts.Metrics.AllowWrites},
+			{"log-queries", opts.Metrics.LogQueries},
+			{"trust-guardrail-header", opts.Metrics.TrustGuardrailHeader},
+			{"client-now", opts.Metrics.AllowClientNow},
+		} {
+			if f.enabled {
+				output.Features = append(output.Features, f.name)
+			}
+		}
+	}
+	if slices.Contains(opts.Toolsets, traces.ToolsetName) {
+		output.Backends = append(output.Backends, "tempo")
+	}
+	if slices.Contains(opts.Toolsets, logs.ToolsetName) {
+		output.Backends = append(output.Backends, "loki")
+	}
+	return output
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/common/version"
+
+	tools "github.com/rhobs/obs-mcp/pkg/metrics"
+	"github.com/rhobs/obs-mcp/pkg/traces"
+)
+
+func TestVersionHandler(t *testing.T) {
+	originalVersion, originalRevision := version.Version, version.Revision
+	version.Version, version.Revision = "1.2.3", "abc1234"
+	t.Cleanup(func() {
+		version.Version, version.Revision = originalVersion, originalRevision
+	})
+
+	opts := ObsMCPOptions{
+		Toolsets: []string{tools.ToolsetName, traces.ToolsetName},
+		Metrics: &tools.Config{
+			AlertmanagerURL:   "https://alertmanager.example.com",
+			Guardrails:        "require-label-matcher",
+			SplitRangeQueries: true,
+		},
+	}
+	req := newMockRequest(nil)
+	_, output, err := VersionHandler(opts)(context.Background(), &req, VersionInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.ServerName != "obs-mcp" || output.ServerVersion != "1.2.3" || output.Commit != "abc1234" {
+		t.Errorf("unexpected build info: %+v", output)
+	}
+	if want := []string{"prometheus", "alertmanager", "tempo"}; !slices.Equal(output.Backends, want) {
+		t.Errorf("backends = %v, want %v", output.Backends, want)
+	}
+	if output.Guardrails != "require-label-matcher" {
+		t.Errorf("guardrails = %q, want %q", output.Guardrails, "require-label-matcher")
+	}
+	if want := []string{"split-range-queries"}; !slices.Equal(output.Features, want) {
+		t.Errorf("features = %v, want %v", output.Features, want)
+	}
+
+	version.Version = ""
+	if got := buildVersion(); got != serverVersion {
+		t.Errorf("buildVersion() without an injected version = %q, want %q", got, serverVersion)
+	}
+}
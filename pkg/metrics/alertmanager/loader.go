@@ -2,6 +2,7 @@ package alertmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,8 +11,10 @@ import (
 	"time"
 
 	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
 	"github.com/prometheus/alertmanager/api/v2/client"
 	"github.com/prometheus/alertmanager/api/v2/client/alert"
+	"github.com/prometheus/alertmanager/api/v2/client/general"
 	"github.com/prometheus/alertmanager/api/v2/client/silence"
 	"github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/client_golang/api"
@@ -21,8 +24,13 @@ import (
 type Loader interface {
 	GetAlerts(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error)
 	GetSilences(ctx context.Context, filter []string) (models.GettableSilences, error)
+	GetStatus(ctx context.Context) (*models.AlertmanagerStatus, error)
+	DeleteSilence(ctx context.Context, id string) error
 }
 
+// ErrSilenceNotFound is returned by DeleteSilence when no silence has the given ID.
+var ErrSilenceNotFound = errors.New("silence not found")
+
 // RealLoader implements Loader
 type RealLoader struct {
 	client *client.AlertmanagerAPI
@@ -120,3 +128,44 @@ func (a *RealLoader) GetSilences(ctx context.Context, filter []string) (models.G
 
 	return resp.Payload, nil
 }
+
+func (a *RealLoader) GetStatus(ctx context.Context) (*models.AlertmanagerStatus, error) {
+	params := general.NewGetStatusParams().WithContext(ctx)
+
+	start := time.Now()
+	resp, err := a.client.General.GetStatus(params)
+	duration := time.Since(start)
+	if err != nil {
+		slog.Error("Backend call failed", "backend", "alertmanager", "operation", "status",
+			"duration_ms", duration.Milliseconds(), "error", err)
+		return nil, fmt.Errorf("error fetching status: %w", err)
+	}
+	slog.Debug("Backend call completed", "backend", "alertmanager", "operation", "status",
+		"duration_ms", duration.Milliseconds())
+	return resp.Payload, nil
+}
+
+// DeleteSilence expires the silence with the given ID. Alertmanager keeps expired
+// silences for a while, so they are still listed by GetSilences.
+func (a *RealLoader) DeleteSilence(ctx context.Context, id string) error {
+	if !strfmt.IsUUID(id) {
+		return fmt.Errorf("invalid silence ID %q: must be a UUID as returned by get_silences", id)
+	}
+	params := silence.NewDeleteSilenceParams().WithContext(ctx).WithSilenceID(strfmt.UUID(id))
+
+	start := time.Now()
+	_, err := a.client.Silence.DeleteSilence(params)
+	duration := time.Since(start)
+	if err != nil {
+		slog.Error("Backend call failed", "backend", "alertmanager", "operation", "delete_silence",
+			"duration_ms", duration.Milliseconds(), "error", err)
+		var notFound *silence.DeleteSilenceNotFound
+		if errors.As(err, &notFound) {
+			return fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
+		}
+		return fmt.Errorf("error deleting silence: %w", err)
+	}
+	slog.Debug("Backend call completed", "backend", "alertmanager", "operation", "delete_silence",
+		"duration_ms", duration.Milliseconds())
+	return nil
+}
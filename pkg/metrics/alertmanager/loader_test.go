@@ -2,15 +2,21 @@ package alertmanager
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/client_golang/api"
 )
 
 // mockAlertmanagerAPI is a mock implementation of the Alertmanager Loader interface
 type mockAlertmanagerAPI struct {
-	getAlertsFunc   func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error)
-	getSilencesFunc func(ctx context.Context, filter []string) (models.GettableSilences, error)
+	getAlertsFunc     func(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error)
+	getSilencesFunc   func(ctx context.Context, filter []string) (models.GettableSilences, error)
+	getStatusFunc     func(ctx context.Context) (*models.AlertmanagerStatus, error)
+	deleteSilenceFunc func(ctx context.Context, id string) error
 }
 
 func (m *mockAlertmanagerAPI) GetAlerts(ctx context.Context, active, silenced, inhibited, unprocessed *bool, filter []string, receiver string) (models.GettableAlerts, error) {
@@ -27,6 +33,20 @@ func (m *mockAlertmanagerAPI) GetSilences(ctx context.Context, filter []string)
 	return models.GettableSilences{}, nil
 }
 
+func (m *mockAlertmanagerAPI) GetStatus(ctx context.Context) (*models.AlertmanagerStatus, error) {
+	if m.getStatusFunc != nil {
+		return m.getStatusFunc(ctx)
+	}
+	return &models.AlertmanagerStatus{}, nil
+}
+
+func (m *mockAlertmanagerAPI) DeleteSilence(ctx context.Context, id string) error {
+	if m.deleteSilenceFunc != nil {
+		return m.deleteSilenceFunc(ctx, id)
+	}
+	return nil
+}
+
 // Ensure mockAlertmanagerAPI implements Loader at compile time
 var _ Loader = (*mockAlertmanagerAPI)(nil)
 
@@ -285,3 +305,49 @@ func TestGetSilences(t *testing.T) {
 		}
 	})
 }
+
+func TestDeleteSilence(t *testing.T) {
+	const id = "4d4c7a8e-3f1b-4c5e-9d2a-1b6f0e8c7a21"
+
+	var method, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		if path != "/api/v2/silence/"+id {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	loader, err := NewAlertmanagerClient(api.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("Delete existing silence", func(t *testing.T) {
+		if err := loader.DeleteSilence(t.Context(), id); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if method != http.MethodDelete || path != "/api/v2/silence/"+id {
+			t.Errorf("request = %s %s, want DELETE /api/v2/silence/%s", method, path, id)
+		}
+	})
+
+	t.Run("Delete unknown silence", func(t *testing.T) {
+		err := loader.DeleteSilence(t.Context(), "9b2e6f0a-7c3d-4e1f-8a5b-2c4d6e8f0a1b")
+		if !errors.Is(err, ErrSilenceNotFound) {
+			t.Errorf("error = %v, want %v", err, ErrSilenceNotFound)
+		}
+	})
+
+	t.Run("Invalid silence ID", func(t *testing.T) {
+		method, path = "", ""
+		if err := loader.DeleteSilence(t.Context(), "not-a-uuid"); err == nil {
+			t.Error("expected error for invalid silence ID, got nil")
+		}
+		if method != "" {
+			t.Errorf("expected no request for invalid silence ID, got %s %s", method, path)
+		}
+	})
+}
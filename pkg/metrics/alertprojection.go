@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// allAlertFields keeps all labels or annotations of alerts when given instead of names.
+const allAlertFields = "*"
+
+// AlertProjection lists the labels and annotations get_alerts returns of each alert.
+// A nil list keeps them all.
+type AlertProjection struct {
+	Labels      []string
+	Annotations []string
+}
+
+// ParseAlertFields parses a comma-separated list of alert label or annotation names,
+// returning nil for an empty list.
+func ParseAlertFields(value string) []string {
+	var names []string
+	for name := range strings.SplitSeq(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// validateAlertFields checks that names are label names, or the single "*" keeping all
+// of them.
+func validateAlertFields(names []string, param string) error {
+	if len(names) == 1 && names[0] == allAlertFields {
+		return nil
+	}
+	for _, name := range names {
+		if !labelNameRe.MatchString(name) {
+			return fmt.Errorf("invalid name %q in %s: expected label names, or [%q] to keep all", name, param, allAlertFields)
+		}
+	}
+	return nil
+}
+
+// Validate checks the label and annotation names of the projection.
+func (p AlertProjection) Validate() error {
+	if err := validateAlertFields(p.Labels, "alert_labels"); err != nil {
+		return err
+	}
+	return validateAlertFields(p.Annotations, "alert_annotations")
+}
+
+// override returns the projection with the labels and annotations requested by a call
+// replacing the configured ones.
+func (p AlertProjection) override(input AlertsInput) (AlertProjection, error) {
+	if len(input.Labels) > 0 {
+		if err := validateAlertFields(input.Labels, "labels"); err != nil {
+			return AlertProjection{}, err
+		}
+		p.Labels = input.Labels
+	}
+	if len(input.Annotations) > 0 {
+		if err := validateAlertFields(input.Annotations, "annotations"); err != nil {
+			return AlertProjection{}, err
+		}
+		p.Annotations = input.Annotations
+	}
+	return p, nil
+}
+
+// apply keeps only the labels and annotations of the projection in alert.
+func (p AlertProjection) apply(alert Alert) Alert {
+	alert.Labels = projectAlertFields(alert.Labels, p.Labels)
+	alert.Annotations = projectAlertFields(alert.Annotations, p.Annotations)
+	return alert
+}
+
+func projectAlertFields(fields map[string]string, names []string) map[string]string {
+	if names == nil || (len(names) == 1 && names[0] == allAlertFields) {
+		return fields
+	}
+	projected := make(map[string]string, len(names))
+	for _, name := range names {
+		if value, ok := fields[name]; ok {
+			projected[name] = value
+		}
+	}
+	return projected
+}
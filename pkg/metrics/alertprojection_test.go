@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAlertFields(t *testing.T) {
+	if got := ParseAlertFields(""); got != nil {
+		t.Errorf("ParseAlertFields(\"\") = %v, want nil", got)
+	}
+	if got, want := ParseAlertFields(" alertname, severity,,namespace "), []string{"alertname", "severity", "namespace"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAlertFields() = %v, want %v", got, want)
+	}
+}
+
+func TestAlertProjection(t *testing.T) {
+	alert := Alert{
+		Labels:      map[string]string{"alertname": "HighCPU", "severity": "critical", "namespace": "web", "pod": "web-0", "prometheus": "openshift-monitoring/k8s"},
+		Annotations: map[string]string{"summary": "CPU is high", "description": "CPU usage of web-0 is above 90%", "runbook_url": "https://runbooks.example.com/HighCPU"},
+		StartsAt:    "2024-01-01T12:00:00Z",
+	}
+	configured := AlertProjection{Labels: []string{"alertname", "severity", "namespace"}, Annotations: []string{"summary"}}
+
+	tests := []struct {
+		name            string
+		projection      AlertProjection
+		input           AlertsInput
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+		wantErr         string
+	}{
+		{
+			name:            "nothing configured",
+			wantLabels:      alert.Labels,
+			wantAnnotations: alert.Annotations,
+		},
+		{
+			name:            "configured allowlists",
+			projection:      configured,
+			wantLabels:      map[string]string{"alertname": "HighCPU", "severity": "critical", "namespace": "web"},
+			wantAnnotations: map[string]string{"summary": "CPU is high"},
+		},
+		{
+			name:            "call overrides the labels",
+			projection:      configured,
+			input:           AlertsInput{Labels: []string{"alertname", "pod", "missing"}},
+			wantLabels:      map[string]string{"alertname": "HighCPU", "pod": "web-0"},
+			wantAnnotations: map[string]string{"summary": "CPU is high"},
+		},
+		{
+			name:            "call requests all annotations",
+			projection:      configured,
+			input:           AlertsInput{Annotations: []string{"*"}},
+			wantLabels:      map[string]string{"alertname": "HighCPU", "severity": "critical", "namespace": "web"},
+			wantAnnotations: alert.Annotations,
+		},
+		{
+			name:    "invalid label name",
+			input:   AlertsInput{Labels: []string{"alert-name"}},
+			wantErr: `invalid name "alert-name" in labels`,
+		},
+		{
+			name:    "wildcard among names",
+			input:   AlertsInput{Annotations: []string{"summary", "*"}},
+			wantErr: `invalid name "*" in annotations`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projection, err := tt.projection.override(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := projection.apply(alert)
+			if !reflect.DeepEqual(got.Labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", got.Labels, tt.wantLabels)
+			}
+			if !reflect.DeepEqual(got.Annotations, tt.wantAnnotations) {
+				t.Errorf("annotations = %v, want %v", got.Annotations, tt.wantAnnotations)
+			}
+			if got.StartsAt != alert.StartsAt {
+				t.Errorf("startsAt = %q, want %q", got.StartsAt, alert.StartsAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+
+	ammodels "github.com/prometheus/alertmanager/api/v2/models"
+)
+
+const (
+	// defaultTopAlerts is the number of alerts summarize_alerts details when 'top' is not set.
+	defaultTopAlerts = 10
+	// maxAlertSummaryGroups caps the number of values listed per grouping label,
+	// keeping the largest groups.
+	maxAlertSummaryGroups = 25
+	// maxTopAlertNamespaces caps the namespaces listed for each top alert.
+	maxTopAlertNamespaces = 5
+)
+
+// severityRanks orders the conventional severity label values, most severe first.
+// Other values rank after them.
+var severityRanks = map[string]int{"critical": 0, "error": 1, "warning": 2, "info": 3, "none": 4}
+
+func severityRank(severity string) int {
+	if rank, ok := severityRanks[severity]; ok {
+		return rank
+	}
+	return len(severityRanks)
+}
+
+// summarizeAlerts counts alerts per severity, namespace and alert name, and details the
+// top alert names, most severe and then most frequent first.
+func summarizeAlerts(alerts ammodels.GettableAlerts, top int) AlertsSummaryOutput {
+	bySeverity := make(map[string]int)
+	byNamespace := make(map[string]int)
+	byAlertname := make(map[string]int)
+	topAlerts := make(map[string]*TopAlert)
+	for _, alert := range alerts {
+		severity := alert.Labels["severity"]
+		namespace := alert.Labels["namespace"]
+		name := alert.Labels["alertname"]
+		bySeverity[severity]++
+		byNamespace[namespace]++
+		byAlertname[name]++
+
+		t, ok := topAlerts[name]
+		if !ok {
+			t = &TopAlert{Alertname: name, Severity: severity, Summary: alert.Annotations["summary"]}
+			topAlerts[name] = t
+		}
+		t.Count++
+		if severityRank(severity) < severityRank(t.Severity) {
+			t.Severity = severity
+		}
+		if namespace != "" && !slices.Contains(t.Namespaces, namespace) && len(t.Namespaces) < maxTopAlertNamespaces {
+			t.Namespaces = append(t.Namespaces, namespace)
+		}
+		if alert.StartsAt != nil {
+			startsAt := time.Time(*alert.StartsAt).UTC().Format(time.RFC3339)
+			if t.Since == "" || startsAt < t.Since {
+				t.Since = startsAt
+			}
+		}
+	}
+
+	output := AlertsSummaryOutput{
+		Total:       len(alerts),
+		BySeverity:  sortedAlertCounts(bySeverity, func(a, b AlertCount) int { return cmp.Compare(severityRank(a.Value), severityRank(b.Value)) }),
+		ByNamespace: sortedAlertCounts(byNamespace, nil),
+		ByAlertname: sortedAlertCounts(byAlertname, nil),
+	}
+
+	ranked := slices.SortedFunc(maps.Values(topAlerts), func(a, b *TopAlert) int {
+		return cmp.Or(
+			cmp.Compare(severityRank(a.Severity), severityRank(b.Severity)),
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Alertname, b.Alertname),
+		)
+	})
+	output.TopAlerts = make([]TopAlert, 0, min(len(ranked), top))
+	for _, t := range ranked[:min(len(ranked), top)] {
+		slices.Sort(t.Namespaces)
+		output.TopAlerts = append(output.TopAlerts, *t)
+	}
+	return output
+}
+
+// sortedAlertCounts returns the largest maxAlertSummaryGroups counts, ordered by
+// order if set, then by count, most first.
+func sortedAlertCounts(counts map[string]int, order func(a, b AlertCount) int) []AlertCount {
+	result := make([]AlertCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, AlertCount{Value: value, Count: count})
+	}
+	slices.SortFunc(result, func(a, b AlertCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+	})
+	result = result[:min(len(result), maxAlertSummaryGroups)]
+	if order != nil {
+		slices.SortStableFunc(result, order)
+	}
+	return result
+}
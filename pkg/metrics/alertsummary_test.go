@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	ammodels "github.com/prometheus/alertmanager/api/v2/models"
+)
+
+func TestSummarizeAlerts(t *testing.T) {
+	alert := func(labels map[string]string, summary string, startsAt time.Time) *ammodels.GettableAlert {
+		ts := strfmt.DateTime(startsAt)
+		return &ammodels.GettableAlert{
+			Alert:       ammodels.Alert{Labels: labels},
+			Annotations: ammodels.LabelSet{"summary": summary},
+			StartsAt:    &ts,
+		}
+	}
+	base := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	alerts := ammodels.GettableAlerts{
+		alert(map[string]string{"alertname": "KubePodCrashLooping", "severity": "warning", "namespace": "web"}, "Pod is crash looping.", base),
+		alert(map[string]string{"alertname": "KubePodCrashLooping", "severity": "warning", "namespace": "api"}, "Pod is crash looping.", base.Add(-time.Hour)),
+		alert(map[string]string{"alertname": "KubePodCrashLooping", "severity": "warning", "namespace": "web"}, "Pod is crash looping.", base),
+		alert(map[string]string{"alertname": "TargetDown", "severity": "critical", "namespace": "api"}, "Targets are down.", base),
+		alert(map[string]string{"alertname": "Watchdog", "severity": "none"}, "", base),
+		alert(map[string]string{"alertname": "InfoInhibitor", "severity": "info", "namespace": "web"}, "", base),
+	}
+
+	got := summarizeAlerts(alerts, 2)
+
+	if got.Total != 6 {
+		t.Errorf("total = %d, want 6", got.Total)
+	}
+	wantSeverity := []AlertCount{{"critical", 1}, {"warning", 3}, {"info", 1}, {"none", 1}}
+	if !reflect.DeepEqual(got.BySeverity, wantSeverity) {
+		t.Errorf("bySeverity = %v, want %v", got.BySeverity, wantSeverity)
+	}
+	wantNamespace := []AlertCount{{"web", 3}, {"api", 2}, {"", 1}}
+	if !reflect.DeepEqual(got.ByNamespace, wantNamespace) {
+		t.Errorf("byNamespace = %v, want %v", got.ByNamespace, wantNamespace)
+	}
+	wantAlertname := []AlertCount{{"KubePodCrashLooping", 3}, {"InfoInhibitor", 1}, {"TargetDown", 1}, {"Watchdog", 1}}
+	if !reflect.DeepEqual(got.ByAlertname, wantAlertname) {
+		t.Errorf("byAlertname = %v, want %v", got.ByAlertname, wantAlertname)
+	}
+	wantTop := []TopAlert{
+		{Alertname: "TargetDown", Severity: "critical", Count: 1, Namespaces: []string{"api"}, Since: "2024-01-02T15:00:00Z", Summary: "Targets are down."},
+		{Alertname: "KubePodCrashLooping", Severity: "warning", Count: 3, Namespaces: []string{"api", "web"}, Since: "2024-01-02T14:00:00Z", Summary: "Pod is crash looping."},
+	}
+	if !reflect.DeepEqual(got.TopAlerts, wantTop) {
+		t.Errorf("topAlerts = %+v, want %+v", got.TopAlerts, wantTop)
+	}
+}
+
+func TestSummarizeAlerts_Empty(t *testing.T) {
+	got := summarizeAlerts(nil, defaultTopAlerts)
+	if got.Total != 0 || len(got.BySeverity) != 0 || got.TopAlerts == nil || len(got.TopAlerts) != 0 {
+		t.Errorf("summary = %+v, want an empty summary", got)
+	}
+}
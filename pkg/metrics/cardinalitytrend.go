@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/prometheus/common/model"
+)
+
+// cardinalityTrendQuery returns the query counting the distinct values of a label of a
+// metric at each step.
+func cardinalityTrendQuery(metric, label, selector string) (string, error) {
+	if !metricNameRe.MatchString(metric) {
+		return "", fmt.Errorf("invalid metric name %q", metric)
+	}
+	if !labelNameRe.MatchString(label) {
+		return "", fmt.Errorf("invalid label name %q", label)
+	}
+	braced, err := bracedSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("count(count by (%s) (%s%s))", label, metric, braced), nil
+}
+
+// buildCardinalityTrend returns the distinct value counts of a cardinality trend query
+// result and the first point with the highest count, or nil when the result is empty.
+func buildCardinalityTrend(matrix model.Matrix) (points []CardinalityPoint, peak *CardinalityPoint) {
+	points = []CardinalityPoint{}
+	if len(matrix) == 0 {
+		return points, nil
+	}
+	for _, sample := range matrix[0].Values {
+		if math.IsNaN(float64(sample.Value)) {
+			continue
+		}
+		points = append(points, CardinalityPoint{
+			Timestamp: float64(sample.Timestamp) / millisecondsPerSecond,
+			Count:     int(sample.Value),
+		})
+		if last := points[len(points)-1]; peak == nil || last.Count > peak.Count {
+			peak = &last
+		}
+	}
+	return points, peak
+}
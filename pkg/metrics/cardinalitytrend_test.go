@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestCardinalityTrendQuery(t *testing.T) {
+	tests := []struct {
+		metric   string
+		label    string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{metric: "http_requests_total", label: "path", want: `count(count by (path) (http_requests_total))`},
+		{metric: "http_requests_total", label: "path", selector: `{job="api"}`, want: `count(count by (path) (http_requests_total{job="api"}))`},
+		{metric: `http_requests_total{job="api"}`, label: "path", wantErr: true},
+		{metric: "http_requests_total", label: "path) (up", wantErr: true},
+		{metric: "http_requests_total", label: "path", selector: `job="api"})) or vector(1) or (count(up{`, wantErr: true},
+		{metric: "http_requests_total", label: "path", selector: `{__name__="up"}`, wantErr: true},
+		{metric: "http_requests_total", label: "path", selector: `job="api", code=~"5.."`, want: `count(count by (path) (http_requests_total{job="api",code=~"5.."}))`},
+	}
+
+	for _, tt := range tests {
+		got, err := cardinalityTrendQuery(tt.metric, tt.label, tt.selector)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("cardinalityTrendQuery(%q, %q) expected an error, got %q", tt.metric, tt.label, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("cardinalityTrendQuery(%q, %q) unexpected error: %v", tt.metric, tt.label, err)
+		} else if got != tt.want {
+			t.Errorf("cardinalityTrendQuery(%q, %q, %q) = %q, want %q", tt.metric, tt.label, tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCardinalityTrend(t *testing.T) {
+	matrix := model.Matrix{{Metric: model.Metric{}, Values: []model.SamplePair{
+		{Timestamp: 0, Value: 3},
+		{Timestamp: 60000, Value: 250},
+		{Timestamp: 120000, Value: 250},
+		{Timestamp: 180000, Value: 40},
+	}}}
+
+	points, peak := buildCardinalityTrend(matrix)
+	want := []CardinalityPoint{{0, 3}, {60, 250}, {120, 250}, {180, 40}}
+	if !slices.Equal(points, want) {
+		t.Errorf("got points %v, want %v", points, want)
+	}
+	if peak == nil || *peak != (CardinalityPoint{Timestamp: 60, Count: 250}) {
+		t.Errorf("got peak %v, want the first point with 250 values", peak)
+	}
+
+	points, peak = buildCardinalityTrend(model.Matrix{})
+	if points == nil || len(points) != 0 || peak != nil {
+		t.Errorf("expected no points and no peak for an empty result, got %v and %v", points, peak)
+	}
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// conditionReturnsBool checks that query is a comparison and reports whether it uses the
+// bool modifier. Comparisons without it drop the series at the steps where they are
+// false, while with it they return 0 or 1 at every step.
+func conditionReturnsBool(query string) (bool, error) {
+	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
+	if err != nil {
+		return false, fmt.Errorf("invalid query: %w", err)
+	}
+	for {
+		paren, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.Expr
+	}
+	binary, ok := expr.(*parser.BinaryExpr)
+	if !ok || !binary.Op.IsComparisonOperator() {
+		return false, fmt.Errorf("query must be a comparison, e.g. avg(rate(http_requests_total{job=\"api\"}[5m])) > 0.8")
+	}
+	return binary.ReturnBool, nil
+}
+
+// evaluateCondition computes how long a condition was true over the steps from start
+// to end. The condition is true at a step when any series of the result is true at it:
+// has a sample, or a sample of 1 when the query uses the bool modifier.
+func evaluateCondition(matrix model.Matrix, start, end time.Time, step time.Duration, returnBool bool) EvaluateConditionOutput {
+	totalSteps := int(end.Sub(start)/step) + 1
+	startMs := model.TimeFromUnixNano(start.UnixNano())
+	stepMs := int64(step / time.Millisecond)
+
+	truth := make([]bool, totalSteps)
+	for _, series := range matrix {
+		for i, isTrue := range seriesTruth(series.Values, startMs, stepMs, totalSteps, returnBool) {
+			truth[i] = truth[i] || isTrue
+		}
+	}
+
+	output := EvaluateConditionOutput{TotalSteps: totalSteps, SeriesCount: len(matrix)}
+	streakStart, streakLen := 0, 0
+	for i, isTrue := range truth {
+		if !isTrue {
+			streakLen = 0
+			continue
+		}
+		output.TrueSteps++
+		if streakLen == 0 {
+			streakStart = i
+		}
+		streakLen++
+		if output.LongestStreak == nil || streakLen > output.LongestStreak.Steps {
+			output.LongestStreak = &ConditionStreak{
+				Start:    float64(startMs+model.Time(int64(streakStart)*stepMs)) / millisecondsPerSecond,
+				End:      float64(startMs+model.Time(int64(i)*stepMs)) / millisecondsPerSecond,
+				Steps:    streakLen,
+				Duration: model.Duration(time.Duration(streakLen-1) * step).String(),
+			}
+		}
+	}
+	output.TrueFraction = math.Round(float64(output.TrueSteps)/float64(totalSteps)*10000) / 10000
+	return output
+}
+
+// seriesTruth returns whether the condition is true for a series at each step.
+func seriesTruth(samples []model.SamplePair, startMs model.Time, stepMs int64, totalSteps int, returnBool bool) []bool {
+	truth := make([]bool, totalSteps)
+	for _, sample := range samples {
+		v := float64(sample.Value)
+		if math.IsNaN(v) || (returnBool && v != 1) {
+			continue
+		}
+		offset := int64(sample.Timestamp - startMs)
+		if offset < 0 || offset%stepMs != 0 || offset/stepMs >= int64(totalSteps) {
+			continue
+		}
+		truth[offset/stepMs] = true
+	}
+	return truth
+}
+
+// evaluateForWindow checks the condition against the 'for' duration of an alert rule. As
+// alerts are per series, a series must be true at every step of a run spanning at least
+// forDuration for the alert to fire; the first such run of any series is when it would
+// have started firing.
+func evaluateForWindow(matrix model.Matrix, start, end time.Time, step, forDuration time.Duration, returnBool bool) *ConditionForWindow {
+	totalSteps := int(end.Sub(start)/step) + 1
+	startMs := model.TimeFromUnixNano(start.UnixNano())
+	stepMs := int64(step / time.Millisecond)
+	// A run of n steps spans (n-1) steps, as the alert becomes pending at its first step.
+	forSteps := int((forDuration + step - 1) / step)
+
+	window := &ConditionForWindow{For: model.Duration(forDuration).String()}
+	firingStep := -1
+	for _, series := range matrix {
+		streakLen := 0
+		for i, isTrue := range seriesTruth(series.Values, startMs, stepMs, totalSteps, returnBool) {
+			if !isTrue {
+				streakLen = 0
+				continue
+			}
+			streakLen++
+			if streakLen-1 >= forSteps && (firingStep < 0 || i < firingStep) {
+				firingStep = i
+			}
+			if i == totalSteps-1 && streakLen-1 >= forSteps {
+				window.FiringAtEnd = true
+			}
+		}
+	}
+	if firingStep >= 0 {
+		window.WouldHaveFired = true
+		firingSince := float64(startMs+model.Time(int64(firingStep)*stepMs)) / millisecondsPerSecond
+		window.FiringSince = &firingSince
+	}
+	return window
+}
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestConditionReturnsBool(t *testing.T) {
+	tests := []struct {
+		query    string
+		wantBool bool
+		wantErr  bool
+	}{
+		{query: `avg(rate(http_requests_total{job="api"}[5m])) > 0.8`},
+		{query: `(up{job="api"} == 0)`},
+		{query: `avg(up{job="api"}) < bool 0.5`, wantBool: true},
+		{query: `avg(rate(http_requests_total{job="api"}[5m]))`, wantErr: true},
+		{query: `up{job="api"} + 1`, wantErr: true},
+		{query: `up{job="api"} >`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := conditionReturnsBool(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("conditionReturnsBool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantBool {
+				t.Errorf("conditionReturnsBool() = %v, want %v", got, tt.wantBool)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	start := time.Unix(1704067200, 0)
+	end := start.Add(9 * time.Minute)
+	at := func(step int, v float64) model.SamplePair {
+		return model.SamplePair{Timestamp: model.TimeFromUnixNano(start.Add(time.Duration(step) * time.Minute).UnixNano()), Value: model.SampleValue(v)}
+	}
+
+	t.Run("filtering comparison", func(t *testing.T) {
+		// Two series are true at steps 1-2, 4-6 and 8 between them.
+		matrix := model.Matrix{
+			{Metric: model.Metric{"pod": "a"}, Values: []model.SamplePair{at(1, 0.9), at(2, 0.9), at(5, 0.9)}},
+			{Metric: model.Metric{"pod": "b"}, Values: []model.SamplePair{at(4, 0.9), at(6, 0.9), at(8, 0.9)}},
+		}
+		got := evaluateCondition(matrix, start, end, time.Minute, false)
+		want := EvaluateConditionOutput{
+			TrueFraction: 0.6,
+			TrueSteps:    6,
+			TotalSteps:   10,
+			SeriesCount:  2,
+			LongestStreak: &ConditionStreak{
+				Start:    float64(start.Add(4 * time.Minute).Unix()),
+				End:      float64(start.Add(6 * time.Minute).Unix()),
+				Steps:    3,
+				Duration: "2m",
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("evaluateCondition() = %+v (streak %+v), want %+v (streak %+v)", got, got.LongestStreak, want, want.LongestStreak)
+		}
+	})
+
+	t.Run("bool comparison", func(t *testing.T) {
+		matrix := model.Matrix{
+			{Metric: model.Metric{}, Values: []model.SamplePair{at(0, 1), at(1, 1), at(2, 0), at(3, math.NaN()), at(4, 1)}},
+		}
+		got := evaluateCondition(matrix, start, end, time.Minute, true)
+		if got.TrueSteps != 3 || got.TrueFraction != 0.3 {
+			t.Errorf("true steps = %d (fraction %v), want 3 (0.3)", got.TrueSteps, got.TrueFraction)
+		}
+		if got.LongestStreak == nil || got.LongestStreak.Steps != 2 || got.LongestStreak.Start != float64(start.Unix()) {
+			t.Errorf("longest streak = %+v, want the first 2 steps", got.LongestStreak)
+		}
+	})
+
+	t.Run("never true", func(t *testing.T) {
+		got := evaluateCondition(model.Matrix{}, start, end, time.Minute, false)
+		if got.TrueSteps != 0 || got.TrueFraction != 0 || got.TotalSteps != 10 || got.LongestStreak != nil {
+			t.Errorf("evaluateCondition() = %+v, want no true steps out of 10", got)
+		}
+	})
+}
+
+func TestEvaluateForWindow(t *testing.T) {
+	start := time.Unix(1704067200, 0)
+	end := start.Add(9 * time.Minute)
+	series := func(name string, v float64, steps ...int) *model.SampleStream {
+		s := &model.SampleStream{Metric: model.Metric{"pod": model.LabelValue(name)}}
+		for _, step := range steps {
+			s.Values = append(s.Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(start.Add(time.Duration(step) * time.Minute).UnixNano()), Value: model.SampleValue(v)})
+		}
+		return s
+	}
+
+	tests := []struct {
+		name            string
+		matrix          model.Matrix
+		returnBool      bool
+		forDuration     time.Duration
+		wantFiringAtEnd bool
+		wantFired       bool
+		wantSince       time.Duration
+	}{
+		{
+			name:            "condition held through the end",
+			matrix:          model.Matrix{series("a", 0.9, 5, 6, 7, 8, 9)},
+			forDuration:     3 * time.Minute,
+			wantFiringAtEnd: true,
+			wantFired:       true,
+			wantSince:       8 * time.Minute,
+		},
+		{
+			name:        "condition held earlier but not at the end",
+			matrix:      model.Matrix{series("a", 0.9, 0, 1, 2, 3, 8, 9)},
+			forDuration: 3 * time.Minute,
+			wantFired:   true,
+			wantSince:   3 * time.Minute,
+		},
+		{
+			name:        "run shorter than the for duration",
+			matrix:      model.Matrix{series("a", 0.9, 7, 8, 9)},
+			forDuration: 3 * time.Minute,
+		},
+		{
+			// Together the series are true at every step, but neither for 3 minutes on its own.
+			name:        "alternating series do not fire",
+			matrix:      model.Matrix{series("a", 0.9, 0, 1, 4, 5, 8, 9), series("b", 0.9, 2, 3, 6, 7)},
+			forDuration: 3 * time.Minute,
+		},
+		{
+			name:            "bool comparison needs ones",
+			matrix:          model.Matrix{series("a", 1, 6, 7, 8, 9), series("b", 0, 0, 1, 2, 3, 4, 5)},
+			returnBool:      true,
+			forDuration:     2 * time.Minute,
+			wantFiringAtEnd: true,
+			wantFired:       true,
+			wantSince:       8 * time.Minute,
+		},
+		{
+			name:            "for duration between steps is rounded up",
+			matrix:          model.Matrix{series("a", 0.9, 7, 8, 9)},
+			forDuration:     90 * time.Second,
+			wantFiringAtEnd: true,
+			wantFired:       true,
+			wantSince:       9 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateForWindow(tt.matrix, start, end, time.Minute, tt.forDuration, tt.returnBool)
+			if got.FiringAtEnd != tt.wantFiringAtEnd || got.WouldHaveFired != tt.wantFired {
+				t.Errorf("firingAtEnd = %v, wouldHaveFired = %v, want %v, %v", got.FiringAtEnd, got.WouldHaveFired, tt.wantFiringAtEnd, tt.wantFired)
+			}
+			var wantSince *float64
+			if tt.wantFired {
+				since := float64(start.Add(tt.wantSince).Unix())
+				wantSince = &since
+			}
+			if !reflect.DeepEqual(got.FiringSince, wantSince) {
+				t.Errorf("firingSince = %v, want %v", got.FiringSince, wantSince)
+			}
+		})
+	}
+}
@@ -3,10 +3,16 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	serverconfig "github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/prometheus/common/model"
 
 	"github.com/rhobs/obs-mcp/pkg/auth"
 	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
@@ -14,6 +20,9 @@ import (
 
 const ToolsetName = "observability/metrics"
 
+// DefaultMaxLabelValues is the default maximum number of values returned by get_label_values.
+const DefaultMaxLabelValues = 1000
+
 // Config holds obs-mcp toolset configuration
 type Config struct {
 	// AuthMode controls where the bearer token is obtained for authenticating
@@ -27,6 +36,7 @@ type Config struct {
 
 	// AlertmanagerURL is the URL of the Alertmanager endpoint.
 	// This field is optional. Example: "https://alertmanager-main-openshift-monitoring.apps.example.com"
+	// When unset, the tools calling Alertmanager report that alerts are not configured.
 	AlertmanagerURL string `toml:"alertmanager_url,omitempty"`
 
 	// Insecure controls whether to skip TLS certificate verification.
@@ -38,6 +48,11 @@ type Config struct {
 	//   - "disallow-explicit-name-label"
 	//   - "require-label-matcher"
 	//   - "disallow-blanket-regex"
+	//   - "max-metric-cardinality"
+	//   - "limit-matchers"
+	//   - "limit-subqueries"
+	//   - "disallow-all-metrics"
+	//   - "max-nesting-depth"
 	Guardrails string `toml:"guardrails,omitempty"`
 
 	// MaxMetricCardinality is the maximum allowed series count per metric.
@@ -50,10 +65,171 @@ type Config struct {
 	// Set to 0 to always disallow blanket regex regardless of cardinality.
 	MaxLabelCardinality *uint64 `toml:"max_label_cardinality,omitempty"`
 
+	// MaxResultSeries is the maximum number of series a query may return.
+	// Single-selector queries are checked against the series API before they run.
+	// When unset, results are not limited.
+	MaxResultSeries *uint64 `toml:"max_result_series,omitempty"`
+
+	// MaxMatchersPerSelector is the maximum number of label matchers in a selector.
+	// Only takes effect if limit-matchers is enabled.
+	// When unset, the default of 20 is used.
+	MaxMatchersPerSelector *uint64 `toml:"max_matchers_per_selector,omitempty"`
+
+	// MaxRegexAlternatives is the maximum number of literal alternatives a regex matcher expands to.
+	// Only takes effect if limit-matchers is enabled.
+	// When unset, the default of 50 is used.
+	MaxRegexAlternatives *uint64 `toml:"max_regex_alternatives,omitempty"`
+
+	// MaxSubqueryRange is the maximum range a subquery may cover, e.g. "12h" or "1d", including
+	// the ranges of the subqueries it is nested in.
+	// Only takes effect if limit-subqueries is enabled.
+	// When unset, the default of 24h is used.
+	MaxSubqueryRange string `toml:"max_subquery_range,omitempty"`
+
+	// MinSubqueryStep is the finest resolution a subquery may use, e.g. "1m".
+	// Only takes effect if limit-subqueries is enabled.
+	// When unset, the default of 30s is used.
+	MinSubqueryStep string `toml:"min_subquery_step,omitempty"`
+
+	// MaxNestingDepth is the maximum number of functions, aggregations and subqueries
+	// nested in each other.
+	// Only takes effect if max-nesting-depth is enabled.
+	// When unset, the default of 8 is used.
+	MaxNestingDepth *uint64 `toml:"max_nesting_depth,omitempty"`
+
+	// TrustGuardrailHeader enables relaxing guardrails per request through the header named
+	// by GuardrailHeader, for deployments where an upstream policy engine decides query
+	// safety. Only enable it behind a gateway that strips the header from client requests.
+	// Default: false (the header is ignored)
+	TrustGuardrailHeader bool `toml:"trust_guardrail_header,omitempty"`
+
+	// GuardrailHeader is the request header carrying a comma-separated list of guardrails
+	// to disable for the request, e.g. "require-label-matcher,max-result-series".
+	// Only takes effect if trust_guardrail_header is enabled.
+	// When unset, the default of "X-Obs-MCP-Guardrails" is used.
+	GuardrailHeader string `toml:"guardrail_header,omitempty"`
+
+	// AllowClientNow lets clients of the HTTP server set the time NOW resolves to with the
+	// X-Obs-MCP-Now request header (RFC3339 or Unix timestamp), so that recorded sessions
+	// can be replayed deterministically.
+	// Default: false (NOW is the server time)
+	AllowClientNow bool `toml:"allow_client_now,omitempty"`
+
+	// MaxLabelValues is the maximum number of values get_label_values returns (0 = no limit).
+	// It is also the default when the tool is called without a limit.
+	// When unset, the default of 1000 is used.
+	MaxLabelValues *int `toml:"max_label_values,omitempty"`
+
+	// Limits overrides the default and maximum number of results per tool, keyed by
+	// "list_metrics", "get_label_values", "get_labels_overview", "get_series",
+	// "query_result_series" or "get_metric_metadata", e.g.:
+	//   [limits.get_series]
+	//   default = 200
+	//   max = 1000
+	// Calls requesting more than the maximum are rejected.
+	// When unset, get_label_values defaults to max_label_values, get_labels_overview to
+	// 50 values per label, query_result_series to max_result_series, get_metric_metadata
+	// to 100 metrics, and the other results are not limited.
+	Limits map[string]ToolLimitConfig `toml:"limits,omitempty"`
+
 	// RangeQueryFullResponse controls whether range queries return full data points
 	// instead of summary statistics.
 	// Default: false (return summary statistics)
 	RangeQueryFullResponse bool `toml:"range_query_full_response,omitempty"`
+
+	// SplitRangeQueries enables splitting range queries with more points per series than
+	// a single request allows into sub-range requests whose results are stitched together.
+	// Default: false
+	SplitRangeQueries bool `toml:"split_range_queries,omitempty"`
+
+	// MaxSplitPoints is the maximum number of points per series of a split range query.
+	// Only takes effect if split_range_queries is enabled.
+	// When unset, the default of 110000 is used.
+	MaxSplitPoints *int `toml:"max_split_points,omitempty"`
+
+	// MetricNamePrefix and MetricNameSuffix are added to the metric names of queries
+	// before they are sent to the backend, and removed from the metric names of results,
+	// for backends storing metrics under transformed names, e.g. with a federation prefix.
+	// Default: "" (names are used as is)
+	MetricNamePrefix string `toml:"metric_name_prefix,omitempty"`
+	MetricNameSuffix string `toml:"metric_name_suffix,omitempty"`
+
+	// OversizedStepPolicy controls how range queries with a step larger than their
+	// time range are handled: "reject" (default) returns a validation error,
+	// "shrink" reduces the step to fit the range and adds a warning to the result.
+	OversizedStepPolicy string `toml:"oversized_step_policy,omitempty"`
+
+	// DeployMarkerQuery is a PromQL query returning the Unix timestamps of deploys, e.g.
+	// "max(deployment_created_timestamp)". When set, execute_range_query accepts
+	// SINCE_LAST_DEPLOY as start or end, resolved to the latest timestamp the query returns.
+	// Default: "" (SINCE_LAST_DEPLOY is not available)
+	DeployMarkerQuery string `toml:"deploy_marker_query,omitempty"`
+
+	// StatusChecks replaces the checks cluster_status runs, each an instant query whose
+	// highest value is compared to a warning and a critical threshold, e.g.
+	//   [[status_checks]]
+	//   name = "pending_pods"
+	//   query = 'sum(kube_pod_status_phase{phase="Pending"})'
+	//   warning = 0
+	//   critical = 10
+	// Default: unset (node readiness, pod restarts, API server latency and etcd health)
+	StatusChecks []StatusCheck `toml:"status_checks,omitempty"`
+
+	// LogQueries controls whether every executed PromQL query and its time window
+	// are logged at info level. Values of sensitive-looking labels are redacted.
+	// Default: false
+	LogQueries bool `toml:"log_queries,omitempty"`
+
+	// AlertLabels and AlertAnnotations are the labels and annotations get_alerts returns
+	// of each alert, e.g. ["alertname", "severity", "namespace"] and ["summary"], to keep
+	// alert lists short. Calls can request other ones, or ["*"] for all of them.
+	// Default: unset (all labels and annotations are returned)
+	AlertLabels      []string `toml:"alert_labels,omitempty"`
+	AlertAnnotations []string `toml:"alert_annotations,omitempty"`
+
+	// AllowFileOutput enables the save_query_result tool, which writes query results
+	// to files in FileOutputDir instead of returning them.
+	// Default: false
+	AllowFileOutput bool `toml:"allow_file_output,omitempty"`
+
+	// FileOutputDir is the directory save_query_result writes files to.
+	// Required when AllowFileOutput is enabled.
+	FileOutputDir string `toml:"file_output_dir,omitempty"`
+
+	// AllowWrites enables the tools changing the state of Alertmanager, such as
+	// delete_silence.
+	// Default: false
+	AllowWrites bool `toml:"allow_writes,omitempty"`
+
+	// MaxIdleConns is the maximum number of idle connections kept open to the
+	// Prometheus and Alertmanager backends (0 = no limit).
+	// When unset, the default of 100 is used.
+	MaxIdleConns *int `toml:"max_idle_conns,omitempty"`
+
+	// MaxConnsPerHost limits the number of connections per backend host (0 = no limit).
+	// When unset, connections are not limited.
+	MaxConnsPerHost *int `toml:"max_conns_per_host,omitempty"`
+
+	// IdleConnTimeout is how long an idle backend connection is kept open, e.g. "30s"
+	// ("0s" = no timeout).
+	// When unset, the default of 30s is used.
+	IdleConnTimeout string `toml:"idle_conn_timeout,omitempty"`
+
+	// TokenFile is a file the bearer token sent to Prometheus and Alertmanager is read
+	// from instead of the kubeconfig, e.g. a projected service account token. The file is
+	// re-read every TokenReloadInterval, so that rotated tokens are picked up.
+	// Only valid with auth_mode kubeconfig.
+	TokenFile string `toml:"token_file,omitempty"`
+
+	// TokenReloadInterval is how often TokenFile is re-read, e.g. "30s".
+	// When unset, the default of 1m is used.
+	TokenReloadInterval string `toml:"token_reload_interval,omitempty"`
+
+	// ToolDescriptions replaces the descriptions of metrics tools, keyed by tool name,
+	// to tune them for a deployment without code changes.
+	// Only used by the standalone server; when running as a toolset, use the
+	// tool_overrides of the hosting server instead.
+	ToolDescriptions map[string]string `toml:"-"`
 }
 
 var _ api.ExtendedConfig = (*Config)(nil)
@@ -64,10 +240,102 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid auth_mode: %q (valid options: %q, %q)", c.AuthMode, auth.AuthModeHeader, auth.AuthModeKubeConfig)
 	}
 
+	if err := validateLimits(c.Limits); err != nil {
+		return err
+	}
+	if c.MaxLabelValues != nil && c.Limits[LimitLabelValues].Max != nil {
+		return fmt.Errorf("max_label_values and limits.%s.max are both set", LimitLabelValues)
+	}
+	if c.MaxResultSeries != nil && c.Limits[LimitQueryResultSeries].Max != nil {
+		return fmt.Errorf("max_result_series and limits.%s.max are both set", LimitQueryResultSeries)
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.Limits)) {
+		// Defaults must also fit the built-in maximums they do not override.
+		if def := c.Limits[key].Default; def != nil {
+			if limit := c.GetLimit(key); limit.Max > 0 && *def > limit.Max {
+				return fmt.Errorf("invalid limits.%s.default: %d exceeds the maximum of %d", key, *def, limit.Max)
+			}
+		}
+	}
+
 	if _, err := c.GetGuardrails(); err != nil {
 		return err
 	}
 
+	if _, err := c.GetTransportConfig(); err != nil {
+		return err
+	}
+
+	if c.TokenFile != "" && c.GetAuthMode() == auth.AuthModeHeader {
+		return fmt.Errorf("token_file cannot be used with auth_mode %q, which sends the token of the caller", auth.AuthModeHeader)
+	}
+	if _, err := c.GetTokenFile(); err != nil {
+		return err
+	}
+
+	if c.GuardrailHeader != "" && !c.TrustGuardrailHeader {
+		return fmt.Errorf("guardrail_header is set but trust_guardrail_header is disabled")
+	}
+
+	if c.MaxLabelValues != nil && *c.MaxLabelValues < 0 {
+		return fmt.Errorf("invalid max_label_values: %d (must not be negative)", *c.MaxLabelValues)
+	}
+
+	if c.MaxSplitPoints != nil {
+		if !c.SplitRangeQueries {
+			return fmt.Errorf("max_split_points is set but split_range_queries is disabled")
+		}
+		if *c.MaxSplitPoints < prometheus.MaxPointsPerQuery {
+			return fmt.Errorf("invalid max_split_points: %d (must be at least %d)", *c.MaxSplitPoints, prometheus.MaxPointsPerQuery)
+		}
+	}
+
+	if err := c.GetMetricNameTransform().Validate(); err != nil {
+		return err
+	}
+
+	if c.OversizedStepPolicy != "" {
+		if _, err := ParseStepPolicy(c.OversizedStepPolicy); err != nil {
+			return fmt.Errorf("invalid oversized_step_policy: %w", err)
+		}
+	}
+
+	if c.DeployMarkerQuery != "" {
+		if err := validateDeployMarkerQuery(c.DeployMarkerQuery); err != nil {
+			return err
+		}
+	}
+
+	if err := validateStatusChecks(c.StatusChecks); err != nil {
+		return err
+	}
+
+	if err := c.GetAlertProjection().Validate(); err != nil {
+		return err
+	}
+
+	if c.AllowFileOutput && c.FileOutputDir == "" {
+		return fmt.Errorf("file_output_dir is required when allow_file_output is enabled")
+	}
+	if !c.AllowFileOutput && c.FileOutputDir != "" {
+		return fmt.Errorf("file_output_dir is set but allow_file_output is disabled")
+	}
+
+	if len(c.ToolDescriptions) > 0 {
+		known := make(map[string]bool)
+		for _, tool := range AllTools() {
+			known[tool.ToMCPTool().Name] = true
+		}
+		for name, description := range c.ToolDescriptions {
+			if !known[name] {
+				return fmt.Errorf("description override for unknown tool %q", name)
+			}
+			if strings.TrimSpace(description) == "" {
+				return fmt.Errorf("description override for tool %q is empty", name)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -79,6 +347,108 @@ func (c *Config) GetAuthMode() auth.AuthMode {
 	return c.AuthMode
 }
 
+// GetMaxLabelValues returns the maximum number of label values returned by
+// get_label_values, defaulting to DefaultMaxLabelValues.
+func (c *Config) GetMaxLabelValues() int {
+	if c.MaxLabelValues == nil {
+		return DefaultMaxLabelValues
+	}
+	return *c.MaxLabelValues
+}
+
+// GetLimit returns the limit on the number of results of the tool named by key, one of
+// the Limit* keys: the built-in limit with the overrides of the limits section applied.
+func (c *Config) GetLimit(key string) ToolLimit {
+	var limit ToolLimit
+	switch key {
+	case LimitLabelValues:
+		limit = ToolLimit{Default: c.GetMaxLabelValues(), Max: c.GetMaxLabelValues()}
+	case LimitLabelsOverview:
+		limit = ToolLimit{Default: defaultLabelsOverviewValues, Max: c.GetLimit(LimitLabelValues).Max}
+	case LimitQueryResultSeries:
+		if c.MaxResultSeries != nil {
+			limit.Max = int(*c.MaxResultSeries)
+		}
+	case LimitMetricMetadata:
+		limit.Default = defaultMetricMetadataLimit
+	}
+	return limit.apply(c.Limits[key])
+}
+
+// GetMaxResultSeries returns the maximum number of series a query may return (0 = no limit).
+func (c *Config) GetMaxResultSeries() int {
+	return c.GetLimit(LimitQueryResultSeries).Max
+}
+
+// GetMaxSplitPoints returns the maximum number of points per series of a split range
+// query, or 0 when range queries are not split.
+func (c *Config) GetMaxSplitPoints() int {
+	if !c.SplitRangeQueries {
+		return 0
+	}
+	if c.MaxSplitPoints == nil {
+		return prometheus.DefaultMaxSplitPoints
+	}
+	return *c.MaxSplitPoints
+}
+
+// GetMetricNameTransform returns the mapping between the metric names of queries and
+// the names the backend stores metrics under.
+func (c *Config) GetMetricNameTransform() prometheus.MetricNameTransform {
+	return prometheus.MetricNameTransform{Prefix: c.MetricNamePrefix, Suffix: c.MetricNameSuffix}
+}
+
+// GetOversizedStepPolicy returns the configured policy for range queries with a step
+// larger than their range, defaulting to StepPolicyReject.
+func (c *Config) GetOversizedStepPolicy() StepPolicy {
+	if c.OversizedStepPolicy == "" {
+		return StepPolicyReject
+	}
+	return StepPolicy(c.OversizedStepPolicy)
+}
+
+// GetRangeQueryOptions returns the settings range queries are executed with.
+func (c *Config) GetRangeQueryOptions() RangeQueryOptions {
+	return RangeQueryOptions{
+		FullResponse:      c.RangeQueryFullResponse,
+		StepPolicy:        c.GetOversizedStepPolicy(),
+		MaxSeries:         c.GetMaxResultSeries(),
+		DeployMarkerQuery: c.DeployMarkerQuery,
+	}
+}
+
+// GetToolDescription returns the description configured for the named tool,
+// or def when it is not overridden.
+func (c *Config) GetToolDescription(name, def string) string {
+	if description, ok := c.ToolDescriptions[name]; ok {
+		return description
+	}
+	return def
+}
+
+// GetFileOutputDir returns the directory query results may be saved to,
+// or an empty string when file output is disabled.
+func (c *Config) GetFileOutputDir() string {
+	if !c.AllowFileOutput {
+		return ""
+	}
+	return c.FileOutputDir
+}
+
+// GetStatusChecks returns the checks cluster_status runs, defaulting to DefaultStatusChecks.
+func (c *Config) GetStatusChecks() []StatusCheck {
+	if len(c.StatusChecks) == 0 {
+		return DefaultStatusChecks
+	}
+	return c.StatusChecks
+}
+
+// GetAlertProjection returns the labels and annotations of alerts get_alerts returns
+// unless a call requests others.
+func (c *Config) GetAlertProjection() AlertProjection {
+	return AlertProjection{Labels: c.AlertLabels, Annotations: c.AlertAnnotations}
+}
+
 // GetGuardrails returns the parsed guardrails configuration with cardinality limits applied.
 func (c *Config) GetGuardrails() (*prometheus.Guardrails, error) {
 	guardrailsStr := c.Guardrails
@@ -115,10 +485,164 @@ func (c *Config) GetGuardrails() (*prometheus.Guardrails, error) {
 		}
 		guardrails.MaxLabelCardinality = *c.MaxLabelCardinality
 	}
+	if c.MaxMatchersPerSelector != nil || c.MaxRegexAlternatives != nil {
+		if guardrails == nil || !guardrails.LimitMatchers {
+			return nil, fmt.Errorf(
+				"max_matchers_per_selector or max_regex_alternatives is set but the %q guardrail is not enabled",
+				prometheus.GuardrailLimitMatchers)
+		}
+		if c.MaxMatchersPerSelector != nil {
+			if *c.MaxMatchersPerSelector == 0 {
+				return nil, fmt.Errorf("max_matchers_per_selector must be greater than 0; use '!%s' in guardrails to disable the limit",
+					prometheus.GuardrailLimitMatchers)
+			}
+			guardrails.MaxMatchersPerSelector = *c.MaxMatchersPerSelector
+		}
+		if c.MaxRegexAlternatives != nil {
+			if *c.MaxRegexAlternatives == 0 {
+				return nil, fmt.Errorf("max_regex_alternatives must be greater than 0; use '!%s' in guardrails to disable the limit",
+					prometheus.GuardrailLimitMatchers)
+			}
+			guardrails.MaxRegexAlternatives = *c.MaxRegexAlternatives
+		}
+	}
+	if c.MaxSubqueryRange != "" || c.MinSubqueryStep != "" {
+		if guardrails == nil || !guardrails.LimitSubqueries {
+			return nil, fmt.Errorf(
+				"max_subquery_range or min_subquery_step is set but the %q guardrail is not enabled",
+				prometheus.GuardrailLimitSubqueries)
+		}
+		if c.MaxSubqueryRange != "" {
+			d, err := model.ParseDuration(c.MaxSubqueryRange)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid max_subquery_range: %q (must be a positive duration, e.g. 12h or 1d)", c.MaxSubqueryRange)
+			}
+			guardrails.MaxSubqueryRange = time.Duration(d)
+		}
+		if c.MinSubqueryStep != "" {
+			d, err := model.ParseDuration(c.MinSubqueryStep)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid min_subquery_step: %q (must be a positive duration, e.g. 1m)", c.MinSubqueryStep)
+			}
+			guardrails.MinSubqueryStep = time.Duration(d)
+		}
+	}
+	if c.MaxNestingDepth != nil {
+		if guardrails == nil || !guardrails.LimitNestingDepth {
+			return nil, fmt.Errorf(
+				"max_nesting_depth is set but the %q guardrail is not enabled",
+				prometheus.GuardrailMaxNestingDepth)
+		}
+		if *c.MaxNestingDepth == 0 {
+			return nil, fmt.Errorf("max_nesting_depth must be greater than 0; use '!%s' in guardrails to disable the limit",
+				prometheus.GuardrailMaxNestingDepth)
+		}
+		guardrails.MaxNestingDepth = *c.MaxNestingDepth
+	}
+	if c.MaxResultSeries != nil {
+		if guardrails == nil {
+			return nil, fmt.Errorf("max_result_series is set but guardrails are disabled")
+		}
+		guardrails.MaxResultSeries = *c.MaxResultSeries
+	}
+	if c.Limits[LimitQueryResultSeries].Max != nil {
+		if guardrails == nil {
+			return nil, fmt.Errorf("limits.%s.max is set but guardrails are disabled", LimitQueryResultSeries)
+		}
+		guardrails.MaxResultSeries = uint64(c.GetMaxResultSeries())
+	}
 
 	return guardrails, nil
 }
 
+// GetTrustedGuardrailHeader returns the request header guardrail overrides are read from,
+// or "" when the header is not trusted.
+func (c *Config) GetTrustedGuardrailHeader() string {
+	if !c.TrustGuardrailHeader {
+		return ""
+	}
+	if c.GuardrailHeader == "" {
+		return auth.DefaultGuardrailHeader
+	}
+	return c.GuardrailHeader
+}
+
+// GetRequestGuardrails returns the guardrails in effect for a request: the configured
+// guardrails, relaxed by the override carried in ctx when the guardrail header is trusted.
+func (c *Config) GetRequestGuardrails(ctx context.Context) (*prometheus.Guardrails, error) {
+	guardrails, err := c.GetGuardrails()
+	if err != nil {
+		return nil, err
+	}
+
+	override := auth.GuardrailOverrideFromContext(ctx)
+	if !c.TrustGuardrailHeader || override == "" {
+		return guardrails, nil
+	}
+	relaxed, err := guardrails.Relax(override)
+	if err != nil {
+		return nil, fmt.Errorf("invalid guardrail override: %w", err)
+	}
+	slog.Info("Relaxing guardrails for request", "override", override)
+	return relaxed, nil
+}
+
+// GetTransportConfig returns the connection pool settings for backend clients,
+// falling back to the defaults for unset values.
+func (c *Config) GetTransportConfig() (auth.TransportConfig, error) {
+	transport := auth.DefaultTransportConfig()
+
+	if c.MaxIdleConns != nil {
+		if *c.MaxIdleConns < 0 {
+			return auth.TransportConfig{}, fmt.Errorf("invalid max_idle_conns: %d (must not be negative)", *c.MaxIdleConns)
+		}
+		transport.MaxIdleConns = *c.MaxIdleConns
+	}
+	if c.MaxConnsPerHost != nil {
+		if *c.MaxConnsPerHost < 0 {
+			return auth.TransportConfig{}, fmt.Errorf("invalid max_conns_per_host: %d (must not be negative)", *c.MaxConnsPerHost)
+		}
+		transport.MaxConnsPerHost = *c.MaxConnsPerHost
+	}
+	if c.IdleConnTimeout != "" {
+		timeout, err := time.ParseDuration(c.IdleConnTimeout)
+		if err != nil {
+			return auth.TransportConfig{}, fmt.Errorf("invalid idle_conn_timeout: %w", err)
+		}
+		if timeout < 0 {
+			return auth.TransportConfig{}, fmt.Errorf("invalid idle_conn_timeout: %q (must not be negative)", c.IdleConnTimeout)
+		}
+		transport.IdleConnTimeout = timeout
+	}
+
+	return transport, nil
+}
+
+// GetTokenFile returns the provider of the token in TokenFile, or nil when no token
+// file is configured.
+func (c *Config) GetTokenFile() (*auth.FileTokenProvider, error) {
+	if c.TokenFile == "" {
+		if c.TokenReloadInterval != "" {
+			return nil, fmt.Errorf("token_reload_interval is set but token_file is not")
+		}
+		return nil, nil
+	}
+
+	interval := auth.DefaultTokenReloadInterval
+	if c.TokenReloadInterval != "" {
+		var err error
+		interval, err = time.ParseDuration(c.TokenReloadInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token_reload_interval: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid token_reload_interval: %q (must be positive)", c.TokenReloadInterval)
+		}
+	}
+
+	return auth.FileToken(c.TokenFile, interval), nil
+}
+
 func obsMCPToolsetParser(_ context.Context, primitive toml.Primitive, md toml.MetaData) (api.ExtendedConfig, error) {
 	var cfg Config
 	if err := md.PrimitiveDecode(primitive, &cfg); err != nil {
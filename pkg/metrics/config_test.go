@@ -1,8 +1,11 @@
 package metrics
 
 import (
+	"cmp"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 
@@ -42,6 +45,11 @@ func TestValidate(t *testing.T) {
 			toml:    `auth_mode = "magic"`,
 			wantErr: `invalid auth_mode`,
 		},
+		{
+			name:    "guardrail_header without trusting it returns error",
+			toml:    `guardrail_header = "X-Policy-Guardrails"`,
+			wantErr: `trust_guardrail_header is disabled`,
+		},
 		// test just a sub set of guardrails validations, the rest is covered in `TestGetGuardrails`
 		{
 			name: "guardrails named list is valid",
@@ -60,6 +68,197 @@ max_metric_cardinality = 5000
 `,
 			wantErr: "max_metric_cardinality is set but",
 		},
+		{
+			name: "oversized_step_policy shrink is valid",
+			toml: `oversized_step_policy = "shrink"`,
+		},
+		{
+			name:    "unknown oversized_step_policy returns error",
+			toml:    `oversized_step_policy = "ignore"`,
+			wantErr: "invalid oversized_step_policy",
+		},
+		{
+			name: "deploy_marker_query is valid",
+			toml: `deploy_marker_query = "max(deployment_created_timestamp)"`,
+		},
+		{
+			name:    "invalid deploy_marker_query returns error",
+			toml:    `deploy_marker_query = "max(deployment_created_timestamp"`,
+			wantErr: "invalid deploy_marker_query",
+		},
+		{
+			name: "status_checks are valid",
+			toml: "[[status_checks]]\nname = \"pending_pods\"\nquery = 'sum(kube_pod_status_phase{phase=\"Pending\"})'\nwarning = 0\ncritical = 10",
+		},
+		{
+			name:    "duplicate status check name returns error",
+			toml:    "[[status_checks]]\nname = \"a\"\nquery = \"up\"\n[[status_checks]]\nname = \"a\"\nquery = \"up\"",
+			wantErr: "duplicate name",
+		},
+		{
+			name:    "invalid status check query returns error",
+			toml:    "[[status_checks]]\nname = \"a\"\nquery = \"sum(up\"",
+			wantErr: "invalid status_checks[0] (a) query",
+		},
+		{
+			name:    "status check critical below warning returns error",
+			toml:    "[[status_checks]]\nname = \"a\"\nquery = \"up\"\nwarning = 5\ncritical = 1",
+			wantErr: "critical threshold 1 is lower than warning threshold 5",
+		},
+		{
+			name: "max_label_values zero disables the limit",
+			toml: `max_label_values = 0`,
+		},
+		{
+			name:    "negative max_label_values returns error",
+			toml:    `max_label_values = -1`,
+			wantErr: "invalid max_label_values",
+		},
+		{
+			name: "split range queries with a points limit is valid",
+			toml: `
+split_range_queries = true
+max_split_points = 50000
+`,
+		},
+		{
+			name:    "max_split_points without split_range_queries returns error",
+			toml:    `max_split_points = 50000`,
+			wantErr: "max_split_points is set but split_range_queries is disabled",
+		},
+		{
+			name: "max_split_points below the single query limit returns error",
+			toml: `
+split_range_queries = true
+max_split_points = 1000
+`,
+			wantErr: "invalid max_split_points",
+		},
+		{
+			name: "metric name prefix and suffix are valid",
+			toml: `
+metric_name_prefix = "federate:"
+metric_name_suffix = "_fed"
+`,
+		},
+		{
+			name:    "invalid metric name prefix returns error",
+			toml:    `metric_name_prefix = "my-cluster"`,
+			wantErr: "invalid metric name prefix",
+		},
+		{
+			name: "limits are valid",
+			toml: `
+[limits.get_series]
+default = 200
+max = 1000
+
+[limits.list_metrics]
+max = 500
+`,
+		},
+		{
+			name:    "unknown limits key returns error",
+			toml:    "[limits.get_alerts]\nmax = 10",
+			wantErr: `invalid limits key "get_alerts"`,
+		},
+		{
+			name:    "negative limit returns error",
+			toml:    "[limits.get_series]\nmax = -1",
+			wantErr: "must not be negative",
+		},
+		{
+			name:    "limit default above max returns error",
+			toml:    "[limits.get_series]\ndefault = 100\nmax = 10",
+			wantErr: "default 100 exceeds max 10",
+		},
+		{
+			name:    "limit default above the built-in max returns error",
+			toml:    "[limits.get_label_values]\ndefault = 5000",
+			wantErr: "exceeds the maximum of 1000",
+		},
+		{
+			name:    "query result series default returns error",
+			toml:    "[limits.query_result_series]\ndefault = 100",
+			wantErr: "limits.query_result_series.default is not supported",
+		},
+		{
+			name: "max_label_values with limits.get_label_values.max returns error",
+			toml: `
+max_label_values = 100
+[limits.get_label_values]
+max = 200
+`,
+			wantErr: "both set",
+		},
+		{
+			name: "query result series limit with guardrails disabled returns error",
+			toml: `
+guardrails = "none"
+[limits.query_result_series]
+max = 100
+`,
+			wantErr: "guardrails are disabled",
+		},
+		{
+			name: "file output with a directory is valid",
+			toml: `
+allow_file_output = true
+file_output_dir = "/var/lib/obs-mcp"
+`,
+		},
+		{
+			name:    "file output without a directory returns error",
+			toml:    `allow_file_output = true`,
+			wantErr: "file_output_dir is required",
+		},
+		{
+			name:    "file output directory without enabling file output returns error",
+			toml:    `file_output_dir = "/var/lib/obs-mcp"`,
+			wantErr: "allow_file_output is disabled",
+		},
+		{
+			name: "alert label and annotation allowlists are valid",
+			toml: `
+alert_labels = ["alertname", "severity", "namespace"]
+alert_annotations = ["summary"]
+`,
+		},
+		{
+			name:    "invalid alert label name returns error",
+			toml:    `alert_labels = ["alertname", "severity level"]`,
+			wantErr: `invalid name "severity level" in alert_labels`,
+		},
+		{
+			name: "token_file with auth_mode kubeconfig is valid",
+			toml: `
+auth_mode = "kubeconfig"
+token_file = "/var/run/secrets/tokens/obs-mcp"
+token_reload_interval = "30s"
+`,
+		},
+		{
+			name:    "token_file with auth_mode header returns error",
+			toml:    `token_file = "/var/run/secrets/tokens/obs-mcp"`,
+			wantErr: `token_file cannot be used with auth_mode "header"`,
+		},
+		{
+			name: "token_reload_interval without token_file returns error",
+			toml: `
+auth_mode = "kubeconfig"
+token_reload_interval = "30s"
+`,
+			wantErr: `token_file is not`,
+		},
+		{
+			name: "zero token_reload_interval returns error",
+			toml: `
+auth_mode = "kubeconfig"
+token_file = "/var/run/secrets/tokens/obs-mcp"
+token_reload_interval = "0s"
+`,
+			wantErr: `invalid token_reload_interval`,
+		},
 		{
 			name: "full valid config",
 			toml: `
@@ -94,6 +293,45 @@ max_label_cardinality = 200
 	}
 }
 
+func TestValidate_ToolDescriptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		descriptions map[string]string
+		wantErr      string
+	}{
+		{
+			name:         "override of an existing tool is valid",
+			descriptions: map[string]string{"execute_range_query": "Query our metrics, named <team>_<metric>."},
+		},
+		{
+			name:         "override of an unknown tool returns error",
+			descriptions: map[string]string{"execute_query": "Query metrics."},
+			wantErr:      `unknown tool "execute_query"`,
+		},
+		{
+			name:         "empty override returns error",
+			descriptions: map[string]string{"list_metrics": " "},
+			wantErr:      `tool "list_metrics" is empty`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{ToolDescriptions: tt.descriptions}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGetAuthMode(t *testing.T) {
 	tests := []struct {
 		name string
@@ -128,6 +366,85 @@ func TestGetAuthMode(t *testing.T) {
 	}
 }
 
+func TestGetRangeQueryOptions(t *testing.T) {
+	if got, want := parseConfig(t, ``).GetRangeQueryOptions(), (RangeQueryOptions{StepPolicy: StepPolicyReject}); got != want {
+		t.Errorf("GetRangeQueryOptions() = %+v, want %+v", got, want)
+	}
+
+	cfg := parseConfig(t, `
+range_query_full_response = true
+oversized_step_policy = "shrink"
+max_result_series = 500
+deploy_marker_query = "changes(kube_deployment_status_observed_generation[5m]) > 0"
+`)
+	want := RangeQueryOptions{
+		FullResponse:      true,
+		StepPolicy:        StepPolicyShrink,
+		MaxSeries:         500,
+		DeployMarkerQuery: "changes(kube_deployment_status_observed_generation[5m]) > 0",
+	}
+	if got := cfg.GetRangeQueryOptions(); got != want {
+		t.Errorf("GetRangeQueryOptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetTransportConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		toml    string
+		want    auth.TransportConfig
+		wantErr string
+	}{
+		{
+			name: "empty config returns defaults",
+			toml: ``,
+			want: auth.DefaultTransportConfig(),
+		},
+		{
+			name: "explicit values override defaults",
+			toml: `
+max_idle_conns = 20
+max_conns_per_host = 0
+idle_conn_timeout = "2m"
+`,
+			want: auth.TransportConfig{
+				MaxIdleConns:    20,
+				MaxConnsPerHost: 0,
+				IdleConnTimeout: 2 * time.Minute,
+			},
+		},
+		{
+			name:    "negative max_idle_conns returns error",
+			toml:    `max_idle_conns = -1`,
+			wantErr: "invalid max_idle_conns",
+		},
+		{
+			name:    "invalid idle_conn_timeout returns error",
+			toml:    `idle_conn_timeout = "soon"`,
+			wantErr: "invalid idle_conn_timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := parseConfig(t, tt.toml)
+			got, err := cfg.GetTransportConfig()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetTransportConfig() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTransportConfig() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetTransportConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetGuardrails(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -237,6 +554,133 @@ max_label_cardinality = 200
 `,
 			wantErr: "max_label_cardinality is set but",
 		},
+		{
+			name: "matcher limits override defaults when limit-matchers is enabled",
+			toml: `
+guardrails = "limit-matchers"
+max_matchers_per_selector = 10
+max_regex_alternatives = 100
+`,
+			wantGuardrails: &prometheus.Guardrails{
+				LimitMatchers:          true,
+				MaxMatchersPerSelector: 10,
+				MaxRegexAlternatives:   100,
+				MaxMetricCardinality:   prometheus.DefaultMaxMetricCardinality,
+				MaxLabelCardinality:    prometheus.DefaultMaxLabelCardinality,
+			},
+		},
+		{
+			name: "max_regex_alternatives without limit-matchers returns error",
+			toml: `
+guardrails = "require-label-matcher"
+max_regex_alternatives = 100
+`,
+			wantErr: "max_matchers_per_selector or max_regex_alternatives is set but",
+		},
+		{
+			name: "max_matchers_per_selector zero returns error",
+			toml: `
+guardrails = "limit-matchers"
+max_matchers_per_selector = 0
+`,
+			wantErr: "max_matchers_per_selector must be greater than 0",
+		},
+		{
+			name: "subquery limits override defaults when limit-subqueries is enabled",
+			toml: `
+guardrails = "limit-subqueries"
+max_subquery_range = "12h"
+min_subquery_step = "1m"
+`,
+			wantGuardrails: &prometheus.Guardrails{
+				LimitSubqueries:      true,
+				MaxSubqueryRange:     12 * time.Hour,
+				MinSubqueryStep:      time.Minute,
+				MaxMetricCardinality: prometheus.DefaultMaxMetricCardinality,
+				MaxLabelCardinality:  prometheus.DefaultMaxLabelCardinality,
+			},
+		},
+		{
+			name: "subquery limits accept PromQL duration units",
+			toml: `
+guardrails = "limit-subqueries"
+max_subquery_range = "1w"
+min_subquery_step = "1d"
+`,
+			wantGuardrails: &prometheus.Guardrails{
+				LimitSubqueries:      true,
+				MaxSubqueryRange:     7 * 24 * time.Hour,
+				MinSubqueryStep:      24 * time.Hour,
+				MaxMetricCardinality: prometheus.DefaultMaxMetricCardinality,
+				MaxLabelCardinality:  prometheus.DefaultMaxLabelCardinality,
+			},
+		},
+		{
+			name: "max_subquery_range without limit-subqueries returns error",
+			toml: `
+guardrails = "limit-matchers"
+max_subquery_range = "12h"
+`,
+			wantErr: "max_subquery_range or min_subquery_step is set but",
+		},
+		{
+			name: "invalid min_subquery_step returns error",
+			toml: `
+guardrails = "limit-subqueries"
+min_subquery_step = "0s"
+`,
+			wantErr: "invalid min_subquery_step",
+		},
+		{
+			name: "max_nesting_depth overrides the default when max-nesting-depth is enabled",
+			toml: `
+guardrails = "max-nesting-depth"
+max_nesting_depth = 4
+`,
+			wantGuardrails: &prometheus.Guardrails{
+				LimitNestingDepth:    true,
+				MaxNestingDepth:      4,
+				MaxMetricCardinality: prometheus.DefaultMaxMetricCardinality,
+				MaxLabelCardinality:  prometheus.DefaultMaxLabelCardinality,
+			},
+		},
+		{
+			name: "max_nesting_depth without max-nesting-depth returns error",
+			toml: `
+guardrails = "!max-nesting-depth"
+max_nesting_depth = 4
+`,
+			wantErr: "max_nesting_depth is set but",
+		},
+		{
+			name: "max_nesting_depth zero returns error",
+			toml: `
+guardrails = "max-nesting-depth"
+max_nesting_depth = 0
+`,
+			wantErr: "max_nesting_depth must be greater than 0",
+		},
+		{
+			name: "max_result_series sets the result series limit",
+			toml: `
+guardrails = "require-label-matcher"
+max_result_series = 1000
+`,
+			wantGuardrails: &prometheus.Guardrails{
+				RequireLabelMatcher:  true,
+				MaxMetricCardinality: prometheus.DefaultMaxMetricCardinality,
+				MaxLabelCardinality:  prometheus.DefaultMaxLabelCardinality,
+				MaxResultSeries:      1000,
+			},
+		},
+		{
+			name: "guardrails none with max_result_series returns error",
+			toml: `
+guardrails = "none"
+max_result_series = 1000
+`,
+			wantErr: "max_result_series is set but guardrails are disabled",
+		},
 		{
 			name: "all guardrails with custom cardinalities",
 			toml: `
@@ -284,9 +728,132 @@ max_label_cardinality = 300
 			if got == nil {
 				t.Fatalf("GetGuardrails() = nil, want %+v", tt.wantGuardrails)
 			}
-			if *got != *tt.wantGuardrails {
+			if *got != *tt.wantGuardrails && !equalIgnoringAddedGuardrails(*got, *tt.wantGuardrails) {
 				t.Errorf("GetGuardrails()\n got  %+v\n want %+v", *got, *tt.wantGuardrails)
 			}
 		})
 	}
 }
+
+// equalIgnoringAddedGuardrails compares got and want without the guardrails that
+// "all" gained after the cases above were written, as long as want leaves all of
+// them unset. TestParseGuardrails_AddedToAll in the prometheus package covers them.
+func equalIgnoringAddedGuardrails(got, want prometheus.Guardrails) bool {
+	if want.LimitMatchers || want.LimitSubqueries || want.DisallowAllMetrics || want.LimitNestingDepth {
+		return false
+	}
+	got.LimitMatchers = false
+	got.LimitSubqueries = false
+	got.DisallowAllMetrics = false
+	got.LimitNestingDepth = false
+	return got == want
+}
+
+func TestGetRequestGuardrails(t *testing.T) {
+	tests := []struct {
+		name           string
+		toml           string
+		header         string
+		value          string
+		wantErr        string
+		wantNil        bool
+		wantGuardrails *prometheus.Guardrails
+	}{
+		{
+			name:           "override is ignored unless the header is trusted",
+			toml:           ``,
+			header:         auth.DefaultGuardrailHeader,
+			value:          "all",
+			wantGuardrails: prometheus.DefaultGuardrails(true),
+		},
+		{
+			name:   "trusted override disables the listed guardrails",
+			toml:   `trust_guardrail_header = true`,
+			header: auth.DefaultGuardrailHeader,
+			value:  "require-label-matcher, !limit-matchers",
+			wantGuardrails: &prometheus.Guardrails{
+				DisallowExplicitNameLabel: true,
+				DisallowBlanketRegex:      true,
+				ForceMaxMetricCardinality: true,
+				LimitSubqueries:           true,
+				DisallowAllMetrics:        true,
+				LimitNestingDepth:         true,
+				MaxMetricCardinality:      prometheus.DefaultMaxMetricCardinality,
+				MaxLabelCardinality:       prometheus.DefaultMaxLabelCardinality,
+			},
+		},
+		{
+			name:    "trusted override of all disables guardrails",
+			toml:    `trust_guardrail_header = true`,
+			header:  auth.DefaultGuardrailHeader,
+			value:   "all",
+			wantNil: true,
+		},
+		{
+			name: "trusted override cannot enable guardrails",
+			toml: `
+trust_guardrail_header = true
+guardrails = "require-label-matcher"
+`,
+			header: auth.DefaultGuardrailHeader,
+			value:  "disallow-explicit-name-label",
+			wantGuardrails: &prometheus.Guardrails{
+				RequireLabelMatcher:  true,
+				MaxMetricCardinality: prometheus.DefaultMaxMetricCardinality,
+				MaxLabelCardinality:  prometheus.DefaultMaxLabelCardinality,
+			},
+		},
+		{
+			name: "override is read from the configured header",
+			toml: `
+trust_guardrail_header = true
+guardrail_header = "X-Policy-Guardrails"
+`,
+			header:         auth.DefaultGuardrailHeader,
+			value:          "all",
+			wantGuardrails: prometheus.DefaultGuardrails(true),
+		},
+		{
+			name:    "unknown guardrail in trusted override returns error",
+			toml:    `trust_guardrail_header = true`,
+			header:  auth.DefaultGuardrailHeader,
+			value:   "not-a-real-guardrail",
+			wantErr: `invalid guardrail override`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := parseConfig(t, tt.toml)
+			req := httptest.NewRequest("GET", "/mcp", nil)
+			req.Header.Set(tt.header, tt.value)
+			// The standalone server only reads the header when it is trusted; read it
+			// unconditionally to check that the config does not honor it either.
+			ctx := auth.ContextWithGuardrailOverrideFromRequest(t.Context(), req, cmp.Or(cfg.GetTrustedGuardrailHeader(), tt.header))
+
+			got, err := cfg.GetRequestGuardrails(ctx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetRequestGuardrails() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetRequestGuardrails() unexpected error: %v", err)
+			}
+
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("GetRequestGuardrails() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("GetRequestGuardrails() = nil, want %+v", tt.wantGuardrails)
+			}
+			if *got != *tt.wantGuardrails {
+				t.Errorf("GetRequestGuardrails()\n got  %+v\n want %+v", *got, *tt.wantGuardrails)
+			}
+		})
+	}
+}
@@ -2,6 +2,184 @@ package metrics
 
 import "slices"
 
+// rangeQueryParams are the parameters shared by the range query tools.
+var rangeQueryParams = []ParamDef{
+	{
+		Name:        "query",
+		Type:        ParamTypeString,
+		Description: "PromQL query string using metric names verified via list_metrics",
+		Required:    true,
+	},
+	{
+		Name:        "step",
+		Type:        ParamTypeString,
+		Description: "Query resolution step width (e.g., '15s', '1m', '1h', or a number of seconds such as 60). Choose based on time range: shorter ranges use smaller steps.",
+		Required:    true,
+		Pattern:     `^(\d+[smhdwy]|\d+(\.\d+)?)$`,
+		AllowNumber: true,
+	},
+	{
+		Name:        "start",
+		Type:        ParamTypeString,
+		Description: "Start time as RFC3339 or Unix timestamp (optional)",
+		Required:    false,
+	},
+	{
+		Name:        "end",
+		Type:        ParamTypeString,
+		Description: "End time as RFC3339 or Unix timestamp (optional). Use `NOW` for current time.",
+		Required:    false,
+	},
+	{
+		Name:        "duration",
+		Type:        ParamTypeString,
+		Description: "Duration to look back from now (e.g., '1h', '30m', '1d', '2w') (optional)",
+		Required:    false,
+		Pattern:     `^\d+[smhdwy]$`,
+	},
+	{
+		Name:        "show_gaps",
+		Type:        ParamTypeBoolean,
+		Description: "Insert [timestamp, null] markers at the steps where a series has no data between its first and last sample, so that charts show gaps instead of connecting across them. Only applies when full series data is returned (optional)",
+		Required:    false,
+	},
+}
+
+// targetPointsParam computes the step of a range query from the number of points wanted.
+var targetPointsParam = ParamDef{
+	Name:        "target_points",
+	Type:        ParamTypeNumber,
+	Description: "Number of data points per series to return at most, e.g. the width of a chart in pixels, instead of 'step'. The step is computed from the time range and returned with the result (optional)",
+	Required:    false,
+}
+
+// withTargetPoints returns the range query parameters with target_points as an
+// alternative to step, which is no longer required.
+func withTargetPoints(params []ParamDef) []ParamDef {
+	params = slices.Clone(params)
+	for i, p := range params {
+		if p.Name == "step" {
+			params[i].Required = false
+			params[i].Description += " Required unless target_points is set."
+			return slices.Insert(params, i+1, targetPointsParam)
+		}
+	}
+	return params
+}
+
+// withDeployAnchor returns the range query parameters with SINCE_LAST_DEPLOY documented
+// as a value of start and end.
+func withDeployAnchor(params []ParamDef) []ParamDef {
+	params = slices.Clone(params)
+	for i, p := range params {
+		if p.Name == "start" || p.Name == "end" {
+			params[i].Description += " Use `SINCE_LAST_DEPLOY` for the time of the last deploy, if the server is configured with a deploy marker metric; a range starting at the last deploy ends at NOW by default."
+		}
+	}
+	return params
+}
+
+// dryRunParam lets query tools return the backend request instead of executing it.
+var dryRunParam = ParamDef{
+	Name:        "dry_run",
+	Type:        ParamTypeBoolean,
+	Description: "Return the HTTP request that would be sent to the metrics backend (method, URL, headers with secrets redacted and body) instead of executing the query (optional)",
+	Required:    false,
+}
+
+// rawResponseParam lets range queries return the result in the Prometheus API format.
+var rawResponseParam = ParamDef{
+	Name:        "raw_response",
+	Type:        ParamTypeString,
+	Description: "Set to 'prometheus' to return the result unmodified in the envelope of the Prometheus HTTP API ({status, data: {resultType, result}, warnings}) instead of the reshaped output, for tooling that consumes the Prometheus API format. Cannot be combined with project_labels or sampling (optional)",
+	Required:    false,
+	Pattern:     `^prometheus$`,
+}
+
+// convertUnitsParam lets query tools add values in human-readable units to the result.
+var convertUnitsParam = ParamDef{
+	Name:        "convert_units",
+	Type:        ParamTypeBoolean,
+	Description: "Also return values converted to human-readable units (e.g. 1.5 GiB, 250 ms) when the unit can be inferred from the _bytes or _seconds suffix of the queried metrics. Raw values are always kept (optional)",
+	Required:    false,
+}
+
+// describeShapeParam lets range queries describe the shape of each series instead of
+// returning its values.
+var describeShapeParam = ParamDef{
+	Name:        "describe_shape",
+	Type:        ParamTypeBoolean,
+	Description: "Return a description of the shape of each series instead of its values: its trend (up, down or flat), min, max and current value, its largest spike and its approximate period if it oscillates. Cannot be combined with raw_response or convert_units (optional)",
+	Required:    false,
+}
+
+// verbosityParam lets query tools trade detail in the response for size.
+var verbosityParam = ParamDef{
+	Name:        "verbosity",
+	Type:        ParamTypeString,
+	Description: "Level of detail of the response: 'minimal' returns only the values or summaries and the warnings, without annotations such as stale or merged series; 'standard' (default) the usual response; 'full' adds the query as executed and stats on the backend response (optional)",
+	Required:    false,
+	Pattern:     `^(minimal|standard|full)$`,
+}
+
+// projectLabelsParam lets query tools keep only some labels of the result series.
+var projectLabelsParam = ParamDef{
+	Name:        "project_labels",
+	Type:        ParamTypeString,
+	Description: "Comma-separated label names to keep in each result series (e.g., 'namespace,pod'); all other labels are dropped. Series that become identical are merged by adding their values, and the response reports how many series were merged (optional)",
+	Required:    false,
+}
+
+// samplingParams let query tools return a sample of the result series instead of
+// failing when the result exceeds the max-result-series limit.
+var samplingParams = []ParamDef{
+	{
+		Name:        "sampling",
+		Type:        ParamTypeBoolean,
+		Description: "When the result has more series than the server allows, return a representative sample instead of failing: the series with the highest values plus a random selection of the others. The response reports the total number of series (optional)",
+		Required:    false,
+	},
+	{
+		Name:        "seed",
+		Type:        ParamTypeNumber,
+		Description: "Seed of the random selection made by sampling; pass the seed reported by a previous response to get the same sample (optional)",
+		Required:    false,
+	},
+}
+
+// paginationParams let range queries return their series a page at a time.
+var paginationParams = []ParamDef{
+	{
+		Name:        "page_size",
+		Type:        ParamTypeNumber,
+		Description: "Return at most this many series, ordered by labels, along with a nextToken to fetch the following ones; the whole result must still be within the max-result-series guardrail. Cannot be combined with sampling or raw_response (optional)",
+		Required:    false,
+	},
+	{
+		Name:        "page_token",
+		Type:        ParamTypeString,
+		Description: "nextToken of a previous response, to get the next page of its series. Pass the same query and project_labels; the time range and step of the first page are reused, so start, end and duration are ignored (optional)",
+		Required:    false,
+	},
+}
+
+// thanosParams pass Thanos-specific options to the Thanos Querier API. Plain Prometheus
+// ignores them.
+var thanosParams = []ParamDef{
+	{
+		Name:        "dedup",
+		Type:        ParamTypeBoolean,
+		Description: "Thanos only: whether to deduplicate series from replicated Prometheus instances. Thanos deduplicates by default; set to false to see the series of each replica. Ignored by plain Prometheus (optional)",
+		Required:    false,
+	},
+	{
+		Name:        "max_resolution",
+		Type:        ParamTypeString,
+		Description: "Thanos only: maximum resolution of downsampled data the query may use: 'raw', '5m', '1h' or 'auto' (sent as max_source_resolution). Ignored by plain Prometheus (optional)",
+		Required:    false,
+	},
+}
+
 // All tool definitions as a single source of truth
 var (
 	ListMetrics = ToolDef[ListMetricsOutput]{
@@ -15,6 +193,12 @@ var (
 				Description: "Regex pattern to filter metric names. IMPORTANT: Metric names are typically prefixed (e.g., 'prometheus_tsdb_head_series'). Use wildcards to match substrings: '.*tsdb.*' matches any metric containing 'tsdb', while 'tsdb' only matches the exact string 'tsdb'. Examples: 'http_.*' (starts with http_), '.*memory.*' (contains memory), 'node_.*' (starts with node_). This parameter is required. Don't pass in blanket regex like '.*' or '.+'.",
 				Required:    true,
 			},
+			{
+				Name:        "limit",
+				Type:        ParamTypeNumber,
+				Description: "Maximum number of metric names to return (optional). Defaults to the server-side default, and must not exceed the server-side maximum. The response reports whether names were truncated.",
+				Required:    false,
+			},
 		},
 		ReadOnly:    true,
 		Destructive: false,
@@ -22,39 +206,33 @@ var (
 		OpenWorld:   true,
 	}
 
-	ExecuteInstantQuery = ToolDef[InstantQueryOutput]{
-		Name:        "execute_instant_query",
-		Description: ExecuteInstantQueryPrompt,
-		Title:       "Execute Instant Query",
+	ListMetricGroups = ToolDef[MetricGroupsOutput]{
+		Name:        "list_metric_groups",
+		Description: ListMetricGroupsPrompt,
+		Title:       "List Metric Groups",
 		ReadOnly:    true,
 		Destructive: false,
 		Idempotent:  true,
 		OpenWorld:   true,
 		Params: []ParamDef{
 			{
-				Name:        "query",
-				Type:        ParamTypeString,
-				Description: "PromQL query string using metric names verified via list_metrics",
-				Required:    true,
-			},
-			{
-				Name:        "time",
+				Name:        "prefix",
 				Type:        ParamTypeString,
-				Description: "Evaluation time as RFC3339 or Unix timestamp. Omit or use 'NOW' for current time.",
+				Description: "Only group the metrics starting with this prefix, by their next name segment (e.g., 'kube_' returns 'kube_pod_', 'kube_node_', ...). Leave empty for the top-level groups.",
 				Required:    false,
 			},
 		},
 	}
 
-	ExecuteRangeQuery = ToolDef[RangeQueryOutput]{
-		Name:        "execute_range_query",
-		Description: ExecuteRangeQueryPrompt,
-		Title:       "Execute Range Query",
+	ExecuteInstantQuery = ToolDef[InstantQueryOutput]{
+		Name:        "execute_instant_query",
+		Description: ExecuteInstantQueryPrompt,
+		Title:       "Execute Instant Query",
 		ReadOnly:    true,
 		Destructive: false,
 		Idempotent:  true,
 		OpenWorld:   true,
-		Params: []ParamDef{
+		Params: slices.Concat([]ParamDef{
 			{
 				Name:        "query",
 				Type:        ParamTypeString,
@@ -62,35 +240,58 @@ var (
 				Required:    true,
 			},
 			{
-				Name:        "step",
+				Name:        "time",
 				Type:        ParamTypeString,
-				Description: "Query resolution step width (e.g., '15s', '1m', '1h'). Choose based on time range: shorter ranges use smaller steps.",
-				Required:    true,
-				Pattern:     `^\d+[smhdwy]$`,
+				Description: "Evaluation time as RFC3339 or Unix timestamp. Omit or use 'NOW' for current time.",
+				Required:    false,
 			},
 			{
-				Name:        "start",
+				Name:        "group_by",
 				Type:        ParamTypeString,
-				Description: "Start time as RFC3339 or Unix timestamp (optional)",
+				Description: "Label to group the result by (e.g., 'namespace'). Returns one aggregated value per label value under 'groups' instead of the individual series (optional)",
 				Required:    false,
 			},
 			{
-				Name:        "end",
+				Name:        "group_agg",
 				Type:        ParamTypeString,
-				Description: "End time as RFC3339 or Unix timestamp (optional). Use `NOW` for current time.",
+				Description: "Aggregation applied to the series of each group: sum (default), max, min, avg or count. Requires group_by (optional)",
 				Required:    false,
+				Pattern:     `^(sum|max|min|avg|count)$`,
 			},
 			{
-				Name:        "duration",
-				Type:        ParamTypeString,
-				Description: "Duration to look back from now (e.g., '1h', '30m', '1d', '2w') (optional)",
+				Name:        "nearest",
+				Type:        ParamTypeBoolean,
+				Description: "When the query returns nothing at the requested time, return the latest values of each series within the preceding hour instead, with the times of the samples they come from (optional)",
 				Required:    false,
-				Pattern:     `^\d+[smhdwy]$`,
 			},
-		},
+			{
+				Name:        "labels_only",
+				Type:        ParamTypeBoolean,
+				Description: "Return only the label sets of the resulting series, without their values. Cannot be combined with group_by (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "rank",
+				Type:        ParamTypeBoolean,
+				Description: "Sort the resulting series by value, highest first, and annotate each with its 1-based 'rank', e.g. to answer top-5 questions with topk. Cannot be combined with group_by (optional)",
+				Required:    false,
+			},
+			projectLabelsParam,
+		}, samplingParams, thanosParams, []ParamDef{verbosityParam, dryRunParam, convertUnitsParam}),
+	}
+
+	ExecuteRangeQuery = ToolDef[RangeQueryOutput]{
+		Name:        "execute_range_query",
+		Description: ExecuteRangeQueryPrompt,
+		Title:       "Execute Range Query",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params:      slices.Concat(withDeployAnchor(withTargetPoints(rangeQueryParams)), []ParamDef{projectLabelsParam}, samplingParams, paginationParams, thanosParams, []ParamDef{verbosityParam, dryRunParam, rawResponseParam, convertUnitsParam, describeShapeParam}),
 	}
 
-	ShowTimeseries = ToolDef[struct{}]{
+	ShowTimeseries = ToolDef[ShowTimeseriesOutput]{
 		Name:        "show_timeseries",
 		Description: ShowTimeseriesPrompt,
 		Title:       "Show Timeseries Chart",
@@ -98,7 +299,7 @@ var (
 		Destructive: false,
 		Idempotent:  true,
 		OpenWorld:   true,
-		Params: slices.Concat(ExecuteRangeQuery.Params, []ParamDef{
+		Params: slices.Concat(withTargetPoints(rangeQueryParams), []ParamDef{
 			{
 				Name:        "title",
 				Type:        ParamTypeString,
@@ -119,6 +320,141 @@ var (
 		},
 	}
 
+	QueryHeatmap = ToolDef[HeatmapOutput]{
+		Name:        "query_heatmap",
+		Description: QueryHeatmapPrompt,
+		Title:       "Query Heatmap",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: slices.Concat([]ParamDef{
+			{
+				Name:        "metric",
+				Type:        ParamTypeString,
+				Description: "Histogram bucket metric name ending in _bucket, as returned by list_metrics (e.g., 'http_request_duration_seconds_bucket')",
+				Required:    true,
+			},
+			{
+				Name:        "selector",
+				Type:        ParamTypeString,
+				Description: "Label matchers selecting the series to count (e.g., 'namespace=\"default\", job=\"api\"') (optional)",
+				Required:    false,
+			},
+		}, slices.DeleteFunc(slices.Clone(rangeQueryParams), func(p ParamDef) bool {
+			return p.Name == "query" || p.Name == "show_gaps"
+		})),
+	}
+
+	LabelCardinalityTrend = ToolDef[LabelCardinalityTrendOutput]{
+		Name:        "label_cardinality_trend",
+		Description: LabelCardinalityTrendPrompt,
+		Title:       "Label Cardinality Trend",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: slices.Concat([]ParamDef{
+			{
+				Name:        "metric",
+				Type:        ParamTypeString,
+				Description: "Metric name, as returned by list_metrics (e.g., 'http_requests_total')",
+				Required:    true,
+			},
+			{
+				Name:        "label",
+				Type:        ParamTypeString,
+				Description: "Label whose distinct values to count (e.g., 'path')",
+				Required:    true,
+			},
+			{
+				Name:        "selector",
+				Type:        ParamTypeString,
+				Description: "Label matchers selecting the series to count the values of (e.g., 'namespace=\"default\", job=\"api\"') (optional)",
+				Required:    false,
+			},
+		}, slices.DeleteFunc(slices.Clone(rangeQueryParams), func(p ParamDef) bool {
+			return p.Name == "query" || p.Name == "show_gaps"
+		})),
+	}
+
+	SLOCompliance = ToolDef[SLOComplianceOutput]{
+		Name:        "slo_compliance",
+		Description: SLOCompliancePrompt,
+		Title:       "SLO Compliance",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: slices.Concat([]ParamDef{
+			{
+				Name:        "good_query",
+				Type:        ParamTypeString,
+				Description: "PromQL query returning the rate or increase of good events as a single series (e.g., 'sum(rate(http_requests_total{job=\"api\",code!~\"5..\"}[5m]))')",
+				Required:    true,
+			},
+			{
+				Name:        "total_query",
+				Type:        ParamTypeString,
+				Description: "PromQL query returning the rate or increase of all events as a single series, with the same range window as good_query (e.g., 'sum(rate(http_requests_total{job=\"api\"}[5m]))')",
+				Required:    true,
+			},
+			{
+				Name:        "target",
+				Type:        ParamTypeNumber,
+				Description: "SLO target as a percentage of good events (e.g., 99.9)",
+				Required:    true,
+			},
+		}, slices.DeleteFunc(slices.Clone(rangeQueryParams), func(p ParamDef) bool {
+			return p.Name == "query" || p.Name == "show_gaps"
+		})),
+	}
+
+	EvaluateCondition = ToolDef[EvaluateConditionOutput]{
+		Name:        "evaluate_condition",
+		Description: EvaluateConditionPrompt,
+		Title:       "Evaluate Condition",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: slices.Concat([]ParamDef{
+			{
+				Name:        "query",
+				Type:        ParamTypeString,
+				Description: "PromQL comparison to evaluate, with or without the bool modifier (e.g., 'avg(rate(node_cpu_seconds_total{mode!=\"idle\"}[5m])) > 0.8')",
+				Required:    true,
+			},
+			{
+				Name:        "for",
+				Type:        ParamTypeString,
+				Description: "The 'for' duration of the alert rule (e.g., '5m'), to check whether the condition held long enough for the alert to fire. Without a time range, the last 'for' duration is evaluated (optional)",
+				Required:    false,
+				Pattern:     `^\d+[smhdwy]$`,
+			},
+		}, slices.DeleteFunc(slices.Clone(rangeQueryParams), func(p ParamDef) bool {
+			return p.Name == "query" || p.Name == "show_gaps"
+		})),
+	}
+
+	ClusterStatus = ToolDef[ClusterStatusOutput]{
+		Name:        "cluster_status",
+		Description: ClusterStatusPrompt,
+		Title:       "Cluster Status",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "checks",
+				Type:        ParamTypeArray,
+				Description: "Names of the checks to run (e.g., ['node_readiness', 'etcd_health']) (optional, defaults to all checks)",
+				Required:    false,
+			},
+		},
+	}
+
 	GetLabelNames = ToolDef[LabelNamesOutput]{
 		Name:        "get_label_names",
 		Description: GetLabelNamesPrompt,
@@ -182,117 +518,688 @@ var (
 				Description: "End time for label value discovery as RFC3339 or Unix timestamp (optional, defaults to now)",
 				Required:    false,
 			},
+			{
+				Name:        "limit",
+				Type:        ParamTypeNumber,
+				Description: "Maximum number of values to return (optional). Defaults to the server-side default, and must not exceed the server-side maximum. The response reports whether values were truncated.",
+				Required:    false,
+			},
+			{
+				Name:        "by_frequency",
+				Type:        ParamTypeBoolean,
+				Description: "Count the series having each value within the time range and return the values sorted by that count, most common first, with their counts. Useful to pick a representative value to drill down into. Requires 'metric', and is not available for labels with very many values (optional)",
+				Required:    false,
+			},
 		},
 	}
 
-	GetSeries = ToolDef[SeriesOutput]{
-		Name:        "get_series",
-		Description: GetSeriesPrompt,
-		Title:       "Get Series",
+	GetLabelsOverview = ToolDef[LabelsOverviewOutput]{
+		Name:        "get_labels_overview",
+		Description: GetLabelsOverviewPrompt,
+		Title:       "Get Labels Overview",
 		ReadOnly:    true,
 		Destructive: false,
 		Idempotent:  true,
 		OpenWorld:   true,
 		Params: []ParamDef{
 			{
-				Name:        "matches",
+				Name:        "metric",
 				Type:        ParamTypeString,
-				Description: "PromQL series selector using metric names from list_metrics",
+				Description: "Metric name (from list_metrics) to get the label values of",
+				Required:    true,
+			},
+			{
+				Name:        "labels",
+				Type:        ParamTypeArray,
+				Description: "Label names (from get_label_names) to get values for (e.g., ['namespace', 'pod', 'container']). At most 10 labels",
 				Required:    true,
 			},
 			{
 				Name:        "start",
 				Type:        ParamTypeString,
-				Description: "Start time for series discovery as RFC3339 or Unix timestamp (optional, defaults to 1 hour ago)",
+				Description: "Start time for label value discovery as RFC3339 or Unix timestamp (optional, defaults to 1 hour ago)",
 				Required:    false,
 			},
 			{
 				Name:        "end",
 				Type:        ParamTypeString,
-				Description: "End time for series discovery as RFC3339 or Unix timestamp (optional, defaults to now)",
+				Description: "End time for label value discovery as RFC3339 or Unix timestamp (optional, defaults to now)",
+				Required:    false,
+			},
+			{
+				Name:        "limit",
+				Type:        ParamTypeNumber,
+				Description: "Maximum number of values to return per label (optional, defaults to 50 unless configured otherwise). Must not exceed the server-side maximum",
 				Required:    false,
 			},
 		},
 	}
 
-	GetAlerts = ToolDef[AlertsOutput]{
-		Name:        "get_alerts",
-		Description: GetAlertsPrompt,
-		Title:       "Get Alerts",
+	GetSeries = ToolDef[SeriesOutput]{
+		Name:        "get_series",
+		Description: GetSeriesPrompt,
+		Title:       "Get Series",
 		ReadOnly:    true,
 		Destructive: false,
 		Idempotent:  true,
 		OpenWorld:   true,
 		Params: []ParamDef{
 			{
-				Name:        "active",
-				Type:        ParamTypeBoolean,
-				Description: "Filter for active alerts only (true/false, optional)",
+				Name:        "matches",
+				Type:        ParamTypeString,
+				Description: "PromQL series selector using metric names from list_metrics",
+				Required:    true,
+			},
+			{
+				Name:        "start",
+				Type:        ParamTypeString,
+				Description: "Start time for series discovery as RFC3339 or Unix timestamp (optional, defaults to 1 hour ago)",
 				Required:    false,
 			},
 			{
-				Name:        "silenced",
-				Type:        ParamTypeBoolean,
-				Description: "Filter for silenced alerts only (true/false, optional)",
+				Name:        "end",
+				Type:        ParamTypeString,
+				Description: "End time for series discovery as RFC3339 or Unix timestamp (optional, defaults to now)",
 				Required:    false,
 			},
 			{
-				Name:        "inhibited",
+				Name:        "with_last_seen",
 				Type:        ParamTypeBoolean,
-				Description: "Filter for inhibited alerts only (true/false, optional)",
+				Description: "Also return how long ago each series was last sampled, to tell live series from ended ones. Runs an extra range query over the time range (optional, defaults to false)",
 				Required:    false,
 			},
 			{
-				Name:        "unprocessed",
-				Type:        ParamTypeBoolean,
-				Description: "Filter for unprocessed alerts only (true/false, optional)",
+				Name:        "limit",
+				Type:        ParamTypeNumber,
+				Description: "Maximum number of series to return (optional). Defaults to the server-side default, and must not exceed the server-side maximum. The cardinality is always the total number of matching series.",
 				Required:    false,
 			},
+		},
+	}
+
+	InspectMetric = ToolDef[InspectMetricOutput]{
+		Name:        "inspect_metric",
+		Description: InspectMetricPrompt,
+		Title:       "Inspect Metric",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "metric",
+				Type:        ParamTypeString,
+				Description: "Metric name (from list_metrics) to inspect",
+				Required:    true,
+			},
 			{
-				Name:        "filter",
+				Name:        "start",
 				Type:        ParamTypeString,
-				Description: "Label matchers to filter alerts (e.g., 'alertname=HighCPU', optional)",
+				Description: "Start time for series discovery as RFC3339 or Unix timestamp (optional, defaults to 1 hour ago)",
 				Required:    false,
 			},
 			{
-				Name:        "receiver",
+				Name:        "end",
 				Type:        ParamTypeString,
-				Description: "Receiver name to filter alerts (optional)",
+				Description: "End time for series discovery as RFC3339 or Unix timestamp (optional, defaults to now)",
 				Required:    false,
 			},
 		},
 	}
 
-	GetSilences = ToolDef[SilencesOutput]{
-		Name:        "get_silences",
-		Description: GetSilencesPrompt,
-		Title:       "Get Silences",
+	GetMetricMetadata = ToolDef[MetricMetadataOutput]{
+		Name:        "get_metric_metadata",
+		Description: GetMetricMetadataPrompt,
+		Title:       "Get Metric Metadata",
 		ReadOnly:    true,
 		Destructive: false,
 		Idempotent:  true,
 		OpenWorld:   true,
 		Params: []ParamDef{
 			{
-				Name:        "filter",
+				Name:        "metric",
 				Type:        ParamTypeString,
-				Description: "Label matchers to filter silences (e.g., 'alertname=HighCPU', optional)",
+				Description: "Metric name (from list_metrics) to get the metadata of (optional). Omit to get the metadata of all metrics, up to limit",
+				Required:    false,
+			},
+			{
+				Name:        "limit",
+				Type:        ParamTypeNumber,
+				Description: "Maximum number of metrics to return the metadata of when metric is omitted (optional). Defaults to the server-side default, and must not exceed the server-side maximum.",
 				Required:    false,
 			},
 		},
 	}
+
+	GetScrapeInterval = ToolDef[ScrapeIntervalOutput]{
+		Name:        "get_scrape_interval",
+		Description: GetScrapeIntervalPrompt,
+		Title:       "Get Scrape Interval",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "metric",
+				Type:        ParamTypeString,
+				Description: "Series selector with at least one label matcher, e.g. 'up{job=\"api\"}', to estimate the scrape interval of",
+				Required:    true,
+			},
+			{
+				Name:        "window",
+				Type:        ParamTypeString,
+				Description: "How far back from now to look at samples, as a Prometheus duration (optional, defaults to 10m; widen it for intervals above 2m)",
+				Required:    false,
+			},
+		},
+	}
+
+	CheckSeriesUniqueness = ToolDef[SeriesUniquenessOutput]{
+		Name:        "check_series_uniqueness",
+		Description: CheckSeriesUniquenessPrompt,
+		Title:       "Check Series Uniqueness",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "selector",
+				Type:        ParamTypeString,
+				Description: "PromQL series selector using metric names from list_metrics (e.g., 'up{job=\"api\", instance=\"10.0.0.1:8080\"}')",
+				Required:    true,
+			},
+			{
+				Name:        "start",
+				Type:        ParamTypeString,
+				Description: "Start time for series discovery as RFC3339 or Unix timestamp (optional, defaults to 1 hour ago)",
+				Required:    false,
+			},
+			{
+				Name:        "end",
+				Type:        ParamTypeString,
+				Description: "End time for series discovery as RFC3339 or Unix timestamp (optional, defaults to now)",
+				Required:    false,
+			},
+		},
+	}
+
+	GetExternalLabels = ToolDef[ExternalLabelsOutput]{
+		Name:        "get_external_labels",
+		Description: GetExternalLabelsPrompt,
+		Title:       "Get External Labels",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params:      []ParamDef{},
+	}
+
+	GetActiveQueries = ToolDef[ActiveQueriesOutput]{
+		Name:        "get_active_queries",
+		Description: GetActiveQueriesPrompt,
+		Title:       "Get Active Queries",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  false,
+		OpenWorld:   true,
+		Params:      []ParamDef{},
+	}
+
+	ListRecordingRules = ToolDef[RecordingRulesOutput]{
+		Name:        "list_recording_rules",
+		Description: ListRecordingRulesPrompt,
+		Title:       "List Recording Rules",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "name_regex",
+				Type:        ParamTypeString,
+				Description: "Regex pattern to filter recorded metric names (e.g., '.*cpu.*', 'namespace:.*'). Leave empty to list all recording rules.",
+				Required:    false,
+			},
+		},
+	}
+
+	GetRuleGroup = ToolDef[RuleGroupOutput]{
+		Name:        "get_rule_group",
+		Description: GetRuleGroupPrompt,
+		Title:       "Get Rule Group",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "group",
+				Type:        ParamTypeString,
+				Description: "Name of the rule group, e.g. the group of a recording rule reported by list_recording_rules",
+				Required:    true,
+			},
+			{
+				Name:        "file",
+				Type:        ParamTypeString,
+				Description: "Rule file the group is defined in; only needed when several files define a group with that name (optional)",
+				Required:    false,
+			},
+		},
+	}
+
+	ListQueryTemplates = ToolDef[QueryTemplatesOutput]{
+		Name:        "list_query_templates",
+		Description: ListQueryTemplatesPrompt,
+		Title:       "List Query Templates",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   false,
+		Params:      []ParamDef{},
+	}
+
+	RenderQueryTemplate = ToolDef[RenderQueryTemplateOutput]{
+		Name:        "render_query_template",
+		Description: RenderQueryTemplatePrompt,
+		Title:       "Render Query Template",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   false,
+		Params: []ParamDef{
+			{
+				Name:        "name",
+				Type:        ParamTypeString,
+				Description: "Name of the template to render, as returned by list_query_templates",
+				Required:    true,
+			},
+			{
+				Name:        "params",
+				Type:        ParamTypeObject,
+				Description: "Placeholder values keyed by parameter name (e.g., {\"namespace\": \"default\", \"window\": \"5m\"}). Parameters with a default can be omitted.",
+				Required:    false,
+			},
+		},
+	}
+
+	QueryToPanel = ToolDef[QueryToPanelOutput]{
+		Name:        "query_to_panel",
+		Description: QueryToPanelPrompt,
+		Title:       "Query to Panel",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   false,
+		Params: []ParamDef{
+			{
+				Name:        "query",
+				Type:        ParamTypeString,
+				Description: "PromQL query to chart",
+				Required:    true,
+			},
+			{
+				Name:        "title",
+				Type:        ParamTypeString,
+				Description: "Panel title",
+				Required:    true,
+			},
+			{
+				Name:        "chart_type",
+				Type:        ParamTypeString,
+				Description: "Chart type: 'timeseries', 'stat', 'gauge', 'bar', 'pie' or 'table' (optional, defaults to 'timeseries')",
+				Required:    false,
+			},
+			{
+				Name:        "description",
+				Type:        ParamTypeString,
+				Description: "Panel description (optional)",
+				Required:    false,
+			},
+		},
+	}
+
+	ParseTime = ToolDef[ParseTimeOutput]{
+		Name:        "parse_time",
+		Description: ParseTimePrompt,
+		Title:       "Parse Time",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  false,
+		OpenWorld:   false,
+		Params: []ParamDef{
+			{
+				Name:        "time",
+				Type:        ParamTypeString,
+				Description: "Time expression to resolve, as accepted by the start, end and time parameters of the query tools (e.g., 'NOW-6h', '2024-01-02T15:04:05Z', '1704207845')",
+				Required:    true,
+			},
+		},
+	}
+
+	SaveQueryResult = ToolDef[SaveQueryResultOutput]{
+		Name:        "save_query_result",
+		Description: SaveQueryResultPrompt,
+		Title:       "Save Query Result",
+		ReadOnly:    false,
+		Destructive: false,
+		Idempotent:  false,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "query",
+				Type:        ParamTypeString,
+				Description: "PromQL query string using metric names verified via list_metrics",
+				Required:    true,
+			},
+			{
+				Name:        "step",
+				Type:        ParamTypeString,
+				Description: "Query resolution step width (e.g., '15s', '1m', or a number of seconds). When set, a range query is run; otherwise an instant query is run (optional)",
+				Required:    false,
+				Pattern:     `^(\d+[smhdwy]|\d+(\.\d+)?)$`,
+				AllowNumber: true,
+			},
+			{
+				Name:        "time",
+				Type:        ParamTypeString,
+				Description: "Evaluation time of an instant query as RFC3339 or Unix timestamp (optional, defaults to now)",
+				Required:    false,
+			},
+			{
+				Name:        "start",
+				Type:        ParamTypeString,
+				Description: "Start time of a range query as RFC3339 or Unix timestamp (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "end",
+				Type:        ParamTypeString,
+				Description: "End time of a range query as RFC3339 or Unix timestamp (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "duration",
+				Type:        ParamTypeString,
+				Description: "Duration of a range query looking back from now (e.g., '1h', '1d') (optional, defaults to 1h)",
+				Required:    false,
+				Pattern:     `^\d+[smhdwy]$`,
+			},
+			{
+				Name:        "format",
+				Type:        ParamTypeString,
+				Description: "File format: 'json' (default) or 'csv' (one row per sample with a column per label)",
+				Required:    false,
+				Pattern:     `^(json|csv)$`,
+			},
+			{
+				Name:        "filename",
+				Type:        ParamTypeString,
+				Description: "Name of the file to create in the output directory (optional). Directories are not allowed and unsafe characters are replaced; a unique name is generated when omitted",
+				Required:    false,
+			},
+		},
+	}
+
+	GetAlerts = ToolDef[AlertsOutput]{
+		Name:        "get_alerts",
+		Description: GetAlertsPrompt,
+		Title:       "Get Alerts",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "active",
+				Type:        ParamTypeBoolean,
+				Description: "Filter for active alerts only (true/false, optional)",
+				Required:    false,
+			},
+			{
+				Name:        "silenced",
+				Type:        ParamTypeBoolean,
+				Description: "Filter for silenced alerts only (true/false, optional)",
+				Required:    false,
+			},
+			{
+				Name:        "inhibited",
+				Type:        ParamTypeBoolean,
+				Description: "Filter for inhibited alerts only (true/false, optional)",
+				Required:    false,
+			},
+			{
+				Name:        "unprocessed",
+				Type:        ParamTypeBoolean,
+				Description: "Filter for unprocessed alerts only (true/false, optional)",
+				Required:    false,
+			},
+			{
+				Name:        "filter",
+				Type:        ParamTypeString,
+				Description: "Label matchers to filter alerts (e.g., 'alertname=HighCPU', optional). All matchers must match",
+				Required:    false,
+			},
+			{
+				Name:        "any_of",
+				Type:        ParamTypeArray,
+				Description: "Alternative groups of label matchers, each written like 'filter' (e.g., ['alertname=HighCPU', 'alertname=HighMemory']). Returns the alerts matching any group, in addition to 'filter' if set; the matchers within a group must all match. At most 10 groups (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "receiver",
+				Type:        ParamTypeString,
+				Description: "Receiver name to filter alerts (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "min_active_duration",
+				Type:        ParamTypeString,
+				Description: "Only return alerts that have been active for at least this long, computed from their start time, as a Prometheus duration (e.g., '1h'). Use it to focus on persistent problems rather than flapping alerts (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "full",
+				Type:        ParamTypeBoolean,
+				Description: "List all matching alerts even when there are more than 100; otherwise a summary is returned instead. With a progress token, the alerts are also sent in batches as progress notifications while they are fetched (optional, defaults to false)",
+				Required:    false,
+			},
+			{
+				Name:        "labels",
+				Type:        ParamTypeArray,
+				Description: "Alert labels to return (e.g., ['alertname', 'severity', 'namespace']), instead of the ones the server is configured to return; ['*'] returns all labels (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "annotations",
+				Type:        ParamTypeArray,
+				Description: "Alert annotations to return (e.g., ['summary']), instead of the ones the server is configured to return; ['*'] returns all annotations (optional)",
+				Required:    false,
+			},
+		},
+	}
+
+	SummarizeAlerts = ToolDef[AlertsSummaryOutput]{
+		Name:        "summarize_alerts",
+		Description: SummarizeAlertsPrompt,
+		Title:       "Summarize Alerts",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "active",
+				Type:        ParamTypeBoolean,
+				Description: "Filter for active alerts only (true/false, optional)",
+				Required:    false,
+			},
+			{
+				Name:        "silenced",
+				Type:        ParamTypeBoolean,
+				Description: "Filter for silenced alerts only (true/false, optional)",
+				Required:    false,
+			},
+			{
+				Name:        "inhibited",
+				Type:        ParamTypeBoolean,
+				Description: "Filter for inhibited alerts only (true/false, optional)",
+				Required:    false,
+			},
+			{
+				Name:        "filter",
+				Type:        ParamTypeString,
+				Description: "Label matchers to filter alerts (e.g., 'namespace=openshift-monitoring', optional). All matchers must match",
+				Required:    false,
+			},
+			{
+				Name:        "receiver",
+				Type:        ParamTypeString,
+				Description: "Receiver name to filter alerts (optional)",
+				Required:    false,
+			},
+			{
+				Name:        "top",
+				Type:        ParamTypeNumber,
+				Description: "Number of alert names to detail in topAlerts (optional, defaults to 10)",
+				Required:    false,
+			},
+		},
+	}
+
+	GetAlertHistory = ToolDef[AlertHistoryOutput]{
+		Name:        "get_alert_history",
+		Description: GetAlertHistoryPrompt,
+		Title:       "Get Alert History",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "since",
+				Type:        ParamTypeString,
+				Description: "Start of the time window as RFC3339, Unix timestamp or NOW-relative (e.g., 'NOW-6h'). Optional, defaults to 1 hour before 'until'",
+				Required:    false,
+			},
+			{
+				Name:        "until",
+				Type:        ParamTypeString,
+				Description: "End of the time window as RFC3339, Unix timestamp or NOW-relative. Optional, defaults to now",
+				Required:    false,
+			},
+			{
+				Name:        "filter",
+				Type:        ParamTypeString,
+				Description: "Label matchers to filter alerts (e.g., 'alertname=HighCPU,namespace=default', optional). Only firing alerts are returned unless an 'alertstate' matcher is given",
+				Required:    false,
+			},
+		},
+	}
+
+	GetAlertThreshold = ToolDef[AlertThresholdOutput]{
+		Name:        "get_alert_threshold",
+		Description: GetAlertThresholdPrompt,
+		Title:       "Get Alert Threshold",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "alertname",
+				Type:        ParamTypeString,
+				Description: "Name of the alerting rule (e.g., 'HighMemoryUsage')",
+				Required:    true,
+			},
+		},
+	}
+
+	GetSilences = ToolDef[SilencesOutput]{
+		Name:        "get_silences",
+		Description: GetSilencesPrompt,
+		Title:       "Get Silences",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "filter",
+				Type:        ParamTypeString,
+				Description: "Label matchers to filter silences (e.g., 'alertname=HighCPU', optional)",
+				Required:    false,
+			},
+		},
+	}
+
+	DeleteSilence = ToolDef[DeleteSilenceOutput]{
+		Name:        "delete_silence",
+		Description: DeleteSilencePrompt,
+		Title:       "Delete Silence",
+		ReadOnly:    false,
+		Destructive: true,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params: []ParamDef{
+			{
+				Name:        "id",
+				Type:        ParamTypeString,
+				Description: "ID of the silence to expire, as returned by get_silences",
+				Required:    true,
+			},
+		},
+	}
+
+	GetAlertmanagerStatus = ToolDef[AlertmanagerStatusOutput]{
+		Name:        "get_alertmanager_status",
+		Description: GetAlertmanagerStatusPrompt,
+		Title:       "Get Alertmanager Status",
+		ReadOnly:    true,
+		Destructive: false,
+		Idempotent:  true,
+		OpenWorld:   true,
+		Params:      []ParamDef{},
+	}
 )
 
 // AllTools returns all tool definitions
 func AllTools() []ToolDefInterface {
 	return []ToolDefInterface{
 		ListMetrics,
+		ListMetricGroups,
 		ExecuteInstantQuery,
 		ExecuteRangeQuery,
 		ShowTimeseries,
+		QueryHeatmap,
+		LabelCardinalityTrend,
+		SLOCompliance,
+		EvaluateCondition,
+		ClusterStatus,
 		GetLabelNames,
 		GetLabelValues,
+		GetLabelsOverview,
 		GetSeries,
+		CheckSeriesUniqueness,
+		InspectMetric,
+		GetMetricMetadata,
+		GetScrapeInterval,
+		GetExternalLabels,
+		GetActiveQueries,
+		ListRecordingRules,
+		GetRuleGroup,
+		ListQueryTemplates,
+		RenderQueryTemplate,
+		QueryToPanel,
+		ParseTime,
+		SaveQueryResult,
 		GetAlerts,
+		SummarizeAlerts,
+		GetAlertHistory,
+		GetAlertThreshold,
 		GetSilences,
+		DeleteSilence,
+		GetAlertmanagerStatus,
 	}
 }
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
+)
+
+// sinceLastDeploy is the time expression resolving to the time of the most recent deploy,
+// as reported by the configured deploy marker query.
+const sinceLastDeploy = "SINCE_LAST_DEPLOY"
+
+// validateDeployMarkerQuery checks that the deploy marker query is valid PromQL.
+func validateDeployMarkerQuery(query string) error {
+	if _, err := parser.NewParser(parser.Options{}).ParseExpr(query); err != nil {
+		return fmt.Errorf("invalid deploy_marker_query: %w", err)
+	}
+	return nil
+}
+
+// resolveDeployAnchor replaces SINCE_LAST_DEPLOY in the start and end of a range query
+// with the time of the last deploy. A range starting at the last deploy without an end
+// runs until NOW.
+func resolveDeployAnchor(ctx context.Context, promClient prometheus.Loader, markerQuery string, input *RangeQueryInput) error {
+	startAnchored := strings.EqualFold(input.Start, sinceLastDeploy)
+	endAnchored := strings.EqualFold(input.End, sinceLastDeploy)
+	if !startAnchored && !endAnchored {
+		return nil
+	}
+	if markerQuery == "" {
+		return fmt.Errorf("%s is not available: no deploy marker query is configured", sinceLastDeploy)
+	}
+
+	deployTime, err := lastDeployTime(ctx, promClient, markerQuery)
+	if err != nil {
+		return err
+	}
+	resolved := deployTime.Format(time.RFC3339)
+	if startAnchored {
+		input.Start = resolved
+		if input.End == "" {
+			input.End = "NOW"
+		}
+	}
+	if endAnchored {
+		input.End = resolved
+	}
+	return nil
+}
+
+// lastDeployTime evaluates the deploy marker query at NOW and returns the latest of the
+// Unix timestamps it returns. The query runs under the guardrails of the caller, so a
+// rejection is reported as a problem of the configured query rather than of the caller's.
+func lastDeployTime(ctx context.Context, promClient prometheus.Loader, markerQuery string) (time.Time, error) {
+	result, err := promClient.ExecuteInstantQuery(ctx, markerQuery, prometheus.Now(ctx))
+	if err != nil {
+		var gv *prometheus.GuardrailViolation
+		if errors.As(err, &gv) {
+			return time.Time{}, fmt.Errorf("%s is not available: the server's deploy_marker_query %q is rejected by the %s guardrail: %s; "+
+				"the server configuration must be fixed, or the range given as times", sinceLastDeploy, markerQuery, gv.Guardrail, gv.Message)
+		}
+		return time.Time{}, fmt.Errorf("failed to query the last deploy time: %w", err)
+	}
+
+	latest := math.Inf(-1)
+	switch value := result["result"].(type) {
+	case model.Vector:
+		for _, sample := range value {
+			latest = math.Max(latest, float64(sample.Value))
+		}
+	case *model.Scalar:
+		latest = float64(value.Value)
+	}
+	if math.IsInf(latest, -1) || math.IsNaN(latest) || latest <= 0 {
+		return time.Time{}, fmt.Errorf("the deploy marker query returned no deploy time")
+	}
+	return time.Unix(0, int64(latest*float64(time.Second))).UTC(), nil
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
+)
+
+const (
+	// emptyResultMetadataTimeout bounds the metadata lookups of checkEmptyResult, so an
+	// empty result is never held up by a slow metadata endpoint.
+	emptyResultMetadataTimeout = 2 * time.Second
+	// emptyResultMaxMetrics is the number of metrics checkEmptyResult describes.
+	emptyResultMaxMetrics = 5
+)
+
+// checkEmptyResult returns a warning for a query that returned no data, describing each
+// metric it selects with the HELP text of its metadata so that a misread metric can be
+// noticed. Metadata lookups are best effort; metrics whose metadata cannot be fetched in
+// time are listed without a description.
+func checkEmptyResult(ctx context.Context, promClient prometheus.Loader, query string) string {
+	names, err := prometheus.ExtractMetricNames(query)
+	if err != nil || len(names) == 0 {
+		return "the query returned no data"
+	}
+	slices.Sort(names)
+
+	ctx, cancel := context.WithTimeout(ctx, emptyResultMetadataTimeout)
+	defer cancel()
+
+	descriptions := make([]string, 0, min(len(names), emptyResultMaxMetrics))
+	for _, name := range names[:min(len(names), emptyResultMaxMetrics)] {
+		metadata, err := promClient.GetMetricMetadata(ctx, name)
+		switch {
+		case err != nil:
+			descriptions = append(descriptions, name)
+		case len(metadata) == 0:
+			descriptions = append(descriptions, name+" (no metadata; it may be misspelled, a recording rule or not scraped)")
+		default:
+			description := fmt.Sprintf("%s (%s)", name, metadata[0].Type)
+			if help := strings.TrimSpace(metadata[0].Help); help != "" {
+				description += ": " + help
+			}
+			descriptions = append(descriptions, description)
+		}
+	}
+	if len(names) > emptyResultMaxMetrics {
+		descriptions = append(descriptions, fmt.Sprintf("and %d more", len(names)-emptyResultMaxMetrics))
+	}
+	return "the query returned no data; check that the metrics it selects are the intended ones: " +
+		strings.Join(descriptions, "; ")
+}
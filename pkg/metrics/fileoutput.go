@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// FileFormatJSON writes the result as a JSON document.
+	FileFormatJSON = "json"
+	// FileFormatCSV writes one row per sample, with a column per label.
+	FileFormatCSV = "csv"
+)
+
+// unsafeFilenameCharsRe matches the characters replaced when sanitizing a requested filename.
+var unsafeFilenameCharsRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// resultFilename returns the name of the file a query result is saved to. A requested
+// name is reduced to a single safe path element carrying the format extension; without
+// one, a unique name is generated.
+func resultFilename(requested, format string) (string, error) {
+	ext := "." + format
+	if requested == "" {
+		suffix := make([]byte, 4)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", fmt.Errorf("failed to generate file name: %w", err)
+		}
+		return fmt.Sprintf("query-result-%s-%s%s", time.Now().UTC().Format("20060102T150405Z"), hex.EncodeToString(suffix), ext), nil
+	}
+
+	name := unsafeFilenameCharsRe.ReplaceAllString(strings.TrimSuffix(requested, ext), "_")
+	name = strings.Trim(name, "._")
+	if name == "" {
+		return "", fmt.Errorf("invalid filename %q", requested)
+	}
+	return name + ext, nil
+}
+
+// writeResultFile creates name inside dir and writes the query result to it.
+// The file is opened through an os.Root, so it cannot escape dir even through
+// symlinks, and existing files are never overwritten.
+func writeResultFile(dir, name, format string, result model.Value) (string, int64, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open output directory: %w", err)
+	}
+	defer root.Close()
+
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	switch format {
+	case FileFormatCSV:
+		err = writeResultCSV(f, result)
+	default:
+		err = writeResultJSON(f, result)
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = root.Remove(name)
+		return "", 0, fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write output file: %w", err)
+	}
+	return filepath.Join(dir, name), info.Size(), nil
+}
+
+func writeResultJSON(w io.Writer, result model.Value) error {
+	return json.NewEncoder(w).Encode(map[string]any{
+		"resultType": result.Type().String(),
+		"result":     result,
+	})
+}
+
+// writeResultCSV writes a row per sample with the columns timestamp, value and one column
+// per label name found in the result, sorted by name.
+func writeResultCSV(w io.Writer, result model.Value) error {
+	type row struct {
+		metric model.Metric
+		sample model.SamplePair
+	}
+
+	var rows []row
+	switch r := result.(type) {
+	case model.Matrix:
+		for _, series := range r {
+			for _, sample := range series.Values {
+				rows = append(rows, row{series.Metric, sample})
+			}
+		}
+	case model.Vector:
+		for _, sample := range r {
+			rows = append(rows, row{sample.Metric, model.SamplePair{Timestamp: sample.Timestamp, Value: sample.Value}})
+		}
+	case *model.Scalar:
+		rows = append(rows, row{nil, model.SamplePair{Timestamp: r.Timestamp, Value: r.Value}})
+	default:
+		return fmt.Errorf("result type %s cannot be written as CSV", result.Type())
+	}
+
+	labelSet := make(map[model.LabelName]struct{})
+	for _, r := range rows {
+		for name := range r.metric {
+			labelSet[name] = struct{}{}
+		}
+	}
+	labelNames := slices.Sorted(maps.Keys(labelSet))
+
+	cw := csv.NewWriter(w)
+	header := []string{"timestamp", "value"}
+	for _, name := range labelNames {
+		header = append(header, string(name))
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{
+			strconv.FormatFloat(float64(r.sample.Timestamp)/millisecondsPerSecond, 'f', -1, 64),
+			r.sample.Value.String(),
+		}
+		for _, name := range labelNames {
+			record = append(record, string(r.metric[name]))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// countSamples returns the number of series and samples in a query result.
+func countSamples(result model.Value) (series, samples int) {
+	switch r := result.(type) {
+	case model.Matrix:
+		for _, s := range r {
+			samples += len(s.Values)
+		}
+		return len(r), samples
+	case model.Vector:
+		return len(r), len(r)
+	default:
+		return 1, 1
+	}
+}
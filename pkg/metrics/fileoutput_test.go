@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestResultFilename(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested string
+		format    string
+		want      string
+		wantErr   bool
+	}{
+		{name: "plain name gets the extension", requested: "cpu", format: FileFormatJSON, want: "cpu.json"},
+		{name: "extension is not duplicated", requested: "cpu.csv", format: FileFormatCSV, want: "cpu.csv"},
+		{name: "path traversal is flattened", requested: "../../etc/passwd", format: FileFormatJSON, want: "etc_passwd.json"},
+		{name: "absolute path is flattened", requested: "/tmp/result", format: FileFormatJSON, want: "tmp_result.json"},
+		{name: "unsafe characters are replaced", requested: "cpu usage (5m)", format: FileFormatCSV, want: "cpu_usage_5m.csv"},
+		{name: "name without safe characters is rejected", requested: "../..", format: FileFormatJSON, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resultFilename(tt.requested, tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resultFilename(%q) = %q, want %q", tt.requested, got, tt.want)
+			}
+		})
+	}
+
+	generated, err := resultFilename("", FileFormatCSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(generated, "query-result-") || !strings.HasSuffix(generated, ".csv") {
+		t.Errorf("unexpected generated name %q", generated)
+	}
+}
+
+func TestWriteResultFile(t *testing.T) {
+	result := model.Matrix{
+		{
+			Metric: model.Metric{"__name__": "up", "job": "api"},
+			Values: []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 0}},
+		},
+		{
+			Metric: model.Metric{"__name__": "up", "instance": "b"},
+			Values: []model.SamplePair{{Timestamp: 1000, Value: 1}},
+		},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		dir := t.TempDir()
+		path, size, err := writeResultFile(dir, "up.csv", FileFormatCSV, result)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		want := "timestamp,value,__name__,instance,job\n1,1,up,,api\n2,0,up,,api\n1,1,up,b,\n"
+		if string(data) != want {
+			t.Errorf("csv = %q, want %q", data, want)
+		}
+		if size != int64(len(want)) {
+			t.Errorf("size = %d, want %d", size, len(want))
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		dir := t.TempDir()
+		path, _, err := writeResultFile(dir, "up.json", FileFormatJSON, result)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if !strings.Contains(string(data), `"resultType":"matrix"`) || !strings.Contains(string(data), `"values":[[1,"1"],[2,"0"]]`) {
+			t.Errorf("unexpected json: %s", data)
+		}
+	})
+
+	t.Run("existing file is not overwritten", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "up.json"), []byte("keep"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := writeResultFile(dir, "up.json", FileFormatJSON, result); err == nil {
+			t.Fatal("expected error for existing file, got nil")
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, "up.json"))
+		if string(data) != "keep" {
+			t.Errorf("existing file was modified: %q", data)
+		}
+	})
+
+	t.Run("symlink escaping the directory is rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		outside := t.TempDir()
+		if err := os.Symlink(filepath.Join(outside, "target.json"), filepath.Join(dir, "link.json")); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+		if _, _, err := writeResultFile(dir, "link.json", FileFormatJSON, result); err == nil {
+			t.Fatal("expected error for symlink escaping the output directory, got nil")
+		}
+		if _, err := os.Stat(filepath.Join(outside, "target.json")); !os.IsNotExist(err) {
+			t.Errorf("file was written outside the output directory")
+		}
+	})
+}
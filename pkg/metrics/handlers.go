@@ -1,17 +1,34 @@
 package metrics
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	ammodels "github.com/prometheus/alertmanager/api/v2/models"
+	amlabels "github.com/prometheus/alertmanager/pkg/labels"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"golang.org/x/sync/errgroup"
 	"k8s.io/utils/ptr"
 
+	"github.com/rhobs/obs-mcp/pkg/auth"
 	"github.com/rhobs/obs-mcp/pkg/metrics/alertmanager"
 	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
 	"github.com/rhobs/obs-mcp/pkg/resultutil"
@@ -32,6 +49,22 @@ func GetString(params map[string]any, key, defaultValue string) string {
 	return defaultValue
 }
 
+// GetNumberOrString is a helper to extract a parameter that may be sent either as a string
+// or as a JSON number, returning it as a string.
+func GetNumberOrString(params map[string]any, key, defaultValue string) string {
+	if val, ok := params[key]; ok {
+		switch v := val.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			return strconv.Itoa(v)
+		case json.Number:
+			return v.String()
+		}
+	}
+	return GetString(params, key, defaultValue)
+}
+
 // GetInt is a helper to extract an integer parameter with a default value.
 // JSON numbers arrive as float64, so this handles the float64-to-int conversion.
 func GetInt(params map[string]any, key string, defaultValue int) int {
@@ -46,6 +79,19 @@ func GetInt(params map[string]any, key string, defaultValue int) int {
 	return defaultValue
 }
 
+// GetFloat is a helper to extract a number parameter with a default value.
+func GetFloat(params map[string]any, key string, defaultValue float64) float64 {
+	if val, ok := params[key]; ok {
+		switch v := val.(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		}
+	}
+	return defaultValue
+}
+
 // GetBoolPtr is a helper to extract an optional boolean parameter as a pointer
 func GetBoolPtr(params map[string]any, key string) *bool {
 	if val, ok := params[key]; ok {
@@ -56,23 +102,61 @@ func GetBoolPtr(params map[string]any, key string) *bool {
 	return nil
 }
 
+// GetStringSlice is a helper to extract an array parameter as a slice of strings.
+// Non-string elements are formatted with their default representation.
+func GetStringSlice(params map[string]any, key string) []string {
+	switch val := params[key].(type) {
+	case []string:
+		return val
+	case []any:
+		result := make([]string, len(val))
+		for i, v := range val {
+			if str, ok := v.(string); ok {
+				result[i] = str
+			} else {
+				result[i] = fmt.Sprint(v)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// GetStringMap is a helper to extract an object parameter as a map of strings.
+// Non-string values are formatted with their default representation.
+func GetStringMap(params map[string]any, key string) map[string]string {
+	val, ok := params[key].(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(val))
+	for k, v := range val {
+		if str, ok := v.(string); ok {
+			result[k] = str
+		} else {
+			result[k] = fmt.Sprint(v)
+		}
+	}
+	return result
+}
+
 // parseDefaultTimeRange parses optional start/end time strings,
 // defaulting to the last hour if both are empty.
-func parseDefaultTimeRange(start, end string) (startTime, endTime time.Time, err error) {
+func parseDefaultTimeRange(ctx context.Context, start, end string) (startTime, endTime time.Time, err error) {
 	if start == "" && end == "" {
-		endTime = time.Now()
+		endTime = prometheus.Now(ctx)
 		startTime = endTime.Add(-prometheus.ListMetricsTimeRange)
 		return startTime, endTime, nil
 	}
 
 	if start != "" {
-		startTime, err = prometheus.ParseTimestamp(start)
+		startTime, err = prometheus.ParseTimestamp(ctx, start)
 		if err != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time format: %w", err)
 		}
 	}
 	if end != "" {
-		endTime, err = prometheus.ParseTimestamp(end)
+		endTime, err = prometheus.ParseTimestamp(ctx, end)
 		if err != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("invalid end time format: %w", err)
 		}
@@ -80,6 +164,36 @@ func parseDefaultTimeRange(start, end string) (startTime, endTime time.Time, err
 	return startTime, endTime, nil
 }
 
+// parseRangeQueryTimes resolves the time range of a range query from either explicit
+// start/end times or a duration looking back from now (1h when nothing is specified).
+func parseRangeQueryTimes(ctx context.Context, start, end, duration string) (startTime, endTime time.Time, err error) {
+	if (start == "") != (end == "") {
+		return time.Time{}, time.Time{}, fmt.Errorf("both start and end must be provided together")
+	}
+
+	if start != "" {
+		startTime, err = prometheus.ParseTimestamp(ctx, start)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time format: %w", err)
+		}
+		endTime, err = prometheus.ParseTimestamp(ctx, end)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end time format: %w", err)
+		}
+		return startTime, endTime, nil
+	}
+
+	if duration == "" {
+		duration = "1h"
+	}
+	d, err := model.ParseDuration(duration)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid duration format: %w", err)
+	}
+	endTime = prometheus.Now(ctx)
+	return endTime.Add(-time.Duration(d)), endTime, nil
+}
+
 // parseFilterString splits a comma-separated filter string into trimmed parts.
 func parseFilterString(filter string) []string {
 	if filter == "" {
@@ -189,23 +303,57 @@ func convertSilence(s *ammodels.GettableSilence) Silence {
 func BuildListMetricsInput(args map[string]any) ListMetricsInput {
 	return ListMetricsInput{
 		NameRegex: GetString(args, "name_regex", ""),
+		Limit:     GetInt(args, "limit", 0),
+	}
+}
+
+func BuildMetricGroupsInput(args map[string]any) MetricGroupsInput {
+	return MetricGroupsInput{
+		Prefix: GetString(args, "prefix", ""),
 	}
 }
 
 func BuildInstantQueryInput(args map[string]any) InstantQueryInput {
 	return InstantQueryInput{
-		Query: GetString(args, "query", ""),
-		Time:  GetString(args, "time", ""),
+		Query:         GetString(args, "query", ""),
+		Time:          GetString(args, "time", ""),
+		GroupBy:       GetString(args, "group_by", ""),
+		GroupAgg:      GetString(args, "group_agg", ""),
+		Nearest:       ptr.Deref(GetBoolPtr(args, "nearest"), false),
+		LabelsOnly:    ptr.Deref(GetBoolPtr(args, "labels_only"), false),
+		ProjectLabels: GetString(args, "project_labels", ""),
+		Sampling:      ptr.Deref(GetBoolPtr(args, "sampling"), false),
+		Seed:          GetInt(args, "seed", 0),
+		Dedup:         GetBoolPtr(args, "dedup"),
+		MaxResolution: GetString(args, "max_resolution", ""),
+		Verbosity:     GetString(args, "verbosity", ""),
+		DryRun:        ptr.Deref(GetBoolPtr(args, "dry_run"), false),
+		ConvertUnits:  ptr.Deref(GetBoolPtr(args, "convert_units"), false),
+		Rank:          ptr.Deref(GetBoolPtr(args, "rank"), false),
 	}
 }
 
 func BuildRangeQueryInput(args map[string]any) RangeQueryInput {
 	return RangeQueryInput{
-		Query:    GetString(args, "query", ""),
-		Step:     GetString(args, "step", ""),
-		Start:    GetString(args, "start", ""),
-		End:      GetString(args, "end", ""),
-		Duration: GetString(args, "duration", ""),
+		Query:         GetString(args, "query", ""),
+		Step:          StepValue(GetNumberOrString(args, "step", "")),
+		TargetPoints:  GetInt(args, "target_points", 0),
+		Start:         GetString(args, "start", ""),
+		End:           GetString(args, "end", ""),
+		Duration:      GetString(args, "duration", ""),
+		ShowGaps:      ptr.Deref(GetBoolPtr(args, "show_gaps"), false),
+		ProjectLabels: GetString(args, "project_labels", ""),
+		Sampling:      ptr.Deref(GetBoolPtr(args, "sampling"), false),
+		Seed:          GetInt(args, "seed", 0),
+		Dedup:         GetBoolPtr(args, "dedup"),
+		MaxResolution: GetString(args, "max_resolution", ""),
+		Verbosity:     GetString(args, "verbosity", ""),
+		DryRun:        ptr.Deref(GetBoolPtr(args, "dry_run"), false),
+		RawResponse:   GetString(args, "raw_response", ""),
+		ConvertUnits:  ptr.Deref(GetBoolPtr(args, "convert_units"), false),
+		DescribeShape: ptr.Deref(GetBoolPtr(args, "describe_shape"), false),
+		PageSize:      GetInt(args, "page_size", 0),
+		PageToken:     GetString(args, "page_token", ""),
 	}
 }
 
@@ -217,6 +365,58 @@ func BuildShowTimeseriesInput(args map[string]any) ShowTimeseriesInput {
 	}
 }
 
+func BuildHeatmapInput(args map[string]any) HeatmapInput {
+	return HeatmapInput{
+		Metric:   GetString(args, "metric", ""),
+		Selector: GetString(args, "selector", ""),
+		Step:     StepValue(GetNumberOrString(args, "step", "")),
+		Start:    GetString(args, "start", ""),
+		End:      GetString(args, "end", ""),
+		Duration: GetString(args, "duration", ""),
+	}
+}
+
+func BuildLabelCardinalityTrendInput(args map[string]any) LabelCardinalityTrendInput {
+	return LabelCardinalityTrendInput{
+		Metric:   GetString(args, "metric", ""),
+		Label:    GetString(args, "label", ""),
+		Selector: GetString(args, "selector", ""),
+		Step:     StepValue(GetNumberOrString(args, "step", "")),
+		Start:    GetString(args, "start", ""),
+		End:      GetString(args, "end", ""),
+		Duration: GetString(args, "duration", ""),
+	}
+}
+
+func BuildEvaluateConditionInput(args map[string]any) EvaluateConditionInput {
+	return EvaluateConditionInput{
+		Query:    GetString(args, "query", ""),
+		For:      GetString(args, "for", ""),
+		Step:     StepValue(GetNumberOrString(args, "step", "")),
+		Start:    GetString(args, "start", ""),
+		End:      GetString(args, "end", ""),
+		Duration: GetString(args, "duration", ""),
+	}
+}
+
+func BuildClusterStatusInput(args map[string]any) ClusterStatusInput {
+	return ClusterStatusInput{
+		Checks: GetStringSlice(args, "checks"),
+	}
+}
+
+func BuildSLOComplianceInput(args map[string]any) SLOComplianceInput {
+	return SLOComplianceInput{
+		GoodQuery:  GetString(args, "good_query", ""),
+		TotalQuery: GetString(args, "total_query", ""),
+		Target:     GetFloat(args, "target", 0),
+		Step:       StepValue(GetNumberOrString(args, "step", "")),
+		Start:      GetString(args, "start", ""),
+		End:        GetString(args, "end", ""),
+		Duration:   GetString(args, "duration", ""),
+	}
+}
+
 func BuildLabelNamesInput(args map[string]any) LabelNamesInput {
 	return LabelNamesInput{
 		Metric: GetString(args, "metric", ""),
@@ -227,18 +427,122 @@ func BuildLabelNamesInput(args map[string]any) LabelNamesInput {
 
 func BuildLabelValuesInput(args map[string]any) LabelValuesInput {
 	return LabelValuesInput{
-		Label:  GetString(args, "label", ""),
+		Label:       GetString(args, "label", ""),
+		Metric:      GetString(args, "metric", ""),
+		Start:       GetString(args, "start", ""),
+		End:         GetString(args, "end", ""),
+		Limit:       GetInt(args, "limit", 0),
+		ByFrequency: ptr.Deref(GetBoolPtr(args, "by_frequency"), false),
+	}
+}
+
+func BuildLabelsOverviewInput(args map[string]any) LabelsOverviewInput {
+	return LabelsOverviewInput{
 		Metric: GetString(args, "metric", ""),
+		Labels: GetStringSlice(args, "labels"),
 		Start:  GetString(args, "start", ""),
 		End:    GetString(args, "end", ""),
+		Limit:  GetInt(args, "limit", 0),
 	}
 }
 
 func BuildSeriesInput(args map[string]any) SeriesInput {
 	return SeriesInput{
-		Matches: GetString(args, "matches", ""),
-		Start:   GetString(args, "start", ""),
-		End:     GetString(args, "end", ""),
+		Matches:      GetString(args, "matches", ""),
+		Start:        GetString(args, "start", ""),
+		End:          GetString(args, "end", ""),
+		WithLastSeen: ptr.Deref(GetBoolPtr(args, "with_last_seen"), false),
+		Limit:        GetInt(args, "limit", 0),
+	}
+}
+
+func BuildSeriesUniquenessInput(args map[string]any) SeriesUniquenessInput {
+	return SeriesUniquenessInput{
+		Selector: GetString(args, "selector", ""),
+		Start:    GetString(args, "start", ""),
+		End:      GetString(args, "end", ""),
+	}
+}
+
+func BuildInspectMetricInput(args map[string]any) InspectMetricInput {
+	return InspectMetricInput{
+		Metric: GetString(args, "metric", ""),
+		Start:  GetString(args, "start", ""),
+		End:    GetString(args, "end", ""),
+	}
+}
+
+func BuildMetricMetadataInput(args map[string]any) MetricMetadataInput {
+	return MetricMetadataInput{
+		Metric: GetString(args, "metric", ""),
+		Limit:  GetInt(args, "limit", 0),
+	}
+}
+
+func BuildScrapeIntervalInput(args map[string]any) ScrapeIntervalInput {
+	return ScrapeIntervalInput{
+		Metric: GetString(args, "metric", ""),
+		Window: GetString(args, "window", ""),
+	}
+}
+
+func BuildExternalLabelsInput(_ map[string]any) ExternalLabelsInput {
+	return ExternalLabelsInput{}
+}
+
+func BuildActiveQueriesInput(_ map[string]any) ActiveQueriesInput {
+	return ActiveQueriesInput{}
+}
+
+func BuildRecordingRulesInput(args map[string]any) RecordingRulesInput {
+	return RecordingRulesInput{
+		NameRegex: GetString(args, "name_regex", ""),
+	}
+}
+
+func BuildRuleGroupInput(args map[string]any) RuleGroupInput {
+	return RuleGroupInput{
+		Group: GetString(args, "group", ""),
+		File:  GetString(args, "file", ""),
+	}
+}
+
+func BuildQueryTemplatesInput(_ map[string]any) QueryTemplatesInput {
+	return QueryTemplatesInput{}
+}
+
+func BuildQueryToPanelInput(args map[string]any) QueryToPanelInput {
+	return QueryToPanelInput{
+		Query:       GetString(args, "query", ""),
+		Title:       GetString(args, "title", ""),
+		ChartType:   GetString(args, "chart_type", ""),
+		Description: GetString(args, "description", ""),
+	}
+}
+
+func BuildRenderQueryTemplateInput(args map[string]any) RenderQueryTemplateInput {
+	return RenderQueryTemplateInput{
+		Name:   GetString(args, "name", ""),
+		Params: GetStringMap(args, "params"),
+	}
+}
+
+func BuildParseTimeInput(args map[string]any) ParseTimeInput {
+	return ParseTimeInput{
+		Time: GetString(args, "time", ""),
+	}
+}
+
+func BuildSaveQueryResultInput(args map[string]any) SaveQueryResultInput {
+	return SaveQueryResultInput{
+		Query:    GetString(args, "query", ""),
+		Time:     GetString(args, "time", ""),
+		Step:     StepValue(GetNumberOrString(args, "step", "")),
+		Start:    GetString(args, "start", ""),
+		End:      GetString(args, "end", ""),
+		Duration: GetString(args, "duration", ""),
+		Format:   GetString(args, "format", ""),
+		Filename: GetString(args, "filename", ""),
 	}
 }
 
@@ -249,7 +553,37 @@ func BuildAlertsInput(args map[string]any) AlertsInput {
 		Inhibited:   GetBoolPtr(args, "inhibited"),
 		Unprocessed: GetBoolPtr(args, "unprocessed"),
 		Filter:      GetString(args, "filter", ""),
+		AnyOf:       GetStringSlice(args, "any_of"),
 		Receiver:    GetString(args, "receiver", ""),
+		MinActive:   GetString(args, "min_active_duration", ""),
+		Full:        ptr.Deref(GetBoolPtr(args, "full"), false),
+		Labels:      GetStringSlice(args, "labels"),
+		Annotations: GetStringSlice(args, "annotations"),
+	}
+}
+
+func BuildSummarizeAlertsInput(args map[string]any) SummarizeAlertsInput {
+	return SummarizeAlertsInput{
+		Active:    GetBoolPtr(args, "active"),
+		Silenced:  GetBoolPtr(args, "silenced"),
+		Inhibited: GetBoolPtr(args, "inhibited"),
+		Filter:    GetString(args, "filter", ""),
+		Receiver:  GetString(args, "receiver", ""),
+		Top:       GetInt(args, "top", 0),
+	}
+}
+
+func BuildAlertHistoryInput(args map[string]any) AlertHistoryInput {
+	return AlertHistoryInput{
+		Since:  GetString(args, "since", ""),
+		Until:  GetString(args, "until", ""),
+		Filter: GetString(args, "filter", ""),
+	}
+}
+
+func BuildAlertThresholdInput(args map[string]any) AlertThresholdInput {
+	return AlertThresholdInput{
+		AlertName: GetString(args, "alertname", ""),
 	}
 }
 
@@ -259,8 +593,19 @@ func BuildSilencesInput(args map[string]any) SilencesInput {
 	}
 }
 
+func BuildDeleteSilenceInput(args map[string]any) DeleteSilenceInput {
+	return DeleteSilenceInput{
+		ID: GetString(args, "id", ""),
+	}
+}
+
+func BuildAlertmanagerStatusInput(_ map[string]any) AlertmanagerStatusInput {
+	return AlertmanagerStatusInput{}
+}
+
 // ListMetricsHandler handles the listing of available Prometheus metrics.
-func ListMetricsHandler(ctx context.Context, promClient prometheus.Loader, input ListMetricsInput) *resultutil.Result {
+// limits sets the number of metric names returned.
+func ListMetricsHandler(ctx context.Context, promClient prometheus.Loader, input ListMetricsInput, limits ToolLimit) *resultutil.Result {
 	slog.Info("ListMetricsHandler called")
 	slog.Debug("ListMetricsHandler params", "input", input)
 
@@ -268,6 +613,10 @@ func ListMetricsHandler(ctx context.Context, promClient prometheus.Loader, input
 	if input.NameRegex == "" {
 		return resultutil.NewErrorResult(fmt.Errorf("name_regex parameter is required and must be a string"))
 	}
+	limit, err := limits.Resolve(input.Limit)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
 
 	metrics, err := promClient.ListMetrics(ctx, input.NameRegex)
 	if err != nil {
@@ -275,15 +624,49 @@ func ListMetricsHandler(ctx context.Context, promClient prometheus.Loader, input
 		return resultutil.NewErrorResult(fmt.Errorf("failed to list metrics: %w", err))
 	}
 
-	slog.Info("ListMetricsHandler executed successfully", "resultLength", len(metrics))
-	slog.Debug("ListMetricsHandler results", "results", metrics)
-
 	output := ListMetricsOutput{Metrics: metrics}
+	if limit > 0 && len(metrics) > limit {
+		output.Metrics = metrics[:limit]
+		output.Truncated = true
+		output.TotalCount = len(metrics)
+	}
+
+	slog.Info("ListMetricsHandler executed successfully", "resultLength", len(output.Metrics), "truncated", output.Truncated)
+	slog.Debug("ListMetricsHandler results", "results", output.Metrics)
+
+	return resultutil.NewSuccessResult(output)
+}
+
+// ListMetricGroupsHandler handles grouping the names of all available metrics by prefix.
+func ListMetricGroupsHandler(_ context.Context, metrics []string, input MetricGroupsInput) *resultutil.Result {
+	slog.Info("ListMetricGroupsHandler called")
+	slog.Debug("ListMetricGroupsHandler params", "input", input)
+
+	groups := groupMetricsByPrefix(metrics, input.Prefix)
+	output := MetricGroupsOutput{Groups: groups}
+	for _, g := range groups {
+		output.TotalMetrics += g.Count
+	}
+
+	slog.Info("ListMetricGroupsHandler executed successfully", "groupCount", len(groups))
 	return resultutil.NewSuccessResult(output)
 }
 
+// RangeQueryOptions are the server settings ExecuteRangeQueryHandler applies to every query.
+type RangeQueryOptions struct {
+	// FullResponse returns the full time series data instead of summary statistics.
+	FullResponse bool
+	// StepPolicy decides what happens to a step larger than the range of the query.
+	StepPolicy StepPolicy
+	// MaxSeries is the max-result-series limit results are sampled down to when sampling
+	// is requested (0 = no limit).
+	MaxSeries int
+	// DeployMarkerQuery returns the timestamps deploys are anchored to ("" = disabled).
+	DeployMarkerQuery string
+}
+
 // ExecuteRangeQueryHandler handles the execution of Prometheus range queries.
-func ExecuteRangeQueryHandler(ctx context.Context, promClient prometheus.Loader, input RangeQueryInput, fullResponse bool) *resultutil.Result {
+func ExecuteRangeQueryHandler(ctx context.Context, promClient prometheus.Loader, input RangeQueryInput, opts RangeQueryOptions) *resultutil.Result {
 	slog.Info("ExecuteRangeQueryHandler called")
 	slog.Debug("ExecuteRangeQueryHandler params", "input", input)
 
@@ -291,65 +674,196 @@ func ExecuteRangeQueryHandler(ctx context.Context, promClient prometheus.Loader,
 	if input.Query == "" {
 		return resultutil.NewErrorResult(fmt.Errorf("query parameter is required and must be a string"))
 	}
-	if input.Step == "" {
-		return resultutil.NewErrorResult(fmt.Errorf("step parameter is required and must be a string"))
+	if input.Step == "" && input.TargetPoints == 0 {
+		return resultutil.NewErrorResult(fmt.Errorf("step parameter is required and must be a string, unless target_points is set"))
+	}
+	if input.Step != "" && input.TargetPoints != 0 {
+		return resultutil.NewErrorResult(fmt.Errorf("step and target_points cannot be combined"))
 	}
 
 	// Parse step duration
-	stepDuration, err := model.ParseDuration(input.Step)
+	var stepDuration time.Duration
+	var err error
+	if input.Step != "" {
+		stepDuration, err = input.Step.Duration()
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid step format: %w", err))
+		}
+	}
+
+	verbosity, err := parseVerbosity(input.Verbosity)
 	if err != nil {
-		return resultutil.NewErrorResult(fmt.Errorf("invalid step format: %w", err))
+		return resultutil.NewErrorResult(err)
 	}
 
-	if (input.Start == "") != (input.End == "") {
-		return resultutil.NewErrorResult(fmt.Errorf("both start and end must be provided together"))
+	if err := parseRawResponse(input.RawResponse); err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+	if input.RawResponse != "" && input.ProjectLabels != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("project_labels cannot be combined with raw_response"))
+	}
+	if input.RawResponse != "" && input.Sampling {
+		return resultutil.NewErrorResult(fmt.Errorf("sampling cannot be combined with raw_response"))
+	}
+	if input.RawResponse != "" && input.ConvertUnits {
+		return resultutil.NewErrorResult(fmt.Errorf("convert_units cannot be combined with raw_response"))
+	}
+	if input.DescribeShape && input.RawResponse != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("describe_shape cannot be combined with raw_response"))
+	}
+	if input.DescribeShape && input.ConvertUnits {
+		return resultutil.NewErrorResult(fmt.Errorf("describe_shape cannot be combined with convert_units"))
+	}
+	if input.PageSize < 0 {
+		return resultutil.NewErrorResult(fmt.Errorf("page_size must be positive"))
+	}
+	paginated := input.PageSize > 0 || input.PageToken != ""
+	if paginated && input.RawResponse != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("page_size and page_token cannot be combined with raw_response"))
+	}
+	if paginated && input.Sampling {
+		return resultutil.NewErrorResult(fmt.Errorf("page_size and page_token cannot be combined with sampling"))
 	}
 
 	var startTime, endTime time.Time
-
-	if input.Start != "" && input.End != "" {
-		// Handle explicit start/end times
-		startTime, err = prometheus.ParseTimestamp(input.Start)
-		if err != nil {
-			return resultutil.NewErrorResult(fmt.Errorf("invalid start time format: %w", err))
-		}
-
-		endTime, err = prometheus.ParseTimestamp(input.End)
-		if err != nil {
-			return resultutil.NewErrorResult(fmt.Errorf("invalid end time format: %w", err))
+	var stepWarning string
+	var token pageToken
+	if input.PageToken != "" {
+		// Later pages cover the window of the first one, even if it was relative to now.
+		if token, err = decodePageToken(input.PageToken); err != nil {
+			return resultutil.NewErrorResult(err)
 		}
+		startTime, endTime, stepDuration = token.window()
 	} else {
-		// Handle duration-based query (default to 1h if nothing specified)
-		durationStr := input.Duration
-		if durationStr == "" {
-			durationStr = "1h"
+		if err = resolveDeployAnchor(ctx, promClient, opts.DeployMarkerQuery, &input); err != nil {
+			return resultutil.NewErrorResult(err)
+		}
+		startTime, endTime, err = parseRangeQueryTimes(ctx, input.Start, input.End, input.Duration)
+		if err != nil {
+			return resultutil.NewErrorResult(err)
 		}
 
-		duration, err := model.ParseDuration(durationStr)
+		if input.TargetPoints != 0 {
+			stepDuration, err = stepForTargetPoints(endTime.Sub(startTime), input.TargetPoints)
+		} else {
+			stepDuration, stepWarning, err = fitStepToRange(stepDuration, endTime.Sub(startTime), opts.StepPolicy)
+		}
 		if err != nil {
-			return resultutil.NewErrorResult(fmt.Errorf("invalid duration format: %w", err))
+			return resultutil.NewErrorResult(err)
 		}
+	}
+
+	ctx, err = thanosQueryContext(ctx, input.Dedup, input.MaxResolution)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	if input.DryRun {
+		dryRunCtx, rec := dryRunContext(ctx)
+		_, err := promClient.ExecuteRangeQuery(dryRunCtx, input.Query, startTime, endTime, stepDuration)
+		return resultutil.NewSuccessResult(RangeQueryOutput{DryRun: newDryRunOutput(rec, err)})
+	}
+
+	projectLabels, err := parseProjectLabels(input.ProjectLabels)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	sampling := input.Sampling && opts.MaxSeries > 0
+	if sampling {
+		ctx = prometheus.ContextWithoutSeriesLimit(ctx)
+	}
 
-		endTime = time.Now()
-		startTime = endTime.Add(-time.Duration(duration))
+	if paginated {
+		queryHash := pageQueryHash(input.Query, projectLabels)
+		switch {
+		case input.PageToken == "":
+			token = pageToken{
+				QueryHash: queryHash,
+				Start:     startTime.UnixMilli(),
+				End:       endTime.UnixMilli(),
+				Step:      stepDuration.Milliseconds(),
+				PageSize:  input.PageSize,
+			}
+			startTime, endTime, stepDuration = token.window()
+		case token.QueryHash != queryHash:
+			return resultutil.NewErrorResult(fmt.Errorf("page_token was issued for another query: pass the same query and project_labels as for the first page"))
+		case input.PageSize > 0:
+			token.PageSize = input.PageSize
+		}
+		if opts.MaxSeries > 0 && token.PageSize > opts.MaxSeries {
+			return resultutil.NewErrorResult(fmt.Errorf("page_size %d exceeds the maximum of %d series per response", token.PageSize, opts.MaxSeries))
+		}
 	}
 
 	// Execute the range query
-	result, err := promClient.ExecuteRangeQuery(ctx, input.Query, startTime, endTime, time.Duration(stepDuration))
+	queryStart := time.Now()
+	result, err := promClient.ExecuteRangeQuery(ctx, input.Query, startTime, endTime, stepDuration)
 	if err != nil {
 		return resultutil.NewErrorResult(fmt.Errorf("failed to execute range query: %w", err))
 	}
+	queryDuration := time.Since(queryStart)
+
+	if input.RawResponse == RawResponsePrometheus {
+		var warnings []string
+		if stepWarning != "" {
+			warnings = append(warnings, stepWarning)
+		}
+		return resultutil.NewSuccessResult(newPrometheusResponse(result, warnings...))
+	}
 
 	// Convert to structured output
 	output := RangeQueryOutput{
 		ResultType: fmt.Sprintf("%v", result["resultType"]),
+		ExecutedQuery: &ExecutedQuery{
+			Query: input.Query,
+			Start: startTime.UTC().Format(time.RFC3339),
+			End:   endTime.UTC().Format(time.RFC3339),
+			Step:  model.Duration(stepDuration).String(),
+		},
+		Stats: newQueryStats(0, 0, queryDuration),
+	}
+	if input.TargetPoints != 0 {
+		output.Step = model.Duration(stepDuration).String()
 	}
 
+	var pageWarning string
 	resMatrix, ok := result["result"].(model.Matrix)
 	if ok {
 		slog.Info("ExecuteRangeQueryHandler executed successfully", "resultLength", resMatrix.Len())
 
-		if fullResponse {
+		output.Stats.Series = len(resMatrix)
+		for _, series := range resMatrix {
+			output.Stats.Samples += len(series.Values)
+		}
+
+		var merged map[model.Fingerprint]int
+		if len(projectLabels) > 0 {
+			resMatrix, merged = projectMatrix(resMatrix, projectLabels)
+		}
+		if sampling && len(resMatrix) > opts.MaxSeries {
+			resMatrix, output.Sampled = sampleMatrix(resMatrix, opts.MaxSeries, samplingSeed(input.Seed))
+		}
+		if paginated {
+			if token.Total > 0 && token.Total != len(resMatrix) {
+				pageWarning = fmt.Sprintf("the query returns %d series now but returned %d for the previous page, so pages may skip or repeat series",
+					len(resMatrix), token.Total)
+			}
+			resMatrix, output.Page, output.NextToken = paginateMatrix(resMatrix, token)
+		}
+
+		switch {
+		case input.DescribeShape:
+			output.Shapes = make([]SeriesShape, len(resMatrix))
+			for i, series := range resMatrix {
+				output.Shapes[i] = SeriesShape{
+					Series: convertMetricToMap(series.Metric),
+					Shape:  resultutil.DescribeShape(series.Values),
+					Stale:  seriesEnded(series.Values, endTime, stepDuration),
+					Merged: merged[series.Metric.Fingerprint()],
+				}
+			}
+		case opts.FullResponse:
 			// Return full data
 			output.Result = make([]SeriesResult, len(resMatrix))
 			for i, series := range resMatrix {
@@ -357,22 +871,34 @@ func ExecuteRangeQueryHandler(ctx context.Context, promClient prometheus.Loader,
 				for k, v := range series.Metric {
 					labels[string(k)] = string(v)
 				}
-				values := make([][]any, len(series.Values))
-				for j, sample := range series.Values {
-					values[j] = []any{float64(sample.Timestamp) / millisecondsPerSecond, sample.Value.String()}
+				var values [][]any
+				if input.ShowGaps {
+					values = valuesWithGaps(series.Values, stepDuration)
+				} else {
+					values = make([][]any, len(series.Values))
+					for j, sample := range series.Values {
+						values[j] = []any{float64(sample.Timestamp) / millisecondsPerSecond, sample.Value.String()}
+					}
 				}
 				output.Result[i] = SeriesResult{
 					Metric: labels,
 					Values: values,
+					Stale:  seriesEnded(series.Values, endTime, stepDuration),
+					Merged: merged[series.Metric.Fingerprint()],
 				}
 			}
-		} else {
+		default:
 			// Return summary statistics instead of full data
 			output.Summary = make([]SeriesResultSummary, len(resMatrix))
 			for i, series := range resMatrix {
 				output.Summary[i] = CalculateSeriesSummary(series.Metric, series.Values)
+				output.Summary[i].Stale = seriesEnded(series.Values, endTime, stepDuration)
+				output.Summary[i].Merged = merged[series.Metric.Fingerprint()]
 			}
 		}
+		if input.ConvertUnits {
+			output.convertUnits(input.Query)
+		}
 
 		slog.Debug("ExecuteRangeQueryHandler output", "output", output)
 	} else {
@@ -380,28 +906,450 @@ func ExecuteRangeQueryHandler(ctx context.Context, promClient prometheus.Loader,
 	}
 
 	if warnings, ok := result["warnings"].([]string); ok {
-		output.Warnings = warnings
+		output.Warnings = append(output.Warnings, warnings...)
+	}
+	if stepWarning != "" {
+		output.Warnings = append(output.Warnings, stepWarning)
+	}
+	if pageWarning != "" {
+		output.Warnings = append(output.Warnings, pageWarning)
+	}
+	if advisory := prometheus.AggregationAdvisory(input.Query); advisory != "" {
+		output.Warnings = append(output.Warnings, advisory)
+	}
+	if advisories, ok := result["advisories"].([]string); ok {
+		output.Warnings = append(output.Warnings, advisories...)
+	}
+	if ok && len(resMatrix) == 0 && (output.Page == nil || output.Page.TotalSeries == 0) {
+		output.Warnings = append(output.Warnings, checkEmptyResult(ctx, promClient, input.Query))
 	}
 
+	output.applyVerbosity(verbosity)
 	return resultutil.NewSuccessResult(output)
 }
 
+// valuesWithGaps converts the samples of a range query series to [timestamp, value] pairs,
+// inserting a [timestamp, nil] pair at each step boundary without a sample between the
+// first and last samples. Range query samples are aligned on the step, so any distance
+// larger than the step between consecutive samples is a gap.
+func valuesWithGaps(samples []model.SamplePair, step time.Duration) [][]any {
+	stepMs := model.Time(step.Milliseconds())
+	values := make([][]any, 0, len(samples))
+	for j, sample := range samples {
+		if j > 0 && stepMs > 0 {
+			for ts := samples[j-1].Timestamp + stepMs; ts < sample.Timestamp; ts += stepMs {
+				values = append(values, []any{float64(ts) / millisecondsPerSecond, nil})
+			}
+		}
+		values = append(values, []any{float64(sample.Timestamp) / millisecondsPerSecond, sample.Value.String()})
+	}
+	return values
+}
+
+// staleSteps is the number of steps without samples before the end of a range query
+// after which a series is considered to have ended.
+const staleSteps = 3
+
+// seriesEnded reports whether a range query series stopped before the end of the range,
+// as happens when a stale marker ends it, e.g. after its target disappeared. Samples are
+// aligned on the step, so the last one is normally less than a step before end; a series
+// with a gap in the middle is not reported as long as it resumes.
+func seriesEnded(samples []model.SamplePair, end time.Time, step time.Duration) bool {
+	if len(samples) == 0 || step <= 0 {
+		return false
+	}
+	last := samples[len(samples)-1].Timestamp.Time()
+	return end.Sub(last) > staleSteps*step
+}
+
+// thanosQueryContext returns a context under which queries carry the dedup and
+// max_source_resolution parameters of the Thanos Querier API, when requested. Such queries
+// are not shared with identical queries in flight, which may use other options.
+func thanosQueryContext(ctx context.Context, dedup *bool, maxResolution string) (context.Context, error) {
+	params := url.Values{}
+	if dedup != nil {
+		params.Set("dedup", strconv.FormatBool(*dedup))
+	}
+	switch maxResolution {
+	case "":
+	case "raw":
+		params.Set("max_source_resolution", "0s")
+	case "auto":
+		params.Set("max_source_resolution", "auto")
+	default:
+		d, err := model.ParseDuration(maxResolution)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_resolution %q: use 'raw', 'auto' or a duration like '5m' or '1h'", maxResolution)
+		}
+		params.Set("max_source_resolution", d.String())
+	}
+	if len(params) == 0 {
+		return ctx, nil
+	}
+	return auth.ContextWithQueryParams(prometheus.ContextWithoutDeduplication(ctx), params), nil
+}
+
+// dryRunContext returns a context under which query requests to the Prometheus API are
+// recorded instead of sent. Requests made to validate the query beforehand still go out.
+// Dry runs never share an identical query in flight, as the request would not be recorded.
+func dryRunContext(ctx context.Context) (context.Context, *auth.DryRunRecorder) {
+	return auth.ContextWithDryRun(prometheus.ContextWithoutDeduplication(ctx), func(r *http.Request) bool {
+		return strings.HasSuffix(r.URL.Path, "/api/v1/query") || strings.HasSuffix(r.URL.Path, "/api/v1/query_range")
+	})
+}
+
+// newDryRunOutput converts the requests recorded during a dry run. The error returned by
+// the client is only reported when it was not caused by the request being held back.
+func newDryRunOutput(rec *auth.DryRunRecorder, err error) *DryRunOutput {
+	recorded := rec.Requests()
+	output := &DryRunOutput{Requests: make([]DryRunRequest, len(recorded))}
+	for i, r := range recorded {
+		headers := make(map[string]string, len(r.Header))
+		for name, values := range r.Header {
+			headers[name] = strings.Join(values, ", ")
+		}
+		output.Requests[i] = DryRunRequest{
+			Method:  r.Method,
+			URL:     r.URL,
+			Headers: headers,
+			Body:    r.Body,
+		}
+	}
+	if err != nil && !rec.Intercepted() {
+		output.Error = err.Error()
+	}
+	return output
+}
+
 // ShowTimeseriesHandler handles the show_timeseries tool, returning full range query data for chart rendering.
+// Without target_points, the query is only executed to validate it, and the chart loads the
+// data with the input step. With target_points, the data is bounded by the number of points
+// the chart is able to show, so it is returned along with the step computed for it.
 func ShowTimeseriesHandler(ctx context.Context, promClient prometheus.Loader, input ShowTimeseriesInput) *resultutil.Result {
 	slog.Info("ShowTimeseriesHandler called")
 	slog.Debug("ShowTimeseriesHandler params", "input", input)
 
-	// Executing the query handler just to validate the query is correct.
-	result := ExecuteRangeQueryHandler(ctx, promClient, input.RangeQueryInput, true)
+	// The chart reloads the data with the input step and times, so the step cannot be
+	// shrunk here, nor the start or end anchored to the last deploy.
+	result := ExecuteRangeQueryHandler(ctx, promClient, input.RangeQueryInput, RangeQueryOptions{FullResponse: true, StepPolicy: StepPolicyReject})
 	if result.Error != nil {
 		return result
 	}
+	if input.TargetPoints == 0 {
+		return resultutil.NewSuccessResult(ShowTimeseriesOutput{})
+	}
 
-	return resultutil.NewSuccessResult(struct{}{})
+	output, err := resultutil.Unwrap[RangeQueryOutput](result)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+	return resultutil.NewSuccessResult(ShowTimeseriesOutput{Step: output.Step, Result: output.Result})
 }
 
-// ExecuteInstantQueryHandler handles the execution of Prometheus instant queries.
-func ExecuteInstantQueryHandler(ctx context.Context, promClient prometheus.Loader, input InstantQueryInput) *resultutil.Result {
+// QueryHeatmapHandler handles the query_heatmap tool, returning the observation counts of
+// a histogram per time step and bucket.
+func QueryHeatmapHandler(ctx context.Context, promClient prometheus.Loader, input HeatmapInput, stepPolicy StepPolicy) *resultutil.Result {
+	slog.Info("QueryHeatmapHandler called")
+	slog.Debug("QueryHeatmapHandler params", "input", input)
+
+	if input.Metric == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("metric parameter is required and must be a string"))
+	}
+	if input.Step == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("step parameter is required and must be a string"))
+	}
+
+	stepDuration, err := input.Step.Duration()
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid step format: %w", err))
+	}
+
+	startTime, endTime, err := parseRangeQueryTimes(ctx, input.Start, input.End, input.Duration)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	stepDuration, stepWarning, err := fitStepToRange(stepDuration, endTime.Sub(startTime), stepPolicy)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	query, err := heatmapQuery(input.Metric, input.Selector, stepDuration)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	result, err := promClient.ExecuteRangeQuery(ctx, query, startTime, endTime, stepDuration)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to execute heatmap query: %w", err))
+	}
+
+	matrix, _ := result["result"].(model.Matrix)
+	buckets, timestamps, counts, err := buildHeatmap(matrix)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	output := HeatmapOutput{
+		Query:      query,
+		Buckets:    buckets,
+		Timestamps: timestamps,
+		Counts:     counts,
+	}
+	if warnings, ok := result["warnings"].([]string); ok {
+		output.Warnings = append(output.Warnings, warnings...)
+	}
+	if stepWarning != "" {
+		output.Warnings = append(output.Warnings, stepWarning)
+	}
+
+	slog.Info("QueryHeatmapHandler executed successfully", "bucketCount", len(buckets), "timestampCount", len(timestamps))
+	return resultutil.NewSuccessResult(output)
+}
+
+// LabelCardinalityTrendHandler handles the label_cardinality_trend tool, counting the
+// distinct values of a label of a metric at each step of a time range.
+func LabelCardinalityTrendHandler(ctx context.Context, promClient prometheus.Loader, input LabelCardinalityTrendInput, stepPolicy StepPolicy) *resultutil.Result {
+	slog.Info("LabelCardinalityTrendHandler called")
+	slog.Debug("LabelCardinalityTrendHandler params", "input", input)
+
+	if input.Metric == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("metric parameter is required and must be a string"))
+	}
+	if input.Label == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("label parameter is required and must be a string"))
+	}
+	if input.Step == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("step parameter is required and must be a string"))
+	}
+
+	query, err := cardinalityTrendQuery(input.Metric, input.Label, input.Selector)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	stepDuration, err := input.Step.Duration()
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid step format: %w", err))
+	}
+
+	startTime, endTime, err := parseRangeQueryTimes(ctx, input.Start, input.End, input.Duration)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	stepDuration, stepWarning, err := fitStepToRange(stepDuration, endTime.Sub(startTime), stepPolicy)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	result, err := promClient.ExecuteRangeQuery(ctx, query, startTime, endTime, stepDuration)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to execute cardinality trend query: %w", err))
+	}
+
+	matrix, _ := result["result"].(model.Matrix)
+	points, peak := buildCardinalityTrend(matrix)
+	output := LabelCardinalityTrendOutput{
+		Query:  query,
+		Label:  input.Label,
+		Points: points,
+		Peak:   peak,
+	}
+	if warnings, ok := result["warnings"].([]string); ok {
+		output.Warnings = append(output.Warnings, warnings...)
+	}
+	if stepWarning != "" {
+		output.Warnings = append(output.Warnings, stepWarning)
+	}
+
+	slog.Info("LabelCardinalityTrendHandler executed successfully", "pointCount", len(points))
+	return resultutil.NewSuccessResult(output)
+}
+
+// ClusterStatusHandler handles the cluster_status tool, evaluating the status checks and
+// summarizing them as the worst of their statuses.
+func ClusterStatusHandler(ctx context.Context, promClient prometheus.Loader, input ClusterStatusInput, checks []StatusCheck) *resultutil.Result {
+	slog.Info("ClusterStatusHandler called")
+	slog.Debug("ClusterStatusHandler params", "input", input)
+
+	if len(input.Checks) > 0 {
+		selected := make([]StatusCheck, 0, len(input.Checks))
+		for _, name := range input.Checks {
+			i := slices.IndexFunc(checks, func(c StatusCheck) bool { return c.Name == name })
+			if i < 0 {
+				names := make([]string, len(checks))
+				for j, c := range checks {
+					names[j] = c.Name
+				}
+				return resultutil.NewErrorResult(fmt.Errorf("unknown check %q (available checks: %s)", name, strings.Join(names, ", ")))
+			}
+			selected = append(selected, checks[i])
+		}
+		checks = selected
+	}
+
+	output := runStatusChecks(ctx, promClient, checks)
+
+	slog.Info("ClusterStatusHandler executed successfully", "status", output.Status)
+	return resultutil.NewSuccessResult(output)
+}
+
+// SLOComplianceHandler handles the slo_compliance tool, comparing the ratio of good to
+// total events over a time range to an SLO target.
+func SLOComplianceHandler(ctx context.Context, promClient prometheus.Loader, input SLOComplianceInput, stepPolicy StepPolicy) *resultutil.Result {
+	slog.Info("SLOComplianceHandler called")
+	slog.Debug("SLOComplianceHandler params", "input", input)
+
+	if input.GoodQuery == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("good_query parameter is required and must be a string"))
+	}
+	if input.TotalQuery == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("total_query parameter is required and must be a string"))
+	}
+	if input.Target <= 0 || input.Target >= 100 {
+		return resultutil.NewErrorResult(fmt.Errorf("target must be a percentage between 0 and 100 (exclusive), e.g. 99.9"))
+	}
+	if input.Step == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("step parameter is required and must be a string"))
+	}
+
+	stepDuration, err := input.Step.Duration()
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid step format: %w", err))
+	}
+
+	startTime, endTime, err := parseRangeQueryTimes(ctx, input.Start, input.End, input.Duration)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	stepDuration, stepWarning, err := fitStepToRange(stepDuration, endTime.Sub(startTime), stepPolicy)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	// Both queries are evaluated at the same steps, so that their points line up.
+	var good, total map[string]any
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		good, err = promClient.ExecuteRangeQuery(gctx, input.GoodQuery, startTime, endTime, stepDuration)
+		if err != nil {
+			return fmt.Errorf("failed to execute good_query: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		total, err = promClient.ExecuteRangeQuery(gctx, input.TotalQuery, startTime, endTime, stepDuration)
+		if err != nil {
+			return fmt.Errorf("failed to execute total_query: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	goodMatrix, _ := good["result"].(model.Matrix)
+	totalMatrix, _ := total["result"].(model.Matrix)
+	output, err := computeSLOCompliance(goodMatrix, totalMatrix, input.Target)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+	if stepWarning != "" {
+		output.Warnings = append(output.Warnings, stepWarning)
+	}
+
+	slog.Info("SLOComplianceHandler executed successfully", "sli", output.SLI, "compliant", output.Compliant)
+	return resultutil.NewSuccessResult(output)
+}
+
+// EvaluateConditionHandler handles the evaluate_condition tool, measuring how long a
+// comparison query was true over a time range.
+func EvaluateConditionHandler(ctx context.Context, promClient prometheus.Loader, input EvaluateConditionInput, stepPolicy StepPolicy) *resultutil.Result {
+	slog.Info("EvaluateConditionHandler called")
+	slog.Debug("EvaluateConditionHandler params", "input", input)
+
+	if input.Query == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("query parameter is required and must be a string"))
+	}
+	if input.Step == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("step parameter is required and must be a string"))
+	}
+
+	returnBool, err := conditionReturnsBool(input.Query)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	stepDuration, err := input.Step.Duration()
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid step format: %w", err))
+	}
+
+	var forDuration time.Duration
+	if input.For != "" {
+		d, err := model.ParseDuration(input.For)
+		if err != nil || d <= 0 {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid for duration %q: must be a positive duration such as 5m", input.For))
+		}
+		forDuration = time.Duration(d)
+		// Without a time range, check whether the alert would be firing now.
+		if input.Start == "" && input.End == "" && input.Duration == "" {
+			input.Duration = input.For
+		}
+	}
+
+	startTime, endTime, err := parseRangeQueryTimes(ctx, input.Start, input.End, input.Duration)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	stepDuration, stepWarning, err := fitStepToRange(stepDuration, endTime.Sub(startTime), stepPolicy)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	// Prometheus evaluates at millisecond precision, rounding the start and truncating the
+	// step. Samples are matched to steps by their exact timestamps, so the times are
+	// aligned to the millisecond before the query rather than left to the backend.
+	startTime, endTime = startTime.Truncate(time.Millisecond), endTime.Truncate(time.Millisecond)
+	stepDuration = stepDuration.Truncate(time.Millisecond)
+	if stepDuration <= 0 {
+		return resultutil.NewErrorResult(fmt.Errorf("step must be at least 1ms"))
+	}
+
+	result, err := promClient.ExecuteRangeQuery(ctx, input.Query, startTime, endTime, stepDuration)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to execute condition query: %w", err))
+	}
+
+	matrix, _ := result["result"].(model.Matrix)
+	output := evaluateCondition(matrix, startTime, endTime, stepDuration, returnBool)
+	if forDuration > 0 {
+		output.ForWindow = evaluateForWindow(matrix, startTime, endTime, stepDuration, forDuration, returnBool)
+		if stepDuration > forDuration {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("step %s is larger than the for duration %s, so the condition is not checked between steps; use a step no larger than the rule evaluation interval",
+				model.Duration(stepDuration), model.Duration(forDuration)))
+		}
+	}
+	if warnings, ok := result["warnings"].([]string); ok {
+		output.Warnings = append(output.Warnings, warnings...)
+	}
+	if stepWarning != "" {
+		output.Warnings = append(output.Warnings, stepWarning)
+	}
+
+	slog.Info("EvaluateConditionHandler executed successfully", "trueFraction", output.TrueFraction)
+	return resultutil.NewSuccessResult(output)
+}
+
+// ExecuteInstantQueryHandler handles the execution of Prometheus instant queries.
+// maxSeries is the max-result-series limit results are sampled down to when sampling
+// is requested (0 = no limit).
+func ExecuteInstantQueryHandler(ctx context.Context, promClient prometheus.Loader, input InstantQueryInput, maxSeries int) *resultutil.Result {
 	slog.Info("ExecuteInstantQueryHandler called")
 	slog.Debug("ExecuteInstantQueryHandler params", "input", input)
 
@@ -410,166 +1358,1492 @@ func ExecuteInstantQueryHandler(ctx context.Context, promClient prometheus.Loade
 		return resultutil.NewErrorResult(fmt.Errorf("query parameter is required and must be a string"))
 	}
 
-	var queryTime time.Time
-	var err error
-	if input.Time == "" {
-		queryTime = time.Now()
-	} else {
-		queryTime, err = prometheus.ParseTimestamp(input.Time)
+	groupAgg := input.GroupAgg
+	if groupAgg == "" {
+		groupAgg = GroupAggSum
+	}
+	if !slices.Contains(groupAggregations, groupAgg) {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid group_agg %q (valid options: %s)", groupAgg, strings.Join(groupAggregations, ", ")))
+	}
+	if input.GroupAgg != "" && input.GroupBy == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("group_agg requires group_by to be set"))
+	}
+	if input.LabelsOnly && input.GroupBy != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("labels_only cannot be combined with group_by"))
+	}
+	if input.Sampling && input.GroupBy != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("sampling cannot be combined with group_by"))
+	}
+	if input.ProjectLabels != "" && input.GroupBy != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("project_labels cannot be combined with group_by"))
+	}
+	if input.Rank && input.GroupBy != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("rank cannot be combined with group_by"))
+	}
+	projectLabels, err := parseProjectLabels(input.ProjectLabels)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+	verbosity, err := parseVerbosity(input.Verbosity)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	var queryTime time.Time
+	if input.Time == "" {
+		queryTime = prometheus.Now(ctx)
+	} else {
+		queryTime, err = prometheus.ParseTimestamp(ctx, input.Time)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid time format: %w", err))
+		}
+	}
+
+	ctx, err = thanosQueryContext(ctx, input.Dedup, input.MaxResolution)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	if input.DryRun {
+		dryRunCtx, rec := dryRunContext(ctx)
+		_, err := promClient.ExecuteInstantQuery(dryRunCtx, input.Query, queryTime)
+		return resultutil.NewSuccessResult(InstantQueryOutput{DryRun: newDryRunOutput(rec, err)})
+	}
+
+	sampling := input.Sampling && maxSeries > 0
+	if sampling {
+		ctx = prometheus.ContextWithoutSeriesLimit(ctx)
+	}
+
+	// Execute the instant query
+	queryStart := time.Now()
+	result, err := promClient.ExecuteInstantQuery(ctx, input.Query, queryTime)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to execute instant query: %w", err))
+	}
+	queryDuration := time.Since(queryStart)
+
+	// Convert to structured output
+	output := InstantQueryOutput{
+		ResultType: fmt.Sprintf("%v", result["resultType"]),
+		ExecutedQuery: &ExecutedQuery{
+			Query: input.Query,
+			Time:  queryTime.UTC().Format(time.RFC3339),
+		},
+	}
+
+	resVector, ok := result["result"].(model.Vector)
+	switch value := result["result"].(type) {
+	case model.Vector:
+		output.Stats = newQueryStats(len(value), len(value), queryDuration)
+	case model.Matrix:
+		output.Stats = newQueryStats(len(value), 0, queryDuration)
+		for _, series := range value {
+			output.Stats.Samples += len(series.Values)
+		}
+	case *model.Scalar, *model.String:
+		output.Stats = newQueryStats(0, 1, queryDuration)
+	default:
+		output.Stats = newQueryStats(0, 0, queryDuration)
+	}
+	if ok && len(resVector) == 0 && input.Nearest {
+		resVector, err = nearestVector(ctx, promClient, input.Query, queryTime)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("failed to look up the nearest samples: %w", err))
+		}
+		output.Nearest = len(resVector) > 0
+	}
+	var merged map[model.Fingerprint]int
+	if ok && len(projectLabels) > 0 {
+		resVector, merged = projectVector(resVector, projectLabels)
+	}
+	// Series are ranked before sampling, so that their ranks are those in the full result.
+	var ranks map[*model.Sample]int
+	if ok && input.Rank {
+		resVector = rankVector(resVector)
+		output.RankedCount = len(resVector)
+		ranks = make(map[*model.Sample]int, len(resVector))
+		for i, sample := range resVector {
+			ranks[sample] = i + 1
+		}
+	}
+	if ok && sampling && len(resVector) > maxSeries {
+		resVector, output.Sampled = sampleVector(resVector, maxSeries, samplingSeed(input.Seed))
+	}
+	if ok {
+		slog.Info("ExecuteInstantQueryHandler executed successfully", "resultLength", len(resVector))
+		slog.Debug("ExecuteInstantQueryHandler results", "results", resVector)
+
+		if input.GroupBy != "" {
+			output.Groups = groupVector(resVector, model.LabelName(input.GroupBy), groupAgg)
+		} else {
+			output.Result = make([]InstantResult, len(resVector))
+			for i, sample := range resVector {
+				labels := make(map[string]string)
+				for k, v := range sample.Metric {
+					labels[string(k)] = string(v)
+				}
+				output.Result[i] = InstantResult{Metric: labels, Merged: merged[sample.Metric.Fingerprint()], Rank: ranks[sample]}
+				if !input.LabelsOnly {
+					output.Result[i].Value = []any{float64(sample.Timestamp) / millisecondsPerSecond, sample.Value.String()}
+				}
+			}
+			if input.ConvertUnits && !input.LabelsOnly {
+				output.convertUnits(input.Query)
+			}
+			if !input.LabelsOnly {
+				output.ScalarValue = singleValue(resVector)
+			}
+		}
+	} else if input.GroupBy != "" {
+		return resultutil.NewErrorResult(fmt.Errorf("group_by requires the query to return a vector, got %v", result["resultType"]))
+	} else if scalar, isScalar := result["result"].(*model.Scalar); isScalar {
+		slog.Info("ExecuteInstantQueryHandler executed successfully (scalar)")
+		output.ScalarValue = scalar.Value.String()
+	} else {
+		slog.Info("ExecuteInstantQueryHandler executed successfully (unknown format)", "result", result)
+	}
+
+	if warnings, ok := result["warnings"].([]string); ok {
+		output.Warnings = append(output.Warnings, warnings...)
+	}
+	if advisory := prometheus.AggregationAdvisory(input.Query); advisory != "" {
+		output.Warnings = append(output.Warnings, advisory)
+	}
+	if advisories, ok := result["advisories"].([]string); ok {
+		output.Warnings = append(output.Warnings, advisories...)
+	}
+	if ok && len(resVector) == 0 {
+		output.Warnings = append(output.Warnings, checkEmptyResult(ctx, promClient, input.Query))
+	}
+
+	output.applyVerbosity(verbosity)
+	return resultutil.NewSuccessResult(output)
+}
+
+// singleValue returns the value of a vector holding a single sample without labels, as
+// returned by aggregations such as count(up{job="api"}), and "" for other vectors.
+func singleValue(vector model.Vector) string {
+	if len(vector) != 1 {
+		return ""
+	}
+	for name := range vector[0].Metric {
+		if name != model.MetricNameLabel {
+			return ""
+		}
+	}
+	return vector[0].Value.String()
+}
+
+const (
+	// nearestLookback is how far before the requested time nearest mode looks for samples.
+	nearestLookback = time.Hour
+	// nearestStep is the resolution of the range query used by nearest mode. It is below
+	// the Prometheus lookback delta, so no sample in the lookback window is skipped.
+	nearestStep = time.Minute
+)
+
+// nearestVector returns the latest value of each series the query returned within
+// nearestLookback before ts. Each value is timestamped with the time of the sample it
+// was computed from, as returned by timestamp(), rather than the step it was found at,
+// which may be up to the lookback delta later.
+func nearestVector(ctx context.Context, promClient prometheus.Loader, query string, ts time.Time) (model.Vector, error) {
+	matrix, err := nearestMatrix(ctx, promClient, query, ts)
+	if err != nil {
+		return nil, err
+	}
+	timestamps, err := nearestMatrix(ctx, promClient, "timestamp("+query+")", ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the sample times: %w", err)
+	}
+
+	// timestamp() drops the metric name, so series are matched on their other labels.
+	sampleTimes := make(map[model.Fingerprint][]model.SamplePair, len(timestamps))
+	for _, series := range timestamps {
+		sampleTimes[withoutMetricName(series.Metric).Fingerprint()] = series.Values
+	}
+
+	vector := make(model.Vector, 0, len(matrix))
+	for _, series := range matrix {
+		if len(series.Values) == 0 {
+			continue
+		}
+		last := series.Values[len(series.Values)-1]
+		sample := &model.Sample{Metric: series.Metric, Value: last.Value, Timestamp: last.Timestamp}
+		for _, t := range sampleTimes[withoutMetricName(series.Metric).Fingerprint()] {
+			if t.Timestamp == last.Timestamp {
+				sample.Timestamp = model.Time(math.Round(float64(t.Value) * millisecondsPerSecond))
+			}
+		}
+		vector = append(vector, sample)
+	}
+	return vector, nil
+}
+
+// nearestMatrix runs query over the nearestLookback before ts.
+func nearestMatrix(ctx context.Context, promClient prometheus.Loader, query string, ts time.Time) (model.Matrix, error) {
+	result, err := promClient.ExecuteRangeQuery(ctx, query, ts.Add(-nearestLookback), ts, nearestStep)
+	if err != nil {
+		return nil, err
+	}
+	matrix, ok := result["result"].(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %v", result["resultType"])
+	}
+	return matrix, nil
+}
+
+// withoutMetricName returns a copy of metric without its __name__ label.
+func withoutMetricName(metric model.Metric) model.Metric {
+	metric = metric.Clone()
+	delete(metric, model.MetricNameLabel)
+	return metric
+}
+
+// Aggregations applied to the series sharing a group_by label value.
+const (
+	GroupAggSum   = "sum"
+	GroupAggMax   = "max"
+	GroupAggMin   = "min"
+	GroupAggAvg   = "avg"
+	GroupAggCount = "count"
+)
+
+var groupAggregations = []string{GroupAggSum, GroupAggMax, GroupAggMin, GroupAggAvg, GroupAggCount}
+
+// groupVector aggregates the samples of a vector by the value of the given label, like
+// "<agg> by (<label>)" would in PromQL. Series without the label form the "" group.
+func groupVector(vector model.Vector, label model.LabelName, agg string) map[string]InstantGroup {
+	type accumulator struct {
+		value float64
+		count int
+	}
+
+	acc := make(map[string]*accumulator)
+	for _, sample := range vector {
+		key := string(sample.Metric[label])
+		v := float64(sample.Value)
+		a, ok := acc[key]
+		if !ok {
+			acc[key] = &accumulator{value: v, count: 1}
+			continue
+		}
+		a.count++
+		switch agg {
+		case GroupAggMax:
+			a.value = max(a.value, v)
+		case GroupAggMin:
+			a.value = min(a.value, v)
+		default:
+			a.value += v
+		}
+	}
+
+	groups := make(map[string]InstantGroup, len(acc))
+	for key, a := range acc {
+		value := a.value
+		switch agg {
+		case GroupAggAvg:
+			value /= float64(a.count)
+		case GroupAggCount:
+			value = float64(a.count)
+		}
+		groups[key] = InstantGroup{
+			Value:       model.SampleValue(value).String(),
+			SeriesCount: a.count,
+		}
+	}
+	return groups
+}
+
+// GetLabelNamesHandler handles the retrieval of label names.
+func GetLabelNamesHandler(ctx context.Context, promClient prometheus.Loader, input LabelNamesInput) *resultutil.Result {
+	slog.Info("GetLabelNamesHandler called")
+	slog.Debug("GetLabelNamesHandler params", "input", input)
+
+	startTime, endTime, err := parseDefaultTimeRange(ctx, input.Start, input.End)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	// Get label names
+	labels, err := promClient.GetLabelNames(ctx, input.Metric, startTime, endTime)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get label names: %w", err))
+	}
+
+	output := LabelNamesOutput{Labels: labels}
+	// One TSDB stats request gives the value counts of every label, instead of a label
+	// values request per label. They are left out when the backend does not serve them.
+	counts, err := promClient.GetLabelValueCounts(ctx)
+	if err != nil {
+		slog.Debug("Label value counts unavailable", "error", err)
+	} else {
+		for _, label := range labels {
+			if count, ok := counts[label]; ok {
+				if output.ValueCounts == nil {
+					output.ValueCounts = make(map[string]uint64, len(labels))
+				}
+				output.ValueCounts[label] = count
+			}
+		}
+	}
+
+	slog.Info("GetLabelNamesHandler executed successfully", "labelCount", len(labels))
+	slog.Debug("GetLabelNamesHandler results", "results", labels)
+
+	return resultutil.NewSuccessResult(output)
+}
+
+// GetLabelValuesHandler handles the retrieval of label values.
+// limits sets the number of values returned.
+func GetLabelValuesHandler(ctx context.Context, promClient prometheus.Loader, input LabelValuesInput, limits ToolLimit) *resultutil.Result {
+	slog.Info("GetLabelValuesHandler called")
+	slog.Debug("GetLabelValuesHandler params", "input", input)
+
+	// Validate required parameters
+	if input.Label == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("label parameter is required and must be a string"))
+	}
+
+	startTime, endTime, err := parseDefaultTimeRange(ctx, input.Start, input.End)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	limit, err := limits.Resolve(input.Limit)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	if input.ByFrequency {
+		return labelValuesByFrequency(ctx, promClient, input, startTime, endTime, limit)
+	}
+
+	// Ask the backend for one value more than the limit to detect truncation.
+	var fetchLimit uint64
+	if limit > 0 {
+		fetchLimit = uint64(limit) + 1
+	}
+
+	// Get label values
+	values, err := promClient.GetLabelValues(ctx, input.Label, input.Metric, startTime, endTime, fetchLimit)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get label values: %w", err))
+	}
+
+	output := LabelValuesOutput{Values: values, TotalCount: len(values)}
+	if limit > 0 && len(values) > limit {
+		output.Values = values[:limit]
+		output.Truncated = true
+		// When the backend applied the limit, the total is unknown; otherwise the
+		// values were truncated here and the total is exact.
+		if uint64(len(values)) <= fetchLimit {
+			output.TotalCount = 0
+		}
+	}
+
+	slog.Info("GetLabelValuesHandler executed successfully", "valueCount", len(output.Values), "truncated", output.Truncated)
+	slog.Debug("GetLabelValuesHandler results", "results", output.Values)
+
+	return resultutil.NewSuccessResult(output)
+}
+
+// maxFrequencyLabelValues caps the number of values of a label whose frequencies are
+// counted, as the count query returns a series per value.
+const maxFrequencyLabelValues = 1000
+
+// labelValuesByFrequency returns the values of a label sorted by the number of series
+// having them within the time range, most common first. The series are counted by a
+// single query evaluated at end, after checking that the label has few enough values.
+func labelValuesByFrequency(ctx context.Context, promClient prometheus.Loader, input LabelValuesInput, start, end time.Time, limit int) *resultutil.Result {
+	if !labelNameRe.MatchString(input.Label) {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid label name %q", input.Label))
+	}
+	// Without a metric, the count query would scan every series of the backend.
+	if input.Metric == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("metric parameter is required with by_frequency"))
+	}
+
+	values, err := promClient.GetLabelValues(ctx, input.Label, input.Metric, start, end, maxFrequencyLabelValues+1)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get label values: %w", err))
+	}
+	if len(values) > maxFrequencyLabelValues {
+		return resultutil.NewErrorResult(fmt.Errorf("label %q has more than %d values, too many to count by frequency; scope it to a shorter time range", input.Label, maxFrequencyLabelValues))
+	}
+
+	// The label API leaves open ends of the range unbounded; the count query needs both.
+	if end.IsZero() {
+		end = prometheus.Now(ctx)
+	}
+	if start.IsZero() {
+		start = end.Add(-prometheus.ListMetricsTimeRange)
+	}
+	matchers := fmt.Sprintf("__name__=%q,%s!=\"\"", input.Metric, input.Label)
+	window := max(end.Sub(start).Round(time.Second), time.Second)
+	query := fmt.Sprintf("count by (%s) (last_over_time({%s}[%s]))", input.Label, matchers, model.Duration(window))
+
+	result, err := promClient.ExecuteInstantQuery(ctx, query, end)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to count series per label value: %w", err))
+	}
+	vector, _ := result["result"].(model.Vector)
+
+	frequencies := make([]LabelValueFrequency, 0, len(vector))
+	for _, sample := range vector {
+		frequencies = append(frequencies, LabelValueFrequency{
+			Value:       string(sample.Metric[model.LabelName(input.Label)]),
+			SeriesCount: int(sample.Value),
+		})
+	}
+	slices.SortFunc(frequencies, func(a, b LabelValueFrequency) int {
+		return cmp.Or(cmp.Compare(b.SeriesCount, a.SeriesCount), cmp.Compare(a.Value, b.Value))
+	})
+
+	output := LabelValuesOutput{TotalCount: len(frequencies)}
+	if limit > 0 && len(frequencies) > limit {
+		frequencies = frequencies[:limit]
+		output.Truncated = true
+	}
+	output.Frequencies = frequencies
+	output.Values = make([]string, len(frequencies))
+	for i, f := range frequencies {
+		output.Values[i] = f.Value
+	}
+
+	slog.Info("GetLabelValuesHandler executed successfully", "valueCount", len(output.Values), "truncated", output.Truncated, "byFrequency", true)
+	return resultutil.NewSuccessResult(output)
+}
+
+const (
+	// defaultLabelsOverviewValues is the number of values get_labels_overview returns per
+	// label when no limit is requested and the limits configuration sets no other default.
+	defaultLabelsOverviewValues = 50
+	// maxLabelsOverviewLabels caps the number of labels of a get_labels_overview call,
+	// each of which costs a backend request.
+	maxLabelsOverviewLabels = 10
+)
+
+// GetLabelsOverviewHandler returns the values of several labels of a metric at once,
+// fetching them concurrently. limits sets the number of values returned per label.
+func GetLabelsOverviewHandler(ctx context.Context, promClient prometheus.Loader, input LabelsOverviewInput, limits ToolLimit) *resultutil.Result {
+	slog.Info("GetLabelsOverviewHandler called")
+	slog.Debug("GetLabelsOverviewHandler params", "input", input)
+
+	if input.Metric == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("metric parameter is required and must be a string"))
+	}
+	if len(input.Labels) == 0 {
+		return resultutil.NewErrorResult(fmt.Errorf("labels parameter is required and must list at least one label"))
+	}
+	var labels []string
+	for _, label := range input.Labels {
+		label = strings.TrimSpace(label)
+		if !labelNameRe.MatchString(label) {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid label name %q", label))
+		}
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) > maxLabelsOverviewLabels {
+		return resultutil.NewErrorResult(fmt.Errorf("labels has %d labels, at most %d are allowed", len(labels), maxLabelsOverviewLabels))
+	}
+
+	startTime, endTime, err := parseDefaultTimeRange(ctx, input.Start, input.End)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	limit, err := limits.Resolve(input.Limit)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	// Ask the backend for one value more than the limit to detect truncation.
+	var fetchLimit uint64
+	if limit > 0 {
+		fetchLimit = uint64(limit) + 1
+	}
+
+	output := LabelsOverviewOutput{Labels: make([]LabelOverview, len(labels))}
+	g, gctx := errgroup.WithContext(ctx)
+	for i, label := range labels {
+		g.Go(func() error {
+			values, err := promClient.GetLabelValues(gctx, label, input.Metric, startTime, endTime, fetchLimit)
+			if err != nil {
+				return fmt.Errorf("failed to get values of label %q: %w", label, err)
+			}
+			overview := LabelOverview{Label: label, Values: values}
+			if limit > 0 && len(values) > limit {
+				overview.Values = values[:limit]
+				overview.Truncated = true
+			}
+			output.Labels[i] = overview
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	slog.Info("GetLabelsOverviewHandler executed successfully", "labelCount", len(output.Labels))
+	slog.Debug("GetLabelsOverviewHandler results", "results", output.Labels)
+
+	return resultutil.NewSuccessResult(output)
+}
+
+// GetSeriesHandler handles the retrieval of time series.
+// limits sets the number of series returned.
+func GetSeriesHandler(ctx context.Context, promClient prometheus.Loader, input SeriesInput, limits ToolLimit) *resultutil.Result {
+	slog.Info("GetSeriesHandler called")
+	slog.Debug("GetSeriesHandler params", "input", input)
+
+	// Validate required parameters
+	if input.Matches == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("matches parameter is required and must be a string"))
+	}
+
+	// Parse matches - could be comma-separated
+	matches := []string{input.Matches}
+	// If it contains comma outside of braces, split it
+	// For simplicity, treat the entire string as one match for now
+	// Users can make multiple calls if needed
+
+	startTime, endTime, err := parseDefaultTimeRange(ctx, input.Start, input.End)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+	limit, err := limits.Resolve(input.Limit)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	// Get series
+	series, err := promClient.GetSeries(ctx, matches, startTime, endTime)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get series: %w", err))
+	}
+
+	output := SeriesOutput{
+		Series:      series,
+		Cardinality: len(series),
+	}
+	if limit > 0 && len(series) > limit {
+		output.Series = series[:limit]
+		output.Truncated = true
+	}
+	if input.WithLastSeen && len(output.Series) > 0 {
+		output.LastSeen, err = seriesLastSeen(ctx, promClient, input.Matches, output.Series, startTime, endTime)
+		if err != nil {
+			return resultutil.NewErrorResult(err)
+		}
+	}
+
+	slog.Info("GetSeriesHandler executed successfully", "cardinality", len(series), "truncated", output.Truncated)
+	slog.Debug("GetSeriesHandler results", "results", output.Series)
+	return resultutil.NewSuccessResult(output)
+}
+
+// lastSeenProbePoints is the number of steps of the range query probing when series were
+// last sampled, which sets the precision of the reported times.
+const lastSeenProbePoints = 120
+
+// seriesLastSeen describes for each series how long before end it was last sampled, using
+// a range query of the selector over the time range. Series that ended before end, as
+// told by seriesEnded, are reported as stale.
+func seriesLastSeen(ctx context.Context, promClient prometheus.Loader, selector string, series []map[string]string, start, end time.Time) ([]string, error) {
+	if end.IsZero() {
+		end = prometheus.Now(ctx)
+	}
+	if start.IsZero() {
+		start = end.Add(-prometheus.ListMetricsTimeRange)
+	}
+	step := max(time.Second, (end.Sub(start) / lastSeenProbePoints).Round(time.Second))
+
+	result, err := promClient.ExecuteRangeQuery(ctx, selector, start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query when series were last seen: %w", err)
+	}
+	matrix, _ := result["result"].(model.Matrix)
+	samples := make(map[model.Fingerprint][]model.SamplePair, len(matrix))
+	for _, s := range matrix {
+		samples[s.Metric.Fingerprint()] = s.Values
+	}
+
+	lastSeen := make([]string, len(series))
+	for i, labels := range series {
+		metric := make(model.Metric, len(labels))
+		for k, v := range labels {
+			metric[model.LabelName(k)] = model.LabelValue(v)
+		}
+		values := samples[metric.Fingerprint()]
+		if len(values) == 0 {
+			lastSeen[i] = "no samples in range"
+			continue
+		}
+		age := model.Duration(end.Sub(values[len(values)-1].Timestamp.Time()).Round(step))
+		if seriesEnded(values, end, step) {
+			lastSeen[i] = "stale " + age.String()
+		} else {
+			lastSeen[i] = age.String() + " ago"
+		}
+	}
+	return lastSeen, nil
+}
+
+// maxVaryingLabelValues caps the sample of values returned for each varying label.
+const maxVaryingLabelValues = 10
+
+// CheckSeriesUniquenessHandler checks whether a selector matches exactly one series
+// and reports the labels that differ among the matches otherwise.
+func CheckSeriesUniquenessHandler(ctx context.Context, promClient prometheus.Loader, input SeriesUniquenessInput) *resultutil.Result {
+	slog.Info("CheckSeriesUniquenessHandler called")
+	slog.Debug("CheckSeriesUniquenessHandler params", "input", input)
+
+	if input.Selector == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("selector parameter is required and must be a string"))
+	}
+
+	startTime, endTime, err := parseDefaultTimeRange(ctx, input.Start, input.End)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	series, err := promClient.GetSeries(ctx, []string{input.Selector}, startTime, endTime)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get series: %w", err))
+	}
+
+	output := SeriesUniquenessOutput{
+		Unique:      len(series) == 1,
+		SeriesCount: len(series),
+	}
+	output.CommonLabels, output.VaryingLabels = diffSeriesLabels(series)
+
+	slog.Info("CheckSeriesUniquenessHandler executed successfully", "seriesCount", len(series), "varyingLabels", len(output.VaryingLabels))
+	return resultutil.NewSuccessResult(output)
+}
+
+// InspectMetricHandler reports the jobs exporting a metric and whether they export it
+// with different label names.
+func InspectMetricHandler(ctx context.Context, promClient prometheus.Loader, input InspectMetricInput) *resultutil.Result {
+	slog.Info("InspectMetricHandler called")
+	slog.Debug("InspectMetricHandler params", "input", input)
+
+	if input.Metric == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("metric parameter is required and must be a string"))
+	}
+	if !metricNameRe.MatchString(input.Metric) {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid metric name %q", input.Metric))
+	}
+
+	startTime, endTime, err := parseDefaultTimeRange(ctx, input.Start, input.End)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	series, err := promClient.GetSeries(ctx, []string{input.Metric}, startTime, endTime)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get series: %w", err))
+	}
+
+	output := inspectMetricJobs(input.Metric, series)
+	slog.Info("InspectMetricHandler executed successfully", "seriesCount", output.SeriesCount, "jobs", len(output.Jobs), "ambiguous", output.Ambiguous)
+	return resultutil.NewSuccessResult(output)
+}
+
+// defaultMetricMetadataLimit is the number of metrics get_metric_metadata returns the
+// metadata of when no metric or limit is requested and the limits configuration sets no
+// other default.
+const defaultMetricMetadataLimit = 100
+
+// GetMetricMetadataHandler reports the type, help and unit of a metric, or of all metrics
+// with metadata up to the limit when no metric is given.
+func GetMetricMetadataHandler(ctx context.Context, promClient prometheus.Loader, input MetricMetadataInput, limits ToolLimit) *resultutil.Result {
+	slog.Info("GetMetricMetadataHandler called")
+	slog.Debug("GetMetricMetadataHandler params", "input", input)
+
+	var metadata map[string][]v1.Metadata
+	output := MetricMetadataOutput{Metadata: []MetricMetadata{}}
+	if input.Metric != "" {
+		if !metricNameRe.MatchString(input.Metric) {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid metric name %q", input.Metric))
+		}
+		entries, err := promClient.GetMetricMetadata(ctx, input.Metric)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("failed to get metric metadata: %w", err))
+		}
+		metadata = map[string][]v1.Metadata{input.Metric: entries}
+	} else {
+		limit, err := limits.Resolve(input.Limit)
+		if err != nil {
+			return resultutil.NewErrorResult(err)
+		}
+		// One more metric than the limit tells whether the metadata was truncated.
+		fetch := 0
+		if limit > 0 {
+			fetch = limit + 1
+		}
+		metadata, err = promClient.ListMetricMetadata(ctx, fetch)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("failed to get metric metadata: %w", err))
+		}
+		if limit > 0 && len(metadata) > limit {
+			for _, name := range slices.Sorted(maps.Keys(metadata))[limit:] {
+				delete(metadata, name)
+			}
+			output.Truncated = true
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(metadata)) {
+		for _, entry := range metadata[name] {
+			output.Metadata = append(output.Metadata, MetricMetadata{
+				Metric: name,
+				Type:   string(entry.Type),
+				Help:   entry.Help,
+				Unit:   entry.Unit,
+			})
+		}
+	}
+
+	slog.Info("GetMetricMetadataHandler executed successfully", "entryCount", len(output.Metadata), "truncated", output.Truncated)
+	return resultutil.NewSuccessResult(output)
+}
+
+// GetScrapeIntervalHandler estimates the scrape interval of a metric from the raw samples
+// of its series in a short window.
+func GetScrapeIntervalHandler(ctx context.Context, promClient prometheus.Loader, input ScrapeIntervalInput) *resultutil.Result {
+	slog.Info("GetScrapeIntervalHandler called")
+	slog.Debug("GetScrapeIntervalHandler params", "input", input)
+
+	if input.Metric == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("metric parameter is required and must be a string"))
+	}
+	matchers, err := parser.NewParser(parser.Options{}).ParseMetricSelector(input.Metric)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid metric %q: must be a metric name or series selector: %w", input.Metric, err))
+	}
+	// The raw samples of every matched series are fetched, so the series must be narrowed
+	// down beyond the metric name.
+	if !slices.ContainsFunc(matchers, func(m *labels.Matcher) bool { return m.Name != model.MetricNameLabel }) {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid metric %q: must have a label matcher, e.g. %s{job=\"api\"}, as the samples of every matched series are fetched", input.Metric, input.Metric))
+	}
+	window, err := model.ParseDuration(cmp.Or(input.Window, defaultScrapeIntervalWindow))
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid window format: %w", err))
+	}
+
+	query := fmt.Sprintf("%s[%s]", input.Metric, window)
+	result, err := promClient.ExecuteInstantQuery(ctx, query, prometheus.Now(ctx))
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to fetch samples: %w", err))
+	}
+	matrix, _ := result["result"].(model.Matrix)
+	output, ok := estimateScrapeInterval(input.Metric, matrix)
+	if !ok {
+		return resultutil.NewErrorResult(fmt.Errorf("no series of %s has two samples in the last %s; check the metric name or widen the window", input.Metric, window))
+	}
+
+	slog.Info("GetScrapeIntervalHandler executed successfully", "interval", output.Interval, "seriesCount", output.SeriesCount)
+	return resultutil.NewSuccessResult(output)
+}
+
+// GetExternalLabelsHandler handles retrieving the external labels of the metrics backend.
+func GetExternalLabelsHandler(ctx context.Context, promClient prometheus.Loader, _ ExternalLabelsInput) *resultutil.Result {
+	slog.Info("GetExternalLabelsHandler called")
+
+	labels, err := promClient.GetExternalLabels(ctx)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get external labels: %w", err))
+	}
+
+	output := ExternalLabelsOutput{Labels: make([]ExternalLabel, 0, len(labels.Labels)), Source: labels.Source}
+	for _, name := range slices.Sorted(maps.Keys(labels.Labels)) {
+		output.Labels = append(output.Labels, ExternalLabel{Name: name, Values: labels.Labels[name]})
+	}
+
+	slog.Info("GetExternalLabelsHandler executed successfully", "labelCount", len(output.Labels), "source", output.Source)
+	return resultutil.NewSuccessResult(output)
+}
+
+// GetActiveQueriesHandler handles reporting the queries running on the query engines of the backend.
+func GetActiveQueriesHandler(ctx context.Context, promClient prometheus.Loader, _ ActiveQueriesInput) *resultutil.Result {
+	slog.Info("GetActiveQueriesHandler called")
+
+	active, err := promClient.GetActiveQueries(ctx)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get active queries: %w", err))
+	}
+
+	output := ActiveQueriesOutput{Engines: make([]QueryEngine, len(active.Engines)), QueryLogFile: active.QueryLogFile}
+	for i, engine := range active.Engines {
+		output.Engines[i] = QueryEngine{Labels: engine.Labels, Queries: engine.Queries, MaxConcurrency: engine.MaxConcurrency}
+		output.TotalQueries += engine.Queries
+	}
+	if len(output.Engines) == 0 {
+		output.Note = "no query engine metrics were found; the Prometheus instances behind the backend are not scraped, so their query load is unknown"
+	}
+
+	slog.Info("GetActiveQueriesHandler executed successfully", "engineCount", len(output.Engines), "totalQueries", output.TotalQueries)
+	return resultutil.NewSuccessResult(output)
+}
+
+// diffSeriesLabels splits the labels of the given series into those with the same value
+// on every series and those whose values differ. A label missing from some series is
+// treated as having the empty value there.
+func diffSeriesLabels(series []map[string]string) (map[string]string, []VaryingLabel) {
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]map[string]struct{})
+	for _, s := range series {
+		for name := range s {
+			if values[name] == nil {
+				values[name] = make(map[string]struct{})
+			}
+		}
+	}
+	for _, s := range series {
+		for name := range values {
+			values[name][s[name]] = struct{}{}
+		}
+	}
+
+	common := make(map[string]string)
+	var varying []VaryingLabel
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		distinct := slices.Sorted(maps.Keys(values[name]))
+		if len(distinct) == 1 {
+			common[name] = distinct[0]
+			continue
+		}
+		varying = append(varying, VaryingLabel{
+			Name:       name,
+			ValueCount: len(distinct),
+			Values:     distinct[:min(len(distinct), maxVaryingLabelValues)],
+		})
+	}
+	return common, varying
+}
+
+// ListRecordingRulesHandler handles the listing of recording rules and the metrics they record.
+func ListRecordingRulesHandler(ctx context.Context, promClient prometheus.Loader, input RecordingRulesInput) *resultutil.Result {
+	slog.Info("ListRecordingRulesHandler called")
+	slog.Debug("ListRecordingRulesHandler params", "input", input)
+
+	var nameRe *regexp.Regexp
+	if input.NameRegex != "" {
+		var err error
+		// Anchor the pattern the same way Prometheus anchors label matcher regexes.
+		nameRe, err = regexp.Compile("^(?:" + input.NameRegex + ")$")
 		if err != nil {
-			return resultutil.NewErrorResult(fmt.Errorf("invalid time format: %w", err))
+			return resultutil.NewErrorResult(fmt.Errorf("invalid name_regex %q: %w", input.NameRegex, err))
 		}
 	}
 
-	// Execute the instant query
-	result, err := promClient.ExecuteInstantQuery(ctx, input.Query, queryTime)
+	rules, err := promClient.GetRules(ctx)
 	if err != nil {
-		return resultutil.NewErrorResult(fmt.Errorf("failed to execute instant query: %w", err))
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get rules: %w", err))
 	}
 
-	// Convert to structured output
-	output := InstantQueryOutput{
-		ResultType: fmt.Sprintf("%v", result["resultType"]),
+	output := RecordingRulesOutput{
+		Rules: []RecordingRule{},
 	}
+	for _, group := range rules.Groups {
+		for _, rule := range group.Rules {
+			rr, ok := rule.(v1.RecordingRule)
+			if !ok {
+				continue
+			}
+			if nameRe != nil && !nameRe.MatchString(rr.Name) {
+				continue
+			}
 
-	resVector, ok := result["result"].(model.Vector)
-	if ok {
-		slog.Info("ExecuteInstantQueryHandler executed successfully", "resultLength", len(resVector))
-		slog.Debug("ExecuteInstantQueryHandler results", "results", resVector)
-
-		output.Result = make([]InstantResult, len(resVector))
-		for i, sample := range resVector {
-			labels := make(map[string]string)
-			for k, v := range sample.Metric {
+			labels := make(map[string]string, len(rr.Labels))
+			for k, v := range rr.Labels {
 				labels[string(k)] = string(v)
 			}
-			output.Result[i] = InstantResult{
-				Metric: labels,
-				Value:  []any{float64(sample.Timestamp) / millisecondsPerSecond, sample.Value.String()},
-			}
+			output.Rules = append(output.Rules, RecordingRule{
+				Metric: rr.Name,
+				Query:  rr.Query,
+				Group:  group.Name,
+				Labels: labels,
+				Health: string(rr.Health),
+			})
 		}
-	} else {
-		slog.Info("ExecuteInstantQueryHandler executed successfully (unknown format)", "result", result)
 	}
 
-	if warnings, ok := result["warnings"].([]string); ok {
-		output.Warnings = warnings
+	// Cross-reference with the metrics known to the backend, so that recorded metrics
+	// without data can be told apart. This is best effort and is skipped on failure.
+	if len(output.Rules) > 0 {
+		available, err := promClient.ListMetrics(ctx, ".*")
+		if err != nil {
+			slog.Warn("failed to list metrics for recording rules cross-reference", "error", err)
+		} else {
+			for i := range output.Rules {
+				output.Rules[i].HasData = ptr.To(slices.Contains(available, output.Rules[i].Metric))
+			}
+		}
 	}
 
+	slog.Info("ListRecordingRulesHandler executed successfully", "ruleCount", len(output.Rules))
+	slog.Debug("ListRecordingRulesHandler results", "results", output.Rules)
+
 	return resultutil.NewSuccessResult(output)
 }
 
-// GetLabelNamesHandler handles the retrieval of label names.
-func GetLabelNamesHandler(ctx context.Context, promClient prometheus.Loader, input LabelNamesInput) *resultutil.Result {
-	slog.Info("GetLabelNamesHandler called")
-	slog.Debug("GetLabelNamesHandler params", "input", input)
+// GetRuleGroupHandler returns the rules of a rule group with their last evaluation and
+// health, and which of them are failing.
+func GetRuleGroupHandler(ctx context.Context, promClient prometheus.Loader, input RuleGroupInput) *resultutil.Result {
+	slog.Info("GetRuleGroupHandler called")
+	slog.Debug("GetRuleGroupHandler params", "input", input)
+
+	if input.Group == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("group parameter is required and must be a string"))
+	}
 
-	startTime, endTime, err := parseDefaultTimeRange(input.Start, input.End)
+	rules, err := promClient.GetRules(ctx)
 	if err != nil {
-		return resultutil.NewErrorResult(err)
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get rules: %w", err))
 	}
 
-	// Get label names
-	labels, err := promClient.GetLabelNames(ctx, input.Metric, startTime, endTime)
+	group, err := findRuleGroup(rules.Groups, input.Group, input.File)
 	if err != nil {
-		return resultutil.NewErrorResult(fmt.Errorf("failed to get label names: %w", err))
+		return resultutil.NewErrorResult(err)
 	}
 
-	slog.Info("GetLabelNamesHandler executed successfully", "labelCount", len(labels))
-	slog.Debug("GetLabelNamesHandler results", "results", labels)
+	output := describeRuleGroup(group)
+	slog.Info("GetRuleGroupHandler executed successfully", "ruleCount", len(output.Rules), "failing", len(output.Failing))
+	return resultutil.NewSuccessResult(output)
+}
 
-	output := LabelNamesOutput{Labels: labels}
+// ListQueryTemplatesHandler handles the listing of built-in query templates.
+func ListQueryTemplatesHandler(_ context.Context, _ QueryTemplatesInput) *resultutil.Result {
+	slog.Info("ListQueryTemplatesHandler called")
+
+	output := QueryTemplatesOutput{Templates: SortedQueryTemplates()}
+
+	slog.Info("ListQueryTemplatesHandler executed successfully", "templateCount", len(output.Templates))
 	return resultutil.NewSuccessResult(output)
 }
 
-// GetLabelValuesHandler handles the retrieval of label values.
-func GetLabelValuesHandler(ctx context.Context, promClient prometheus.Loader, input LabelValuesInput) *resultutil.Result {
-	slog.Info("GetLabelValuesHandler called")
-	slog.Debug("GetLabelValuesHandler params", "input", input)
+// RenderQueryTemplateHandler handles rendering a query template into a PromQL query.
+func RenderQueryTemplateHandler(_ context.Context, input RenderQueryTemplateInput) *resultutil.Result {
+	slog.Info("RenderQueryTemplateHandler called")
+	slog.Debug("RenderQueryTemplateHandler params", "input", input)
 
-	// Validate required parameters
-	if input.Label == "" {
-		return resultutil.NewErrorResult(fmt.Errorf("label parameter is required and must be a string"))
+	if input.Name == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("name parameter is required and must be a string"))
+	}
+
+	query, err := ExpandQueryTemplate(input.Name, input.Params)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to render query template: %w", err))
+	}
+
+	slog.Info("RenderQueryTemplateHandler executed successfully", "template", input.Name)
+	slog.Debug("RenderQueryTemplateHandler results", "query", query)
+
+	return resultutil.NewSuccessResult(RenderQueryTemplateOutput{Name: input.Name, Query: query})
+}
+
+// QueryToPanelHandler handles turning a PromQL query into a Perses panel.
+func QueryToPanelHandler(_ context.Context, input QueryToPanelInput) *resultutil.Result {
+	slog.Info("QueryToPanelHandler called")
+	slog.Debug("QueryToPanelHandler params", "input", input)
+
+	if input.Query == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("query parameter is required and must be a string"))
+	}
+	if input.Title == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("title parameter is required and must be a string"))
+	}
+	if _, err := parser.NewParser(parser.Options{}).ParseExpr(input.Query); err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid query: %w", err))
 	}
 
-	startTime, endTime, err := parseDefaultTimeRange(input.Start, input.End)
+	panel, err := buildPersesPanel(input.Query, input.Title, input.Description, input.ChartType)
 	if err != nil {
 		return resultutil.NewErrorResult(err)
 	}
 
-	// Get label values
-	values, err := promClient.GetLabelValues(ctx, input.Label, input.Metric, startTime, endTime)
+	slog.Info("QueryToPanelHandler executed successfully", "chartType", panel.Spec.Plugin.Kind)
+	return resultutil.NewSuccessResult(QueryToPanelOutput{Panel: panel})
+}
+
+// ParseTimeHandler handles resolving a time expression the way the query tools do.
+func ParseTimeHandler(ctx context.Context, input ParseTimeInput) *resultutil.Result {
+	slog.Info("ParseTimeHandler called")
+	slog.Debug("ParseTimeHandler params", "input", input)
+
+	if input.Time == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("time parameter is required and must be a string"))
+	}
+
+	now := prometheus.Now(ctx)
+	t, err := prometheus.ParseTimestamp(ctx, input.Time)
 	if err != nil {
-		return resultutil.NewErrorResult(fmt.Errorf("failed to get label values: %w", err))
+		if suggestions := timestampSuggestions(input.Time); len(suggestions) > 0 {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid time %q: %w; did you mean %q?", input.Time, err, suggestions[0]))
+		}
+		return resultutil.NewErrorResult(fmt.Errorf("invalid time %q: %w", input.Time, err))
 	}
 
-	slog.Info("GetLabelValuesHandler executed successfully", "valueCount", len(values))
-	slog.Debug("GetLabelValuesHandler results", "results", values)
+	output := ParseTimeOutput{
+		Input:     input.Time,
+		Timestamp: t.UTC().Format(time.RFC3339),
+		Unix:      t.Unix(),
+		Relative:  describeOffset(t, now),
+		Warning:   timestampWarning(input.Time, t, now),
+	}
 
-	output := LabelValuesOutput{Values: values}
+	slog.Info("ParseTimeHandler executed successfully", "timestamp", output.Timestamp)
 	return resultutil.NewSuccessResult(output)
 }
 
-// GetSeriesHandler handles the retrieval of time series.
-func GetSeriesHandler(ctx context.Context, promClient prometheus.Loader, input SeriesInput) *resultutil.Result {
-	slog.Info("GetSeriesHandler called")
-	slog.Debug("GetSeriesHandler params", "input", input)
+// SaveQueryResultHandler runs a query and writes its full result to a file in outputDir,
+// returning the file path and a summary. An empty outputDir means file output is disabled.
+func SaveQueryResultHandler(ctx context.Context, promClient prometheus.Loader, input SaveQueryResultInput, outputDir string) *resultutil.Result {
+	slog.Info("SaveQueryResultHandler called")
+	slog.Debug("SaveQueryResultHandler params", "input", input)
 
-	// Validate required parameters
-	if input.Matches == "" {
-		return resultutil.NewErrorResult(fmt.Errorf("matches parameter is required and must be a string"))
+	if outputDir == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("file output is disabled; enable it with --allow-file-output and --file-output-dir"))
+	}
+	if input.Query == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("query parameter is required and must be a string"))
 	}
 
-	// Parse matches - could be comma-separated
-	matches := []string{input.Matches}
-	// If it contains comma outside of braces, split it
-	// For simplicity, treat the entire string as one match for now
-	// Users can make multiple calls if needed
+	format := input.Format
+	if format == "" {
+		format = FileFormatJSON
+	}
+	if format != FileFormatJSON && format != FileFormatCSV {
+		return resultutil.NewErrorResult(fmt.Errorf("invalid format %q (valid options: %q, %q)", format, FileFormatJSON, FileFormatCSV))
+	}
 
-	startTime, endTime, err := parseDefaultTimeRange(input.Start, input.End)
+	filename, err := resultFilename(input.Filename, format)
 	if err != nil {
 		return resultutil.NewErrorResult(err)
 	}
 
-	// Get series
-	series, err := promClient.GetSeries(ctx, matches, startTime, endTime)
-	if err != nil {
-		return resultutil.NewErrorResult(fmt.Errorf("failed to get series: %w", err))
+	var result map[string]any
+	if input.Step != "" {
+		step, err := input.Step.Duration()
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid step format: %w", err))
+		}
+		startTime, endTime, err := parseRangeQueryTimes(ctx, input.Start, input.End, input.Duration)
+		if err != nil {
+			return resultutil.NewErrorResult(err)
+		}
+		result, err = promClient.ExecuteRangeQuery(ctx, input.Query, startTime, endTime, step)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("failed to execute range query: %w", err))
+		}
+	} else {
+		queryTime := prometheus.Now(ctx)
+		if input.Time != "" {
+			queryTime, err = prometheus.ParseTimestamp(ctx, input.Time)
+			if err != nil {
+				return resultutil.NewErrorResult(fmt.Errorf("invalid time format: %w", err))
+			}
+		}
+		result, err = promClient.ExecuteInstantQuery(ctx, input.Query, queryTime)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("failed to execute instant query: %w", err))
+		}
 	}
 
-	slog.Info("GetSeriesHandler executed successfully", "cardinality", len(series))
-	slog.Debug("GetSeriesHandler results", "results", series)
+	value, ok := result["result"].(model.Value)
+	if !ok {
+		return resultutil.NewErrorResult(fmt.Errorf("unexpected query result of type %T", result["result"]))
+	}
 
-	output := SeriesOutput{
-		Series:      series,
-		Cardinality: len(series),
+	path, size, err := writeResultFile(outputDir, filename, format, value)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	output := SaveQueryResultOutput{
+		Path:       path,
+		Format:     format,
+		ResultType: value.Type().String(),
+		SizeBytes:  size,
 	}
+	output.SeriesCount, output.SampleCount = countSamples(value)
+
+	slog.Info("SaveQueryResultHandler executed successfully", "path", path, "sizeBytes", size)
 	return resultutil.NewSuccessResult(output)
 }
 
 // GetAlertsHandler handles the retrieval of alerts from Alertmanager.
-func GetAlertsHandler(ctx context.Context, amClient alertmanager.Loader, input AlertsInput) *resultutil.Result {
+// Without 'full', more than maxListedAlerts alerts are summarized instead of listed.
+// With 'full' and a non-nil progress, the alerts are also passed to progress in batches
+// as they are fetched and converted, so that a client can process them before the
+// complete list is returned. Alerts keep only the labels and annotations of projection,
+// or of the labels and annotations of the input when set.
+func GetAlertsHandler(ctx context.Context, amClient alertmanager.Loader, input AlertsInput, projection AlertProjection, progress AlertsProgressFunc) *resultutil.Result {
 	slog.Info("GetAlertsHandler called")
 	slog.Debug("GetAlertsHandler params", "input", input)
 
-	alerts, err := amClient.GetAlerts(ctx, input.Active, input.Silenced, input.Inhibited, input.Unprocessed, parseFilterString(input.Filter), input.Receiver)
+	projection, err := projection.override(input)
 	if err != nil {
-		return resultutil.NewErrorResult(fmt.Errorf("failed to get alerts: %w", err))
+		return resultutil.NewErrorResult(err)
 	}
 
-	output := AlertsOutput{
-		Alerts: make([]Alert, len(alerts)),
+	if len(input.AnyOf) > maxAlertFilterGroups {
+		return resultutil.NewErrorResult(fmt.Errorf("any_of has %d matcher groups, at most %d are allowed", len(input.AnyOf), maxAlertFilterGroups))
+	}
+	var minActive time.Duration
+	if input.MinActive != "" {
+		d, err := model.ParseDuration(input.MinActive)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid min_active_duration format: %w", err))
+		}
+		minActive = time.Duration(d)
+	}
+	now := prometheus.Now(ctx)
+
+	output := AlertsOutput{Alerts: []Alert{}}
+	var fetched ammodels.GettableAlerts
+	var batch []Alert
+	flush := func() {
+		if len(batch) > 0 {
+			progress(batch, len(output.Alerts)-len(batch))
+			batch = nil
+		}
+	}
+	err = getAlertsMatchingAny(ctx, amClient, input, func(alerts ammodels.GettableAlerts) {
+		if minActive > 0 {
+			alerts = slices.DeleteFunc(alerts, func(alert *ammodels.GettableAlert) bool {
+				return alertActiveDuration(alert, now) < minActive
+			})
+		}
+		fetched = append(fetched, alerts...)
+		for _, alert := range alerts {
+			converted := projection.apply(convertAlert(alert))
+			output.Alerts = append(output.Alerts, converted)
+			if input.Full && progress != nil {
+				batch = append(batch, converted)
+				if len(batch) == alertsProgressBatchSize {
+					flush()
+				}
+			}
+		}
+		if input.Full && progress != nil {
+			flush()
+		}
+	})
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get alerts: %w", err))
 	}
-	for i, alert := range alerts {
-		output.Alerts[i] = convertAlert(alert)
+
+	if !input.Full && len(fetched) > maxListedAlerts {
+		summary := summarizeAlerts(fetched, defaultTopAlerts)
+		output = AlertsOutput{
+			Alerts:  []Alert{},
+			Summary: &summary,
+			Note: fmt.Sprintf("%d alerts match, more than the %d listed without 'full'; showing a summary instead. "+
+				"Narrow the filters, e.g. by alertname or namespace from the summary, or set 'full' to list them all", len(fetched), maxListedAlerts),
+		}
 	}
 
-	slog.Info("GetAlertsHandler executed successfully", "alertCount", len(alerts))
+	slog.Info("GetAlertsHandler executed successfully", "alertCount", len(fetched), "summarized", output.Summary != nil)
 	slog.Debug("GetAlertsHandler results", "results", output.Alerts)
 
 	return resultutil.NewSuccessResult(output)
 }
 
+// SummarizeAlertsHandler handles counting alerts from Alertmanager by severity,
+// namespace and alert name.
+func SummarizeAlertsHandler(ctx context.Context, amClient alertmanager.Loader, input SummarizeAlertsInput) *resultutil.Result {
+	slog.Info("SummarizeAlertsHandler called")
+	slog.Debug("SummarizeAlertsHandler params", "input", input)
+
+	if input.Top < 0 {
+		return resultutil.NewErrorResult(fmt.Errorf("top must not be negative"))
+	}
+
+	alerts, err := amClient.GetAlerts(ctx, input.Active, input.Silenced, input.Inhibited, nil, parseFilterString(input.Filter), input.Receiver)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get alerts: %w", err))
+	}
+
+	output := summarizeAlerts(alerts, cmp.Or(input.Top, defaultTopAlerts))
+
+	slog.Info("SummarizeAlertsHandler executed successfully", "alertCount", output.Total)
+	return resultutil.NewSuccessResult(output)
+}
+
+const (
+	// maxAlertFilterGroups caps the number of any_of groups, each of which costs an
+	// Alertmanager request.
+	maxAlertFilterGroups = 10
+	// maxListedAlerts is the number of alerts get_alerts lists without 'full'.
+	maxListedAlerts = 100
+	// alertsProgressBatchSize is the number of alerts passed to an AlertsProgressFunc at once.
+	alertsProgressBatchSize = 50
+)
+
+// alertActiveDuration returns how long an alert has been active at now, from its start
+// time; alerts without a start time count as just started.
+func alertActiveDuration(alert *ammodels.GettableAlert, now time.Time) time.Duration {
+	if alert.StartsAt == nil {
+		return 0
+	}
+	return now.Sub(time.Time(*alert.StartsAt))
+}
+
+// AlertsProgressFunc receives a batch of the alerts of a get_alerts call, sent is the
+// number of alerts passed in earlier batches.
+type AlertsProgressFunc func(batch []Alert, sent int)
+
+// getAlertsMatchingAny passes to add the alerts matching filter and at least one of the
+// any_of matcher groups, as each request completes. Alertmanager ANDs the matchers of a
+// request, so each group is fetched separately and the results are merged, keeping the
+// first occurrence of each alert.
+func getAlertsMatchingAny(ctx context.Context, amClient alertmanager.Loader, input AlertsInput, add func(ammodels.GettableAlerts)) error {
+	filter := parseFilterString(input.Filter)
+	if len(input.AnyOf) == 0 {
+		alerts, err := amClient.GetAlerts(ctx, input.Active, input.Silenced, input.Inhibited, input.Unprocessed, filter, input.Receiver)
+		if err != nil {
+			return err
+		}
+		add(alerts)
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, group := range input.AnyOf {
+		matchers := slices.Concat(filter, parseFilterString(group))
+		alerts, err := amClient.GetAlerts(ctx, input.Active, input.Silenced, input.Inhibited, input.Unprocessed, matchers, input.Receiver)
+		if err != nil {
+			return err
+		}
+		var added ammodels.GettableAlerts
+		for _, alert := range alerts {
+			key := ptr.Deref(alert.Fingerprint, "")
+			if key == "" {
+				key = strconv.FormatUint(model.LabelsToSignature(alert.Labels), 10)
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			added = append(added, alert)
+		}
+		add(added)
+	}
+	return nil
+}
+
+const (
+	// defaultAlertHistoryWindow is how far back get_alert_history looks when 'since' is not set.
+	defaultAlertHistoryWindow = time.Hour
+	// minAlertHistoryStep is the finest resolution the ALERTS series is sampled at,
+	// in line with common rule evaluation intervals.
+	minAlertHistoryStep = 30 * time.Second
+	// maxAlertHistoryPoints bounds the number of samples per series for long windows.
+	maxAlertHistoryPoints = 1000
+)
+
+// GetAlertHistoryHandler reconstructs the intervals during which alerts were active
+// from the ALERTS series, since Alertmanager keeps no history of resolved alerts.
+func GetAlertHistoryHandler(ctx context.Context, promClient prometheus.Loader, input AlertHistoryInput) *resultutil.Result {
+	slog.Info("GetAlertHistoryHandler called")
+	slog.Debug("GetAlertHistoryHandler params", "input", input)
+
+	var err error
+	until := prometheus.Now(ctx)
+	if input.Until != "" {
+		until, err = prometheus.ParseTimestamp(ctx, input.Until)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid until time format: %w", err))
+		}
+	}
+	since := until.Add(-defaultAlertHistoryWindow)
+	if input.Since != "" {
+		since, err = prometheus.ParseTimestamp(ctx, input.Since)
+		if err != nil {
+			return resultutil.NewErrorResult(fmt.Errorf("invalid since time format: %w", err))
+		}
+	}
+	if !since.Before(until) {
+		return resultutil.NewErrorResult(fmt.Errorf("since must be before until"))
+	}
+
+	query, err := alertHistoryQuery(input.Filter)
+	if err != nil {
+		return resultutil.NewErrorResult(err)
+	}
+
+	step := max(minAlertHistoryStep, until.Sub(since)/maxAlertHistoryPoints).Round(time.Second)
+	result, err := promClient.ExecuteRangeQuery(ctx, query, since, until, step)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to query alert history: %w", err))
+	}
+
+	matrix, _ := result["result"].(model.Matrix)
+	output := AlertHistoryOutput{
+		Alerts:     make([]AlertHistory, 0, len(matrix)),
+		Resolution: model.Duration(step).String(),
+	}
+	for _, series := range matrix {
+		if len(series.Values) == 0 {
+			continue
+		}
+		labels := make(map[string]string, len(series.Metric))
+		for k, v := range series.Metric {
+			if k != model.MetricNameLabel {
+				labels[string(k)] = string(v)
+			}
+		}
+		output.Alerts = append(output.Alerts, AlertHistory{
+			Labels:    labels,
+			Intervals: alertIntervals(series.Values, step, until),
+		})
+	}
+	slices.SortStableFunc(output.Alerts, func(a, b AlertHistory) int {
+		return strings.Compare(a.Intervals[0].Start, b.Intervals[0].Start)
+	})
+
+	slog.Info("GetAlertHistoryHandler executed successfully", "alertCount", len(output.Alerts))
+	slog.Debug("GetAlertHistoryHandler results", "results", output.Alerts)
+	return resultutil.NewSuccessResult(output)
+}
+
+// alertHistoryQuery builds the ALERTS selector for the given Alertmanager-style label
+// matchers, restricted to firing alerts unless an alertstate matcher is provided.
+func alertHistoryQuery(filter string) (string, error) {
+	matchers := []string{}
+	hasState := false
+	if filter != "" {
+		parsed, err := amlabels.ParseMatchers(filter)
+		if err != nil {
+			return "", fmt.Errorf("invalid filter: %w", err)
+		}
+		for _, m := range parsed {
+			hasState = hasState || m.Name == "alertstate"
+			matchers = append(matchers, m.String())
+		}
+	}
+	if !hasState {
+		matchers = append([]string{`alertstate="firing"`}, matchers...)
+	}
+	return "ALERTS{" + strings.Join(matchers, ", ") + "}", nil
+}
+
+// alertIntervals groups the samples of an ALERTS series into intervals of consecutive
+// evaluation steps. A missing step means the alert was not active at that time.
+func alertIntervals(samples []model.SamplePair, step time.Duration, until time.Time) []AlertInterval {
+	var intervals []AlertInterval
+	for i := 0; i < len(samples); {
+		j := i
+		for j+1 < len(samples) && samples[j+1].Timestamp.Sub(samples[j].Timestamp) <= step {
+			j++
+		}
+		end := samples[j].Timestamp.Time()
+		intervals = append(intervals, AlertInterval{
+			Start:   samples[i].Timestamp.Time().UTC().Format(time.RFC3339),
+			End:     end.UTC().Format(time.RFC3339),
+			Ongoing: until.Sub(end) < step,
+		})
+		i = j + 1
+	}
+	return intervals
+}
+
+// GetAlertThresholdHandler returns the threshold of the alerting rules with the given
+// name, together with the current values they compare against it.
+func GetAlertThresholdHandler(ctx context.Context, promClient prometheus.Loader, input AlertThresholdInput) *resultutil.Result {
+	slog.Info("GetAlertThresholdHandler called")
+	slog.Debug("GetAlertThresholdHandler params", "input", input)
+
+	if input.AlertName == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("alertname parameter is required"))
+	}
+
+	rules, err := promClient.GetRules(ctx)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get rules: %w", err))
+	}
+
+	output := AlertThresholdOutput{
+		Rules: []AlertThreshold{},
+	}
+	now := prometheus.Now(ctx)
+	for _, group := range rules.Groups {
+		for _, rule := range group.Rules {
+			ar, ok := rule.(v1.AlertingRule)
+			if !ok || ar.Name != input.AlertName {
+				continue
+			}
+
+			entry := AlertThreshold{
+				Alert:      ar.Name,
+				Group:      group.Name,
+				Expression: ar.Query,
+			}
+			if ar.Duration > 0 {
+				entry.For = model.Duration(time.Duration(ar.Duration * float64(time.Second))).String()
+			}
+			threshold, ok := extractThreshold(ar.Query)
+			if !ok {
+				entry.Note = "the expression does not compare a query against a constant threshold"
+				output.Rules = append(output.Rules, entry)
+				continue
+			}
+			entry.ValueQuery = threshold.value
+			entry.Operator = threshold.op
+			entry.Threshold = model.SampleValue(threshold.threshold).String()
+
+			result, err := promClient.ExecuteInstantQuery(ctx, threshold.value, now)
+			if err != nil {
+				return resultutil.NewErrorResult(fmt.Errorf("failed to evaluate %q of alert %s: %w", threshold.value, ar.Name, err))
+			}
+			switch value := result["result"].(type) {
+			case model.Vector:
+				entry.Series = make([]ThresholdSeries, 0, len(value))
+				for _, sample := range value {
+					entry.Series = append(entry.Series, thresholdSeries(sample.Metric, sample.Value, threshold))
+				}
+			case *model.Scalar:
+				entry.Series = []ThresholdSeries{thresholdSeries(model.Metric{}, value.Value, threshold)}
+			}
+			if len(entry.Series) == 0 {
+				entry.Note = "the value query currently returns no series"
+			}
+			output.Rules = append(output.Rules, entry)
+		}
+	}
+	if len(output.Rules) == 0 {
+		return resultutil.NewErrorResult(fmt.Errorf("no alerting rule named %q found", input.AlertName))
+	}
+
+	slog.Info("GetAlertThresholdHandler executed successfully", "ruleCount", len(output.Rules))
+	slog.Debug("GetAlertThresholdHandler results", "results", output.Rules)
+	return resultutil.NewSuccessResult(output)
+}
+
+// thresholdSeries compares the current value of a series against an alert threshold.
+func thresholdSeries(metric model.Metric, value model.SampleValue, threshold ruleThreshold) ThresholdSeries {
+	labels := make(map[string]string, len(metric))
+	for k, v := range metric {
+		labels[string(k)] = string(v)
+	}
+	return ThresholdSeries{
+		Labels:   labels,
+		Value:    value.String(),
+		Margin:   (value - model.SampleValue(threshold.threshold)).String(),
+		Breached: threshold.compare(float64(value)),
+	}
+}
+
 // GetSilencesHandler handles the retrieval of silences from Alertmanager.
 func GetSilencesHandler(ctx context.Context, amClient alertmanager.Loader, input SilencesInput) *resultutil.Result {
 	slog.Info("GetSilencesHandler called")
@@ -592,3 +2866,71 @@ func GetSilencesHandler(ctx context.Context, amClient alertmanager.Loader, input
 
 	return resultutil.NewSuccessResult(output)
 }
+
+// DeleteSilenceHandler handles expiring a silence in Alertmanager. allowWrites tells
+// whether the server may change the state of Alertmanager.
+func DeleteSilenceHandler(ctx context.Context, amClient alertmanager.Loader, input DeleteSilenceInput, allowWrites bool) *resultutil.Result {
+	slog.Info("DeleteSilenceHandler called")
+	slog.Debug("DeleteSilenceHandler params", "input", input)
+
+	if !allowWrites {
+		return resultutil.NewErrorResult(fmt.Errorf("writes are disabled; enable them with --allow-writes"))
+	}
+	if input.ID == "" {
+		return resultutil.NewErrorResult(fmt.Errorf("id parameter is required and must be a string"))
+	}
+
+	if err := amClient.DeleteSilence(ctx, input.ID); err != nil {
+		if errors.Is(err, alertmanager.ErrSilenceNotFound) {
+			return resultutil.NewErrorResult(fmt.Errorf("%w; list the silences with get_silences to find its ID", err))
+		}
+		return resultutil.NewErrorResult(fmt.Errorf("failed to delete silence: %w", err))
+	}
+
+	slog.Info("DeleteSilenceHandler executed successfully", "id", input.ID)
+	return resultutil.NewSuccessResult(DeleteSilenceOutput{
+		ID:      input.ID,
+		Message: fmt.Sprintf("silence %s expired; the alerts it muted notify again", input.ID),
+	})
+}
+
+// GetAlertmanagerStatusHandler handles reporting the version, cluster state and
+// configuration hash of Alertmanager. The configuration itself is not returned, as it may
+// contain credentials of receivers.
+func GetAlertmanagerStatusHandler(ctx context.Context, amClient alertmanager.Loader, _ AlertmanagerStatusInput) *resultutil.Result {
+	slog.Info("GetAlertmanagerStatusHandler called")
+
+	status, err := amClient.GetStatus(ctx)
+	if err != nil {
+		return resultutil.NewErrorResult(fmt.Errorf("failed to get Alertmanager status: %w", err))
+	}
+
+	output := AlertmanagerStatusOutput{Peers: []AlertmanagerPeer{}}
+	if info := status.VersionInfo; info != nil {
+		output.Version = ptr.Deref(info.Version, "")
+		output.Revision = ptr.Deref(info.Revision, "")
+	}
+	if status.Uptime != nil {
+		output.StartedAt = time.Time(*status.Uptime).UTC().Format(time.RFC3339)
+	}
+	if cluster := status.Cluster; cluster != nil {
+		output.ClusterStatus = ptr.Deref(cluster.Status, "")
+		output.ClusterName = cluster.Name
+		for _, peer := range cluster.Peers {
+			if peer == nil {
+				continue
+			}
+			output.Peers = append(output.Peers, AlertmanagerPeer{
+				Name:    ptr.Deref(peer.Name, ""),
+				Address: ptr.Deref(peer.Address, ""),
+			})
+		}
+	}
+	if status.Config != nil {
+		sum := sha256.Sum256([]byte(ptr.Deref(status.Config.Original, "")))
+		output.ConfigHash = hex.EncodeToString(sum[:])
+	}
+
+	slog.Info("GetAlertmanagerStatusHandler executed successfully", "clusterStatus", output.ClusterStatus, "peerCount", len(output.Peers))
+	return resultutil.NewSuccessResult(output)
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+var bucketMetricRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*_bucket$`)
+
+// heatmapQuery returns the query counting the observations of a classic histogram per
+// cumulative bucket within each step.
+func heatmapQuery(metric, selector string, step time.Duration) (string, error) {
+	if !bucketMetricRe.MatchString(metric) {
+		return "", fmt.Errorf("metric %q is not a histogram bucket metric; use the metric name ending in _bucket (e.g., http_request_duration_seconds_bucket)", metric)
+	}
+	braced, err := bracedSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sum by (le) (increase(%s%s[%s]))", metric, braced, model.Duration(step)), nil
+}
+
+// bracedSelector returns label matchers given with or without braces in braces, or an
+// empty string when there are none, to be appended to a metric name. The matchers are
+// parsed and rebuilt, so that the selector cannot add anything else to the query.
+func bracedSelector(selector string) (string, error) {
+	selector = strings.TrimSpace(selector)
+	selector = strings.TrimSuffix(strings.TrimPrefix(selector, "{"), "}")
+	if strings.TrimSpace(selector) == "" {
+		return "", nil
+	}
+	matchers, err := parser.NewParser(parser.Options{}).ParseMetricSelector("{" + selector + "}")
+	if err != nil {
+		return "", fmt.Errorf("invalid selector %q: must be label matchers such as {job=\"api\"}: %w", selector, err)
+	}
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Name == model.MetricNameLabel {
+			return "", fmt.Errorf("invalid selector %q: must not match %s, the metric is given separately", selector, model.MetricNameLabel)
+		}
+		parts = append(parts, m.String())
+	}
+	return "{" + strings.Join(parts, ",") + "}", nil
+}
+
+// heatmapBucket is a cumulative histogram bucket of a heatmap query result.
+type heatmapBucket struct {
+	le      string
+	bound   float64
+	samples []model.SamplePair
+}
+
+// buildHeatmap reshapes the cumulative bucket series of a heatmap query into a grid of
+// observation counts per time and bucket. Each bucket counts the observations above the
+// previous bucket bound; negative differences, e.g. from counter resets in the middle of
+// a step, are counted as 0.
+func buildHeatmap(matrix model.Matrix) (buckets []string, timestamps []float64, counts [][]float64, err error) {
+	var series []heatmapBucket
+	for _, s := range matrix {
+		le, ok := s.Metric[model.BucketLabel]
+		if !ok {
+			continue
+		}
+		bound, err := strconv.ParseFloat(string(le), 64)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid bucket bound le=%q: %w", le, err)
+		}
+		series = append(series, heatmapBucket{le: string(le), bound: bound, samples: s.Values})
+	}
+	if len(series) == 0 {
+		if len(matrix) > 0 {
+			return nil, nil, nil, fmt.Errorf("the result has no %q label; query_heatmap only supports classic histograms", model.BucketLabel)
+		}
+		return []string{}, []float64{}, [][]float64{}, nil
+	}
+	slices.SortFunc(series, func(a, b heatmapBucket) int {
+		return cmp.Compare(a.bound, b.bound)
+	})
+
+	var times []model.Time
+	values := make([]map[model.Time]float64, len(series))
+	for i, s := range series {
+		values[i] = make(map[model.Time]float64, len(s.samples))
+		for _, sample := range s.samples {
+			values[i][sample.Timestamp] = float64(sample.Value)
+			times = append(times, sample.Timestamp)
+		}
+	}
+	slices.Sort(times)
+	times = slices.Compact(times)
+
+	buckets = make([]string, len(series))
+	for i, s := range series {
+		buckets[i] = s.le
+	}
+	timestamps = make([]float64, len(times))
+	counts = make([][]float64, len(times))
+	for t, ts := range times {
+		timestamps[t] = float64(ts) / millisecondsPerSecond
+		counts[t] = make([]float64, len(series))
+		previous := 0.0
+		for i := range series {
+			cumulative, ok := values[i][ts]
+			if !ok || math.IsNaN(cumulative) {
+				cumulative = previous
+			}
+			counts[t][i] = max(0, cumulative-previous)
+			previous = max(previous, cumulative)
+		}
+	}
+	return buckets, timestamps, counts, nil
+}
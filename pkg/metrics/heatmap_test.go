@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestHeatmapQuery(t *testing.T) {
+	tests := []struct {
+		metric   string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{metric: "rpc_duration_seconds_bucket", want: `sum by (le) (increase(rpc_duration_seconds_bucket[5m]))`},
+		{metric: "rpc_duration_seconds_bucket", selector: ` {job="api"} `, want: `sum by (le) (increase(rpc_duration_seconds_bucket{job="api"}[5m]))`},
+		{metric: "rpc_duration_seconds_bucket", selector: `job="api",code!~"5.."`, want: `sum by (le) (increase(rpc_duration_seconds_bucket{job="api",code!~"5.."}[5m]))`},
+		{metric: "rpc_duration_seconds_count", wantErr: true},
+		{metric: `rpc_bucket{job="api"}`, wantErr: true},
+		{metric: "rpc_duration_seconds_bucket", selector: `job="api"}[1h])) or vector(1) or (rpc_bucket{`, wantErr: true},
+		{metric: "rpc_duration_seconds_bucket", selector: `job="api"} or up{`, wantErr: true},
+		{metric: "rpc_duration_seconds_bucket", selector: `{__name__="up"}`, wantErr: true},
+		{metric: "rpc_duration_seconds_bucket", selector: `{ job = 'api' }`, want: `sum by (le) (increase(rpc_duration_seconds_bucket{job="api"}[5m]))`},
+	}
+
+	for _, tt := range tests {
+		got, err := heatmapQuery(tt.metric, tt.selector, 5*time.Minute)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("heatmapQuery(%q) expected an error, got %q", tt.metric, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("heatmapQuery(%q) unexpected error: %v", tt.metric, err)
+		} else if got != tt.want {
+			t.Errorf("heatmapQuery(%q, %q) = %q, want %q", tt.metric, tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestBuildHeatmap_MissingSamples(t *testing.T) {
+	// The 0.5 bucket has no sample at the second timestamp, and the counts of +Inf
+	// dropped below those of the 1 bucket, e.g. because of a counter reset.
+	matrix := model.Matrix{
+		{Metric: model.Metric{"le": "0.5"}, Values: []model.SamplePair{{Timestamp: 0, Value: 2}}},
+		{Metric: model.Metric{"le": "1"}, Values: []model.SamplePair{{Timestamp: 0, Value: 3}, {Timestamp: 60000, Value: 4}}},
+		{Metric: model.Metric{"le": "+Inf"}, Values: []model.SamplePair{{Timestamp: 0, Value: 1}, {Timestamp: 60000, Value: 5}}},
+	}
+
+	buckets, timestamps, counts, err := buildHeatmap(matrix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) != 3 || len(timestamps) != 2 {
+		t.Fatalf("unexpected shape: buckets %v, timestamps %v", buckets, timestamps)
+	}
+	want := [][]float64{{2, 1, 0}, {0, 4, 1}}
+	for i := range want {
+		for j := range want[i] {
+			if counts[i][j] != want[i][j] {
+				t.Fatalf("counts = %v, want %v", counts, want)
+			}
+		}
+	}
+
+	if _, _, _, err := buildHeatmap(model.Matrix{{Metric: model.Metric{"job": "api"}}}); err == nil {
+		t.Error("expected an error for series without an le label")
+	}
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+// inspectMetricJobs groups the series of a metric by job and compares the label names
+// the jobs export it with. Label names that not every job uses mark the metric as
+// ambiguous, as the jobs likely give it different meanings.
+func inspectMetricJobs(metric string, series []map[string]string) InspectMetricOutput {
+	counts := make(map[string]int)
+	labelSets := make(map[string]map[string]bool)
+	for _, s := range series {
+		job := s["job"]
+		counts[job]++
+		if labelSets[job] == nil {
+			labelSets[job] = make(map[string]bool)
+		}
+		for name := range s {
+			if name != model.MetricNameLabel && name != "job" {
+				labelSets[job][name] = true
+			}
+		}
+	}
+
+	jobs := slices.Sorted(maps.Keys(counts))
+	var common []string
+	if len(jobs) > 0 {
+		for name := range labelSets[jobs[0]] {
+			if !slices.ContainsFunc(jobs, func(job string) bool { return !labelSets[job][name] }) {
+				common = append(common, name)
+			}
+		}
+		slices.Sort(common)
+	}
+
+	output := InspectMetricOutput{
+		Metric:       metric,
+		SeriesCount:  len(series),
+		CommonLabels: common,
+		Jobs:         make([]MetricJob, 0, len(jobs)),
+	}
+	var differing []string
+	for _, job := range jobs {
+		labels := slices.Sorted(maps.Keys(labelSets[job]))
+		j := MetricJob{Job: job, SeriesCount: counts[job], Labels: labels}
+		for _, name := range labels {
+			if !slices.Contains(common, name) {
+				j.JobOnlyLabels = append(j.JobOnlyLabels, name)
+			}
+		}
+		if len(j.JobOnlyLabels) > 0 {
+			differing = append(differing, fmt.Sprintf("%s (%s)", cmp.Or(job, "<no job>"), strings.Join(j.JobOnlyLabels, ", ")))
+		}
+		output.Jobs = append(output.Jobs, j)
+	}
+	slices.SortStableFunc(output.Jobs, func(a, b MetricJob) int { return cmp.Compare(b.SeriesCount, a.SeriesCount) })
+
+	if len(differing) > 0 {
+		output.Ambiguous = true
+		output.Warnings = append(output.Warnings, fmt.Sprintf(
+			"%s is exported by %d jobs with different labels: %s; they may give it different meanings, so filter on job instead of mixing their series",
+			metric, len(jobs), strings.Join(differing, "; ")))
+	}
+	return output
+}
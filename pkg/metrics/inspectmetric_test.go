@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInspectMetricJobs(t *testing.T) {
+	t.Run("jobs with different labels are ambiguous", func(t *testing.T) {
+		series := []map[string]string{
+			{"__name__": "requests_total", "job": "api", "instance": "a:8080", "code": "200"},
+			{"__name__": "requests_total", "job": "api", "instance": "a:8080", "code": "500"},
+			{"__name__": "requests_total", "job": "api", "instance": "b:8080", "code": "200"},
+			{"__name__": "requests_total", "job": "proxy", "instance": "c:9090", "backend": "db"},
+		}
+
+		got := inspectMetricJobs("requests_total", series)
+
+		if got.SeriesCount != 4 || !got.Ambiguous {
+			t.Errorf("seriesCount = %d, ambiguous = %v, want 4 and true", got.SeriesCount, got.Ambiguous)
+		}
+		if want := []string{"instance"}; !reflect.DeepEqual(got.CommonLabels, want) {
+			t.Errorf("commonLabels = %v, want %v", got.CommonLabels, want)
+		}
+		want := []MetricJob{
+			{Job: "api", SeriesCount: 3, Labels: []string{"code", "instance"}, JobOnlyLabels: []string{"code"}},
+			{Job: "proxy", SeriesCount: 1, Labels: []string{"backend", "instance"}, JobOnlyLabels: []string{"backend"}},
+		}
+		if !reflect.DeepEqual(got.Jobs, want) {
+			t.Errorf("jobs = %+v, want %+v", got.Jobs, want)
+		}
+		if len(got.Warnings) != 1 || !strings.Contains(got.Warnings[0], "api (code); proxy (backend)") {
+			t.Errorf("expected a warning naming the differing labels, got %v", got.Warnings)
+		}
+	})
+
+	t.Run("jobs with the same labels are not ambiguous", func(t *testing.T) {
+		series := []map[string]string{
+			{"__name__": "up", "job": "api", "instance": "a:8080"},
+			{"__name__": "up", "job": "db", "instance": "b:5432"},
+			{"__name__": "up", "job": "db", "instance": "c:5432"},
+		}
+
+		got := inspectMetricJobs("up", series)
+
+		if got.Ambiguous || len(got.Warnings) != 0 {
+			t.Errorf("expected no ambiguity, got %+v", got)
+		}
+		if len(got.Jobs) != 2 || got.Jobs[0].Job != "db" || got.Jobs[1].Job != "api" {
+			t.Errorf("expected jobs ordered by series count, got %+v", got.Jobs)
+		}
+	})
+
+	t.Run("series without a job label", func(t *testing.T) {
+		got := inspectMetricJobs("up", []map[string]string{
+			{"__name__": "up", "instance": "a:8080"},
+			{"__name__": "up", "job": "api", "instance": "b:8080", "pod": "api-1"},
+		})
+
+		if !got.Ambiguous || len(got.Warnings) != 1 || !strings.Contains(got.Warnings[0], "api (pod)") {
+			t.Errorf("expected the job with the extra label to be reported, got %+v", got)
+		}
+		if got.Jobs[0].Job != "" && got.Jobs[1].Job != "" {
+			t.Errorf("expected series without a job label to be grouped under an empty job, got %+v", got.Jobs)
+		}
+	})
+
+	t.Run("no series", func(t *testing.T) {
+		got := inspectMetricJobs("up", nil)
+		if got.SeriesCount != 0 || len(got.Jobs) != 0 || got.Ambiguous {
+			t.Errorf("expected an empty report, got %+v", got)
+		}
+	})
+}
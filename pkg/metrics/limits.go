@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Keys of the limits configuration, naming the results each limit applies to.
+const (
+	LimitListMetrics       = "list_metrics"
+	LimitLabelValues       = "get_label_values"
+	LimitLabelsOverview    = "get_labels_overview"
+	LimitSeries            = "get_series"
+	LimitQueryResultSeries = "query_result_series"
+	LimitMetricMetadata    = "get_metric_metadata"
+)
+
+// limitKeys lists the valid keys of the limits configuration.
+var limitKeys = []string{LimitListMetrics, LimitLabelValues, LimitLabelsOverview, LimitSeries, LimitQueryResultSeries, LimitMetricMetadata}
+
+// ToolLimitConfig overrides the built-in limits of a tool. Unset fields keep their
+// built-in value.
+type ToolLimitConfig struct {
+	// Default is the number of results returned when a call does not request a limit
+	// (0 = the maximum).
+	Default *int `toml:"default,omitempty"`
+	// Max is the largest limit a call may request (0 = no limit).
+	Max *int `toml:"max,omitempty"`
+}
+
+// ToolLimit is the effective limit on the number of results of a tool.
+type ToolLimit struct {
+	// Default is the limit of calls not requesting one (0 = no limit).
+	Default int
+	// Max is the largest limit a call may request (0 = no limit).
+	Max int
+}
+
+// Resolve returns the limit of a call requesting limit results (0 = not requested),
+// rejecting requests above the maximum rather than silently returning less.
+func (l ToolLimit) Resolve(limit int) (int, error) {
+	switch {
+	case limit < 0:
+		return 0, fmt.Errorf("limit must not be negative")
+	case limit == 0:
+		return l.Default, nil
+	case l.Max > 0 && limit > l.Max:
+		return 0, fmt.Errorf("limit %d exceeds the server-side maximum of %d", limit, l.Max)
+	}
+	return limit, nil
+}
+
+// apply returns l with the overrides of c, keeping the default within the maximum.
+func (l ToolLimit) apply(c ToolLimitConfig) ToolLimit {
+	if c.Max != nil {
+		l.Max = *c.Max
+	}
+	if c.Default != nil {
+		l.Default = *c.Default
+	}
+	if l.Max > 0 && (l.Default == 0 || l.Default > l.Max) {
+		l.Default = l.Max
+	}
+	return l
+}
+
+// validateLimits checks the keys and values of a limits configuration.
+func validateLimits(limits map[string]ToolLimitConfig) error {
+	for _, key := range slices.Sorted(maps.Keys(limits)) {
+		if !slices.Contains(limitKeys, key) {
+			return fmt.Errorf("invalid limits key %q (valid keys: %s)", key, strings.Join(limitKeys, ", "))
+		}
+		limit := limits[key]
+		if limit.Default != nil && *limit.Default < 0 {
+			return fmt.Errorf("invalid limits.%s.default: %d (must not be negative)", key, *limit.Default)
+		}
+		if limit.Max != nil && *limit.Max < 0 {
+			return fmt.Errorf("invalid limits.%s.max: %d (must not be negative)", key, *limit.Max)
+		}
+		if limit.Default != nil && limit.Max != nil && *limit.Max > 0 && *limit.Default > *limit.Max {
+			return fmt.Errorf("invalid limits.%s: default %d exceeds max %d", key, *limit.Default, *limit.Max)
+		}
+		if key == LimitQueryResultSeries && limit.Default != nil {
+			return fmt.Errorf("limits.%s.default is not supported, as queries take no limit; set max instead", key)
+		}
+	}
+	return nil
+}
+
+// ParseLimits parses limits given as a comma-separated list of <key>.<default|max>=<n>
+// entries, e.g. "get_series.max=500,list_metrics.default=200".
+func ParseLimits(s string) (map[string]ToolLimitConfig, error) {
+	limits := make(map[string]ToolLimitConfig)
+	for entry := range strings.SplitSeq(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		key, field, hasField := strings.Cut(name, ".")
+		if !ok || !hasField {
+			return nil, fmt.Errorf("invalid limit %q (expected <key>.default=<n> or <key>.max=<n>)", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %q: %q is not a number", entry, value)
+		}
+		limit := limits[key]
+		switch field {
+		case "default":
+			limit.Default = &n
+		case "max":
+			limit.Max = &n
+		default:
+			return nil, fmt.Errorf("invalid limit %q (expected <key>.default=<n> or <key>.max=<n>)", entry)
+		}
+		limits[key] = limit
+	}
+	if err := validateLimits(limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
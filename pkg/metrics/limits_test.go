@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToolLimitResolve(t *testing.T) {
+	limit := ToolLimit{Default: 50, Max: 100}
+	tests := []struct {
+		requested int
+		want      int
+		wantErr   string
+	}{
+		{requested: 0, want: 50},
+		{requested: 10, want: 10},
+		{requested: 100, want: 100},
+		{requested: 101, wantErr: "limit 101 exceeds the server-side maximum of 100"},
+		{requested: -1, wantErr: "must not be negative"},
+	}
+	for _, tt := range tests {
+		got, err := limit.Resolve(tt.requested)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%d) error = %v, want it to contain %q", tt.requested, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%d) = %d, %v, want %d", tt.requested, got, err, tt.want)
+		}
+	}
+
+	if got, _ := (ToolLimit{}).Resolve(1_000_000); got != 1_000_000 {
+		t.Errorf("Resolve without a maximum = %d, want the requested limit", got)
+	}
+}
+
+func TestGetLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		toml string
+		key  string
+		want ToolLimit
+	}{
+		{name: "built-in label values limit", key: LimitLabelValues, want: ToolLimit{Default: 1000, Max: 1000}},
+		{name: "max_label_values", toml: "max_label_values = 20", key: LimitLabelValues, want: ToolLimit{Default: 20, Max: 20}},
+		{name: "label values default", toml: "[limits.get_label_values]\ndefault = 100", key: LimitLabelValues, want: ToolLimit{Default: 100, Max: 1000}},
+		{name: "overview follows the label values maximum", toml: "max_label_values = 20", key: LimitLabelsOverview, want: ToolLimit{Default: 20, Max: 20}},
+		{name: "built-in overview limit", key: LimitLabelsOverview, want: ToolLimit{Default: 50, Max: 1000}},
+		{name: "unlimited by default", key: LimitSeries, want: ToolLimit{}},
+		{name: "max is the default", toml: "[limits.get_series]\nmax = 300", key: LimitSeries, want: ToolLimit{Default: 300, Max: 300}},
+		{name: "default without max", toml: "[limits.list_metrics]\ndefault = 200", key: LimitListMetrics, want: ToolLimit{Default: 200}},
+		{name: "max_result_series", toml: "max_result_series = 40", key: LimitQueryResultSeries, want: ToolLimit{Default: 40, Max: 40}},
+		{name: "query result series max", toml: "[limits.query_result_series]\nmax = 40", key: LimitQueryResultSeries, want: ToolLimit{Default: 40, Max: 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := parseConfig(t, tt.toml)
+			if got := cfg.GetLimit(tt.key); got != tt.want {
+				t.Errorf("GetLimit(%q) = %+v, want %+v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("get_series.max=500, list_metrics.default=200,get_series.default=100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := &Config{Limits: limits}
+	if got, want := cfg.GetLimit(LimitSeries), (ToolLimit{Default: 100, Max: 500}); got != want {
+		t.Errorf("get_series limit = %+v, want %+v", got, want)
+	}
+	if got, want := cfg.GetLimit(LimitListMetrics), (ToolLimit{Default: 200}); got != want {
+		t.Errorf("list_metrics limit = %+v, want %+v", got, want)
+	}
+
+	for _, invalid := range []string{"get_series=5", "get_series.min=5", "get_series.max=many", "get_alerts.max=5", "get_series.max=-5"} {
+		if _, err := ParseLimits(invalid); err == nil {
+			t.Errorf("ParseLimits(%q) expected an error", invalid)
+		}
+	}
+}
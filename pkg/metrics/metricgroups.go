@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// maxMetricGroupExamples is the number of example metric names returned per group.
+const maxMetricGroupExamples = 3
+
+// metricGroupPrefix returns the prefix grouping a metric below the given parent prefix:
+// the parent followed by the next name segment, up to and including the next '_' or ':'
+// separator. A metric without a further separator forms a group of its own.
+func metricGroupPrefix(metric, parent string) string {
+	rest := metric[len(parent):]
+	if i := strings.IndexAny(rest, "_:"); i >= 0 {
+		return parent + rest[:i+1]
+	}
+	return metric
+}
+
+// groupMetricsByPrefix clusters the metric names starting with parent by their next
+// name segment, e.g. "node_", "kube_" at the top level or "kube_pod_" below "kube_".
+// Groups are ordered by decreasing size.
+func groupMetricsByPrefix(metrics []string, parent string) []MetricGroup {
+	byPrefix := make(map[string][]string)
+	for _, metric := range metrics {
+		if !strings.HasPrefix(metric, parent) || metric == parent {
+			continue
+		}
+		prefix := metricGroupPrefix(metric, parent)
+		byPrefix[prefix] = append(byPrefix[prefix], metric)
+	}
+
+	groups := make([]MetricGroup, 0, len(byPrefix))
+	for prefix, names := range byPrefix {
+		slices.Sort(names)
+		groups = append(groups, MetricGroup{
+			Prefix:   prefix,
+			Count:    len(names),
+			Examples: names[:min(len(names), maxMetricGroupExamples)],
+		})
+	}
+	slices.SortFunc(groups, func(a, b MetricGroup) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Prefix, b.Prefix))
+	})
+	return groups
+}
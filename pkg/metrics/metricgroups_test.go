@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupMetricsByPrefix(t *testing.T) {
+	metrics := []string{
+		"kube_pod_status_phase",
+		"kube_pod_info",
+		"kube_deployment_status_replicas",
+		"node_cpu_seconds_total",
+		"node_memory_MemAvailable_bytes",
+		"node_load1",
+		"kube_node_info",
+		"kube_pod_container_status_restarts_total",
+		"namespace:container_cpu_usage:sum",
+		"up",
+	}
+
+	tests := []struct {
+		name   string
+		parent string
+		want   []MetricGroup
+	}{
+		{
+			name: "top level groups by first segment",
+			want: []MetricGroup{
+				{Prefix: "kube_", Count: 5, Examples: []string{"kube_deployment_status_replicas", "kube_node_info", "kube_pod_container_status_restarts_total"}},
+				{Prefix: "node_", Count: 3, Examples: []string{"node_cpu_seconds_total", "node_load1", "node_memory_MemAvailable_bytes"}},
+				{Prefix: "namespace:", Count: 1, Examples: []string{"namespace:container_cpu_usage:sum"}},
+				{Prefix: "up", Count: 1, Examples: []string{"up"}},
+			},
+		},
+		{
+			name:   "prefix groups by the next segment",
+			parent: "kube_",
+			want: []MetricGroup{
+				{Prefix: "kube_pod_", Count: 3, Examples: []string{"kube_pod_container_status_restarts_total", "kube_pod_info", "kube_pod_status_phase"}},
+				{Prefix: "kube_deployment_", Count: 1, Examples: []string{"kube_deployment_status_replicas"}},
+				{Prefix: "kube_node_", Count: 1, Examples: []string{"kube_node_info"}},
+			},
+		},
+		{
+			name:   "metric without further segment forms its own group",
+			parent: "node_",
+			want: []MetricGroup{
+				{Prefix: "node_cpu_", Count: 1, Examples: []string{"node_cpu_seconds_total"}},
+				{Prefix: "node_load1", Count: 1, Examples: []string{"node_load1"}},
+				{Prefix: "node_memory_", Count: 1, Examples: []string{"node_memory_MemAvailable_bytes"}},
+			},
+		},
+		{
+			name:   "unknown prefix",
+			parent: "apiserver_",
+			want:   []MetricGroup{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := groupMetricsByPrefix(metrics, tt.parent)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupMetricsByPrefix() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
+)
+
+// pageToken is the state needed to fetch the next page of a paginated range query. The
+// server keeps no state between calls, so the token pins everything the series of a page
+// depend on: the query, the time window and step it was first resolved to, and the
+// position in the result ordered by labels.
+type pageToken struct {
+	QueryHash string `json:"q"`
+	Start     int64  `json:"s"`
+	End       int64  `json:"e"`
+	Step      int64  `json:"st"`
+	Offset    int    `json:"o"`
+	PageSize  int    `json:"n"`
+	Total     int    `json:"t"`
+}
+
+// pageQueryHash identifies the series a paginated query returns: its canonical form, so
+// that reformatting the query or reordering its matchers and grouping labels between calls
+// does not invalidate a token, and the labels it is projected on.
+func pageQueryHash(query string, projectLabels []model.LabelName) string {
+	if canonical, err := prometheus.CanonicalizeQuery(query); err == nil {
+		query = canonical
+	}
+	labels := make([]string, len(projectLabels))
+	for i, name := range projectLabels {
+		labels[i] = string(name)
+	}
+	slices.Sort(labels)
+	sum := sha256.Sum256([]byte(query + "\x00" + strings.Join(labels, ",")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (t pageToken) encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(s string) (pageToken, error) {
+	var t pageToken
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &t)
+	}
+	if err != nil || t.Offset < 0 || t.PageSize <= 0 || t.Step <= 0 || t.End < t.Start {
+		return pageToken{}, fmt.Errorf("invalid page_token: pass the nextToken of a previous response unchanged")
+	}
+	return t, nil
+}
+
+// window returns the time window and step the token was issued for.
+func (t pageToken) window() (start, end time.Time, step time.Duration) {
+	return time.UnixMilli(t.Start), time.UnixMilli(t.End), time.Duration(t.Step) * time.Millisecond
+}
+
+// paginateMatrix returns the page of the series of a range query result starting at
+// offset, along with the token of the next page, empty on the last one. Series are
+// ordered by labels so that the same result is split into the same pages on every call.
+func paginateMatrix(matrix model.Matrix, token pageToken) (model.Matrix, *PageInfo, string) {
+	sort.Sort(matrix)
+	total := len(matrix)
+	from := min(token.Offset, total)
+	to := min(from+token.PageSize, total)
+	page := &PageInfo{TotalSeries: total, Offset: from, ReturnedSeries: to - from}
+
+	if to >= total {
+		return matrix[from:to], page, ""
+	}
+	next := token
+	next.Offset = to
+	next.Total = total
+	return matrix[from:to], page, next.encode()
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestPageQueryHash(t *testing.T) {
+	base := pageQueryHash(`sum by (pod) (rate(http_requests_total{job="api"}[5m]))`, nil)
+
+	if got := pageQueryHash(`sum  by(pod)(rate(http_requests_total{job='api'}[5m]))`, nil); got != base {
+		t.Errorf("reformatted query hashes to %s, want %s", got, base)
+	}
+	multi := pageQueryHash(`sum by (pod, namespace) (rate(http_requests_total{job="api",code="500"}[5m]))`, nil)
+	if got := pageQueryHash(`sum by (namespace, pod) (rate(http_requests_total{code="500",job="api"}[5m]))`, nil); got != multi {
+		t.Errorf("query with reordered matchers and grouping hashes to %s, want %s", got, multi)
+	}
+	if got := pageQueryHash(`sum by (pod) (rate(http_requests_total{job="web"}[5m]))`, nil); got == base {
+		t.Errorf("different query hashes to the same %s", got)
+	}
+	if got := pageQueryHash(`sum by (pod) (rate(http_requests_total{job="api"}[5m]))`, []model.LabelName{"pod"}); got == base {
+		t.Errorf("projected query hashes to the same %s", got)
+	}
+	a := pageQueryHash("up", []model.LabelName{"job", "pod"})
+	if b := pageQueryHash("up", []model.LabelName{"pod", "job"}); a != b {
+		t.Errorf("project_labels order changed the hash: %s, then %s", a, b)
+	}
+}
+
+func TestDecodePageToken(t *testing.T) {
+	token := pageToken{QueryHash: "abc", Start: 1000, End: 61000, Step: 15000, Offset: 20, PageSize: 10, Total: 42}
+	decoded, err := decodePageToken(token.encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded != token {
+		t.Errorf("decoded token = %+v, want %+v", decoded, token)
+	}
+
+	for _, s := range []string{
+		"not a token",
+		pageToken{QueryHash: "abc", Start: 1000, End: 61000, Step: 15000, PageSize: 0}.encode(),
+		pageToken{QueryHash: "abc", Start: 1000, End: 61000, Step: 15000, Offset: -1, PageSize: 10}.encode(),
+		pageToken{QueryHash: "abc", Start: 61000, End: 1000, Step: 15000, PageSize: 10}.encode(),
+	} {
+		if _, err := decodePageToken(s); err == nil {
+			t.Errorf("decodePageToken(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestPaginateMatrix(t *testing.T) {
+	newMatrix := func() model.Matrix {
+		var matrix model.Matrix
+		for _, i := range []int{3, 0, 4, 1, 2} {
+			matrix = append(matrix, &model.SampleStream{Metric: model.Metric{"pod": model.LabelValue(fmt.Sprintf("pod-%d", i))}})
+		}
+		return matrix
+	}
+
+	var pods []string
+	token := pageToken{QueryHash: "abc", Step: 1000, PageSize: 2}
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatal("expected the result to fit in 3 pages")
+		}
+		page, info, next := paginateMatrix(newMatrix(), token)
+		if info.TotalSeries != 5 || info.Offset != token.Offset || info.ReturnedSeries != len(page) {
+			t.Errorf("page info = %+v, want 5 series in total from offset %d", info, token.Offset)
+		}
+		for _, s := range page {
+			pods = append(pods, string(s.Metric["pod"]))
+		}
+		if next == "" {
+			break
+		}
+		var err error
+		if token, err = decodePageToken(next); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token.Total != 5 {
+			t.Errorf("next token total = %d, want 5", token.Total)
+		}
+	}
+	if want := []string{"pod-0", "pod-1", "pod-2", "pod-3", "pod-4"}; !slices.Equal(pods, want) {
+		t.Errorf("pages returned %v, want %v", pods, want)
+	}
+
+	t.Run("offset beyond the result", func(t *testing.T) {
+		page, info, next := paginateMatrix(newMatrix(), pageToken{Offset: 10, PageSize: 2})
+		if len(page) != 0 || next != "" || info.Offset != 5 {
+			t.Errorf("got %d series from offset %d with next token %q, want an empty last page", len(page), info.Offset, next)
+		}
+	})
+}
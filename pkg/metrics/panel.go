@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	// persesPrometheusQueryKind is the Perses plugin kind of a PromQL query.
+	persesPrometheusQueryKind = "PrometheusTimeSeriesQuery"
+	// persesTimeSeriesQueryKind is the Perses kind of the queries of a panel.
+	persesTimeSeriesQueryKind = "TimeSeriesQuery"
+)
+
+// persesChartKinds maps the chart types of query_to_panel to Perses panel plugin kinds.
+var persesChartKinds = map[string]string{
+	"timeseries": "TimeSeriesChart",
+	"stat":       "StatChart",
+	"gauge":      "GaugeChart",
+	"bar":        "BarChart",
+	"pie":        "PieChart",
+	"table":      "Table",
+}
+
+// persesCalculationCharts are the panel plugins that reduce each series to a single
+// value and require the calculation to do it with.
+var persesCalculationCharts = []string{"StatChart", "GaugeChart", "BarChart", "PieChart"}
+
+// PersesPanel is a Perses dashboard panel.
+type PersesPanel struct {
+	Kind string          `json:"kind" jsonschema:"Always 'Panel'"`
+	Spec PersesPanelSpec `json:"spec" jsonschema:"Panel specification"`
+}
+
+// PersesPanelSpec is the specification of a Perses panel.
+type PersesPanelSpec struct {
+	Display PersesPanelDisplay `json:"display" jsonschema:"Title and description of the panel"`
+	Plugin  PersesPlugin       `json:"plugin" jsonschema:"Chart plugin rendering the panel"`
+	Queries []PersesQuery      `json:"queries" jsonschema:"Queries providing the data of the panel"`
+}
+
+// PersesPanelDisplay is the title and description of a Perses panel.
+type PersesPanelDisplay struct {
+	Name        string `json:"name" jsonschema:"Panel title"`
+	Description string `json:"description,omitempty" jsonschema:"Panel description"`
+}
+
+// PersesPlugin is a Perses plugin reference with its plugin-specific settings.
+type PersesPlugin struct {
+	Kind string         `json:"kind" jsonschema:"Plugin kind"`
+	Spec map[string]any `json:"spec" jsonschema:"Plugin settings"`
+}
+
+// PersesQuery is a query of a Perses panel.
+type PersesQuery struct {
+	Kind string          `json:"kind" jsonschema:"Query kind"`
+	Spec PersesQuerySpec `json:"spec" jsonschema:"Query specification"`
+}
+
+// PersesQuerySpec is the specification of a Perses panel query.
+type PersesQuerySpec struct {
+	Plugin PersesPlugin `json:"plugin" jsonschema:"Query plugin, with the PromQL query in spec.query"`
+}
+
+// buildPersesPanel returns a Perses panel charting query with the given chart type.
+func buildPersesPanel(query, title, description, chartType string) (PersesPanel, error) {
+	chartType = strings.ToLower(cmp.Or(chartType, "timeseries"))
+	kind, ok := persesChartKinds[chartType]
+	if !ok {
+		return PersesPanel{}, fmt.Errorf("unsupported chart_type %q, must be one of: %s",
+			chartType, strings.Join(slices.Sorted(maps.Keys(persesChartKinds)), ", "))
+	}
+
+	chartSpec := map[string]any{}
+	if slices.Contains(persesCalculationCharts, kind) {
+		chartSpec["calculation"] = "last-number"
+	}
+	return PersesPanel{
+		Kind: "Panel",
+		Spec: PersesPanelSpec{
+			Display: PersesPanelDisplay{Name: title, Description: description},
+			Plugin:  PersesPlugin{Kind: kind, Spec: chartSpec},
+			Queries: []PersesQuery{{
+				Kind: persesTimeSeriesQueryKind,
+				Spec: PersesQuerySpec{Plugin: PersesPlugin{
+					Kind: persesPrometheusQueryKind,
+					Spec: map[string]any{"query": query},
+				}},
+			}},
+		},
+	}, nil
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildPersesPanel(t *testing.T) {
+	query := `sum by (namespace) (rate(container_cpu_usage_seconds_total{namespace="default"}[5m]))`
+
+	tests := []struct {
+		chartType       string
+		wantKind        string
+		wantCalculation bool
+	}{
+		{chartType: "", wantKind: "TimeSeriesChart"},
+		{chartType: "timeseries", wantKind: "TimeSeriesChart"},
+		{chartType: "Stat", wantKind: "StatChart", wantCalculation: true},
+		{chartType: "gauge", wantKind: "GaugeChart", wantCalculation: true},
+		{chartType: "table", wantKind: "Table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantKind+"/"+tt.chartType, func(t *testing.T) {
+			panel, err := buildPersesPanel(query, "CPU usage", "", tt.chartType)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if panel.Kind != "Panel" || panel.Spec.Display.Name != "CPU usage" {
+				t.Errorf("panel = %+v, want a panel titled 'CPU usage'", panel)
+			}
+			if panel.Spec.Plugin.Kind != tt.wantKind {
+				t.Errorf("plugin kind = %q, want %q", panel.Spec.Plugin.Kind, tt.wantKind)
+			}
+			if _, ok := panel.Spec.Plugin.Spec["calculation"]; ok != tt.wantCalculation {
+				t.Errorf("plugin spec = %v, want calculation: %v", panel.Spec.Plugin.Spec, tt.wantCalculation)
+			}
+
+			// The query must be read back from the panel as it is pasted into a dashboard.
+			if queries := panelQueries(t, panel); !slices.Equal(queries, []string{query}) {
+				t.Errorf("extracted queries = %q, want %q", queries, query)
+			}
+		})
+	}
+
+	if _, err := buildPersesPanel(query, "CPU usage", "", "heatmap"); err == nil || !strings.Contains(err.Error(), "unsupported chart_type") {
+		t.Errorf("expected unsupported chart_type error, got %v", err)
+	}
+}
+
+// panelQueries returns the PromQL queries of panel after a round trip through JSON.
+func panelQueries(t *testing.T, panel PersesPanel) []string {
+	t.Helper()
+	data, err := json.Marshal(panel)
+	if err != nil {
+		t.Fatalf("failed to encode panel: %v", err)
+	}
+	var decoded PersesPanel
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode panel: %v", err)
+	}
+	var queries []string
+	for _, q := range decoded.Spec.Queries {
+		if q.Spec.Plugin.Kind != persesPrometheusQueryKind {
+			continue
+		}
+		if query, ok := q.Spec.Plugin.Spec["query"].(string); ok {
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+var labelNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// metricNameRe matches valid metric names.
+var metricNameRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// parseProjectLabels parses the comma-separated label names of project_labels.
+func parseProjectLabels(value string) ([]model.LabelName, error) {
+	var labels []model.LabelName
+	for name := range strings.SplitSeq(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !labelNameRe.MatchString(name) {
+			return nil, fmt.Errorf("invalid label name %q in project_labels", name)
+		}
+		labels = append(labels, model.LabelName(name))
+	}
+	return labels, nil
+}
+
+// projectMetric returns the labels of metric that are listed in labels.
+func projectMetric(metric model.Metric, labels []model.LabelName) model.Metric {
+	projected := make(model.Metric, len(labels))
+	for _, name := range labels {
+		if value, ok := metric[name]; ok {
+			projected[name] = value
+		}
+	}
+	return projected
+}
+
+// projectVector keeps only the given labels of each sample. Samples whose label sets
+// become identical are merged by adding their values; merged maps the fingerprint of
+// each resulting sample to the number of samples merged into it, when more than one.
+func projectVector(vector model.Vector, labels []model.LabelName) (projected model.Vector, merged map[model.Fingerprint]int) {
+	index := make(map[model.Fingerprint]int, len(vector))
+	merged = make(map[model.Fingerprint]int)
+	for _, sample := range vector {
+		metric := projectMetric(sample.Metric, labels)
+		fp := metric.Fingerprint()
+		if i, ok := index[fp]; ok {
+			projected[i].Value += sample.Value
+			projected[i].Timestamp = max(projected[i].Timestamp, sample.Timestamp)
+			merged[fp] = max(merged[fp], 1) + 1
+			continue
+		}
+		index[fp] = len(projected)
+		projected = append(projected, &model.Sample{Metric: metric, Value: sample.Value, Timestamp: sample.Timestamp})
+	}
+	return projected, merged
+}
+
+// projectMatrix keeps only the given labels of each series. Series whose label sets
+// become identical are merged by adding their values at each timestamp; merged maps the
+// fingerprint of each resulting series to the number of series merged into it, when
+// more than one.
+func projectMatrix(matrix model.Matrix, labels []model.LabelName) (projected model.Matrix, merged map[model.Fingerprint]int) {
+	index := make(map[model.Fingerprint]int, len(matrix))
+	merged = make(map[model.Fingerprint]int)
+	for _, series := range matrix {
+		metric := projectMetric(series.Metric, labels)
+		fp := metric.Fingerprint()
+		if i, ok := index[fp]; ok {
+			projected[i].Values = addSamples(projected[i].Values, series.Values)
+			merged[fp] = max(merged[fp], 1) + 1
+			continue
+		}
+		index[fp] = len(projected)
+		projected = append(projected, &model.SampleStream{Metric: metric, Values: slices.Clone(series.Values)})
+	}
+	return projected, merged
+}
+
+// addSamples merges two timestamp-ordered sample lists, adding the values of samples
+// with the same timestamp.
+func addSamples(a, b []model.SamplePair) []model.SamplePair {
+	sum := make([]model.SamplePair, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i].Timestamp < b[j].Timestamp):
+			sum = append(sum, a[i])
+			i++
+		case i == len(a) || b[j].Timestamp < a[i].Timestamp:
+			sum = append(sum, b[j])
+			j++
+		default:
+			sum = append(sum, model.SamplePair{Timestamp: a[i].Timestamp, Value: a[i].Value + b[j].Value})
+			i++
+			j++
+		}
+	}
+	return sum
+}
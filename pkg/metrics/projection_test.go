@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestParseProjectLabels(t *testing.T) {
+	got, err := parseProjectLabels(" namespace, pod ,,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []model.LabelName{"namespace", "pod"}; !slices.Equal(got, want) {
+		t.Errorf("parseProjectLabels() = %v, want %v", got, want)
+	}
+
+	if _, err := parseProjectLabels("namespace,pod-name"); err == nil {
+		t.Error("expected error for an invalid label name, got nil")
+	}
+}
+
+func TestProjectVector(t *testing.T) {
+	vector := model.Vector{
+		{Metric: model.Metric{"namespace": "a", "pod": "a-1"}, Value: 1, Timestamp: 1000},
+		{Metric: model.Metric{"namespace": "a", "pod": "a-2"}, Value: 2, Timestamp: 2000},
+		{Metric: model.Metric{"namespace": "b", "pod": "b-1"}, Value: 4, Timestamp: 1000},
+	}
+
+	projected, merged := projectVector(vector, []model.LabelName{"namespace"})
+	if len(projected) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(projected))
+	}
+	a, b := projected[0], projected[1]
+	if a.Metric.String() != `{namespace="a"}` || a.Value != 3 || a.Timestamp != 2000 {
+		t.Errorf("first sample = %v, want {namespace=\"a\"} => 3 @ 2000", a)
+	}
+	if b.Metric.String() != `{namespace="b"}` || b.Value != 4 {
+		t.Errorf("second sample = %v, want {namespace=\"b\"} => 4", b)
+	}
+	if got := merged[a.Metric.Fingerprint()]; got != 2 {
+		t.Errorf("merged count of namespace a = %d, want 2", got)
+	}
+	if got := merged[b.Metric.Fingerprint()]; got != 0 {
+		t.Errorf("merged count of namespace b = %d, want 0", got)
+	}
+}
+
+func TestProjectMatrix(t *testing.T) {
+	matrix := model.Matrix{
+		{Metric: model.Metric{"job": "api", "instance": "1"}, Values: []model.SamplePair{{Timestamp: 0, Value: 1}, {Timestamp: 60000, Value: 1}}},
+		{Metric: model.Metric{"job": "api", "instance": "2"}, Values: []model.SamplePair{{Timestamp: 60000, Value: 2}, {Timestamp: 120000, Value: 2}}},
+	}
+
+	projected, merged := projectMatrix(matrix, []model.LabelName{"job"})
+	if len(projected) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(projected))
+	}
+	want := []model.SamplePair{{Timestamp: 0, Value: 1}, {Timestamp: 60000, Value: 3}, {Timestamp: 120000, Value: 2}}
+	if !slices.Equal(projected[0].Values, want) {
+		t.Errorf("values = %v, want %v", projected[0].Values, want)
+	}
+	if got := merged[projected[0].Metric.Fingerprint()]; got != 2 {
+		t.Errorf("merged count = %d, want 2", got)
+	}
+	if len(matrix[0].Values) != 2 {
+		t.Errorf("input series was modified: %v", matrix[0].Values)
+	}
+}
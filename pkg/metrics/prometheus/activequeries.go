@@ -0,0 +1,117 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// Self-monitoring metrics of the Prometheus query engine.
+const (
+	engineQueriesMetric       = "prometheus_engine_queries"
+	engineMaxConcurrentMetric = "prometheus_engine_queries_concurrent_max"
+)
+
+// ActiveQueries describes the load on the query engines behind the backend. Prometheus
+// does not list running queries over its API; it only reports how many are executing or
+// waiting, and can log completed queries to a file on the server.
+type ActiveQueries struct {
+	// Engines holds one entry per Prometheus instance reporting engine metrics,
+	// which requires the instances to be scraped, by themselves or by the backend.
+	Engines []QueryEngine
+	// QueryLogFile is the file completed queries are logged to, when the backend
+	// serves its configuration and query logging is enabled.
+	QueryLogFile string
+}
+
+// QueryEngine is the query load of a single Prometheus instance.
+type QueryEngine struct {
+	// Labels identify the instance, e.g. its job, instance or pod.
+	Labels map[string]string
+	// Queries is the number of queries currently executing or waiting.
+	Queries float64
+	// MaxConcurrency is the maximum number of concurrent queries, or 0 when unknown.
+	MaxConcurrency float64
+}
+
+// GetActiveQueries reports the number of queries running on the query engines behind the
+// backend and, when the backend serves its configuration, the file queries are logged to.
+// The engine metrics are queried directly, without guardrails, since they are neither
+// user input nor expensive.
+func (p *RealLoader) GetActiveQueries(ctx context.Context) (*ActiveQueries, error) {
+	apiStart := time.Now()
+	now := time.Now()
+
+	queries, err := p.engineMetric(ctx, engineQueriesMetric, now)
+	if err != nil {
+		err = classifyBackendError(err)
+		slog.Error("Backend call failed", "backend", p.backend, "operation", "active_queries",
+			"duration_ms", time.Since(apiStart).Milliseconds(), "error_code", errorCode(err), "error", err)
+		return nil, fmt.Errorf("error fetching active queries: %w", err)
+	}
+	// The limit is informative only; without it the number of queries is still reported.
+	maxConcurrency, err := p.engineMetric(ctx, engineMaxConcurrentMetric, now)
+	if err != nil {
+		slog.Debug("Query engine concurrency limit unavailable", "backend", p.backend, "error", err)
+	}
+
+	limits := make(map[string]float64, len(maxConcurrency))
+	for _, sample := range maxConcurrency {
+		limits[engineKey(sample.Metric)] = float64(sample.Value)
+	}
+	slices.SortFunc(queries, func(a, b *model.Sample) int {
+		return strings.Compare(engineKey(a.Metric), engineKey(b.Metric))
+	})
+
+	result := &ActiveQueries{Engines: make([]QueryEngine, 0, len(queries))}
+	for _, sample := range queries {
+		engine := QueryEngine{
+			Labels:         make(map[string]string, len(sample.Metric)),
+			Queries:        float64(sample.Value),
+			MaxConcurrency: limits[engineKey(sample.Metric)],
+		}
+		for name, value := range sample.Metric {
+			if name != model.MetricNameLabel {
+				engine.Labels[string(name)] = string(value)
+			}
+		}
+		result.Engines = append(result.Engines, engine)
+	}
+
+	global, err := p.globalConfig(ctx)
+	if err != nil {
+		slog.Debug("Backend configuration unavailable", "backend", p.backend, "error", err)
+	} else {
+		result.QueryLogFile = global.QueryLogFile
+	}
+
+	slog.Debug("Backend call completed", "backend", p.backend, "operation", "active_queries",
+		"duration_ms", time.Since(apiStart).Milliseconds(), "engine_count", len(result.Engines))
+	return result, nil
+}
+
+// engineMetric returns the current samples of a query engine metric.
+func (p *RealLoader) engineMetric(ctx context.Context, metric string, ts time.Time) (model.Vector, error) {
+	value, _, err := p.client.Query(ctx, metric, ts)
+	if err != nil {
+		return nil, err
+	}
+	vector, ok := value.(model.Vector)
+	if !ok {
+		return nil, errors.New("unexpected result type " + value.Type().String())
+	}
+	return vector, nil
+}
+
+// engineKey identifies the instance a query engine metric was collected from.
+func engineKey(metric model.Metric) string {
+	labels := metric.Clone()
+	delete(labels, model.MetricNameLabel)
+	return labels.String()
+}
@@ -0,0 +1,92 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// engineMetricsAPI serves query engine metrics by name and, optionally, a configuration.
+type engineMetricsAPI struct {
+	mockPrometheusAPI
+	vectors   map[string]model.Vector
+	configErr error
+}
+
+func (m *engineMetricsAPI) Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	vector, ok := m.vectors[query]
+	if !ok {
+		return nil, nil, &v1.Error{Type: v1.ErrBadData, Msg: "unexpected query " + query}
+	}
+	return vector, nil, nil
+}
+
+func (m *engineMetricsAPI) Config(ctx context.Context) (v1.ConfigResult, error) {
+	if m.configErr != nil {
+		return v1.ConfigResult{}, m.configErr
+	}
+	return v1.ConfigResult{YAML: "global:\n  query_log_file: /prometheus/query.log\n"}, nil
+}
+
+func TestGetActiveQueries(t *testing.T) {
+	engine := func(metric, pod string, value float64) *model.Sample {
+		return &model.Sample{
+			Metric: model.Metric{model.MetricNameLabel: model.LabelValue(metric), "pod": model.LabelValue(pod)},
+			Value:  model.SampleValue(value),
+		}
+	}
+
+	t.Run("engines are matched with their concurrency limit", func(t *testing.T) {
+		loader := &RealLoader{client: &engineMetricsAPI{vectors: map[string]model.Vector{
+			engineQueriesMetric:       {engine(engineQueriesMetric, "prometheus-1", 4), engine(engineQueriesMetric, "prometheus-0", 2)},
+			engineMaxConcurrentMetric: {engine(engineMaxConcurrentMetric, "prometheus-0", 20)},
+		}}}
+
+		active, err := loader.GetActiveQueries(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []QueryEngine{
+			{Labels: map[string]string{"pod": "prometheus-0"}, Queries: 2, MaxConcurrency: 20},
+			{Labels: map[string]string{"pod": "prometheus-1"}, Queries: 4},
+		}
+		if len(active.Engines) != len(want) {
+			t.Fatalf("engines = %+v, want %+v", active.Engines, want)
+		}
+		for i, got := range active.Engines {
+			if !maps.Equal(got.Labels, want[i].Labels) || got.Queries != want[i].Queries || got.MaxConcurrency != want[i].MaxConcurrency {
+				t.Errorf("engine %d = %+v, want %+v", i, got, want[i])
+			}
+		}
+		if active.QueryLogFile != "/prometheus/query.log" {
+			t.Errorf("queryLogFile = %q, want /prometheus/query.log", active.QueryLogFile)
+		}
+	})
+
+	t.Run("missing limit and configuration are tolerated", func(t *testing.T) {
+		loader := &RealLoader{client: &engineMetricsAPI{
+			vectors:   map[string]model.Vector{engineQueriesMetric: {engine(engineQueriesMetric, "prometheus-0", 1)}},
+			configErr: errors.New("not found"),
+		}}
+
+		active, err := loader.GetActiveQueries(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(active.Engines) != 1 || active.Engines[0].MaxConcurrency != 0 || active.QueryLogFile != "" {
+			t.Errorf("unexpected result: %+v", active)
+		}
+	})
+
+	t.Run("failing engine query is an error", func(t *testing.T) {
+		loader := &RealLoader{client: &engineMetricsAPI{}}
+		if _, err := loader.GetActiveQueries(context.Background()); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
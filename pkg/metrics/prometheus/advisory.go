@@ -0,0 +1,36 @@
+package prometheus
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// AggregationAdvisory returns a warning for a query whose outermost expression aggregates
+// all series into one without by or without, e.g. sum(up). Such results carry neither the
+// metric name nor any identifying label, which is easily misread. It returns "" for other
+// queries, including those that fail to parse.
+func AggregationAdvisory(query string) string {
+	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
+	if err != nil {
+		return ""
+	}
+	for {
+		paren, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.Expr
+	}
+
+	agg, ok := expr.(*parser.AggregateExpr)
+	if !ok || agg.Without || len(agg.Grouping) > 0 {
+		return ""
+	}
+	switch agg.Op {
+	case parser.TOPK, parser.BOTTOMK, parser.LIMITK, parser.LIMIT_RATIO:
+		// These select series and keep their labels.
+		return ""
+	}
+	return fmt.Sprintf("%s() without a by or without clause aggregates all series into one, so the result has no metric name or identifying labels; add by (<label>) to keep the labels the result should be broken down by", agg.Op)
+}
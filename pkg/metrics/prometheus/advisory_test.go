@@ -0,0 +1,26 @@
+package prometheus
+
+import "testing"
+
+func TestAggregationAdvisory(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "sum(up)", want: true},
+		{query: `(avg(rate(http_requests_total{job="api"}[5m])))`, want: true},
+		{query: `count_values("version", build_info)`, want: true},
+		{query: "sum by (job) (up)", want: false},
+		{query: "sum without (instance) (up)", want: false},
+		{query: "topk(5, up)", want: false},
+		{query: "up", want: false},
+		{query: "sum(up) / count(up)", want: false},
+		{query: "sum(", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := AggregationAdvisory(tt.query); (got != "") != tt.want {
+			t.Errorf("AggregationAdvisory(%q) = %q, want advisory: %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package prometheus
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// CanonicalizeQuery returns a normalized form of a PromQL query, in which formatting and the
+// order of label matchers and grouping labels do not matter. Semantically equivalent queries
+// that differ only in these respects have the same canonical form.
+func CanonicalizeQuery(query string) (string, error) {
+	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.VectorSelector:
+			slices.SortFunc(n.LabelMatchers, compareMatchers)
+		case *parser.AggregateExpr:
+			slices.Sort(n.Grouping)
+		case *parser.BinaryExpr:
+			if n.VectorMatching != nil {
+				slices.Sort(n.VectorMatching.MatchingLabels)
+				slices.Sort(n.VectorMatching.Include)
+			}
+		}
+		return nil
+	})
+
+	return expr.String(), nil
+}
+
+func compareMatchers(a, b *labels.Matcher) int {
+	return cmp.Or(
+		cmp.Compare(a.Name, b.Name),
+		cmp.Compare(a.Type, b.Type),
+		cmp.Compare(a.Value, b.Value),
+	)
+}
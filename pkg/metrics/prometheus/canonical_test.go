@@ -0,0 +1,79 @@
+package prometheus
+
+import "testing"
+
+func TestCanonicalizeQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       string
+		wantEquals bool
+	}{
+		{
+			name:       "whitespace is ignored",
+			a:          `sum by (job) (rate(http_requests_total{job="api"}[5m]))`,
+			b:          "sum  by(job)(\n  rate( http_requests_total{ job = \"api\" }[5m] )\n)",
+			wantEquals: true,
+		},
+		{
+			name:       "label matcher order is ignored",
+			a:          `up{job="api", namespace="default", pod=~"api-.*"}`,
+			b:          `up{pod=~"api-.*", namespace="default", job="api"}`,
+			wantEquals: true,
+		},
+		{
+			name:       "metric name as a matcher",
+			a:          `{__name__="up", job="api"}`,
+			b:          `{job="api", __name__="up"}`,
+			wantEquals: true,
+		},
+		{
+			name:       "grouping label order is ignored",
+			a:          `sum by (namespace, pod) (up)`,
+			b:          `sum(up) by (pod, namespace)`,
+			wantEquals: true,
+		},
+		{
+			name:       "vector matching label order is ignored",
+			a:          `a * on (job, instance) group_left (team, owner) b`,
+			b:          `a * on (instance, job) group_left (owner, team) b`,
+			wantEquals: true,
+		},
+		{
+			name: "different matcher values differ",
+			a:    `up{job="api"}`,
+			b:    `up{job="db"}`,
+		},
+		{
+			name: "different matcher types differ",
+			a:    `up{job="api"}`,
+			b:    `up{job=~"api"}`,
+		},
+		{
+			name: "operand order is preserved",
+			a:    `a - b`,
+			b:    `b - a`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := CanonicalizeQuery(tt.a)
+			if err != nil {
+				t.Fatalf("CanonicalizeQuery(%q) error: %v", tt.a, err)
+			}
+			b, err := CanonicalizeQuery(tt.b)
+			if err != nil {
+				t.Fatalf("CanonicalizeQuery(%q) error: %v", tt.b, err)
+			}
+			if (a == b) != tt.wantEquals {
+				t.Errorf("canonical forms %q and %q: equal = %v, want %v", a, b, a == b, tt.wantEquals)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeQuery_InvalidQuery(t *testing.T) {
+	if _, err := CanonicalizeQuery(`sum(up`); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+}
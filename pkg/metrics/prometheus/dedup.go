@@ -0,0 +1,136 @@
+package prometheus
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/singleflight"
+)
+
+// inflightQueries shares identical queries in flight. Loaders are created per tool call,
+// so the group is shared by all of them.
+var inflightQueries singleflight.Group
+
+// sharedCall is the context the shared query for a key runs under. It is canceled once no
+// caller waits for the result anymore, e.g. after all clients disconnected.
+type sharedCall struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+}
+
+var (
+	sharedCallsMu sync.Mutex
+	sharedCalls   = map[string]*sharedCall{}
+)
+
+// joinSharedCall registers a caller waiting for the shared query for key.
+func joinSharedCall(ctx context.Context, key string) *sharedCall {
+	sharedCallsMu.Lock()
+	defer sharedCallsMu.Unlock()
+	call, ok := sharedCalls[key]
+	if !ok {
+		sharedCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &sharedCall{ctx: sharedCtx, cancel: cancel}
+		sharedCalls[key] = call
+	}
+	call.waiters++
+	return call
+}
+
+// leaveSharedCall unregisters a caller of the shared query for key. When it was the last
+// one, the query is canceled and forgotten, so that later callers start a new one instead
+// of receiving the cancellation.
+func leaveSharedCall(key string, call *sharedCall) {
+	sharedCallsMu.Lock()
+	defer sharedCallsMu.Unlock()
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	delete(sharedCalls, key)
+	inflightQueries.Forget(key)
+}
+
+// sharedQueryWaiting, when set by tests, is called once a caller waits for the shared
+// query for key, so that tests know when all callers share it.
+var sharedQueryWaiting func(key string)
+
+type noDedupKey struct{}
+
+// queryResult is the outcome of a backend query shared between deduplicated callers.
+type queryResult struct {
+	value    model.Value
+	warnings v1.Warnings
+}
+
+// WithSharedScope lets concurrent identical queries share a single backend call, and
+// backend metadata be cached between calls. Results are only shared between loaders for
+// the same backend and credential scope, so that a caller never receives data it could
+// not query itself.
+func (p *RealLoader) WithSharedScope(scope string) *RealLoader {
+	p.shared = true
+	p.scope = scope
+	return p
+}
+
+// ContextWithoutDeduplication returns a context under which queries are always sent by the
+// caller itself, e.g. when the outgoing requests are inspected.
+func ContextWithoutDeduplication(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noDedupKey{}, true)
+}
+
+// dedupKey identifies a query for deduplication. Times are compared at second precision,
+// so that requests relative to "now" issued at the same moment are shared.
+func (p *RealLoader) dedupKey(operation, query string, start, end time.Time, step time.Duration) string {
+	canonical, err := CanonicalizeQuery(query)
+	if err != nil {
+		canonical = query
+	}
+	return strings.Join([]string{
+		p.address,
+		p.scope,
+		operation,
+		canonical,
+		strconv.FormatInt(start.Unix(), 10),
+		strconv.FormatInt(end.Unix(), 10),
+		step.String(),
+	}, "\x00")
+}
+
+// sharedQuery runs query, or waits for an identical query already in flight. The shared
+// call is detached from the cancellation of the caller that started it, so that it does
+// not fail the other callers; each caller still stops waiting when its own context is done,
+// and the call is canceled when no caller is left waiting.
+func (p *RealLoader) sharedQuery(ctx context.Context, key string, query func(context.Context) (model.Value, v1.Warnings, error)) (model.Value, v1.Warnings, error) {
+	if !p.shared || ctx.Value(noDedupKey{}) != nil {
+		return query(ctx)
+	}
+
+	call := joinSharedCall(ctx, key)
+	defer leaveSharedCall(key, call)
+
+	ch := inflightQueries.DoChan(key, func() (any, error) {
+		sharedCtx, cancel := context.WithTimeout(call.ctx, DefaultQueryTimeout)
+		defer cancel()
+		value, warnings, err := query(sharedCtx)
+		return queryResult{value: value, warnings: warnings}, err
+	})
+	if sharedQueryWaiting != nil {
+		sharedQueryWaiting(key)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case res := <-ch:
+		result, _ := res.Val.(queryResult)
+		return result.value, result.warnings, res.Err
+	}
+}
@@ -0,0 +1,224 @@
+package prometheus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// blockingQueryAPI counts range queries and holds them until released.
+type blockingQueryAPI struct {
+	mockPrometheusAPI
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingQueryAPI) QueryRange(ctx context.Context, query string, r v1.Range, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	m.calls.Add(1)
+	m.started <- struct{}{}
+	<-m.release
+	return model.Matrix{{Metric: model.Metric{"job": "api"}}}, nil, nil
+}
+
+// waitForSharedQueries returns a channel receiving the key of each shared query a caller
+// waits for, with room for n callers.
+func waitForSharedQueries(t *testing.T, n int) <-chan string {
+	t.Helper()
+	waiting := make(chan string, n)
+	sharedQueryWaiting = func(key string) { waiting <- key }
+	t.Cleanup(func() { sharedQueryWaiting = nil })
+	return waiting
+}
+
+func TestExecuteRangeQuery_DeduplicatesInFlightQueries(t *testing.T) {
+	const callers = 10
+	start := time.Unix(1700000000, 0)
+	end := start.Add(time.Hour)
+
+	run := func(t *testing.T, loaders []*RealLoader, queries []string) int32 {
+		t.Helper()
+		api := &blockingQueryAPI{
+			mockPrometheusAPI: mockPrometheusAPI{availableMetrics: []string{"up"}},
+			started:           make(chan struct{}, len(loaders)),
+			release:           make(chan struct{}),
+		}
+		waiting := waitForSharedQueries(t, len(loaders))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, len(loaders))
+		for i, loader := range loaders {
+			loader.client = api
+			wg.Go(func() {
+				result, err := loader.ExecuteRangeQuery(context.Background(), queries[i], start, end, time.Minute)
+				if err == nil && len(result["result"].(model.Matrix)) != 1 {
+					t.Errorf("caller %d got an unexpected result: %v", i, result["result"])
+				}
+				errs <- err
+			})
+		}
+
+		// Release the backend call only once every caller waits for it.
+		for _, loader := range loaders {
+			if loader.shared {
+				<-waiting
+			} else {
+				<-api.started
+			}
+		}
+		close(api.release)
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return api.calls.Load()
+	}
+
+	t.Run("identical queries share one backend call", func(t *testing.T) {
+		loaders := make([]*RealLoader, callers)
+		queries := make([]string, callers)
+		for i := range loaders {
+			loaders[i] = (&RealLoader{address: "http://prometheus:9090"}).WithSharedScope("scope")
+			queries[i] = `sum by (job) (up{job="api"})`
+		}
+		// Formatting differences do not prevent sharing.
+		queries[1] = `sum   by(job)(up{job="api"})`
+		queries[2] = `sum(up{job="api"}) by (job)`
+
+		if calls := run(t, loaders, queries); calls != 1 {
+			t.Errorf("backend called %d times, want 1", calls)
+		}
+	})
+
+	t.Run("queries in different scopes are not shared", func(t *testing.T) {
+		loaders := []*RealLoader{
+			(&RealLoader{address: "http://prometheus:9090"}).WithSharedScope("user-a"),
+			(&RealLoader{address: "http://prometheus:9090"}).WithSharedScope("user-b"),
+		}
+		queries := []string{"up", "up"}
+
+		if calls := run(t, loaders, queries); calls != 2 {
+			t.Errorf("backend called %d times, want 2", calls)
+		}
+	})
+
+	t.Run("deduplication is disabled by default", func(t *testing.T) {
+		loaders := []*RealLoader{{}, {}}
+		queries := []string{"up", "up"}
+
+		if calls := run(t, loaders, queries); calls != 2 {
+			t.Errorf("backend called %d times, want 2", calls)
+		}
+	})
+}
+
+// cancelableQueryAPI holds range queries until released or canceled and reports the
+// canceled ones.
+type cancelableQueryAPI struct {
+	mockPrometheusAPI
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func (m *cancelableQueryAPI) QueryRange(ctx context.Context, query string, r v1.Range, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	m.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		m.canceled <- struct{}{}
+		return nil, nil, ctx.Err()
+	case <-m.release:
+		return model.Matrix{{Metric: model.Metric{"job": "api"}}}, nil, nil
+	}
+}
+
+func TestExecuteRangeQuery_CancelsAbandonedQueries(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	end := start.Add(time.Hour)
+	newAPI := func() *cancelableQueryAPI {
+		return &cancelableQueryAPI{
+			mockPrometheusAPI: mockPrometheusAPI{availableMetrics: []string{"up"}},
+			started:           make(chan struct{}, 10),
+			release:           make(chan struct{}),
+			canceled:          make(chan struct{}, 10),
+		}
+	}
+	query := func(loader *RealLoader, ctx context.Context) <-chan error {
+		errs := make(chan error, 1)
+		go func() {
+			_, err := loader.ExecuteRangeQuery(ctx, "up", start, end, time.Minute)
+			errs <- err
+		}()
+		return errs
+	}
+	waitCanceled := func(t *testing.T, api *cancelableQueryAPI) {
+		t.Helper()
+		select {
+		case <-api.canceled:
+		case <-time.After(time.Second):
+			t.Fatal("backend query was not canceled")
+		}
+	}
+
+	t.Run("query is canceled with its caller", func(t *testing.T) {
+		api := newAPI()
+		loader := &RealLoader{client: api}
+		ctx, cancel := context.WithCancel(t.Context())
+		errs := query(loader, ctx)
+		<-api.started
+		cancel()
+		if err := <-errs; err == nil {
+			t.Fatal("expected an error for the canceled query, got nil")
+		}
+		waitCanceled(t, api)
+	})
+
+	t.Run("shared query is canceled when its last caller leaves", func(t *testing.T) {
+		api := newAPI()
+		newLoader := func() *RealLoader {
+			loader := (&RealLoader{address: "http://prometheus-cancel:9090"}).WithSharedScope("scope")
+			loader.client = api
+			return loader
+		}
+		waiting := waitForSharedQueries(t, 3)
+		ctx1, cancel1 := context.WithCancel(t.Context())
+		ctx2, cancel2 := context.WithCancel(t.Context())
+		errs1 := query(newLoader(), ctx1)
+		errs2 := query(newLoader(), ctx2)
+		key := <-waiting
+		<-waiting
+		<-api.started
+
+		cancel1()
+		if err := <-errs1; err == nil {
+			t.Fatal("expected an error for the canceled caller, got nil")
+		}
+		// The first caller left synchronously, so the shared call would already be canceled.
+		sharedCallsMu.Lock()
+		call := sharedCalls[key]
+		sharedCallsMu.Unlock()
+		if call == nil || call.ctx.Err() != nil {
+			t.Fatalf("backend query canceled while a caller still waits for it")
+		}
+
+		cancel2()
+		if err := <-errs2; err == nil {
+			t.Fatal("expected an error for the canceled caller, got nil")
+		}
+		waitCanceled(t, api)
+
+		// A later caller starts a new query instead of receiving the cancellation.
+		close(api.release)
+		if err := <-query(newLoader(), t.Context()); err != nil {
+			t.Fatalf("unexpected error for a later caller: %v", err)
+		}
+	})
+}
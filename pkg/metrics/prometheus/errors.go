@@ -0,0 +1,103 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// Error codes classifying the failures reported by the metrics backend.
+const (
+	ErrorCodeInvalidQuery       = "invalid_query"
+	ErrorCodeExecution          = "execution_failed"
+	ErrorCodeTimeout            = "timeout"
+	ErrorCodeCanceled           = "canceled"
+	ErrorCodeRequestRejected    = "request_rejected"
+	ErrorCodeBackendUnavailable = "backend_unavailable"
+	ErrorCodeBadResponse        = "bad_response"
+)
+
+// errorHints tells the agent how to react to each class of backend failure.
+var errorHints = map[string]string{
+	ErrorCodeInvalidQuery:       "the query or its parameters are invalid, check the PromQL syntax, time range and step",
+	ErrorCodeExecution:          "the query failed during evaluation, e.g. on a type mismatch or a sample limit",
+	ErrorCodeTimeout:            "the query took too long, narrow the time range, increase the step or add label matchers",
+	ErrorCodeCanceled:           "the query was canceled before it completed",
+	ErrorCodeRequestRejected:    "the backend rejected the request, check the credentials and the backend URL",
+	ErrorCodeBackendUnavailable: "the backend failed to process the request, retry later",
+	ErrorCodeBadResponse:        "the backend response could not be decoded",
+}
+
+// BackendError is returned when the metrics backend fails a request. Code classifies
+// the failure from the errorType reported by the backend, for callers and structured logging.
+type BackendError struct {
+	Code    string
+	Message string
+	err     error
+}
+
+func (e *BackendError) Error() string {
+	if hint, ok := errorHints[e.Code]; ok {
+		return fmt.Sprintf("%s: %s (%s)", e.Code, e.Message, hint)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *BackendError) Unwrap() error {
+	return e.err
+}
+
+// classifyBackendError converts an error returned by the Prometheus client into a
+// BackendError when its class is known, and returns other errors unchanged.
+func classifyBackendError(err error) error {
+	var apiErr *v1.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return &BackendError{Code: errorCodeFor(apiErr.Type), Message: apiErr.Msg, err: err}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &BackendError{Code: ErrorCodeTimeout, Message: err.Error(), err: err}
+	case errors.Is(err, context.Canceled):
+		return &BackendError{Code: ErrorCodeCanceled, Message: err.Error(), err: err}
+	default:
+		return err
+	}
+}
+
+func errorCodeFor(errorType v1.ErrorType) string {
+	switch errorType {
+	case v1.ErrBadData:
+		return ErrorCodeInvalidQuery
+	case v1.ErrExec:
+		return ErrorCodeExecution
+	case v1.ErrTimeout:
+		return ErrorCodeTimeout
+	case v1.ErrCanceled:
+		return ErrorCodeCanceled
+	case v1.ErrClient:
+		return ErrorCodeRequestRejected
+	case v1.ErrServer:
+		return ErrorCodeBackendUnavailable
+	case v1.ErrBadResponse:
+		return ErrorCodeBadResponse
+	default:
+		return string(errorType)
+	}
+}
+
+// isRequestRejected reports whether the backend refused a request as invalid, as opposed
+// to failing to process it.
+func isRequestRejected(err error) bool {
+	code := errorCode(err)
+	return code == ErrorCodeInvalidQuery || code == ErrorCodeRequestRejected
+}
+
+// errorCode returns the code of a BackendError, or "unknown" for other errors.
+func errorCode(err error) string {
+	var be *BackendError
+	if errors.As(err, &be) {
+		return be.Code
+	}
+	return "unknown"
+}
@@ -0,0 +1,126 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+func TestClassifyBackendError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{
+			name:     "bad_data",
+			err:      &v1.Error{Type: v1.ErrBadData, Msg: `invalid parameter "query": 1:5: parse error`},
+			wantCode: ErrorCodeInvalidQuery,
+		},
+		{
+			name:     "execution",
+			err:      &v1.Error{Type: v1.ErrExec, Msg: "query processing would load too many samples into memory"},
+			wantCode: ErrorCodeExecution,
+		},
+		{
+			name:     "timeout",
+			err:      &v1.Error{Type: v1.ErrTimeout, Msg: "query timed out in expression evaluation"},
+			wantCode: ErrorCodeTimeout,
+		},
+		{
+			name:     "canceled",
+			err:      &v1.Error{Type: v1.ErrCanceled, Msg: "query was canceled in expression evaluation"},
+			wantCode: ErrorCodeCanceled,
+		},
+		{
+			name:     "client_error",
+			err:      &v1.Error{Type: v1.ErrClient, Msg: "client error: 403"},
+			wantCode: ErrorCodeRequestRejected,
+		},
+		{
+			name:     "server_error",
+			err:      &v1.Error{Type: v1.ErrServer, Msg: "server error: 503"},
+			wantCode: ErrorCodeBackendUnavailable,
+		},
+		{
+			name:     "bad_response",
+			err:      &v1.Error{Type: v1.ErrBadResponse, Msg: "readObjectStart: expect { or n"},
+			wantCode: ErrorCodeBadResponse,
+		},
+		{
+			name:     "unknown errorType is kept",
+			err:      &v1.Error{Type: "not_acceptable", Msg: "unsupported format"},
+			wantCode: "not_acceptable",
+		},
+		{
+			name:     "wrapped client error",
+			err:      fmt.Errorf("request failed: %w", &v1.Error{Type: v1.ErrTimeout, Msg: "query timed out"}),
+			wantCode: ErrorCodeTimeout,
+		},
+		{
+			name:     "context deadline",
+			err:      fmt.Errorf("Post \"http://prometheus/api/v1/query\": %w", context.DeadlineExceeded),
+			wantCode: ErrorCodeTimeout,
+		},
+		{
+			name:     "context canceled",
+			err:      context.Canceled,
+			wantCode: ErrorCodeCanceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyBackendError(tt.err)
+			var be *BackendError
+			if !errors.As(err, &be) {
+				t.Fatalf("expected a BackendError, got %T: %v", err, err)
+			}
+			if be.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", be.Code, tt.wantCode)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("expected the original error to be wrapped")
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantCode+": ") {
+				t.Errorf("error message %q does not start with the code", err.Error())
+			}
+		})
+	}
+
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		orig := errors.New("dial tcp: connection refused")
+		if err := classifyBackendError(orig); err != orig {
+			t.Errorf("expected the error to be returned unchanged, got %v", err)
+		}
+	})
+}
+
+// failingQueryAPI fails every instant query with the given error.
+type failingQueryAPI struct {
+	mockPrometheusAPI
+	err error
+}
+
+func (m *failingQueryAPI) Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	return nil, nil, m.err
+}
+
+func TestExecuteInstantQuery_BackendErrorCode(t *testing.T) {
+	loader := &RealLoader{client: &failingQueryAPI{
+		mockPrometheusAPI: mockPrometheusAPI{availableMetrics: []string{"up"}},
+		err:               &v1.Error{Type: v1.ErrExec, Msg: "vector cannot contain metrics with the same labelset"},
+	}}
+
+	_, err := loader.ExecuteInstantQuery(context.Background(), `up{job="api"}`, time.Now())
+	var be *BackendError
+	if !errors.As(err, &be) || be.Code != ErrorCodeExecution {
+		t.Fatalf("expected a %q backend error, got %v", ErrorCodeExecution, err)
+	}
+}
@@ -0,0 +1,167 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// externalLabelsTTL is how long discovered external labels are cached.
+const externalLabelsTTL = 10 * time.Minute
+
+const (
+	// ExternalLabelsSourceConfig marks external labels read from the Prometheus configuration.
+	ExternalLabelsSourceConfig = "prometheus_config"
+	// ExternalLabelsSourceStores marks external labels read from the label sets of Thanos stores.
+	ExternalLabelsSourceStores = "thanos_stores"
+)
+
+// ExternalLabels are the labels the backend attaches to every series it returns, such as
+// the cluster or replica of the Prometheus instance that collected them. Behind Thanos,
+// each store contributes its own values.
+type ExternalLabels struct {
+	Labels map[string][]string
+	Source string
+}
+
+type externalLabelsEntry struct {
+	labels  *ExternalLabels
+	fetched time.Time
+}
+
+var externalLabelsCache = struct {
+	sync.Mutex
+	entries map[string]externalLabelsEntry
+}{entries: make(map[string]externalLabelsEntry)}
+
+// GetExternalLabels returns the external labels of the backend, read from the Prometheus
+// configuration or, for a Thanos Querier, from the label sets of its stores.
+// With a shared scope, the result is cached for externalLabelsTTL.
+func (p *RealLoader) GetExternalLabels(ctx context.Context) (*ExternalLabels, error) {
+	key := p.address + "\x00" + p.scope
+	if p.shared {
+		externalLabelsCache.Lock()
+		entry, ok := externalLabelsCache.entries[key]
+		externalLabelsCache.Unlock()
+		if ok && time.Since(entry.fetched) < externalLabelsTTL {
+			return entry.labels, nil
+		}
+	}
+
+	apiStart := time.Now()
+	labels, configErr := p.externalLabelsFromConfig(ctx)
+	if configErr != nil {
+		var storesErr error
+		labels, storesErr = p.externalLabelsFromStores(ctx)
+		if storesErr != nil {
+			err := classifyBackendError(errors.Join(configErr, storesErr))
+			slog.Error("Backend call failed", "backend", p.backend, "operation", "external_labels",
+				"duration_ms", time.Since(apiStart).Milliseconds(), "error_code", errorCode(err), "error", err)
+			return nil, fmt.Errorf("error fetching external labels: %w", err)
+		}
+	}
+	slog.Debug("Backend call completed", "backend", p.backend, "operation", "external_labels",
+		"duration_ms", time.Since(apiStart).Milliseconds(), "source", labels.Source, "label_count", len(labels.Labels))
+
+	if p.shared {
+		externalLabelsCache.Lock()
+		externalLabelsCache.entries[key] = externalLabelsEntry{labels: labels, fetched: time.Now()}
+		externalLabelsCache.Unlock()
+	}
+	return labels, nil
+}
+
+// globalConfig is the part of the global section of the Prometheus configuration read by the loader.
+type globalConfig struct {
+	ExternalLabels map[string]string `json:"external_labels"`
+	QueryLogFile   string            `json:"query_log_file"`
+}
+
+// globalConfig fetches the Prometheus configuration and returns its global section.
+// Thanos Querier does not serve its configuration, so this fails behind it.
+func (p *RealLoader) globalConfig(ctx context.Context) (globalConfig, error) {
+	cfg, err := p.client.Config(ctx)
+	if err != nil {
+		return globalConfig{}, err
+	}
+
+	var parsed struct {
+		Global globalConfig `json:"global"`
+	}
+	if err := yaml.Unmarshal([]byte(cfg.YAML), &parsed); err != nil {
+		return globalConfig{}, fmt.Errorf("failed to parse Prometheus configuration: %w", err)
+	}
+	return parsed.Global, nil
+}
+
+// externalLabelsFromConfig reads global.external_labels from the Prometheus configuration.
+func (p *RealLoader) externalLabelsFromConfig(ctx context.Context) (*ExternalLabels, error) {
+	global, err := p.globalConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := &ExternalLabels{Labels: make(map[string][]string), Source: ExternalLabelsSourceConfig}
+	for name, value := range global.ExternalLabels {
+		labels.Labels[name] = []string{value}
+	}
+	return labels, nil
+}
+
+// externalLabelsFromStores collects the label sets announced by the stores of a Thanos
+// Querier through its /api/v1/stores endpoint.
+func (p *RealLoader) externalLabelsFromStores(ctx context.Context) (*ExternalLabels, error) {
+	if p.apiClient == nil {
+		return nil, fmt.Errorf("stores API not available")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiClient.URL("/api/v1/stores", nil).String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, body, err := p.apiClient.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("stores API returned status %s", resp.Status)
+	}
+
+	var parsed struct {
+		Data map[string][]struct {
+			LabelSets []map[string]string `json:"labelSets"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode stores API response: %w", err)
+	}
+
+	values := make(map[string]map[string]struct{})
+	for _, stores := range parsed.Data {
+		for _, store := range stores {
+			for _, labelSet := range store.LabelSets {
+				for name, value := range labelSet {
+					if values[name] == nil {
+						values[name] = make(map[string]struct{})
+					}
+					values[name][value] = struct{}{}
+				}
+			}
+		}
+	}
+
+	labels := &ExternalLabels{Labels: make(map[string][]string, len(values)), Source: ExternalLabelsSourceStores}
+	for name, set := range values {
+		labels.Labels[name] = slices.Sorted(maps.Keys(set))
+	}
+	return labels, nil
+}
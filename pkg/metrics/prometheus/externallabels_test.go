@@ -0,0 +1,71 @@
+package prometheus
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// configAPI serves a fixed Prometheus configuration and counts the requests for it.
+type configAPI struct {
+	mockPrometheusAPI
+	yaml  string
+	calls atomic.Int32
+}
+
+func (m *configAPI) Config(ctx context.Context) (v1.ConfigResult, error) {
+	m.calls.Add(1)
+	return v1.ConfigResult{YAML: m.yaml}, nil
+}
+
+func TestGetExternalLabels_FromConfig(t *testing.T) {
+	api := &configAPI{yaml: `
+global:
+  scrape_interval: 30s
+  external_labels:
+    cluster: prod
+    replica: prometheus-0
+`}
+
+	run := func(t *testing.T, loader *RealLoader) {
+		t.Helper()
+		labels, err := loader.GetExternalLabels(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string][]string{"cluster": {"prod"}, "replica": {"prometheus-0"}}
+		if !reflect.DeepEqual(labels.Labels, want) {
+			t.Errorf("labels = %v, want %v", labels.Labels, want)
+		}
+		if labels.Source != ExternalLabelsSourceConfig {
+			t.Errorf("source = %q, want %q", labels.Source, ExternalLabelsSourceConfig)
+		}
+	}
+
+	t.Run("not cached by default", func(t *testing.T) {
+		api.calls.Store(0)
+		loader := &RealLoader{client: api, address: "http://prometheus:9090"}
+		run(t, loader)
+		run(t, loader)
+		if calls := api.calls.Load(); calls != 2 {
+			t.Errorf("config fetched %d times, want 2", calls)
+		}
+	})
+
+	t.Run("cached within a shared scope", func(t *testing.T) {
+		api.calls.Store(0)
+		run(t, (&RealLoader{client: api, address: "http://external-labels:9090"}).WithSharedScope("scope"))
+		run(t, (&RealLoader{client: api, address: "http://external-labels:9090"}).WithSharedScope("scope"))
+		if calls := api.calls.Load(); calls != 1 {
+			t.Errorf("config fetched %d times, want 1", calls)
+		}
+
+		run(t, (&RealLoader{client: api, address: "http://external-labels:9090"}).WithSharedScope("other"))
+		if calls := api.calls.Load(); calls != 2 {
+			t.Errorf("config fetched %d times after a scope change, want 2", calls)
+		}
+	})
+}
@@ -1,9 +1,16 @@
 package prometheus
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"log/slog"
+	"math"
+	"regexp/syntax"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	model "github.com/prometheus/common/model"
@@ -17,6 +24,15 @@ const (
 	GuardrailRequireLabelMatcher       = "require-label-matcher"
 	GuardrailDisallowBlanketRegex      = "disallow-blanket-regex"
 	GuardrailMaxMetricCardinality      = "max-metric-cardinality"
+	GuardrailLimitMatchers             = "limit-matchers"
+	GuardrailLimitSubqueries           = "limit-subqueries"
+	GuardrailDisallowAllMetrics        = "disallow-all-metrics"
+	GuardrailMaxNestingDepth           = "max-nesting-depth"
+
+	// GuardrailMaxResultSeries identifies violations of the result series limit.
+	// It is not selected through ParseGuardrails; it is enabled by setting
+	// a non-zero MaxResultSeries.
+	GuardrailMaxResultSeries = "max-result-series"
 
 	// GuardrailShortcutTSDB is a shortcut that refers to both TSDB-dependent
 	// guardrails (max-metric-cardinality and disallow-blanket-regex). Use
@@ -31,6 +47,22 @@ const (
 	DefaultMaxLabelCardinality  uint64 = 500
 )
 
+// Default matcher limits
+const (
+	DefaultMaxMatchersPerSelector uint64 = 20
+	DefaultMaxRegexAlternatives   uint64 = 50
+)
+
+// Default subquery limits
+const (
+	DefaultMaxSubqueryRange = 24 * time.Hour
+	DefaultMinSubqueryStep  = 30 * time.Second
+)
+
+// DefaultMaxNestingDepth is the default maximum number of functions and aggregations
+// nested in each other.
+const DefaultMaxNestingDepth uint64 = 8
+
 // GuardrailViolation is returned when a query violates a specific guardrail rule.
 // It carries the guardrail name for structured logging.
 type GuardrailViolation struct {
@@ -58,6 +90,34 @@ type Guardrails struct {
 	// MaxLabelCardinality sets the maximum allowed label value count for blanket regex
 	// (0 = always disallow regex matcher provided DisallowBlanketRegex is true)
 	MaxLabelCardinality uint64
+	// MaxResultSeries sets the maximum number of series a query may return
+	// (0 = no limit)
+	MaxResultSeries uint64
+	// LimitMatchers bounds the number of label matchers per selector and the number of
+	// alternatives in each regex matcher
+	LimitMatchers bool
+	// MaxMatchersPerSelector sets the maximum number of label matchers in a selector,
+	// not counting the metric name (0 = DefaultMaxMatchersPerSelector)
+	MaxMatchersPerSelector uint64
+	// MaxRegexAlternatives sets the maximum number of literal alternatives a regex matcher expands to
+	// (0 = DefaultMaxRegexAlternatives)
+	MaxRegexAlternatives uint64
+	// LimitSubqueries bounds the range and resolution of subqueries
+	LimitSubqueries bool
+	// MaxSubqueryRange sets the maximum range a subquery may cover, including the ranges
+	// of the subqueries it is nested in (0 = DefaultMaxSubqueryRange)
+	MaxSubqueryRange time.Duration
+	// MinSubqueryStep sets the finest resolution a subquery may use
+	// (0 = DefaultMinSubqueryStep)
+	MinSubqueryStep time.Duration
+	// DisallowAllMetrics prevents selectors like {__name__=~".+"} that match every metric
+	// without any other matcher narrowing them down
+	DisallowAllMetrics bool
+	// LimitNestingDepth bounds how deeply functions, aggregations and subqueries are nested
+	LimitNestingDepth bool
+	// MaxNestingDepth sets the maximum nesting depth of functions, aggregations and
+	// subqueries (0 = DefaultMaxNestingDepth)
+	MaxNestingDepth uint64
 }
 
 // DefaultGuardrails returns a Guardrails instance with default numeric thresholds.
@@ -68,6 +128,10 @@ func DefaultGuardrails(enableAll bool) *Guardrails {
 		RequireLabelMatcher:       enableAll,
 		DisallowBlanketRegex:      enableAll,
 		ForceMaxMetricCardinality: enableAll,
+		LimitMatchers:             enableAll,
+		LimitSubqueries:           enableAll,
+		DisallowAllMetrics:        enableAll,
+		LimitNestingDepth:         enableAll,
 		MaxMetricCardinality:      DefaultMaxMetricCardinality,
 		MaxLabelCardinality:       DefaultMaxLabelCardinality,
 	}
@@ -113,6 +177,14 @@ func ParseGuardrails(value string) (*Guardrails, error) {
 			g.DisallowBlanketRegex = !defaultValue
 		case GuardrailMaxMetricCardinality:
 			g.ForceMaxMetricCardinality = !defaultValue
+		case GuardrailLimitMatchers:
+			g.LimitMatchers = !defaultValue
+		case GuardrailLimitSubqueries:
+			g.LimitSubqueries = !defaultValue
+		case GuardrailDisallowAllMetrics:
+			g.DisallowAllMetrics = !defaultValue
+		case GuardrailMaxNestingDepth:
+			g.LimitNestingDepth = !defaultValue
 		case GuardrailShortcutTSDB:
 			if !negative {
 				return nil, fmt.Errorf("%q is only valid as a negative shortcut (!tsdb); use individual guardrail names in positive mode", GuardrailShortcutTSDB)
@@ -120,14 +192,59 @@ func ParseGuardrails(value string) (*Guardrails, error) {
 			g.ForceMaxMetricCardinality = false
 			g.DisallowBlanketRegex = false
 		default:
-			return nil, fmt.Errorf("unknown guardrail: %q (valid options: %s, %s, %s, %s)",
+			return nil, fmt.Errorf("unknown guardrail: %q (valid options: %s, %s, %s, %s, %s, %s, %s, %s)",
 				name, GuardrailDisallowExplicitNameLabel, GuardrailRequireLabelMatcher,
-				GuardrailDisallowBlanketRegex, GuardrailMaxMetricCardinality)
+				GuardrailDisallowBlanketRegex, GuardrailMaxMetricCardinality, GuardrailLimitMatchers,
+				GuardrailLimitSubqueries, GuardrailDisallowAllMetrics, GuardrailMaxNestingDepth)
 		}
 	}
 	return g, nil
 }
 
+// Relax returns a copy of the guardrails with the named guardrails disabled. Names are
+// given as a comma-separated list and may carry a "!" prefix; "all" disables every
+// guardrail, and "max-result-series" removes the result series limit. Relaxing can only
+// disable guardrails, never enable them or raise their thresholds.
+func (g *Guardrails) Relax(value string) (*Guardrails, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	relaxed := *g
+	for name := range strings.SplitSeq(strings.ToLower(value), ",") {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "!")
+		switch name {
+		case "":
+		case "all":
+			return nil, nil
+		case GuardrailDisallowExplicitNameLabel:
+			relaxed.DisallowExplicitNameLabel = false
+		case GuardrailRequireLabelMatcher:
+			relaxed.RequireLabelMatcher = false
+		case GuardrailDisallowBlanketRegex:
+			relaxed.DisallowBlanketRegex = false
+		case GuardrailMaxMetricCardinality:
+			relaxed.ForceMaxMetricCardinality = false
+		case GuardrailLimitMatchers:
+			relaxed.LimitMatchers = false
+		case GuardrailLimitSubqueries:
+			relaxed.LimitSubqueries = false
+		case GuardrailDisallowAllMetrics:
+			relaxed.DisallowAllMetrics = false
+		case GuardrailMaxNestingDepth:
+			relaxed.LimitNestingDepth = false
+		case GuardrailMaxResultSeries:
+			relaxed.MaxResultSeries = 0
+		case GuardrailShortcutTSDB:
+			relaxed.ForceMaxMetricCardinality = false
+			relaxed.DisallowBlanketRegex = false
+		default:
+			return nil, fmt.Errorf("unknown guardrail: %q", name)
+		}
+	}
+	return &relaxed, nil
+}
+
 // IsSafeQuery analyzes a PromQL query string and returns false if it's
 // deemed unsafe or too expensive based on the configured rules.
 // If client is provided and MaxMetricCardinality is set, it checks TSDB metric cardinality.
@@ -136,33 +253,70 @@ func ParseGuardrails(value string) (*Guardrails, error) {
 // Returns (false, error) if the query is invalid or violates a guardrail rule.
 // The error message explains which rule was violated.
 // Returns (true, nil) if the query is valid and passes all rules.
+func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.API) (bool, error) {
+	if _, err := g.CheckQuery(ctx, query, client); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CheckQuery applies the same rules as IsSafeQuery, returning an error for a query that
+// violates one of them. For a query that passes, it returns advisories that do not block
+// it, such as metrics whose cardinality comes close to MaxMetricCardinality.
 //
 //nolint:gocyclo // complex validation logic, refactoring would reduce readability
-func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.API) (bool, error) {
+func (g *Guardrails) CheckQuery(ctx context.Context, query string, client v1.API) ([]string, error) {
 	if ((g.DisallowBlanketRegex && g.MaxLabelCardinality > 0) || g.ForceMaxMetricCardinality) && (client == nil || ctx == nil) {
-		return false, fmt.Errorf("cannot verify cardinality without TSDB client")
+		return nil, fmt.Errorf("cannot verify cardinality without TSDB client")
 	}
 
 	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
 	if err != nil {
-		return false, fmt.Errorf("failed to parse query: %w", err)
+		return nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	if g.LimitNestingDepth {
+		maxDepth := cmp.Or(g.MaxNestingDepth, DefaultMaxNestingDepth)
+		if depth := nestingDepth(expr); uint64(depth) > maxDepth {
+			return nil, &GuardrailViolation{
+				Guardrail: GuardrailMaxNestingDepth,
+				Message: fmt.Sprintf("query nests %d functions, aggregations or subqueries in each other, which exceeds maximum allowed %d; simplify the query",
+					depth, maxDepth),
+			}
+		}
 	}
 
 	var unsafeReason error
 
 	parser.Inspect(expr, func(node parser.Node, path []parser.Node) error {
+		if sq, ok := node.(*parser.SubqueryExpr); ok && g.LimitSubqueries {
+			unsafeReason = g.checkSubqueryLimits(sq, path)
+			return unsafeReason
+		}
+
 		vs, ok := node.(*parser.VectorSelector)
 		if !ok {
 			return nil
 		}
 
-		// Check for explicit __name__ label query
+		// Reject selectors matching every metric before the more general __name__ check,
+		// so that the error says what is actually wrong with them
+		if g.DisallowAllMetrics && selectsAllMetrics(vs) {
+			unsafeReason = &GuardrailViolation{
+				Guardrail: GuardrailDisallowAllMetrics,
+				Message: fmt.Sprintf("query for %s selects all metrics, which is disallowed; match the metric names with a narrower pattern such as {__name__=~\"http_.*\"} or add a label matcher such as job=\"api\"",
+					describeSelector(vs)),
+			}
+			return unsafeReason
+		}
+
+		// Check for explicit __name__ label query, covering both equality and regex matchers
 		if g.DisallowExplicitNameLabel && vs.Name == "" {
 			for _, m := range vs.LabelMatchers {
 				if m.Name == model.MetricNameLabel {
 					unsafeReason = &GuardrailViolation{
 						Guardrail: GuardrailDisallowExplicitNameLabel,
-						Message:   "query uses explicit __name__ label matcher, which is disallowed",
+						Message:   fmt.Sprintf("query for %s uses explicit __name__ label matcher, which is disallowed", describeSelector(vs)),
 					}
 					return unsafeReason
 				}
@@ -181,30 +335,39 @@ func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.AP
 			if !hasNonNameMatcher {
 				unsafeReason = &GuardrailViolation{
 					Guardrail: GuardrailRequireLabelMatcher,
-					Message:   fmt.Sprintf("query for metric %q does not have any label matchers, which is required", vs.Name),
+					Message: fmt.Sprintf("query for %s does not have any label matchers, which is required%s",
+						describeSelector(vs), describeBinaryOperand(vs, path)),
 				}
 				return unsafeReason
 			}
 		}
 
+		if g.LimitMatchers {
+			if unsafeReason = g.checkMatcherLimits(vs); unsafeReason != nil {
+				return unsafeReason
+			}
+		}
+
 		return nil
 	})
 
 	if unsafeReason != nil {
-		return false, unsafeReason
+		return nil, unsafeReason
 	}
 
+	var advisories []string
+
 	// Check metric cardinality
 	if g.ForceMaxMetricCardinality {
 		metricNames, err := ExtractMetricNames(query)
 		if err != nil {
-			return false, fmt.Errorf("failed to extract metric names: %w", err)
+			return nil, fmt.Errorf("failed to extract metric names: %w", err)
 		}
 
 		if len(metricNames) > 0 {
 			tsdbResult, err := client.TSDB(ctx)
 			if err != nil {
-				return false, fmt.Errorf(
+				return nil, fmt.Errorf(
 					"cannot enforce max-metric-cardinality guardrail: TSDB stats endpoint is unavailable on this backend "+
 						"(Thanos Querier < v0.40.0 does not implement /api/v1/status/tsdb); "+
 						"disable this guardrail with --guardrails '!tsdb': %w", err)
@@ -215,16 +378,23 @@ func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.AP
 				seriesCountByMetric[stat.Name] = stat.Value
 			}
 
+			var highCardinality []string
 			for _, metricName := range metricNames {
 				if count, exists := seriesCountByMetric[metricName]; exists {
 					if count > g.MaxMetricCardinality {
-						return false, &GuardrailViolation{
+						return nil, &GuardrailViolation{
 							Guardrail: GuardrailMaxMetricCardinality,
 							Message:   fmt.Sprintf("metric %q has cardinality %d, which exceeds maximum allowed %d", metricName, count, g.MaxMetricCardinality),
 						}
 					}
+					if float64(count) > highCardinalityRatio*float64(g.MaxMetricCardinality) {
+						highCardinality = append(highCardinality, metricName)
+					}
 				}
 			}
+			if advisory := cardinalityAdvisory(highCardinality, seriesCountByMetric, g.MaxMetricCardinality); advisory != "" {
+				advisories = append(advisories, advisory)
+			}
 		}
 	}
 
@@ -232,13 +402,13 @@ func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.AP
 	if g.DisallowBlanketRegex {
 		blanketRegexLabels, err := ExtractBlanketRegexLabels(query)
 		if err != nil {
-			return false, fmt.Errorf("failed to extract blanket regex labels: %w", err)
+			return nil, fmt.Errorf("failed to extract blanket regex labels: %w", err)
 		}
 
 		if len(blanketRegexLabels) > 0 {
 			// If MaxLabelCardinality is 0, always disallow blanket regex
 			if g.MaxLabelCardinality == 0 {
-				return false, &GuardrailViolation{
+				return nil, &GuardrailViolation{
 					Guardrail: GuardrailDisallowBlanketRegex,
 					Message:   fmt.Sprintf("query uses blanket regex on label %q, which is disallowed", blanketRegexLabels[0]),
 				}
@@ -247,7 +417,7 @@ func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.AP
 			// Check TSDB label cardinality for blanket regex
 			tsdbResult, err := client.TSDB(ctx)
 			if err != nil {
-				return false, fmt.Errorf(
+				return nil, fmt.Errorf(
 					"cannot enforce max-label-cardinality guardrail: TSDB stats endpoint is unavailable on this backend "+
 						"(Thanos Querier < v0.40.0 does not implement /api/v1/status/tsdb); "+
 						"disable this guardrail with --guardrails '!tsdb': %w", err)
@@ -261,7 +431,7 @@ func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.AP
 			for _, labelName := range blanketRegexLabels {
 				if count, exists := labelValueCountByLabel[labelName]; exists {
 					if count > g.MaxLabelCardinality {
-						return false, &GuardrailViolation{
+						return nil, &GuardrailViolation{
 							Guardrail: GuardrailDisallowBlanketRegex,
 							Message:   fmt.Sprintf("label %q has cardinality %d, which exceeds maximum allowed %d for blanket regex", labelName, count, g.MaxLabelCardinality),
 						}
@@ -271,7 +441,448 @@ func (g *Guardrails) IsSafeQuery(ctx context.Context, query string, client v1.AP
 		}
 	}
 
-	return true, nil
+	return advisories, nil
+}
+
+// highCardinalityRatio is the share of MaxMetricCardinality above which a metric is
+// reported as high cardinality, before it gets rejected.
+const highCardinalityRatio = 0.5
+
+// cardinalityAdvisory returns a warning listing the high cardinality metrics of a query,
+// highest first, so that the query can be narrowed down before they hit the limit. It
+// returns "" when there are none.
+func cardinalityAdvisory(metricNames []string, seriesCountByMetric map[string]uint64, maxCardinality uint64) string {
+	if len(metricNames) == 0 {
+		return ""
+	}
+	slices.SortFunc(metricNames, func(a, b string) int {
+		return cmp.Or(cmp.Compare(seriesCountByMetric[b], seriesCountByMetric[a]), cmp.Compare(a, b))
+	})
+	described := make([]string, len(metricNames))
+	for i, name := range metricNames {
+		described[i] = fmt.Sprintf("%s (%d series)", name, seriesCountByMetric[name])
+	}
+	return fmt.Sprintf("high cardinality metrics, above %d%% of the maximum allowed %d series: %s; add label matchers to select only the series needed",
+		int(highCardinalityRatio*100), maxCardinality, strings.Join(described, ", "))
+}
+
+// checkMatcherLimits rejects selectors with more label matchers than MaxMatchersPerSelector,
+// or a regex matcher with more alternatives than MaxRegexAlternatives. Long alternations
+// such as pod=~"a|b|...|z" are selective but still expensive to match against every series.
+func (g *Guardrails) checkMatcherLimits(vs *parser.VectorSelector) error {
+	maxMatchers := cmp.Or(g.MaxMatchersPerSelector, DefaultMaxMatchersPerSelector)
+	maxAlternatives := cmp.Or(g.MaxRegexAlternatives, DefaultMaxRegexAlternatives)
+
+	matchers := 0
+	for _, m := range vs.LabelMatchers {
+		// The metric name written before the braces is not a matcher the user wrote.
+		if vs.Name != "" && m.Name == model.MetricNameLabel && m.Type == labels.MatchEqual && m.Value == vs.Name {
+			continue
+		}
+		matchers++
+
+		if m.Type != labels.MatchRegexp && m.Type != labels.MatchNotRegexp {
+			continue
+		}
+		if alternatives := regexAlternatives(m.Value); uint64(alternatives) > maxAlternatives {
+			return &GuardrailViolation{
+				Guardrail: GuardrailLimitMatchers,
+				Message: fmt.Sprintf("query for %s has %d alternatives in the regex matcher on label %q, which exceeds maximum allowed %d; use a broader pattern or split the query",
+					describeSelectorName(vs), alternatives, m.Name, maxAlternatives),
+			}
+		}
+	}
+
+	if uint64(matchers) > maxMatchers {
+		return &GuardrailViolation{
+			Guardrail: GuardrailLimitMatchers,
+			Message: fmt.Sprintf("query for %s has %d label matchers, which exceeds maximum allowed %d",
+				describeSelectorName(vs), matchers, maxMatchers),
+		}
+	}
+	return nil
+}
+
+// checkSubqueryLimits rejects subqueries without an explicit resolution, with a finer one
+// than MinSubqueryStep, or covering more than MaxSubqueryRange. A nested subquery is
+// evaluated over its own range back from every step of the subqueries enclosing it, so
+// their ranges add up.
+func (g *Guardrails) checkSubqueryLimits(sq *parser.SubqueryExpr, path []parser.Node) error {
+	maxRange := cmp.Or(g.MaxSubqueryRange, DefaultMaxSubqueryRange)
+	minStep := cmp.Or(g.MinSubqueryStep, DefaultMinSubqueryStep)
+
+	if sq.Step == 0 {
+		return &GuardrailViolation{
+			Guardrail: GuardrailLimitSubqueries,
+			Message: fmt.Sprintf("subquery %s does not set a resolution, so it is evaluated at the default evaluation interval; set one explicitly, e.g. [%s:%s]",
+				sq, model.Duration(sq.Range), model.Duration(max(minStep, time.Minute))),
+		}
+	}
+	if sq.Step < minStep {
+		return &GuardrailViolation{
+			Guardrail: GuardrailLimitSubqueries,
+			Message: fmt.Sprintf("subquery %s has a resolution of %s, which is finer than the minimum allowed %s",
+				sq, model.Duration(sq.Step), model.Duration(minStep)),
+		}
+	}
+
+	covered := sq.Range
+	for _, node := range path {
+		if outer, ok := node.(*parser.SubqueryExpr); ok {
+			covered += outer.Range
+		}
+	}
+	if covered > maxRange {
+		nested := ""
+		if covered != sq.Range {
+			nested = " including the subqueries it is nested in"
+		}
+		return &GuardrailViolation{
+			Guardrail: GuardrailLimitSubqueries,
+			Message: fmt.Sprintf("subquery %s covers %s%s, which exceeds maximum allowed %s; shorten the range or use a recording rule",
+				sq, model.Duration(covered), nested, model.Duration(maxRange)),
+		}
+	}
+	return nil
+}
+
+// nestingDepth returns the largest number of functions, aggregations and subqueries
+// nested in each other in an expression, so that sum(rate(x[5m])) has a depth of 2.
+// Parentheses and operators do not add to the depth, as long but flat expressions such
+// as a + b + c are neither expensive nor unusual.
+func nestingDepth(node parser.Node) int {
+	depth := 0
+	for _, child := range parser.Children(node) {
+		depth = max(depth, nestingDepth(child))
+	}
+	switch node.(type) {
+	case *parser.Call, *parser.AggregateExpr, *parser.SubqueryExpr:
+		depth++
+	}
+	return depth
+}
+
+// selectsAllMetrics reports whether a selector matches every metric: its metric name is
+// only given by a blanket regex on __name__, and none of its other matchers narrows the
+// series down, as matchers like job=~".+" or namespace!="" do not.
+func selectsAllMetrics(vs *parser.VectorSelector) bool {
+	if vs.Name != "" {
+		return false
+	}
+	blanketName := false
+	for _, m := range vs.LabelMatchers {
+		blanket := m.Type == labels.MatchRegexp && isBlanketRegex(m.Value)
+		switch {
+		case m.Name == model.MetricNameLabel && blanket:
+			blanketName = true
+		case m.Name == model.MetricNameLabel:
+			return false
+		case m.Matches("") || m.Type == labels.MatchNotEqual || blanket:
+			// Only requires the label to be present, or not even that.
+		default:
+			return false
+		}
+	}
+	return blanketName
+}
+
+// isBlanketRegex reports whether a label matcher regex matches any value, or any non-empty
+// one. Besides the literal .* and .+ it recognizes the same patterns written differently,
+// such as ^.*$, (.+), .+.*, [\s\S]* or an alternation with such a branch like foo|.*.
+func isBlanketRegex(re string) bool {
+	// Prometheus anchors regexes and lets . match newlines, see labels.NewFastRegexMatcher.
+	parsed, err := syntax.Parse(re, syntax.Perl|syntax.DotNL)
+	if err != nil {
+		return false
+	}
+	return blanketRegexp(parsed.Simplify())
+}
+
+func blanketRegexp(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpCapture:
+		return blanketRegexp(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		return anyCharRegexp(re.Sub[0])
+	case syntax.OpAlternate:
+		return slices.ContainsFunc(re.Sub, blanketRegexp)
+	case syntax.OpConcat:
+		blanket := false
+		for _, sub := range re.Sub {
+			switch sub.Op {
+			case syntax.OpBeginText, syntax.OpEndText, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpEmptyMatch:
+			default:
+				if !blanketRegexp(sub) {
+					return false
+				}
+				blanket = true
+			}
+		}
+		return blanket
+	}
+	return false
+}
+
+// anyCharRegexp reports whether re matches any single character.
+func anyCharRegexp(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar:
+		return true
+	case syntax.OpCharClass:
+		return len(re.Rune) == 2 && re.Rune[0] == 0 && re.Rune[1] == unicode.MaxRune
+	}
+	return false
+}
+
+// maxEnumeratedClass is the largest character class regexAlternatives counts as one
+// alternative per character. The regexp parser merges single-character alternatives
+// such as 1|2|3 into classes, while larger classes are patterns, not lists.
+const maxEnumeratedClass = 256
+
+// regexAlternatives returns the number of alternatives in a regular expression: the
+// branches of its alternations, multiplied across a concatenation, e.g. 3 for
+// web-(1|2|3) and 4 for (a|b)-(c|d). A character class such as [0-9] or \d, the any
+// character and repetitions such as [0-9]+ count as one, so node-[0-9][0-9] is 1.
+func regexAlternatives(re string) int {
+	parsed, err := syntax.Parse(singleRuneClasses(re), syntax.Perl|syntax.DotNL)
+	if err != nil {
+		return 1
+	}
+	return literalAlternatives(parsed)
+}
+
+// classPlaceholderBase is the first of the private use runes singleRuneClasses replaces
+// character classes with.
+const classPlaceholderBase = 0xE000
+
+// singleRuneClasses replaces the character classes of a regular expression, bracketed
+// or escaped, and the any character with a distinct placeholder rune each. The parser
+// then counts them as a single alternative, while still merging single-character
+// alternatives such as 1|2|3 into a class of one rune per branch.
+func singleRuneClasses(re string) string {
+	var b strings.Builder
+	placeholders := 0
+	placeholder := func() {
+		b.WriteRune(rune(classPlaceholderBase + placeholders%0x1900))
+		placeholders++
+	}
+	for i := 0; i < len(re); i++ {
+		switch re[i] {
+		case '.':
+			placeholder()
+		case '[':
+			end := classEnd(re, i)
+			if end < 0 {
+				b.WriteString(re[i:])
+				return b.String()
+			}
+			placeholder()
+			i = end
+		case '\\':
+			if i+1 >= len(re) {
+				b.WriteByte(re[i])
+				continue
+			}
+			switch re[i+1] {
+			case 'd', 'D', 's', 'S', 'w', 'W':
+				placeholder()
+				i++
+			case 'p', 'P':
+				end := i + 2
+				if end < len(re) && re[end] == '{' {
+					if close := strings.IndexByte(re[end:], '}'); close >= 0 {
+						end += close
+					}
+				}
+				placeholder()
+				i = end
+			case 'Q':
+				// Quoted text is literal up to \E.
+				end := strings.Index(re[i:], `\E`)
+				if end < 0 {
+					b.WriteString(re[i:])
+					return b.String()
+				}
+				b.WriteString(re[i : i+end+2])
+				i += end + 1
+			default:
+				b.WriteString(re[i : i+2])
+				i++
+			}
+		default:
+			b.WriteByte(re[i])
+		}
+	}
+	return b.String()
+}
+
+// classEnd returns the index of the ']' closing the bracketed character class opened at
+// start, or -1 when it is not closed.
+func classEnd(re string, start int) int {
+	i := start + 1
+	if i < len(re) && re[i] == '^' {
+		i++
+	}
+	// A ']' right after the opening bracket is a literal.
+	if i < len(re) && re[i] == ']' {
+		i++
+	}
+	for ; i < len(re); i++ {
+		switch {
+		case re[i] == '\\':
+			i++
+		case strings.HasPrefix(re[i:], "[:"):
+			if end := strings.Index(re[i+2:], ":]"); end >= 0 {
+				i += end + 3
+			}
+		case re[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func literalAlternatives(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpCapture:
+		return literalAlternatives(re.Sub[0])
+	case syntax.OpAlternate:
+		n := 0
+		for _, sub := range re.Sub {
+			n = min(n+literalAlternatives(sub), math.MaxInt32)
+		}
+		return n
+	case syntax.OpConcat:
+		n := 1
+		for _, sub := range re.Sub {
+			n = min(n*literalAlternatives(sub), math.MaxInt32)
+		}
+		return n
+	case syntax.OpQuest:
+		return min(literalAlternatives(re.Sub[0])+1, math.MaxInt32)
+	case syntax.OpCharClass:
+		size := 0
+		for i := 0; i < len(re.Rune); i += 2 {
+			size += int(re.Rune[i+1]-re.Rune[i]) + 1
+		}
+		if size <= maxEnumeratedClass {
+			return size
+		}
+	}
+	return 1
+}
+
+// EstimateResultSeries rejects a query before execution when it is a single vector
+// selector whose matching series over [start, end] exceed MaxResultSeries. The count
+// comes from the series API, which is much cheaper than evaluating the query itself.
+// Any other query shape is left to CheckResultSeries once its result is known.
+func (g *Guardrails) EstimateResultSeries(ctx context.Context, query string, start, end time.Time, client v1.API) error {
+	if g.MaxResultSeries == 0 || client == nil {
+		return nil
+	}
+
+	selector, ok := singleSelector(query)
+	if !ok {
+		return nil
+	}
+
+	series, _, err := client.Series(ctx, []string{selector}, start, end)
+	if err != nil {
+		// The estimate is an optimization only; the limit is still enforced after execution.
+		slog.Debug("Result series estimate failed", "query", query, "error", err)
+		return nil
+	}
+
+	if uint64(len(series)) > g.MaxResultSeries {
+		return &GuardrailViolation{
+			Guardrail: GuardrailMaxResultSeries,
+			Message: fmt.Sprintf("query selects %d series, which exceeds maximum allowed %d; add label matchers or aggregate the result",
+				len(series), g.MaxResultSeries),
+		}
+	}
+	return nil
+}
+
+// CheckResultSeries rejects a query result containing more than MaxResultSeries series.
+func (g *Guardrails) CheckResultSeries(count int) error {
+	if g.MaxResultSeries == 0 || uint64(count) <= g.MaxResultSeries {
+		return nil
+	}
+	return &GuardrailViolation{
+		Guardrail: GuardrailMaxResultSeries,
+		Message: fmt.Sprintf("query returned %d series, which exceeds maximum allowed %d; add label matchers or aggregate the result",
+			count, g.MaxResultSeries),
+	}
+}
+
+// singleSelector returns the selector of a query consisting of nothing but a vector
+// selector, optionally wrapped in parentheses. Selectors using offset or @ modifiers
+// are not reported, as their series would have to be looked up at a different time.
+func singleSelector(query string) (string, bool) {
+	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		paren, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.Expr
+	}
+
+	vs, ok := expr.(*parser.VectorSelector)
+	if !ok || vs.OriginalOffset != 0 || vs.Timestamp != nil || vs.StartOrEnd != 0 {
+		return "", false
+	}
+	return vs.String(), true
+}
+
+// describeSelector returns a readable representation of a vector selector for guardrail messages.
+// Selectors such as {__name__=~"http_.*"} have no metric name, so their matchers are rendered instead.
+func describeSelector(vs *parser.VectorSelector) string {
+	if vs.Name != "" {
+		return fmt.Sprintf("metric %q", vs.Name)
+	}
+
+	matchers := make([]string, len(vs.LabelMatchers))
+	for i, m := range vs.LabelMatchers {
+		matchers[i] = m.String()
+	}
+	return fmt.Sprintf("selector {%s}", strings.Join(matchers, ", "))
+}
+
+// describeSelectorName is describeSelector for messages about oversized selectors,
+// whose matchers are too long to be repeated.
+func describeSelectorName(vs *parser.VectorSelector) string {
+	if vs.Name != "" {
+		return fmt.Sprintf("metric %q", vs.Name)
+	}
+	return "selector without metric name"
+}
+
+// describeBinaryOperand locates a selector within the innermost binary operation containing
+// it, so that a message about e.g. rate(a{job="x"}[5m]) / rate(a[5m]) points at the side that
+// needs fixing. path holds the ancestors of the selector, as passed by parser.Inspect.
+func describeBinaryOperand(vs *parser.VectorSelector, path []parser.Node) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		bin, ok := path[i].(*parser.BinaryExpr)
+		if !ok {
+			continue
+		}
+
+		var operand parser.Node = vs
+		if i+1 < len(path) {
+			operand = path[i+1]
+		}
+		side := "right"
+		if operand == parser.Node(bin.LHS) {
+			side = "left"
+		}
+		return fmt.Sprintf("; it appears in the %s operand of %q: %s", side, bin.Op.String(), operand.String())
+	}
+	return ""
 }
 
 func ExtractMetricNames(query string) ([]string, error) {
@@ -303,7 +914,8 @@ func ExtractMetricNames(query string) ([]string, error) {
 	return result, nil
 }
 
-// ExtractBlanketRegexLabels extracts label names that use blanket regex patterns (.* or .+).
+// ExtractBlanketRegexLabels extracts label names that use blanket regex patterns (.* or .+
+// and their equivalents, see isBlanketRegex).
 func ExtractBlanketRegexLabels(query string) ([]string, error) {
 	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
 	if err != nil {
@@ -315,7 +927,7 @@ func ExtractBlanketRegexLabels(query string) ([]string, error) {
 		if vs, ok := node.(*parser.VectorSelector); ok {
 			for _, m := range vs.LabelMatchers {
 				isRegex := m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp
-				if isRegex && (m.Value == ".*" || m.Value == ".+") {
+				if isRegex && isBlanketRegex(m.Value) {
 					labelNames[m.Name] = true
 				}
 			}
@@ -2,6 +2,10 @@ package prometheus
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -59,6 +63,15 @@ func TestParseGuardrails(t *testing.T) {
 				MaxLabelCardinality:       DefaultMaxLabelCardinality,
 			},
 		},
+		{
+			name:  "limit-matchers only",
+			input: GuardrailLimitMatchers,
+			wantGuardrails: &Guardrails{
+				LimitMatchers:        true,
+				MaxMetricCardinality: DefaultMaxMetricCardinality,
+				MaxLabelCardinality:  DefaultMaxLabelCardinality,
+			},
+		},
 		// Whitespace tolerance
 		{
 			name:  "spaces around commas are trimmed",
@@ -202,13 +215,63 @@ func TestParseGuardrails(t *testing.T) {
 			if got == nil {
 				t.Fatalf("ParseGuardrails(%q) = nil, want non-nil", tt.input)
 			}
-			if *got != *tt.wantGuardrails {
+			if *got != *tt.wantGuardrails && !equalIgnoringAddedGuardrails(*got, *tt.wantGuardrails) {
 				t.Errorf("ParseGuardrails(%q)\n got  %+v\n want %+v", tt.input, *got, *tt.wantGuardrails)
 			}
 		})
 	}
 }
 
+// equalIgnoringAddedGuardrails compares got and want without the guardrails that
+// "all" gained after the cases above were written, as long as want leaves all of
+// them unset. TestParseGuardrails_AddedToAll covers those guardrails.
+func equalIgnoringAddedGuardrails(got, want Guardrails) bool {
+	if want.LimitMatchers || want.LimitSubqueries || want.DisallowAllMetrics || want.LimitNestingDepth {
+		return false
+	}
+	got.LimitMatchers = false
+	got.LimitSubqueries = false
+	got.DisallowAllMetrics = false
+	got.LimitNestingDepth = false
+	return got == want
+}
+
+// Guardrails added to "all" are on by default and stay on under negative
+// lists that do not name them, so upgrading can reject previously accepted queries.
+func TestParseGuardrails_AddedToAll(t *testing.T) {
+	added := map[string]func(*Guardrails) *bool{
+		GuardrailLimitMatchers:      func(g *Guardrails) *bool { return &g.LimitMatchers },
+		GuardrailLimitSubqueries:    func(g *Guardrails) *bool { return &g.LimitSubqueries },
+		GuardrailDisallowAllMetrics: func(g *Guardrails) *bool { return &g.DisallowAllMetrics },
+		GuardrailMaxNestingDepth:    func(g *Guardrails) *bool { return &g.LimitNestingDepth },
+	}
+
+	for name, field := range added {
+		t.Run(name, func(t *testing.T) {
+			for _, input := range []string{"", "all", name, "!" + GuardrailRequireLabelMatcher} {
+				got, err := ParseGuardrails(input)
+				if err != nil {
+					t.Fatalf("ParseGuardrails(%q) unexpected error: %v", input, err)
+				}
+				if !*field(got) {
+					t.Errorf("ParseGuardrails(%q) left %s disabled, want enabled", input, name)
+				}
+			}
+
+			input := "!" + name
+			got, err := ParseGuardrails(input)
+			if err != nil {
+				t.Fatalf("ParseGuardrails(%q) unexpected error: %v", input, err)
+			}
+			want := DefaultGuardrails(true)
+			*field(want) = false
+			if *got != *want {
+				t.Errorf("ParseGuardrails(%q)\n got  %+v\n want %+v", input, *got, *want)
+			}
+		})
+	}
+}
+
 func TestGuardrails_IsSafeQuery(t *testing.T) {
 	// Use static guardrails without cardinality limits (no TSDB client needed)
 	g := &Guardrails{
@@ -222,6 +285,8 @@ func TestGuardrails_IsSafeQuery(t *testing.T) {
 		// Rule 1: __name__ queries
 		`{__name__="http_requests_total"}`:      false,
 		`sum({__name__="http_requests_total"})`: false,
+		`{__name__=~"http_.*"}`:                 false,
+		`{__name__=~"http_.*", job="api"}`:      false,
 
 		// Rule 2: Vector selector without non-name label matchers
 		`http_requests_total`:                          false,
@@ -285,6 +350,160 @@ func TestGuardrails_IsSafeQuery(t *testing.T) {
 	}
 }
 
+func TestGuardrails_NameRegexSelector(t *testing.T) {
+	tests := []struct {
+		name          string
+		guardrails    *Guardrails
+		query         string
+		wantSafe      bool
+		wantGuardrail string
+		wantMessage   string
+	}{
+		{
+			name:          "regex __name__ rejected by disallow-explicit-name-label",
+			guardrails:    &Guardrails{DisallowExplicitNameLabel: true, RequireLabelMatcher: true},
+			query:         `{__name__=~"http_.*"}`,
+			wantGuardrail: GuardrailDisallowExplicitNameLabel,
+			wantMessage:   `query for selector {__name__=~"http_.*"} uses explicit __name__ label matcher, which is disallowed`,
+		},
+		{
+			name:          "regex __name__ without label matchers rejected by require-label-matcher",
+			guardrails:    &Guardrails{RequireLabelMatcher: true},
+			query:         `{__name__=~"http_.*"}`,
+			wantGuardrail: GuardrailRequireLabelMatcher,
+			wantMessage:   `query for selector {__name__=~"http_.*"} does not have any label matchers, which is required`,
+		},
+		{
+			name:       "regex __name__ with label matcher allowed when explicit name label is permitted",
+			guardrails: &Guardrails{RequireLabelMatcher: true},
+			query:      `{__name__=~"http_.*", job="api"}`,
+			wantSafe:   true,
+		},
+		{
+			name:          "named metric keeps metric name in message",
+			guardrails:    &Guardrails{RequireLabelMatcher: true},
+			query:         `http_requests_total`,
+			wantGuardrail: GuardrailRequireLabelMatcher,
+			wantMessage:   `query for metric "http_requests_total" does not have any label matchers, which is required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safe, err := tt.guardrails.IsSafeQuery(context.TODO(), tt.query, nil)
+			if safe != tt.wantSafe {
+				t.Fatalf("IsSafeQuery(%q) = %v (err: %v), want %v", tt.query, safe, err, tt.wantSafe)
+			}
+			if tt.wantSafe {
+				return
+			}
+
+			var gv *GuardrailViolation
+			if !errors.As(err, &gv) {
+				t.Fatalf("expected GuardrailViolation, got %T: %v", err, err)
+			}
+			if gv.Guardrail != tt.wantGuardrail {
+				t.Errorf("guardrail = %q, want %q", gv.Guardrail, tt.wantGuardrail)
+			}
+			if gv.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", gv.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGuardrails_AllMetricsSelector(t *testing.T) {
+	tests := []struct {
+		name          string
+		guardrails    *Guardrails
+		query         string
+		wantSafe      bool
+		wantGuardrail string
+		wantMessage   string
+	}{
+		{
+			name:          "non-empty blanket regex on __name__ selects all metrics",
+			guardrails:    &Guardrails{DisallowAllMetrics: true},
+			query:         `{__name__=~".+"}`,
+			wantGuardrail: GuardrailDisallowAllMetrics,
+			wantMessage: `query for selector {__name__=~".+"} selects all metrics, which is disallowed; ` +
+				`match the metric names with a narrower pattern such as {__name__=~"http_.*"} or add a label matcher such as job="api"`,
+		},
+		{
+			name:          "reported before the explicit __name__ guardrail",
+			guardrails:    &Guardrails{DisallowAllMetrics: true, DisallowExplicitNameLabel: true, RequireLabelMatcher: true},
+			query:         `count({__name__=~".+"})`,
+			wantGuardrail: GuardrailDisallowAllMetrics,
+			wantMessage: `query for selector {__name__=~".+"} selects all metrics, which is disallowed; ` +
+				`match the metric names with a narrower pattern such as {__name__=~"http_.*"} or add a label matcher such as job="api"`,
+		},
+		{
+			name:          "blanket regex on __name__ with only blanket label matchers",
+			guardrails:    &Guardrails{DisallowAllMetrics: true},
+			query:         `{__name__=~".*", job=~".+", namespace!=""}`,
+			wantGuardrail: GuardrailDisallowAllMetrics,
+			wantMessage: `query for selector {__name__=~".*", job=~".+", namespace!=""} selects all metrics, which is disallowed; ` +
+				`match the metric names with a narrower pattern such as {__name__=~"http_.*"} or add a label matcher such as job="api"`,
+		},
+		{
+			name:          "blanket regex on __name__ written differently",
+			guardrails:    &Guardrails{DisallowAllMetrics: true},
+			query:         `{__name__=~"^(.+)$"}`,
+			wantGuardrail: GuardrailDisallowAllMetrics,
+			wantMessage: `query for selector {__name__=~"^(.+)$"} selects all metrics, which is disallowed; ` +
+				`match the metric names with a narrower pattern such as {__name__=~"http_.*"} or add a label matcher such as job="api"`,
+		},
+		{
+			name:       "scoped __name__ regex with label matcher is allowed",
+			guardrails: &Guardrails{DisallowAllMetrics: true, RequireLabelMatcher: true},
+			query:      `{__name__=~"http_.*", job="api"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "blanket regex on __name__ narrowed by a label matcher is allowed",
+			guardrails: &Guardrails{DisallowAllMetrics: true},
+			query:      `{__name__=~".+", job="api"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "allowed when the guardrail is disabled",
+			guardrails: &Guardrails{},
+			query:      `{__name__=~".+"}`,
+			wantSafe:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safe, err := tt.guardrails.IsSafeQuery(context.TODO(), tt.query, nil)
+			if safe != tt.wantSafe {
+				t.Fatalf("IsSafeQuery(%q) = %v (err: %v), want %v", tt.query, safe, err, tt.wantSafe)
+			}
+			if tt.wantSafe {
+				return
+			}
+
+			var gv *GuardrailViolation
+			if !errors.As(err, &gv) {
+				t.Fatalf("expected GuardrailViolation, got %T: %v", err, err)
+			}
+			if gv.Guardrail != tt.wantGuardrail {
+				t.Errorf("guardrail = %q, want %q", gv.Guardrail, tt.wantGuardrail)
+			}
+			if gv.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", gv.Message, tt.wantMessage)
+			}
+		})
+	}
+
+	// {__name__=~".*"} matches the empty string too, so PromQL itself refuses it before
+	// any guardrail runs.
+	safe, err := (&Guardrails{DisallowAllMetrics: true}).IsSafeQuery(context.TODO(), `{__name__=~".*"}`, nil)
+	if safe || err == nil || !strings.Contains(err.Error(), "failed to parse query") {
+		t.Errorf(`IsSafeQuery({__name__=~".*"}) = %v, %v, want a parse error`, safe, err)
+	}
+}
+
 func TestGuardrails_DisabledRules(t *testing.T) {
 	t.Run("DisallowExplicitNameLabel disabled", func(t *testing.T) {
 		g := &Guardrails{
@@ -424,6 +643,11 @@ func TestExtractBlanketRegexLabels(t *testing.T) {
 			query:    `sum by (job) (rate(http_requests_total{pod=~".*"}[5m]))`,
 			expected: []string{"pod"},
 		},
+		{
+			name:     "blanket regex written differently",
+			query:    `http_requests_total{pod=~"^(.*)$", instance=~"web-1|.+"}`,
+			expected: []string{"pod", "instance"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -516,10 +740,108 @@ func TestGuardrails_MaxLabelCardinality(t *testing.T) {
 	})
 }
 
+func TestGuardrails_EstimateResultSeries(t *testing.T) {
+	threeSeries := []model.LabelSet{{"pod": "a"}, {"pod": "b"}, {"pod": "c"}}
+
+	tests := []struct {
+		name         string
+		query        string
+		max          uint64
+		series       []model.LabelSet
+		wantErr      bool
+		wantSelector string
+	}{
+		{
+			name:         "single selector over the limit is rejected",
+			query:        `up{job="api"}`,
+			max:          2,
+			series:       threeSeries,
+			wantErr:      true,
+			wantSelector: `up{job="api"}`,
+		},
+		{
+			name:         "parenthesized selector is estimated",
+			query:        `(up{job="api"})`,
+			max:          2,
+			series:       threeSeries,
+			wantErr:      true,
+			wantSelector: `up{job="api"}`,
+		},
+		{
+			name:         "single selector within the limit passes",
+			query:        `up{job="api"}`,
+			max:          3,
+			series:       threeSeries,
+			wantSelector: `up{job="api"}`,
+		},
+		{
+			name:   "aggregation is not estimated",
+			query:  `sum(up{job="api"})`,
+			max:    2,
+			series: threeSeries,
+		},
+		{
+			name:   "selector with offset is not estimated",
+			query:  `up{job="api"} offset 1h`,
+			max:    2,
+			series: threeSeries,
+		},
+		{
+			name:   "zero limit disables the check",
+			query:  `up{job="api"}`,
+			max:    0,
+			series: threeSeries,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockPrometheusAPI{series: tt.series}
+			g := &Guardrails{MaxResultSeries: tt.max}
+
+			err := g.EstimateResultSeries(context.TODO(), tt.query, time.Now().Add(-time.Hour), time.Now(), client)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EstimateResultSeries(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			if err != nil {
+				var gv *GuardrailViolation
+				if !errors.As(err, &gv) || gv.Guardrail != GuardrailMaxResultSeries {
+					t.Errorf("expected %q violation, got %v", GuardrailMaxResultSeries, err)
+				}
+			}
+
+			var gotSelector string
+			if len(client.seriesMatches) == 1 {
+				gotSelector = client.seriesMatches[0]
+			}
+			if gotSelector != tt.wantSelector {
+				t.Errorf("series API called with %q, want %q", gotSelector, tt.wantSelector)
+			}
+		})
+	}
+}
+
+func TestGuardrails_CheckResultSeries(t *testing.T) {
+	g := &Guardrails{MaxResultSeries: 10}
+	if err := g.CheckResultSeries(10); err != nil {
+		t.Errorf("expected result at the limit to pass, got %v", err)
+	}
+	if err := g.CheckResultSeries(11); err == nil {
+		t.Error("expected result over the limit to be rejected")
+	}
+
+	unlimited := &Guardrails{}
+	if err := unlimited.CheckResultSeries(1_000_000); err != nil {
+		t.Errorf("expected no limit when MaxResultSeries is 0, got %v", err)
+	}
+}
+
 // mockPrometheusAPI is a mock implementation of v1.API for testing
 type mockPrometheusAPI struct {
 	tsdbResult       v1.TSDBResult
 	availableMetrics []string
+	series           []model.LabelSet
+	seriesMatches    []string
 }
 
 func (m *mockPrometheusAPI) TSDB(ctx context.Context, opts ...v1.Option) (v1.TSDBResult, error) {
@@ -573,7 +895,8 @@ func (m *mockPrometheusAPI) Runtimeinfo(ctx context.Context) (v1.RuntimeinfoResu
 	return v1.RuntimeinfoResult{}, nil
 }
 func (m *mockPrometheusAPI) Series(ctx context.Context, matches []string, startTime, endTime time.Time, opts ...v1.Option) ([]model.LabelSet, v1.Warnings, error) {
-	return nil, nil, nil
+	m.seriesMatches = matches
+	return m.series, nil, nil
 }
 func (m *mockPrometheusAPI) Snapshot(ctx context.Context, skipHead bool) (v1.SnapshotResult, error) {
 	return v1.SnapshotResult{}, nil
@@ -828,3 +1151,462 @@ func TestGuardrails_MaxLabelCardinalityWithMockedTSDB(t *testing.T) {
 		}
 	})
 }
+
+func TestGuardrails_LimitMatchers(t *testing.T) {
+	alternation := func(n int) string {
+		values := make([]string, n)
+		for i := range values {
+			values[i] = fmt.Sprintf("pod-%d", i)
+		}
+		return strings.Join(values, "|")
+	}
+	manyMatchers := func(n int) string {
+		matchers := make([]string, n)
+		for i := range matchers {
+			matchers[i] = fmt.Sprintf(`l%d="v"`, i)
+		}
+		return strings.Join(matchers, ",")
+	}
+
+	tests := []struct {
+		name       string
+		guardrails *Guardrails
+		query      string
+		wantSafe   bool
+	}{
+		{
+			name:       "small alternation is allowed",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `up{pod=~"` + alternation(5) + `"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "alternation at the default limit is allowed",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `up{pod=~"` + alternation(int(DefaultMaxRegexAlternatives)) + `"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "large alternation is rejected",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `up{pod=~"` + alternation(500) + `"}`,
+		},
+		{
+			name:       "large grouped alternation is rejected",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `up{pod=~"(` + alternation(500) + `)"}`,
+		},
+		{
+			name:       "large negative alternation is rejected",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `up{pod!~"` + alternation(500) + `"}`,
+		},
+		{
+			name:       "large alternation in a nested selector is rejected",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `sum by (job) (rate(http_requests_total{job="api"}[5m])) / sum by (job) (rate(http_requests_total{pod=~"` + alternation(500) + `"}[5m]))`,
+		},
+		{
+			name:       "pipes inside character classes are not alternatives",
+			guardrails: &Guardrails{LimitMatchers: true, MaxRegexAlternatives: 2},
+			query:      `up{pod=~"a[|]b[|]c[|]d"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "escaped pipes are not alternatives",
+			guardrails: &Guardrails{LimitMatchers: true, MaxRegexAlternatives: 2},
+			query:      `up{pod=~"a\\|b\\|c\\|d"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "factored alternation is counted once per value",
+			guardrails: &Guardrails{LimitMatchers: true, MaxRegexAlternatives: 3},
+			query:      `up{pod=~"web-(1|2|3|4)"}`,
+		},
+		{
+			name:       "repetition is a single alternative",
+			guardrails: &Guardrails{LimitMatchers: true, MaxRegexAlternatives: 2},
+			query:      `up{pod=~"web-[0-9a-f]+"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "character classes are a single alternative",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `up{instance=~"node-[0-9][0-9]"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "custom alternation limit",
+			guardrails: &Guardrails{LimitMatchers: true, MaxRegexAlternatives: 2},
+			query:      `up{pod=~"a|b|c"}`,
+		},
+		{
+			name:       "too many matchers are rejected",
+			guardrails: &Guardrails{LimitMatchers: true},
+			query:      `up{` + manyMatchers(int(DefaultMaxMatchersPerSelector)+1) + `}`,
+		},
+		{
+			name:       "metric name does not count as a matcher",
+			guardrails: &Guardrails{LimitMatchers: true, MaxMatchersPerSelector: 1},
+			query:      `up{job="api"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "explicit __name__ matcher counts as a matcher",
+			guardrails: &Guardrails{LimitMatchers: true, MaxMatchersPerSelector: 1},
+			query:      `{__name__="up", job="api"}`,
+		},
+		{
+			name:       "disabled guardrail allows large alternations",
+			guardrails: &Guardrails{},
+			query:      `up{pod=~"` + alternation(500) + `", ` + manyMatchers(50) + `}`,
+			wantSafe:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safe, err := tt.guardrails.IsSafeQuery(context.Background(), tt.query, nil)
+			if safe != tt.wantSafe {
+				t.Fatalf("IsSafeQuery() = %v (err: %v), want %v", safe, err, tt.wantSafe)
+			}
+			if tt.wantSafe {
+				return
+			}
+			var violation *GuardrailViolation
+			if !errors.As(err, &violation) || violation.Guardrail != GuardrailLimitMatchers {
+				t.Errorf("expected %s violation, got %v", GuardrailLimitMatchers, err)
+			}
+		})
+	}
+}
+
+func TestGuardrails_LimitSubqueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		guardrails *Guardrails
+		query      string
+		wantSafe   bool
+	}{
+		{
+			name:       "subquery within the limits is allowed",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `max_over_time(rate(http_requests_total{job="api"}[5m])[1h:1m])`,
+			wantSafe:   true,
+		},
+		{
+			name:       "query without subqueries is allowed",
+			guardrails: &Guardrails{LimitSubqueries: true, MaxSubqueryRange: time.Minute},
+			query:      `max_over_time(http_requests_total{job="api"}[30d])`,
+			wantSafe:   true,
+		},
+		{
+			name:       "subquery without a resolution is rejected",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `max_over_time(rate(http_requests_total{job="api"}[5m])[1h:])`,
+		},
+		{
+			name:       "subquery with a fine resolution is rejected",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `max_over_time(rate(http_requests_total{job="api"}[5m])[1h:1s])`,
+		},
+		{
+			name:       "subquery with a long range is rejected",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `max_over_time(rate(http_requests_total{job="api"}[5m])[30d:1h])`,
+		},
+		{
+			name:       "subquery in a binary expression is checked",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `up{job="api"} * on (instance) max_over_time(up{job="api"}[7d:5m])`,
+		},
+		{
+			name:       "custom limits",
+			guardrails: &Guardrails{LimitSubqueries: true, MaxSubqueryRange: 30 * 24 * time.Hour, MinSubqueryStep: time.Second},
+			query:      `max_over_time(rate(http_requests_total{job="api"}[5m])[30d:1s])`,
+			wantSafe:   true,
+		},
+		{
+			name:       "nested subqueries within the limits are allowed",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `max_over_time(deriv(rate(http_requests_total{job="api"}[5m])[30m:1m])[1h:5m])`,
+			wantSafe:   true,
+		},
+		{
+			name:       "nested subquery ranges add up",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `max_over_time(deriv(rate(http_requests_total{job="api"}[5m])[20h:5m])[12h:5m])`,
+		},
+		{
+			name:       "nested subquery without a resolution is rejected",
+			guardrails: &Guardrails{LimitSubqueries: true},
+			query:      `max_over_time(deriv(rate(http_requests_total{job="api"}[5m])[30m:])[1h:5m])`,
+		},
+		{
+			name:       "disabled guardrail allows any subquery",
+			guardrails: &Guardrails{},
+			query:      `max_over_time(deriv(rate(http_requests_total{job="api"}[5m])[30d:])[30d:1s])`,
+			wantSafe:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safe, err := tt.guardrails.IsSafeQuery(context.Background(), tt.query, nil)
+			if safe != tt.wantSafe {
+				t.Fatalf("IsSafeQuery() = %v (err: %v), want %v", safe, err, tt.wantSafe)
+			}
+			if tt.wantSafe {
+				return
+			}
+			var violation *GuardrailViolation
+			if !errors.As(err, &violation) || violation.Guardrail != GuardrailLimitSubqueries {
+				t.Errorf("expected %s violation, got %v", GuardrailLimitSubqueries, err)
+			}
+		})
+	}
+}
+
+func TestGuardrails_MaxNestingDepth(t *testing.T) {
+	tests := []struct {
+		name       string
+		guardrails *Guardrails
+		query      string
+		wantSafe   bool
+	}{
+		{
+			name:       "shallow query is allowed",
+			guardrails: &Guardrails{LimitNestingDepth: true},
+			query:      `sum by (job) (rate(http_requests_total{job="api"}[5m]))`,
+			wantSafe:   true,
+		},
+		{
+			name:       "long chain of binary operations is allowed",
+			guardrails: &Guardrails{LimitNestingDepth: true},
+			query:      `a{job="api"} + b{job="api"} + c{job="api"} + d{job="api"} + e{job="api"} + f{job="api"} + g{job="api"} + h{job="api"} + i{job="api"} + j{job="api"}`,
+			wantSafe:   true,
+		},
+		{
+			name:       "nesting at the default limit is allowed",
+			guardrails: &Guardrails{LimitNestingDepth: true},
+			query:      `abs(abs(abs(abs(abs(abs(abs(abs(up{job="api"}))))))))`,
+			wantSafe:   true,
+		},
+		{
+			name:       "nesting beyond the default limit is rejected",
+			guardrails: &Guardrails{LimitNestingDepth: true},
+			query:      `abs(abs(abs(abs(abs(abs(abs(abs(abs(up{job="api"})))))))))`,
+		},
+		{
+			name:       "aggregations and subqueries count towards the depth",
+			guardrails: &Guardrails{LimitNestingDepth: true, MaxNestingDepth: 3},
+			query:      `max(max_over_time(sum(rate(http_requests_total{job="api"}[5m]))[1h:1m]))`,
+		},
+		{
+			name:       "depth is taken from the deepest operand",
+			guardrails: &Guardrails{LimitNestingDepth: true, MaxNestingDepth: 2},
+			query:      `up{job="api"} * on (instance) sum by (instance) (rate(http_requests_total{job="api"}[5m])) / abs(abs(abs(up{job="api"})))`,
+		},
+		{
+			name:       "custom limit",
+			guardrails: &Guardrails{LimitNestingDepth: true, MaxNestingDepth: 2},
+			query:      `sum by (job) (rate(http_requests_total{job="api"}[5m]))`,
+			wantSafe:   true,
+		},
+		{
+			name:       "disabled guardrail allows any nesting",
+			guardrails: &Guardrails{},
+			query:      `abs(abs(abs(abs(abs(abs(abs(abs(abs(abs(up{job="api"}))))))))))`,
+			wantSafe:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safe, err := tt.guardrails.IsSafeQuery(context.Background(), tt.query, nil)
+			if safe != tt.wantSafe {
+				t.Fatalf("IsSafeQuery() = %v (err: %v), want %v", safe, err, tt.wantSafe)
+			}
+			if tt.wantSafe {
+				return
+			}
+			var violation *GuardrailViolation
+			if !errors.As(err, &violation) || violation.Guardrail != GuardrailMaxNestingDepth {
+				t.Errorf("expected %s violation, got %v", GuardrailMaxNestingDepth, err)
+			}
+		})
+	}
+}
+
+func TestRegexAlternatives(t *testing.T) {
+	tests := map[string]int{
+		``:                1,
+		`api`:             1,
+		`a|b|c`:           3,
+		`(a|b)|c`:         3,
+		`web-(1|2|3)`:     3,
+		`[|]`:             1,
+		`a\|b`:            1,
+		`[a\]|]|b`:        2,
+		`(a|(b|c))|d|e`:   5,
+		`(a|b)-(c|d)`:     4,
+		`web-1?`:          2,
+		`web-[0-9]+`:      1,
+		`[^/]`:            1,
+		`x|.*`:            2,
+		`(a|b|c){2,5}`:    1,
+		`node-[0-9][0-9]`: 1,
+		`node-\d\d`:       1,
+		`[0-9]?`:          2,
+		`[ab]|[cd]`:       2,
+		`a|[bc]|.`:        3,
+		`[[:alpha:]]|b`:   2,
+		`\pL|\p{Greek}`:   2,
+		`\Q[a|b]\E|c`:     2,
+	}
+	for re, want := range tests {
+		if got := regexAlternatives(re); got != want {
+			t.Errorf("regexAlternatives(%q) = %d, want %d", re, got, want)
+		}
+	}
+}
+
+func TestIsBlanketRegex(t *testing.T) {
+	tests := map[string]bool{
+		`.*`:       true,
+		`.+`:       true,
+		`^.*$`:     true,
+		`(.+)`:     true,
+		`(?:.*)`:   true,
+		`.+.*`:     true,
+		`.*.*`:     true,
+		`[\s\S]*`:  true,
+		`foo|.*`:   true,
+		`(web|.+)`: true,
+		`web-.*`:   false,
+		`.*-web`:   false,
+		`.`:        false,
+		`.?`:       false,
+		`[a-z]*`:   false,
+		`api`:      false,
+		`(`:        false,
+	}
+	for re, want := range tests {
+		if got := isBlanketRegex(re); got != want {
+			t.Errorf("isBlanketRegex(%q) = %v, want %v", re, got, want)
+		}
+	}
+}
+
+func TestGuardrails_RequireLabelMatcherBinaryOperand(t *testing.T) {
+	g := &Guardrails{RequireLabelMatcher: true}
+	tests := []struct {
+		query       string
+		wantMessage string
+	}{
+		{
+			query:       `rate(a{job="x"}[5m]) / rate(a[5m])`,
+			wantMessage: `query for metric "a" does not have any label matchers, which is required; it appears in the right operand of "/": rate(a[5m])`,
+		},
+		{
+			query:       `sum(rate(a[5m])) / sum(rate(a{job="x"}[5m]))`,
+			wantMessage: `query for metric "a" does not have any label matchers, which is required; it appears in the left operand of "/": sum(rate(a[5m]))`,
+		},
+		{
+			query:       `b{job="x"} and on (instance) (a{job="x"} > c)`,
+			wantMessage: `query for metric "c" does not have any label matchers, which is required; it appears in the right operand of ">": c`,
+		},
+		{
+			query:       `sum(rate(a[5m]))`,
+			wantMessage: `query for metric "a" does not have any label matchers, which is required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			safe, err := g.IsSafeQuery(context.TODO(), tt.query, nil)
+			if safe {
+				t.Fatalf("IsSafeQuery(%q) = true, want false", tt.query)
+			}
+			var gv *GuardrailViolation
+			if !errors.As(err, &gv) || gv.Guardrail != GuardrailRequireLabelMatcher {
+				t.Fatalf("expected %s violation, got %v", GuardrailRequireLabelMatcher, err)
+			}
+			if gv.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", gv.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGuardrails_Relax(t *testing.T) {
+	base := DefaultGuardrails(true)
+	base.MaxResultSeries = 100
+
+	relaxed, err := base.Relax("!tsdb, max-result-series")
+	if err != nil {
+		t.Fatalf("Relax() unexpected error: %v", err)
+	}
+	want := *base
+	want.ForceMaxMetricCardinality = false
+	want.DisallowBlanketRegex = false
+	want.MaxResultSeries = 0
+	if *relaxed != want {
+		t.Errorf("Relax()\n got  %+v\n want %+v", *relaxed, want)
+	}
+	if !base.ForceMaxMetricCardinality || base.MaxResultSeries != 100 {
+		t.Error("Relax() modified the original guardrails")
+	}
+
+	if _, err := base.Relax("require-label-matcher,bogus"); err == nil {
+		t.Error("Relax() expected an error for an unknown guardrail")
+	}
+
+	var disabled *Guardrails
+	if got, err := disabled.Relax("require-label-matcher"); got != nil || err != nil {
+		t.Errorf("Relax() on nil guardrails = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestGuardrails_CheckQueryCardinalityAdvisory(t *testing.T) {
+	mock := &mockPrometheusAPI{
+		tsdbResult: v1.TSDBResult{
+			SeriesCountByMetricName: []v1.Stat{
+				{Name: "http_requests_total", Value: 12000},
+				{Name: "node_cpu_seconds_total", Value: 15000},
+				{Name: "up", Value: 3000},
+			},
+		},
+	}
+	g := &Guardrails{ForceMaxMetricCardinality: true, MaxMetricCardinality: 20000}
+
+	t.Run("lists high cardinality metrics, highest first", func(t *testing.T) {
+		advisories, err := g.CheckQuery(context.TODO(), `sum(rate(http_requests_total[5m])) / sum(rate(node_cpu_seconds_total[5m])) * sum(up)`, mock)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"high cardinality metrics, above 50% of the maximum allowed 20000 series: " +
+			"node_cpu_seconds_total (15000 series), http_requests_total (12000 series); add label matchers to select only the series needed"}
+		if !slices.Equal(advisories, want) {
+			t.Errorf("advisories = %q, want %q", advisories, want)
+		}
+	})
+
+	t.Run("no advisory below the threshold", func(t *testing.T) {
+		advisories, err := g.CheckQuery(context.TODO(), `up`, mock)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(advisories) != 0 {
+			t.Errorf("expected no advisories, got %q", advisories)
+		}
+	})
+
+	t.Run("rejected queries carry no advisory", func(t *testing.T) {
+		strict := &Guardrails{ForceMaxMetricCardinality: true, MaxMetricCardinality: 14000}
+		advisories, err := strict.CheckQuery(context.TODO(), `node_cpu_seconds_total + on() group_left http_requests_total`, mock)
+		if err == nil || advisories != nil {
+			t.Errorf("expected a violation without advisories, got %q, %v", advisories, err)
+		}
+	})
+}
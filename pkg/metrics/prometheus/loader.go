@@ -7,10 +7,12 @@ import (
 	"log/slog"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/api"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 )
 
 const (
@@ -18,23 +20,50 @@ const (
 	ListMetricsTimeRange = 1 * time.Hour
 	// DefaultQueryTimeout is the default timeout for Prometheus queries
 	DefaultQueryTimeout = 30 * time.Second
+	// instantQueryLookback matches the Prometheus lookback delta used to select
+	// samples for an instant query
+	instantQueryLookback = 5 * time.Minute
 )
 
-// Loader defines the interface for querying Prometheus
+// Loader defines the interface for querying Prometheus.
+// The query methods return the result type and value under "resultType" and "result",
+// and, when there are any, the backend warnings and guardrail advisories as []string
+// under "warnings" and "advisories".
 type Loader interface {
 	ListMetrics(ctx context.Context, nameRegex string) ([]string, error)
 	ExecuteRangeQuery(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]any, error)
 	ExecuteInstantQuery(ctx context.Context, query string, time time.Time) (map[string]any, error)
 	GetLabelNames(ctx context.Context, metricName string, start, end time.Time) ([]string, error)
-	GetLabelValues(ctx context.Context, label string, metricName string, start, end time.Time) ([]string, error)
+	GetLabelValues(ctx context.Context, label string, metricName string, start, end time.Time, limit uint64) ([]string, error)
 	GetSeries(ctx context.Context, matches []string, start, end time.Time) ([]map[string]string, error)
+	GetRules(ctx context.Context) (v1.RulesResult, error)
+	GetExternalLabels(ctx context.Context) (*ExternalLabels, error)
+	GetMetricMetadata(ctx context.Context, metric string) ([]v1.Metadata, error)
+	ListMetricMetadata(ctx context.Context, limit int) (map[string][]v1.Metadata, error)
+	GetActiveQueries(ctx context.Context) (*ActiveQueries, error)
+	GetLabelValueCounts(ctx context.Context) (map[string]uint64, error)
 }
 
 // RealLoader implements Loader using the Prometheus HTTP API.
 type RealLoader struct {
 	client     v1.API
+	apiClient  api.Client
 	guardrails *Guardrails
+	address    string
 	backend    string
+	logQueries bool
+
+	// nameTransform maps canonical metric names to backend names.
+	nameTransform MetricNameTransform
+
+	// splitMaxPoints enables splitting range queries too large for a single request,
+	// up to this number of points per series (0 = disabled).
+	splitMaxPoints int
+
+	// shared enables sharing in-flight queries and cached backend metadata with
+	// other loaders for the same backend and credential scope.
+	shared bool
+	scope  string
 }
 
 var _ Loader = (*RealLoader)(nil)
@@ -53,7 +82,9 @@ func NewPrometheusLoader(apiConfig api.Config) (*RealLoader, error) {
 	v1api := v1.NewAPI(client)
 	return &RealLoader{
 		client:     v1api,
+		apiClient:  client,
 		guardrails: DefaultGuardrails(true),
+		address:    apiConfig.Address,
 		backend:    backend,
 	}, nil
 }
@@ -64,42 +95,123 @@ func (p *RealLoader) WithGuardrails(g *Guardrails) *RealLoader {
 	return p
 }
 
+// ListMetrics returns the names of the metrics matching nameRegex. With a metric name
+// transform, the regex and the returned names are canonical names.
 func (p *RealLoader) ListMetrics(ctx context.Context, nameRegex string) ([]string, error) {
+	if !p.nameTransform.Enabled() {
+		return p.listMetricNames(ctx, nameRegex)
+	}
+
+	if nameRegex == "" {
+		nameRegex = ".*"
+	}
+	if _, err := regexp.Compile(nameRegex); err != nil {
+		return nil, fmt.Errorf("invalid name_regex %q: %w", nameRegex, err)
+	}
+	names, err := p.listMetricNames(ctx, p.nameTransform.toBackendRegex(nameRegex))
+	if err != nil {
+		return nil, err
+	}
+	metrics := make([]string, 0, len(names))
+	for _, name := range names {
+		if canonical, ok := p.nameTransform.fromBackend(name); ok {
+			metrics = append(metrics, canonical)
+		}
+	}
+	return metrics, nil
+}
+
+// nameMatchUnsupportedTTL is how long a backend that rejected a match[] selector is
+// remembered, so that a transient rejection does not disable match[] for good.
+const nameMatchUnsupportedTTL = 10 * time.Minute
+
+// nameMatchUnsupported records when the backends, by address, rejected a match[]
+// selector when listing metric names. Their metric names are filtered client-side.
+var nameMatchUnsupported = struct {
+	sync.Mutex
+	addresses map[string]time.Time
+}{addresses: make(map[string]time.Time)}
+
+// listMetricNames returns the backend names of the metrics matching nameRegex.
+// The regex is passed to the backend as a match[] selector so that it filters the names,
+// falling back to fetching all names for backends that reject it. The names are always
+// filtered client-side too, as some backends ignore match[] for label values.
+func (p *RealLoader) listMetricNames(ctx context.Context, nameRegex string) ([]string, error) {
 	var matches []string
+	var nameRe *regexp.Regexp
 
 	// For blanket regex patterns like ".*", use empty matcher to get all metrics to not get 4xx.
 	if nameRegex != ".*" && nameRegex != ".+" && nameRegex != "" {
-		if _, err := regexp.Compile(nameRegex); err != nil {
+		re, err := regexp.Compile("^(?:" + nameRegex + ")$")
+		if err != nil {
 			return nil, fmt.Errorf("invalid name_regex %q: %w", nameRegex, err)
 		}
 		if strings.ContainsAny(nameRegex, `"}`) {
 			return nil, fmt.Errorf("invalid name_regex %q: contains disallowed characters", nameRegex)
 		}
-		matcher := fmt.Sprintf("{__name__=~\"%s\"}", nameRegex) //nolint:gocritic
-		matches = []string{matcher}
+		nameRe = re
+
+		nameMatchUnsupported.Lock()
+		rejected, unsupported := nameMatchUnsupported.addresses[p.address]
+		if unsupported && time.Since(rejected) >= nameMatchUnsupportedTTL {
+			delete(nameMatchUnsupported.addresses, p.address)
+			unsupported = false
+		}
+		nameMatchUnsupported.Unlock()
+		if !unsupported {
+			matcher := fmt.Sprintf("{__name__=~\"%s\"}", nameRegex) //nolint:gocritic
+			matches = []string{matcher}
+		}
 	}
 
+	labelValues, err := p.fetchMetricNames(ctx, matches)
+	if err != nil && matches != nil && isRequestRejected(err) {
+		slog.Info("Backend rejected match[] when listing metric names, filtering client-side",
+			"backend", p.backend, "error", err)
+		// Only a backend refusing the request marks match[] as unsupported. An invalid
+		// query may come from the regex of this call alone and must not disable match[]
+		// for the other callers.
+		rejected := errorCode(err) == ErrorCodeRequestRejected
+		labelValues, err = p.fetchMetricNames(ctx, nil)
+		if err == nil && rejected {
+			nameMatchUnsupported.Lock()
+			nameMatchUnsupported.addresses[p.address] = time.Now()
+			nameMatchUnsupported.Unlock()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching metric names: %w", err)
+	}
+
+	metrics := make([]string, 0, len(labelValues))
+	for _, value := range labelValues {
+		if nameRe == nil || nameRe.MatchString(string(value)) {
+			metrics = append(metrics, string(value))
+		}
+	}
+	return metrics, nil
+}
+
+// fetchMetricNames returns the values of __name__ over the last ListMetricsTimeRange,
+// restricted to the series matching matches, if any.
+func (p *RealLoader) fetchMetricNames(ctx context.Context, matches []string) (model.LabelValues, error) {
 	start := time.Now()
 	labelValues, _, err := p.client.LabelValues(ctx, "__name__", matches, time.Now().Add(-ListMetricsTimeRange), time.Now())
 	duration := time.Since(start)
 	if err != nil {
+		err = classifyBackendError(err)
 		slog.Error("Backend call failed", "backend", p.backend, "operation", "list_metrics",
-			"duration_ms", duration.Milliseconds(), "error", err)
-		return nil, fmt.Errorf("error fetching metric names: %w", err)
+			"duration_ms", duration.Milliseconds(), "error_code", errorCode(err), "error", err)
+		return nil, err
 	}
 	slog.Debug("Backend call completed", "backend", p.backend, "operation", "list_metrics",
-		"duration_ms", duration.Milliseconds(), "result_count", len(labelValues))
-
-	metrics := make([]string, len(labelValues))
-	for i, value := range labelValues {
-		metrics[i] = string(value)
-	}
-	return metrics, nil
+		"duration_ms", duration.Milliseconds(), "match_count", len(matches), "result_count", len(labelValues))
+	return labelValues, nil
 }
 
 // ValidateMetricsExist validates that all metrics referenced in a query exist in Prometheus TSDB.
 // This is an always-on validation that should be called before executing any query.
-// It uses ListMetrics to fetch available metrics and ensures all metrics in the query exist.
+// It fetches the backend names of the available metrics and ensures all metrics in the query exist.
 func (p *RealLoader) ValidateMetricsExist(ctx context.Context, query string) error {
 	metricNames, err := ExtractMetricNames(query)
 	if err != nil {
@@ -112,7 +224,7 @@ func (p *RealLoader) ValidateMetricsExist(ctx context.Context, query string) err
 	}
 
 	// Use ".*" to match all metrics for validation
-	availableMetricsList, err := p.ListMetrics(ctx, ".*")
+	availableMetricsList, err := p.listMetricNames(ctx, ".*")
 	if err != nil {
 		return fmt.Errorf("failed to fetch available metrics: %w", err)
 	}
@@ -132,34 +244,82 @@ func (p *RealLoader) ValidateMetricsExist(ctx context.Context, query string) err
 }
 
 // validateQuery checks that all metrics in the query exist and that
-// the query passes any configured guardrails.
-func (p *RealLoader) validateQuery(ctx context.Context, query string) error {
+// the query passes any configured guardrails. It returns the advisories
+// of the guardrails about a query that passes them.
+func (p *RealLoader) validateQuery(ctx context.Context, query string) ([]string, error) {
 	if err := p.ValidateMetricsExist(ctx, query); err != nil {
 		slog.Warn("Query validation rejected", "reason", "metric-not-found", "query", query, "error", err)
-		return fmt.Errorf("metric validation failed: %w", err)
+		return nil, fmt.Errorf("metric validation failed: %w", err)
 	}
 
-	if p.guardrails != nil {
-		isSafe, err := p.guardrails.IsSafeQuery(ctx, query, p.client)
-		if err != nil {
-			guardrail := "unknown"
-			var gv *GuardrailViolation
-			if errors.As(err, &gv) {
-				guardrail = gv.Guardrail
-			}
-			slog.Warn("Guardrail rejected query", "guardrail", guardrail, "query", query, "error", err)
-			return fmt.Errorf("query validation failed: %w", err)
-		}
-		if !isSafe {
-			return fmt.Errorf("query is not safe")
+	if p.guardrails == nil {
+		return nil, nil
+	}
+	advisories, err := p.guardrails.CheckQuery(ctx, query, p.client)
+	if err != nil {
+		guardrail := "unknown"
+		var gv *GuardrailViolation
+		if errors.As(err, &gv) {
+			guardrail = gv.Guardrail
 		}
+		slog.Warn("Guardrail rejected query", "guardrail", guardrail, "query", query, "error", err)
+		return nil, fmt.Errorf("query validation failed: %w", err)
+	}
+	return advisories, nil
+}
+
+type noSeriesLimitKey struct{}
+
+// ContextWithoutSeriesLimit returns a context under which the max-result-series guardrail
+// is not enforced, for callers that reduce oversized results themselves.
+func ContextWithoutSeriesLimit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noSeriesLimitKey{}, true)
+}
+
+// estimateResultSeries rejects single-selector queries that would exceed the
+// max-result-series guardrail without executing them.
+func (p *RealLoader) estimateResultSeries(ctx context.Context, query string, start, end time.Time) error {
+	if p.guardrails == nil || ctx.Value(noSeriesLimitKey{}) != nil {
+		return nil
+	}
+	if err := p.guardrails.EstimateResultSeries(ctx, query, start, end, p.client); err != nil {
+		slog.Warn("Guardrail rejected query", "guardrail", GuardrailMaxResultSeries, "query", query, "error", err)
+		return fmt.Errorf("query validation failed: %w", err)
+	}
+	return nil
+}
+
+// checkResultSeries enforces the max-result-series guardrail on an executed query.
+func (p *RealLoader) checkResultSeries(ctx context.Context, query string, result model.Value) error {
+	if p.guardrails == nil || ctx.Value(noSeriesLimitKey{}) != nil {
+		return nil
+	}
+
+	count := 1
+	switch r := result.(type) {
+	case model.Matrix:
+		count = len(r)
+	case model.Vector:
+		count = len(r)
 	}
 
+	if err := p.guardrails.CheckResultSeries(count); err != nil {
+		slog.Warn("Guardrail rejected query result", "guardrail", GuardrailMaxResultSeries, "query", query, "error", err)
+		return fmt.Errorf("query result rejected: %w", err)
+	}
 	return nil
 }
 
 func (p *RealLoader) ExecuteRangeQuery(ctx context.Context, query string, queryStart, queryEnd time.Time, step time.Duration) (map[string]any, error) {
-	if err := p.validateQuery(ctx, query); err != nil {
+	query, err := p.nameTransform.RewriteQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	advisories, err := p.validateQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.estimateResultSeries(ctx, query, queryStart, queryEnd); err != nil {
 		return nil, err
 	}
 
@@ -169,52 +329,98 @@ func (p *RealLoader) ExecuteRangeQuery(ctx context.Context, query string, queryS
 		Step:  step,
 	}
 
+	split := p.splitMaxPoints > 0 && rangePoints(r) > MaxPointsPerQuery
+	if split && rangePoints(r) > p.splitMaxPoints {
+		return nil, fmt.Errorf("query range has %d points per series, which exceeds the maximum of %d; increase the step or shorten the range",
+			rangePoints(r), p.splitMaxPoints)
+	}
+
+	p.logQuery(query, queryStart, queryEnd, step)
+
 	start := time.Now()
-	result, warnings, err := p.client.QueryRange(ctx, query, r, v1.WithTimeout(DefaultQueryTimeout))
+	var result model.Value
+	var warnings v1.Warnings
+	if split {
+		result, warnings, err = p.splitRangeQuery(ctx, query, r)
+	} else {
+		key := p.dedupKey("range_query", query, queryStart, queryEnd, step)
+		result, warnings, err = p.sharedQuery(ctx, key, func(ctx context.Context) (model.Value, v1.Warnings, error) {
+			return p.client.QueryRange(ctx, query, r, v1.WithTimeout(DefaultQueryTimeout))
+		})
+	}
 	duration := time.Since(start)
 	if err != nil {
+		err = classifyBackendError(err)
 		slog.Error("Backend call failed", "backend", p.backend, "operation", "range_query",
-			"duration_ms", duration.Milliseconds(), "query", query, "error", err)
+			"duration_ms", duration.Milliseconds(), "query", query, "error_code", errorCode(err), "error", err)
 		return nil, fmt.Errorf("error executing range query: %w", err)
 	}
 	slog.Debug("Backend call completed", "backend", p.backend, "operation", "range_query",
 		"duration_ms", duration.Milliseconds(), "query", query)
 
+	if err := p.checkResultSeries(ctx, query, result); err != nil {
+		return nil, err
+	}
+
 	response := map[string]any{
 		"resultType": result.Type().String(),
-		"result":     result,
+		"result":     p.nameTransform.restoreValue(result),
 	}
 
 	if len(warnings) > 0 {
-		response["warnings"] = warnings
+		response["warnings"] = []string(warnings)
+	}
+	if len(advisories) > 0 {
+		response["advisories"] = advisories
 	}
 
 	return response, nil
 }
 
 func (p *RealLoader) ExecuteInstantQuery(ctx context.Context, query string, ts time.Time) (map[string]any, error) {
-	if err := p.validateQuery(ctx, query); err != nil {
+	query, err := p.nameTransform.RewriteQuery(query)
+	if err != nil {
 		return nil, err
 	}
+	advisories, err := p.validateQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.estimateResultSeries(ctx, query, ts.Add(-instantQueryLookback), ts); err != nil {
+		return nil, err
+	}
+
+	p.logQuery(query, ts, time.Time{}, 0)
 
 	start := time.Now()
-	result, warnings, err := p.client.Query(ctx, query, ts)
+	key := p.dedupKey("instant_query", query, ts, time.Time{}, 0)
+	result, warnings, err := p.sharedQuery(ctx, key, func(ctx context.Context) (model.Value, v1.Warnings, error) {
+		return p.client.Query(ctx, query, ts)
+	})
 	duration := time.Since(start)
 	if err != nil {
+		err = classifyBackendError(err)
 		slog.Error("Backend call failed", "backend", p.backend, "operation", "instant_query",
-			"duration_ms", duration.Milliseconds(), "query", query, "error", err)
+			"duration_ms", duration.Milliseconds(), "query", query, "error_code", errorCode(err), "error", err)
 		return nil, fmt.Errorf("error executing instant query: %w", err)
 	}
 	slog.Debug("Backend call completed", "backend", p.backend, "operation", "instant_query",
 		"duration_ms", duration.Milliseconds(), "query", query)
 
+	if err := p.checkResultSeries(ctx, query, result); err != nil {
+		return nil, err
+	}
+
 	response := map[string]any{
 		"resultType": result.Type().String(),
-		"result":     result,
+		"result":     p.nameTransform.restoreValue(result),
 	}
 
 	if len(warnings) > 0 {
-		response["warnings"] = warnings
+		response["warnings"] = []string(warnings)
+	}
+	if len(advisories) > 0 {
+		response["advisories"] = advisories
 	}
 
 	return response, nil
@@ -225,13 +431,18 @@ func (p *RealLoader) GetLabelNames(ctx context.Context, metricName string, start
 	if metricName != "" {
 		matches = []string{metricName}
 	}
+	matches, err := p.nameTransform.rewriteSelectors(matches)
+	if err != nil {
+		return nil, err
+	}
 
 	apiStart := time.Now()
 	labelNames, _, err := p.client.LabelNames(ctx, matches, start, end)
 	duration := time.Since(apiStart)
 	if err != nil {
+		err = classifyBackendError(err)
 		slog.Error("Backend call failed", "backend", p.backend, "operation", "label_names",
-			"duration_ms", duration.Milliseconds(), "error", err)
+			"duration_ms", duration.Milliseconds(), "error_code", errorCode(err), "error", err)
 		return nil, fmt.Errorf("error fetching label names: %w", err)
 	}
 	slog.Debug("Backend call completed", "backend", p.backend, "operation", "label_names",
@@ -242,37 +453,63 @@ func (p *RealLoader) GetLabelNames(ctx context.Context, metricName string, start
 	return labels, nil
 }
 
-func (p *RealLoader) GetLabelValues(ctx context.Context, label, metricName string, start, end time.Time) ([]string, error) {
+// GetLabelValues returns the values of a label. A non-zero limit is passed to the backend
+// to cap the number of values; backends that do not support it return all values.
+func (p *RealLoader) GetLabelValues(ctx context.Context, label, metricName string, start, end time.Time, limit uint64) ([]string, error) {
 	var matches []string
 	if metricName != "" {
 		matches = []string{metricName}
 	}
+	matches, err := p.nameTransform.rewriteSelectors(matches)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []v1.Option
+	if limit > 0 {
+		opts = append(opts, v1.WithLimit(limit))
+	}
 
 	apiStart := time.Now()
-	labelValues, _, err := p.client.LabelValues(ctx, label, matches, start, end)
+	labelValues, _, err := p.client.LabelValues(ctx, label, matches, start, end, opts...)
 	duration := time.Since(apiStart)
 	if err != nil {
+		err = classifyBackendError(err)
 		slog.Error("Backend call failed", "backend", p.backend, "operation", "label_values",
-			"duration_ms", duration.Milliseconds(), "label", label, "error", err)
+			"duration_ms", duration.Milliseconds(), "label", label, "error_code", errorCode(err), "error", err)
 		return nil, fmt.Errorf("error fetching label values: %w", err)
 	}
 	slog.Debug("Backend call completed", "backend", p.backend, "operation", "label_values",
 		"duration_ms", duration.Milliseconds(), "label", label, "result_count", len(labelValues))
 
-	values := make([]string, len(labelValues))
-	for i, value := range labelValues {
-		values[i] = string(value)
+	values := make([]string, 0, len(labelValues))
+	for _, value := range labelValues {
+		if label == model.MetricNameLabel && p.nameTransform.Enabled() {
+			name, ok := p.nameTransform.fromBackend(string(value))
+			if !ok {
+				continue
+			}
+			values = append(values, name)
+			continue
+		}
+		values = append(values, string(value))
 	}
 	return values, nil
 }
 
 func (p *RealLoader) GetSeries(ctx context.Context, matches []string, start, end time.Time) ([]map[string]string, error) {
+	matches, err := p.nameTransform.rewriteSelectors(matches)
+	if err != nil {
+		return nil, err
+	}
+
 	apiStart := time.Now()
 	seriesList, _, err := p.client.Series(ctx, matches, start, end)
 	duration := time.Since(apiStart)
 	if err != nil {
+		err = classifyBackendError(err)
 		slog.Error("Backend call failed", "backend", p.backend, "operation", "series",
-			"duration_ms", duration.Milliseconds(), "error", err)
+			"duration_ms", duration.Milliseconds(), "error_code", errorCode(err), "error", err)
 		return nil, fmt.Errorf("error fetching series: %w", err)
 	}
 	slog.Debug("Backend call completed", "backend", p.backend, "operation", "series",
@@ -280,6 +517,7 @@ func (p *RealLoader) GetSeries(ctx context.Context, matches []string, start, end
 
 	result := make([]map[string]string, len(seriesList))
 	for i, series := range seriesList {
+		series = model.LabelSet(p.nameTransform.restoreMetric(model.Metric(series)))
 		labels := make(map[string]string)
 		for k, v := range series {
 			labels[string(k)] = string(v)
@@ -288,3 +526,19 @@ func (p *RealLoader) GetSeries(ctx context.Context, matches []string, start, end
 	}
 	return result, nil
 }
+
+func (p *RealLoader) GetRules(ctx context.Context) (v1.RulesResult, error) {
+	apiStart := time.Now()
+	rules, err := p.client.Rules(ctx)
+	duration := time.Since(apiStart)
+	if err != nil {
+		err = classifyBackendError(err)
+		slog.Error("Backend call failed", "backend", p.backend, "operation", "rules",
+			"duration_ms", duration.Milliseconds(), "error_code", errorCode(err), "error", err)
+		return v1.RulesResult{}, fmt.Errorf("error fetching rules: %w", err)
+	}
+	slog.Debug("Backend call completed", "backend", p.backend, "operation", "rules",
+		"duration_ms", duration.Milliseconds(), "group_count", len(rules.Groups))
+
+	return rules, nil
+}
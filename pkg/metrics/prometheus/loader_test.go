@@ -2,8 +2,14 @@ package prometheus
 
 import (
 	"context"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 )
 
 func TestValidateMetricsExist(t *testing.T) {
@@ -129,3 +135,159 @@ func TestListMetrics_RejectsInvalidRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteRangeQuery_ContextWithoutSeriesLimit(t *testing.T) {
+	api := &rangeRecordingAPI{mockPrometheusAPI: mockPrometheusAPI{availableMetrics: []string{"up"}}, cutoff: time.Unix(1700003600, 0)}
+	loader := (&RealLoader{client: api}).WithGuardrails(&Guardrails{MaxResultSeries: 1})
+	start := time.Unix(1700000000, 0)
+
+	if _, err := loader.ExecuteRangeQuery(context.Background(), "up", start, start.Add(time.Hour), time.Minute); err == nil {
+		t.Fatal("expected the result to exceed the series limit")
+	}
+
+	result, err := loader.ExecuteRangeQuery(ContextWithoutSeriesLimit(context.Background()), "up", start, start.Add(time.Hour), time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if matrix := result["result"].(model.Matrix); len(matrix) != 2 {
+		t.Errorf("expected the full result of 2 series, got %d", len(matrix))
+	}
+}
+
+// nameValuesAPI records the match[] selectors of __name__ value requests. It rejects
+// selectors with an error of type rejectMatch when set, and ignores them otherwise,
+// returning all names.
+type nameValuesAPI struct {
+	mockPrometheusAPI
+	rejectMatch v1.ErrorType
+	matches     [][]string
+}
+
+func (m *nameValuesAPI) LabelValues(ctx context.Context, label string, matches []string, startTime, endTime time.Time, opts ...v1.Option) (model.LabelValues, v1.Warnings, error) {
+	m.matches = append(m.matches, matches)
+	if m.rejectMatch != "" && len(matches) > 0 {
+		return nil, nil, &v1.Error{Type: m.rejectMatch, Msg: "match[] is not supported"}
+	}
+	return m.mockPrometheusAPI.LabelValues(ctx, label, matches, startTime, endTime, opts...)
+}
+
+func TestListMetrics_NameMatch(t *testing.T) {
+	metrics := []string{"http_requests_total", "node_cpu_seconds_total", "up"}
+
+	t.Run("regex is sent as match[]", func(t *testing.T) {
+		api := &nameValuesAPI{mockPrometheusAPI: mockPrometheusAPI{availableMetrics: metrics}}
+		loader := &RealLoader{client: api, address: "http://match-supported"}
+
+		got, err := loader.ListMetrics(context.Background(), "http_.*")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := [][]string{{`{__name__=~"http_.*"}`}}; !reflect.DeepEqual(api.matches, want) {
+			t.Errorf("expected match[] %v, got %v", want, api.matches)
+		}
+		// The mock ignores match[], so the names must be filtered client-side too.
+		if want := []string{"http_requests_total"}; !slices.Equal(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("blanket regex sends no match[]", func(t *testing.T) {
+		api := &nameValuesAPI{mockPrometheusAPI: mockPrometheusAPI{availableMetrics: metrics}}
+		loader := &RealLoader{client: api, address: "http://match-supported"}
+
+		got, err := loader.ListMetrics(context.Background(), ".*")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.matches) != 1 || api.matches[0] != nil {
+			t.Errorf("expected a single request without match[], got %v", api.matches)
+		}
+		if !slices.Equal(got, metrics) {
+			t.Errorf("expected %v, got %v", metrics, got)
+		}
+	})
+
+	t.Run("rejected match[] falls back to client-side filtering", func(t *testing.T) {
+		api := &nameValuesAPI{mockPrometheusAPI: mockPrometheusAPI{availableMetrics: metrics}, rejectMatch: v1.ErrClient}
+		loader := &RealLoader{client: api, address: "http://match-unsupported"}
+
+		got, err := loader.ListMetrics(context.Background(), "node_.*|up")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"node_cpu_seconds_total", "up"}; !slices.Equal(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+		if len(api.matches) != 2 || api.matches[1] != nil {
+			t.Errorf("expected a rejected request and one without match[], got %v", api.matches)
+		}
+
+		// The backend is remembered as not supporting match[].
+		api.matches = nil
+		if _, err := loader.ListMetrics(context.Background(), "up"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.matches) != 1 || api.matches[0] != nil {
+			t.Errorf("expected a single request without match[], got %v", api.matches)
+		}
+
+		// Until the rejection expires.
+		nameMatchUnsupported.Lock()
+		nameMatchUnsupported.addresses[loader.address] = time.Now().Add(-nameMatchUnsupportedTTL)
+		nameMatchUnsupported.Unlock()
+		api.matches = nil
+		if _, err := loader.ListMetrics(context.Background(), "up"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{`{__name__=~"up"}`}; len(api.matches) == 0 || !slices.Equal(api.matches[0], want) {
+			t.Errorf("expected match[] to be tried again after the rejection expired, got %v", api.matches)
+		}
+	})
+
+	t.Run("invalid match[] does not disable it for other calls", func(t *testing.T) {
+		api := &nameValuesAPI{mockPrometheusAPI: mockPrometheusAPI{availableMetrics: metrics}, rejectMatch: v1.ErrBadData}
+		loader := &RealLoader{client: api, address: "http://match-invalid"}
+
+		got, err := loader.ListMetrics(context.Background(), "node_.*|up")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"node_cpu_seconds_total", "up"}; !slices.Equal(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+
+		api.matches = nil
+		if _, err := loader.ListMetrics(context.Background(), "up"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{`{__name__=~"up"}`}; len(api.matches) == 0 || !slices.Equal(api.matches[0], want) {
+			t.Errorf("expected the next call to send match[] again, got %v", api.matches)
+		}
+	})
+}
+
+// vectorAPI answers instant queries with an empty vector.
+type vectorAPI struct {
+	mockPrometheusAPI
+}
+
+func (m *vectorAPI) Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	return model.Vector{}, nil, nil
+}
+
+func TestExecuteInstantQuery_Advisories(t *testing.T) {
+	api := &vectorAPI{mockPrometheusAPI{
+		availableMetrics: []string{"up"},
+		tsdbResult:       v1.TSDBResult{SeriesCountByMetricName: []v1.Stat{{Name: "up", Value: 15000}}},
+	}}
+	loader := (&RealLoader{client: api}).WithGuardrails(&Guardrails{ForceMaxMetricCardinality: true, MaxMetricCardinality: 20000})
+
+	result, err := loader.ExecuteInstantQuery(context.Background(), "up", time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	advisories, _ := result["advisories"].([]string)
+	if len(advisories) != 1 || !strings.Contains(advisories[0], "up (15000 series)") {
+		t.Errorf("expected an advisory about up, got %v", result["advisories"])
+	}
+}
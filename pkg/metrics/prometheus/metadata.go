@@ -0,0 +1,92 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// metadataTTL is how long the metadata of a metric is cached.
+const metadataTTL = 10 * time.Minute
+
+type metadataEntry struct {
+	metadata []v1.Metadata
+	fetched  time.Time
+}
+
+var metadataCache = struct {
+	sync.Mutex
+	entries map[string]metadataEntry
+}{entries: make(map[string]metadataEntry)}
+
+// GetMetricMetadata returns the type, help and unit the targets report for a metric, one
+// entry per distinct combination. It returns no entries for a metric without metadata,
+// such as a recording rule or a metric that does not exist.
+// With a shared scope, the result is cached for metadataTTL.
+func (p *RealLoader) GetMetricMetadata(ctx context.Context, metric string) ([]v1.Metadata, error) {
+	metric = p.nameTransform.toBackend(metric)
+	key := p.address + "\x00" + p.scope + "\x00" + metric
+	if p.shared {
+		metadataCache.Lock()
+		entry, ok := metadataCache.entries[key]
+		metadataCache.Unlock()
+		if ok && time.Since(entry.fetched) < metadataTTL {
+			return entry.metadata, nil
+		}
+	}
+
+	apiStart := time.Now()
+	result, err := p.client.Metadata(ctx, metric, "")
+	duration := time.Since(apiStart)
+	if err != nil {
+		err = classifyBackendError(err)
+		slog.Error("Backend call failed", "backend", p.backend, "operation", "metadata",
+			"duration_ms", duration.Milliseconds(), "error_code", errorCode(err), "error", err)
+		return nil, fmt.Errorf("error fetching metadata: %w", err)
+	}
+	metadata := result[metric]
+	slog.Debug("Backend call completed", "backend", p.backend, "operation", "metadata",
+		"duration_ms", duration.Milliseconds(), "metric", metric, "entry_count", len(metadata))
+
+	if p.shared {
+		metadataCache.Lock()
+		metadataCache.entries[key] = metadataEntry{metadata: metadata, fetched: time.Now()}
+		metadataCache.Unlock()
+	}
+	return metadata, nil
+}
+
+// ListMetricMetadata returns the metadata of all metrics the targets report metadata for,
+// keyed by metric name, for at most limit metrics (0 = no limit). Metrics whose backend
+// names the name transform does not apply to are left out.
+func (p *RealLoader) ListMetricMetadata(ctx context.Context, limit int) (map[string][]v1.Metadata, error) {
+	backendLimit := ""
+	if limit > 0 {
+		backendLimit = strconv.Itoa(limit)
+	}
+
+	apiStart := time.Now()
+	result, err := p.client.Metadata(ctx, "", backendLimit)
+	duration := time.Since(apiStart)
+	if err != nil {
+		err = classifyBackendError(err)
+		slog.Error("Backend call failed", "backend", p.backend, "operation", "metadata",
+			"duration_ms", duration.Milliseconds(), "error_code", errorCode(err), "error", err)
+		return nil, fmt.Errorf("error fetching metadata: %w", err)
+	}
+	slog.Debug("Backend call completed", "backend", p.backend, "operation", "metadata",
+		"duration_ms", duration.Milliseconds(), "metric_count", len(result))
+
+	metadata := make(map[string][]v1.Metadata, len(result))
+	for name, entries := range result {
+		if name, ok := p.nameTransform.fromBackend(name); ok {
+			metadata[name] = entries
+		}
+	}
+	return metadata, nil
+}
@@ -0,0 +1,86 @@
+package prometheus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// metadataAPI serves fixed metric metadata, all of it for an empty metric, and counts
+// the requests for it.
+type metadataAPI struct {
+	mockPrometheusAPI
+	metadata map[string][]v1.Metadata
+	calls    atomic.Int32
+}
+
+func (m *metadataAPI) Metadata(ctx context.Context, metric, limit string) (map[string][]v1.Metadata, error) {
+	m.calls.Add(1)
+	if metric == "" {
+		return m.metadata, nil
+	}
+	if entries, ok := m.metadata[metric]; ok {
+		return map[string][]v1.Metadata{metric: entries}, nil
+	}
+	return map[string][]v1.Metadata{}, nil
+}
+
+func TestGetMetricMetadata(t *testing.T) {
+	api := &metadataAPI{metadata: map[string][]v1.Metadata{
+		"up": {{Type: v1.MetricTypeGauge, Help: "Whether the target is up."}},
+	}}
+
+	run := func(t *testing.T, loader *RealLoader, metric string, wantEntries int) {
+		t.Helper()
+		metadata, err := loader.GetMetricMetadata(context.Background(), metric)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(metadata) != wantEntries {
+			t.Errorf("got %d metadata entries for %s, want %d", len(metadata), metric, wantEntries)
+		}
+	}
+
+	t.Run("not cached by default", func(t *testing.T) {
+		api.calls.Store(0)
+		loader := &RealLoader{client: api, address: "http://prometheus:9090"}
+		run(t, loader, "up", 1)
+		run(t, loader, "up", 1)
+		run(t, loader, "missing", 0)
+		if calls := api.calls.Load(); calls != 3 {
+			t.Errorf("metadata fetched %d times, want 3", calls)
+		}
+	})
+
+	t.Run("cached per metric within a shared scope", func(t *testing.T) {
+		api.calls.Store(0)
+		run(t, (&RealLoader{client: api, address: "http://metadata:9090"}).WithSharedScope("scope"), "up", 1)
+		run(t, (&RealLoader{client: api, address: "http://metadata:9090"}).WithSharedScope("scope"), "up", 1)
+		if calls := api.calls.Load(); calls != 1 {
+			t.Errorf("metadata fetched %d times, want 1", calls)
+		}
+
+		run(t, (&RealLoader{client: api, address: "http://metadata:9090"}).WithSharedScope("scope"), "missing", 0)
+		if calls := api.calls.Load(); calls != 2 {
+			t.Errorf("metadata fetched %d times for another metric, want 2", calls)
+		}
+	})
+}
+
+func TestListMetricMetadata(t *testing.T) {
+	api := &metadataAPI{metadata: map[string][]v1.Metadata{
+		"federate:up":  {{Type: v1.MetricTypeGauge, Help: "Whether the target is up."}},
+		"local_metric": {{Type: v1.MetricTypeCounter}},
+	}}
+	loader := &RealLoader{client: api, nameTransform: MetricNameTransform{Prefix: "federate:"}}
+
+	metadata, err := loader.ListMetricMetadata(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(metadata) != 1 || len(metadata["up"]) != 1 {
+		t.Errorf("expected the metadata of up under its canonical name only, got %v", metadata)
+	}
+}
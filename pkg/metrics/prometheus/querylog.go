@@ -0,0 +1,69 @@
+package prometheus
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const redactedValue = "<redacted>"
+
+// sensitiveLabelRe matches label names whose matcher values are redacted from logged queries.
+var sensitiveLabelRe = regexp.MustCompile(`(?i)(secret|password|passwd|token|api_?key|credential|authorization)`)
+
+// WithQueryLogging enables logging of every executed query at info level.
+func (p *RealLoader) WithQueryLogging(enabled bool) *RealLoader {
+	p.logQueries = enabled
+	return p
+}
+
+// logQuery logs an executed query and its resolved time window when query logging is enabled.
+// A zero end marks an instant query evaluated at start.
+func (p *RealLoader) logQuery(query string, start, end time.Time, step time.Duration) {
+	if !p.logQueries {
+		return
+	}
+
+	attrs := []any{"backend", p.backend, "query", RedactQuery(query)}
+	if end.IsZero() {
+		attrs = append(attrs, "time", start.UTC().Format(time.RFC3339))
+	} else {
+		attrs = append(attrs,
+			"start", start.UTC().Format(time.RFC3339),
+			"end", end.UTC().Format(time.RFC3339),
+			"step", step.String())
+	}
+	slog.Info("Executing query", attrs...)
+}
+
+// RedactQuery replaces the values of label matchers on sensitive-looking labels
+// (e.g. token or password) so that queries can be logged safely.
+// Queries that cannot be parsed are returned unchanged, as they are rejected before execution.
+func RedactQuery(query string) string {
+	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
+	if err != nil {
+		return query
+	}
+
+	redacted := false
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		for _, m := range vs.LabelMatchers {
+			if sensitiveLabelRe.MatchString(m.Name) {
+				m.Value = redactedValue
+				redacted = true
+			}
+		}
+		return nil
+	})
+
+	if !redacted {
+		return query
+	}
+	return expr.String()
+}
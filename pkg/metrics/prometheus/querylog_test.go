@@ -0,0 +1,40 @@
+package prometheus
+
+import "testing"
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "query without sensitive labels is unchanged",
+			query: `sum by (pod) (rate(http_requests_total{job="api"}[5m]))`,
+			want:  `sum by (pod) (rate(http_requests_total{job="api"}[5m]))`,
+		},
+		{
+			name:  "sensitive label value is redacted",
+			query: `up{job="api", auth_token="abc123"}`,
+			want:  `up{auth_token="<redacted>",job="api"}`,
+		},
+		{
+			name:  "regex matcher on sensitive label is redacted",
+			query: `rate(logins_total{Password=~"hunter.*"}[5m])`,
+			want:  `rate(logins_total{Password=~"<redacted>"}[5m])`,
+		},
+		{
+			name:  "unparsable query is returned as is",
+			query: `up{api_key="x"`,
+			want:  `up{api_key="x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactQuery(tt.query); got != tt.want {
+				t.Errorf("RedactQuery(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
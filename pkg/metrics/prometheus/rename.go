@@ -0,0 +1,168 @@
+package prometheus
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+var (
+	metricNamePrefixRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	metricNameSuffixRe = regexp.MustCompile(`^[a-zA-Z0-9_:]*$`)
+)
+
+// MetricNameTransform maps the canonical metric names used in queries to the names the
+// backend stores them under, e.g. with the prefix added by federation. Names in results
+// are mapped back, so callers only ever see canonical names.
+type MetricNameTransform struct {
+	Prefix string
+	Suffix string
+}
+
+// Validate checks that the prefix and suffix only contain characters valid in metric names.
+func (t MetricNameTransform) Validate() error {
+	if t.Prefix != "" && !metricNamePrefixRe.MatchString(t.Prefix) {
+		return fmt.Errorf("invalid metric name prefix %q", t.Prefix)
+	}
+	if !metricNameSuffixRe.MatchString(t.Suffix) {
+		return fmt.Errorf("invalid metric name suffix %q", t.Suffix)
+	}
+	return nil
+}
+
+// Enabled reports whether the transform changes any name.
+func (t MetricNameTransform) Enabled() bool {
+	return t.Prefix != "" || t.Suffix != ""
+}
+
+// toBackend returns the backend name of a canonical metric name.
+func (t MetricNameTransform) toBackend(name string) string {
+	return t.Prefix + name + t.Suffix
+}
+
+// fromBackend returns the canonical name of a backend metric name, and false for names
+// without the prefix and suffix, which canonical names cannot refer to.
+func (t MetricNameTransform) fromBackend(name string) (string, bool) {
+	if len(name) < len(t.Prefix)+len(t.Suffix) || !strings.HasPrefix(name, t.Prefix) || !strings.HasSuffix(name, t.Suffix) {
+		return name, false
+	}
+	return name[len(t.Prefix) : len(name)-len(t.Suffix)], true
+}
+
+// toBackendRegex returns a regex matching the backend names of the canonical names
+// matched by re. Prometheus anchors label matcher regexes, so the result is anchored too.
+func (t MetricNameTransform) toBackendRegex(re string) string {
+	return regexp.QuoteMeta(t.Prefix) + "(?:" + re + ")" + regexp.QuoteMeta(t.Suffix)
+}
+
+// RewriteQuery rewrites the metric names of a query, given by name or by a __name__
+// matcher, to their backend names.
+func (t MetricNameTransform) RewriteQuery(query string) (string, error) {
+	if !t.Enabled() {
+		return query, nil
+	}
+	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	var rewriteErr error
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		if vs.Name != "" {
+			vs.Name = t.toBackend(vs.Name)
+		}
+		for i, m := range vs.LabelMatchers {
+			if m.Name != model.MetricNameLabel {
+				continue
+			}
+			value := t.toBackend(m.Value)
+			if m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp {
+				value = t.toBackendRegex(m.Value)
+			}
+			matcher, err := labels.NewMatcher(m.Type, m.Name, value)
+			if err != nil {
+				rewriteErr = fmt.Errorf("failed to rewrite metric name matcher %s: %w", m, err)
+				return err
+			}
+			vs.LabelMatchers[i] = matcher
+		}
+		return nil
+	})
+	if rewriteErr != nil {
+		return "", rewriteErr
+	}
+	return expr.String(), nil
+}
+
+// rewriteSelectors rewrites the metric names of series selectors, as passed in match[].
+func (t MetricNameTransform) rewriteSelectors(selectors []string) ([]string, error) {
+	if !t.Enabled() || len(selectors) == 0 {
+		return selectors, nil
+	}
+	rewritten := make([]string, len(selectors))
+	for i, selector := range selectors {
+		s, err := t.RewriteQuery(selector)
+		if err != nil {
+			return nil, err
+		}
+		rewritten[i] = s
+	}
+	return rewritten, nil
+}
+
+// restoreMetric returns metric with its name mapped back to the canonical name.
+// The metric is copied, as results may be shared with other callers.
+func (t MetricNameTransform) restoreMetric(metric model.Metric) model.Metric {
+	name, ok := metric[model.MetricNameLabel]
+	if !ok {
+		return metric
+	}
+	canonical, ok := t.fromBackend(string(name))
+	if !ok {
+		return metric
+	}
+	restored := metric.Clone()
+	restored[model.MetricNameLabel] = model.LabelValue(canonical)
+	return restored
+}
+
+// restoreValue returns a query result with the metric names of its series mapped back
+// to canonical names.
+func (t MetricNameTransform) restoreValue(value model.Value) model.Value {
+	if !t.Enabled() {
+		return value
+	}
+	switch v := value.(type) {
+	case model.Vector:
+		restored := make(model.Vector, len(v))
+		for i, sample := range v {
+			s := *sample
+			s.Metric = t.restoreMetric(sample.Metric)
+			restored[i] = &s
+		}
+		return restored
+	case model.Matrix:
+		restored := make(model.Matrix, len(v))
+		for i, series := range v {
+			s := *series
+			s.Metric = t.restoreMetric(series.Metric)
+			restored[i] = &s
+		}
+		return restored
+	}
+	return value
+}
+
+// WithMetricNameTransform sets the mapping between canonical and backend metric names.
+func (p *RealLoader) WithMetricNameTransform(t MetricNameTransform) *RealLoader {
+	p.nameTransform = t
+	return p
+}
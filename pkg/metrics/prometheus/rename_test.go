@@ -0,0 +1,211 @@
+package prometheus
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+func TestMetricNameTransform_RewriteQuery(t *testing.T) {
+	transform := MetricNameTransform{Prefix: "federate:", Suffix: "_fed"}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "metric name",
+			query: "up",
+			want:  "federate:up_fed",
+		},
+		{
+			name:  "metric name with matchers",
+			query: `up{job="api"}`,
+			want:  `federate:up_fed{job="api"}`,
+		},
+		{
+			name:  "function and range vector",
+			query: `sum by (job) (rate(http_requests_total{code="500"}[5m]))`,
+			want:  `sum by (job) (rate(federate:http_requests_total_fed{code="500"}[5m]))`,
+		},
+		{
+			name:  "binary expression",
+			query: "node_memory_MemFree_bytes / node_memory_MemTotal_bytes",
+			want:  "federate:node_memory_MemFree_bytes_fed / federate:node_memory_MemTotal_bytes_fed",
+		},
+		{
+			name:  "subquery",
+			query: "max_over_time(rate(up[1m])[1h:5m])",
+			want:  "max_over_time(rate(federate:up_fed[1m])[1h:5m])",
+		},
+		{
+			name:  "name equality matcher",
+			query: `{__name__="up",job="api"}`,
+			want:  `{__name__="federate:up_fed",job="api"}`,
+		},
+		{
+			name:  "name regex matcher",
+			query: `{__name__=~"node_.*|up"}`,
+			want:  `{__name__=~"federate:(?:node_.*|up)_fed"}`,
+		},
+		{
+			name:  "name negative regex matcher",
+			query: `{__name__!~"go_.*",job="api"}`,
+			want:  `{__name__!~"federate:(?:go_.*)_fed",job="api"}`,
+		},
+		{
+			name:  "label named like a metric is untouched",
+			query: `up{instance="up"}`,
+			want:  `federate:up_fed{instance="up"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transform.RewriteQuery(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RewriteQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("disabled transform keeps the query as is", func(t *testing.T) {
+		query := "rate(up[5m])  "
+		got, err := MetricNameTransform{}.RewriteQuery(query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != query {
+			t.Errorf("expected %q, got %q", query, got)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		if _, err := transform.RewriteQuery("rate(up[5m]"); err == nil {
+			t.Error("expected an error for an invalid query")
+		}
+	})
+}
+
+func TestMetricNameTransform_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform MetricNameTransform
+		wantErr   bool
+	}{
+		{name: "empty", transform: MetricNameTransform{}},
+		{name: "prefix and suffix", transform: MetricNameTransform{Prefix: "cluster:", Suffix: "_total"}},
+		{name: "prefix starting with a digit", transform: MetricNameTransform{Prefix: "1_"}, wantErr: true},
+		{name: "prefix with a dash", transform: MetricNameTransform{Prefix: "my-"}, wantErr: true},
+		{name: "suffix with a quote", transform: MetricNameTransform{Suffix: `"`}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.transform.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMetricNameTransform_RestoreValue(t *testing.T) {
+	transform := MetricNameTransform{Prefix: "federate:"}
+
+	sample := &model.Sample{Metric: model.Metric{model.MetricNameLabel: "federate:up", "job": "api"}, Value: 1}
+	restored := transform.restoreValue(model.Vector{
+		sample,
+		{Metric: model.Metric{model.MetricNameLabel: "other"}},
+		{Metric: model.Metric{"job": "api"}},
+	}).(model.Vector)
+
+	if got := restored[0].Metric[model.MetricNameLabel]; got != "up" {
+		t.Errorf("expected restored name 'up', got %q", got)
+	}
+	if got := restored[1].Metric[model.MetricNameLabel]; got != "other" {
+		t.Errorf("expected name without prefix to be kept, got %q", got)
+	}
+	if _, ok := restored[2].Metric[model.MetricNameLabel]; ok {
+		t.Error("expected series without a name to stay without one")
+	}
+	if got := sample.Metric[model.MetricNameLabel]; got != "federate:up" {
+		t.Errorf("expected the original result to be left unchanged, got %q", got)
+	}
+
+	matrix := transform.restoreValue(model.Matrix{
+		{Metric: model.Metric{model.MetricNameLabel: "federate:up"}},
+	}).(model.Matrix)
+	if got := matrix[0].Metric[model.MetricNameLabel]; got != "up" {
+		t.Errorf("expected restored name 'up' in matrix, got %q", got)
+	}
+}
+
+// renameAPI records the queries it receives and returns a series of the queried metric.
+type renameAPI struct {
+	mockPrometheusAPI
+	query string
+}
+
+func (m *renameAPI) Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	m.query = query
+	return model.Vector{{Metric: model.Metric{model.MetricNameLabel: "federate:up", "job": "api"}, Value: 1}}, nil, nil
+}
+
+func TestRealLoader_MetricNameTransform(t *testing.T) {
+	transform := MetricNameTransform{Prefix: "federate:"}
+	newLoader := func() (*RealLoader, *renameAPI) {
+		api := &renameAPI{mockPrometheusAPI: mockPrometheusAPI{
+			availableMetrics: []string{"federate:up", "federate:node_load1", "local_metric"},
+			series:           []model.LabelSet{{model.MetricNameLabel: "federate:up", "job": "api"}},
+		}}
+		return (&RealLoader{client: api}).WithMetricNameTransform(transform), api
+	}
+
+	t.Run("instant query", func(t *testing.T) {
+		loader, api := newLoader()
+		result, err := loader.ExecuteInstantQuery(context.Background(), `sum(up{job="api"})`, time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := `sum(federate:up{job="api"})`; api.query != want {
+			t.Errorf("expected backend query %q, got %q", want, api.query)
+		}
+		vector := result["result"].(model.Vector)
+		if got := vector[0].Metric[model.MetricNameLabel]; got != "up" {
+			t.Errorf("expected result name 'up', got %q", got)
+		}
+	})
+
+	t.Run("list metrics", func(t *testing.T) {
+		loader, _ := newLoader()
+		metrics, err := loader.ListMetrics(context.Background(), "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"up", "node_load1"}; !slices.Equal(metrics, want) {
+			t.Errorf("expected %v, got %v", want, metrics)
+		}
+	})
+
+	t.Run("series", func(t *testing.T) {
+		loader, api := newLoader()
+		series, err := loader.GetSeries(context.Background(), []string{`up{job="api"}`}, time.Now().Add(-time.Hour), time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{`federate:up{job="api"}`}; !slices.Equal(api.seriesMatches, want) {
+			t.Errorf("expected backend matches %v, got %v", want, api.seriesMatches)
+		}
+		if got := series[0][model.MetricNameLabel]; got != "up" {
+			t.Errorf("expected series name 'up', got %q", got)
+		}
+	})
+}
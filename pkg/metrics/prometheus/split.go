@@ -0,0 +1,121 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// MaxPointsPerQuery is the number of points per series above which Prometheus and
+	// Thanos reject a range query ("exceeded maximum resolution of 11,000 points").
+	MaxPointsPerQuery = 11000
+	// DefaultMaxSplitPoints is the default maximum number of points per series of a
+	// range query split into sub-range requests.
+	DefaultMaxSplitPoints = 10 * MaxPointsPerQuery
+	// splitConcurrency is the number of sub-range requests of a split query run at once.
+	splitConcurrency = 4
+)
+
+// WithRangeSplitting splits range queries with more than MaxPointsPerQuery points per
+// series into sub-range requests whose results are stitched together, up to maxPoints
+// points per series (0 = disabled).
+func (p *RealLoader) WithRangeSplitting(maxPoints int) *RealLoader {
+	p.splitMaxPoints = maxPoints
+	return p
+}
+
+// rangePoints returns the number of evaluation steps of a range query.
+func rangePoints(r v1.Range) int {
+	if r.Step <= 0 || r.End.Before(r.Start) {
+		return 0
+	}
+	return int(r.End.Sub(r.Start)/r.Step) + 1
+}
+
+// splitRange divides r into consecutive sub-ranges of at most MaxPointsPerQuery points,
+// keeping every evaluation step of r.
+func splitRange(r v1.Range) []v1.Range {
+	span := time.Duration(MaxPointsPerQuery-1) * r.Step
+	var ranges []v1.Range
+	for start := r.Start; !start.After(r.End); {
+		end := start.Add(span)
+		if end.After(r.End) {
+			end = r.End
+		}
+		ranges = append(ranges, v1.Range{Start: start, End: end, Step: r.Step})
+		start = end.Add(r.Step)
+	}
+	return ranges
+}
+
+// splitRangeQuery runs a range query as sub-range requests, at most splitConcurrency at a
+// time, and merges their matrices by series. Each sub-range is shared like a whole query.
+func (p *RealLoader) splitRangeQuery(ctx context.Context, query string, r v1.Range) (model.Value, v1.Warnings, error) {
+	ranges := splitRange(r)
+	slog.Debug("Splitting range query", "backend", p.backend, "query", query, "sub_ranges", len(ranges))
+
+	matrices := make([]model.Matrix, len(ranges))
+	warnings := make([]v1.Warnings, len(ranges))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(splitConcurrency)
+	for i, sub := range ranges {
+		g.Go(func() error {
+			key := p.dedupKey("range_query", query, sub.Start, sub.End, sub.Step)
+			value, w, err := p.sharedQuery(gctx, key, func(ctx context.Context) (model.Value, v1.Warnings, error) {
+				return p.client.QueryRange(ctx, query, sub, v1.WithTimeout(DefaultQueryTimeout))
+			})
+			if err != nil {
+				return err
+			}
+			matrix, ok := value.(model.Matrix)
+			if !ok {
+				return fmt.Errorf("unexpected result type %s for sub-range query", value.Type())
+			}
+			matrices[i], warnings[i] = matrix, w
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	var merged v1.Warnings
+	for _, w := range warnings {
+		for _, warning := range w {
+			if !slices.Contains(merged, warning) {
+				merged = append(merged, warning)
+			}
+		}
+	}
+	return mergeMatrices(matrices), merged, nil
+}
+
+// mergeMatrices stitches the matrices of consecutive sub-ranges together, appending the
+// samples of each series in sub-range order.
+func mergeMatrices(matrices []model.Matrix) model.Matrix {
+	series := make(map[model.Fingerprint]*model.SampleStream)
+	var result model.Matrix
+	for _, matrix := range matrices {
+		for _, s := range matrix {
+			fp := s.Metric.Fingerprint()
+			stream, ok := series[fp]
+			if !ok {
+				stream = &model.SampleStream{Metric: s.Metric}
+				series[fp] = stream
+				result = append(result, stream)
+			}
+			stream.Values = append(stream.Values, s.Values...)
+			stream.Histograms = append(stream.Histograms, s.Histograms...)
+		}
+	}
+	sort.Sort(result)
+	return result
+}
@@ -0,0 +1,141 @@
+package prometheus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// rangeRecordingAPI records the ranges of range queries and returns a sample per step
+// for a series present over the whole range and one that only exists before cutoff.
+type rangeRecordingAPI struct {
+	mockPrometheusAPI
+	cutoff time.Time
+
+	mu     sync.Mutex
+	ranges []v1.Range
+}
+
+func (m *rangeRecordingAPI) QueryRange(ctx context.Context, query string, r v1.Range, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	m.mu.Lock()
+	m.ranges = append(m.ranges, r)
+	m.mu.Unlock()
+
+	always := &model.SampleStream{Metric: model.Metric{"pod": "always"}}
+	ended := &model.SampleStream{Metric: model.Metric{"pod": "ended"}}
+	for ts := r.Start; !ts.After(r.End); ts = ts.Add(r.Step) {
+		sample := model.SamplePair{Timestamp: model.TimeFromUnixNano(ts.UnixNano()), Value: 1}
+		always.Values = append(always.Values, sample)
+		if ts.Before(m.cutoff) {
+			ended.Values = append(ended.Values, sample)
+		}
+	}
+
+	matrix := model.Matrix{always}
+	if len(ended.Values) > 0 {
+		matrix = append(matrix, ended)
+	}
+	return matrix, v1.Warnings{"partial response"}, nil
+}
+
+func TestExecuteRangeQuery_Split(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	step := time.Second
+
+	t.Run("large range is split and stitched", func(t *testing.T) {
+		const points = 2*MaxPointsPerQuery + 500
+		end := start.Add((points - 1) * step)
+		api := &rangeRecordingAPI{
+			mockPrometheusAPI: mockPrometheusAPI{availableMetrics: []string{"up"}},
+			cutoff:            start.Add(MaxPointsPerQuery * step),
+		}
+		loader := (&RealLoader{client: api}).WithRangeSplitting(DefaultMaxSplitPoints)
+
+		result, err := loader.ExecuteRangeQuery(context.Background(), "up", start, end, step)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(api.ranges) != 3 {
+			t.Fatalf("backend called %d times, want 3", len(api.ranges))
+		}
+		for _, r := range api.ranges {
+			if n := rangePoints(r); n > MaxPointsPerQuery {
+				t.Errorf("sub-range %v-%v has %d points, want at most %d", r.Start, r.End, n, MaxPointsPerQuery)
+			}
+		}
+
+		matrix := result["result"].(model.Matrix)
+		if len(matrix) != 2 {
+			t.Fatalf("expected 2 series, got %d", len(matrix))
+		}
+		for _, series := range matrix {
+			want := points
+			if series.Metric["pod"] == "ended" {
+				want = MaxPointsPerQuery
+			}
+			if len(series.Values) != want {
+				t.Errorf("series %v has %d samples, want %d", series.Metric, len(series.Values), want)
+			}
+			for i := 1; i < len(series.Values); i++ {
+				if series.Values[i].Timestamp-series.Values[i-1].Timestamp != model.Time(step.Milliseconds()) {
+					t.Fatalf("series %v is not contiguous at sample %d", series.Metric, i)
+				}
+			}
+		}
+		if warnings := result["warnings"].([]string); len(warnings) != 1 {
+			t.Errorf("warnings = %v, want a single deduplicated warning", warnings)
+		}
+	})
+
+	t.Run("range exceeding the split limit is rejected", func(t *testing.T) {
+		api := &rangeRecordingAPI{mockPrometheusAPI: mockPrometheusAPI{availableMetrics: []string{"up"}}}
+		loader := (&RealLoader{client: api}).WithRangeSplitting(2 * MaxPointsPerQuery)
+
+		_, err := loader.ExecuteRangeQuery(context.Background(), "up", start, start.Add(3*MaxPointsPerQuery*step), step)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if len(api.ranges) != 0 {
+			t.Errorf("backend called %d times, want 0", len(api.ranges))
+		}
+	})
+
+	t.Run("splitting is disabled by default", func(t *testing.T) {
+		api := &rangeRecordingAPI{mockPrometheusAPI: mockPrometheusAPI{availableMetrics: []string{"up"}}}
+		loader := &RealLoader{client: api}
+
+		if _, err := loader.ExecuteRangeQuery(context.Background(), "up", start, start.Add(3*MaxPointsPerQuery*step), step); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.ranges) != 1 {
+			t.Errorf("backend called %d times, want 1", len(api.ranges))
+		}
+	})
+}
+
+func TestSplitRange(t *testing.T) {
+	start := time.Unix(0, 0)
+	r := v1.Range{Start: start, End: start.Add(25000 * time.Minute), Step: time.Minute}
+
+	ranges := splitRange(r)
+	total := 0
+	next := r.Start
+	for _, sub := range ranges {
+		if !sub.Start.Equal(next) {
+			t.Errorf("sub-range starts at %v, want %v", sub.Start, next)
+		}
+		total += rangePoints(sub)
+		next = sub.End.Add(r.Step)
+	}
+	if total != rangePoints(r) {
+		t.Errorf("sub-ranges cover %d points, want %d", total, rangePoints(r))
+	}
+	if len(ranges) != 3 {
+		t.Errorf("got %d sub-ranges, want 3", len(ranges))
+	}
+}
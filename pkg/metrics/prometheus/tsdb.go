@@ -0,0 +1,69 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+const (
+	// labelValueCountsTTL is how long the label value counts of the TSDB stats are cached.
+	labelValueCountsTTL = 5 * time.Minute
+	// labelValueCountsLimit is the number of label names the TSDB stats are requested for,
+	// above the label names of typical backends, which default to reporting only 10.
+	labelValueCountsLimit = 1000
+)
+
+type labelValueCountsEntry struct {
+	counts  map[string]uint64
+	fetched time.Time
+}
+
+var labelValueCountsCache = struct {
+	sync.Mutex
+	entries map[string]labelValueCountsEntry
+}{entries: make(map[string]labelValueCountsEntry)}
+
+// GetLabelValueCounts returns the number of distinct values of each label name across
+// all series of the TSDB head, from the same /api/v1/status/tsdb endpoint the cardinality
+// guardrails use. The counts are approximate, as they cover the head block only, and
+// fail on backends without the endpoint, such as Thanos Querier before v0.40.0.
+// With a shared scope, the result is cached for labelValueCountsTTL.
+func (p *RealLoader) GetLabelValueCounts(ctx context.Context) (map[string]uint64, error) {
+	key := p.address + "\x00" + p.scope
+	if p.shared {
+		labelValueCountsCache.Lock()
+		entry, ok := labelValueCountsCache.entries[key]
+		labelValueCountsCache.Unlock()
+		if ok && time.Since(entry.fetched) < labelValueCountsTTL {
+			return entry.counts, nil
+		}
+	}
+
+	apiStart := time.Now()
+	result, err := p.client.TSDB(ctx, v1.WithLimit(labelValueCountsLimit))
+	duration := time.Since(apiStart)
+	if err != nil {
+		err = classifyBackendError(err)
+		slog.Error("Backend call failed", "backend", p.backend, "operation", "tsdb_stats",
+			"duration_ms", duration.Milliseconds(), "error_code", errorCode(err), "error", err)
+		return nil, fmt.Errorf("error fetching TSDB stats: %w", err)
+	}
+	counts := make(map[string]uint64, len(result.LabelValueCountByLabelName))
+	for _, stat := range result.LabelValueCountByLabelName {
+		counts[stat.Name] = stat.Value
+	}
+	slog.Debug("Backend call completed", "backend", p.backend, "operation", "tsdb_stats",
+		"duration_ms", duration.Milliseconds(), "label_count", len(counts))
+
+	if p.shared {
+		labelValueCountsCache.Lock()
+		labelValueCountsCache.entries[key] = labelValueCountsEntry{counts: counts, fetched: time.Now()}
+		labelValueCountsCache.Unlock()
+	}
+	return counts, nil
+}
@@ -0,0 +1,59 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync/atomic"
+	"testing"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// tsdbAPI serves fixed TSDB stats, or fails like backends without the endpoint, and
+// counts the requests for them.
+type tsdbAPI struct {
+	mockPrometheusAPI
+	err   error
+	calls atomic.Int32
+}
+
+func (m *tsdbAPI) TSDB(ctx context.Context, opts ...v1.Option) (v1.TSDBResult, error) {
+	m.calls.Add(1)
+	return m.tsdbResult, m.err
+}
+
+func TestGetLabelValueCounts(t *testing.T) {
+	api := &tsdbAPI{mockPrometheusAPI: mockPrometheusAPI{tsdbResult: v1.TSDBResult{
+		LabelValueCountByLabelName: []v1.Stat{{Name: "pod", Value: 1200}, {Name: "namespace", Value: 40}},
+	}}}
+
+	t.Run("counts per label", func(t *testing.T) {
+		counts, err := (&RealLoader{client: api, address: "http://prometheus:9090"}).GetLabelValueCounts(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := map[string]uint64{"pod": 1200, "namespace": 40}; !maps.Equal(counts, want) {
+			t.Errorf("counts = %v, want %v", counts, want)
+		}
+	})
+
+	t.Run("cached within a shared scope", func(t *testing.T) {
+		api.calls.Store(0)
+		for range 2 {
+			if _, err := (&RealLoader{client: api, address: "http://tsdb:9090"}).WithSharedScope("scope").GetLabelValueCounts(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if calls := api.calls.Load(); calls != 1 {
+			t.Errorf("TSDB stats fetched %d times, want 1", calls)
+		}
+	})
+
+	t.Run("endpoint unavailable", func(t *testing.T) {
+		failing := &tsdbAPI{err: errors.New("404 page not found")}
+		if _, err := (&RealLoader{client: failing, address: "http://thanos:9090"}).GetLabelValueCounts(context.Background()); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package prometheus
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,10 +10,29 @@ import (
 	"github.com/prometheus/common/model"
 )
 
-func ParseTimestamp(timestamp string) (time.Time, error) {
+type nowKey struct{}
+
+// ContextWithNow returns a context under which NOW resolves to the given time instead of
+// the server's wall clock, e.g. to replay a session at the time it was recorded.
+func ContextWithNow(ctx context.Context, now time.Time) context.Context {
+	return context.WithValue(ctx, nowKey{}, now)
+}
+
+// Now returns the time NOW resolves to under ctx: the time set with ContextWithNow,
+// or the current server time.
+func Now(ctx context.Context) time.Time {
+	if now, ok := ctx.Value(nowKey{}).(time.Time); ok {
+		return now
+	}
+	return time.Now()
+}
+
+// ParseTimestamp parses an RFC3339 or Unix timestamp, NOW or a NOW-relative expression
+// (NOW±duration). NOW is resolved with Now(ctx).
+func ParseTimestamp(ctx context.Context, timestamp string) (time.Time, error) {
 	// Handle NOW keyword (case-insensitive)
 	if strings.EqualFold(timestamp, "NOW") {
-		return time.Now(), nil
+		return Now(ctx), nil
 	}
 
 	// Handle relative time expressions like NOW-5m, NOW+1h
@@ -33,9 +53,9 @@ func ParseTimestamp(timestamp string) (time.Time, error) {
 
 			offset := time.Duration(duration)
 			if isNegative {
-				return time.Now().Add(-offset), nil
+				return Now(ctx).Add(-offset), nil
 			}
-			return time.Now().Add(offset), nil
+			return Now(ctx).Add(offset), nil
 		}
 	}
 
@@ -145,7 +145,7 @@ func TestParseTimestamp(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := ParseTimestamp(tt.input)
+			result, err := ParseTimestamp(t.Context(), tt.input)
 
 			if tt.expectError {
 				if err == nil {
@@ -164,3 +164,31 @@ func TestParseTimestamp(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTimestamp_InjectedNow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := ContextWithNow(t.Context(), now)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{input: "NOW", want: now},
+		{input: "now-5m", want: now.Add(-5 * time.Minute)},
+		{input: "NOW+1h", want: now.Add(time.Hour)},
+		{input: "2023-06-01T00:00:00Z", want: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(ctx, tt.input)
+		if err != nil {
+			t.Fatalf("ParseTimestamp(%q) unexpected error: %v", tt.input, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimestamp(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if got := Now(t.Context()); time.Since(got) > 2*time.Second {
+		t.Errorf("Now() without an injected time = %v, want the current time", got)
+	}
+}
@@ -20,9 +20,13 @@ If the user mentions a specific alert by name, use get_alerts with a filter to r
 
 **STEP 2: Call get_label_names for the metric you found**
 - Discover available labels for filtering (namespace, pod, service, etc.)
+- Use inspect_metric when a generic metric name may be exported by several jobs with different meanings
+- Use get_metric_metadata to tell counters from gauges and learn what a metric measures
+- Use get_scrape_interval to pick rate() windows of at least 4 times the scrape interval
 
 **STEP 3: Call get_label_values if you need specific filter values**
 - Find exact label values (e.g., actual namespace names, pod names)
+- Use get_labels_overview to get the values of several labels in one call
 
 **STEP 4: Execute your query using the EXACT metric name from Step 1**
 - Use execute_instant_query for current state questions
@@ -39,7 +43,9 @@ If the user mentions a specific alert by name, use get_alerts with a filter to r
 ## Query Type Selection
 
 - **execute_instant_query**: Current values, point-in-time snapshots, "right now" questions
-- **execute_range_query**: Trends over time, rate calculations, historical analysis`
+- **execute_range_query**: Trends over time, rate calculations, historical analysis
+- **slo_compliance**: SLI/SLO questions, comparing good to total events against a target
+- **evaluate_condition**: How long a threshold or alert condition held over a time range`
 
 	ListMetricsPrompt = `MANDATORY FIRST STEP: List all available metric names in Prometheus.
 
@@ -66,6 +72,14 @@ After calling this tool:
 2. Use the EXACT metric name found in subsequent queries
 3. If no relevant metric exists, inform the user`
 
+	ListMetricGroupsPrompt = `Get a map of the metric catalog: metric names grouped by their name prefix, with counts and examples.
+
+Use this when you do not know how the metrics of this environment are named, before guessing a
+'name_regex' for list_metrics. Groups are formed by the first name segment (e.g. 'node_', 'kube_',
+'container_'); pass one of them as 'prefix' to split it further (e.g. 'kube_pod_', 'kube_node_').
+
+Then call list_metrics with a regex for the relevant group (e.g. 'kube_pod_.*') to get the exact names.`
+
 	ExecuteInstantQueryPrompt = `Execute a PromQL instant query to get current/point-in-time values.
 
 PREREQUISITE: You MUST call list_metrics first to verify the metric exists
@@ -75,6 +89,21 @@ WHEN TO USE:
 - Point-in-time snapshots: "How many pods are running?"
 - Latest values: "Which pods are in Pending state?"
 
+GROUPING: For per-label breakdowns (e.g., "errors by namespace"), set 'group_by' to the label and
+optionally 'group_agg' (sum, max, min, avg, count) to get one value per label value.
+
+SERIES DISCOVERY: To learn which series a query returns without their values (e.g. which pods
+are failing), set 'labels_only' for a smaller result.
+
+TOP-N: topk and bottomk do not order their result; set 'rank' to get the series sorted by value,
+highest first, each with its 'rank' and the number ranked in 'rankedCount'.
+
+SPARSE METRICS: If a metric is scraped or pushed rarely and the query returns nothing, set 'nearest'
+to get the latest values from the preceding hour; 'nearest' in the output tells when that happened.
+
+SINGLE VALUES: When the query returns a single number, such as count(up{job="api"}), 'scalarValue'
+holds it; read the answer from there.
+
 The 'query' parameter MUST use metric names that were returned by list_metrics.`
 
 	ExecuteRangeQueryPrompt = `Execute a PromQL range query to get time-series data over a period.
@@ -89,6 +118,11 @@ WHEN TO USE:
 TIME PARAMETERS:
 - 'duration': Look back from now (e.g., "5m", "1h", "24h")
 - 'step': Data point resolution (e.g., "1m" for 1-hour duration, "5m" for 24-hour duration)
+- 'target_points': Instead of 'step', the number of data points per series wanted; the step is computed from the time range and returned as 'step'
+
+LARGE RESULTS:
+- Set 'page_size' to get the series a page at a time, then pass the returned 'nextToken' as 'page_token' with the same query until no nextToken is returned; the query must still return no more series than the server allows, use 'sampling' or aggregate beyond that
+- For trend questions ("Is memory growing?", "Was there a spike?"), set 'describe_shape' to get the trend, extremes, largest spike and period of each series instead of its values
 
 The 'query' parameter MUST use metric names that were returned by list_metrics.`
 
@@ -101,25 +135,111 @@ Use the show_timeseries as the last tool call after all the other Prometheus too
 TIME PARAMETERS:
 - 'duration': Look back from now (e.g., "5m", "1h", "24h")
 - 'step': Data point resolution (e.g., "1m" for 1-hour duration, "5m" for 24-hour duration)
+- 'target_points': Instead of 'step', the number of points the chart should show per series (e.g., its width in pixels); the step is computed from the time range and returned with the chart data
 - 'title': A descriptive chart title (e.g., "API Error Rate Over Last Hour")
 - 'description': An explanation of the chart's meaning or context (e.g., "Shows the rate of HTTP 5xx errors per second, broken down by pod")
 
 The 'query' parameter MUST be a range query and must use metric names that were returned by list_metrics.`
 
+	QueryHeatmapPrompt = `Count the observations of a histogram per time step and bucket, for rendering as a heatmap.
+
+WHEN TO USE:
+- To show how a latency or size distribution changes over time, e.g. request durations from a *_bucket metric
+- Use execute_range_query with histogram_quantile instead when a few percentiles are enough
+
+The result is a grid: 'counts[i][j]' is the number of observations during the step ending at 'timestamps[i]'
+that fall into 'buckets[j]', i.e. above the previous bucket bound and up to the 'le' bound.
+Choose a 'step' of at least twice the scrape interval (e.g., '1m' or more), as counts are computed with increase() over one step.
+Only classic histograms with an 'le' label are supported.`
+
+	LabelCardinalityTrendPrompt = `Count the distinct values of a label of a metric at each step of a time range, to find when its cardinality grew.
+
+WHEN TO USE:
+- "When did the number of series of this metric explode?", after get_series or get_label_values reported many values
+- To find the label driving churn, e.g. a 'path' or 'user_id' label with unbounded values
+
+The result lists the number of distinct values per step along with the first step reaching the peak.
+Compare the counts before and after a jump to date the change, then inspect the new values with get_label_values.`
+
+	SLOCompliancePrompt = `Compute an SLI as the ratio of good to total events over a time range and compare it to an SLO target.
+
+WHEN TO USE:
+- "Did the API meet its 99.9% availability SLO this week?", "How much error budget is left?"
+- Prefer this over computing the ratio yourself: both queries are evaluated at the same steps and weighted by event volume
+
+QUERIES:
+- 'good_query' and 'total_query' must return the rate (or increase) of good and of all events, each aggregated to a single series
+  e.g. good_query: sum(rate(http_requests_total{job="api",code!~"5.."}[5m])), total_query: sum(rate(http_requests_total{job="api"}[5m]))
+- Use the same range window and aggregation in both queries
+- Choose a 'step' no larger than the range window, so that every event is counted
+
+RESULT:
+- 'sli' and 'target' are percentages; 'compliant' tells whether the SLI meets the target
+- 'burnRate' is how fast the error budget was consumed over the time range (1 = exactly at the allowed rate)
+- 'errorBudgetRemaining' takes the time range as the SLO period; 'worstStep' points at the worst moment`
+
+	EvaluateConditionPrompt = `Evaluate a PromQL comparison at each step of a time range and report how long it was true.
+
+WHEN TO USE:
+- Alert-like checks: "How much of the last day was CPU above 80%?", "Would this alert have fired?"
+- To compare the longest time a condition held with the 'for' duration of an alert rule
+
+QUERY:
+- The query must be a comparison, e.g. avg(rate(http_requests_total{job="api",code=~"5.."}[5m])) > 0.8
+- The condition is true at a step when any series of the result is true; aggregate the query to check a single condition
+
+RESULT:
+- 'trueFraction' is the fraction of the steps at which the condition was true
+- 'longestStreak' is the longest run of consecutive true steps, with its 'duration'; choose a 'step' no larger than the scrape interval to measure it precisely
+
+FOR DURATION:
+- "Would this alert fire?": pass the alert's expression as 'query' and its 'for' duration as 'for'
+- 'forWindow.firingAtEnd' tells whether the alert would be firing at the end of the range; 'wouldHaveFired' and 'firingSince' whether and when it would have fired within it
+- Like alerts, the condition must hold for the same series; choose a 'step' no larger than the rule evaluation interval (e.g., '30s' or '1m')`
+
+	ClusterStatusPrompt = `Run a set of canned health checks of the cluster and summarize them as a traffic light.
+
+WHEN TO USE:
+- As a first step for open questions such as "Is the cluster healthy?" or "Is anything wrong right now?"
+- Before digging into a specific component, to see which areas need attention
+
+The default checks cover node readiness, pod restarts, API server latency and etcd health; deployments can configure their own.
+Pass 'checks' to run only some of them.
+
+RESULT:
+- 'status' is the worst status of the checks: ok, unknown, warning or critical
+- Each check reports its 'value', 'status' and 'query'; investigate warning and critical checks further with their query
+- A check failing or returning no data, e.g. because the component is not monitored, is unknown and does not affect the other checks`
+
 	GetLabelNamesPrompt = `Get all label names (dimensions) available for filtering a metric.
 
 WHEN TO USE (after calling list_metrics):
 - To discover how to filter metrics (by namespace, pod, service, etc.)
 - Before constructing label matchers in PromQL queries
 
-The 'metric' parameter should use a metric name from list_metrics output.`
+The 'metric' parameter should use a metric name from list_metrics output.
+
+When the backend serves TSDB stats, 'valueCounts' gives the approximate number of values of each
+label across all metrics: an upper bound for the metric, enough to tell low-cardinality labels
+(e.g., namespace) from high-cardinality ones (e.g., pod) without calling get_label_values for each.`
 
 	GetLabelValuesPrompt = `Get all unique values for a specific label.
 
 WHEN TO USE (after calling list_metrics and get_label_names):
 - To find exact label values for filtering (namespace names, pod names, etc.)
 - To see what values exist before constructing queries
+- With 'by_frequency' and 'metric', to find the most common values, e.g. to pick a representative one to drill down into
 
+The 'metric' parameter should use a metric name from list_metrics output.`
+
+	GetLabelsOverviewPrompt = `Get the values of several labels of a metric in one call.
+
+WHEN TO USE (after calling list_metrics and get_label_names):
+- Instead of several get_label_values calls, e.g. to get namespace, pod and container values at once
+- To get an overview of the filter options of a metric before constructing a query
+
+The values of each label are capped by 'limit'; the response reports which labels were truncated.
+Use get_label_values for a single label with many values, or to sort values by frequency.
 The 'metric' parameter should use a metric name from list_metrics output.`
 
 	GetSeriesPrompt = `Get time series matching selectors and preview cardinality.
@@ -133,8 +253,135 @@ CARDINALITY GUIDANCE:
 - 100-1000: Usually fine
 - >1000: Add more label filters
 
+The selector should use metric names from list_metrics output.
+Set 'with_last_seen' to see how long ago each series was last sampled, e.g. to tell live series from ones whose target disappeared.`
+
+	CheckSeriesUniquenessPrompt = `Check whether a selector matches exactly one time series.
+
+WHEN TO USE (optional, after calling list_metrics):
+- Before building a single-value query (e.g., for a gauge or stat panel), to confirm the label matchers identify one series
+- When a query unexpectedly returns several series
+
+If more than one series matches, the response lists the labels whose values differ among them.
+Add matchers on those labels to narrow the selector down to a single series.
+
 The selector should use metric names from list_metrics output.`
 
+	InspectMetricPrompt = `Find out which jobs export a metric and whether they export it with different labels.
+
+WHEN TO USE (optional, after calling list_metrics):
+- Before aggregating a metric whose name is generic (e.g., 'requests_total', 'errors_total') across jobs
+- When a query mixes series that look unrelated
+
+Several jobs may export the same metric name with different meanings. The response lists the jobs
+with the label names of their series; when their label names differ, the metric is reported as
+ambiguous and you should filter on 'job' rather than combine the series of different jobs.`
+
+	GetMetricMetadataPrompt = `Get the type, help text and unit the targets report for metrics.
+
+WHEN TO USE (optional, after calling list_metrics):
+- To tell counters from gauges before choosing between rate() and the raw values
+- To learn what a metric measures and in which unit, when its name leaves it unclear
+
+Pass a metric name to get its metadata, or omit it to get the metadata of many metrics at once.
+Recording rules and metrics without metadata return no entries.`
+
+	GetScrapeIntervalPrompt = `Estimate how often a metric is scraped, from the spacing between its samples.
+
+WHEN TO USE (optional, after calling list_metrics):
+- Before choosing the range of rate(), irate() or increase(), or the 'step' of a range query
+
+The response holds the estimated interval and 'minRateWindow', the shortest range to use in
+rate() and increase(): 4 times the scrape interval, so that the range holds enough samples even
+when a scrape is missed. Shorter ranges return gaps or no data. Recording rules are reported
+with their evaluation interval.`
+
+	GetExternalLabelsPrompt = `Get the external labels the metrics backend attaches to every series, such as 'cluster' or 'replica'.
+
+WHEN TO USE (optional):
+- When query results carry labels you did not select on, e.g. one series per cluster or replica
+- Before aggregating across clusters or Prometheus replicas
+
+External labels identify where a series was collected, not what it measures. Filter on them to
+pick a cluster (e.g. {cluster="prod"}), or drop them when aggregating (e.g. 'sum without (replica)')
+so that replicas of the same series are not counted twice.`
+
+	GetActiveQueriesPrompt = `Get the number of queries currently running on each Prometheus query engine behind the backend.
+
+WHEN TO USE (optional):
+- When queries are slow or time out, to check whether the backend is saturated
+- Before running many expensive queries in a row
+
+Each engine reports the queries executing or waiting and, when known, its concurrency limit; an engine
+at its limit queues further queries. The text of running queries is not available through the API;
+when a query log file is reported, completed queries are logged there on the Prometheus server.
+Engines are only reported when the Prometheus instances are scraped, so the result may be empty.`
+
+	ListRecordingRulesPrompt = `List recording rules and the precomputed metrics they produce.
+
+WHEN TO USE (optional, alongside list_metrics):
+- Before writing an expensive aggregation, to check whether a recorded metric already computes it
+- To understand how a metric with a colon-separated name (e.g., 'namespace:container_cpu_usage:sum') is derived
+
+Each rule returns the recorded metric name, its source PromQL expression and whether the recorded metric currently has data.
+Prefer querying recorded metrics that have data over re-computing the same expression from raw metrics.`
+
+	GetRuleGroupPrompt = `Get all recording and alerting rules of a rule group with the health, time and duration of their last evaluation.
+
+WHEN TO USE:
+- When a recorded metric is stale or missing, to check whether its rule is failing (find its group with list_recording_rules)
+- To review the health of a rule group as a whole
+
+'failing' lists the rules whose last evaluation failed; their 'lastError' explains why.
+A rule without 'lastEvaluation' has not been evaluated yet.`
+
+	ListQueryTemplatesPrompt = `List ready-made PromQL query templates for common questions.
+
+WHEN TO USE:
+- When asked about pod CPU or memory usage, container restarts, node disk usage or HTTP error rates
+- When unsure how to write a correct PromQL query for one of these questions
+
+Each template has a name, a description, the PromQL query with ${name} placeholders and the list of placeholders.
+Use render_query_template to fill in the placeholders, then run the rendered query with execute_instant_query or execute_range_query.
+Templates reference well-known metric names; still verify them with list_metrics if the query returns no data.`
+
+	RenderQueryTemplatePrompt = `Render a query template from list_query_templates into a ready-to-run PromQL query.
+
+Pass the template 'name' and the placeholder values in 'params'. Label values are escaped automatically,
+durations must use Prometheus duration syntax (e.g., "5m", "1h"). Placeholders with a default can be omitted.
+
+Use get_label_values to find exact values (e.g., namespace names) before rendering.`
+
+	QueryToPanelPrompt = `Turn a PromQL query into a Perses dashboard panel.
+
+WHEN TO USE (optional):
+- When the user wants to keep a query from an investigation on a dashboard
+
+Returns the panel specification as JSON, to be pasted into the panels of a Perses dashboard and added to its layout.
+Pick the 'chart_type' matching the query: 'timeseries' for trends, 'stat' or 'gauge' for a single current value,
+'bar' or 'pie' to compare a few series, 'table' to list series and values.
+The query is checked for syntax only; run it with execute_range_query or execute_instant_query first to confirm it returns data.`
+
+	ParseTimePrompt = `Resolve a time expression to the timestamp the query tools would use for it.
+
+WHEN TO USE (optional):
+- Before a query, to check that a computed start, end or evaluation time is the one you intended
+- After a query tool rejected a time parameter
+
+Accepted forms are RFC3339 (e.g., '2024-01-02T15:04:05Z'), Unix timestamps in seconds, NOW and NOW±duration
+(e.g., 'NOW-6h', 'NOW-7d'). Expressions that cannot be parsed return an error with supported rewrites when possible.
+The tool only computes the time, it does not query the backend.`
+
+	SaveQueryResultPrompt = `Run a PromQL query and save the full result to a file on the server instead of returning it.
+
+WHEN TO USE:
+- When a result is too large to return in a response, but the full data is needed for offline analysis or by another program
+- Use execute_instant_query or execute_range_query instead when you need to read the values yourself
+
+Set 'step' to run a range query; without it an instant query is run.
+The response contains the path of the written file and counts of the series and samples it holds.
+Existing files are never overwritten.`
+
 	GetAlertsPrompt = `Get alerts from Alertmanager.
 
 WHEN TO USE:
@@ -151,9 +398,55 @@ FILTERING:
 - Use 'silenced' to filter for silenced alerts
 - Use 'inhibited' to filter for inhibited alerts
 - Use 'filter' to apply label matchers (e.g., "alertname=HighCPU")
+- Use 'any_of' for alternatives: alerts matching any of its matcher groups are returned (e.g., filter "namespace=X" with any_of ["alertname=HighCPU", "alertname=HighMemory"] for HighCPU or HighMemory in namespace X)
 - Use 'receiver' to filter alerts by receiver name
+- Use 'min_active_duration' to keep alerts firing for at least that long (e.g., "1h"), to focus on persistent problems rather than flapping alerts
+
+All filter parameters are optional. Without filters, all alerts are returned.
+The server may be configured to return only some labels and annotations of each alert; use 'labels'
+and 'annotations' to request others, or ['*'] for all of them, e.g. to see an alert's runbook_url.
+When more than 100 alerts match, a summary by severity, namespace and alert name is returned instead
+of the list; narrow the filters using the summary, or set 'full' to list every alert.`
+
+	SummarizeAlertsPrompt = `Summarize the alerts in Alertmanager instead of listing them.
 
-All filter parameters are optional. Without filters, all alerts are returned.`
+WHEN TO USE:
+- When many alerts are firing, e.g. during an incident, to triage before looking at individual alerts
+- To see which severities, namespaces and alerts dominate
+
+Returns the number of alerts per severity, namespace and alert name, and the alert names to look at first,
+most severe and then most frequent first, with the namespaces they fire in.
+Use get_alerts with a 'filter' on an alert name or namespace from the summary to get the full alerts.
+Accepts the same 'active', 'silenced', 'inhibited', 'filter' and 'receiver' filters as get_alerts.`
+
+	GetAlertHistoryPrompt = `Get the alerts that were active within a past time window, with the intervals during which they were active.
+
+WHEN TO USE:
+- For post-incident analysis, to find which alerts fired during an incident and when they started and resolved
+- To check whether an alert has been flapping
+- get_alerts only returns the current state of Alertmanager; use this tool to look back in time
+
+Alertmanager keeps no history, so the intervals are reconstructed from the Prometheus ALERTS series.
+LIMITATIONS:
+- Interval boundaries are only as precise as the returned resolution, and never more precise than the rule evaluation interval; alerts active for less than that may be missed
+- Only alerts evaluated by Prometheus rules are covered, not alerts sent to Alertmanager by other sources
+- History is bounded by the Prometheus retention period
+
+FILTERING:
+- Use 'since' and 'until' to set the time window (defaults to the last hour)
+- Use 'filter' to apply label matchers (e.g., "alertname=HighCPU,namespace=default")
+- Firing alerts are returned by default; add "alertstate=pending" to the filter to see pending alerts instead`
+
+	GetAlertThresholdPrompt = `Get the threshold of an alerting rule together with the current value it is compared against and the margin between the two.
+
+WHEN TO USE:
+- To see how close a gauge-based alert is to firing, or how far past its threshold a firing alert is
+- To check whether an alert threshold fits the values the metric actually takes
+
+The threshold is read from the comparison of the rule expression against a constant, e.g. 80 in "node_memory_usage_percent > 80" or in "80 < node_memory_usage_percent", and the other side of the comparison is evaluated at the current time. Conditions joined with 'and' or 'unless' are ignored, so the values are returned even where those conditions suppress the alert.
+LIMITATIONS:
+- Rules comparing two queries (e.g. "x > y"), using 'or', 'bool' or no comparison at all (e.g. "absent(x)") have no single threshold; their expression is returned with a note
+- A positive margin means the value is above the threshold, whichever way the alert compares`
 
 	GetSilencesPrompt = `Get silences from Alertmanager.
 
@@ -166,4 +459,20 @@ FILTERING:
 - Use 'filter' to apply label matchers to find specific silences
 
 Silences are used to temporarily mute alerts based on label matchers. This tool helps you understand what is currently silenced in your environment.`
+
+	DeleteSilencePrompt = `Expire a silence in Alertmanager before its end time, so that the alerts it mutes notify again.
+
+WHEN TO USE:
+- When the user asks to end a silence early, e.g. after maintenance finished sooner than planned
+
+Find the 'id' of the silence with get_silences first, and confirm with the user before expiring it.
+Expired silences are still listed by get_silences with the state 'expired'.`
+
+	GetAlertmanagerStatusPrompt = `Get the status of the Alertmanager the server is connected to: its version, uptime, cluster state and a hash of its configuration.
+
+WHEN TO USE:
+- To confirm which Alertmanager instance the server talks to
+- To diagnose a clustered Alertmanager, e.g. a split brain where replicas do not see each other, a cluster that is still settling, or replicas running different configurations
+
+Compare the peers and config hash reported by each replica: all replicas of a healthy cluster list the same peers and have the same config hash.`
 )
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/prometheus/common/model"
+)
+
+// rankVector sorts the samples of vector by value, highest first, keeping the order of
+// samples with equal values. NaN values are sorted last. The rank of a sample is its
+// 1-based position in the sorted vector.
+func rankVector(vector model.Vector) model.Vector {
+	ranked := slices.Clone(vector)
+	slices.SortStableFunc(ranked, func(a, b *model.Sample) int {
+		aNaN, bNaN := math.IsNaN(float64(a.Value)), math.IsNaN(float64(b.Value))
+		if aNaN || bNaN {
+			switch {
+			case aNaN && bNaN:
+				return 0
+			case aNaN:
+				return 1
+			default:
+				return -1
+			}
+		}
+		return cmp.Compare(b.Value, a.Value)
+	})
+	return ranked
+}
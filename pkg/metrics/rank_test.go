@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestRankVector(t *testing.T) {
+	vector := model.Vector{
+		{Metric: model.Metric{"pod": "a"}, Value: 1},
+		{Metric: model.Metric{"pod": "b"}, Value: model.SampleValue(math.NaN())},
+		{Metric: model.Metric{"pod": "c"}, Value: 3},
+		{Metric: model.Metric{"pod": "d"}, Value: 1},
+		{Metric: model.Metric{"pod": "e"}, Value: -2},
+	}
+
+	ranked := rankVector(vector)
+	want := []model.LabelValue{"c", "a", "d", "e", "b"}
+	if len(ranked) != len(want) {
+		t.Fatalf("expected %d samples, got %d", len(want), len(ranked))
+	}
+	for i, pod := range want {
+		if got := ranked[i].Metric["pod"]; got != pod {
+			t.Errorf("rank %d = %s, want %s", i+1, got, pod)
+		}
+	}
+	if vector[0].Metric["pod"] != "a" || vector[2].Metric["pod"] != "c" {
+		t.Error("rankVector modified its input")
+	}
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import "fmt"
+
+const (
+	// RawResponsePrometheus returns a query result in the envelope of the Prometheus
+	// HTTP API, for tooling that consumes the Prometheus API format.
+	RawResponsePrometheus = "prometheus"
+
+	// prometheusStatusSuccess is the status of a successful Prometheus API response.
+	prometheusStatusSuccess = "success"
+)
+
+// PrometheusData is the data of a Prometheus HTTP API query response.
+type PrometheusData struct {
+	ResultType string `json:"resultType" jsonschema:"The type of result returned: matrix or vector or scalar"`
+	Result     any    `json:"result" jsonschema:"The query result in the Prometheus API format"`
+}
+
+// parseRawResponse validates a raw_response value; empty selects the reshaped output.
+func parseRawResponse(rawResponse string) error {
+	if rawResponse != "" && rawResponse != RawResponsePrometheus {
+		return fmt.Errorf("invalid raw_response %q (valid options: %s)", rawResponse, RawResponsePrometheus)
+	}
+	return nil
+}
+
+// newPrometheusResponse returns a range query result as returned by the query_range
+// endpoint of the Prometheus HTTP API: {status, data: {resultType, result}, warnings}.
+func newPrometheusResponse(result map[string]any, extraWarnings ...string) RangeQueryOutput {
+	output := RangeQueryOutput{
+		Status: prometheusStatusSuccess,
+		Data: &PrometheusData{
+			ResultType: fmt.Sprintf("%v", result["resultType"]),
+			Result:     result["result"],
+		},
+	}
+	if warnings, ok := result["warnings"].([]string); ok {
+		output.Warnings = append(output.Warnings, warnings...)
+	}
+	output.Warnings = append(output.Warnings, extraWarnings...)
+	return output
+}
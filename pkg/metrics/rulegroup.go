@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// Rule types reported by get_rule_group.
+const (
+	RuleTypeRecording = "recording"
+	RuleTypeAlerting  = "alerting"
+)
+
+// findRuleGroup returns the rule group with the given name, restricted to the given
+// file when it is set. Group names are only unique within a file, so a name found in
+// several files must be disambiguated with the file.
+func findRuleGroup(groups []v1.RuleGroup, name, file string) (v1.RuleGroup, error) {
+	var found []v1.RuleGroup
+	for _, group := range groups {
+		if group.Name == name && (file == "" || group.File == file) {
+			found = append(found, group)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		if file != "" {
+			return v1.RuleGroup{}, fmt.Errorf("rule group %q not found in file %q", name, file)
+		}
+		return v1.RuleGroup{}, fmt.Errorf("rule group %q not found; list_recording_rules reports the groups of the recording rules", name)
+	case 1:
+		return found[0], nil
+	}
+	files := make([]string, len(found))
+	for i, group := range found {
+		files[i] = group.File
+	}
+	return v1.RuleGroup{}, fmt.Errorf("rule group %q is defined in several files (%s); set file to choose one", name, strings.Join(files, ", "))
+}
+
+// describeRuleGroup returns the rules of a group with their last evaluation, in the
+// order they are evaluated in, and the names of those whose last evaluation failed.
+func describeRuleGroup(group v1.RuleGroup) RuleGroupOutput {
+	output := RuleGroupOutput{
+		Name:     group.Name,
+		File:     group.File,
+		Interval: model.Duration(time.Duration(group.Interval * float64(time.Second))).String(),
+		Rules:    make([]RuleGroupRule, 0, len(group.Rules)),
+	}
+
+	for _, rule := range group.Rules {
+		var r RuleGroupRule
+		switch rule := rule.(type) {
+		case v1.RecordingRule:
+			r = RuleGroupRule{
+				Name:               rule.Name,
+				Type:               RuleTypeRecording,
+				Query:              rule.Query,
+				Labels:             labelSetToMap(rule.Labels),
+				Health:             string(rule.Health),
+				LastError:          rule.LastError,
+				LastEvaluation:     formatEvaluationTime(rule.LastEvaluation),
+				EvaluationDuration: formatEvaluationDuration(rule.EvaluationTime),
+			}
+		case v1.AlertingRule:
+			r = RuleGroupRule{
+				Name:               rule.Name,
+				Type:               RuleTypeAlerting,
+				Query:              rule.Query,
+				Labels:             labelSetToMap(rule.Labels),
+				Health:             string(rule.Health),
+				LastError:          rule.LastError,
+				LastEvaluation:     formatEvaluationTime(rule.LastEvaluation),
+				EvaluationDuration: formatEvaluationDuration(rule.EvaluationTime),
+				State:              rule.State,
+				ActiveAlerts:       len(rule.Alerts),
+			}
+		default:
+			continue
+		}
+
+		output.Rules = append(output.Rules, r)
+		if r.Health == string(v1.RuleHealthBad) {
+			output.Failing = append(output.Failing, r.Name)
+		}
+	}
+	return output
+}
+
+func labelSetToMap(set model.LabelSet) map[string]string {
+	if len(set) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(set))
+	for k, v := range set {
+		labels[string(k)] = string(v)
+	}
+	return labels
+}
+
+// formatEvaluationTime formats the last evaluation of a rule, which is zero for rules
+// that have not been evaluated yet.
+func formatEvaluationTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatEvaluationDuration formats the time a rule evaluation took, in seconds. Rules
+// often evaluate in well under a millisecond, hence the microsecond precision.
+func formatEvaluationDuration(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Microsecond).String()
+}
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+func TestFindRuleGroup(t *testing.T) {
+	groups := []v1.RuleGroup{
+		{Name: "k8s.rules", File: "/etc/prometheus/rules/k8s.yaml"},
+		{Name: "node.rules", File: "/etc/prometheus/rules/node.yaml"},
+		{Name: "node.rules", File: "/etc/prometheus/rules/node-extra.yaml"},
+	}
+
+	tests := []struct {
+		name     string
+		group    string
+		file     string
+		wantFile string
+		wantErr  string
+	}{
+		{name: "unique name", group: "k8s.rules", wantFile: "/etc/prometheus/rules/k8s.yaml"},
+		{name: "name and file", group: "node.rules", file: "/etc/prometheus/rules/node-extra.yaml", wantFile: "/etc/prometheus/rules/node-extra.yaml"},
+		{name: "name in several files", group: "node.rules", wantErr: "set file to choose one"},
+		{name: "unknown name", group: "etcd.rules", wantErr: `rule group "etcd.rules" not found`},
+		{name: "name in another file", group: "k8s.rules", file: "/etc/prometheus/rules/node.yaml", wantErr: `not found in file`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, err := findRuleGroup(groups, tt.group, tt.file)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if group.File != tt.wantFile {
+				t.Errorf("file = %q, want %q", group.File, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestDescribeRuleGroup(t *testing.T) {
+	evaluated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	group := v1.RuleGroup{
+		Name:     "k8s.rules",
+		File:     "/etc/prometheus/rules/k8s.yaml",
+		Interval: 30,
+		Rules: v1.Rules{
+			v1.RecordingRule{
+				Name:           "namespace:container_cpu_usage:sum",
+				Query:          "sum by (namespace) (rate(container_cpu_usage_seconds_total[5m]))",
+				Health:         v1.RuleHealthGood,
+				EvaluationTime: 0.0012345,
+				LastEvaluation: evaluated,
+			},
+			v1.RecordingRule{
+				Name:           "namespace:container_memory_usage:sum",
+				Query:          "sum by (namespace) (container_memory_working_set_bytes)",
+				Labels:         model.LabelSet{"source": "cadvisor"},
+				Health:         v1.RuleHealthBad,
+				LastError:      "vector cannot contain metrics with the same labelset",
+				EvaluationTime: 0.5,
+				LastEvaluation: evaluated,
+			},
+			v1.AlertingRule{
+				Name:   "HighCPU",
+				Query:  "namespace:container_cpu_usage:sum > 10",
+				Health: v1.RuleHealthUnknown,
+				State:  "firing",
+				Alerts: []*v1.Alert{{}, {}},
+			},
+		},
+	}
+
+	want := RuleGroupOutput{
+		Name:     "k8s.rules",
+		File:     "/etc/prometheus/rules/k8s.yaml",
+		Interval: "30s",
+		Rules: []RuleGroupRule{
+			{
+				Name:               "namespace:container_cpu_usage:sum",
+				Type:               RuleTypeRecording,
+				Query:              "sum by (namespace) (rate(container_cpu_usage_seconds_total[5m]))",
+				Health:             "ok",
+				LastEvaluation:     "2024-01-01T11:00:00Z",
+				EvaluationDuration: "1.235ms",
+			},
+			{
+				Name:               "namespace:container_memory_usage:sum",
+				Type:               RuleTypeRecording,
+				Query:              "sum by (namespace) (container_memory_working_set_bytes)",
+				Labels:             map[string]string{"source": "cadvisor"},
+				Health:             "err",
+				LastError:          "vector cannot contain metrics with the same labelset",
+				LastEvaluation:     "2024-01-01T11:00:00Z",
+				EvaluationDuration: "500ms",
+			},
+			{
+				Name:               "HighCPU",
+				Type:               RuleTypeAlerting,
+				Query:              "namespace:container_cpu_usage:sum > 10",
+				Health:             "unknown",
+				EvaluationDuration: "0s",
+				State:              "firing",
+				ActiveAlerts:       2,
+			},
+		},
+		Failing: []string{"namespace:container_memory_usage:sum"},
+	}
+
+	if got := describeRuleGroup(group); !reflect.DeepEqual(got, want) {
+		t.Errorf("describeRuleGroup() =\n%+v\nwant\n%+v", got, want)
+	}
+}
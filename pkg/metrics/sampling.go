@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/prometheus/common/model"
+)
+
+// maxSamplingSeed bounds generated sampling seeds, so that they survive a round trip
+// through JSON numbers unchanged.
+const maxSamplingSeed = math.MaxInt32
+
+// samplingSeed returns the seed of a sampled result: the requested one, or a random one.
+func samplingSeed(requested int) int {
+	if requested != 0 {
+		return requested
+	}
+	return rand.IntN(maxSamplingSeed) + 1
+}
+
+// sampleIndices selects limit of the len(scores) series of a result: the half with the
+// highest scores, so that outliers are always visible, and a random selection of the rest
+// drawn with seed, to show the distribution. Series without a score (NaN) are only picked
+// at random. The selected indices are returned in ascending order, keeping the order of
+// the result, along with the number of them picked by score.
+func sampleIndices(scores []float64, limit int, seed int) (indices []int, top int) {
+	if len(scores) <= limit {
+		indices = make([]int, len(scores))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices, 0
+	}
+
+	byScore := make([]int, len(scores))
+	for i := range byScore {
+		byScore[i] = i
+	}
+	slices.SortStableFunc(byScore, func(a, b int) int {
+		sa, sb := scores[a], scores[b]
+		switch {
+		case math.IsNaN(sa) && math.IsNaN(sb):
+			return 0
+		case math.IsNaN(sa):
+			return 1
+		case math.IsNaN(sb):
+			return -1
+		}
+		return cmp.Compare(sb, sa)
+	})
+
+	top = limit / 2
+	for top > 0 && math.IsNaN(scores[byScore[top-1]]) {
+		top--
+	}
+	selected := slices.Clone(byScore[:top])
+
+	rest := byScore[top:]
+	slices.Sort(rest)
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	rng.Shuffle(len(rest), func(i, j int) { rest[i], rest[j] = rest[j], rest[i] })
+	selected = append(selected, rest[:limit-top]...)
+
+	slices.Sort(selected)
+	return selected, top
+}
+
+// seriesScore ranks a range query series for sampling by its largest finite value.
+func seriesScore(values []model.SamplePair) float64 {
+	score := math.NaN()
+	for _, v := range values {
+		f := float64(v.Value)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		if math.IsNaN(score) || f > score {
+			score = f
+		}
+	}
+	return score
+}
+
+// sampleVector reduces an instant query result to a sample of limit series.
+func sampleVector(vector model.Vector, limit, seed int) (model.Vector, *SamplingInfo) {
+	scores := make([]float64, len(vector))
+	for i, s := range vector {
+		scores[i] = float64(s.Value)
+		if math.IsInf(scores[i], 0) {
+			scores[i] = math.NaN()
+		}
+	}
+	indices, top := sampleIndices(scores, limit, seed)
+
+	sampled := make(model.Vector, len(indices))
+	for i, idx := range indices {
+		sampled[i] = vector[idx]
+	}
+	return sampled, &SamplingInfo{TotalSeries: len(vector), ReturnedSeries: len(sampled), TopSeries: top, Seed: seed}
+}
+
+// sampleMatrix reduces a range query result to a sample of limit series.
+func sampleMatrix(matrix model.Matrix, limit, seed int) (model.Matrix, *SamplingInfo) {
+	scores := make([]float64, len(matrix))
+	for i, s := range matrix {
+		scores[i] = seriesScore(s.Values)
+	}
+	indices, top := sampleIndices(scores, limit, seed)
+
+	sampled := make(model.Matrix, len(indices))
+	for i, idx := range indices {
+		sampled[i] = matrix[idx]
+	}
+	return sampled, &SamplingInfo{TotalSeries: len(matrix), ReturnedSeries: len(sampled), TopSeries: top, Seed: seed}
+}
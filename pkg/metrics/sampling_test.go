@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestSampleIndices(t *testing.T) {
+	scores := make([]float64, 100)
+	for i := range scores {
+		scores[i] = float64(i % 37)
+	}
+	scores[3] = 1000
+	scores[50] = 500
+
+	t.Run("same seed gives the same sample", func(t *testing.T) {
+		first, _ := sampleIndices(scores, 10, 42)
+		for range 5 {
+			if again, _ := sampleIndices(scores, 10, 42); !slices.Equal(first, again) {
+				t.Fatalf("sample changed with the same seed: %v, then %v", first, again)
+			}
+		}
+	})
+
+	t.Run("different seeds give different random picks", func(t *testing.T) {
+		a, _ := sampleIndices(scores, 10, 1)
+		b, _ := sampleIndices(scores, 10, 2)
+		if slices.Equal(a, b) {
+			t.Errorf("expected different samples for different seeds, got %v", a)
+		}
+	})
+
+	t.Run("highest scores are always included", func(t *testing.T) {
+		for seed := 1; seed <= 20; seed++ {
+			indices, top := sampleIndices(scores, 10, seed)
+			if len(indices) != 10 || top != 5 {
+				t.Fatalf("got %d indices with %d by score, want 10 with 5", len(indices), top)
+			}
+			if !slices.Contains(indices, 3) || !slices.Contains(indices, 50) {
+				t.Errorf("seed %d: sample %v misses the outliers", seed, indices)
+			}
+			if !slices.IsSorted(indices) {
+				t.Errorf("seed %d: sample %v is not in result order", seed, indices)
+			}
+		}
+	})
+
+	t.Run("NaN scores are only picked at random", func(t *testing.T) {
+		nan := []float64{math.NaN(), math.NaN(), math.NaN(), 1, math.NaN()}
+		indices, top := sampleIndices(nan, 4, 7)
+		if top != 1 || len(indices) != 4 || !slices.Contains(indices, 3) {
+			t.Errorf("got %v with %d by score, want 4 indices including 3 with 1 by score", indices, top)
+		}
+	})
+
+	t.Run("results within the limit are kept", func(t *testing.T) {
+		indices, top := sampleIndices([]float64{3, 1, 2}, 10, 1)
+		if !slices.Equal(indices, []int{0, 1, 2}) || top != 0 {
+			t.Errorf("got %v with %d by score, want every index", indices, top)
+		}
+	})
+}
+
+func TestSampleMatrix(t *testing.T) {
+	matrix := make(model.Matrix, 20)
+	for i := range matrix {
+		matrix[i] = &model.SampleStream{
+			Metric: model.Metric{"pod": model.LabelValue(fmt.Sprintf("pod-%02d", i))},
+			Values: []model.SamplePair{{Timestamp: 0, Value: model.SampleValue(i)}, {Timestamp: 60000, Value: model.SampleValue(math.Inf(1))}},
+		}
+	}
+
+	sampled, info := sampleMatrix(matrix, 4, 99)
+	if len(sampled) != 4 || info.TotalSeries != 20 || info.ReturnedSeries != 4 || info.TopSeries != 2 || info.Seed != 99 {
+		t.Fatalf("unexpected sample: %d series, %+v", len(sampled), info)
+	}
+	// Infinite values are ignored when ranking, so the largest finite values win.
+	for _, pod := range []model.LabelValue{"pod-18", "pod-19"} {
+		if !slices.ContainsFunc(sampled, func(s *model.SampleStream) bool { return s.Metric["pod"] == pod }) {
+			t.Errorf("sample misses %s", pod)
+		}
+	}
+
+	again, _ := sampleMatrix(matrix, 4, 99)
+	for i := range sampled {
+		if sampled[i] != again[i] {
+			t.Fatalf("sample changed with the same seed")
+		}
+	}
+}
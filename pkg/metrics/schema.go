@@ -1,51 +1,354 @@
 package metrics
 
+import "github.com/rhobs/obs-mcp/pkg/resultutil"
+
 // ListMetricsOutput defines the output schema for the list_metrics tool.
 type ListMetricsOutput struct {
-	Metrics []string `json:"metrics" jsonschema:"List of all available metric names"`
+	Metrics    []string `json:"metrics" jsonschema:"List of all available metric names"`
+	Truncated  bool     `json:"truncated,omitempty" jsonschema:"Whether more metrics matched than were returned"`
+	TotalCount int      `json:"totalCount,omitempty" jsonschema:"Total number of matching metrics; only set when truncated"`
+}
+
+// MetricMetadataOutput defines the output schema for the get_metric_metadata tool.
+type MetricMetadataOutput struct {
+	Metadata  []MetricMetadata `json:"metadata" jsonschema:"Type, help and unit of the metrics, sorted by metric name"`
+	Truncated bool             `json:"truncated,omitempty" jsonschema:"Whether more metrics have metadata than were returned"`
+}
+
+// MetricMetadata is the metadata a metric is reported with. A metric reported with
+// different metadata by different targets has one entry per distinct metadata.
+type MetricMetadata struct {
+	Metric string `json:"metric" jsonschema:"Metric name"`
+	Type   string `json:"type" jsonschema:"Metric type: counter, gauge, histogram, gaugehistogram, summary, info, stateset or unknown"`
+	Help   string `json:"help,omitempty" jsonschema:"Description of what the metric measures"`
+	Unit   string `json:"unit,omitempty" jsonschema:"Unit of the metric values, e.g. seconds or bytes (when reported)"`
+}
+
+// MetricGroupsOutput defines the output schema for the list_metric_groups tool.
+type MetricGroupsOutput struct {
+	Groups       []MetricGroup `json:"groups" jsonschema:"Groups of metrics sharing a name prefix, largest first"`
+	TotalMetrics int           `json:"totalMetrics" jsonschema:"Number of metrics below the requested prefix"`
+}
+
+// MetricGroup is a set of metrics sharing a name prefix.
+type MetricGroup struct {
+	Prefix   string   `json:"prefix" jsonschema:"Name prefix shared by the metrics of the group"`
+	Count    int      `json:"count" jsonschema:"Number of metrics in the group"`
+	Examples []string `json:"examples" jsonschema:"A few metric names of the group"`
 }
 
 // InstantQueryOutput defines the output schema for the execute_instant_query tool.
 type InstantQueryOutput struct {
-	ResultType string          `json:"resultType" jsonschema:"The type of result returned (e.g. vector, scalar, string)"`
-	Result     []InstantResult `json:"result" jsonschema:"The query results as an array of instant values"`
-	Warnings   []string        `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+	ResultType    string                  `json:"resultType" jsonschema:"The type of result returned (e.g. vector, scalar, string)"`
+	Result        []InstantResult         `json:"result,omitempty" jsonschema:"The query results as an array of instant values (omitted when group_by is set)"`
+	ScalarValue   string                  `json:"scalarValue,omitempty" jsonschema:"The value of the result when it is a scalar or a single sample without labels, e.g. the result of count(up{job=\"api\"})"`
+	Groups        map[string]InstantGroup `json:"groups,omitempty" jsonschema:"Aggregated values keyed by the value of the group_by label (when group_by is set)"`
+	Nearest       bool                    `json:"nearest,omitempty" jsonschema:"Whether the result holds the latest values found before the requested time, as there were none at it (when nearest is set)"`
+	Sampled       *SamplingInfo           `json:"sampled,omitempty" jsonschema:"How the result was reduced to a sample of its series (when sampling is set and the result exceeds the series limit)"`
+	RankedCount   int                     `json:"rankedCount,omitempty" jsonschema:"Number of series ranked, i.e. the rank of the last one, including series left out by sampling (when rank is set)"`
+	Unit          string                  `json:"unit,omitempty" jsonschema:"Unit of the values inferred from the query: bytes, bytes/s or seconds (when convert_units is set and the unit is known)"`
+	Warnings      []string                `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+	ExecutedQuery *ExecutedQuery          `json:"executedQuery,omitempty" jsonschema:"Query as sent to the backend (when verbosity is full)"`
+	Stats         *QueryStats             `json:"stats,omitempty" jsonschema:"Size of the backend response and time taken (when verbosity is full)"`
+	DryRun        *DryRunOutput           `json:"dryRun,omitempty" jsonschema:"Requests that would have been sent to the backend (when dry_run is set)"`
 }
 
 // InstantResult represents a single instant query result.
 type InstantResult struct {
-	Metric map[string]string `json:"metric" jsonschema:"The metric labels"`
-	Value  []any             `json:"value" jsonschema:"[timestamp, value] pair for the instant query"`
+	Metric    map[string]string `json:"metric" jsonschema:"The metric labels"`
+	Value     []any             `json:"value,omitempty" jsonschema:"[timestamp, value] pair for the instant query (omitted when labels_only is set)"`
+	Converted string            `json:"converted,omitempty" jsonschema:"Value converted to a human-readable unit, e.g. 1.5 GiB (when convert_units is set and the unit is known)"`
+	Merged    int               `json:"merged,omitempty" jsonschema:"Number of series whose values were added into this one because they have the same labels after project_labels, when more than one"`
+	Rank      int               `json:"rank,omitempty" jsonschema:"1-based position of the series when the result is sorted by value, highest first (when rank is set)"`
+}
+
+// InstantGroup is the aggregated value of the series sharing a group_by label value.
+type InstantGroup struct {
+	Value       string `json:"value" jsonschema:"Aggregated value of the series in the group"`
+	SeriesCount int    `json:"seriesCount" jsonschema:"Number of series aggregated into the group"`
 }
 
 // LabelNamesOutput defines the output schema for the get_label_names tool.
 type LabelNamesOutput struct {
-	Labels []string `json:"labels" jsonschema:"List of label names available for the specified metric or all metrics"`
+	Labels      []string          `json:"labels" jsonschema:"List of label names available for the specified metric or all metrics"`
+	ValueCounts map[string]uint64 `json:"valueCounts,omitempty" jsonschema:"Approximate number of distinct values of each label across all series of the backend, not only those of the metric, from its TSDB stats (omitted when the backend does not serve them)"`
 }
 
 // LabelValuesOutput defines the output schema for the get_label_values tool.
 type LabelValuesOutput struct {
-	Values []string `json:"values" jsonschema:"List of unique values for the specified label"`
+	Values      []string              `json:"values" jsonschema:"List of unique values for the specified label"`
+	Truncated   bool                  `json:"truncated,omitempty" jsonschema:"Whether more values exist than were returned"`
+	TotalCount  int                   `json:"totalCount,omitempty" jsonschema:"Total number of values for the label; omitted when truncated by the backend, as the total is then unknown"`
+	Frequencies []LabelValueFrequency `json:"frequencies,omitempty" jsonschema:"Number of series with each value, most common first; only set when by_frequency is requested"`
+}
+
+// LabelValueFrequency is the number of series having a label value.
+type LabelValueFrequency struct {
+	Value       string `json:"value" jsonschema:"Label value"`
+	SeriesCount int    `json:"seriesCount" jsonschema:"Number of series with the value within the time range"`
+}
+
+// LabelsOverviewOutput defines the output schema for the get_labels_overview tool.
+type LabelsOverviewOutput struct {
+	Labels []LabelOverview `json:"labels" jsonschema:"Values of each requested label, in the order requested"`
+}
+
+// LabelOverview lists the values of one label of a get_labels_overview call.
+type LabelOverview struct {
+	Label     string   `json:"label" jsonschema:"Label name"`
+	Values    []string `json:"values" jsonschema:"Unique values of the label for the metric"`
+	Truncated bool     `json:"truncated,omitempty" jsonschema:"Whether more values exist than were returned"`
 }
 
 // SeriesOutput defines the output schema for the get_series tool.
 type SeriesOutput struct {
 	Series      []map[string]string `json:"series" jsonschema:"List of time series matching the selector, each series is a map of label names to values"`
 	Cardinality int                 `json:"cardinality" jsonschema:"Total number of series matching the selector"`
+	Truncated   bool                `json:"truncated,omitempty" jsonschema:"Whether more series matched than were returned; cardinality is still the total"`
+	LastSeen    []string            `json:"lastSeen,omitempty" jsonschema:"Time since the last sample of each series, in the order of series (e.g. '2m ago', or 'stale 1h' for series that have ended); only set when with_last_seen is requested"`
+}
+
+// HeatmapOutput defines the output schema for the query_heatmap tool.
+type HeatmapOutput struct {
+	Query      string      `json:"query" jsonschema:"PromQL query that computed the counts"`
+	Buckets    []string    `json:"buckets" jsonschema:"Upper bounds (le) of the histogram buckets in increasing order; each bucket holds the observations above the previous bound"`
+	Timestamps []float64   `json:"timestamps" jsonschema:"Unix timestamps of the time buckets; each covers the step ending at the timestamp"`
+	Counts     [][]float64 `json:"counts" jsonschema:"Observation counts indexed by time bucket and then by histogram bucket, i.e. counts[i][j] is the count at timestamps[i] in buckets[j]"`
+	Warnings   []string    `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+}
+
+// LabelCardinalityTrendOutput defines the output schema for the label_cardinality_trend tool.
+type LabelCardinalityTrendOutput struct {
+	Query    string             `json:"query" jsonschema:"PromQL query that counted the label values"`
+	Label    string             `json:"label" jsonschema:"Label whose distinct values were counted"`
+	Points   []CardinalityPoint `json:"points" jsonschema:"Number of distinct values of the label at each step, in time order"`
+	Peak     *CardinalityPoint  `json:"peak,omitempty" jsonschema:"First step with the highest number of distinct values (absent when the metric has no series in the time range)"`
+	Warnings []string           `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+}
+
+// CardinalityPoint is the number of distinct values of a label at a point in time.
+type CardinalityPoint struct {
+	Timestamp float64 `json:"timestamp" jsonschema:"Unix timestamp of the step"`
+	Count     int     `json:"count" jsonschema:"Number of distinct values of the label"`
+}
+
+// EvaluateConditionOutput defines the output schema for the evaluate_condition tool.
+type EvaluateConditionOutput struct {
+	TrueFraction  float64             `json:"trueFraction" jsonschema:"Fraction of the steps of the time range at which the condition was true, between 0 and 1"`
+	TrueSteps     int                 `json:"trueSteps" jsonschema:"Number of steps at which the condition was true for at least one series"`
+	TotalSteps    int                 `json:"totalSteps" jsonschema:"Number of steps in the time range"`
+	SeriesCount   int                 `json:"seriesCount" jsonschema:"Number of series the query returned over the time range"`
+	LongestStreak *ConditionStreak    `json:"longestStreak,omitempty" jsonschema:"First of the longest runs of consecutive steps at which the condition was true (absent when it was never true)"`
+	ForWindow     *ConditionForWindow `json:"forWindow,omitempty" jsonschema:"Whether the condition held long enough for an alert with the given 'for' duration to fire (only when 'for' is set)"`
+	Warnings      []string            `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+}
+
+// ConditionForWindow tells whether a condition held for the 'for' duration of an alert rule.
+type ConditionForWindow struct {
+	For            string   `json:"for" jsonschema:"The 'for' duration the condition was checked against"`
+	FiringAtEnd    bool     `json:"firingAtEnd" jsonschema:"Whether a series was true at every step of the last 'for' duration up to the end of the time range, i.e. the alert would be firing at the end"`
+	WouldHaveFired bool     `json:"wouldHaveFired" jsonschema:"Whether a series was true at every step of a 'for' duration anywhere in the time range, i.e. the alert would have fired at some point"`
+	FiringSince    *float64 `json:"firingSince,omitempty" jsonschema:"Unix timestamp of the first step at which the alert would have fired (absent when it never would)"`
+}
+
+// ConditionStreak is a run of consecutive steps at which a condition was true.
+type ConditionStreak struct {
+	Start    float64 `json:"start" jsonschema:"Unix timestamp of the first step of the run"`
+	End      float64 `json:"end" jsonschema:"Unix timestamp of the last step of the run"`
+	Steps    int     `json:"steps" jsonschema:"Number of steps in the run"`
+	Duration string  `json:"duration" jsonschema:"Time from the first to the last step of the run, e.g. 15m; compare it to the 'for' duration of an alert"`
+}
+
+// ClusterStatusOutput defines the output schema for the cluster_status tool.
+type ClusterStatusOutput struct {
+	Status string              `json:"status" jsonschema:"Worst status of the checks: ok, unknown, warning or critical"`
+	Checks []StatusCheckResult `json:"checks" jsonschema:"Result of each check, in the configured order"`
+}
+
+// StatusCheckResult is the result of a single check of the cluster_status tool.
+type StatusCheckResult struct {
+	Name        string   `json:"name" jsonschema:"Name of the check"`
+	Description string   `json:"description,omitempty" jsonschema:"What the value of the check measures"`
+	Status      string   `json:"status" jsonschema:"ok, warning or critical by comparing the value to the thresholds of the check; unknown when the query failed or returned no data"`
+	Value       *float64 `json:"value,omitempty" jsonschema:"Highest value the query returned (absent when the status is unknown or the value is infinite)"`
+	Query       string   `json:"query" jsonschema:"PromQL query of the check, to investigate it further with execute_instant_query or execute_range_query"`
+	Error       string   `json:"error,omitempty" jsonschema:"Why the check could not be evaluated"`
+	Warnings    []string `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+}
+
+// SLOComplianceOutput defines the output schema for the slo_compliance tool.
+type SLOComplianceOutput struct {
+	Target               float64  `json:"target" jsonschema:"SLO target in percent"`
+	SLI                  float64  `json:"sli" jsonschema:"Ratio of good to total events over the time range, in percent"`
+	Compliant            bool     `json:"compliant" jsonschema:"Whether the SLI meets the target"`
+	ErrorBudget          float64  `json:"errorBudget" jsonschema:"Share of events allowed to be bad, in percent (100 - target)"`
+	BurnRate             float64  `json:"burnRate" jsonschema:"Rate the error budget was consumed at over the time range: 1 consumes exactly the budget, above 1 consumes it faster than the SLO allows"`
+	ErrorBudgetRemaining float64  `json:"errorBudgetRemaining" jsonschema:"Share of the error budget left, in percent, taking the time range as the SLO period; negative when the budget is exhausted"`
+	AlignedPoints        int      `json:"alignedPoints" jsonschema:"Number of steps of total_query used; steps without good_query data count as no good events"`
+	WorstStep            *SLOStep `json:"worstStep,omitempty" jsonschema:"Step with the lowest SLI"`
+	Warnings             []string `json:"warnings,omitempty" jsonschema:"Warnings about the results of the queries"`
+}
+
+// SLOStep is the SLI at a single step of an SLO computation.
+type SLOStep struct {
+	Timestamp float64 `json:"timestamp" jsonschema:"Unix timestamp of the step"`
+	SLI       float64 `json:"sli" jsonschema:"Ratio of good to total events at the step, in percent"`
+}
+
+// ExternalLabelsOutput defines the output schema for the get_external_labels tool.
+type ExternalLabelsOutput struct {
+	Labels []ExternalLabel `json:"labels" jsonschema:"External labels attached to every series by the backend"`
+	Source string          `json:"source" jsonschema:"Where the labels were read from: prometheus_config or thanos_stores"`
+}
+
+// ExternalLabel is an external label and the values it takes.
+type ExternalLabel struct {
+	Name   string   `json:"name" jsonschema:"Label name"`
+	Values []string `json:"values" jsonschema:"Values of the label; behind Thanos, one per group of stores"`
+}
+
+// ActiveQueriesOutput defines the output schema for the get_active_queries tool.
+type ActiveQueriesOutput struct {
+	Engines      []QueryEngine `json:"engines" jsonschema:"Query load of each Prometheus instance behind the backend"`
+	TotalQueries float64       `json:"totalQueries" jsonschema:"Number of queries executing or waiting across all engines"`
+	QueryLogFile string        `json:"queryLogFile,omitempty" jsonschema:"File completed queries are logged to on the Prometheus server, when query logging is enabled"`
+	Note         string        `json:"note,omitempty" jsonschema:"Explanation when the query load could not be determined"`
+}
+
+// QueryEngine is the query load of a single Prometheus instance.
+type QueryEngine struct {
+	Labels         map[string]string `json:"labels" jsonschema:"Labels identifying the Prometheus instance"`
+	Queries        float64           `json:"queries" jsonschema:"Number of queries executing or waiting"`
+	MaxConcurrency float64           `json:"maxConcurrency,omitempty" jsonschema:"Maximum number of concurrently executing queries, when known"`
+}
+
+// SeriesUniquenessOutput defines the output schema for the check_series_uniqueness tool.
+type SeriesUniquenessOutput struct {
+	Unique        bool              `json:"unique" jsonschema:"Whether exactly one series matches the selector"`
+	SeriesCount   int               `json:"seriesCount" jsonschema:"Number of series matching the selector"`
+	CommonLabels  map[string]string `json:"commonLabels,omitempty" jsonschema:"Labels that have the same value on every matching series"`
+	VaryingLabels []VaryingLabel    `json:"varyingLabels,omitempty" jsonschema:"Labels whose values differ among the matching series, sorted by name"`
+}
+
+// InspectMetricOutput defines the output schema for the inspect_metric tool.
+type InspectMetricOutput struct {
+	Metric       string      `json:"metric" jsonschema:"Inspected metric name"`
+	SeriesCount  int         `json:"seriesCount" jsonschema:"Number of series of the metric within the time range"`
+	Ambiguous    bool        `json:"ambiguous" jsonschema:"Whether jobs export the metric with different label names, hinting at different meanings"`
+	CommonLabels []string    `json:"commonLabels,omitempty" jsonschema:"Label names, besides job, that the series of every job have, sorted"`
+	Jobs         []MetricJob `json:"jobs" jsonschema:"Jobs exporting the metric, most series first"`
+	Warnings     []string    `json:"warnings,omitempty" jsonschema:"Warnings about mixing the series of different jobs"`
+}
+
+// ScrapeIntervalOutput defines the output schema for the get_scrape_interval tool.
+type ScrapeIntervalOutput struct {
+	Metric          string                `json:"metric" jsonschema:"Metric name or series selector the interval was estimated for"`
+	Interval        string                `json:"interval" jsonschema:"Estimated scrape interval: the median over the series of the median spacing between their samples, e.g. 30s"`
+	IntervalSeconds float64               `json:"intervalSeconds" jsonschema:"Estimated scrape interval in seconds"`
+	MinRateWindow   string                `json:"minRateWindow" jsonschema:"Shortest recommended range for rate() and increase(): 4 times the longest scrape interval of the series"`
+	SeriesCount     int                   `json:"seriesCount" jsonschema:"Number of series the interval was estimated from"`
+	Intervals       []ScrapeIntervalCount `json:"intervals,omitempty" jsonschema:"Scrape intervals of the series and how many series have each, most series first (when they differ)"`
+	Warnings        []string              `json:"warnings,omitempty" jsonschema:"Warnings about differing intervals or series left out"`
+}
+
+// ScrapeIntervalCount is a scrape interval and the number of series scraped at it.
+type ScrapeIntervalCount struct {
+	Interval    string `json:"interval" jsonschema:"Scrape interval, e.g. 30s"`
+	SeriesCount int    `json:"seriesCount" jsonschema:"Number of series scraped at this interval"`
+}
+
+// MetricJob describes the series of a metric exported by one job.
+type MetricJob struct {
+	Job           string   `json:"job" jsonschema:"Value of the job label (empty when the series have no job label)"`
+	SeriesCount   int      `json:"seriesCount" jsonschema:"Number of series of the job"`
+	Labels        []string `json:"labels" jsonschema:"Label names of the series of the job, besides job, sorted"`
+	JobOnlyLabels []string `json:"jobOnlyLabels,omitempty" jsonschema:"Label names that the series of some other jobs do not have"`
+}
+
+// VaryingLabel describes a label that takes different values across the series matching a selector.
+type VaryingLabel struct {
+	Name       string   `json:"name" jsonschema:"Label name"`
+	ValueCount int      `json:"valueCount" jsonschema:"Number of distinct values of the label, counting an absent label as the empty value"`
+	Values     []string `json:"values" jsonschema:"Sample of the distinct values, sorted (an empty string means the label is absent on some series)"`
 }
 
 // RangeQueryOutput defines the output schema for the execute_range_query tool.
 type RangeQueryOutput struct {
-	ResultType string                `json:"resultType" jsonschema:"The type of result returned: matrix or vector or scalar"`
-	Result     []SeriesResult        `json:"result,omitempty" jsonschema:"The query results as an array of time series"`
-	Summary    []SeriesResultSummary `json:"summary,omitempty" jsonschema:"Summary statistics for each time series (when summarize flag is enabled)"`
-	Warnings   []string              `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+	Status        string                `json:"status,omitempty" jsonschema:"Status of the Prometheus API response (when raw_response is 'prometheus')"`
+	Data          *PrometheusData       `json:"data,omitempty" jsonschema:"Data of the Prometheus API response (when raw_response is 'prometheus')"`
+	ResultType    string                `json:"resultType,omitempty" jsonschema:"The type of result returned: matrix or vector or scalar"`
+	Step          string                `json:"step,omitempty" jsonschema:"Step computed from target_points that the values are spaced by (when target_points is set)"`
+	Result        []SeriesResult        `json:"result,omitempty" jsonschema:"The query results as an array of time series"`
+	Summary       []SeriesResultSummary `json:"summary,omitempty" jsonschema:"Summary statistics for each time series (when summarize flag is enabled)"`
+	Shapes        []SeriesShape         `json:"shapes,omitempty" jsonschema:"Description of the shape of each time series instead of its values (when describe_shape is set)"`
+	Sampled       *SamplingInfo         `json:"sampled,omitempty" jsonschema:"How the result was reduced to a sample of its series (when sampling is set and the result exceeds the series limit)"`
+	Page          *PageInfo             `json:"page,omitempty" jsonschema:"Which of the result series this page holds (when page_size or page_token is set)"`
+	NextToken     string                `json:"nextToken,omitempty" jsonschema:"Token to pass as page_token, along with the same query, to get the next page of series; absent on the last page"`
+	Unit          string                `json:"unit,omitempty" jsonschema:"Unit of the values inferred from the query: bytes, bytes/s or seconds (when convert_units is set and the unit is known)"`
+	Warnings      []string              `json:"warnings,omitempty" jsonschema:"Any warnings generated during query execution"`
+	ExecutedQuery *ExecutedQuery        `json:"executedQuery,omitempty" jsonschema:"Query as sent to the backend, with the step actually used (when verbosity is full)"`
+	Stats         *QueryStats           `json:"stats,omitempty" jsonschema:"Size of the backend response and time taken (when verbosity is full)"`
+	DryRun        *DryRunOutput         `json:"dryRun,omitempty" jsonschema:"Requests that would have been sent to the backend (when dry_run is set)"`
+}
+
+// ShowTimeseriesOutput defines the output schema for the show_timeseries tool.
+type ShowTimeseriesOutput struct {
+	Step   string         `json:"step,omitempty" jsonschema:"Step computed from target_points, for the chart to load the data with (when target_points is set)"`
+	Result []SeriesResult `json:"result,omitempty" jsonschema:"Data of the chart, at most target_points per series (when target_points is set)"`
+}
+
+// ExecutedQuery is a query as sent to the backend, with its times resolved.
+type ExecutedQuery struct {
+	Query string `json:"query" jsonschema:"PromQL query"`
+	Time  string `json:"time,omitempty" jsonschema:"Evaluation time of an instant query (RFC3339)"`
+	Start string `json:"start,omitempty" jsonschema:"Start of the range of a range query (RFC3339)"`
+	End   string `json:"end,omitempty" jsonschema:"End of the range of a range query (RFC3339)"`
+	Step  string `json:"step,omitempty" jsonschema:"Step of a range query"`
+}
+
+// QueryStats describes the response of the backend to a query.
+type QueryStats struct {
+	Series   int    `json:"series" jsonschema:"Number of series returned by the backend, before sampling or project_labels"`
+	Samples  int    `json:"samples" jsonschema:"Number of samples returned by the backend"`
+	Duration string `json:"duration" jsonschema:"Time taken to execute the query, including validation requests"`
+}
+
+// SamplingInfo describes a query result reduced to a sample of its series.
+type SamplingInfo struct {
+	TotalSeries    int `json:"totalSeries" jsonschema:"Number of series the query returned"`
+	ReturnedSeries int `json:"returnedSeries" jsonschema:"Number of series in the sample"`
+	TopSeries      int `json:"topSeries" jsonschema:"Number of sampled series picked for having the highest values; the others are picked at random"`
+	Seed           int `json:"seed" jsonschema:"Seed of the random selection; pass it as 'seed' to get the same sample again"`
+}
+
+// PageInfo describes a page of the series of a paginated query result, ordered by labels.
+type PageInfo struct {
+	TotalSeries    int `json:"totalSeries" jsonschema:"Number of series the query returned"`
+	Offset         int `json:"offset" jsonschema:"Position of the first series of the page among all series"`
+	ReturnedSeries int `json:"returnedSeries" jsonschema:"Number of series in the page"`
+}
+
+// DryRunOutput describes the outbound requests of a query executed in dry-run mode.
+type DryRunOutput struct {
+	Requests []DryRunRequest `json:"requests" jsonschema:"HTTP requests made to the backend, in order; the query request itself is not sent"`
+	Error    string          `json:"error,omitempty" jsonschema:"Error that stopped the query before its request was built (e.g. a failed validation call)"`
+}
+
+// DryRunRequest is an HTTP request to the metrics backend, with secrets redacted.
+type DryRunRequest struct {
+	Method  string            `json:"method" jsonschema:"HTTP method"`
+	URL     string            `json:"url" jsonschema:"Full request URL including query parameters"`
+	Headers map[string]string `json:"headers,omitempty" jsonschema:"Request headers, with the values of sensitive headers redacted"`
+	Body    string            `json:"body,omitempty" jsonschema:"Request body (form-encoded query parameters for POST requests)"`
 }
 
 // SeriesResult represents a single time series result from a range query.
 type SeriesResult struct {
-	Metric map[string]string `json:"metric" jsonschema:"The metric labels"`
-	Values [][]any           `json:"values" jsonschema:"Array of [timestamp, value] pairs"`
+	Metric    map[string]string `json:"metric" jsonschema:"The metric labels"`
+	Values    [][]any           `json:"values" jsonschema:"Array of [timestamp, value] pairs; value is null at missing steps when show_gaps is set"`
+	Converted []any             `json:"converted,omitempty" jsonschema:"Values converted to a human-readable unit shared by the series, in the order of values, e.g. 1.5 GiB; null at missing steps (when convert_units is set and the unit is known)"`
+	Stale     bool              `json:"stale,omitempty" jsonschema:"Whether the series ended before the end of the range, e.g. because its target disappeared"`
+	Merged    int               `json:"merged,omitempty" jsonschema:"Number of series whose values were added into this one because they have the same labels after project_labels, when more than one"`
 }
 
 // SeriesResultSummary represents a summary of a time series result from a range query.
@@ -63,11 +366,107 @@ type SeriesResultSummary struct {
 	HasNaN         bool              `json:"hasNaN" jsonschema:"Whether the series contains any NaN values"`
 	HasInf         bool              `json:"hasInf" jsonschema:"Whether the series contains any Inf values"`
 	NonFiniteCount int               `json:"nonFiniteCount" jsonschema:"Count of NaN and Inf values in the series"`
+	Converted      *ConvertedSummary `json:"converted,omitempty" jsonschema:"Summary values converted to a human-readable unit (when convert_units is set and the unit is known)"`
+	Stale          bool              `json:"stale,omitempty" jsonschema:"Whether the series ended before the end of the range, e.g. because its target disappeared"`
+	Merged         int               `json:"merged,omitempty" jsonschema:"Number of series whose values were added into this one because they have the same labels after project_labels, when more than one"`
+}
+
+// SeriesShape describes the shape of a range query series.
+type SeriesShape struct {
+	Series map[string]string `json:"series" jsonschema:"The query result series labelset as a map of label names to values"`
+	Shape  resultutil.Shape  `json:"shape" jsonschema:"Trend, extremes, largest spike and period of the series"`
+	Stale  bool              `json:"stale,omitempty" jsonschema:"Whether the series ended before the end of the range, e.g. because its target disappeared"`
+	Merged int               `json:"merged,omitempty" jsonschema:"Number of series whose values were added into this one because they have the same labels after project_labels, when more than one"`
+}
+
+// ConvertedSummary holds the values of a series summary in a human-readable unit.
+type ConvertedSummary struct {
+	Max        string `json:"max" jsonschema:"Maximum value, e.g. 1.5 GiB"`
+	Min        string `json:"min" jsonschema:"Minimum value"`
+	Avg        string `json:"avg" jsonschema:"Average value"`
+	FirstValue string `json:"firstValue" jsonschema:"Value of the first sample"`
+	LastValue  string `json:"lastValue" jsonschema:"Value of the last sample"`
+	Delta      string `json:"delta" jsonschema:"Difference between last and first values"`
+}
+
+// RecordingRulesOutput defines the output schema for the list_recording_rules tool.
+type RecordingRulesOutput struct {
+	Rules []RecordingRule `json:"rules" jsonschema:"List of recording rules and the metrics they produce"`
+}
+
+// RecordingRule represents a single recording rule and the metric it records.
+type RecordingRule struct {
+	Metric  string            `json:"metric" jsonschema:"Name of the metric produced by the recording rule"`
+	Query   string            `json:"query" jsonschema:"PromQL expression the recorded metric is computed from"`
+	Group   string            `json:"group" jsonschema:"Name of the rule group the recording rule belongs to"`
+	Labels  map[string]string `json:"labels,omitempty" jsonschema:"Extra labels added to the recorded series"`
+	Health  string            `json:"health,omitempty" jsonschema:"Health of the last rule evaluation (ok, err, unknown)"`
+	HasData *bool             `json:"hasData,omitempty" jsonschema:"Whether the recorded metric currently has data in the metrics backend"`
+}
+
+// RuleGroupOutput defines the output schema for the get_rule_group tool.
+type RuleGroupOutput struct {
+	Name     string          `json:"name" jsonschema:"Name of the rule group"`
+	File     string          `json:"file" jsonschema:"Rule file the group is defined in"`
+	Interval string          `json:"interval" jsonschema:"Evaluation interval of the group"`
+	Rules    []RuleGroupRule `json:"rules" jsonschema:"Rules of the group, in evaluation order"`
+	Failing  []string        `json:"failing,omitempty" jsonschema:"Names of the rules whose last evaluation failed (health 'err'); see their lastError"`
+}
+
+// RuleGroupRule is a recording or alerting rule of a rule group with its last evaluation.
+type RuleGroupRule struct {
+	Name               string            `json:"name" jsonschema:"Recorded metric name of a recording rule, or alert name of an alerting rule"`
+	Type               string            `json:"type" jsonschema:"Rule type: recording or alerting"`
+	Query              string            `json:"query" jsonschema:"PromQL expression of the rule"`
+	Labels             map[string]string `json:"labels,omitempty" jsonschema:"Labels the rule adds to its series or alerts"`
+	Health             string            `json:"health" jsonschema:"Health of the last rule evaluation (ok, err, unknown)"`
+	LastError          string            `json:"lastError,omitempty" jsonschema:"Error of the last rule evaluation, when it failed"`
+	LastEvaluation     string            `json:"lastEvaluation,omitempty" jsonschema:"Time of the last rule evaluation (RFC3339); absent when the rule has not been evaluated yet"`
+	EvaluationDuration string            `json:"evaluationDuration" jsonschema:"Time the last rule evaluation took"`
+	State              string            `json:"state,omitempty" jsonschema:"State of an alerting rule: inactive, pending or firing"`
+	ActiveAlerts       int               `json:"activeAlerts,omitempty" jsonschema:"Number of pending or firing alerts of an alerting rule"`
+}
+
+// QueryTemplatesOutput defines the output schema for the list_query_templates tool.
+type QueryTemplatesOutput struct {
+	Templates []QueryTemplate `json:"templates" jsonschema:"List of available query templates"`
+}
+
+// RenderQueryTemplateOutput defines the output schema for the render_query_template tool.
+type RenderQueryTemplateOutput struct {
+	Name  string `json:"name" jsonschema:"Name of the rendered template"`
+	Query string `json:"query" jsonschema:"PromQL query with all placeholders filled in"`
+}
+
+// QueryToPanelOutput defines the output schema for the query_to_panel tool.
+type QueryToPanelOutput struct {
+	Panel PersesPanel `json:"panel" jsonschema:"Perses panel specification, ready to be added to the panels of a dashboard"`
+}
+
+// ParseTimeOutput defines the output schema for the parse_time tool.
+type ParseTimeOutput struct {
+	Input     string `json:"input" jsonschema:"Time expression as given"`
+	Timestamp string `json:"timestamp" jsonschema:"Resolved time as RFC3339 in UTC"`
+	Unix      int64  `json:"unix" jsonschema:"Resolved time as Unix timestamp in seconds"`
+	Relative  string `json:"relative" jsonschema:"Resolved time relative to NOW, e.g. '1h0m0s ago'"`
+	Warning   string `json:"warning,omitempty" jsonschema:"Why the resolved time may not be what was meant"`
+}
+
+// SaveQueryResultOutput defines the output schema for the save_query_result tool.
+type SaveQueryResultOutput struct {
+	Path        string `json:"path" jsonschema:"Path of the file the full result was written to"`
+	Format      string `json:"format" jsonschema:"Format of the file: json or csv"`
+	ResultType  string `json:"resultType" jsonschema:"The type of result returned (e.g. matrix, vector, scalar)"`
+	SeriesCount int    `json:"seriesCount" jsonschema:"Number of series in the result"`
+	SampleCount int    `json:"sampleCount" jsonschema:"Number of samples in the result"`
+	SizeBytes   int64  `json:"sizeBytes" jsonschema:"Size of the written file in bytes"`
 }
 
 // AlertsOutput defines the output schema for the get_alerts tool.
 type AlertsOutput struct {
-	Alerts []Alert `json:"alerts" jsonschema:"List of alerts from Alertmanager"`
+	Alerts  []Alert              `json:"alerts" jsonschema:"List of alerts from Alertmanager (empty when summarized)"`
+	Summary *AlertsSummaryOutput `json:"summary,omitempty" jsonschema:"Summary of the alerts, instead of the list, when too many alerts match and 'full' is not set"`
+	Note    string               `json:"note,omitempty" jsonschema:"Explanation when the alerts were summarized instead of listed"`
 }
 
 // Alert represents a single alert from Alertmanager.
@@ -86,11 +485,87 @@ type AlertStatus struct {
 	InhibitedBy []string `json:"inhibitedBy,omitempty" jsonschema:"List of alerts that are inhibiting this alert"`
 }
 
+// AlertsSummaryOutput defines the output schema for the summarize_alerts tool.
+type AlertsSummaryOutput struct {
+	Total       int          `json:"total" jsonschema:"Number of alerts matching the filters"`
+	BySeverity  []AlertCount `json:"bySeverity" jsonschema:"Number of alerts per severity label value, most severe first"`
+	ByNamespace []AlertCount `json:"byNamespace" jsonschema:"Number of alerts per namespace label value, largest first (at most 25)"`
+	ByAlertname []AlertCount `json:"byAlertname" jsonschema:"Number of alerts per alert name, largest first (at most 25)"`
+	TopAlerts   []TopAlert   `json:"topAlerts" jsonschema:"Alert names to look at first, most severe and then most frequent first"`
+}
+
+// AlertCount is the number of alerts having a label value.
+type AlertCount struct {
+	Value string `json:"value" jsonschema:"Label value; empty for alerts without the label"`
+	Count int    `json:"count" jsonschema:"Number of alerts with the value"`
+}
+
+// TopAlert summarizes the alerts sharing an alert name.
+type TopAlert struct {
+	Alertname  string   `json:"alertname" jsonschema:"Alert name"`
+	Severity   string   `json:"severity,omitempty" jsonschema:"Highest severity among the alerts"`
+	Count      int      `json:"count" jsonschema:"Number of alerts with the name"`
+	Namespaces []string `json:"namespaces,omitempty" jsonschema:"Namespaces the alerts fire in (at most 5)"`
+	Since      string   `json:"since,omitempty" jsonschema:"Start time of the oldest of the alerts (RFC3339)"`
+	Summary    string   `json:"summary,omitempty" jsonschema:"Summary annotation of one of the alerts"`
+}
+
+// AlertHistoryOutput defines the output schema for the get_alert_history tool.
+type AlertHistoryOutput struct {
+	Alerts     []AlertHistory `json:"alerts" jsonschema:"Alerts that were active within the time window, with their active intervals"`
+	Resolution string         `json:"resolution" jsonschema:"Step the ALERTS series was sampled at; interval boundaries are only accurate to this resolution"`
+}
+
+// AlertHistory represents the active intervals of a single alert reconstructed from the ALERTS series.
+type AlertHistory struct {
+	Labels    map[string]string `json:"labels" jsonschema:"Labels of the alert, including alertname and alertstate"`
+	Intervals []AlertInterval   `json:"intervals" jsonschema:"Intervals during which the alert was active, oldest first"`
+}
+
+// AlertInterval is a time interval during which an alert was active.
+type AlertInterval struct {
+	Start   string `json:"start" jsonschema:"First time the alert was seen active in the interval (RFC3339)"`
+	End     string `json:"end" jsonschema:"Last time the alert was seen active in the interval (RFC3339)"`
+	Ongoing bool   `json:"ongoing" jsonschema:"Whether the alert was still active at the end of the time window"`
+}
+
+// AlertThresholdOutput defines the output schema for the get_alert_threshold tool.
+type AlertThresholdOutput struct {
+	Rules []AlertThreshold `json:"rules" jsonschema:"Alerting rules with the given name, one per rule group defining it"`
+}
+
+// AlertThreshold is the threshold of an alerting rule and the current value it is compared against.
+type AlertThreshold struct {
+	Alert      string            `json:"alert" jsonschema:"Name of the alert"`
+	Group      string            `json:"group" jsonschema:"Name of the rule group the alerting rule belongs to"`
+	Expression string            `json:"expression" jsonschema:"PromQL expression of the alerting rule"`
+	For        string            `json:"for,omitempty" jsonschema:"How long the condition must hold before the alert fires"`
+	ValueQuery string            `json:"valueQuery,omitempty" jsonschema:"Side of the comparison holding the evaluated value, as a PromQL query"`
+	Operator   string            `json:"operator,omitempty" jsonschema:"Comparison operator, normalized so that the alert fires while 'value operator threshold' holds"`
+	Threshold  string            `json:"threshold,omitempty" jsonschema:"Constant the value is compared against"`
+	Series     []ThresholdSeries `json:"series,omitempty" jsonschema:"Current values of the value query"`
+	Note       string            `json:"note,omitempty" jsonschema:"Why no threshold or values are returned for the rule"`
+}
+
+// ThresholdSeries is the current value of a series compared against an alert threshold.
+type ThresholdSeries struct {
+	Labels   map[string]string `json:"labels" jsonschema:"Labels of the series"`
+	Value    string            `json:"value" jsonschema:"Current value of the series"`
+	Margin   string            `json:"margin" jsonschema:"Value minus threshold"`
+	Breached bool              `json:"breached" jsonschema:"Whether the value currently meets the alert condition"`
+}
+
 // SilencesOutput defines the output schema for the get_silences tool.
 type SilencesOutput struct {
 	Silences []Silence `json:"silences" jsonschema:"List of silences from Alertmanager"`
 }
 
+// DeleteSilenceOutput defines the output schema for the delete_silence tool.
+type DeleteSilenceOutput struct {
+	ID      string `json:"id" jsonschema:"ID of the expired silence"`
+	Message string `json:"message" jsonschema:"Outcome of the deletion"`
+}
+
 // Silence represents a single silence from Alertmanager.
 type Silence struct {
 	ID        string        `json:"id" jsonschema:"Unique identifier of the silence"`
@@ -107,6 +582,23 @@ type SilenceStatus struct {
 	State string `json:"state" jsonschema:"State of the silence (active, pending, expired)"`
 }
 
+// AlertmanagerStatusOutput defines the output schema for the get_alertmanager_status tool.
+type AlertmanagerStatusOutput struct {
+	Version       string             `json:"version" jsonschema:"Alertmanager version"`
+	Revision      string             `json:"revision,omitempty" jsonschema:"Git revision Alertmanager was built from"`
+	StartedAt     string             `json:"startedAt" jsonschema:"Time the Alertmanager process started (RFC3339)"`
+	ClusterStatus string             `json:"clusterStatus" jsonschema:"Cluster status: ready, settling or disabled when Alertmanager runs without clustering"`
+	ClusterName   string             `json:"clusterName,omitempty" jsonschema:"Name of this Alertmanager in the cluster"`
+	Peers         []AlertmanagerPeer `json:"peers" jsonschema:"Cluster members this Alertmanager sees, including itself"`
+	ConfigHash    string             `json:"configHash" jsonschema:"SHA-256 hash of the loaded configuration; replicas with different hashes run different configurations"`
+}
+
+// AlertmanagerPeer is a member of an Alertmanager cluster.
+type AlertmanagerPeer struct {
+	Name    string `json:"name" jsonschema:"Name of the peer"`
+	Address string `json:"address" jsonschema:"Address of the peer"`
+}
+
 // Matcher represents a label matcher for a silence.
 type Matcher struct {
 	Name    string `json:"name" jsonschema:"Label name to match"`
@@ -120,15 +612,35 @@ type Matcher struct {
 // ListMetricsInput defines the input parameters for ListMetricsHandler.
 type ListMetricsInput struct {
 	NameRegex string `json:"name_regex"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// MetricGroupsInput defines the input parameters for ListMetricGroupsHandler.
+type MetricGroupsInput struct {
+	Prefix string `json:"prefix,omitempty"`
 }
 
 // RangeQueryInput defines the input parameters for ExecuteRangeQueryHandler.
 type RangeQueryInput struct {
-	Query    string `json:"query"`
-	Step     string `json:"step"`
-	Start    string `json:"start,omitempty"`
-	End      string `json:"end,omitempty"`
-	Duration string `json:"duration,omitempty"`
+	Query         string    `json:"query"`
+	Step          StepValue `json:"step"`
+	TargetPoints  int       `json:"target_points,omitempty"`
+	Start         string    `json:"start,omitempty"`
+	End           string    `json:"end,omitempty"`
+	Duration      string    `json:"duration,omitempty"`
+	ShowGaps      bool      `json:"show_gaps,omitempty"`
+	ProjectLabels string    `json:"project_labels,omitempty"`
+	Sampling      bool      `json:"sampling,omitempty"`
+	Seed          int       `json:"seed,omitempty"`
+	Dedup         *bool     `json:"dedup,omitempty"`
+	MaxResolution string    `json:"max_resolution,omitempty"`
+	Verbosity     string    `json:"verbosity,omitempty"`
+	DryRun        bool      `json:"dry_run,omitempty"`
+	RawResponse   string    `json:"raw_response,omitempty"`
+	ConvertUnits  bool      `json:"convert_units,omitempty"`
+	DescribeShape bool      `json:"describe_shape,omitempty"`
+	PageSize      int       `json:"page_size,omitempty"`
+	PageToken     string    `json:"page_token,omitempty"`
 }
 
 // ShowTimeseriesInput defines the input parameters for ShowTimeseriesHandler.
@@ -138,10 +650,70 @@ type ShowTimeseriesInput struct {
 	Description string `json:"description,omitempty"`
 }
 
+// HeatmapInput defines the input parameters for QueryHeatmapHandler.
+type HeatmapInput struct {
+	Metric   string    `json:"metric"`
+	Selector string    `json:"selector,omitempty"`
+	Step     StepValue `json:"step"`
+	Start    string    `json:"start,omitempty"`
+	End      string    `json:"end,omitempty"`
+	Duration string    `json:"duration,omitempty"`
+}
+
+// LabelCardinalityTrendInput defines the input parameters for LabelCardinalityTrendHandler.
+type LabelCardinalityTrendInput struct {
+	Metric   string    `json:"metric"`
+	Label    string    `json:"label"`
+	Selector string    `json:"selector,omitempty"`
+	Step     StepValue `json:"step"`
+	Start    string    `json:"start,omitempty"`
+	End      string    `json:"end,omitempty"`
+	Duration string    `json:"duration,omitempty"`
+}
+
+// EvaluateConditionInput defines the input parameters for EvaluateConditionHandler.
+type EvaluateConditionInput struct {
+	Query    string    `json:"query"`
+	Step     StepValue `json:"step"`
+	Start    string    `json:"start,omitempty"`
+	End      string    `json:"end,omitempty"`
+	Duration string    `json:"duration,omitempty"`
+	For      string    `json:"for,omitempty"`
+}
+
+// ClusterStatusInput defines the input parameters for ClusterStatusHandler.
+type ClusterStatusInput struct {
+	Checks []string `json:"checks,omitempty"`
+}
+
+// SLOComplianceInput defines the input parameters for SLOComplianceHandler.
+type SLOComplianceInput struct {
+	GoodQuery  string    `json:"good_query"`
+	TotalQuery string    `json:"total_query"`
+	Target     float64   `json:"target"`
+	Step       StepValue `json:"step"`
+	Start      string    `json:"start,omitempty"`
+	End        string    `json:"end,omitempty"`
+	Duration   string    `json:"duration,omitempty"`
+}
+
 // InstantQueryInput defines the input parameters for ExecuteInstantQueryHandler.
 type InstantQueryInput struct {
-	Query string `json:"query"`
-	Time  string `json:"time,omitempty"`
+	Query         string `json:"query"`
+	Time          string `json:"time,omitempty"`
+	GroupBy       string `json:"group_by,omitempty"`
+	GroupAgg      string `json:"group_agg,omitempty"`
+	Nearest       bool   `json:"nearest,omitempty"`
+	LabelsOnly    bool   `json:"labels_only,omitempty"`
+	ProjectLabels string `json:"project_labels,omitempty"`
+	Sampling      bool   `json:"sampling,omitempty"`
+	Seed          int    `json:"seed,omitempty"`
+	Dedup         *bool  `json:"dedup,omitempty"`
+	MaxResolution string `json:"max_resolution,omitempty"`
+	Verbosity     string `json:"verbosity,omitempty"`
+	DryRun        bool   `json:"dry_run,omitempty"`
+	ConvertUnits  bool   `json:"convert_units,omitempty"`
+	Rank          bool   `json:"rank,omitempty"`
 }
 
 // LabelNamesInput defines the input parameters for GetLabelNamesHandler.
@@ -153,30 +725,155 @@ type LabelNamesInput struct {
 
 // LabelValuesInput defines the input parameters for GetLabelValuesHandler.
 type LabelValuesInput struct {
-	Label  string `json:"label"`
-	Metric string `json:"metric,omitempty"`
-	Start  string `json:"start,omitempty"`
-	End    string `json:"end,omitempty"`
+	Label       string `json:"label"`
+	Metric      string `json:"metric,omitempty"`
+	Start       string `json:"start,omitempty"`
+	End         string `json:"end,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
+	ByFrequency bool   `json:"by_frequency,omitempty"`
+}
+
+// LabelsOverviewInput defines the input parameters for GetLabelsOverviewHandler.
+type LabelsOverviewInput struct {
+	Metric string   `json:"metric"`
+	Labels []string `json:"labels"`
+	Start  string   `json:"start,omitempty"`
+	End    string   `json:"end,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
 }
 
 // SeriesInput defines the input parameters for GetSeriesHandler.
 type SeriesInput struct {
-	Matches string `json:"matches"`
-	Start   string `json:"start,omitempty"`
-	End     string `json:"end,omitempty"`
+	Matches      string `json:"matches"`
+	Start        string `json:"start,omitempty"`
+	End          string `json:"end,omitempty"`
+	WithLastSeen bool   `json:"with_last_seen,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// SeriesUniquenessInput defines the input parameters for CheckSeriesUniquenessHandler.
+type SeriesUniquenessInput struct {
+	Selector string `json:"selector"`
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+}
+
+// InspectMetricInput defines the input parameters for InspectMetricHandler.
+type InspectMetricInput struct {
+	Metric string `json:"metric"`
+	Start  string `json:"start,omitempty"`
+	End    string `json:"end,omitempty"`
+}
+
+// MetricMetadataInput defines the input parameters for GetMetricMetadataHandler.
+type MetricMetadataInput struct {
+	Metric string `json:"metric,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// ScrapeIntervalInput defines the input parameters for GetScrapeIntervalHandler.
+type ScrapeIntervalInput struct {
+	Metric string `json:"metric"`
+	Window string `json:"window,omitempty"`
+}
+
+// ActiveQueriesInput defines the input parameters for GetActiveQueriesHandler.
+type ActiveQueriesInput struct{}
+
+// ExternalLabelsInput defines the input parameters for GetExternalLabelsHandler.
+type ExternalLabelsInput struct{}
+
+// RecordingRulesInput defines the input parameters for ListRecordingRulesHandler.
+type RecordingRulesInput struct {
+	NameRegex string `json:"name_regex,omitempty"`
+}
+
+// RuleGroupInput defines the input parameters for GetRuleGroupHandler.
+type RuleGroupInput struct {
+	Group string `json:"group"`
+	File  string `json:"file,omitempty"`
+}
+
+// QueryTemplatesInput defines the input parameters for ListQueryTemplatesHandler.
+type QueryTemplatesInput struct{}
+
+// RenderQueryTemplateInput defines the input parameters for RenderQueryTemplateHandler.
+type RenderQueryTemplateInput struct {
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// QueryToPanelInput defines the input parameters for QueryToPanelHandler.
+type QueryToPanelInput struct {
+	Query       string `json:"query"`
+	Title       string `json:"title"`
+	ChartType   string `json:"chart_type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ParseTimeInput defines the input parameters for ParseTimeHandler.
+type ParseTimeInput struct {
+	Time string `json:"time"`
+}
+
+// SaveQueryResultInput defines the input parameters for SaveQueryResultHandler.
+type SaveQueryResultInput struct {
+	Query    string    `json:"query"`
+	Time     string    `json:"time,omitempty"`
+	Step     StepValue `json:"step,omitempty"`
+	Start    string    `json:"start,omitempty"`
+	End      string    `json:"end,omitempty"`
+	Duration string    `json:"duration,omitempty"`
+	Format   string    `json:"format,omitempty"`
+	Filename string    `json:"filename,omitempty"`
 }
 
 // AlertsInput defines the input parameters for GetAlertsHandler.
 type AlertsInput struct {
-	Active      *bool  `json:"active,omitempty"`
-	Silenced    *bool  `json:"silenced,omitempty"`
-	Inhibited   *bool  `json:"inhibited,omitempty"`
-	Unprocessed *bool  `json:"unprocessed,omitempty"`
-	Filter      string `json:"filter,omitempty"`
-	Receiver    string `json:"receiver,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Silenced    *bool    `json:"silenced,omitempty"`
+	Inhibited   *bool    `json:"inhibited,omitempty"`
+	Unprocessed *bool    `json:"unprocessed,omitempty"`
+	Filter      string   `json:"filter,omitempty"`
+	AnyOf       []string `json:"any_of,omitempty"`
+	Receiver    string   `json:"receiver,omitempty"`
+	MinActive   string   `json:"min_active_duration,omitempty"`
+	Full        bool     `json:"full,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// SummarizeAlertsInput defines the input parameters for SummarizeAlertsHandler.
+type SummarizeAlertsInput struct {
+	Active    *bool  `json:"active,omitempty"`
+	Silenced  *bool  `json:"silenced,omitempty"`
+	Inhibited *bool  `json:"inhibited,omitempty"`
+	Filter    string `json:"filter,omitempty"`
+	Receiver  string `json:"receiver,omitempty"`
+	Top       int    `json:"top,omitempty"`
+}
+
+// AlertHistoryInput defines the input parameters for GetAlertHistoryHandler.
+type AlertHistoryInput struct {
+	Since  string `json:"since,omitempty"`
+	Until  string `json:"until,omitempty"`
+	Filter string `json:"filter,omitempty"`
+}
+
+// AlertThresholdInput defines the input parameters for GetAlertThresholdHandler.
+type AlertThresholdInput struct {
+	AlertName string `json:"alertname"`
 }
 
 // SilencesInput defines the input parameters for GetSilencesHandler.
 type SilencesInput struct {
 	Filter string `json:"filter,omitempty"`
 }
+
+// DeleteSilenceInput defines the input parameters for DeleteSilenceHandler.
+type DeleteSilenceInput struct {
+	ID string `json:"id"`
+}
+
+// AlertmanagerStatusInput defines the input parameters for GetAlertmanagerStatusHandler.
+type AlertmanagerStatusInput struct{}
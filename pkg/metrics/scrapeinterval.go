@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// rateWindowFactor is how many scrape intervals a rate window should span at least, so
+// that it holds enough samples even when a scrape is missed.
+const rateWindowFactor = 4
+
+// defaultScrapeIntervalWindow is how far back get_scrape_interval looks at samples by
+// default, holding 10 samples at the common 1m scrape interval.
+const defaultScrapeIntervalWindow = "10m"
+
+// sampleSpacing returns the median spacing between the consecutive samples of a series,
+// rounded to the second above one second to hide scrape jitter, and false for series
+// with fewer than two samples.
+func sampleSpacing(values []model.SamplePair) (time.Duration, bool) {
+	if len(values) < 2 {
+		return 0, false
+	}
+	gaps := make([]time.Duration, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		gaps[i-1] = values[i].Timestamp.Time().Sub(values[i-1].Timestamp.Time())
+	}
+	slices.Sort(gaps)
+	median := gaps[len(gaps)/2]
+	if len(gaps)%2 == 0 {
+		median = (gaps[len(gaps)/2-1] + median) / 2
+	}
+	if median >= time.Second {
+		return median.Round(time.Second), true
+	}
+	return median.Round(time.Millisecond), true
+}
+
+// estimateScrapeInterval estimates the scrape interval of the series of a metric from
+// the median spacing of their samples. The interval reported is the median over the
+// series, and the rate window covers the longest interval, so that it suits every series.
+func estimateScrapeInterval(metric string, matrix model.Matrix) (ScrapeIntervalOutput, bool) {
+	var spacings []time.Duration
+	counts := make(map[time.Duration]int)
+	for _, series := range matrix {
+		if spacing, ok := sampleSpacing(series.Values); ok {
+			spacings = append(spacings, spacing)
+			counts[spacing]++
+		}
+	}
+	if len(spacings) == 0 {
+		return ScrapeIntervalOutput{}, false
+	}
+	slices.Sort(spacings)
+	interval := spacings[len(spacings)/2]
+	longest := spacings[len(spacings)-1]
+
+	output := ScrapeIntervalOutput{
+		Metric:          metric,
+		Interval:        model.Duration(interval).String(),
+		IntervalSeconds: interval.Seconds(),
+		MinRateWindow:   model.Duration(rateWindowFactor * longest).String(),
+		SeriesCount:     len(spacings),
+	}
+	if len(counts) > 1 {
+		for _, spacing := range slices.Sorted(maps.Keys(counts)) {
+			output.Intervals = append(output.Intervals, ScrapeIntervalCount{
+				Interval:    model.Duration(spacing).String(),
+				SeriesCount: counts[spacing],
+			})
+		}
+		slices.SortStableFunc(output.Intervals, func(a, b ScrapeIntervalCount) int { return cmp.Compare(b.SeriesCount, a.SeriesCount) })
+		output.Warnings = append(output.Warnings, fmt.Sprintf(
+			"the series of %s are scraped at different intervals; minRateWindow covers the longest one, filter the series to use a shorter window", metric))
+	}
+	if skipped := len(matrix) - len(spacings); skipped > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf(
+			"%d series with fewer than two samples in the window were left out", skipped))
+	}
+	return output, true
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// scrapedSeries returns a series with samples at the given offsets in milliseconds.
+func scrapedSeries(job string, offsets ...int64) *model.SampleStream {
+	s := &model.SampleStream{Metric: model.Metric{"job": model.LabelValue(job)}}
+	for _, offset := range offsets {
+		s.Values = append(s.Values, model.SamplePair{Timestamp: model.Time(offset), Value: 1})
+	}
+	return s
+}
+
+func TestSampleSpacing(t *testing.T) {
+	tests := []struct {
+		name    string
+		offsets []int64
+		want    time.Duration
+		wantOK  bool
+	}{
+		{"jittered scrapes", []int64{0, 30_004, 59_998, 90_001}, 30 * time.Second, true},
+		{"missed scrape", []int64{0, 15_000, 30_000, 60_000, 75_000}, 15 * time.Second, true},
+		{"even number of gaps", []int64{0, 10_000, 30_000}, 15 * time.Second, true},
+		{"sub-second interval", []int64{0, 500, 1000}, 500 * time.Millisecond, true},
+		{"single sample", []int64{0}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sampleSpacing(scrapedSeries("api", tt.offsets...).Values)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("sampleSpacing() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEstimateScrapeInterval(t *testing.T) {
+	t.Run("same interval", func(t *testing.T) {
+		matrix := model.Matrix{
+			scrapedSeries("api", 0, 30_000, 60_000),
+			scrapedSeries("api", 10_000, 40_000, 70_000),
+		}
+		output, ok := estimateScrapeInterval("up", matrix)
+		if !ok {
+			t.Fatal("expected an estimate")
+		}
+		want := ScrapeIntervalOutput{Metric: "up", Interval: "30s", IntervalSeconds: 30, MinRateWindow: "2m", SeriesCount: 2}
+		if !reflect.DeepEqual(output, want) {
+			t.Errorf("estimateScrapeInterval() = %+v, want %+v", output, want)
+		}
+	})
+
+	t.Run("differing intervals", func(t *testing.T) {
+		matrix := model.Matrix{
+			scrapedSeries("api", 0, 15_000, 30_000),
+			scrapedSeries("api", 0, 15_000, 30_000),
+			scrapedSeries("node", 0, 60_000, 120_000),
+			scrapedSeries("new"),
+		}
+		output, ok := estimateScrapeInterval("up", matrix)
+		if !ok {
+			t.Fatal("expected an estimate")
+		}
+		// The interval is the median over the series, the rate window covers the longest.
+		if output.Interval != "15s" || output.MinRateWindow != "4m" || output.SeriesCount != 3 {
+			t.Errorf("got interval %s, rate window %s and %d series, want 15s, 4m and 3", output.Interval, output.MinRateWindow, output.SeriesCount)
+		}
+		wantIntervals := []ScrapeIntervalCount{{Interval: "15s", SeriesCount: 2}, {Interval: "1m", SeriesCount: 1}}
+		if !reflect.DeepEqual(output.Intervals, wantIntervals) {
+			t.Errorf("intervals = %+v, want %+v", output.Intervals, wantIntervals)
+		}
+		if len(output.Warnings) != 2 {
+			t.Errorf("expected warnings about differing intervals and the series left out, got %v", output.Warnings)
+		}
+	})
+
+	t.Run("no series with two samples", func(t *testing.T) {
+		if _, ok := estimateScrapeInterval("up", model.Matrix{scrapedSeries("api", 0)}); ok {
+			t.Error("expected no estimate")
+		}
+	})
+}
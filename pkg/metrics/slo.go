@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/prometheus/common/model"
+)
+
+// sloPoints sums the series of an SLO query result per timestamp, skipping NaN and
+// infinite values, e.g. from rates of series without requests. It also returns the
+// number of series summed.
+func sloPoints(matrix model.Matrix) (map[model.Time]float64, int) {
+	points := make(map[model.Time]float64)
+	for _, series := range matrix {
+		for _, sample := range series.Values {
+			v := float64(sample.Value)
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			points[sample.Timestamp] += v
+		}
+	}
+	return points, len(matrix)
+}
+
+// computeSLOCompliance compares the ratio of good to total events to target, a
+// percentage. The good and total results are aligned on the timestamps of total: a step
+// missing from good counts as no good events, as queries like
+// sum(rate(requests{code="200"}[5m])) return nothing when there are none, while good
+// steps without total are left out.
+func computeSLOCompliance(good, total model.Matrix, target float64) (SLOComplianceOutput, error) {
+	goodPoints, goodSeries := sloPoints(good)
+	totalPoints, totalSeries := sloPoints(total)
+
+	output := SLOComplianceOutput{Target: target}
+	for param, series := range map[string]int{"good_query": goodSeries, "total_query": totalSeries} {
+		if series > 1 {
+			output.Warnings = append(output.Warnings, fmt.Sprintf(
+				"%s returned %d series, which were summed; aggregate it with sum() to make this explicit", param, series))
+		}
+	}
+	slices.Sort(output.Warnings)
+
+	if len(good) == 0 {
+		output.Warnings = append(output.Warnings, "good_query returned no data; it was counted as no good events")
+	}
+
+	var goodSum, totalSum float64
+	missingGood := 0
+	worst := math.Inf(1)
+	for _, ts := range slices.Sorted(maps.Keys(totalPoints)) {
+		g, ok := goodPoints[ts]
+		if !ok {
+			missingGood++
+		}
+		t := totalPoints[ts]
+		goodSum += g
+		totalSum += t
+		output.AlignedPoints++
+		if t > 0 && g/t < worst {
+			worst = g / t
+			output.WorstStep = &SLOStep{
+				Timestamp: float64(ts) / millisecondsPerSecond,
+				SLI:       roundSLO(100 * g / t),
+			}
+		}
+	}
+	if missingGood > 0 && len(good) > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf(
+			"%d steps without good_query data were counted as no good events", missingGood))
+	}
+	if dropped := len(goodPoints) - (output.AlignedPoints - missingGood); dropped > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf(
+			"%d good_query points without total_query data were left out", dropped))
+	}
+	if totalSum <= 0 {
+		return SLOComplianceOutput{}, fmt.Errorf("total_query returned no events in the time range")
+	}
+	if goodSum > totalSum {
+		output.Warnings = append(output.Warnings,
+			"good_query counts more events than total_query; check that it selects a subset of the total events")
+	}
+
+	sli := 100 * goodSum / totalSum
+	budget := 100 - target
+	output.SLI = roundSLO(sli)
+	output.Compliant = sli >= target
+	output.ErrorBudget = roundSLO(budget)
+	output.BurnRate = roundSLO((100 - sli) / budget)
+	output.ErrorBudgetRemaining = roundSLO(100 * (1 - (100-sli)/budget))
+	return output, nil
+}
+
+// roundSLO rounds an SLO figure to 4 decimals, enough to tell 99.99% from 99.999%
+// without float noise.
+func roundSLO(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
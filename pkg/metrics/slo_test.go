@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func sloSeries(values ...float64) *model.SampleStream {
+	s := &model.SampleStream{Metric: model.Metric{}}
+	for i, v := range values {
+		s.Values = append(s.Values, model.SamplePair{Timestamp: model.Time(i * 60000), Value: model.SampleValue(v)})
+	}
+	return s
+}
+
+func TestComputeSLOCompliance(t *testing.T) {
+	// The last good point has no total and is left out. Of the remaining events, 2990 of
+	// 3000 are good: a third of the error budget of a 99% target. The worst step has 90 of
+	// 100 good events.
+	good := model.Matrix{sloSeries(1000, 90, 1900, 1000)}
+	total := model.Matrix{sloSeries(1000, 100, 1900, 2000)}
+	total[0].Values = total[0].Values[:3]
+
+	output, err := computeSLOCompliance(good, total, 99)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := SLOComplianceOutput{
+		Target:               99,
+		SLI:                  99.6667,
+		Compliant:            true,
+		ErrorBudget:          1,
+		BurnRate:             0.3333,
+		ErrorBudgetRemaining: 66.6667,
+		AlignedPoints:        3,
+		WorstStep:            &SLOStep{Timestamp: 60, SLI: 90},
+		Warnings:             []string{"1 good_query points without total_query data were left out"},
+	}
+	if !reflect.DeepEqual(output, want) {
+		t.Errorf("output = %+v, want %+v", output, want)
+	}
+}
+
+func TestComputeSLOCompliance_Breached(t *testing.T) {
+	good := model.Matrix{sloSeries(990, math.NaN()), sloSeries(0, 5)}
+	total := model.Matrix{sloSeries(1000, 10)}
+
+	output, err := computeSLOCompliance(good, total, 99.9)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The NaN point is skipped and the two good series are summed: 995 of 1010.
+	if output.Compliant || output.SLI != 98.5149 || output.BurnRate != 14.8515 || output.ErrorBudgetRemaining >= 0 {
+		t.Errorf("output = %+v, want a breached SLO", output)
+	}
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "good_query returned 2 series") {
+		t.Errorf("warnings = %v, want a warning about the summed series", output.Warnings)
+	}
+}
+
+func TestComputeSLOCompliance_NoGoodEvents(t *testing.T) {
+	output, err := computeSLOCompliance(nil, model.Matrix{sloSeries(10, 10)}, 99)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.SLI != 0 || output.AlignedPoints != 2 || output.Compliant {
+		t.Errorf("output = %+v, want an SLI of 0 over both steps", output)
+	}
+}
+
+func TestComputeSLOCompliance_GapInGood(t *testing.T) {
+	// The comparison drops the good series at the second step, which had no good events.
+	good := model.Matrix{sloSeries(100, 0, 100)}
+	good[0].Values = slices.Delete(good[0].Values, 1, 2)
+	total := model.Matrix{sloSeries(100, 100, 100)}
+
+	output, err := computeSLOCompliance(good, total, 99)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.SLI != 66.6667 || output.AlignedPoints != 3 || output.Compliant {
+		t.Errorf("output = %+v, want 200 of 300 good events over 3 steps", output)
+	}
+	if output.WorstStep == nil || output.WorstStep.Timestamp != 60 || output.WorstStep.SLI != 0 {
+		t.Errorf("worstStep = %+v, want the step without good events", output.WorstStep)
+	}
+	want := []string{"1 steps without good_query data were counted as no good events"}
+	if !reflect.DeepEqual(output.Warnings, want) {
+		t.Errorf("warnings = %v, want %v", output.Warnings, want)
+	}
+}
+
+func TestComputeSLOCompliance_NoTotalEvents(t *testing.T) {
+	if _, err := computeSLOCompliance(model.Matrix{sloSeries(0)}, model.Matrix{sloSeries(0)}, 99); err == nil {
+		t.Error("expected an error without events")
+	}
+}
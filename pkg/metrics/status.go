@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
+)
+
+// Statuses of the checks of the cluster_status tool, from best to worst.
+const (
+	StatusOK       = "ok"
+	StatusWarning  = "warning"
+	StatusCritical = "critical"
+	StatusUnknown  = "unknown"
+)
+
+// StatusCheck is a canned instant query cluster_status evaluates. Higher values are
+// worse: the check is critical above Critical, warning above Warning and ok otherwise.
+type StatusCheck struct {
+	Name        string  `toml:"name"`
+	Description string  `toml:"description,omitempty"`
+	Query       string  `toml:"query"`
+	Warning     float64 `toml:"warning"`
+	Critical    float64 `toml:"critical"`
+}
+
+// DefaultStatusChecks are the checks cluster_status runs unless status_checks is configured.
+var DefaultStatusChecks = []StatusCheck{
+	{
+		Name:        "node_readiness",
+		Description: "Percentage of nodes that are not Ready",
+		Query:       `100 * (1 - avg(kube_node_status_condition{condition="Ready",status="true"}))`,
+		Warning:     0,
+		Critical:    10,
+	},
+	{
+		Name:        "pod_restarts",
+		Description: "Number of containers restarted more than 3 times in the last hour",
+		Query:       `sum(increase(kube_pod_container_status_restarts_total{job="kube-state-metrics"}[1h]) > bool 3)`,
+		Warning:     0,
+		Critical:    5,
+	},
+	{
+		Name:        "api_latency",
+		Description: "99th percentile latency of Kubernetes API requests over the last 5 minutes, in seconds",
+		Query:       `histogram_quantile(0.99, sum by (le) (rate(apiserver_request_duration_seconds_bucket{job="apiserver",verb!~"WATCH|CONNECT"}[5m])))`,
+		Warning:     1,
+		Critical:    4,
+	},
+	{
+		Name:        "etcd_health",
+		Description: "Whether an etcd member has no leader (1) or all members have one (0)",
+		Query:       `max(1 - etcd_server_has_leader{job="etcd"})`,
+		Warning:     0,
+		Critical:    0,
+	},
+}
+
+// validateStatusChecks checks that the configured status checks have unique names,
+// valid queries and a critical threshold no lower than the warning one.
+func validateStatusChecks(checks []StatusCheck) error {
+	names := make(map[string]bool)
+	for i, check := range checks {
+		if check.Name == "" {
+			return fmt.Errorf("invalid status_checks[%d]: name is required", i)
+		}
+		if names[check.Name] {
+			return fmt.Errorf("invalid status_checks[%d]: duplicate name %q", i, check.Name)
+		}
+		names[check.Name] = true
+		if _, err := parser.NewParser(parser.Options{}).ParseExpr(check.Query); err != nil {
+			return fmt.Errorf("invalid status_checks[%d] (%s) query: %w", i, check.Name, err)
+		}
+		if check.Critical < check.Warning {
+			return fmt.Errorf("invalid status_checks[%d] (%s): critical threshold %v is lower than warning threshold %v", i, check.Name, check.Critical, check.Warning)
+		}
+	}
+	return nil
+}
+
+// statusRank orders statuses from best to worst. Unknown ranks between ok and warning:
+// a check that could not be evaluated is not a known problem, but the cluster cannot be
+// reported healthy either.
+var statusRank = map[string]int{
+	StatusOK:       0,
+	StatusUnknown:  1,
+	StatusWarning:  2,
+	StatusCritical: 3,
+}
+
+// runStatusChecks evaluates the checks concurrently at NOW. A check that fails or returns
+// no data is reported as unknown with its error, without affecting the other checks.
+func runStatusChecks(ctx context.Context, promClient prometheus.Loader, checks []StatusCheck) ClusterStatusOutput {
+	now := prometheus.Now(ctx)
+	results := make([]StatusCheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			results[i] = runStatusCheck(ctx, promClient, check, now)
+		})
+	}
+	wg.Wait()
+
+	output := ClusterStatusOutput{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if statusRank[result.Status] > statusRank[output.Status] {
+			output.Status = result.Status
+		}
+	}
+	return output
+}
+
+func runStatusCheck(ctx context.Context, promClient prometheus.Loader, check StatusCheck, now time.Time) StatusCheckResult {
+	result := StatusCheckResult{
+		Name:        check.Name,
+		Description: check.Description,
+		Query:       check.Query,
+		Status:      StatusUnknown,
+	}
+
+	response, err := promClient.ExecuteInstantQuery(ctx, check.Query, now)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if warnings, ok := response["warnings"].([]string); ok {
+		result.Warnings = warnings
+	}
+
+	// The worst, i.e. highest, value of a check returning several series decides its status.
+	value := math.Inf(-1)
+	switch v := response["result"].(type) {
+	case model.Vector:
+		for _, sample := range v {
+			if !math.IsNaN(float64(sample.Value)) {
+				value = math.Max(value, float64(sample.Value))
+			}
+		}
+	case *model.Scalar:
+		if !math.IsNaN(float64(v.Value)) {
+			value = float64(v.Value)
+		}
+	}
+	if math.IsInf(value, -1) {
+		result.Error = "the query returned no data"
+		return result
+	}
+
+	// An infinite value, e.g. of a quantile above the highest bucket, cannot be encoded
+	// in the result but still decides the status.
+	if !math.IsInf(value, 1) {
+		result.Value = &value
+	}
+	switch {
+	case value > check.Critical:
+		result.Status = StatusCritical
+	case value > check.Warning:
+		result.Status = StatusWarning
+	default:
+		result.Status = StatusOK
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
+)
+
+// StepPolicy controls how range queries with a step larger than their time range are handled.
+// Such queries return at most one data point per series.
+type StepPolicy string
+
+const (
+	// StepPolicyReject fails the query with a validation error.
+	StepPolicyReject StepPolicy = "reject"
+	// StepPolicyShrink reduces the step to fit the range and reports it in the query warnings.
+	StepPolicyShrink StepPolicy = "shrink"
+)
+
+// shrunkStepPoints is the number of steps a range is split into when the step is shrunk.
+const shrunkStepPoints = 60
+
+// ParseStepPolicy validates and converts a string to a StepPolicy.
+func ParseStepPolicy(policy string) (StepPolicy, error) {
+	switch StepPolicy(policy) {
+	case StepPolicyReject, StepPolicyShrink:
+		return StepPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("invalid step policy: %q (valid options: %q, %q)", policy, StepPolicyReject, StepPolicyShrink)
+	}
+}
+
+// fitStepToRange applies the policy to a step larger than the query range. It returns the
+// step to use and a warning when the step was changed. Steps within the range are kept.
+func fitStepToRange(step, queryRange time.Duration, policy StepPolicy) (time.Duration, string, error) {
+	if queryRange <= 0 || step <= queryRange {
+		return step, "", nil
+	}
+
+	shrunk := max(time.Second, (queryRange / shrunkStepPoints).Truncate(time.Second))
+	if policy != StepPolicyShrink {
+		return 0, "", fmt.Errorf("step %s is larger than the query range %s, which returns at most one data point per series; use a smaller step (e.g. %s)",
+			model.Duration(step), model.Duration(queryRange), model.Duration(shrunk))
+	}
+	return shrunk, fmt.Sprintf("step %s is larger than the query range %s and was reduced to %s",
+		model.Duration(step), model.Duration(queryRange), model.Duration(shrunk)), nil
+}
+
+// stepForTargetPoints returns the step returning at most points data points per series
+// over queryRange, e.g. the width of a chart in pixels, rounded up to the second.
+func stepForTargetPoints(queryRange time.Duration, points int) (time.Duration, error) {
+	if points < 2 || points > prometheus.MaxPointsPerQuery {
+		return 0, fmt.Errorf("target_points must be between 2 and %d, got %d", prometheus.MaxPointsPerQuery, points)
+	}
+	if queryRange <= 0 {
+		return 0, fmt.Errorf("target_points requires an end time after the start time")
+	}
+	// A range split into n steps holds n+1 points, counting both ends.
+	step := queryRange / time.Duration(points-1)
+	if step%time.Second != 0 || step*time.Duration(points-1) < queryRange {
+		step = step.Truncate(time.Second) + time.Second
+	}
+	return step, nil
+}
+
+// StepValue is a range query step, given either as a Prometheus duration (e.g. "1m")
+// or as a number of seconds (e.g. 60 or "60"). Numbers are kept in their string form.
+type StepValue string
+
+// UnmarshalJSON accepts both JSON strings and JSON numbers.
+func (s *StepValue) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = StepValue(str)
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return fmt.Errorf("step must be a duration string or a number of seconds")
+	}
+	*s = StepValue(num.String())
+	return nil
+}
+
+// Duration parses the step, treating a number, in any JSON number form such as 60,
+// 0.5 or 6e1, as seconds.
+func (s StepValue) Duration() (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(string(s), 64); err == nil {
+		step := time.Duration(seconds * float64(time.Second))
+		if math.IsNaN(seconds) || math.IsInf(seconds, 0) || step <= 0 {
+			return 0, fmt.Errorf("step must be a positive number of seconds, got %q", string(s))
+		}
+		return step, nil
+	}
+
+	step, err := model.ParseDuration(string(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(step), nil
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+// TemplateParamKind describes how a query template parameter value is validated and substituted.
+type TemplateParamKind string
+
+const (
+	// TemplateParamLabelValue is substituted inside a double-quoted label matcher value.
+	TemplateParamLabelValue TemplateParamKind = "label_value"
+	// TemplateParamDuration is substituted as a PromQL duration (e.g. a range selector window).
+	TemplateParamDuration TemplateParamKind = "duration"
+)
+
+// QueryTemplateParam describes a placeholder of a query template.
+type QueryTemplateParam struct {
+	Name        string            `json:"name" jsonschema:"Name of the placeholder, referenced as ${name} in the query"`
+	Kind        TemplateParamKind `json:"kind" jsonschema:"Kind of value expected: label_value or duration"`
+	Description string            `json:"description" jsonschema:"What the placeholder value represents"`
+	Default     string            `json:"default,omitempty" jsonschema:"Value used when the placeholder is not provided (required otherwise)"`
+}
+
+// QueryTemplate is a named, parameterized PromQL query for a common question.
+type QueryTemplate struct {
+	Name        string               `json:"name" jsonschema:"Unique name of the template"`
+	Description string               `json:"description" jsonschema:"What the rendered query answers"`
+	Query       string               `json:"query" jsonschema:"PromQL query with ${name} placeholders"`
+	Params      []QueryTemplateParam `json:"params" jsonschema:"Placeholders that can be filled in when rendering the template"`
+}
+
+var templatePlaceholderRe = regexp.MustCompile(`\$\{([a-z_]+)\}`)
+
+// QueryTemplates holds the built-in query templates keyed by name.
+// New templates only need to be added here to be listed and rendered by the template tools.
+var QueryTemplates = map[string]QueryTemplate{
+	"pod_cpu_usage": {
+		Name:        "pod_cpu_usage",
+		Description: "CPU usage in cores per pod in a namespace",
+		Query:       `sum by (pod) (rate(container_cpu_usage_seconds_total{namespace="${namespace}", container!=""}[${window}]))`,
+		Params: []QueryTemplateParam{
+			{Name: "namespace", Kind: TemplateParamLabelValue, Description: "Namespace of the pods"},
+			{Name: "window", Kind: TemplateParamDuration, Description: "Rate window", Default: "5m"},
+		},
+	},
+	"pod_memory_usage": {
+		Name:        "pod_memory_usage",
+		Description: "Working set memory in bytes per pod in a namespace",
+		Query:       `sum by (pod) (container_memory_working_set_bytes{namespace="${namespace}", container!=""})`,
+		Params: []QueryTemplateParam{
+			{Name: "namespace", Kind: TemplateParamLabelValue, Description: "Namespace of the pods"},
+		},
+	},
+	"container_restarts": {
+		Name:        "container_restarts",
+		Description: "Container restarts per pod and container in a namespace over a time window",
+		Query:       `sum by (pod, container) (increase(kube_pod_container_status_restarts_total{namespace="${namespace}"}[${window}]))`,
+		Params: []QueryTemplateParam{
+			{Name: "namespace", Kind: TemplateParamLabelValue, Description: "Namespace of the pods"},
+			{Name: "window", Kind: TemplateParamDuration, Description: "Time window to count restarts over", Default: "1h"},
+		},
+	},
+	"node_disk_usage": {
+		Name:        "node_disk_usage",
+		Description: "Used filesystem ratio (0-1) per mountpoint of a node",
+		Query:       `1 - (node_filesystem_avail_bytes{instance="${instance}", fstype!~"tmpfs|overlay"} / node_filesystem_size_bytes{instance="${instance}", fstype!~"tmpfs|overlay"})`,
+		Params: []QueryTemplateParam{
+			{Name: "instance", Kind: TemplateParamLabelValue, Description: "Instance label of the node exporter target"},
+		},
+	},
+	"http_error_rate": {
+		Name:        "http_error_rate",
+		Description: "Ratio (0-1) of HTTP requests answered with a 5xx status code for a job",
+		Query:       `sum(rate(http_requests_total{job="${job}", code=~"5.."}[${window}])) / sum(rate(http_requests_total{job="${job}"}[${window}]))`,
+		Params: []QueryTemplateParam{
+			{Name: "job", Kind: TemplateParamLabelValue, Description: "Job label of the service"},
+			{Name: "window", Kind: TemplateParamDuration, Description: "Rate window", Default: "5m"},
+		},
+	},
+}
+
+// SortedQueryTemplates returns all query templates ordered by name.
+func SortedQueryTemplates() []QueryTemplate {
+	names := make([]string, 0, len(QueryTemplates))
+	for name := range QueryTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	templates := make([]QueryTemplate, len(names))
+	for i, name := range names {
+		templates[i] = QueryTemplates[name]
+	}
+	return templates
+}
+
+// ExpandQueryTemplate fills the placeholders of the named template with the given values.
+// Missing values fall back to the parameter default; unknown parameters are rejected.
+func ExpandQueryTemplate(name string, values map[string]string) (string, error) {
+	tmpl, ok := QueryTemplates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown query template %q", name)
+	}
+
+	for key := range values {
+		if !slices.ContainsFunc(tmpl.Params, func(p QueryTemplateParam) bool { return p.Name == key }) {
+			return "", fmt.Errorf("unknown parameter %q for query template %q", key, name)
+		}
+	}
+
+	replacements := make(map[string]string, len(tmpl.Params))
+	for _, p := range tmpl.Params {
+		value, ok := values[p.Name]
+		if !ok || value == "" {
+			value = p.Default
+		}
+		if value == "" {
+			return "", fmt.Errorf("parameter %q is required for query template %q", p.Name, name)
+		}
+
+		switch p.Kind {
+		case TemplateParamDuration:
+			if _, err := model.ParseDuration(value); err != nil {
+				return "", fmt.Errorf("invalid duration for parameter %q: %w", p.Name, err)
+			}
+		case TemplateParamLabelValue:
+			value = escapeLabelValue(value)
+		}
+		replacements[p.Name] = value
+	}
+
+	return templatePlaceholderRe.ReplaceAllStringFunc(tmpl.Query, func(match string) string {
+		return replacements[templatePlaceholderRe.FindStringSubmatch(match)[1]]
+	}), nil
+}
+
+// escapeLabelValue escapes a value for use inside a double-quoted PromQL string.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+func TestQueryTemplatesAreValid(t *testing.T) {
+	for name, tmpl := range QueryTemplates {
+		t.Run(name, func(t *testing.T) {
+			if tmpl.Name != name {
+				t.Errorf("template key %q does not match template name %q", name, tmpl.Name)
+			}
+
+			values := make(map[string]string)
+			for _, p := range tmpl.Params {
+				if p.Default == "" {
+					values[p.Name] = "example"
+				}
+			}
+
+			query, err := ExpandQueryTemplate(name, values)
+			if err != nil {
+				t.Fatalf("failed to render template: %v", err)
+			}
+			if strings.Contains(query, "${") {
+				t.Errorf("rendered query still contains placeholders: %s", query)
+			}
+			if _, err := parser.NewParser(parser.Options{}).ParseExpr(query); err != nil {
+				t.Errorf("rendered query is not valid PromQL: %v\n%s", err, query)
+			}
+		})
+	}
+}
+
+func TestExpandQueryTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		values    map[string]string
+		wantQuery string
+		wantErr   string
+	}{
+		{
+			name:      "defaults are applied",
+			template:  "pod_cpu_usage",
+			values:    map[string]string{"namespace": "default"},
+			wantQuery: `sum by (pod) (rate(container_cpu_usage_seconds_total{namespace="default", container!=""}[5m]))`,
+		},
+		{
+			name:      "explicit values override defaults",
+			template:  "container_restarts",
+			values:    map[string]string{"namespace": "monitoring", "window": "30m"},
+			wantQuery: `sum by (pod, container) (increase(kube_pod_container_status_restarts_total{namespace="monitoring"}[30m]))`,
+		},
+		{
+			name:      "label values are escaped",
+			template:  "pod_memory_usage",
+			values:    map[string]string{"namespace": `a"} or vector(1) #`},
+			wantQuery: `sum by (pod) (container_memory_working_set_bytes{namespace="a\"} or vector(1) #", container!=""})`,
+		},
+		{
+			name:     "unknown template",
+			template: "does_not_exist",
+			wantErr:  "unknown query template",
+		},
+		{
+			name:     "missing required parameter",
+			template: "pod_memory_usage",
+			values:   map[string]string{},
+			wantErr:  `parameter "namespace" is required`,
+		},
+		{
+			name:     "unknown parameter",
+			template: "pod_memory_usage",
+			values:   map[string]string{"namespace": "default", "pod": "x"},
+			wantErr:  `unknown parameter "pod"`,
+		},
+		{
+			name:     "invalid duration",
+			template: "http_error_rate",
+			values:   map[string]string{"job": "api", "window": "5 minutes"},
+			wantErr:  `invalid duration for parameter "window"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ExpandQueryTemplate(tt.template, tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %s, want %s", query, tt.wantQuery)
+			}
+		})
+	}
+}
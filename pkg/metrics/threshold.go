@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// ruleThreshold is the comparison of an alerting rule expression against a constant,
+// normalized so that the value is on the left: "<value> <op> <threshold>".
+type ruleThreshold struct {
+	value     string
+	op        string
+	threshold float64
+}
+
+// invertedComparisons maps comparison operators to the operator obtained by swapping
+// their operands.
+var invertedComparisons = map[parser.ItemType]parser.ItemType{
+	parser.GTR:  parser.LSS,
+	parser.GTE:  parser.LTE,
+	parser.LSS:  parser.GTR,
+	parser.LTE:  parser.GTE,
+	parser.EQLC: parser.EQLC,
+	parser.NEQ:  parser.NEQ,
+}
+
+// extractThreshold finds the comparison against a number literal an alerting rule fires
+// on, e.g. 80 in "memory_usage_percent > 80" or "80 < memory_usage_percent". Conditions
+// joined with and/unless only restrict the series of their left-hand side, which is
+// followed. It reports false for expressions without such a comparison, e.g. when both
+// sides of the comparison are queries or conditions are joined with or.
+func extractThreshold(expr string) (ruleThreshold, bool) {
+	node, err := parser.NewParser(parser.Options{}).ParseExpr(expr)
+	if err != nil {
+		return ruleThreshold{}, false
+	}
+
+	for {
+		switch n := node.(type) {
+		case *parser.ParenExpr:
+			node = n.Expr
+			continue
+		case *parser.BinaryExpr:
+			if n.Op == parser.LAND || n.Op == parser.LUNLESS {
+				node = n.LHS
+				continue
+			}
+			if !n.Op.IsComparisonOperator() || n.ReturnBool {
+				return ruleThreshold{}, false
+			}
+			if threshold, ok := numberLiteral(n.RHS); ok {
+				return ruleThreshold{value: n.LHS.String(), op: n.Op.String(), threshold: threshold}, true
+			}
+			if threshold, ok := numberLiteral(n.LHS); ok {
+				return ruleThreshold{value: n.RHS.String(), op: invertedComparisons[n.Op].String(), threshold: threshold}, true
+			}
+		}
+		return ruleThreshold{}, false
+	}
+}
+
+// numberLiteral returns the value of an expression consisting of a number, optionally
+// parenthesized or negated.
+func numberLiteral(expr parser.Expr) (float64, bool) {
+	switch e := expr.(type) {
+	case *parser.NumberLiteral:
+		return e.Val, true
+	case *parser.ParenExpr:
+		return numberLiteral(e.Expr)
+	case *parser.UnaryExpr:
+		v, ok := numberLiteral(e.Expr)
+		if e.Op == parser.SUB {
+			v = -v
+		}
+		return v, ok
+	}
+	return 0, false
+}
+
+// compare evaluates "value <op> threshold".
+func (t ruleThreshold) compare(value float64) bool {
+	switch t.op {
+	case ">":
+		return value > t.threshold
+	case ">=":
+		return value >= t.threshold
+	case "<":
+		return value < t.threshold
+	case "<=":
+		return value <= t.threshold
+	case "==":
+		return value == t.threshold
+	case "!=":
+		return value != t.threshold
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import "testing"
+
+func TestExtractThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		want      ruleThreshold
+		wantFound bool
+	}{
+		{
+			name:      "value above threshold",
+			expr:      `node_memory_usage_percent > 80`,
+			want:      ruleThreshold{value: "node_memory_usage_percent", op: ">", threshold: 80},
+			wantFound: true,
+		},
+		{
+			name:      "threshold on the left",
+			expr:      `80 < node_memory_usage_percent`,
+			want:      ruleThreshold{value: "node_memory_usage_percent", op: ">", threshold: 80},
+			wantFound: true,
+		},
+		{
+			name:      "parenthesized ratio",
+			expr:      `(sum by (job) (rate(errors_total[5m])) / sum by (job) (rate(requests_total[5m]))) >= 0.05`,
+			want:      ruleThreshold{value: `(sum by (job) (rate(errors_total[5m])) / sum by (job) (rate(requests_total[5m])))`, op: ">=", threshold: 0.05},
+			wantFound: true,
+		},
+		{
+			name:      "negative threshold",
+			expr:      `temperature_celsius <= -(10)`,
+			want:      ruleThreshold{value: "temperature_celsius", op: "<=", threshold: -10},
+			wantFound: true,
+		},
+		{
+			name:      "equality",
+			expr:      `up{job="api"} == 0`,
+			want:      ruleThreshold{value: `up{job="api"}`, op: "==", threshold: 0},
+			wantFound: true,
+		},
+		{
+			name:      "condition joined with and",
+			expr:      `kube_pod_container_status_restarts_total > 3 and on (pod) kube_pod_status_ready == 0`,
+			want:      ruleThreshold{value: "kube_pod_container_status_restarts_total", op: ">", threshold: 3},
+			wantFound: true,
+		},
+		{
+			name:      "condition joined with unless",
+			expr:      `(disk_free_bytes < 1e9) unless on (instance) maintenance_mode`,
+			want:      ruleThreshold{value: "disk_free_bytes", op: "<", threshold: 1e9},
+			wantFound: true,
+		},
+		{name: "two queries compared", expr: `used_bytes > capacity_bytes`},
+		{name: "bool comparison", expr: `used_bytes > bool 5`},
+		{name: "conditions joined with or", expr: `a > 1 or b > 2`},
+		{name: "no comparison", expr: `absent(up{job="api"})`},
+		{name: "invalid expression", expr: `up >`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := extractThreshold(tt.expr)
+			if found != tt.wantFound {
+				t.Fatalf("extractThreshold(%q) found = %v, want %v", tt.expr, found, tt.wantFound)
+			}
+			if got != tt.want {
+				t.Errorf("extractThreshold(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleThresholdCompare(t *testing.T) {
+	threshold := ruleThreshold{op: ">=", threshold: 80}
+	if !threshold.compare(80) || !threshold.compare(95) || threshold.compare(79.9) {
+		t.Errorf("compare() does not match value >= 80")
+	}
+	threshold.op = "<"
+	if threshold.compare(80) || !threshold.compare(10) {
+		t.Errorf("compare() does not match value < 80")
+	}
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
+)
+
+var (
+	relativeAgoRe  = regexp.MustCompile(`^(?i)(\d+)\s*([a-z]+)\s+ago$`)
+	relativeLastRe = regexp.MustCompile(`^(?i)(?:last|past)\s+(\d+)?\s*([a-z]+)$`)
+	fractionUnixRe = regexp.MustCompile(`^(\d+)\.\d+$`)
+)
+
+// durationUnits maps spelled-out time units to Prometheus duration units.
+var durationUnits = map[string]string{
+	"s": "s", "sec": "s", "secs": "s", "second": "s", "seconds": "s",
+	"m": "m", "min": "m", "mins": "m", "minute": "m", "minutes": "m",
+	"h": "h", "hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	"d": "d", "day": "d", "days": "d",
+	"w": "w", "week": "w", "weeks": "w",
+	"y": "y", "year": "y", "years": "y",
+}
+
+// localTimeLayouts are layouts of timestamps written without a time zone.
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// unixMillisThreshold is the smallest Unix timestamp in seconds that is more likely
+// a timestamp in milliseconds, as it lies beyond the year 33658.
+const unixMillisThreshold = 1e12
+
+// timestampSuggestions returns rewrites of a timestamp that ParseTimestamp rejects into
+// forms it accepts, for common ways of writing times that are not supported.
+func timestampSuggestions(value string) []string {
+	value = strings.TrimSpace(value)
+	var candidates []string
+
+	// "now - 1h", "NOW -1h"
+	candidates = append(candidates, strings.Join(strings.Fields(value), ""))
+
+	// "1h ago", "30 minutes ago", "last 2 hours", "past day"
+	if m := relativeAgoRe.FindStringSubmatch(value); m != nil {
+		if unit, ok := durationUnits[strings.ToLower(m[2])]; ok {
+			candidates = append(candidates, "NOW-"+m[1]+unit)
+		}
+	}
+	if m := relativeLastRe.FindStringSubmatch(value); m != nil {
+		if unit, ok := durationUnits[strings.ToLower(m[2])]; ok {
+			candidates = append(candidates, "NOW-"+cmp.Or(m[1], "1")+unit)
+		}
+	}
+
+	// "-1h", "+30m"
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		candidates = append(candidates, "NOW"+value)
+	}
+
+	switch strings.ToLower(value) {
+	case "today", "current", "current time":
+		candidates = append(candidates, "NOW")
+	case "yesterday":
+		candidates = append(candidates, "NOW-1d")
+	}
+
+	// "2024-01-02T15:04:05", "2024-01-02"
+	for _, layout := range localTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			candidates = append(candidates, t.Format(time.RFC3339))
+			break
+		}
+	}
+
+	// "1700000000.123"
+	if m := fractionUnixRe.FindStringSubmatch(value); m != nil {
+		candidates = append(candidates, m[1])
+	}
+
+	var suggestions []string
+	for _, candidate := range candidates {
+		if candidate == value || candidate == "" {
+			continue
+		}
+		if _, err := prometheus.ParseTimestamp(context.Background(), candidate); err == nil && !slices.Contains(suggestions, candidate) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// timestampWarning returns a warning for a successfully parsed timestamp that is
+// probably not what was meant, or "" when there is none.
+func timestampWarning(value string, t, now time.Time) string {
+	if t.Unix() >= unixMillisThreshold {
+		return "the value was read as a Unix timestamp in seconds and lies far in the future; " +
+			"Unix timestamps in milliseconds must be divided by 1000"
+	}
+	if !strings.EqualFold(strings.TrimSpace(value), "NOW") && t.After(now.Add(time.Minute)) {
+		return "the timestamp lies in the future, where no data exists yet"
+	}
+	return ""
+}
+
+// describeOffset describes t relative to now, e.g. "1h0m0s ago".
+func describeOffset(t, now time.Time) string {
+	offset := now.Sub(t).Round(time.Second)
+	switch {
+	case offset == 0:
+		return "now"
+	case offset > 0:
+		return offset.String() + " ago"
+	default:
+		return (-offset).String() + " from now"
+	}
+}
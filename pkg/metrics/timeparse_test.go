@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTimestampSuggestions(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "now - 5m", want: []string{"now-5m"}},
+		{value: "1h ago", want: []string{"NOW-1h"}},
+		{value: "30 minutes ago", want: []string{"NOW-30m"}},
+		{value: "last 2 days", want: []string{"NOW-2d"}},
+		{value: "past hour", want: []string{"NOW-1h"}},
+		{value: "-15m", want: []string{"NOW-15m"}},
+		{value: "yesterday", want: []string{"NOW-1d"}},
+		{value: "2024-01-02T15:04:05", want: []string{"2024-01-02T15:04:05Z"}},
+		{value: "2024-01-02", want: []string{"2024-01-02T00:00:00Z"}},
+		{value: "1700000000.5", want: []string{"1700000000"}},
+		{value: "3 fortnights ago"},
+		{value: "not a time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := timestampSuggestions(tt.value); !slices.Equal(got, tt.want) {
+				t.Errorf("timestampSuggestions(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampWarning(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	if got := timestampWarning("1700000000000", time.Unix(1700000000000, 0), now); got == "" {
+		t.Error("expected a warning for a timestamp in milliseconds")
+	}
+	if got := timestampWarning("NOW+1h", now.Add(time.Hour), now); got == "" {
+		t.Error("expected a warning for a future timestamp")
+	}
+	if got := timestampWarning("NOW-1h", now.Add(-time.Hour), now); got != "" {
+		t.Errorf("unexpected warning for a past timestamp: %q", got)
+	}
+}
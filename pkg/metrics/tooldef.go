@@ -22,6 +22,8 @@ type ParamDef struct {
 	Description string
 	Required    bool
 	Pattern     string
+	// AllowNumber lets a string parameter also be passed as a JSON number.
+	AllowNumber bool
 }
 
 // ParamType represents the type of a parameter
@@ -31,6 +33,9 @@ const (
 	ParamTypeString  ParamType = "string"
 	ParamTypeBoolean ParamType = "boolean"
 	ParamTypeNumber  ParamType = "number"
+	ParamTypeObject  ParamType = "object"
+	// ParamTypeArray is an array of strings.
+	ParamTypeArray ParamType = "array"
 )
 
 // ToolDef defines a tool that can be converted to different formats (MCP, Toolset, etc.)
@@ -61,6 +66,9 @@ func (d ToolDef[T]) ToMCPTool() *mcp.Tool {
 		switch param.Type {
 		case ParamTypeString:
 			property["type"] = "string"
+			if param.AllowNumber {
+				property["type"] = []any{"string", "number"}
+			}
 			if param.Pattern != "" {
 				property["pattern"] = param.Pattern
 			}
@@ -68,6 +76,11 @@ func (d ToolDef[T]) ToMCPTool() *mcp.Tool {
 			property["type"] = "boolean"
 		case ParamTypeNumber:
 			property["type"] = "number"
+		case ParamTypeObject:
+			property["type"] = "object"
+		case ParamTypeArray:
+			property["type"] = "array"
+			property["items"] = map[string]any{"type": "string"}
 		}
 
 		properties[param.Name] = property
@@ -103,6 +116,14 @@ func (d ToolDef[T]) ToMCPTool() *mcp.Tool {
 		Description:  d.Description,
 		InputSchema:  inputSchema,
 		OutputSchema: outputSchema,
+		// Clients assume unannotated tools are destructive, so the hints are always set.
+		Annotations: &mcp.ToolAnnotations{
+			Title:           d.Title,
+			ReadOnlyHint:    d.ReadOnly,
+			DestructiveHint: new(d.Destructive),
+			IdempotentHint:  d.Idempotent,
+			OpenWorldHint:   new(d.OpenWorld),
+		},
 	}
 
 	if d.Title != "" {
@@ -129,6 +150,10 @@ func (d ToolDef[T]) ToServerTool(handler func(api.ToolHandlerParams) (*api.ToolC
 		switch param.Type {
 		case ParamTypeString:
 			schema.Type = "string"
+			if param.AllowNumber {
+				schema.Type = ""
+				schema.Types = []string{"string", "number"}
+			}
 			if param.Pattern != "" {
 				schema.Pattern = param.Pattern
 			}
@@ -136,6 +161,11 @@ func (d ToolDef[T]) ToServerTool(handler func(api.ToolHandlerParams) (*api.ToolC
 			schema.Type = "boolean"
 		case ParamTypeNumber:
 			schema.Type = "number"
+		case ParamTypeObject:
+			schema.Type = "object"
+		case ParamTypeArray:
+			schema.Type = "array"
+			schema.Items = &jsonschema.Schema{Type: "string"}
 		}
 
 		properties[param.Name] = schema
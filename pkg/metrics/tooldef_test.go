@@ -92,3 +92,20 @@ func TestToMCPTool_AdditionalFieldsFlatInMeta(t *testing.T) {
 		t.Error("Meta should not contain an 'AdditionalFields' wrapper key")
 	}
 }
+
+func TestToolDef_ArrayParam(t *testing.T) {
+	def := ToolDef[testOutput]{
+		Name:   "test_tool",
+		Params: []ParamDef{{Name: "groups", Type: ParamTypeArray}},
+	}
+
+	property := def.ToMCPTool().InputSchema.(map[string]any)["properties"].(map[string]any)["groups"].(map[string]any)
+	if property["type"] != "array" || property["items"].(map[string]any)["type"] != "string" {
+		t.Errorf("MCP schema = %v, want an array of strings", property)
+	}
+
+	schema := def.ToServerTool(nil).Tool.InputSchema.Properties["groups"]
+	if schema.Type != "array" || schema.Items == nil || schema.Items.Type != "string" {
+		t.Errorf("toolset schema = %+v, want an array of strings", schema)
+	}
+}
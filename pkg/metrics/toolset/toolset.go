@@ -28,22 +28,45 @@ func (t *Toolset) GetDescription() string {
 func (t *Toolset) GetTools(_ api.FilteringProvider) []api.ServerTool {
 	return slices.Concat(
 		toolset_tools.InitListMetrics(),
+		toolset_tools.InitListMetricGroups(),
 		toolset_tools.InitExecuteInstantQuery(),
 		toolset_tools.InitExecuteRangeQuery(),
 		toolset_tools.InitShowTimeseries(),
+		toolset_tools.InitQueryHeatmap(),
+		toolset_tools.InitLabelCardinalityTrend(),
+		toolset_tools.InitSLOCompliance(),
+		toolset_tools.InitEvaluateCondition(),
+		toolset_tools.InitClusterStatus(),
 		toolset_tools.InitGetLabelNames(),
 		toolset_tools.InitGetLabelValues(),
+		toolset_tools.InitGetLabelsOverview(),
 		toolset_tools.InitGetSeries(),
+		toolset_tools.InitCheckSeriesUniqueness(),
+		toolset_tools.InitInspectMetric(),
+		toolset_tools.InitGetMetricMetadata(),
+		toolset_tools.InitGetScrapeInterval(),
+		toolset_tools.InitGetExternalLabels(),
+		toolset_tools.InitGetActiveQueries(),
+		toolset_tools.InitListRecordingRules(),
+		toolset_tools.InitGetRuleGroup(),
+		toolset_tools.InitListQueryTemplates(),
+		toolset_tools.InitRenderQueryTemplate(),
+		toolset_tools.InitQueryToPanel(),
+		toolset_tools.InitParseTime(),
+		toolset_tools.InitSaveQueryResult(),
 		toolset_tools.InitGetAlerts(),
+		toolset_tools.InitSummarizeAlerts(),
+		toolset_tools.InitGetAlertHistory(),
+		toolset_tools.InitGetAlertThreshold(),
 		toolset_tools.InitGetSilences(),
+		toolset_tools.InitDeleteSilence(),
+		toolset_tools.InitGetAlertmanagerStatus(),
 	)
 }
 
 // GetPrompts returns prompts provided by this toolset.
 func (t *Toolset) GetPrompts() []api.ServerPrompt {
-	// Currently, prompts are not supported through this toolset
-	// The workflow instructions are embedded in the tool descriptions
-	return nil
+	return toolset_tools.InitWorkflowPrompts()
 }
 
 // GetResources returns resources provided by this toolset.
@@ -1,6 +1,7 @@
 package toolset_tools
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
@@ -15,7 +16,23 @@ func ListMetricsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, erro
 		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
 	}
 
-	return tools.ListMetricsHandler(params.Context, promClient, tools.BuildListMetricsInput(params.GetArguments())).ToToolsetResult()
+	cfg := getConfig(params)
+	return tools.ListMetricsHandler(params.Context, promClient, tools.BuildListMetricsInput(params.GetArguments()), cfg.GetLimit(tools.LimitListMetrics)).ToToolsetResult()
+}
+
+// ListMetricGroupsHandler handles grouping the available metrics by name prefix.
+func ListMetricGroupsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	metrics, err := promClient.ListMetrics(params.Context, ".*")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list metrics: %w", err)), nil
+	}
+
+	return tools.ListMetricGroupsHandler(params.Context, metrics, tools.BuildMetricGroupsInput(params.GetArguments())).ToToolsetResult()
 }
 
 // ExecuteInstantQueryHandler handles the execution of Prometheus instant queries.
@@ -25,7 +42,8 @@ func ExecuteInstantQueryHandler(params api.ToolHandlerParams) (*api.ToolCallResu
 		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
 	}
 
-	return tools.ExecuteInstantQueryHandler(params.Context, promClient, tools.BuildInstantQueryInput(params.GetArguments())).ToToolsetResult()
+	cfg := getConfig(params)
+	return tools.ExecuteInstantQueryHandler(params.Context, promClient, tools.BuildInstantQueryInput(params.GetArguments()), cfg.GetMaxResultSeries()).ToToolsetResult()
 }
 
 // ExecuteRangeQueryHandler handles the execution of Prometheus range queries.
@@ -35,8 +53,7 @@ func ExecuteRangeQueryHandler(params api.ToolHandlerParams) (*api.ToolCallResult
 		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
 	}
 
-	cfg := getConfig(params)
-	return tools.ExecuteRangeQueryHandler(params.Context, promClient, tools.BuildRangeQueryInput(params.GetArguments()), cfg.RangeQueryFullResponse).ToToolsetResult()
+	return tools.ExecuteRangeQueryHandler(params.Context, promClient, tools.BuildRangeQueryInput(params.GetArguments()), getConfig(params).GetRangeQueryOptions()).ToToolsetResult()
 }
 
 // ShowTimeseriesHandler handles the show_timeseries tool.
@@ -49,6 +66,61 @@ func ShowTimeseriesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, e
 	return tools.ShowTimeseriesHandler(params.Context, promClient, tools.BuildShowTimeseriesInput(params.GetArguments())).ToToolsetResult()
 }
 
+// QueryHeatmapHandler handles the query_heatmap tool.
+func QueryHeatmapHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.QueryHeatmapHandler(params.Context, promClient, tools.BuildHeatmapInput(params.GetArguments()), cfg.GetOversizedStepPolicy()).ToToolsetResult()
+}
+
+// LabelCardinalityTrendHandler handles the label_cardinality_trend tool.
+func LabelCardinalityTrendHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.LabelCardinalityTrendHandler(params.Context, promClient, tools.BuildLabelCardinalityTrendInput(params.GetArguments()), cfg.GetOversizedStepPolicy()).ToToolsetResult()
+}
+
+// SLOComplianceHandler handles the slo_compliance tool.
+func SLOComplianceHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.SLOComplianceHandler(params.Context, promClient, tools.BuildSLOComplianceInput(params.GetArguments()), cfg.GetOversizedStepPolicy()).ToToolsetResult()
+}
+
+// EvaluateConditionHandler handles the evaluate_condition tool.
+func EvaluateConditionHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.EvaluateConditionHandler(params.Context, promClient, tools.BuildEvaluateConditionInput(params.GetArguments()), cfg.GetOversizedStepPolicy()).ToToolsetResult()
+}
+
+// ClusterStatusHandler handles the cluster_status tool.
+func ClusterStatusHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.ClusterStatusHandler(params.Context, promClient, tools.BuildClusterStatusInput(params.GetArguments()), cfg.GetStatusChecks()).ToToolsetResult()
+}
+
 // GetLabelNamesHandler handles the retrieval of label names.
 func GetLabelNamesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	promClient, err := getPromClient(params)
@@ -66,7 +138,19 @@ func GetLabelValuesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, e
 		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
 	}
 
-	return tools.GetLabelValuesHandler(params.Context, promClient, tools.BuildLabelValuesInput(params.GetArguments())).ToToolsetResult()
+	cfg := getConfig(params)
+	return tools.GetLabelValuesHandler(params.Context, promClient, tools.BuildLabelValuesInput(params.GetArguments()), cfg.GetLimit(tools.LimitLabelValues)).ToToolsetResult()
+}
+
+// GetLabelsOverviewHandler handles the get_labels_overview tool.
+func GetLabelsOverviewHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.GetLabelsOverviewHandler(params.Context, promClient, tools.BuildLabelsOverviewInput(params.GetArguments()), cfg.GetLimit(tools.LimitLabelsOverview)).ToToolsetResult()
 }
 
 // GetSeriesHandler handles the retrieval of time series.
@@ -76,25 +160,199 @@ func GetSeriesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error)
 		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
 	}
 
-	return tools.GetSeriesHandler(params.Context, promClient, tools.BuildSeriesInput(params.GetArguments())).ToToolsetResult()
+	cfg := getConfig(params)
+	return tools.GetSeriesHandler(params.Context, promClient, tools.BuildSeriesInput(params.GetArguments()), cfg.GetLimit(tools.LimitSeries)).ToToolsetResult()
+}
+
+// CheckSeriesUniquenessHandler handles the check_series_uniqueness tool.
+func CheckSeriesUniquenessHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.CheckSeriesUniquenessHandler(params.Context, promClient, tools.BuildSeriesUniquenessInput(params.GetArguments())).ToToolsetResult()
+}
+
+// InspectMetricHandler handles the inspect_metric tool.
+func InspectMetricHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.InspectMetricHandler(params.Context, promClient, tools.BuildInspectMetricInput(params.GetArguments())).ToToolsetResult()
+}
+
+// GetMetricMetadataHandler handles the get_metric_metadata tool.
+func GetMetricMetadataHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.GetMetricMetadataHandler(params.Context, promClient, tools.BuildMetricMetadataInput(params.GetArguments()), cfg.GetLimit(tools.LimitMetricMetadata)).ToToolsetResult()
+}
+
+// GetScrapeIntervalHandler handles the get_scrape_interval tool.
+func GetScrapeIntervalHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.GetScrapeIntervalHandler(params.Context, promClient, tools.BuildScrapeIntervalInput(params.GetArguments())).ToToolsetResult()
+}
+
+// GetExternalLabelsHandler handles the get_external_labels tool.
+func GetExternalLabelsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.GetExternalLabelsHandler(params.Context, promClient, tools.BuildExternalLabelsInput(params.GetArguments())).ToToolsetResult()
+}
+
+// GetActiveQueriesHandler handles the get_active_queries tool.
+func GetActiveQueriesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.GetActiveQueriesHandler(params.Context, promClient, tools.BuildActiveQueriesInput(params.GetArguments())).ToToolsetResult()
+}
+
+// GetRuleGroupHandler handles the get_rule_group tool.
+func GetRuleGroupHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.GetRuleGroupHandler(params.Context, promClient, tools.BuildRuleGroupInput(params.GetArguments())).ToToolsetResult()
+}
+
+// ListRecordingRulesHandler handles the listing of recording rules.
+func ListRecordingRulesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.ListRecordingRulesHandler(params.Context, promClient, tools.BuildRecordingRulesInput(params.GetArguments())).ToToolsetResult()
+}
+
+// ListQueryTemplatesHandler handles the listing of query templates.
+func ListQueryTemplatesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	return tools.ListQueryTemplatesHandler(params.Context, tools.BuildQueryTemplatesInput(params.GetArguments())).ToToolsetResult()
+}
+
+// RenderQueryTemplateHandler handles rendering of query templates.
+func RenderQueryTemplateHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	return tools.RenderQueryTemplateHandler(params.Context, tools.BuildRenderQueryTemplateInput(params.GetArguments())).ToToolsetResult()
+}
+
+// QueryToPanelHandler handles turning queries into Perses panels.
+func QueryToPanelHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	return tools.QueryToPanelHandler(params.Context, tools.BuildQueryToPanelInput(params.GetArguments())).ToToolsetResult()
+}
+
+// ParseTimeHandler handles resolving time expressions.
+func ParseTimeHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	return tools.ParseTimeHandler(params.Context, tools.BuildParseTimeInput(params.GetArguments())).ToToolsetResult()
+}
+
+// SaveQueryResultHandler handles the save_query_result tool.
+func SaveQueryResultHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	outputDir := getConfig(params).GetFileOutputDir()
+	return tools.SaveQueryResultHandler(params.Context, promClient, tools.BuildSaveQueryResultInput(params.GetArguments()), outputDir).ToToolsetResult()
+}
+
+// alertmanagerClientError returns the result of a tool that could not create its
+// Alertmanager client.
+func alertmanagerClientError(err error) *api.ToolCallResult {
+	if errors.Is(err, errAlertsNotConfigured) {
+		return api.NewToolCallResult("", err)
+	}
+	return api.NewToolCallResult("", fmt.Errorf("failed to create Alertmanager client: %w", err))
 }
 
 // GetAlertsHandler handles the retrieval of alerts from Alertmanager.
 func GetAlertsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	amClient, err := getAlertmanagerClient(params)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to create Alertmanager client: %w", err)), nil
+		return alertmanagerClientError(err), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.GetAlertsHandler(params.Context, amClient, tools.BuildAlertsInput(params.GetArguments()), cfg.GetAlertProjection(), nil).ToToolsetResult()
+}
+
+// SummarizeAlertsHandler handles the summarize_alerts tool.
+func SummarizeAlertsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	amClient, err := getAlertmanagerClient(params)
+	if err != nil {
+		return alertmanagerClientError(err), nil
 	}
 
-	return tools.GetAlertsHandler(params.Context, amClient, tools.BuildAlertsInput(params.GetArguments())).ToToolsetResult()
+	return tools.SummarizeAlertsHandler(params.Context, amClient, tools.BuildSummarizeAlertsInput(params.GetArguments())).ToToolsetResult()
+}
+
+// GetAlertHistoryHandler handles the get_alert_history tool.
+func GetAlertHistoryHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.GetAlertHistoryHandler(params.Context, promClient, tools.BuildAlertHistoryInput(params.GetArguments())).ToToolsetResult()
+}
+
+// GetAlertThresholdHandler handles the get_alert_threshold tool.
+func GetAlertThresholdHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	promClient, err := getPromClient(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create Prometheus client: %w", err)), nil
+	}
+
+	return tools.GetAlertThresholdHandler(params.Context, promClient, tools.BuildAlertThresholdInput(params.GetArguments())).ToToolsetResult()
 }
 
 // GetSilencesHandler handles the retrieval of silences from Alertmanager.
 func GetSilencesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	amClient, err := getAlertmanagerClient(params)
 	if err != nil {
-		return api.NewToolCallResult("", fmt.Errorf("failed to create Alertmanager client: %w", err)), nil
+		return alertmanagerClientError(err), nil
 	}
 
 	return tools.GetSilencesHandler(params.Context, amClient, tools.BuildSilencesInput(params.GetArguments())).ToToolsetResult()
 }
+
+// DeleteSilenceHandler handles the delete_silence tool.
+func DeleteSilenceHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	amClient, err := getAlertmanagerClient(params)
+	if err != nil {
+		return alertmanagerClientError(err), nil
+	}
+
+	cfg := getConfig(params)
+	return tools.DeleteSilenceHandler(params.Context, amClient, tools.BuildDeleteSilenceInput(params.GetArguments()), cfg.AllowWrites).ToToolsetResult()
+}
+
+// GetAlertmanagerStatusHandler handles the get_alertmanager_status tool.
+func GetAlertmanagerStatusHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	amClient, err := getAlertmanagerClient(params)
+	if err != nil {
+		return alertmanagerClientError(err), nil
+	}
+
+	return tools.GetAlertmanagerStatusHandler(params.Context, amClient, tools.BuildAlertmanagerStatusInput(params.GetArguments())).ToToolsetResult()
+}
@@ -1,14 +1,17 @@
 package toolset_tools
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	promapi "github.com/prometheus/client_golang/api"
 
 	"github.com/rhobs/obs-mcp/pkg/auth"
+	"github.com/rhobs/obs-mcp/pkg/instrumentation"
 	"github.com/rhobs/obs-mcp/pkg/metrics"
 	"github.com/rhobs/obs-mcp/pkg/metrics/alertmanager"
 	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
@@ -18,6 +21,13 @@ const (
 	defaultPrometheusURL = "http://localhost:9090"
 )
 
+// errAlertsNotConfigured is returned by the tools calling Alertmanager when no
+// alertmanager_url is configured. Tools are listed before the configuration is known,
+// so they stay registered and explain how to enable them instead.
+var errAlertsNotConfigured = errors.New("alerts are not configured for this server: set alertmanager_url in the " +
+	metrics.ToolsetName + " toolset configuration to enable the Alertmanager tools; " +
+	"get_alert_history and get_alert_threshold work without it, as they read the ALERTS metric from Prometheus")
+
 // getConfig retrieves the obs-mcp toolset configuration from params.
 func getConfig(params api.ToolHandlerParams) *metrics.Config {
 	if cfg, ok := params.GetToolsetConfig(metrics.ToolsetName); ok {
@@ -41,12 +51,12 @@ func getPromClient(params api.ToolHandlerParams) (prometheus.Loader, error) {
 	}
 
 	// Get guardrails configuration
-	guardrails, err := cfg.GetGuardrails()
+	guardrails, err := cfg.GetRequestGuardrails(params.Context)
 	if err != nil {
-		slog.Warn("Failed to parse guardrails configuration", "err", err)
+		return nil, fmt.Errorf("failed to parse guardrails: %w", err)
 	}
 
-	apiConfig, err := buildAPIConfig(params, metricsBackendURL, cfg.Insecure, cfg.GetAuthMode())
+	apiConfig, err := buildAPIConfig(params, metricsBackendURL, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API config: %w", err)
 	}
@@ -57,21 +67,40 @@ func getPromClient(params api.ToolHandlerParams) (prometheus.Loader, error) {
 	}
 
 	promClient.WithGuardrails(guardrails)
+	promClient.WithQueryLogging(cfg.LogQueries)
+	promClient.WithRangeSplitting(cfg.GetMaxSplitPoints())
+	promClient.WithMetricNameTransform(cfg.GetMetricNameTransform())
+	promClient.WithSharedScope(auth.CredentialScope(params.Context, cfg.GetAuthMode()))
 
 	return promClient, nil
 }
 
-// buildAPIConfig creates a Prometheus API config using the configured auth mode.
-func buildAPIConfig(params api.ToolHandlerParams, prometheusURL string, insecure bool, authMode auth.AuthMode) (promapi.Config, error) {
+// buildAPIConfig creates a Prometheus API config using the configured auth mode and connection pool settings.
+func buildAPIConfig(params api.ToolHandlerParams, prometheusURL string, cfg *metrics.Config) (promapi.Config, error) {
+	transport, err := cfg.GetTransportConfig()
+	if err != nil {
+		return promapi.Config{}, err
+	}
+
+	tokens, err := cfg.GetTokenFile()
+	if err != nil {
+		return promapi.Config{}, err
+	}
+
 	tls := strings.HasPrefix(prometheusURL, "https://")
-	rt, err := auth.BuildRoundTripper(params.Context, params.RESTConfig(), authMode, tls, insecure)
+	var rt http.RoundTripper
+	if tokens != nil {
+		rt, err = auth.BuildRoundTripperWithTokenFile(params.RESTConfig(), tokens, prometheusURL, tls, cfg.Insecure, transport)
+	} else {
+		rt, err = auth.BuildRoundTripperWithTransport(params.Context, params.RESTConfig(), cfg.GetAuthMode(), prometheusURL, tls, cfg.Insecure, transport)
+	}
 	if err != nil {
 		return promapi.Config{}, fmt.Errorf("failed to create round tripper: %w", err)
 	}
 
 	return promapi.Config{
 		Address:      prometheusURL,
-		RoundTripper: rt,
+		RoundTripper: instrumentation.TraceContextRoundTripper(rt),
 	}, nil
 }
 
@@ -81,10 +110,10 @@ func getAlertmanagerClient(params api.ToolHandlerParams) (alertmanager.Loader, e
 
 	alertmanagerURL := cfg.AlertmanagerURL
 	if alertmanagerURL == "" {
-		return nil, fmt.Errorf("alertmanager_url not configured")
+		return nil, errAlertsNotConfigured
 	}
 
-	apiConfig, err := buildAPIConfig(params, alertmanagerURL, cfg.Insecure, cfg.GetAuthMode())
+	apiConfig, err := buildAPIConfig(params, alertmanagerURL, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API config: %w", err)
 	}
@@ -2,13 +2,24 @@ package toolset_tools
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/rest"
 
 	"github.com/rhobs/obs-mcp/pkg/auth"
 	"github.com/rhobs/obs-mcp/pkg/metrics"
+	"github.com/rhobs/obs-mcp/pkg/metrics/prometheus"
 )
 
 type mockKubernetesClient struct {
@@ -36,10 +47,12 @@ func (m *mockConfigProvider) GetToolsetConfig(name string) (api.ExtendedConfig,
 	return nil, false
 }
 
-type mockToolCallRequest struct{}
+type mockToolCallRequest struct {
+	args map[string]any
+}
 
 func (m *mockToolCallRequest) GetArguments() map[string]any {
-	return nil
+	return m.args
 }
 
 func newTestParams(ctx context.Context, restConfig *rest.Config, cfg *metrics.Config) api.ToolHandlerParams {
@@ -67,3 +80,147 @@ func TestGetConfig_CustomAuthMode(t *testing.T) {
 		t.Errorf("expected auth mode %q, got %q", auth.AuthModeKubeConfig, got.GetAuthMode())
 	}
 }
+
+func TestExecuteRangeQueryHandler_PromQLDurationUnits(t *testing.T) {
+	// The same inputs are tested against the handlers of the standalone server in pkg/mcp.
+	tests := []struct {
+		args      map[string]any
+		wantStep  string
+		wantRange time.Duration
+	}{
+		{map[string]any{"step": "1d", "duration": "1w"}, "86400", 7 * 24 * time.Hour},
+		{map[string]any{"step": "1h30m", "duration": "2d"}, "5400", 48 * time.Hour},
+		{map[string]any{"step": "1d", "start": "NOW-2w", "end": "NOW"}, "86400", 14 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			var gotStep string
+			var gotRange time.Duration
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/api/v1/label/__name__/values" {
+					_, _ = w.Write([]byte(`{"status":"success","data":["up"]}`))
+					return
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("failed to parse request: %v", err)
+				}
+				start, _ := strconv.ParseFloat(r.Form.Get("start"), 64)
+				end, _ := strconv.ParseFloat(r.Form.Get("end"), 64)
+				gotStep, gotRange = r.Form.Get("step"), time.Duration((end-start)*float64(time.Second))
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"api"},"values":[[1,"1"]]}]}}`))
+			}))
+			defer server.Close()
+
+			args := map[string]any{"query": "up"}
+			maps.Copy(args, tt.args)
+			ctx := prometheus.ContextWithNow(context.Background(), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			params := newTestParams(ctx, &rest.Config{}, &metrics.Config{PrometheusURL: server.URL, Guardrails: "none"})
+			params.ToolCallRequest = &mockToolCallRequest{args: args}
+			result, err := ExecuteRangeQueryHandler(params)
+			if err != nil {
+				t.Fatalf("unexpected protocol error: %v", err)
+			}
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if gotStep != tt.wantStep || gotRange != tt.wantRange {
+				t.Errorf("step = %s and range = %v, want %s and %v", gotStep, gotRange, tt.wantStep, tt.wantRange)
+			}
+		})
+	}
+}
+
+func TestExecuteRangeQueryHandler_InvalidGuardrailOverride(t *testing.T) {
+	queried := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queried = true
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+	}))
+	defer server.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	r.Header.Set(auth.DefaultGuardrailHeader, "bogus")
+	ctx := auth.ContextWithGuardrailOverrideFromRequest(context.Background(), r, auth.DefaultGuardrailHeader)
+	params := newTestParams(ctx, &rest.Config{}, &metrics.Config{PrometheusURL: server.URL, TrustGuardrailHeader: true})
+	params.ToolCallRequest = &mockToolCallRequest{args: map[string]any{"query": "up", "step": "1m"}}
+
+	result, err := ExecuteRangeQueryHandler(params)
+	if err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "invalid guardrail override") {
+		t.Errorf("error = %v, want the guardrail override to be rejected", result.Error)
+	}
+	if queried {
+		t.Error("the query was sent to the backend without guardrails")
+	}
+}
+
+func TestBackendRequests_ForwardTraceContext(t *testing.T) {
+	var mu sync.Mutex
+	traceparents := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents[r.URL.Path] = r.Header.Get("traceparent")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+	}))
+	defer server.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	params := newTestParams(ctx, &rest.Config{}, &metrics.Config{PrometheusURL: server.URL, AlertmanagerURL: server.URL})
+	params.ToolCallRequest = &mockToolCallRequest{args: map[string]any{"query": "up", "step": "1m"}}
+
+	if _, err := ExecuteRangeQueryHandler(params); err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+	params.ToolCallRequest = &mockToolCallRequest{args: map[string]any{}}
+	if _, err := GetSilencesHandler(params); err != nil {
+		t.Fatalf("unexpected protocol error: %v", err)
+	}
+
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	mu.Lock()
+	defer mu.Unlock()
+	// The first request of each client: the metric names checked before a query, and the silences.
+	for _, path := range []string{"/api/v1/label/__name__/values", "/api/v2/silences"} {
+		if got, ok := traceparents[path]; !ok {
+			t.Errorf("no request to %s", path)
+		} else if got != want {
+			t.Errorf("traceparent of %s = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestAlertmanagerTools_NotConfigured(t *testing.T) {
+	handlers := map[string]api.ToolHandlerFunc{
+		"get_alerts":              GetAlertsHandler,
+		"summarize_alerts":        SummarizeAlertsHandler,
+		"get_silences":            GetSilencesHandler,
+		"get_alertmanager_status": GetAlertmanagerStatusHandler,
+	}
+	params := newTestParams(context.Background(), &rest.Config{}, &metrics.Config{PrometheusURL: "http://localhost:9090"})
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler(params)
+			if err != nil {
+				t.Fatalf("unexpected protocol error: %v", err)
+			}
+			if !errors.Is(result.Error, errAlertsNotConfigured) {
+				t.Errorf("error = %v, want %v", result.Error, errAlertsNotConfigured)
+			}
+			if !strings.Contains(result.Error.Error(), "alerts are not configured for this server") {
+				t.Errorf("error = %q, want it to explain that alerts are not configured", result.Error)
+			}
+		})
+	}
+}
@@ -13,6 +13,13 @@ func InitListMetrics() []api.ServerTool {
 	}
 }
 
+// InitListMetricGroups creates the list_metric_groups tool.
+func InitListMetricGroups() []api.ServerTool {
+	return []api.ServerTool{
+		tools.ListMetricGroups.ToServerTool(ListMetricGroupsHandler),
+	}
+}
+
 // InitExecuteInstantQuery creates the execute_instant_query tool.
 func InitExecuteInstantQuery() []api.ServerTool {
 	return []api.ServerTool{
@@ -34,6 +41,41 @@ func InitShowTimeseries() []api.ServerTool {
 	}
 }
 
+// InitQueryHeatmap creates the query_heatmap tool.
+func InitQueryHeatmap() []api.ServerTool {
+	return []api.ServerTool{
+		tools.QueryHeatmap.ToServerTool(QueryHeatmapHandler),
+	}
+}
+
+// InitLabelCardinalityTrend creates the label_cardinality_trend tool.
+func InitLabelCardinalityTrend() []api.ServerTool {
+	return []api.ServerTool{
+		tools.LabelCardinalityTrend.ToServerTool(LabelCardinalityTrendHandler),
+	}
+}
+
+// InitSLOCompliance creates the slo_compliance tool.
+func InitSLOCompliance() []api.ServerTool {
+	return []api.ServerTool{
+		tools.SLOCompliance.ToServerTool(SLOComplianceHandler),
+	}
+}
+
+// InitEvaluateCondition creates the evaluate_condition tool.
+func InitEvaluateCondition() []api.ServerTool {
+	return []api.ServerTool{
+		tools.EvaluateCondition.ToServerTool(EvaluateConditionHandler),
+	}
+}
+
+// InitClusterStatus creates the cluster_status tool.
+func InitClusterStatus() []api.ServerTool {
+	return []api.ServerTool{
+		tools.ClusterStatus.ToServerTool(ClusterStatusHandler),
+	}
+}
+
 // InitGetLabelNames creates the get_label_names tool.
 func InitGetLabelNames() []api.ServerTool {
 	return []api.ServerTool{
@@ -48,6 +90,13 @@ func InitGetLabelValues() []api.ServerTool {
 	}
 }
 
+// InitGetLabelsOverview creates the get_labels_overview tool.
+func InitGetLabelsOverview() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetLabelsOverview.ToServerTool(GetLabelsOverviewHandler),
+	}
+}
+
 // InitGetSeries creates the get_series tool.
 func InitGetSeries() []api.ServerTool {
 	return []api.ServerTool{
@@ -55,6 +104,97 @@ func InitGetSeries() []api.ServerTool {
 	}
 }
 
+// InitCheckSeriesUniqueness creates the check_series_uniqueness tool.
+func InitCheckSeriesUniqueness() []api.ServerTool {
+	return []api.ServerTool{
+		tools.CheckSeriesUniqueness.ToServerTool(CheckSeriesUniquenessHandler),
+	}
+}
+
+// InitInspectMetric creates the inspect_metric tool.
+func InitInspectMetric() []api.ServerTool {
+	return []api.ServerTool{
+		tools.InspectMetric.ToServerTool(InspectMetricHandler),
+	}
+}
+
+// InitGetMetricMetadata creates the get_metric_metadata tool.
+func InitGetMetricMetadata() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetMetricMetadata.ToServerTool(GetMetricMetadataHandler),
+	}
+}
+
+// InitGetScrapeInterval creates the get_scrape_interval tool.
+func InitGetScrapeInterval() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetScrapeInterval.ToServerTool(GetScrapeIntervalHandler),
+	}
+}
+
+// InitGetExternalLabels creates the get_external_labels tool.
+func InitGetExternalLabels() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetExternalLabels.ToServerTool(GetExternalLabelsHandler),
+	}
+}
+
+// InitGetActiveQueries creates the get_active_queries tool.
+func InitGetActiveQueries() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetActiveQueries.ToServerTool(GetActiveQueriesHandler),
+	}
+}
+
+// InitGetRuleGroup creates the get_rule_group tool.
+func InitGetRuleGroup() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetRuleGroup.ToServerTool(GetRuleGroupHandler),
+	}
+}
+
+// InitListRecordingRules creates the list_recording_rules tool.
+func InitListRecordingRules() []api.ServerTool {
+	return []api.ServerTool{
+		tools.ListRecordingRules.ToServerTool(ListRecordingRulesHandler),
+	}
+}
+
+// InitListQueryTemplates creates the list_query_templates tool.
+func InitListQueryTemplates() []api.ServerTool {
+	return []api.ServerTool{
+		tools.ListQueryTemplates.ToServerTool(ListQueryTemplatesHandler),
+	}
+}
+
+// InitRenderQueryTemplate creates the render_query_template tool.
+func InitRenderQueryTemplate() []api.ServerTool {
+	return []api.ServerTool{
+		tools.RenderQueryTemplate.ToServerTool(RenderQueryTemplateHandler),
+	}
+}
+
+// InitQueryToPanel creates the query_to_panel tool.
+func InitQueryToPanel() []api.ServerTool {
+	return []api.ServerTool{
+		tools.QueryToPanel.ToServerTool(QueryToPanelHandler),
+	}
+}
+
+// InitParseTime creates the parse_time tool.
+func InitParseTime() []api.ServerTool {
+	return []api.ServerTool{
+		tools.ParseTime.ToServerTool(ParseTimeHandler),
+	}
+}
+
+// InitSaveQueryResult creates the save_query_result tool.
+func InitSaveQueryResult() []api.ServerTool {
+	return []api.ServerTool{
+		tools.SaveQueryResult.ToServerTool(SaveQueryResultHandler),
+	}
+}
+
 // InitGetAlerts creates the get_alerts tool.
 func InitGetAlerts() []api.ServerTool {
 	return []api.ServerTool{
@@ -62,9 +202,54 @@ func InitGetAlerts() []api.ServerTool {
 	}
 }
 
+// InitSummarizeAlerts creates the summarize_alerts tool.
+func InitSummarizeAlerts() []api.ServerTool {
+	return []api.ServerTool{
+		tools.SummarizeAlerts.ToServerTool(SummarizeAlertsHandler),
+	}
+}
+
+// InitGetAlertHistory creates the get_alert_history tool.
+func InitGetAlertHistory() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetAlertHistory.ToServerTool(GetAlertHistoryHandler),
+	}
+}
+
+// InitGetAlertThreshold creates the get_alert_threshold tool.
+func InitGetAlertThreshold() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetAlertThreshold.ToServerTool(GetAlertThresholdHandler),
+	}
+}
+
 // InitGetSilences creates the get_silences tool.
 func InitGetSilences() []api.ServerTool {
 	return []api.ServerTool{
 		tools.GetSilences.ToServerTool(GetSilencesHandler),
 	}
 }
+
+// InitDeleteSilence creates the delete_silence tool.
+func InitDeleteSilence() []api.ServerTool {
+	return []api.ServerTool{
+		tools.DeleteSilence.ToServerTool(DeleteSilenceHandler),
+	}
+}
+
+// InitGetAlertmanagerStatus creates the get_alertmanager_status tool.
+func InitGetAlertmanagerStatus() []api.ServerTool {
+	return []api.ServerTool{
+		tools.GetAlertmanagerStatus.ToServerTool(GetAlertmanagerStatusHandler),
+	}
+}
+
+// InitWorkflowPrompts creates the investigation workflow prompts.
+func InitWorkflowPrompts() []api.ServerPrompt {
+	prompts := tools.AllWorkflowPrompts()
+	serverPrompts := make([]api.ServerPrompt, len(prompts))
+	for i, p := range prompts {
+		serverPrompts[i] = p.ToServerPrompt()
+	}
+	return serverPrompts
+}
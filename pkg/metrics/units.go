@@ -0,0 +1,325 @@
+package metrics
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Units InferUnit recognizes, following the Prometheus base unit naming conventions.
+const (
+	UnitBytes          = "bytes"
+	UnitBytesPerSecond = "bytes/s"
+	UnitSeconds        = "seconds"
+)
+
+// dimension is the unit of a PromQL expression: a base unit ("" for counts and other
+// dimensionless values) divided by seconds perSecond times, e.g. bytes/s for a rate of
+// a byte counter.
+type dimension struct {
+	base      string
+	perSecond int
+	unknown   bool
+}
+
+var unknownDimension = dimension{unknown: true}
+
+// InferUnit returns the unit of the values of a query, inferred from the _bytes and
+// _seconds suffixes of its metric names and the functions and operators applied to
+// them, e.g. bytes/s for rate(node_network_receive_bytes_total[5m]) and seconds for
+// histogram_quantile(0.99, rate(http_request_duration_seconds_bucket[5m])). It returns
+// "" when the unit is not one of the units above or cannot be inferred.
+func InferUnit(query string) string {
+	expr, err := parser.NewParser(parser.Options{}).ParseExpr(query)
+	if err != nil {
+		return ""
+	}
+	d := inferDimension(expr)
+	switch {
+	case d.unknown:
+		return ""
+	case d.base == UnitBytes && d.perSecond == 0:
+		return UnitBytes
+	case d.base == UnitBytes && d.perSecond == 1:
+		return UnitBytesPerSecond
+	case d.base == UnitSeconds && d.perSecond == 0:
+		return UnitSeconds
+	}
+	return ""
+}
+
+// metricDimension infers the unit of a metric from its name. Counters and histogram
+// series keep the unit of what they count, except _count series, which count events.
+func metricDimension(name string) dimension {
+	if strings.HasSuffix(name, "_count") {
+		return dimension{}
+	}
+	for _, suffix := range []string{"_total", "_sum", "_bucket"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	for _, unit := range []string{UnitBytes, UnitSeconds} {
+		if strings.HasSuffix(name, "_"+unit) {
+			return dimension{base: unit}
+		}
+	}
+	return unknownDimension
+}
+
+// dimensionlessFuncs are the functions returning counts, timestamps and other values
+// unrelated to the unit of their argument.
+var dimensionlessFuncs = map[string]bool{
+	"absent": true, "absent_over_time": true, "changes": true, "count_over_time": true,
+	"present_over_time": true, "resets": true, "scalar": true, "sgn": true,
+}
+
+// unitPreservingFuncs are the functions whose result has the unit of their first vector
+// argument.
+var unitPreservingFuncs = map[string]bool{
+	"abs": true, "avg_over_time": true, "ceil": true, "clamp": true, "clamp_max": true,
+	"clamp_min": true, "delta": true, "floor": true, "idelta": true, "increase": true,
+	"label_join": true, "label_replace": true, "last_over_time": true, "mad_over_time": true,
+	"max_over_time": true, "min_over_time": true, "quantile_over_time": true, "round": true,
+	"sort": true, "sort_by_label": true, "sort_by_label_desc": true, "sort_desc": true,
+	"stddev_over_time": true, "sum_over_time": true, "vector": true,
+}
+
+// inferDimension returns the unit of the values of expr.
+func inferDimension(expr parser.Expr) dimension {
+	switch n := expr.(type) {
+	case *parser.VectorSelector:
+		name := n.Name
+		for _, m := range n.LabelMatchers {
+			if m.Name == "__name__" && name == "" {
+				name = m.Value
+			}
+		}
+		return metricDimension(name)
+	case *parser.MatrixSelector:
+		return inferDimension(n.VectorSelector)
+	case *parser.SubqueryExpr:
+		return inferDimension(n.Expr)
+	case *parser.ParenExpr:
+		return inferDimension(n.Expr)
+	case *parser.StepInvariantExpr:
+		return inferDimension(n.Expr)
+	case *parser.UnaryExpr:
+		return inferDimension(n.Expr)
+	case *parser.NumberLiteral:
+		return dimension{}
+	case *parser.AggregateExpr:
+		switch n.Op {
+		case parser.COUNT, parser.COUNT_VALUES, parser.GROUP:
+			return dimension{}
+		case parser.STDVAR:
+			return unknownDimension
+		}
+		return inferDimension(n.Expr)
+	case *parser.Call:
+		return callDimension(n)
+	case *parser.BinaryExpr:
+		return binaryDimension(n)
+	}
+	return unknownDimension
+}
+
+func callDimension(call *parser.Call) dimension {
+	name := call.Func.Name
+	switch {
+	case name == "rate" || name == "irate" || name == "deriv":
+		d := inferDimension(call.Args[0])
+		d.perSecond++
+		return d
+	case name == "histogram_quantile":
+		// Quantiles of rates of buckets are observations, in the unit of the buckets.
+		d := inferDimension(call.Args[1])
+		d.perSecond = 0
+		return d
+	case dimensionlessFuncs[name]:
+		return dimension{}
+	case unitPreservingFuncs[name]:
+		for _, arg := range call.Args {
+			if arg.Type() == parser.ValueTypeVector || arg.Type() == parser.ValueTypeMatrix {
+				return inferDimension(arg)
+			}
+		}
+	}
+	return unknownDimension
+}
+
+func binaryDimension(n *parser.BinaryExpr) dimension {
+	lhs, rhs := inferDimension(n.LHS), inferDimension(n.RHS)
+	lhsScalar := n.LHS.Type() == parser.ValueTypeScalar
+	rhsScalar := n.RHS.Type() == parser.ValueTypeScalar
+	switch {
+	case n.Op.IsComparisonOperator():
+		if n.ReturnBool {
+			return dimension{}
+		}
+		if lhsScalar {
+			return rhs
+		}
+		return lhs
+	case n.Op == parser.LAND || n.Op == parser.LUNLESS:
+		return lhs
+	case n.Op == parser.LOR:
+		if lhs == rhs {
+			return lhs
+		}
+	case n.Op == parser.ADD || n.Op == parser.SUB:
+		switch {
+		case lhsScalar:
+			return rhs
+		case rhsScalar:
+			return lhs
+		case lhs == rhs:
+			return lhs
+		}
+	case n.Op == parser.DIV:
+		// Constants usually convert units, e.g. "/ 1024" to KiB, so only divisions by
+		// queries are followed: rate(x_seconds_sum[5m]) / rate(x_seconds_count[5m]) is
+		// an average in seconds, x_bytes / x_bytes a ratio.
+		if lhs.unknown || rhs.unknown || lhsScalar || rhsScalar {
+			return unknownDimension
+		}
+		switch rhs.base {
+		case "":
+			return dimension{base: lhs.base, perSecond: lhs.perSecond - rhs.perSecond}
+		case lhs.base:
+			return dimension{perSecond: lhs.perSecond - rhs.perSecond}
+		}
+	case n.Op == parser.MUL:
+		if lhs.unknown || rhs.unknown || lhsScalar || rhsScalar {
+			return unknownDimension
+		}
+		switch {
+		case lhs.base == "":
+			return dimension{base: rhs.base, perSecond: lhs.perSecond + rhs.perSecond}
+		case rhs.base == "":
+			return dimension{base: lhs.base, perSecond: lhs.perSecond + rhs.perSecond}
+		}
+	}
+	return unknownDimension
+}
+
+// unitScale is a human-readable unit a value of a base unit can be converted to.
+type unitScale struct {
+	symbol string
+	factor float64
+}
+
+// unitScales lists the human-readable units of each unit, in increasing order.
+var unitScales = map[string][]unitScale{
+	UnitBytes: {
+		{"B", 1}, {"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40}, {"PiB", 1 << 50},
+	},
+	UnitBytesPerSecond: {
+		{"B/s", 1}, {"KiB/s", 1 << 10}, {"MiB/s", 1 << 20}, {"GiB/s", 1 << 30}, {"TiB/s", 1 << 40}, {"PiB/s", 1 << 50},
+	},
+	UnitSeconds: {
+		{"ns", 1e-9}, {"µs", 1e-6}, {"ms", 1e-3}, {"s", 1}, {"min", 60}, {"h", 3600}, {"d", 86400},
+	},
+}
+
+// scaleFor returns the largest human-readable unit of unit that v is at least one of,
+// or the smallest one for values below all of them.
+func scaleFor(v float64, unit string) unitScale {
+	scales := unitScales[unit]
+	scale := scales[0]
+	for _, s := range scales[1:] {
+		if math.Abs(v) >= s.factor {
+			scale = s
+		}
+	}
+	if unit == UnitSeconds && v == 0 {
+		return unitScale{"s", 1}
+	}
+	return scale
+}
+
+// formatInUnit formats v, a value in the given scale, rounded to 2 decimals.
+func formatInUnit(v float64, scale unitScale) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(math.Round(v/scale.factor*100)/100, 'f', -1, 64) + " " + scale.symbol
+}
+
+// convertUnit formats v, a value of unit, in the human-readable unit that suits it best,
+// e.g. "1.5 GiB" for 1610612736 bytes.
+func convertUnit(v float64, unit string) string {
+	return formatInUnit(v, scaleFor(v, unit))
+}
+
+// unknownUnitWarning is reported when convert_units is set but the unit of the query
+// cannot be inferred.
+const unknownUnitWarning = "convert_units: the unit of the query could not be inferred (only _bytes and _seconds metrics are converted), so values are returned unconverted"
+
+// convertUnits adds the values of the result converted to human-readable units, keeping
+// the raw values.
+func (o *InstantQueryOutput) convertUnits(query string) {
+	unit := InferUnit(query)
+	if unit == "" {
+		o.Warnings = append(o.Warnings, unknownUnitWarning)
+		return
+	}
+	o.Unit = unit
+	for i, r := range o.Result {
+		if len(r.Value) != 2 {
+			continue
+		}
+		if v, ok := parseSampleValue(r.Value[1]); ok {
+			o.Result[i].Converted = convertUnit(v, unit)
+		}
+	}
+}
+
+// convertUnits adds the values of the result converted to human-readable units, keeping
+// the raw values. All values of a series share the unit suiting its largest value, so
+// that they can be compared at a glance.
+func (o *RangeQueryOutput) convertUnits(query string) {
+	unit := InferUnit(query)
+	if unit == "" {
+		o.Warnings = append(o.Warnings, unknownUnitWarning)
+		return
+	}
+	o.Unit = unit
+	for i, series := range o.Result {
+		var largest float64
+		for _, pair := range series.Values {
+			if v, ok := parseSampleValue(pair[1]); ok && !math.IsInf(v, 0) && math.Abs(v) > largest {
+				largest = math.Abs(v)
+			}
+		}
+		scale := scaleFor(largest, unit)
+		converted := make([]any, len(series.Values))
+		for j, pair := range series.Values {
+			if v, ok := parseSampleValue(pair[1]); ok {
+				converted[j] = formatInUnit(v, scale)
+			}
+		}
+		o.Result[i].Converted = converted
+	}
+	for i, s := range o.Summary {
+		o.Summary[i].Converted = &ConvertedSummary{
+			Max:        convertUnit(s.Max, unit),
+			Min:        convertUnit(s.Min, unit),
+			Avg:        convertUnit(s.Avg, unit),
+			FirstValue: convertUnit(s.FirstValue, unit),
+			LastValue:  convertUnit(s.LastValue, unit),
+			Delta:      convertUnit(s.Delta, unit),
+		}
+	}
+}
+
+// parseSampleValue parses the value of a [timestamp, value] pair of the output, which
+// is nil at missing steps.
+func parseSampleValue(value any) (float64, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
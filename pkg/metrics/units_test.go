@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInferUnit(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`node_memory_MemAvailable_bytes`, UnitBytes},
+		{`sum by (pod) (container_memory_working_set_bytes{namespace="default"})`, UnitBytes},
+		{`{__name__="node_memory_MemTotal_bytes"}`, UnitBytes},
+		{`rate(node_network_receive_bytes_total[5m])`, UnitBytesPerSecond},
+		{`increase(node_network_receive_bytes_total[1h])`, UnitBytes},
+		{`histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket[5m])))`, UnitSeconds},
+		{`rate(http_request_duration_seconds_sum[5m]) / rate(http_request_duration_seconds_count[5m])`, UnitSeconds},
+		{`time() - process_start_time_seconds`, UnitSeconds},
+		{`max_over_time(go_memstats_heap_inuse_bytes[1h]) > 1e9`, UnitBytes},
+		{`topk(5, node_filesystem_avail_bytes)`, UnitBytes},
+		// Ratios, CPU usage and conversions by constants have no convertible unit.
+		{`node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes`, ""},
+		{`rate(node_cpu_seconds_total[5m])`, ""},
+		{`node_memory_MemAvailable_bytes / 1024`, ""},
+		{`count(node_memory_MemAvailable_bytes)`, ""},
+		{`rate(http_request_duration_seconds_count[5m])`, ""},
+		{`up`, ""},
+		{`node_memory_MemAvailable_bytes + process_start_time_seconds`, ""},
+		{`invalid(`, ""},
+	}
+	for _, tt := range tests {
+		if got := InferUnit(tt.query); got != tt.want {
+			t.Errorf("InferUnit(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestConvertUnit(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+		want  string
+	}{
+		{512, UnitBytes, "512 B"},
+		{1536, UnitBytes, "1.5 KiB"},
+		{1610612736, UnitBytes, "1.5 GiB"},
+		{-2 << 20, UnitBytes, "-2 MiB"},
+		{5 << 20, UnitBytesPerSecond, "5 MiB/s"},
+		{0.25, UnitSeconds, "250 ms"},
+		{0.0000015, UnitSeconds, "1.5 µs"},
+		{90, UnitSeconds, "1.5 min"},
+		{172800, UnitSeconds, "2 d"},
+		{0, UnitSeconds, "0 s"},
+		{math.NaN(), UnitBytes, "NaN"},
+	}
+	for _, tt := range tests {
+		if got := convertUnit(tt.value, tt.unit); got != tt.want {
+			t.Errorf("convertUnit(%v, %q) = %q, want %q", tt.value, tt.unit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Output verbosity levels of the query tools.
+const (
+	VerbosityMinimal  = "minimal"
+	VerbosityStandard = "standard"
+	VerbosityFull     = "full"
+)
+
+var verbosityLevels = []string{VerbosityMinimal, VerbosityStandard, VerbosityFull}
+
+// parseVerbosity validates a verbosity level, defaulting to standard.
+func parseVerbosity(verbosity string) (string, error) {
+	verbosity = cmp.Or(verbosity, VerbosityStandard)
+	if !slices.Contains(verbosityLevels, verbosity) {
+		return "", fmt.Errorf("invalid verbosity %q (valid options: %s)", verbosity, strings.Join(verbosityLevels, ", "))
+	}
+	return verbosity, nil
+}
+
+// newQueryStats describes the result returned by the backend for a query that took duration.
+func newQueryStats(series, samples int, duration time.Duration) *QueryStats {
+	return &QueryStats{
+		Series:   series,
+		Samples:  samples,
+		Duration: duration.Round(time.Millisecond).String(),
+	}
+}
+
+// applyVerbosity drops the fields not shown at the given verbosity: the executed query
+// and stats below full, and the per-series annotations at minimal. The warnings and the
+// sampled and nearest markers are kept at every level, as without them a partial or
+// approximate result would read as exact.
+func (o *InstantQueryOutput) applyVerbosity(verbosity string) {
+	switch verbosity {
+	case VerbosityMinimal:
+		for i := range o.Result {
+			o.Result[i].Merged = 0
+		}
+		fallthrough
+	case VerbosityStandard:
+		o.ExecutedQuery = nil
+		o.Stats = nil
+	}
+}
+
+// applyVerbosity drops the fields not shown at the given verbosity: the executed query
+// and stats below full, and the per-series annotations at minimal. The warnings and the
+// sampled marker are kept at every level.
+func (o *RangeQueryOutput) applyVerbosity(verbosity string) {
+	switch verbosity {
+	case VerbosityMinimal:
+		for i := range o.Result {
+			o.Result[i].Stale = false
+			o.Result[i].Merged = 0
+		}
+		for i := range o.Summary {
+			o.Summary[i].Stale = false
+			o.Summary[i].Merged = 0
+		}
+		for i := range o.Shapes {
+			o.Shapes[i].Stale = false
+			o.Shapes[i].Merged = 0
+		}
+		fallthrough
+	case VerbosityStandard:
+		o.ExecutedQuery = nil
+		o.Stats = nil
+	}
+}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// WorkflowPromptArgument defines an argument of a workflow prompt.
+type WorkflowPromptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+	Default     string
+}
+
+// WorkflowPrompt is an MCP prompt that starts a guided investigation using the metrics tools.
+// Template is a text/template rendered with the prompt arguments into a single user message.
+type WorkflowPrompt struct {
+	Name        string
+	Title       string
+	Description string
+	Arguments   []WorkflowPromptArgument
+	Template    string
+}
+
+var timeframeArgument = WorkflowPromptArgument{
+	Name:        "timeframe",
+	Description: "How far back to look, as a Prometheus duration (e.g. 30m, 1h, 1d)",
+	Default:     "1h",
+}
+
+var (
+	InvestigateIssue = WorkflowPrompt{
+		Name:        "investigate_issue",
+		Title:       "Investigate Issue",
+		Description: "Investigate an observability issue following the recommended metrics workflow",
+		Arguments: []WorkflowPromptArgument{
+			{Name: "issue", Description: "Description of the problem to investigate", Required: true},
+			{Name: "namespace", Description: "Optional namespace to focus the investigation on"},
+			timeframeArgument,
+		},
+		Template: ServerPrompt + `
+
+## TASK
+
+Investigate the following issue: {{.issue}}
+
+Scope the investigation to {{if .namespace}}namespace "{{.namespace}}"{{else}}all namespaces{{end}} over the last {{.timeframe}}.`,
+	}
+
+	InvestigateHighCPU = WorkflowPrompt{
+		Name:        "investigate_high_cpu",
+		Title:       "Investigate High CPU",
+		Description: "Find the workloads responsible for high CPU usage and check for throttling",
+		Arguments: []WorkflowPromptArgument{
+			{Name: "namespace", Description: "Optional namespace to limit the investigation to"},
+			timeframeArgument,
+		},
+		Template: `Investigate high CPU usage in {{if .namespace}}namespace "{{.namespace}}"{{else}}the cluster{{end}} over the last {{.timeframe}}.
+
+1. Call get_alerts to check for firing CPU-related alerts (e.g. CPUThrottlingHigh, KubeCPUOvercommit).
+2. Use list_metrics to find the CPU usage and throttling metrics available (e.g. '.*cpu.*').
+3. Identify the pods with the highest CPU usage with execute_range_query, aggregating by pod.
+4. Compare their usage with their CPU requests and limits, and check for CPU throttling.
+5. Summarize which workloads are responsible, since when, and whether they are throttled or starving others.`,
+	}
+
+	DiagnoseFailingDeployment = WorkflowPrompt{
+		Name:        "diagnose_failing_deployment",
+		Title:       "Diagnose Failing Deployment",
+		Description: "Diagnose why a deployment is not healthy using alerts and metrics",
+		Arguments: []WorkflowPromptArgument{
+			{Name: "deployment", Description: "Name of the deployment", Required: true},
+			{Name: "namespace", Description: "Namespace of the deployment", Required: true},
+			timeframeArgument,
+		},
+		Template: `Diagnose why deployment "{{.deployment}}" in namespace "{{.namespace}}" is failing, looking at the last {{.timeframe}}.
+
+1. Call get_alerts filtered on namespace "{{.namespace}}" to find related firing alerts.
+2. Use list_metrics to find deployment, pod status and container restart metrics (e.g. 'kube_deployment.*', 'kube_pod.*').
+3. Compare desired, available and unavailable replicas of the deployment over time.
+4. Check the status phase, readiness and container restarts of its pods, and the reasons containers were terminated or are waiting.
+5. Check the resource usage of its pods against their limits to spot OOM kills or throttling.
+6. Summarize the most likely root cause and the evidence supporting it.`,
+	}
+)
+
+// AllWorkflowPrompts returns all workflow prompt definitions.
+func AllWorkflowPrompts() []WorkflowPrompt {
+	return []WorkflowPrompt{
+		InvestigateIssue,
+		InvestigateHighCPU,
+		DiagnoseFailingDeployment,
+	}
+}
+
+// Render fills the prompt template with the given arguments, applying defaults
+// for missing optional arguments.
+func (p WorkflowPrompt) Render(args map[string]string) (string, error) {
+	values := make(map[string]string, len(p.Arguments))
+	for _, arg := range p.Arguments {
+		value := strings.TrimSpace(args[arg.Name])
+		if value == "" {
+			value = arg.Default
+		}
+		if value == "" && arg.Required {
+			return "", fmt.Errorf("argument %q is required for prompt %q", arg.Name, p.Name)
+		}
+		values[arg.Name] = value
+	}
+
+	tmpl, err := template.New(p.Name).Option("missingkey=error").Parse(p.Template)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt %q: %w", p.Name, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, values); err != nil {
+		return "", fmt.Errorf("failed to render prompt %q: %w", p.Name, err)
+	}
+	return sb.String(), nil
+}
+
+// ToMCPPrompt converts the workflow prompt to an MCP prompt and its handler.
+func (p WorkflowPrompt) ToMCPPrompt() (*mcp.Prompt, mcp.PromptHandler) {
+	arguments := make([]*mcp.PromptArgument, len(p.Arguments))
+	for i, arg := range p.Arguments {
+		arguments[i] = &mcp.PromptArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+		}
+	}
+
+	prompt := &mcp.Prompt{
+		Name:        p.Name,
+		Title:       p.Title,
+		Description: p.Description,
+		Arguments:   arguments,
+	}
+
+	handler := func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		text, err := p.Render(req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.GetPromptResult{
+			Description: p.Description,
+			Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: text}},
+			},
+		}, nil
+	}
+
+	return prompt, handler
+}
+
+// ToServerPrompt converts the workflow prompt to an api.ServerPrompt for the toolset.
+func (p WorkflowPrompt) ToServerPrompt() api.ServerPrompt {
+	arguments := make([]api.PromptArgument, len(p.Arguments))
+	for i, arg := range p.Arguments {
+		arguments[i] = api.PromptArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+		}
+	}
+
+	return api.ServerPrompt{
+		Prompt: api.Prompt{
+			Name:        p.Name,
+			Title:       p.Title,
+			Description: p.Description,
+			Arguments:   arguments,
+		},
+		Handler: func(params api.PromptHandlerParams) (*api.PromptCallResult, error) {
+			text, err := p.Render(params.GetArguments())
+			if err != nil {
+				return nil, err
+			}
+			return api.NewPromptCallResult(p.Description, []api.PromptMessage{
+				{Role: "user", Content: api.PromptContent{Type: "text", Text: text}},
+			}, nil), nil
+		},
+		ClusterAware: new(false),
+	}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorkflowPromptsRender(t *testing.T) {
+	for _, p := range AllWorkflowPrompts() {
+		t.Run(p.Name, func(t *testing.T) {
+			args := make(map[string]string)
+			for _, arg := range p.Arguments {
+				args[arg.Name] = "example"
+			}
+
+			text, err := p.Render(args)
+			if err != nil {
+				t.Fatalf("failed to render prompt: %v", err)
+			}
+			if strings.Contains(text, "<no value>") || strings.Contains(text, "{{") {
+				t.Errorf("rendered prompt contains unresolved placeholders:\n%s", text)
+			}
+		})
+	}
+}
+
+func TestWorkflowPromptRender(t *testing.T) {
+	tests := []struct {
+		name         string
+		prompt       WorkflowPrompt
+		args         map[string]string
+		wantContains []string
+		wantErr      string
+	}{
+		{
+			name:         "defaults and optional arguments",
+			prompt:       InvestigateHighCPU,
+			args:         map[string]string{},
+			wantContains: []string{"in the cluster over the last 1h"},
+		},
+		{
+			name:         "namespace and timeframe are templated",
+			prompt:       InvestigateHighCPU,
+			args:         map[string]string{"namespace": "payments", "timeframe": "6h"},
+			wantContains: []string{`in namespace "payments" over the last 6h`},
+		},
+		{
+			name:         "investigation includes the server workflow",
+			prompt:       InvestigateIssue,
+			args:         map[string]string{"issue": "checkout latency is up"},
+			wantContains: []string{"MANDATORY WORKFLOW FOR QUERYING", "Investigate the following issue: checkout latency is up"},
+		},
+		{
+			name:    "missing required argument",
+			prompt:  DiagnoseFailingDeployment,
+			args:    map[string]string{"namespace": "payments"},
+			wantErr: `argument "deployment" is required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.prompt.Render(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(text, want) {
+					t.Errorf("rendered prompt does not contain %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package resultutil
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// Trends of a series described by DescribeShape.
+const (
+	TrendUp   = "up"
+	TrendDown = "down"
+	TrendFlat = "flat"
+)
+
+const (
+	// minTrendChange is the fraction of the range of values a linear fit of the series
+	// must rise or fall by for the series to trend up or down.
+	minTrendChange = 0.25
+	// spikeDeviations is how many robust standard deviations (MAD based) a value must
+	// be off the trend to be a spike.
+	spikeDeviations = 6
+	// maxSpikeFraction is the fraction of values that may be off the trend for the largest
+	// one to still be a spike rather than part of a noisy or shifting series.
+	maxSpikeFraction = 0.05
+	// minDeviation is the fraction of the range of values a value must be off the trend
+	// to be a spike, or the series must oscillate around it to have a period.
+	minDeviation = 0.1
+	// minPeriodCorrelation is the correlation a series must have with itself shifted by
+	// a candidate period for it to be periodic.
+	minPeriodCorrelation = 0.5
+	// minShapePoints is the number of values needed to detect spikes and periods.
+	minShapePoints = 8
+)
+
+// Shape describes a series with heuristic descriptors instead of its values.
+type Shape struct {
+	Trend   string  `json:"trend,omitempty" jsonschema:"Overall direction of the series from a linear fit: up, down or flat; absent when the series has no finite values"`
+	Min     float64 `json:"min" jsonschema:"Minimum value of the series (excluding NaN/Inf)"`
+	Max     float64 `json:"max" jsonschema:"Maximum value of the series (excluding NaN/Inf)"`
+	Current float64 `json:"current" jsonschema:"Last value of the series (excluding NaN/Inf)"`
+	Spike   *Spike  `json:"spike,omitempty" jsonschema:"Largest value far off the trend of the series, when only a few values are"`
+	Period  string  `json:"period,omitempty" jsonschema:"Approximate period of the series when it oscillates, e.g. 1d"`
+	Points  int     `json:"points" jsonschema:"Number of finite values the shape was computed from"`
+}
+
+// Spike is a value far off the trend of a series.
+type Spike struct {
+	Timestamp float64 `json:"timestamp" jsonschema:"Timestamp of the spike (Unix seconds)"`
+	Value     float64 `json:"value" jsonschema:"Value at the spike"`
+	Direction string  `json:"direction" jsonschema:"up for a peak above the trend, down for a dip below it"`
+}
+
+// DescribeShape describes the trend, extremes, largest spike and period of a series.
+// Samples are expected in time order and evenly spaced, as returned by range queries;
+// NaN and Inf values are ignored.
+func DescribeShape(samples []model.SamplePair) Shape {
+	var times, values []float64
+	for _, s := range samples {
+		v := float64(s.Value)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		times = append(times, float64(s.Timestamp)/1000)
+		values = append(values, v)
+	}
+
+	n := len(values)
+	if n == 0 {
+		return Shape{}
+	}
+	shape := Shape{
+		Trend:   TrendFlat,
+		Min:     slices.Min(values),
+		Max:     slices.Max(values),
+		Current: values[n-1],
+		Points:  n,
+	}
+	valueRange := shape.Max - shape.Min
+	if n < 2 || valueRange == 0 {
+		return shape
+	}
+
+	slope, intercept := linearFit(times, values)
+	if change := slope * (times[n-1] - times[0]); math.Abs(change) >= minTrendChange*valueRange {
+		shape.Trend = TrendUp
+		if change < 0 {
+			shape.Trend = TrendDown
+		}
+	}
+	if n < minShapePoints {
+		return shape
+	}
+
+	residuals := make([]float64, n)
+	for i := range values {
+		residuals[i] = values[i] - (intercept + slope*times[i])
+	}
+	center := median(residuals)
+
+	spike := findSpike(residuals, center, minDeviation*valueRange)
+	if spike >= 0 {
+		shape.Spike = &Spike{Timestamp: times[spike], Value: values[spike], Direction: TrendUp}
+		if residuals[spike] < center {
+			shape.Spike.Direction = TrendDown
+		}
+		// The spike would otherwise dominate the correlations the period is found with.
+		residuals[spike] = center
+	}
+
+	if lag := findPeriod(residuals, minDeviation*valueRange); lag > 0 {
+		spacing := (times[n-1] - times[0]) / float64(n-1)
+		period := time.Duration(math.Round(float64(lag)*spacing)) * time.Second
+		shape.Period = model.Duration(period).String()
+	}
+	return shape
+}
+
+// linearFit returns the slope and intercept of the least squares line through the points.
+func linearFit(xs, ys []float64) (slope, intercept float64) {
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if varX == 0 {
+		return 0, meanY
+	}
+	slope = cov / varX
+	return slope, meanY - slope*meanX
+}
+
+// findSpike returns the index of the residual furthest from center, if it is a spike:
+// off by more than spikeDeviations robust standard deviations and minSize, with few
+// other residuals off as much. It returns -1 otherwise.
+func findSpike(residuals []float64, center, minSize float64) int {
+	deviations := make([]float64, len(residuals))
+	for i, r := range residuals {
+		deviations[i] = math.Abs(r - center)
+	}
+	// 1.4826 scales the median absolute deviation to the standard deviation of normally
+	// distributed values.
+	threshold := max(spikeDeviations*1.4826*median(deviations), minSize)
+
+	largest, outliers := -1, 0
+	for i, d := range deviations {
+		if d <= threshold {
+			continue
+		}
+		outliers++
+		if largest < 0 || d > deviations[largest] {
+			largest = i
+		}
+	}
+	if outliers > max(1, int(maxSpikeFraction*float64(len(residuals)))) {
+		return -1
+	}
+	return largest
+}
+
+// findPeriod returns the shortest lag, in samples, at which the residuals correlate
+// with themselves after first anti-correlating, as oscillating series do, or 0 when
+// they do not oscillate by at least minSize. Only lags up to half the series are
+// considered, so that at least two periods are seen.
+func findPeriod(residuals []float64, minSize float64) int {
+	if slices.Max(residuals)-slices.Min(residuals) < minSize {
+		return 0
+	}
+
+	maxLag := len(residuals) / 2
+	correlations := make([]float64, maxLag+2)
+	for lag := 1; lag < len(correlations) && lag < len(residuals)-1; lag++ {
+		correlations[lag] = correlation(residuals[:len(residuals)-lag], residuals[lag:])
+	}
+
+	anticorrelated := false
+	for lag := 2; lag <= maxLag; lag++ {
+		if correlations[lag] < 0 {
+			anticorrelated = true
+		}
+		if anticorrelated && correlations[lag] >= minPeriodCorrelation &&
+			correlations[lag] >= correlations[lag-1] && correlations[lag] >= correlations[lag+1] {
+			return lag
+		}
+	}
+	return 0
+}
+
+// correlation returns the Pearson correlation of two series of the same length.
+func correlation(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+		varY += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,141 @@
+package resultutil
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+// hourly returns a sample per hour with the values computed by f from the sample index.
+func hourly(n int, f func(i int) float64) []model.SamplePair {
+	samples := make([]model.SamplePair, n)
+	for i := range samples {
+		samples[i] = model.SamplePair{
+			Timestamp: model.Time(1767225600000 + int64(i)*3600*1000),
+			Value:     model.SampleValue(f(i)),
+		}
+	}
+	return samples
+}
+
+// noise returns deterministic values in [-1, 1) that look random.
+func noise(i int) float64 {
+	x := math.Sin(float64(i)*12.9898) * 43758.5453
+	return 2*(x-math.Floor(x)) - 1
+}
+
+func TestDescribeShape(t *testing.T) {
+	tests := []struct {
+		name       string
+		samples    []model.SamplePair
+		wantTrend  string
+		wantSpike  string
+		wantPeriod string
+	}{
+		{
+			name:      "rising series trends up",
+			samples:   hourly(48, func(i int) float64 { return 100 + 10*float64(i) + noise(i) }),
+			wantTrend: TrendUp,
+		},
+		{
+			name:      "falling series trends down",
+			samples:   hourly(48, func(i int) float64 { return 1000 - 5*float64(i) + noise(i) }),
+			wantTrend: TrendDown,
+		},
+		{
+			name:      "noisy series is flat",
+			samples:   hourly(48, func(i int) float64 { return 50 + noise(i) }),
+			wantTrend: TrendFlat,
+		},
+		{
+			name: "burst is a spike",
+			samples: hourly(48, func(i int) float64 {
+				if i == 30 {
+					return 500
+				}
+				return 50 + noise(i)
+			}),
+			wantTrend: TrendFlat,
+			wantSpike: TrendUp,
+		},
+		{
+			name: "drop is a spike",
+			samples: hourly(48, func(i int) float64 {
+				if i == 10 {
+					return 0
+				}
+				return 50 + noise(i)
+			}),
+			wantTrend: TrendFlat,
+			wantSpike: TrendDown,
+		},
+		{
+			name: "level shift is not a spike",
+			samples: hourly(48, func(i int) float64 {
+				if i >= 24 {
+					return 80 + noise(i)
+				}
+				return 50 + noise(i)
+			}),
+			wantTrend: TrendUp,
+		},
+		{
+			name:       "daily oscillation has a period",
+			samples:    hourly(96, func(i int) float64 { return 50 + 20*math.Sin(2*math.Pi*float64(i)/24) + noise(i) }),
+			wantTrend:  TrendFlat,
+			wantPeriod: "1d",
+		},
+		{
+			name:       "oscillation on a trend has a period",
+			samples:    hourly(96, func(i int) float64 { return float64(i) + 20*math.Sin(2*math.Pi*float64(i)/12) }),
+			wantTrend:  TrendUp,
+			wantPeriod: "12h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shape := DescribeShape(tt.samples)
+			if shape.Trend != tt.wantTrend {
+				t.Errorf("trend = %q, want %q", shape.Trend, tt.wantTrend)
+			}
+			var spike string
+			if shape.Spike != nil {
+				spike = shape.Spike.Direction
+			}
+			if spike != tt.wantSpike {
+				t.Errorf("spike = %+v, want direction %q", shape.Spike, tt.wantSpike)
+			}
+			if shape.Period != tt.wantPeriod {
+				t.Errorf("period = %q, want %q", shape.Period, tt.wantPeriod)
+			}
+			if shape.Points != len(tt.samples) {
+				t.Errorf("points = %d, want %d", shape.Points, len(tt.samples))
+			}
+		})
+	}
+}
+
+func TestDescribeShape_Values(t *testing.T) {
+	samples := hourly(10, func(i int) float64 {
+		if i == 9 {
+			return math.NaN()
+		}
+		return float64(i%3) + 1
+	})
+	shape := DescribeShape(samples)
+	if shape.Min != 1 || shape.Max != 3 || shape.Current != 3 {
+		t.Errorf("min/max/current = %v/%v/%v, want 1/3/3", shape.Min, shape.Max, shape.Current)
+	}
+	if shape.Points != 9 {
+		t.Errorf("points = %d, want 9 (NaN excluded)", shape.Points)
+	}
+
+	if shape := DescribeShape(nil); shape.Trend != "" || shape.Points != 0 {
+		t.Errorf("shape of empty series = %+v, want zero", shape)
+	}
+	if shape := DescribeShape(hourly(20, func(int) float64 { return 7 })); shape.Trend != TrendFlat || shape.Spike != nil || shape.Period != "" {
+		t.Errorf("shape of constant series = %+v, want flat without spike or period", shape)
+	}
+}
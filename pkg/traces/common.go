@@ -28,16 +28,20 @@ var tempoStackGVK = schema.GroupVersionKind{
 var (
 	tempoNamespaceSchema = &jsonschema.Schema{
 		Type:        "string",
-		Description: "The Kubernetes namespace where the Tempo instance is deployed. Use tempo_list_instances to discover available namespaces.",
+		Description: "The Kubernetes namespace where the Tempo instance is deployed. Use tempo_list_instances to discover available namespaces. Not needed when instance is provided.",
 	}
 	tempoNameSchema = &jsonschema.Schema{
 		Type:        "string",
-		Description: "The name of the Tempo instance to query. Use tempo_list_instances to discover available instance names.",
+		Description: "The name of the Tempo instance to query. Use tempo_list_instances to discover available instance names. Not needed when instance is provided.",
 	}
 	tempoTenantSchema = &jsonschema.Schema{
 		Type:        "string",
 		Description: "The tenant to query. This parameter is required for multi-tenant instances. Use tempo_list_instances to discover available tenants for each instance.",
 	}
+	tempoInstanceSchema = &jsonschema.Schema{
+		Type:        "string",
+		Description: "A selection key from tempo_list_instances, e.g. \"tracing/tempo/dev\", selecting the instance and tenant to query instead of tempoNamespace, tempoName and tenant.",
+	}
 )
 
 func hasTempoStackCRD(p api.FilteringProvider) func() bool {
@@ -52,12 +56,15 @@ func hasTempoStackCRD(p api.FilteringProvider) func() bool {
 // When a static TempoURL is configured, it is used directly without discovery.
 // Otherwise, the Tempo instance is resolved via Kubernetes discovery using the provided parameters.
 func getTempoClient(params api.ToolHandlerParams) (tempoclient.Loader, error) {
-	cfg := getToolsetConfig(params)
-
 	url, err := resolveTempoURL(params)
 	if err != nil {
 		return nil, err
 	}
+	return newTempoClient(params, url)
+}
+
+func newTempoClient(params api.ToolHandlerParams, url string) (tempoclient.Loader, error) {
+	cfg := getToolsetConfig(params)
 
 	tls := strings.HasPrefix(url, "https://")
 	rt, err := auth.BuildRoundTripper(params.Context, params.RESTConfig(), cfg.GetAuthMode(), tls, cfg.Insecure)
@@ -66,6 +73,7 @@ func getTempoClient(params api.ToolHandlerParams) (tempoclient.Loader, error) {
 	}
 
 	rt = instrumentation.RoundTripper(rt, cfg.ClientMetrics, "tempo")
+	rt = instrumentation.TraceContextRoundTripper(rt)
 
 	httpClient := &http.Client{
 		Timeout:   tempoclient.RequestTimeout,
@@ -81,11 +89,22 @@ func resolveTempoURL(params api.ToolHandlerParams) (string, error) {
 	}
 
 	p := api.WrapParams(params)
-	namespace := p.RequiredString("tempoNamespace")
-	name := p.RequiredString("tempoName")
-	tenant := p.OptionalString("tenant", "")
-	if namespace == "" && name == "" {
-		return "", fmt.Errorf("tempo URL not configured; set tempo_url/--traces.tempo-url/TEMPO_URL or provide tempoNamespace and tempoName")
+	var namespace, name, tenant string
+	if key := p.OptionalString("instance", ""); key != "" {
+		if p.OptionalString("tempoNamespace", "") != "" || p.OptionalString("tempoName", "") != "" || p.OptionalString("tenant", "") != "" {
+			return "", errors.New("instance cannot be combined with tempoNamespace, tempoName or tenant")
+		}
+		var err error
+		if namespace, name, tenant, err = discovery.ParseSelectionKey(key); err != nil {
+			return "", err
+		}
+	} else {
+		namespace = p.RequiredString("tempoNamespace")
+		name = p.RequiredString("tempoName")
+		tenant = p.OptionalString("tenant", "")
+		if namespace == "" && name == "" {
+			return "", fmt.Errorf("tempo URL not configured; set tempo_url/--traces.tempo-url/TEMPO_URL or provide instance, or tempoNamespace and tempoName")
+		}
 	}
 	if err := p.Err(); err != nil {
 		return "", err
@@ -120,6 +139,67 @@ func resolveTempoURL(params api.ToolHandlerParams) (string, error) {
 	return instance.GetURL(tenant), nil
 }
 
+// resolveAnyTempoURL is like resolveTempoURL, but when neither instance, tempoNamespace
+// nor tempoName is provided, it selects the only discovered Tempo instance, or tenant of
+// a multi-tenant instance, that can be queried. If there are several, the error lists them
+// so that the caller can pick one.
+func resolveAnyTempoURL(params api.ToolHandlerParams) (string, error) {
+	cfg := getToolsetConfig(params)
+	p := api.WrapParams(params)
+	namespace := p.OptionalString("tempoNamespace", "")
+	name := p.OptionalString("tempoName", "")
+	tenant := p.OptionalString("tenant", "")
+	instance := p.OptionalString("instance", "")
+	if (cfg != nil && cfg.TempoURL != "") || namespace != "" || name != "" || instance != "" {
+		return resolveTempoURL(params)
+	}
+
+	instances, err := discovery.ListInstances(params.Context, params.DynamicClient(), cfg.UseRoute)
+	if err != nil {
+		return "", err
+	}
+
+	type candidate struct {
+		instance discovery.TempoInstance
+		tenant   string
+	}
+	var candidates []candidate
+	for _, instance := range instances {
+		switch {
+		case !instance.Multitenancy:
+			candidates = append(candidates, candidate{instance: instance})
+		case tenant != "":
+			if slices.Contains(instance.Tenants, tenant) {
+				candidates = append(candidates, candidate{instance: instance, tenant: tenant})
+			}
+		default:
+			for _, t := range instance.Tenants {
+				candidates = append(candidates, candidate{instance: instance, tenant: t})
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		if tenant != "" {
+			return "", fmt.Errorf("no Tempo instance with tenant '%s' found", tenant)
+		}
+		return "", errors.New("no Tempo instance found; set tempo_url/--traces.tempo-url/TEMPO_URL or deploy a TempoStack or TempoMonolithic")
+	case 1:
+		return candidates[0].instance.GetURL(candidates[0].tenant), nil
+	}
+
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.instance.Namespace + "/" + c.instance.Name
+		if c.tenant != "" {
+			names[i] += " (tenant " + c.tenant + ")"
+		}
+	}
+	return "", fmt.Errorf("multiple Tempo instances found: %s; set tempoNamespace, tempoName and, for multi-tenant instances, tenant to select one",
+		strings.Join(names, ", "))
+}
+
 func findInstanceByName(instances []discovery.TempoInstance, namespace, name string) (discovery.TempoInstance, error) {
 	for _, instance := range instances {
 		if instance.Namespace == namespace && instance.Name == name {
@@ -130,12 +210,12 @@ func findInstanceByName(instances []discovery.TempoInstance, namespace, name str
 	return discovery.TempoInstance{}, fmt.Errorf("instance '%s' in namespace '%s' not found", name, namespace)
 }
 
-func parseTime(s string) (int64, error) {
+func parseTime(ctx context.Context, s string) (int64, error) {
 	if s == "" {
 		return 0, nil
 	}
 
-	ts, err := prometheus.ParseTimestamp(s)
+	ts, err := prometheus.ParseTimestamp(ctx, s)
 	if err != nil {
 		return 0, err
 	}
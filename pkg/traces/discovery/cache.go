@@ -0,0 +1,61 @@
+package discovery
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+)
+
+// instancesTTL is how long discovered Tempo instances are cached.
+const instancesTTL = 5 * time.Minute
+
+// instancesKey identifies the client instances were discovered with. Clients are not shared
+// between callers with different credentials, so that a caller never receives instances it
+// could not list itself.
+type instancesKey struct {
+	client   dynamic.Interface
+	useRoute bool
+}
+
+type instancesEntry struct {
+	instances []TempoInstance
+	fetched   time.Time
+}
+
+var instancesCache = struct {
+	sync.Mutex
+	entries map[instancesKey]instancesEntry
+}{entries: make(map[instancesKey]instancesEntry)}
+
+// ListInstances returns the TempoStack and TempoMonolithic instances of the cluster whose
+// endpoint can be resolved. The result is cached per client for instancesTTL.
+func ListInstances(ctx context.Context, k8sClient dynamic.Interface, useRoute bool) ([]TempoInstance, error) {
+	key := instancesKey{client: k8sClient, useRoute: useRoute}
+	instancesCache.Lock()
+	entry, ok := instancesCache.entries[key]
+	instancesCache.Unlock()
+	if ok && time.Since(entry.fetched) < instancesTTL {
+		return slices.Clone(entry.instances), nil
+	}
+
+	instances, err := listInstances(ctx, k8sClient, useRoute)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	instancesCache.Lock()
+	// Clients may be created per request, so expired entries are dropped rather than
+	// left to accumulate.
+	for k, e := range instancesCache.entries {
+		if now.Sub(e.fetched) >= instancesTTL {
+			delete(instancesCache.entries, k)
+		}
+	}
+	instancesCache.entries[key] = instancesEntry{instances: instances, fetched: now}
+	instancesCache.Unlock()
+	return slices.Clone(instances), nil
+}
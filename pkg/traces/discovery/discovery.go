@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,7 +19,10 @@ type TempoInstance struct {
 	Multitenancy bool     `json:"multitenancy"`
 	Tenants      []string `json:"tenants,omitempty"`
 	Status       string   `json:"status"`
-	baseURL      string
+	// SelectionKeys select the instance, or one of its tenants, with the instance parameter
+	// of the Tempo tools: namespace/name, or namespace/name/tenant for multi-tenant instances.
+	SelectionKeys []string `json:"selectionKeys"`
+	baseURL       string
 }
 
 type KindType string
@@ -28,7 +32,7 @@ const (
 	KindTempoMonolithic KindType = "TempoMonolithic"
 )
 
-func ListInstances(ctx context.Context, k8sClient dynamic.Interface, useRoute bool) ([]TempoInstance, error) {
+func listInstances(ctx context.Context, k8sClient dynamic.Interface, useRoute bool) ([]TempoInstance, error) {
 	tempos := []TempoInstance{}
 
 	tempoStacks, err := listTempoStacks(ctx, k8sClient, useRoute)
@@ -82,13 +86,14 @@ func listTempoStacks(ctx context.Context, k8sClient dynamic.Interface, useRoute
 		status := getStatusFromConditions(tempo.Status.Conditions)
 
 		instances = append(instances, TempoInstance{
-			Kind:         KindTempoStack,
-			Namespace:    tempo.Namespace,
-			Name:         tempo.Name,
-			Multitenancy: multitenancy,
-			Tenants:      tenants,
-			Status:       status,
-			baseURL:      baseURL,
+			Kind:          KindTempoStack,
+			Namespace:     tempo.Namespace,
+			Name:          tempo.Name,
+			Multitenancy:  multitenancy,
+			Tenants:       tenants,
+			Status:        status,
+			SelectionKeys: selectionKeys(tempo.Namespace, tempo.Name, multitenancy, tenants),
+			baseURL:       baseURL,
 		})
 	}
 
@@ -131,13 +136,14 @@ func listTempoMonolithics(ctx context.Context, k8sClient dynamic.Interface, useR
 		status := getStatusFromConditions(tempo.Status.Conditions)
 
 		instances = append(instances, TempoInstance{
-			Kind:         KindTempoMonolithic,
-			Namespace:    tempo.Namespace,
-			Name:         tempo.Name,
-			Multitenancy: multitenancy,
-			Tenants:      tenants,
-			Status:       status,
-			baseURL:      baseURL,
+			Kind:          KindTempoMonolithic,
+			Namespace:     tempo.Namespace,
+			Name:          tempo.Name,
+			Multitenancy:  multitenancy,
+			Tenants:       tenants,
+			Status:        status,
+			SelectionKeys: selectionKeys(tempo.Namespace, tempo.Name, multitenancy, tenants),
+			baseURL:       baseURL,
 		})
 	}
 
@@ -188,3 +194,27 @@ func (t *TempoInstance) GetURL(tenant string) string {
 	}
 	return t.baseURL
 }
+
+func selectionKeys(namespace, name string, multitenancy bool, tenants []string) []string {
+	if !multitenancy {
+		return []string{namespace + "/" + name}
+	}
+	keys := make([]string, len(tenants))
+	for i, tenant := range tenants {
+		keys[i] = namespace + "/" + name + "/" + tenant
+	}
+	return keys
+}
+
+// ParseSelectionKey splits a selection key of an instance into its namespace, name and,
+// for multi-tenant instances, tenant.
+func ParseSelectionKey(key string) (namespace, name, tenant string, err error) {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" || (len(parts) == 3 && parts[2] == "") {
+		return "", "", "", fmt.Errorf("invalid instance %q: expected namespace/name or namespace/name/tenant, as listed by tempo_list_instances", key)
+	}
+	if len(parts) == 3 {
+		tenant = parts[2]
+	}
+	return parts[0], parts[1], tenant, nil
+}
@@ -24,13 +24,16 @@ func initGetTraceByID(p api.FilteringProvider) api.ServerTool {
 			Name: "tempo_get_trace_by_id",
 			Description: `Retrieve a single distributed trace by its trace ID from Tempo.
 Returns the full trace with all its spans, including service names, operation names, durations, and attributes.
-Use this tool when you already have a specific trace ID, e.g. from search results or logs.`,
+Use this tool when you already have a specific trace ID, e.g. from search results or logs.
+tempoNamespace and tempoName may be omitted when the cluster has a single Tempo instance, or a single tenant of a multi-tenant instance;
+otherwise the error lists the instances to choose from.`,
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
 					"tempoNamespace": tempoNamespaceSchema,
 					"tempoName":      tempoNameSchema,
 					"tenant":         tempoTenantSchema,
+					"instance":       tempoInstanceSchema,
 					"traceid": {
 						Type:        "string",
 						Description: `The trace ID to retrieve, e.g. "26dad4a0e2b0dd9a440dd5ff203a24a4".`,
@@ -46,7 +49,7 @@ Narrows the time range to improve query performance.`,
 Narrows the time range to improve query performance.`,
 					},
 				},
-				Required: []string{"tempoNamespace", "tempoName", "traceid"},
+				Required: []string{"traceid"},
 			},
 			OutputSchema: getTraceByIDOutputSchema,
 			Annotations: api.ToolAnnotations{
@@ -73,17 +76,21 @@ func getTraceByIDHandler(params api.ToolHandlerParams) (*api.ToolCallResult, err
 		return api.NewToolCallResult("", fmt.Errorf("failed to get trace by ID: %w", err)), nil
 	}
 
-	start, err := parseTime(startStr)
+	start, err := parseTime(params.Context, startStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid start time: %v", err)), nil
 	}
 
-	end, err := parseTime(endStr)
+	end, err := parseTime(params.Context, endStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid end time: %v", err)), nil
 	}
 
-	client, err := getTempoClient(params)
+	url, err := resolveAnyTempoURL(params)
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	client, err := newTempoClient(params, url)
 	if err != nil {
 		return api.NewToolCallResult("", err), nil
 	}
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // tempoServer starts an httptest server that responds to Tempo API paths with the given responses.
@@ -79,6 +81,33 @@ func TestSearchTracesHandler_Success(t *testing.T) {
 	require.Len(t, output.Traces, 1)
 }
 
+func TestSearchTracesHandler_SearchOptions(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"traces":[]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	params := handlerParams(t, srv.URL, map[string]any{
+		"query": `{ resource.service.name="frontend" }`,
+		"limit": 5,
+		"start": "2025-01-01T00:00:00Z",
+		"end":   "2025-01-01T01:00:00Z",
+		"spss":  2,
+	})
+
+	result, err := searchTracesHandler(params)
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+	require.Equal(t, `{ resource.service.name="frontend" }`, query.Get("q"))
+	require.Equal(t, "5", query.Get("limit"))
+	require.Equal(t, "1735689600", query.Get("start"))
+	require.Equal(t, "1735693200", query.Get("end"))
+	require.Equal(t, "2", query.Get("spss"))
+}
+
 func TestSearchTracesHandler_EmptyQuery(t *testing.T) {
 	srv := tempoServer(t, nil)
 	result, err := searchTracesHandler(handlerParams(t, srv.URL, map[string]any{"query": ""}))
@@ -190,6 +219,89 @@ func TestGetTraceByIDHandler_NullTrace(t *testing.T) {
 	require.ErrorContains(t, result.Error, "not found")
 }
 
+func TestGetTraceByIDHandler_InstanceSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		args    map[string]any
+		wantURL string
+		wantErr string
+	}{
+		{
+			name:    "single instance is selected",
+			objects: []runtime.Object{newTempoMonolithic("tracing", "tempo", nil)},
+			args:    map[string]any{"traceid": "abc"},
+			wantURL: "http://tempo-tempo.tracing.svc:3200",
+		},
+		{
+			name:    "single tenant of a multi-tenant instance is selected",
+			objects: []runtime.Object{newTempoStack("tracing", "tempo", []string{"dev"})},
+			args:    map[string]any{"traceid": "abc"},
+			wantURL: "https://tempo-tempo-gateway.tracing.svc:8080/api/traces/v1/dev/tempo",
+		},
+		{
+			name:    "tenant narrows the candidates",
+			objects: []runtime.Object{newTempoStack("tracing", "tempo", []string{"dev", "prod"})},
+			args:    map[string]any{"traceid": "abc", "tenant": "prod"},
+			wantURL: "https://tempo-tempo-gateway.tracing.svc:8080/api/traces/v1/prod/tempo",
+		},
+		{
+			name: "instance selects an instance and tenant",
+			objects: []runtime.Object{
+				newTempoMonolithic("team-a", "tempo", nil),
+				newTempoStack("team-b", "tempo", []string{"dev", "prod"}),
+			},
+			args:    map[string]any{"traceid": "abc", "instance": "team-b/tempo/prod"},
+			wantURL: "https://tempo-tempo-gateway.team-b.svc:8080/api/traces/v1/prod/tempo",
+		},
+		{
+			name:    "instance of an unknown tenant is rejected",
+			objects: []runtime.Object{newTempoStack("team-b", "tempo", []string{"dev"})},
+			args:    map[string]any{"traceid": "abc", "instance": "team-b/tempo/prod"},
+			wantErr: "tenant 'prod' does not exist",
+		},
+		{
+			name:    "malformed instance is rejected",
+			objects: []runtime.Object{newTempoMonolithic("team-a", "tempo", nil)},
+			args:    map[string]any{"traceid": "abc", "instance": "team-a"},
+			wantErr: "invalid instance \"team-a\"",
+		},
+		{
+			name:    "instance cannot be combined with tempoNamespace",
+			objects: []runtime.Object{newTempoMonolithic("team-a", "tempo", nil)},
+			args:    map[string]any{"traceid": "abc", "instance": "team-a/tempo", "tempoNamespace": "team-a"},
+			wantErr: "instance cannot be combined",
+		},
+		{
+			name: "multiple instances are listed",
+			objects: []runtime.Object{
+				newTempoMonolithic("team-a", "tempo", nil),
+				newTempoStack("team-b", "tempo", []string{"dev", "prod"}),
+			},
+			args:    map[string]any{"traceid": "abc"},
+			wantErr: "multiple Tempo instances found: team-b/tempo (tenant dev), team-b/tempo (tenant prod), team-a/tempo;",
+		},
+		{
+			name:    "no instance",
+			args:    map[string]any{"traceid": "abc"},
+			wantErr: "no Tempo instance found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := newTestParams(t, &Config{}, newMockK8sClient(tt.objects...), tt.args)
+			url, err := resolveAnyTempoURL(params)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantURL, url)
+		})
+	}
+}
+
 // --- SearchTagsHandler ---
 
 func TestSearchTagsHandler_Success(t *testing.T) {
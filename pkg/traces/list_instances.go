@@ -21,7 +21,9 @@ func initListInstances(p api.FilteringProvider) api.ServerTool {
 			Name: "tempo_list_instances",
 			Description: `List all Tempo instances available in the Kubernetes cluster.
 Call this tool first to discover available Tempo instances before using other Tempo tools,
-as the returned namespace, name, and tenant values are required parameters for all other Tempo tools.
+as they need an instance to query: pass one of the returned selectionKeys as their instance parameter,
+or the namespace, name, and tenant values as tempoNamespace, tempoName, and tenant.
+Results are cached for 5 minutes, so newly created instances may take that long to appear.
 Always print the output of this tool in a table.`,
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
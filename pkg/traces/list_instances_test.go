@@ -1,9 +1,12 @@
 package traces
 
 import (
+	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestListInstancesHandler_Success(t *testing.T) {
@@ -23,6 +26,7 @@ func TestListInstancesHandler_Success(t *testing.T) {
 	require.Equal(t, "stack1", inst.Name)
 	require.Equal(t, []string{"tenant-a", "tenant-b"}, inst.Tenants)
 	require.Equal(t, "Ready", inst.Status)
+	require.Equal(t, []string{"ns1/stack1/tenant-a", "ns1/stack1/tenant-b"}, inst.SelectionKeys)
 
 	inst2 := output.Instances[1]
 	require.Equal(t, "ns2", inst2.Namespace)
@@ -46,6 +50,7 @@ func TestListInstancesHandler_TempoMonolithic(t *testing.T) {
 	require.False(t, inst.Multitenancy)
 	require.Empty(t, inst.Tenants)
 	require.Equal(t, "Ready", inst.Status)
+	require.Equal(t, []string{"mono-ns/mono1"}, inst.SelectionKeys)
 }
 
 func TestListInstancesHandler_TempoMonolithic_Multitenancy(t *testing.T) {
@@ -81,3 +86,54 @@ func TestListInstancesHandler_MixedInstances(t *testing.T) {
 	require.Equal(t, "stack1", output.Instances[0].Name)
 	require.Equal(t, "mono1", output.Instances[1].Name)
 }
+
+func TestListInstancesHandler_Cached(t *testing.T) {
+	fakeClient := newMockK8sClient(
+		newTempoStack("ns1", "stack1", []string{}),
+	)
+
+	result, err := listInstancesHandler(newTestParams(t, &Config{UseRoute: false}, fakeClient, nil))
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+	require.Len(t, result.StructuredContent.(listInstancesOutput).Instances, 1)
+
+	_, err = fakeClient.Resource(tempoMonolithicGVR).Namespace("ns2").Create(t.Context(), newTempoMonolithic("ns2", "mono1", []string{}), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// The instances discovered with the same client are served from the cache.
+	result, err = listInstancesHandler(newTestParams(t, &Config{UseRoute: false}, fakeClient, nil))
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+	require.Len(t, result.StructuredContent.(listInstancesOutput).Instances, 1)
+
+	// Other clients, e.g. of other callers, discover the instances themselves.
+	otherClient := newMockK8sClient(
+		newTempoStack("ns1", "stack1", []string{}),
+		newTempoMonolithic("ns2", "mono1", []string{}),
+	)
+	result, err = listInstancesHandler(newTestParams(t, &Config{UseRoute: false}, otherClient, nil))
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+	require.Len(t, result.StructuredContent.(listInstancesOutput).Instances, 2)
+}
+
+// TestListInstancesHandler_Concurrent runs parallel tool calls against the same client,
+// as the server does for concurrent requests; run it with -race.
+func TestListInstancesHandler_Concurrent(t *testing.T) {
+	fakeClient := newMockK8sClient(
+		newTempoStack("ns1", "stack1", []string{"tenant-a"}),
+		newTempoMonolithic("ns2", "mono1", []string{}),
+	)
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			result, err := listInstancesHandler(newTestParams(t, &Config{UseRoute: false}, fakeClient, nil))
+			assert.NoError(t, err)
+			if assert.NoError(t, result.Error) {
+				assert.Len(t, result.StructuredContent.(listInstancesOutput).Instances, 2)
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -31,6 +31,7 @@ This is useful for building accurate TraceQL queries with tempo_search_traces.`,
 					"tempoNamespace": tempoNamespaceSchema,
 					"tempoName":      tempoNameSchema,
 					"tenant":         tempoTenantSchema,
+					"instance":       tempoInstanceSchema,
 					"tag": {
 						Type: "string",
 						Description: `The fully qualified tag name to get values for, including its scope prefix, e.g. "resource.service.name" or "span.http.response.status_code".
@@ -58,7 +59,7 @@ e.g. '{ resource.service.name="payment-service" }' to only show tag values from
 						Description: "Maximum number of consecutive blocks without new values before the search stops early. Higher values are more thorough but slower.",
 					},
 				},
-				Required: []string{"tempoNamespace", "tempoName", "tag"},
+				Required: []string{"tag"},
 			},
 			OutputSchema: searchTagValuesOutputSchema,
 			Annotations: api.ToolAnnotations{
@@ -92,12 +93,12 @@ func searchTagValuesHandler(params api.ToolHandlerParams) (*api.ToolCallResult,
 		return api.NewToolCallResult("", fmt.Errorf("tag parameter must not be empty")), nil
 	}
 
-	start, err := parseTime(startStr)
+	start, err := parseTime(params.Context, startStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid start time: %v", err)), nil
 	}
 
-	end, err := parseTime(endStr)
+	end, err := parseTime(params.Context, endStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid end time: %v", err)), nil
 	}
@@ -32,6 +32,7 @@ To use these in TraceQL queries, prefix them with their scope, e.g. "resource.se
 					"tempoNamespace": tempoNamespaceSchema,
 					"tempoName":      tempoNameSchema,
 					"tenant":         tempoTenantSchema,
+					"instance":       tempoInstanceSchema,
 					"scope": {
 						Type: "string",
 						Description: `Filter tags to a specific scope. One of:
@@ -62,7 +63,6 @@ e.g. '{ resource.service.name="payment-service" }' to only show tags present in
 						Description: "Maximum number of consecutive blocks without new tag names before the search stops early. Higher values are more thorough but slower.",
 					},
 				},
-				Required: []string{"tempoNamespace", "tempoName"},
 			},
 			OutputSchema: searchTagsOutputSchema,
 			Annotations: api.ToolAnnotations{
@@ -92,12 +92,12 @@ func searchTagsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error
 		return api.NewToolCallResult("", fmt.Errorf("failed to search tags: %w", err)), nil
 	}
 
-	start, err := parseTime(startStr)
+	start, err := parseTime(params.Context, startStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid start time: %v", err)), nil
 	}
 
-	end, err := parseTime(endStr)
+	end, err := parseTime(params.Context, endStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid end time: %v", err)), nil
 	}
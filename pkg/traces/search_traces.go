@@ -37,6 +37,7 @@ Skip this two-step process only when the user provides an explicit duration (e.g
 					"tempoNamespace": tempoNamespaceSchema,
 					"tempoName":      tempoNameSchema,
 					"tenant":         tempoTenantSchema,
+					"instance":       tempoInstanceSchema,
 					"query": {
 						Type: "string",
 						Description: `A TraceQL query expression. Format:
@@ -110,7 +111,7 @@ Both start and end should be provided to search the full time range; if omitted,
 						Description: "Maximum number of matching spans to return per trace.",
 					},
 				},
-				Required: []string{"tempoNamespace", "tempoName", "query"},
+				Required: []string{"query"},
 			},
 			OutputSchema: searchTracesOutputSchema,
 			Annotations: api.ToolAnnotations{
@@ -142,12 +143,12 @@ func searchTracesHandler(params api.ToolHandlerParams) (*api.ToolCallResult, err
 		return api.NewToolCallResult("", fmt.Errorf("query parameter must not be empty")), nil
 	}
 
-	start, err := parseTime(startStr)
+	start, err := parseTime(params.Context, startStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid start time: %w", err)), nil
 	}
 
-	end, err := parseTime(endStr)
+	end, err := parseTime(params.Context, endStr)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("invalid end time: %w", err)), nil
 	}